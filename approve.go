@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+var (
+	approveAll bool
+	rejectAll  bool
+)
+
+// approveCmd 批准等待审批的版本
+var approveCmd = &cobra.Command{
+	Use:   "approve [批次ID...]",
+	Short: "批准等待审批的版本并发送到正式通知的渠道，不指定批次时列出等待审批的版本",
+	Long: `启用发送审批（approval）后，检测到的版本先发送汇总到审批渠道，批准后才发送到其他渠道。
+批准可以点击 Telegram 审批消息中的按钮（在下一次运行开始时生效），也可以使用本命令立即发送。
+不指定批次ID时列出等待审批的批次，--all 批准全部批次；配置了租户时使用 --tenant 指定租户。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := approvalManager()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 && !approveAll {
+			return printApprovals(manager)
+		}
+
+		approved, errs := manager.Approve(context.Background(), args)
+		for _, batch := range approved {
+			fmt.Printf("✓ 已批准批次 %s（%d 个版本）\n", batch.ID, len(batch.Releases))
+		}
+		if len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Printf("发送通知失败: %v\n", err)
+			}
+			return fmt.Errorf("部分通知发送失败，将在下次运行时重发")
+		}
+		return checkDecided(args, approved)
+	},
+}
+
+// rejectCmd 拒绝等待审批的版本
+var rejectCmd = &cobra.Command{
+	Use:   "reject [批次ID...]",
+	Short: "拒绝等待审批的版本，这些版本不再发送",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && !rejectAll {
+			return fmt.Errorf("请指定要拒绝的批次ID，或使用 --all 拒绝全部批次")
+		}
+		manager, err := approvalManager()
+		if err != nil {
+			return err
+		}
+
+		rejected, err := manager.Reject(args)
+		if err != nil {
+			return err
+		}
+		for _, batch := range rejected {
+			fmt.Printf("✓ 已拒绝批次 %s（%d 个版本）\n", batch.ID, len(batch.Releases))
+		}
+		return checkDecided(args, rejected)
+	},
+}
+
+// approvalManager 加载配置并创建通知管理器
+func approvalManager() (*notifier.Manager, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	manager, err := notifier.NewManager(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建通知管理器失败: %v", err)
+	}
+	return manager, nil
+}
+
+// printApprovals 列出等待审批的批次
+func printApprovals(manager *notifier.Manager) error {
+	batches, err := manager.PendingApprovals()
+	if err != nil {
+		return err
+	}
+	if len(batches) == 0 {
+		fmt.Println("没有等待审批的版本")
+		return nil
+	}
+	for _, batch := range batches {
+		fmt.Printf("批次 %s（%s，%d 个版本）\n", batch.ID, batch.CreatedAt.Local().Format(time.DateTime), len(batch.Releases))
+		for _, release := range batch.Releases {
+			fmt.Printf("  %s/%s@%s\n", release.Owner, release.Repository, release.TagName)
+		}
+	}
+	return nil
+}
+
+// checkDecided 检查指定的批次是否都在审批队列中
+func checkDecided(ids []string, decided []notifier.ApprovalBatch) error {
+	if len(ids) == 0 {
+		if len(decided) == 0 {
+			fmt.Println("没有等待审批的版本")
+		}
+		return nil
+	}
+	found := make(map[string]bool, len(decided))
+	for _, batch := range decided {
+		found[batch.ID] = true
+	}
+	for _, id := range ids {
+		if !found[id] {
+			return fmt.Errorf("找不到等待审批的批次: %s（使用 notify approve 查看）", id)
+		}
+	}
+	return nil
+}
+
+func init() {
+	approveCmd.Flags().BoolVar(&approveAll, "all", false, "批准全部等待审批的批次")
+	rejectCmd.Flags().BoolVar(&rejectAll, "all", false, "拒绝全部等待审批的批次")
+	RootCmd.AddCommand(approveCmd)
+	RootCmd.AddCommand(rejectCmd)
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/autostart"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/spf13/cobra"
+)
+
+// autostartCmd 管理定时运行的自启动
+var autostartCmd = &cobra.Command{
+	Use:   "autostart",
+	Short: "将定时运行注册为当前用户的自启动服务（systemd --user、launchd、Windows 任务计划程序）",
+	Long: `将 notify 的定时运行注册到当前平台的用户级自启动机制，登录（或开机）后自动启动，异常退出后自动重启：
+Linux 使用 systemd --user 服务，macOS 使用 launchd LaunchAgent，Windows 使用登录时运行的计划任务。
+自启动使用当前的配置文件（--config 或默认查找到的文件），--tenant、--shard 参数同样会带上，
+每个租户、分片注册为独立的自启动项。配置中需要启用定时运行（schedule.enabled）。`,
+}
+
+// autostartEnableCmd 注册并启动自启动项
+var autostartEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "注册自启动并立即启动定时运行，已注册时按当前参数更新",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := autostartConfig()
+		if err != nil {
+			return err
+		}
+		targets, err := runTargets(cfg)
+		if err != nil {
+			return err
+		}
+		if !scheduled(targets) {
+			return fmt.Errorf("配置中未启用定时运行（schedule.enabled），自启动后只会检查一次就退出")
+		}
+
+		entry, err := autostartEntry(cfg)
+		if err != nil {
+			return err
+		}
+		location, err := autostart.Enable(entry)
+		if err != nil {
+			return fmt.Errorf("注册自启动失败: %v", err)
+		}
+
+		fmt.Printf("✓ 已注册自启动 %s: %s\n", entry.Name, location)
+		fmt.Printf("  启动命令: %s %s\n", entry.Executable, strings.Join(entry.Args, " "))
+		switch {
+		case strings.HasSuffix(location, ".service"):
+			fmt.Printf("  查看日志: journalctl --user -u %s -f\n", entry.Name)
+			fmt.Println("  提示：未登录时也要运行，请执行 loginctl enable-linger")
+		case entry.LogPath != "" && strings.HasSuffix(location, ".plist"):
+			fmt.Printf("  日志文件: %s\n", entry.LogPath)
+		}
+		return nil
+	},
+}
+
+// autostartDisableCmd 停止并删除自启动项
+var autostartDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "停止定时运行并删除自启动",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := autostartConfig()
+		if err != nil {
+			return err
+		}
+		location, err := autostart.Disable(autostartName(cfg))
+		if err != nil {
+			return fmt.Errorf("删除自启动失败: %v", err)
+		}
+		fmt.Printf("✓ 已删除自启动 %s: %s\n", autostartName(cfg), location)
+		return nil
+	},
+}
+
+// autostartStatusCmd 查看自启动项是否已注册
+var autostartStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "查看是否已注册自启动",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := autostartConfig()
+		if err != nil {
+			return err
+		}
+		name := autostartName(cfg)
+		status, err := autostart.Query(name)
+		if err != nil {
+			return err
+		}
+		if !status.Installed {
+			fmt.Printf("未注册自启动 %s\n", name)
+			return nil
+		}
+		fmt.Printf("已注册自启动 %s: %s\n", name, status.Location)
+		return nil
+	},
+}
+
+func init() {
+	autostartCmd.AddCommand(autostartEnableCmd)
+	autostartCmd.AddCommand(autostartDisableCmd)
+	autostartCmd.AddCommand(autostartStatusCmd)
+	RootCmd.AddCommand(autostartCmd)
+}
+
+// autostartConfig 加载配置并应用 --shard，用于确定自启动项的名称
+func autostartConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %v", err)
+	}
+	if shardFlag != "" {
+		cfg.Shard, err = config.ParseShard(shardFlag)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// autostartName 自启动项名称：notify，指定租户或分片时加上与数据文件相同的后缀，如 notify-tenant-a-shard-1-of-3
+func autostartName(cfg *config.Config) string {
+	suffix := cfg.Shard.Suffix()
+	if tenantFlag != "" {
+		suffix = ".tenant-" + tenantFlag + suffix
+	}
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, suffix)
+	return "notify" + name
+}
+
+// autostartEntry 按当前的可执行文件、配置文件和命令行参数生成自启动项
+func autostartEntry(cfg *config.Config) (autostart.Entry, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return autostart.Entry{}, fmt.Errorf("获取可执行文件路径失败: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	// 自启动时不会带上当前的环境变量，需要使用配置文件
+	configPath := config.UsedConfigFile()
+	if configPath == "" {
+		return autostart.Entry{}, fmt.Errorf("未找到配置文件，自启动需要通过 --config 指定配置文件")
+	}
+	configPath, err = filepath.Abs(configPath)
+	if err != nil {
+		return autostart.Entry{}, fmt.Errorf("解析配置文件路径失败: %v", err)
+	}
+
+	args := []string{"--config", configPath}
+	if tenantFlag != "" {
+		args = append(args, "--tenant", tenantFlag)
+	}
+	if shardFlag != "" {
+		args = append(args, "--shard", shardFlag)
+	}
+
+	name := autostartName(cfg)
+	logPath, err := util.DefaultPath(name + ".log")
+	if err != nil {
+		return autostart.Entry{}, err
+	}
+
+	return autostart.Entry{
+		Name:        name,
+		Description: "notify 版本发布通知（定时运行）",
+		Executable:  exe,
+		Args:        args,
+		WorkDir:     filepath.Dir(configPath),
+		LogPath:     logPath,
+	}, nil
+}
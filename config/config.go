@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -11,9 +12,265 @@ import (
 // Config 应用配置结构
 type Config struct {
 	GitHub        GitHubConfig        `mapstructure:"github"`
+	GitLab        GitLabConfig        `mapstructure:"gitlab"`
+	NPM           NPMConfig           `mapstructure:"npm"`
+	PyPI          PyPIConfig          `mapstructure:"pypi"`
+	Crates        CratesConfig        `mapstructure:"crates"`
+	GoProxy       GoProxyConfig       `mapstructure:"goproxy"`
+	Maven         MavenConfig         `mapstructure:"maven"`
+	VSCode        VSCodeConfig        `mapstructure:"vscode"`
 	Notifications NotificationsConfig `mapstructure:"notifications"`
 	Template      string              `mapstructure:"template"`
 	Schedule      ScheduleConfig      `mapstructure:"schedule"`
+	Highlight     HighlightConfig     `mapstructure:"highlight"`
+	Network       NetworkConfig       `mapstructure:"network"`
+	State         StateConfig         `mapstructure:"state"`
+	Shard         ShardConfig         `mapstructure:"shard"`
+	// Partials 命名模板片段，可在模板中通过 {{template "名称" .}} 引用（名称统一为小写）
+	Partials map[string]string `mapstructure:"partials"`
+	Format   FormatConfig      `mapstructure:"format"`
+	Serve    ServeConfig       `mapstructure:"serve"`
+	// Templates 按语言配置的通知模板，渠道通过 lang 选择，未配置的语言使用内置模板
+	Templates map[string]string `mapstructure:"templates"`
+	Run       RunConfig         `mapstructure:"run"`
+	// Retention 确认记录、运行记录、失败队列和日志文件的保留策略，由 notify gc 清理，定时运行时每天自动清理一次
+	Retention RetentionConfig `mapstructure:"retention"`
+	// Pacing 按渠道覆盖发送速率，键为渠道名称（如 dingtalk、slack、钉钉实例 dingtalk/ops），未配置的渠道使用内置的默认速率
+	Pacing map[string]PacingConfig `mapstructure:"pacing"`
+	// DeliveryWindows 按渠道限制发送时间段，键为渠道名称（如 email、钉钉实例 dingtalk/ops），值为时间段列表（如 "mon-fri 09:00-18:00"）
+	// 不在时间段内检测到的版本放入队列，时间段开始后发送；未配置的渠道随时发送
+	DeliveryWindows map[string][]string `mapstructure:"delivery_windows"`
+	// ChannelGroups 命名的渠道组，键为组名，值为渠道名称（如 dingtalk/ops、telegram/ops），在 routes 中引用
+	ChannelGroups map[string][]string `mapstructure:"channel_groups"`
+	// Routes 按仓库选择通知渠道的规则，版本使用第一条匹配的规则；没有规则匹配的版本发送到所有渠道
+	Routes []RouteConfig `mapstructure:"routes"`
+	// Shortener 短链接服务，配置后消息中的版本链接和对比链接使用短链接
+	Shortener ShortenerConfig `mapstructure:"shortener"`
+	// Redact 发送前的内容过滤规则，按顺序替换版本名称和发布说明中匹配的内容
+	Redact []RedactRule `mapstructure:"redact"`
+	// Approval 发送审批，启用后检测到的版本先发送汇总到审批渠道，批准后才发送到其他渠道
+	Approval ApprovalConfig `mapstructure:"approval"`
+	// Tenants 租户列表，配置后每个租户使用各自的Token、仓库、通知渠道、状态文件和定时配置
+	Tenants []TenantConfig `mapstructure:"tenants"`
+	// TenantName 当前租户的名称，顶层配置为空
+	TenantName string `mapstructure:"-"`
+}
+
+// RedactRule 内容过滤规则
+type RedactRule struct {
+	// 正则表达式（Go RE2 语法）
+	Pattern string `mapstructure:"pattern"`
+	// 替换文字，支持 $1 引用分组，为空时替换为 [已隐藏]
+	Replacement string `mapstructure:"replacement"`
+}
+
+// ApprovalConfig 发送审批配置
+type ApprovalConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 接收待审批汇总的渠道名称，如 telegram；该渠道只用于审批，不再接收正式通知
+	Channel string `mapstructure:"channel"`
+	// 允许通过按钮审批的用户（Telegram 用户名或用户ID），为空时审批渠道中的所有成员都可以审批
+	Approvers []string `mapstructure:"approvers"`
+}
+
+// ShortenerConfig 自建短链接服务配置
+type ShortenerConfig struct {
+	// 短链接服务: shlink、yourls，为空时不缩短链接
+	Provider string `mapstructure:"provider"`
+	// 服务地址，如 https://s.example.com
+	URL string `mapstructure:"url"`
+	// Shlink 的 API Key，或 YOURLS 的签名令牌（signature）
+	APIKey string `mapstructure:"api_key"`
+	// Shlink 短链接使用的域名（可选）
+	Domain string `mapstructure:"domain"`
+}
+
+// PacingConfig 渠道的发送速率（令牌桶）
+type PacingConfig struct {
+	// 补充一个令牌的间隔，即持续发送时两个请求之间的最小间隔，如 4s
+	Interval string `mapstructure:"interval"`
+	// 允许连续发送的请求数
+	Burst int `mapstructure:"burst"`
+}
+
+// RunConfig 运行控制配置
+type RunConfig struct {
+	// 已有实例运行时等待其结束的最长时间，如 30m，为空时立即退出
+	LockWait string `mapstructure:"lock_wait"`
+	// 最近一次成功的运行在该时间内完成时跳过本次运行，如 10m，为空时不跳过
+	// 用于cron调用重叠的场景：等待文件锁的实例不会紧接着再完整扫描一次
+	SkipIfFresh string `mapstructure:"skip_if_fresh"`
+	// 保留的运行记录数（~/.notify/runs.json），默认50
+	HistorySize int `mapstructure:"history_size"`
+	// 运行指标（~/.notify/metrics.json）的保留天数，默认90，用于 notify stats
+	MetricsDays int `mapstructure:"metrics_days"`
+}
+
+// RetentionConfig 数据文件的保留策略，各项为0时不清理
+type RetentionConfig struct {
+	// 确认记录（acks.json，notify history）的保留天数，按发送时间计算；未配置时最多保留1000条
+	HistoryDays int `mapstructure:"history_days"`
+	// 运行记录（runs.json）的保留天数，按结束时间计算；未配置时只按 run.history_size 保留条数
+	RunsDays int `mapstructure:"runs_days"`
+	// 失败队列（outbox.json）中通知的保留天数，按最后一次失败的时间计算，超过后丢弃
+	OutboxDays int `mapstructure:"outbox_days"`
+	// 日志文件（~/.notify/*.log，如 notify autostart 的日志）超过该大小（MB）时轮转
+	LogMaxMB int `mapstructure:"log_max_mb"`
+	// 轮转后保留的旧日志文件数，默认3
+	LogBackups int `mapstructure:"log_backups"`
+}
+
+// Enabled 是否配置了任意一项保留策略
+func (r RetentionConfig) Enabled() bool {
+	return r.HistoryDays > 0 || r.RunsDays > 0 || r.OutboxDays > 0 || r.LogMaxMB > 0
+}
+
+// ServeConfig webhook服务配置（notify serve）
+type ServeConfig struct {
+	// 监听地址，默认 :8080
+	Listen string `mapstructure:"listen"`
+	// GitHub webhook的Secret，用于校验 X-Hub-Signature-256，为空时不校验
+	GitHubSecret string `mapstructure:"github_secret"`
+	// GitLab webhook的Secret Token，用于校验 X-Gitlab-Token，为空时不校验
+	GitLabToken string `mapstructure:"gitlab_token"`
+	// Gitea webhook的Secret，用于校验 X-Gitea-Signature，为空时不校验
+	GiteaSecret string `mapstructure:"gitea_secret"`
+	// 是否在通知中显示发布说明
+	ShowDescription bool `mapstructure:"show_description"`
+	// 接收Prometheus Alertmanager的告警（POST /alertmanager），通过相同的通知渠道转发
+	Alertmanager ServeAlertmanagerConfig `mapstructure:"alertmanager"`
+}
+
+// ServeAlertmanagerConfig 接收Alertmanager webhook的配置
+type ServeAlertmanagerConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Bearer Token，对应Alertmanager webhook_configs 中的 http_config.authorization.credentials，为空时不校验
+	Token string `mapstructure:"token"`
+	// 告警消息模板（Go模板，数据为Alertmanager的webhook请求体，可使用 .Alerts、.Firing、.Resolved、.CommonLabels 等），为空时使用内置模板
+	Template string `mapstructure:"template"`
+}
+
+// FormatConfig 消息格式配置
+type FormatConfig struct {
+	// 时间格式：预设名称（datetime、date、time、iso、rfc1123、long、short）或Go时间格式，默认datetime
+	TimeFormat string `mapstructure:"time_format"`
+	// 相对时间和本地化日期使用的语言：zh（默认）或 en
+	Locale string `mapstructure:"locale"`
+}
+
+// StateConfig 状态存储配置
+type StateConfig struct {
+	// 状态文件路径，默认为 ~/.notify/state.json
+	// 分片运行时会自动加上分片后缀，如 state.shard-1-of-4.json，多台机器可共享同一目录
+	Path string `mapstructure:"path"`
+	// 设置为true时加密状态文件（AES-256-GCM），避免泄露监控了哪些私有仓库
+	// 已有的明文状态文件会在加载后自动迁移为加密格式
+	Encrypt bool `mapstructure:"encrypt"`
+	// 读取加密密钥的环境变量名，默认为 NOTIFY_STATE_KEY
+	KeyEnv string `mapstructure:"key_env"`
+	// 设置为true时，环境变量为空时从系统密钥环读取密钥（macOS钥匙串或Linux Secret Service）
+	Keyring bool `mapstructure:"keyring"`
+}
+
+// NetworkConfig 出站网络配置
+type NetworkConfig struct {
+	// 绑定的本地IP或网卡名（如 192.168.1.10、eth1），用于钉钉机器人的IP白名单等场景
+	LocalAddr string `mapstructure:"local_addr"`
+	// 设置为true时，启动时查询并打印出口IP（配置了local_addr时总是打印）
+	ReportEgressIP bool `mapstructure:"report_egress_ip"`
+	// 查询出口IP的地址，需返回纯文本IP，默认为 https://api.ipify.org
+	EgressCheckURL string `mapstructure:"egress_check_url"`
+}
+
+// GitLabConfig GitLab项目的版本检查配置，检查期限、时区和预发布版本沿用 github 中的设置
+type GitLabConfig struct {
+	// 访问令牌（Personal/Project/Group Access Token，需要 read_api 权限），只监控公开项目时可以为空
+	Token string `mapstructure:"token"`
+	// GitLab地址，默认 https://gitlab.com，自建实例如 https://gitlab.example.com
+	BaseURL string `mapstructure:"base_url"`
+	// 监控的项目，为空时不检查GitLab
+	Projects []GitLabProjectConfig `mapstructure:"projects"`
+}
+
+// GitLabProjectConfig 监控的GitLab项目
+type GitLabProjectConfig struct {
+	// 项目路径，如 gitlab-org/gitlab-runner 或 group/subgroup/project
+	Path string `mapstructure:"path"`
+	// 设置为true时按版本号最高的标签检查，用于只推送标签、不创建Release的项目
+	Tags bool `mapstructure:"tags"`
+}
+
+// NPMConfig npm包的版本检查配置，检查期限和时区沿用 github 中的设置
+type NPMConfig struct {
+	// registry地址，默认 https://registry.npmjs.org，私有registry如 https://npm.example.com
+	Registry string `mapstructure:"registry"`
+	// 访问令牌，私有registry或私有包需要，以 Bearer 方式发送
+	Token string `mapstructure:"token"`
+	// 监控的包，为空时不检查npm
+	Packages []NPMPackageConfig `mapstructure:"packages"`
+}
+
+// NPMPackageConfig 监控的npm包
+type NPMPackageConfig struct {
+	// 包名，如 react 或 @types/node
+	Name string `mapstructure:"name"`
+	// 关注的 dist-tag，如 latest、next，每个标签分别通知，默认只检查 latest
+	DistTags []string `mapstructure:"dist_tags"`
+}
+
+// PyPIConfig PyPI包的版本检查配置，检查期限、时区和预发布版本沿用 github 中的设置
+type PyPIConfig struct {
+	// 包索引地址，默认 https://pypi.org，需要提供 /pypi/<包名>/json 接口，如 https://test.pypi.org
+	IndexURL string `mapstructure:"index_url"`
+	// 监控的包，为空时不检查PyPI
+	Packages []PyPIPackageConfig `mapstructure:"packages"`
+}
+
+// PyPIPackageConfig 监控的PyPI包
+type PyPIPackageConfig struct {
+	// 包名，如 django、requests
+	Name string `mapstructure:"name"`
+	// 版本范围，如 ">=4.2,<5"、"==4.2.*"、"~=2.1"，只通知范围内最新的版本，为空时不限制
+	Versions string `mapstructure:"versions"`
+	// 设置为true时该包的预发布版本（a、b、rc、dev）也会通知，否则沿用 github.include_prereleases
+	Prereleases bool `mapstructure:"prereleases"`
+}
+
+// CratesConfig crates.io 上Rust包的版本检查配置，检查期限、时区和预发布版本沿用 github 中的设置
+type CratesConfig struct {
+	// 监控的包名，如 serde、tokio，为空时不检查crates.io
+	Packages []string `mapstructure:"packages"`
+	// crates.io 地址，默认 https://crates.io，用于兼容 crates.io API 的镜像
+	BaseURL string `mapstructure:"base_url"`
+	// 请求的User-Agent，crates.io 要求包含联系方式，默认 "notify (https://github.com/orange-juzipi/notify)"
+	UserAgent string `mapstructure:"user_agent"`
+}
+
+// GoProxyConfig 通过Go模块代理检查Go模块的配置，检查期限、时区和预发布版本沿用 github 中的设置
+type GoProxyConfig struct {
+	// 监控的模块路径，如 golang.org/x/net、github.com/spf13/cobra，主版本号不同的模块（如 .../v2）需要分别列出，为空时不检查
+	Modules []string `mapstructure:"modules"`
+	// 模块代理地址，默认 https://proxy.golang.org，私有代理如 https://goproxy.example.com
+	Proxy string `mapstructure:"proxy"`
+}
+
+// MavenConfig Maven构件的新版本检查配置，检查期限、时区和预发布版本沿用 github 中的设置
+type MavenConfig struct {
+	// 监控的构件坐标 groupId:artifactId，如 com.google.guava:guava，为空时不检查
+	Packages []string `mapstructure:"packages"`
+	// 仓库地址，默认 https://repo1.maven.org/maven2，私有仓库如 https://nexus.example.com/repository/maven-public
+	Repository string `mapstructure:"repository"`
+	// 私有仓库的用户名和密码（或访问令牌），使用Basic认证
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// VSCodeConfig VS Code扩展的新版本检查配置，检查期限、时区和预发布版本沿用 github 中的设置
+type VSCodeConfig struct {
+	// 监控的扩展ID publisher.name，如 golang.go，为空时不检查
+	Extensions []string `mapstructure:"extensions"`
+	// 扩展市场接口地址，默认 https://marketplace.visualstudio.com/_apis/public/gallery，Open VSX 为 https://open-vsx.org/vscode/gallery
+	Gallery string `mapstructure:"gallery"`
 }
 
 // GitHubConfig GitHub相关配置
@@ -26,58 +283,687 @@ type GitHubConfig struct {
 	WatchStarred bool `mapstructure:"watch_starred"`
 	// 要监控的组织，如果为空则不监控组织仓库
 	WatchOrgs []string `mapstructure:"watch_orgs"`
+	// 设置为true时，预发布版本（rc、beta等）也会通知，转为正式版时发送跟进通知而不是重复的新版本通知
+	IncludePrereleases bool `mapstructure:"include_prereleases"`
 	// 设置为true时，检查仓库是否有release并只监控有release的仓库
 	OnlyWithReleases bool `mapstructure:"only_with_releases"`
 	// 检查最近多少天内的版本发布，默认为3天
 	CheckDays int `mapstructure:"check_days"`
 	// 时区配置，默认为中国时区（UTC+8）
 	Timezone string `mapstructure:"timezone"`
+	// 设置为true时，检测release附件中是否包含签名/来源证明文件（.sig、.pem、provenance等）
+	DetectSignatures bool `mapstructure:"detect_signatures"`
+	// 依赖清单路径（go.mod、package.json、requirements.txt），从直接依赖中推导要监控的仓库
+	Manifests []string `mapstructure:"manifests"`
+	// 设置为true时，对有锁定版本的仓库只在锁定版本确实落后时才通知
+	OnlyWhenBehind bool `mapstructure:"only_when_behind"`
+	// 设置为true时，记录已通知版本的发布说明，大幅修改后发送更新提醒
+	TrackEdits bool `mapstructure:"track_edits"`
+	// 发布说明按行变化比例超过该阈值（0~1）时才发送更新提醒，默认0.2
+	EditThreshold float64 `mapstructure:"edit_threshold"`
+	// 关注带有指定标签的Issue的新建和关闭
+	WatchLabels WatchLabelsConfig `mapstructure:"watch_labels"`
+	// 设置为true时，对比每次发现的监控列表，有仓库加入或移出时发送通知（如新star了仓库、组织仓库被删除）
+	NotifyWatchListChanges bool `mapstructure:"notify_watchlist_changes"`
+	// 设置为true时，标记授权用户提交过代码的仓库的新版本，并优先发送
+	MarkContributed bool `mapstructure:"mark_contributed"`
+	// 仓库重要性评分，按分数排列批量消息中的版本，达到每日上限时优先发送分数高的版本
+	Scoring ScoringConfig `mapstructure:"scoring"`
+	// 首次扫描大量仓库时的预热模式
+	Warmup WarmupConfig `mapstructure:"warmup"`
+	// 设置为true时，未配置Token或API配额用完后改为读取公开仓库的 releases.atom 订阅检查版本
+	// 订阅不消耗API配额，但无法检查私有仓库，也不支持附件规则和编辑跟踪
+	FeedFallback bool `mapstructure:"feed_fallback"`
+	// 要关注的Gist（ID或地址），有新的修订时通知
+	Gists []string `mapstructure:"gists"`
+	// 跟踪仓库的标签，先推送标签、几天后才创建Release的项目在推送标签时就通知
+	Tags TagsConfig `mapstructure:"tags"`
+	// 跟踪仓库和npm包的弃用状态，被归档或标记为弃用时通知
+	Deprecation DeprecationConfig `mapstructure:"deprecation"`
+	// API请求的超时、重试和User-Agent
+	HTTP GitHubHTTPConfig `mapstructure:"http"`
+	// 发布说明为空时生成说明: compare（按与上一个版本之间的提交生成）、api（调用 generate-notes 接口，需要仓库的写权限，失败时按提交生成），为空时不生成
+	GenerateNotes string `mapstructure:"generate_notes"`
+}
+
+// 发布说明为空时的生成方式
+const (
+	GenerateNotesCompare = "compare"
+	GenerateNotesAPI     = "api"
+)
+
+// GitHubHTTPConfig GitHub API客户端的请求设置
+type GitHubHTTPConfig struct {
+	// 每次请求的超时时间，如 30s，默认30s
+	Timeout string `mapstructure:"timeout"`
+	// 网络错误和5xx响应的最多重试次数，只重试GET请求，默认2，设置为负数时不重试
+	// 速率限制（403、429）不在这里重试，由配额检查和 feed_fallback 处理
+	MaxRetries int `mapstructure:"max_retries"`
+	// 请求的User-Agent，建议附带联系方式，如 "notify (+https://example.com/ops; ops@example.com)"，为空时使用 go-github 的默认值
+	UserAgent string `mapstructure:"user_agent"`
+}
+
+// DeprecationConfig 弃用状态跟踪配置
+type DeprecationConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 设置为true时检查README开头是否有弃用说明（如 "This project is no longer maintained"），每个仓库多消耗1次API请求
+	// 仓库描述总是会检查
+	Readme bool `mapstructure:"readme"`
+	// 设置为true时检查所有监控的仓库，否则只检查repos中手动列出的仓库，每个仓库每次检查消耗1次API请求
+	AllRepos bool `mapstructure:"all_repos"`
+	// 关注的npm包（如 request、@scope/name），最新版本被标记为弃用（npm deprecate）时通知，不消耗GitHub API配额
+	NPM []string `mapstructure:"npm"`
+	// npm registry地址，默认 https://registry.npmjs.org
+	NPMRegistry string `mapstructure:"npm_registry"`
+}
+
+// TagsConfig 标签跟踪配置
+type TagsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 已通知过的标签之后创建了Release时的处理方式: merge（默认，发送一条"已创建Release"的跟进通知）、suppress（不再通知）
+	OnRelease string `mapstructure:"on_release"`
+	// 设置为true时检查所有监控的仓库，否则只检查repos中手动列出的仓库
+	// 每个仓库每次检查消耗1次API请求，发现新标签时再消耗2次
+	AllRepos bool `mapstructure:"all_repos"`
+}
+
+// 已通知过的标签创建Release时的处理方式
+const (
+	TagsOnReleaseMerge    = "merge"
+	TagsOnReleaseSuppress = "suppress"
+)
+
+// ScoringConfig 仓库重要性评分配置
+// 分数 = 仓库的 weight + star数的数量级（log10）× stars_weight + 自己的仓库的 own_bonus
+type ScoringConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// star数每增加一个数量级增加的分数，如 stars_weight 为1时 1000 star 的仓库加3分，0表示不考虑star数
+	// 每个有新版本的仓库消耗1次API请求读取star数
+	StarsWeight float64 `mapstructure:"stars_weight"`
+	// Token对应用户或 owners 中的用户/组织的仓库增加的分数
+	OwnBonus float64 `mapstructure:"own_bonus"`
+	// 视为自己的仓库的用户或组织（可选），Token对应的用户始终包含在内
+	Owners []string `mapstructure:"owners"`
+}
+
+// WarmupConfig 预热模式配置
+// 启用后尚未建立基线（没有状态记录）的仓库按配额预算分散到多次运行中检查，避免首次运行消耗大量配额
+type WarmupConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 每次运行最多检查的新仓库数，为0时按剩余配额和 budget 计算
+	BatchSize int `mapstructure:"batch_size"`
+	// 每次运行最多使用剩余配额的比例（0~1），默认0.5
+	Budget float64 `mapstructure:"budget"`
+	// 设置为true时，新建立基线的仓库只记录当前版本，不发送通知
+	Silent bool `mapstructure:"silent"`
+}
+
+// WatchLabelsConfig Issue标签监控配置
+type WatchLabelsConfig struct {
+	// 要关注的Issue标签，如 roadmap、breaking-change
+	Labels []string `mapstructure:"labels"`
+	// 设置为true时检查所有监控的仓库，否则只检查repos中手动列出的仓库
+	// 每个仓库的每个标签都会消耗一次API请求
+	AllRepos bool `mapstructure:"all_repos"`
 }
 
 // RepoConfig 仓库配置
 type RepoConfig struct {
 	Owner string `mapstructure:"owner"`
 	Name  string `mapstructure:"name"`
+	// 当前锁定（使用中）的版本，可选
+	PinnedVersion string `mapstructure:"pinned_version"`
+	// 附件匹配规则（glob，如 *linux_amd64.tar.gz），可选
+	// 设置后只有release中出现匹配的附件时才通知，附件上传前不会记录为已通知
+	AssetPattern string `mapstructure:"asset_pattern"`
+	// MinBump 只通知达到该升级类型的版本: major、minor、patch，为空时通知所有版本
+	// 升级类型相对于上一个检测到的版本判断，预发布转正式版始终通知
+	MinBump string `mapstructure:"min_bump"`
+	// EveryNthPatch 补丁版本每累计N个通知一次（0表示不限制），配置后补丁版本不受 min_bump 限制
+	EveryNthPatch int `mapstructure:"every_nth_patch"`
+	// Weight 仓库的重要性权重（需要开启 github.scoring），可以为负数，分数高的仓库的版本优先发送
+	Weight float64 `mapstructure:"weight"`
+	// ID GitHub仓库ID，仅自动发现的仓库有值，用于跟踪仓库重命名或转移
+	ID int64 `mapstructure:"-"`
 }
 
 // NotificationsConfig 通知渠道配置
 type NotificationsConfig struct {
-	DingTalk DingTalkConfig `mapstructure:"dingtalk"`
-	Telegram TelegramConfig `mapstructure:"telegram"`
+	// 钉钉和Telegram可以配置为单个机器人，也可以配置为多个实例的列表，分别发送到不同的群或机器人
+	DingTalk DingTalkConfigs `mapstructure:"dingtalk"`
+	Telegram TelegramConfigs `mapstructure:"telegram"`
+	Slack    SlackConfig     `mapstructure:"slack"`
+	Email    EmailConfig     `mapstructure:"email"`
+	WeCom    WeComConfig     `mapstructure:"wecom"`
+	Feishu   FeishuConfig    `mapstructure:"feishu"`
+	Webhook  WebhookConfig   `mapstructure:"webhook"`
+	Ntfy     NtfyConfig      `mapstructure:"ntfy"`
+	Teams    TeamsConfig     `mapstructure:"teams"`
+	Desktop  DesktopConfig   `mapstructure:"desktop"`
+	MQTT     MQTTConfig      `mapstructure:"mqtt"`
+	// Rocket.Chat incoming webhook
+	RocketChat RocketChatConfig `mapstructure:"rocketchat"`
+	// Google Chat 聊天室webhook
+	GoogleChat GoogleChatConfig `mapstructure:"googlechat"`
+	IRC        IRCConfig        `mapstructure:"irc"`
+	Atom       AtomConfig       `mapstructure:"atom"`
+	Pushbullet PushbulletConfig `mapstructure:"pushbullet"`
+	Syslog     SyslogConfig     `mapstructure:"syslog"`
+	// 标准输出，每个版本输出一行JSON，供脚本处理
+	Stdout StdoutConfig `mapstructure:"stdout"`
+	// Mastodon 等兼容 Mastodon API 的联邦宇宙实例
+	Mastodon MastodonConfig `mapstructure:"mastodon"`
+	// Kafka 主题，供平台内的下游服务自行分发通知
+	Kafka KafkaConfig `mapstructure:"kafka"`
+	// PagerDuty、Opsgenie: 关键依赖发布新版本时创建低紧急程度的事件或告警，提醒值班人员安排升级
+	PagerDuty PagerDutyConfig `mapstructure:"pagerduty"`
+	Opsgenie  OpsgenieConfig  `mapstructure:"opsgenie"`
+	// Webex 机器人，发送markdown消息到指定空间
+	Webex WebexConfig `mapstructure:"webex"`
 }
 
 // DingTalkConfig 钉钉机器人配置
 type DingTalkConfig struct {
+	// 实例名称，配置多个实例时用于区分，渠道名称为 dingtalk/<名称>
+	Name       string `mapstructure:"name"`
 	Enabled    bool   `mapstructure:"enabled"`
 	WebhookURL string `mapstructure:"webhook_url"`
 	Secret     string `mapstructure:"secret"`
+	// 设置为true时，包含多个版本的批量消息使用FeedCard（每个版本一张卡片），在手机上显示效果更好
+	FeedCard bool `mapstructure:"feed_card"`
+	// 机器人使用"自定义关键词"安全设置时的关键词，会自动追加到每条消息中
+	Keyword string `mapstructure:"keyword"`
+	// 企业内部应用的凭证，配置后通过应用机器人的接口发送（不使用webhook_url），不受webhook每分钟20条的限制
+	AppKey    string `mapstructure:"app_key"`
+	AppSecret string `mapstructure:"app_secret"`
+	// 应用机器人的robotCode，为空时使用 app_key
+	RobotCode string `mapstructure:"robot_code"`
+	// 应用模式下接收消息的群会话ID（openConversationId），机器人需要已加入该群
+	ConversationID string `mapstructure:"conversation_id"`
+	// 应用模式下以单聊接收消息的用户ID（userid），可以与 conversation_id 同时配置
+	UserIDs []string `mapstructure:"user_ids"`
+	// 应用模式的API地址，默认为 https://api.dingtalk.com
+	APIBaseURL string `mapstructure:"api_base_url"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
 }
 
 // TelegramConfig Telegram机器人配置
 type TelegramConfig struct {
+	// 实例名称，配置多个实例时用于区分，渠道名称为 telegram/<名称>
+	Name     string `mapstructure:"name"`
 	Enabled  bool   `mapstructure:"enabled"`
 	BotToken string `mapstructure:"bot_token"`
 	ChatID   string `mapstructure:"chat_id"`
+	// 消息解析模式: Markdown（默认）或 HTML
+	ParseMode string `mapstructure:"parse_mode"`
+	// 设置为true时，单个版本的消息以仓库预览图+说明文字的形式发送
+	SendPhoto bool `mapstructure:"send_photo"`
+	// 设置为true时，版本消息下方附带"已查看"按钮并记录确认情况，通过 notify history --unacked 查看无人查看的版本
+	Ack bool `mapstructure:"ack"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+	// Bot API地址，默认为 https://api.telegram.org，使用自建Bot API服务时修改
+	APIBaseURL string `mapstructure:"api_base_url"`
+	// 仅用于Telegram的代理地址（socks5://、socks5h://、http://），GitHub请求不受影响
+	Proxy string `mapstructure:"proxy"`
+	// 仅用于Telegram的SSH跳板机，与 proxy 二选一
+	SSH TelegramSSHConfig `mapstructure:"ssh"`
+}
+
+// TelegramSSHConfig 通过SSH跳板机访问Telegram的配置
+type TelegramSSHConfig struct {
+	// 跳板机地址，如 jump.example.com:22，为空表示不使用
+	Host     string `mapstructure:"host"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	// 私钥文件路径，与 password 二选一
+	KeyFile       string `mapstructure:"key_file"`
+	KeyPassphrase string `mapstructure:"key_passphrase"`
+	// 校验跳板机主机密钥的 known_hosts 文件，默认为 ~/.ssh/known_hosts
+	KnownHosts string `mapstructure:"known_hosts"`
+}
+
+// SlackConfig Slack通知配置
+// webhook_url 与 bot_token 二选一：配置了 bot_token 时使用 chat.postMessage 发送到 channel
+type SlackConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	WebhookURL string `mapstructure:"webhook_url"`
+	BotToken   string `mapstructure:"bot_token"`
+	// 使用 bot_token 时发送的频道ID或名称
+	Channel string `mapstructure:"channel"`
+	// 设置为true时，单个版本的消息附带仓库的OpenGraph预览图
+	PreviewImage bool `mapstructure:"preview_image"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// EmailConfig 邮件（SMTP）通知配置
+type EmailConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// 发件人，支持 "名称 <地址>" 格式
+	From string   `mapstructure:"from"`
+	To   []string `mapstructure:"to"`
+	// 连接加密方式: starttls（默认）、tls、none
+	Security string `mapstructure:"security"`
+	// 每天最多发送的邮件数，超过后当天剩余的版本合并为一封摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// WeComConfig 企业微信群机器人配置
+type WeComConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 机器人webhook地址，形如 https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=xxx
+	WebhookURL string `mapstructure:"webhook_url"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// RocketChatConfig Rocket.Chat incoming webhook 配置
+type RocketChatConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 集成设置中生成的webhook地址，形如 https://chat.example.com/hooks/xxx/yyy
+	WebhookURL string `mapstructure:"webhook_url"`
+	// 消息显示的发送者名称，为空时使用集成中设置的名称
+	Alias string `mapstructure:"alias"`
+	// 发送者头像使用的emoji，如 :package:
+	Emoji string `mapstructure:"emoji"`
+	// 覆盖集成中设置的默认频道，如 #releases 或 @username
+	Channel string `mapstructure:"channel"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// GoogleChatConfig Google Chat 聊天室webhook配置
+type GoogleChatConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 聊天室"应用和集成"中创建的webhook地址，形如 https://chat.googleapis.com/v1/spaces/xxx/messages?key=...&token=...
+	WebhookURL string `mapstructure:"webhook_url"`
+	// 设置为true时按仓库归入会话，同一仓库的更新始终回复在同一个会话中（批量消息按仓库拆分发送）
+	ThreadByRepo bool `mapstructure:"thread_by_repo"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// IRCConfig IRC频道通知配置，每个版本发送一行公告
+type IRCConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 服务器地址，如 irc.libera.chat:6697，未指定端口时TLS使用6697，否则使用6667
+	Server string `mapstructure:"server"`
+	// 是否使用TLS加密连接
+	TLS bool `mapstructure:"tls"`
+	// 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+	// 昵称，默认为 notify-bot，被占用时自动追加下划线
+	Nick string `mapstructure:"nick"`
+	// 服务器密码（PASS），可选
+	Password string `mapstructure:"password"`
+	// NickServ 认证密码，为空时不认证
+	NickServPassword string `mapstructure:"nickserv_password"`
+	// 发送到的频道，如 #releases
+	Channel string `mapstructure:"channel"`
+	// 频道密码（+k），可选
+	ChannelKey string `mapstructure:"channel_key"`
+	// 使用NOTICE而不是PRIVMSG发送，避免其他机器人响应
+	Notice bool `mapstructure:"notice"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// AtomConfig Atom订阅输出配置，将版本通知写入本地的订阅文件，供阅读器订阅
+type AtomConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 订阅文件路径，默认为 ~/.notify/releases.atom（分片运行时自动加上分片后缀）
+	Path string `mapstructure:"path"`
+	// 订阅标题
+	Title string `mapstructure:"title"`
+	// 保留最近的条目数，默认50
+	MaxEntries int `mapstructure:"max_entries"`
+	// 订阅的公开地址（可选），如 notify serve 提供的 http://host:8080/feed.atom
+	SelfURL string `mapstructure:"self_url"`
+	// 条目内容的语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// PushbulletConfig Pushbullet 推送配置
+type PushbulletConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 账号设置（Settings → Access Tokens）中创建的访问令牌
+	AccessToken string `mapstructure:"access_token"`
+	// 只推送到指定设备（可选），为空时推送到账号的所有设备
+	DeviceIden string `mapstructure:"device_iden"`
+	// 推送到自己创建的频道（可选），订阅该频道的用户都会收到，与 device_iden 二选一
+	ChannelTag string `mapstructure:"channel_tag"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// SyslogConfig syslog输出配置，每个版本写入一条 RFC 5424 格式的日志，供日志平台或SIEM采集
+type SyslogConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 传输方式: udp、tcp、tls，为空时写入本机的syslog（/dev/log）
+	Network string `mapstructure:"network"`
+	// 远程服务器地址，如 logs.example.com:514，未指定端口时UDP和TCP使用514，TLS使用6514；写入本机时可指定套接字路径
+	Address string `mapstructure:"address"`
+	// 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+	// TCP和TLS的分帧方式: octet（长度前缀，默认）、newline（每条日志以换行结尾）
+	Framing string `mapstructure:"framing"`
+	// 设施，如 user、daemon、local0~local7，默认 local0
+	Facility string `mapstructure:"facility"`
+	// 级别，如 info、notice、warning，默认 notice
+	Severity string `mapstructure:"severity"`
+	// 应用名称（APP-NAME），默认 notify
+	AppName string `mapstructure:"app_name"`
+	// 主机名，默认使用本机主机名
+	Hostname string `mapstructure:"hostname"`
+	// 日志正文的语言（zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// StdoutConfig 标准输出配置，每个版本输出一行JSON（NDJSON），可以通过管道交给 jq 等工具处理
+// 启用后其他进度信息输出到标准错误
+type StdoutConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// MastodonConfig Mastodon 发布配置，每个版本发布一条嘟文
+type MastodonConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 实例地址，如 https://mastodon.social
+	InstanceURL string `mapstructure:"instance_url"`
+	// 应用的访问令牌（首选项 → 开发 → 新建应用，需要 write:statuses 权限）
+	AccessToken string `mapstructure:"access_token"`
+	// 可见性: public、unlisted（默认）、private、direct
+	Visibility string `mapstructure:"visibility"`
+	// 话题标签模板，如 "#release #{{.Repository}}"
+	Hashtags string `mapstructure:"hashtags"`
+	// 嘟文语言（ISO 639-1，如 zh、en），可选
+	Language string `mapstructure:"language"`
+	// 实例允许的嘟文最大字符数，默认500
+	MaxChars int `mapstructure:"max_chars"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// KafkaConfig Kafka写入配置，每个版本以JSON格式写入主题，消息键为 owner/repo
+type KafkaConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 引导服务器地址，如 ["kafka-1:9092", "kafka-2:9092"]
+	Brokers []string `mapstructure:"brokers"`
+	// 写入的主题
+	Topic string `mapstructure:"topic"`
+	// 客户端ID，默认为 notify
+	ClientID string `mapstructure:"client_id"`
+	// 确认级别: all（默认）、leader
+	Acks string          `mapstructure:"acks"`
+	TLS  KafkaTLSConfig  `mapstructure:"tls"`
+	SASL KafkaSASLConfig `mapstructure:"sasl"`
+}
+
+// KafkaTLSConfig Kafka加密连接配置
+type KafkaTLSConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 自签名服务器证书的CA证书文件
+	CAFile string `mapstructure:"ca_file"`
+	// 客户端证书和私钥，用于双向认证
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
+}
+
+// KafkaSASLConfig Kafka SASL认证配置
+type KafkaSASLConfig struct {
+	// 认证机制: PLAIN、SCRAM-SHA-256、SCRAM-SHA-512，为空时不认证
+	Mechanism string `mapstructure:"mechanism"`
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password"`
+}
+
+// PagerDutyConfig PagerDuty 事件配置（Events API v2），只为 repos 中的仓库创建事件
+type PagerDutyConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 服务集成（Events API v2）的 Integration Key
+	RoutingKey string `mapstructure:"routing_key"`
+	// 需要创建事件的仓库（owner/repo，支持 * 通配，如 kubernetes/*）
+	Repos []string `mapstructure:"repos"`
+	// 事件级别: info（默认）、warning、error、critical，在服务的事件规则中按级别设置紧急程度
+	Severity string `mapstructure:"severity"`
+}
+
+// OpsgenieConfig Opsgenie 告警配置，只为 repos 中的仓库创建告警
+type OpsgenieConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// API集成的密钥
+	APIKey string `mapstructure:"api_key"`
+	// 需要创建告警的仓库（owner/repo，支持 * 通配，如 kubernetes/*）
+	Repos []string `mapstructure:"repos"`
+	// 告警优先级: P1~P5，默认 P5
+	Priority string `mapstructure:"priority"`
+	// 账号所在区域: us（默认）、eu
+	Region string `mapstructure:"region"`
+	// 附加到告警的标签
+	Tags []string `mapstructure:"tags"`
+}
+
+// WebexConfig Webex 机器人配置
+type WebexConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 在 developer.webex.com 创建机器人后获得的访问令牌
+	BotToken string `mapstructure:"bot_token"`
+	// 发送到的空间ID，机器人需要已加入该空间
+	RoomID string `mapstructure:"room_id"`
+	// API地址，默认为 https://webexapis.com
+	APIBaseURL string `mapstructure:"api_base_url"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// FeishuConfig 飞书（Lark）自定义机器人配置
+type FeishuConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	WebhookURL string `mapstructure:"webhook_url"`
+	// 机器人"签名校验"安全设置的密钥，为空时不签名
+	Secret string `mapstructure:"secret"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// WebhookConfig 通用webhook配置，将版本信息以JSON发送到任意地址
+type WebhookConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	URL     string `mapstructure:"url"`
+	// 请求方法，默认为POST
+	Method string `mapstructure:"method"`
+	// 附加的请求头，如 Authorization
+	Headers map[string]string `mapstructure:"headers"`
+	// 自定义请求体模板（Go模板），为空时发送默认的JSON负载
+	BodyTemplate string `mapstructure:"body_template"`
+	// 签名密钥，设置后在签名请求头中携带 sha256=<HMAC-SHA256(请求体)>
+	Secret string `mapstructure:"secret"`
+	// 签名请求头，默认为 X-Notify-Signature
+	SignatureHeader string `mapstructure:"signature_header"`
+	// 每天最多发送的请求数，超过后当天剩余的版本合并为一个摘要请求发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+}
+
+// NtfyConfig ntfy推送配置，支持 ntfy.sh 和自建服务
+type NtfyConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 服务地址，默认为 https://ntfy.sh
+	ServerURL string `mapstructure:"server_url"`
+	Topic     string `mapstructure:"topic"`
+	// 访问令牌，用于需要认证的主题
+	Token string `mapstructure:"token"`
+	// 消息优先级: min、low、default、high、max 或 1-5，为空时使用服务端默认值
+	Priority string `mapstructure:"priority"`
+	// 消息标签，可以是emoji短代码（如 package）
+	Tags []string `mapstructure:"tags"`
+	// 设置为true时，消息附带仓库的OpenGraph预览图
+	PreviewImage bool `mapstructure:"preview_image"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 消息语言，对应 templates 中的模板（如 zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// TeamsConfig Microsoft Teams 传入webhook配置
+type TeamsConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	WebhookURL string `mapstructure:"webhook_url"`
+	// 消息格式: adaptive（默认，Adaptive Card）或 messagecard（旧版 Office 365 连接器）
+	Format string `mapstructure:"format"`
+	// 设置为true时，单个版本的卡片附带仓库的OpenGraph预览图
+	PreviewImage bool `mapstructure:"preview_image"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 卡片文字的语言（zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// DesktopConfig 桌面通知配置，在工作站上运行时弹出系统原生通知
+// Linux 需要 notify-send，macOS 使用 osascript，Windows 使用 PowerShell
+type DesktopConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 通知中显示的应用名称，默认为 Notify
+	AppName string `mapstructure:"app_name"`
+	// 每天最多发送的消息数，超过后当天剩余的版本合并为一条摘要发送，0表示不限制
+	DailyLimit int `mapstructure:"daily_limit"`
+	// 通知文字的语言（zh、en），为空时使用默认语言
+	Lang string `mapstructure:"lang"`
+}
+
+// MQTTConfig MQTT发布配置，将每个版本以JSON格式发布到主题，供 Home Assistant、Node-RED 等订阅
+type MQTTConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 服务器地址，如 tcp://localhost:1883，加密连接使用 mqtts://broker:8883
+	BrokerURL string `mapstructure:"broker_url"`
+	// 客户端ID，为空时自动生成
+	ClientID string `mapstructure:"client_id"`
+	// 发布的主题，支持 {owner}、{repo} 占位符，默认为 notify/releases
+	Topic string `mapstructure:"topic"`
+	// 服务质量等级: 0（默认）或 1
+	QoS int `mapstructure:"qos"`
+	// 设置为true时发布保留消息
+	Retain   bool          `mapstructure:"retain"`
+	Username string        `mapstructure:"username"`
+	Password string        `mapstructure:"password"`
+	TLS      MQTTTLSConfig `mapstructure:"tls"`
+	// Home Assistant MQTT自动发现，选定仓库的最新版本作为传感器出现
+	HomeAssistant HomeAssistantConfig `mapstructure:"home_assistant"`
+}
+
+// HomeAssistantConfig Home Assistant MQTT自动发现配置
+type HomeAssistantConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// 自动发现前缀，与 Home Assistant 中MQTT集成的设置一致，默认为 homeassistant
+	DiscoveryPrefix string `mapstructure:"discovery_prefix"`
+	// 作为传感器出现的仓库，owner/repo 形式，支持 * 通配，为空时包括全部仓库
+	Repos []string `mapstructure:"repos"`
+}
+
+// MQTTTLSConfig MQTT加密连接配置
+type MQTTTLSConfig struct {
+	// 自签名服务器证书的CA证书文件
+	CAFile string `mapstructure:"ca_file"`
+	// 客户端证书和私钥，用于双向认证
+	CertFile string `mapstructure:"cert_file"`
+	KeyFile  string `mapstructure:"key_file"`
+	// 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool `mapstructure:"insecure_skip_verify"`
 }
 
 // ScheduleConfig 定时运行配置
 type ScheduleConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
 	Cron    string `mapstructure:"cron"`
+	// MinQuota 定时运行前GitHub API剩余配额低于该值时，推迟到配额重置后再运行
+	// 默认为100，设置为负数关闭该检查
+	MinQuota int `mapstructure:"min_quota"`
+	// CoalesceWindow 合并窗口，如 30m，窗口内多次检查发现的新版本合并为每个渠道一条消息发送，为空时不合并
+	CoalesceWindow string `mapstructure:"coalesce_window"`
+}
+
+// HighlightConfig 发布说明关键字高亮配置
+type HighlightConfig struct {
+	// 需要高亮的关键字（不区分大小写），如 breaking、security、deprecat
+	Keywords []string `mapstructure:"keywords"`
+	// 设置为true时，命中关键字的版本会单独优先发送，不与普通版本合并
+	Escalate bool `mapstructure:"escalate"`
 }
 
 // DefaultTemplate 默认通知模板
 const DefaultTemplate = `## 📦 新版本发布通知
-
+{{if .IsHighlighted}}
+**{{.HighlightBanner}}**
+{{end}}{{if .Contributed}}
+**{{.ContributorBadge}}**
+{{end}}
 **仓库**: {{.Repository}}
 
 **版本**: {{.TagName}}
 
-**发布时间**: {{.PublishedAt.Format "2006-01-02 15:04:05"}}
+**发布时间**: {{formatTime .PublishedAt}}
+{{if .SignatureChecked}}
+**签名**: {{.SignatureStatus}}
+{{end}}
+{{.Description}}
 
+**[查看详情]({{.Link}})**`
+
+// DefaultTemplateEN 默认英文通知模板
+const DefaultTemplateEN = `## 📦 New Release
+{{if .IsHighlighted}}
+**⚠️ Release notes mention: {{range $i, $k := .Highlights}}{{if $i}}, {{end}}{{$k}}{{end}}**
+{{end}}{{if .Contributed}}
+**🙌 You contribute here**
+{{end}}
+**Repository**: {{.Repository}}
+
+**Version**: {{.TagName}}
+
+**Published**: {{formatTime .PublishedAt}}
+{{if .SignatureChecked}}
+**Signed**: {{if .Signed}}✅ yes{{else}}❌ no{{end}}
+{{end}}
 {{.Description}}
 
-**[查看详情]({{.HTMLURL}})**`
+**[View release]({{.Link}})**`
+
+// DefaultTemplates 各语言的内置通知模板
+var DefaultTemplates = map[string]string{
+	"zh": DefaultTemplate,
+	"en": DefaultTemplateEN,
+}
 
 // DefaultInterval 默认检查间隔时间 (6小时)
 // const DefaultInterval = "6h"
@@ -85,9 +971,32 @@ const DefaultTemplate = `## 📦 新版本发布通知
 // DefaultCheckDays 默认检查最近多少天内的版本发布（3天）
 const DefaultCheckDays = 3
 
+// DefaultEditThreshold 默认发布说明修改提醒阈值（20%的行发生变化）
+const DefaultEditThreshold = 0.2
+
+// DefaultGitHubTimeout 默认的GitHub API请求超时时间
+const DefaultGitHubTimeout = "30s"
+
+// DefaultGitHubMaxRetries 默认的GitHub API请求最多重试次数
+const DefaultGitHubMaxRetries = 2
+
+// DefaultMinQuota 默认定时运行所需的最少GitHub API剩余配额
+const DefaultMinQuota = 100
+
+// DefaultServeListen 默认webhook服务监听地址
+const DefaultServeListen = ":8080"
+
+// DefaultStateKeyEnv 默认读取状态文件加密密钥的环境变量
+const DefaultStateKeyEnv = "NOTIFY_STATE_KEY"
+
 // DefaultTimezone 默认时区（中国时区 UTC+8）
 const DefaultTimezone = "Asia/Shanghai"
 
+// UsedConfigFile 返回 LoadConfig 读取的配置文件路径，未读取配置文件时为空
+func UsedConfigFile() string {
+	return viper.ConfigFileUsed()
+}
+
 // LoadConfig 从文件加载配置
 func LoadConfig(cfgFile string) (*Config, error) {
 	cfg := &Config{}
@@ -115,10 +1024,37 @@ func LoadConfig(cfgFile string) (*Config, error) {
 
 	// 设置环境变量映射
 	viper.BindEnv("github.token", "GITHUB_TOKEN")
+	viper.BindEnv("gitlab.token", "GITLAB_TOKEN")
+	viper.BindEnv("npm.token", "NPM_TOKEN")
+	viper.BindEnv("maven.password", "MAVEN_PASSWORD")
 	viper.BindEnv("notifications.dingtalk.webhook_url", "DINGTALK_WEBHOOK")
 	viper.BindEnv("notifications.dingtalk.secret", "DINGTALK_SECRET")
+	viper.BindEnv("notifications.dingtalk.keyword", "DINGTALK_KEYWORD")
+	viper.BindEnv("notifications.dingtalk.app_secret", "DINGTALK_APP_SECRET")
 	viper.BindEnv("notifications.telegram.bot_token", "TELEGRAM_BOT_TOKEN")
 	viper.BindEnv("notifications.telegram.chat_id", "TELEGRAM_CHAT_ID")
+	viper.BindEnv("notifications.telegram.proxy", "TELEGRAM_PROXY")
+	viper.BindEnv("notifications.telegram.api_base_url", "TELEGRAM_API_BASE_URL")
+	viper.BindEnv("notifications.slack.webhook_url", "SLACK_WEBHOOK_URL")
+	viper.BindEnv("notifications.slack.bot_token", "SLACK_BOT_TOKEN")
+	viper.BindEnv("notifications.email.password", "SMTP_PASSWORD")
+	viper.BindEnv("notifications.wecom.webhook_url", "WECOM_WEBHOOK")
+	viper.BindEnv("notifications.feishu.webhook_url", "FEISHU_WEBHOOK")
+	viper.BindEnv("notifications.feishu.secret", "FEISHU_SECRET")
+	viper.BindEnv("notifications.webhook.secret", "WEBHOOK_SECRET")
+	viper.BindEnv("notifications.ntfy.token", "NTFY_TOKEN")
+	viper.BindEnv("notifications.mqtt.password", "MQTT_PASSWORD")
+	viper.BindEnv("notifications.teams.webhook_url", "TEAMS_WEBHOOK")
+	viper.BindEnv("notifications.rocketchat.webhook_url", "ROCKETCHAT_WEBHOOK")
+	viper.BindEnv("notifications.googlechat.webhook_url", "GOOGLECHAT_WEBHOOK")
+	viper.BindEnv("notifications.irc.nickserv_password", "IRC_NICKSERV_PASSWORD")
+	viper.BindEnv("notifications.pushbullet.access_token", "PUSHBULLET_ACCESS_TOKEN")
+	viper.BindEnv("notifications.mastodon.access_token", "MASTODON_ACCESS_TOKEN")
+	viper.BindEnv("notifications.kafka.sasl.password", "KAFKA_SASL_PASSWORD")
+	viper.BindEnv("notifications.pagerduty.routing_key", "PAGERDUTY_ROUTING_KEY")
+	viper.BindEnv("notifications.opsgenie.api_key", "OPSGENIE_API_KEY")
+	viper.BindEnv("notifications.webex.bot_token", "WEBEX_BOT_TOKEN")
+	viper.BindEnv("shortener.api_key", "SHORTENER_API_KEY")
 	viper.BindEnv("schedule.interval", "SCHEDULE_INTERVAL")
 	viper.BindEnv("github.check_days", "CHECK_DAYS")
 
@@ -130,7 +1066,7 @@ func LoadConfig(cfgFile string) (*Config, error) {
 	}
 
 	// 解析配置到结构体
-	if err := viper.Unmarshal(cfg); err != nil {
+	if err := viper.Unmarshal(cfg, viper.DecodeHook(decodeHook)); err != nil {
 		return nil, fmt.Errorf("解析配置失败: %v", err)
 	}
 
@@ -139,15 +1075,105 @@ func LoadConfig(cfgFile string) (*Config, error) {
 		cfg.Template = DefaultTemplate
 	}
 
+	if err := applyGitHubDefaults(&cfg.GitHub); err != nil {
+		return nil, err
+	}
+	applyScheduleDefaults(&cfg.Schedule)
+
+	// 设置默认webhook服务监听地址
+	if cfg.Serve.Listen == "" {
+		cfg.Serve.Listen = DefaultServeListen
+	}
+
+	// 设置默认状态文件加密密钥环境变量
+	if cfg.State.KeyEnv == "" {
+		cfg.State.KeyEnv = DefaultStateKeyEnv
+	}
+
+	if err := cfg.Shard.Validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Approval.Enabled && cfg.Approval.Channel == "" {
+		return nil, fmt.Errorf("启用发送审批时需要设置 approval.channel")
+	}
+
+	if err := cfg.Notifications.validateInstances(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateRoutes(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.validateTenants(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// applyGitHubDefaults 设置GitHub配置的默认值并检查仓库的通知规则
+func applyGitHubDefaults(g *GitHubConfig) error {
 	// 设置默认检查天数
-	if cfg.GitHub.CheckDays <= 0 {
-		cfg.GitHub.CheckDays = DefaultCheckDays
+	if g.CheckDays <= 0 {
+		g.CheckDays = DefaultCheckDays
+	}
+
+	// 设置默认发布说明修改提醒阈值
+	if g.EditThreshold <= 0 {
+		g.EditThreshold = DefaultEditThreshold
 	}
 
 	// 设置默认时区
-	if cfg.GitHub.Timezone == "" {
-		cfg.GitHub.Timezone = DefaultTimezone
+	if g.Timezone == "" {
+		g.Timezone = DefaultTimezone
 	}
 
-	return cfg, nil
+	// 设置API请求的默认超时和重试次数
+	if g.HTTP.Timeout == "" {
+		g.HTTP.Timeout = DefaultGitHubTimeout
+	}
+	if d, err := time.ParseDuration(g.HTTP.Timeout); err != nil || d <= 0 {
+		return fmt.Errorf("github.http.timeout 取值无效: %s（如 30s、1m）", g.HTTP.Timeout)
+	}
+	if g.HTTP.MaxRetries == 0 {
+		g.HTTP.MaxRetries = DefaultGitHubMaxRetries
+	}
+
+	// 设置已通知标签创建Release时的默认处理方式
+	switch g.Tags.OnRelease {
+	case "":
+		g.Tags.OnRelease = TagsOnReleaseMerge
+	case TagsOnReleaseMerge, TagsOnReleaseSuppress:
+	default:
+		return fmt.Errorf("github.tags.on_release 取值无效: %s（可选 %s、%s）", g.Tags.OnRelease, TagsOnReleaseMerge, TagsOnReleaseSuppress)
+	}
+
+	switch g.GenerateNotes {
+	case "", GenerateNotesCompare, GenerateNotesAPI:
+	default:
+		return fmt.Errorf("github.generate_notes 取值无效: %s（可选 %s、%s）", g.GenerateNotes, GenerateNotesCompare, GenerateNotesAPI)
+	}
+
+	// 检查仓库的通知规则
+	for _, r := range g.Repos {
+		switch r.MinBump {
+		case "", "major", "minor", "patch":
+		default:
+			return fmt.Errorf("仓库 %s/%s 的 min_bump 取值无效: %s（可选 major、minor、patch）", r.Owner, r.Name, r.MinBump)
+		}
+		if r.EveryNthPatch < 0 {
+			return fmt.Errorf("仓库 %s/%s 的 every_nth_patch 不能为负数", r.Owner, r.Name)
+		}
+	}
+	return nil
+}
+
+// applyScheduleDefaults 设置定时运行配置的默认值
+func applyScheduleDefaults(s *ScheduleConfig) {
+	// 设置默认定时运行最少剩余配额
+	if s.MinQuota == 0 {
+		s.MinQuota = DefaultMinQuota
+	}
 }
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// instanceName 渠道实例名称的格式，名称会作为 pacing 等配置的键，viper 读取键时会转为小写
+var instanceName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// DingTalkConfigs 钉钉机器人配置，配置文件中可以是单个机器人，也可以是多个实例的列表
+type DingTalkConfigs []DingTalkConfig
+
+// TelegramConfigs Telegram机器人配置，配置文件中可以是单个机器人，也可以是多个实例的列表
+type TelegramConfigs []TelegramConfig
+
+// Channel 实例的渠道名称，用于日志、发送记录、速率和每日限额，未命名时为 dingtalk
+func (c DingTalkConfig) Channel() string {
+	return instanceChannel("dingtalk", c.Name)
+}
+
+// Channel 实例的渠道名称，用于日志、发送记录、速率和每日限额，未命名时为 telegram
+func (c TelegramConfig) Channel() string {
+	return instanceChannel("telegram", c.Name)
+}
+
+// instanceChannel 渠道实例的名称，如 dingtalk/ops
+func instanceChannel(kind, name string) string {
+	if name == "" {
+		return kind
+	}
+	return kind + "/" + name
+}
+
+// decodeHook 解析配置使用的转换函数，在 viper 默认的转换之外，将单个渠道配置转换为只有一个实例的列表，兼容原有的配置格式
+var decodeHook = mapstructure.ComposeDecodeHookFunc(
+	mapstructure.StringToTimeDurationHookFunc(),
+	mapstructure.StringToSliceHookFunc(","),
+	singleInstanceHook,
+)
+
+// singleInstanceHook 渠道配置为单个对象时包装为列表
+func singleInstanceHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.Map {
+		return data, nil
+	}
+	switch to {
+	case reflect.TypeOf(DingTalkConfigs{}), reflect.TypeOf(TelegramConfigs{}):
+		return []interface{}{data}, nil
+	}
+	return data, nil
+}
+
+// validateInstances 检查钉钉和Telegram实例的名称
+func (n NotificationsConfig) validateInstances() error {
+	dingtalk := make([]string, 0, len(n.DingTalk))
+	for _, c := range n.DingTalk {
+		dingtalk = append(dingtalk, c.Name)
+	}
+	if err := validateInstanceNames("dingtalk", dingtalk); err != nil {
+		return err
+	}
+
+	telegram := make([]string, 0, len(n.Telegram))
+	for _, c := range n.Telegram {
+		telegram = append(telegram, c.Name)
+	}
+	return validateInstanceNames("telegram", telegram)
+}
+
+// validateInstanceNames 多个实例时每个实例都需要名称，名称不能重复
+func validateInstanceNames(kind string, names []string) error {
+	seen := make(map[string]bool)
+	for i, name := range names {
+		if name == "" {
+			if len(names) > 1 {
+				return fmt.Errorf("notifications.%s 配置了多个实例，第 %d 个实例需要设置 name", kind, i+1)
+			}
+			continue
+		}
+		if !instanceName.MatchString(name) {
+			return fmt.Errorf("notifications.%s 的实例名称无效: %q（只能包含小写字母、数字、- 和 _）", kind, name)
+		}
+		if seen[name] {
+			return fmt.Errorf("notifications.%s 的实例名称重复: %s", kind, name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadConfig_Instances 测试钉钉和Telegram既可以配置为单个机器人，也可以配置为多个实例的列表
+func TestLoadConfig_Instances(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`
+notifications:
+  dingtalk:
+    enabled: true
+    webhook_url: "https://oapi.dingtalk.com/robot/send?access_token=a"
+    daily_limit: 10
+  telegram:
+    - name: ops
+      enabled: true
+      bot_token: "1:a"
+      chat_id: "-1001"
+    - name: dev
+      enabled: true
+      bot_token: "2:b"
+      chat_id: "-1002"
+      lang: en
+`), 0o644)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	if len(cfg.Notifications.DingTalk) != 1 {
+		t.Fatalf("单个钉钉配置应解析为1个实例，实际为 %d 个", len(cfg.Notifications.DingTalk))
+	}
+	if d := cfg.Notifications.DingTalk[0]; !d.Enabled || d.DailyLimit != 10 || d.Channel() != "dingtalk" {
+		t.Errorf("钉钉配置不正确: %+v", d)
+	}
+
+	if len(cfg.Notifications.Telegram) != 2 {
+		t.Fatalf("期望2个Telegram实例，实际为 %d 个", len(cfg.Notifications.Telegram))
+	}
+	if ops := cfg.Notifications.Telegram[0]; ops.Channel() != "telegram/ops" || ops.ChatID != "-1001" {
+		t.Errorf("Telegram实例 ops 的配置不正确: %+v", ops)
+	}
+	if dev := cfg.Notifications.Telegram[1]; dev.Channel() != "telegram/dev" || dev.BotToken != "2:b" || dev.Lang != "en" {
+		t.Errorf("Telegram实例 dev 的配置不正确: %+v", dev)
+	}
+}
+
+// TestValidateInstances 测试无效的实例名称
+func TestValidateInstances(t *testing.T) {
+	for name, n := range map[string]NotificationsConfig{
+		"多个实例缺少名称": {DingTalk: DingTalkConfigs{{Name: "a"}, {}}},
+		"名称重复":     {Telegram: TelegramConfigs{{Name: "a"}, {Name: "a"}}},
+		"名称含大写字母":  {Telegram: TelegramConfigs{{Name: "Ops"}}},
+		"名称含路径":    {DingTalk: DingTalkConfigs{{Name: "a/b"}}},
+	} {
+		if err := n.validateInstances(); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+
+	if err := (NotificationsConfig{DingTalk: DingTalkConfigs{{}}}).validateInstances(); err != nil {
+		t.Errorf("单个实例不需要名称: %v", err)
+	}
+}
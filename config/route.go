@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RouteConfig 路由规则，匹配的仓库只发送到指定的渠道
+type RouteConfig struct {
+	// owner/repo 形式的仓库规则，支持 * 通配（如 kubernetes/*），包管理器的包以来源作为 owner（如 npm/react、pypi/django）
+	Repos []string `mapstructure:"repos"`
+	// 接收通知的渠道名称（如 slack、dingtalk/ops，dingtalk 表示所有钉钉实例）或 channel_groups 中的组名
+	Channels []string `mapstructure:"channels"`
+}
+
+// channelKinds 返回通知渠道的类型名称（notifications 下的键），渠道组不能与之重名
+func channelKinds() map[string]bool {
+	kinds := make(map[string]bool)
+	t := reflect.TypeOf(NotificationsConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		kinds[t.Field(i).Tag.Get("mapstructure")] = true
+	}
+	return kinds
+}
+
+// validateRoutes 检查渠道组和路由规则
+func (c *Config) validateRoutes() error {
+	kinds := channelKinds()
+	for name, channels := range c.ChannelGroups {
+		if !instanceName.MatchString(name) {
+			return fmt.Errorf("渠道组名称无效: %q（只能包含小写字母、数字、- 和 _）", name)
+		}
+		if kinds[name] {
+			return fmt.Errorf("渠道组 %s 与通知渠道重名，请换一个名称", name)
+		}
+		if len(channels) == 0 {
+			return fmt.Errorf("渠道组 %s 没有渠道", name)
+		}
+		for _, channel := range channels {
+			if _, ok := c.ChannelGroups[channel]; ok {
+				return fmt.Errorf("渠道组 %s 不能引用其他渠道组 %s", name, channel)
+			}
+		}
+	}
+
+	for i, route := range c.Routes {
+		if len(route.Repos) == 0 {
+			return fmt.Errorf("第 %d 条路由规则需要设置 repos", i+1)
+		}
+		if len(route.Channels) == 0 {
+			return fmt.Errorf("第 %d 条路由规则需要设置 channels", i+1)
+		}
+		for _, repo := range route.Repos {
+			if !strings.Contains(repo, "/") {
+				return fmt.Errorf("第 %d 条路由规则的仓库 %q 无效，格式应为 owner/repo（可以使用 * 通配）", i+1, repo)
+			}
+		}
+	}
+	return nil
+}
+
+// RouteChannels 返回路由规则的渠道名称，渠道组展开为组内的渠道，去除重复
+func (c *Config) RouteChannels(route RouteConfig) []string {
+	seen := make(map[string]bool)
+	var channels []string
+	add := func(channel string) {
+		if !seen[channel] {
+			seen[channel] = true
+			channels = append(channels, channel)
+		}
+	}
+	for _, name := range route.Channels {
+		if group, ok := c.ChannelGroups[name]; ok {
+			for _, channel := range group {
+				add(channel)
+			}
+			continue
+		}
+		add(name)
+	}
+	return channels
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestLoadConfig_Routes 测试渠道组在路由规则中展开
+func TestLoadConfig_Routes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`
+channel_groups:
+  ops: ["dingtalk/ops", "telegram/ops"]
+routes:
+  - repos: ["kubernetes/*", "npm/react"]
+    channels: ["ops", "slack", "telegram/ops"]
+  - repos: ["golang/go"]
+    channels: ["email"]
+`), 0o644)
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if len(cfg.Routes) != 2 {
+		t.Fatalf("期望2条路由规则，实际为 %d 条", len(cfg.Routes))
+	}
+
+	want := []string{"dingtalk/ops", "telegram/ops", "slack"}
+	if got := cfg.RouteChannels(cfg.Routes[0]); !reflect.DeepEqual(got, want) {
+		t.Errorf("第一条规则的渠道为 %v，期望 %v", got, want)
+	}
+	if got := cfg.RouteChannels(cfg.Routes[1]); !reflect.DeepEqual(got, []string{"email"}) {
+		t.Errorf("第二条规则的渠道为 %v", got)
+	}
+}
+
+// TestValidateRoutes 测试无效的渠道组和路由规则
+func TestValidateRoutes(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"组名与渠道重名": {ChannelGroups: map[string][]string{"slack": {"telegram"}}},
+		"组名含路径":   {ChannelGroups: map[string][]string{"a/b": {"slack"}}},
+		"空渠道组":    {ChannelGroups: map[string][]string{"ops": {}}},
+		"引用其他渠道组": {ChannelGroups: map[string][]string{"ops": {"slack"}, "all": {"ops", "email"}}},
+		"规则缺少仓库":  {Routes: []RouteConfig{{Channels: []string{"slack"}}}},
+		"规则缺少渠道":  {Routes: []RouteConfig{{Repos: []string{"a/b"}}}},
+		"仓库格式无效":  {Routes: []RouteConfig{{Repos: []string{"kubernetes"}, Channels: []string{"slack"}}}},
+	} {
+		if err := cfg.validateRoutes(); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
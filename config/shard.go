@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ShardConfig 分片运行配置，多个实例各自检查监控列表中的一部分仓库
+type ShardConfig struct {
+	// 当前分片序号，从1开始
+	Index int `mapstructure:"index"`
+	// 分片总数，小于等于1时不分片
+	Total int `mapstructure:"total"`
+}
+
+// Enabled 是否启用分片
+func (s ShardConfig) Enabled() bool {
+	return s.Total > 1
+}
+
+// Suffix 返回分片专用数据文件的后缀，如 ".shard-1-of-4"，未分片时返回空字符串
+func (s ShardConfig) Suffix() string {
+	if !s.Enabled() {
+		return ""
+	}
+	return fmt.Sprintf(".shard-%d-of-%d", s.Index, s.Total)
+}
+
+// String 返回 "i/n" 形式的分片描述
+func (s ShardConfig) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Total)
+}
+
+// Validate 检查分片配置是否有效
+func (s ShardConfig) Validate() error {
+	if !s.Enabled() {
+		return nil
+	}
+	if s.Index < 1 || s.Index > s.Total {
+		return fmt.Errorf("无效的分片配置 %s，序号应在 1 到 %d 之间", s, s.Total)
+	}
+	return nil
+}
+
+// ParseShard 解析 "i/n" 形式的分片参数
+func ParseShard(value string) (ShardConfig, error) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 2 {
+		return ShardConfig{}, fmt.Errorf("无效的分片参数 %q，格式应为 i/n，如 1/4", value)
+	}
+
+	index, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return ShardConfig{}, fmt.Errorf("无效的分片序号 %q: %v", parts[0], err)
+	}
+	total, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return ShardConfig{}, fmt.Errorf("无效的分片总数 %q: %v", parts[1], err)
+	}
+
+	shard := ShardConfig{Index: index, Total: total}
+	return shard, shard.Validate()
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// tenantName 租户名称的格式，名称会用在数据文件名中
+var tenantName = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TenantConfig 租户配置，一个进程为多个团队分别检查仓库和发送通知
+// github、gitlab、npm、pypi、crates、goproxy、maven、vscode、notifications、schedule 整体替换顶层配置，其他配置（网络、模板、格式、状态加密等）沿用顶层配置
+type TenantConfig struct {
+	// 租户名称，只能包含字母、数字、- 和 _，各租户的状态等数据文件按名称区分，如 state.tenant-team-a.json
+	Name string `mapstructure:"name"`
+	// 从该环境变量读取GitHub Token（可选），设置后覆盖 github.token，避免把各团队的Token写在配置文件中
+	TokenEnv      string              `mapstructure:"token_env"`
+	GitHub        GitHubConfig        `mapstructure:"github"`
+	GitLab        GitLabConfig        `mapstructure:"gitlab"`
+	NPM           NPMConfig           `mapstructure:"npm"`
+	PyPI          PyPIConfig          `mapstructure:"pypi"`
+	Crates        CratesConfig        `mapstructure:"crates"`
+	GoProxy       GoProxyConfig       `mapstructure:"goproxy"`
+	Maven         MavenConfig         `mapstructure:"maven"`
+	VSCode        VSCodeConfig        `mapstructure:"vscode"`
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	// 定时运行配置，未配置 cron 时沿用顶层的 schedule
+	Schedule ScheduleConfig `mapstructure:"schedule"`
+}
+
+// DataSuffix 返回当前租户和分片专用数据文件的后缀，如 ".tenant-team-a.shard-1-of-4"
+// 不使用租户也未分片时返回空字符串
+func (c *Config) DataSuffix() string {
+	return c.TenantSuffix() + c.Shard.Suffix()
+}
+
+// TenantSuffix 返回当前租户专用数据文件的后缀，如 ".tenant-team-a"，用于同一租户的各分片共用的文件（如忽略列表）
+// 不使用租户时返回空字符串
+func (c *Config) TenantSuffix() string {
+	if c.TenantName == "" {
+		return ""
+	}
+	return ".tenant-" + c.TenantName
+}
+
+// Tenant 返回指定租户的配置
+func (c *Config) Tenant(name string) (*Config, error) {
+	for _, t := range c.Tenants {
+		if t.Name == name {
+			return c.forTenant(t), nil
+		}
+	}
+	return nil, fmt.Errorf("未找到租户: %s", name)
+}
+
+// TenantConfigs 按配置顺序返回所有租户的配置
+func (c *Config) TenantConfigs() []*Config {
+	cfgs := make([]*Config, 0, len(c.Tenants))
+	for _, t := range c.Tenants {
+		cfgs = append(cfgs, c.forTenant(t))
+	}
+	return cfgs
+}
+
+// forTenant 以顶层配置为基础生成租户的配置
+func (c *Config) forTenant(t TenantConfig) *Config {
+	cfg := *c
+	cfg.Tenants = nil
+	cfg.TenantName = t.Name
+	cfg.GitHub = t.GitHub
+	cfg.GitLab = t.GitLab
+	cfg.NPM = t.NPM
+	cfg.PyPI = t.PyPI
+	cfg.Crates = t.Crates
+	cfg.GoProxy = t.GoProxy
+	cfg.Maven = t.Maven
+	cfg.VSCode = t.VSCode
+	cfg.Notifications = t.Notifications
+	if t.TokenEnv != "" {
+		if token := os.Getenv(t.TokenEnv); token != "" {
+			cfg.GitHub.Token = token
+		}
+	}
+	if t.Schedule.Cron != "" {
+		cfg.Schedule = t.Schedule
+	}
+	return &cfg
+}
+
+// validateTenants 检查租户名称并为各租户设置默认值
+func (c *Config) validateTenants() error {
+	seen := make(map[string]bool)
+	for i := range c.Tenants {
+		t := &c.Tenants[i]
+		if !tenantName.MatchString(t.Name) {
+			return fmt.Errorf("租户名称无效: %q（只能包含字母、数字、- 和 _）", t.Name)
+		}
+		if seen[t.Name] {
+			return fmt.Errorf("租户名称重复: %s", t.Name)
+		}
+		seen[t.Name] = true
+
+		if err := applyGitHubDefaults(&t.GitHub); err != nil {
+			return fmt.Errorf("租户 %s: %v", t.Name, err)
+		}
+		applyScheduleDefaults(&t.Schedule)
+		if err := t.Notifications.validateInstances(); err != nil {
+			return fmt.Errorf("租户 %s: %v", t.Name, err)
+		}
+	}
+	return nil
+}
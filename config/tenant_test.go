@@ -0,0 +1,96 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadConfig_Tenants 测试租户沿用顶层配置、替换各自的部分并设置默认值
+func TestLoadConfig_Tenants(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte(`
+network:
+  local_addr: "10.0.0.1"
+schedule:
+  enabled: true
+  cron: "0 0 * * * *"
+tenants:
+  - name: team-a
+    token_env: TEAM_A_TOKEN
+    github:
+      repos:
+        - owner: golang
+          name: go
+    notifications:
+      slack:
+        enabled: true
+  - name: team-b
+    github:
+      token: "b-token"
+      check_days: 7
+    schedule:
+      enabled: true
+      cron: "0 30 9 * * *"
+`), 0o644)
+	t.Setenv("TEAM_A_TOKEN", "a-token")
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+	if cfg.DataSuffix() != "" {
+		t.Errorf("顶层配置的数据文件后缀为 %q", cfg.DataSuffix())
+	}
+
+	a, err := cfg.Tenant("team-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.GitHub.Token != "a-token" || len(a.GitHub.Repos) != 1 || !a.Notifications.Slack.Enabled {
+		t.Errorf("租户 team-a 的配置不正确: %+v", a.GitHub)
+	}
+	if a.GitHub.CheckDays != DefaultCheckDays || a.GitHub.Tags.OnRelease != TagsOnReleaseMerge {
+		t.Errorf("租户 team-a 未设置默认值: %+v", a.GitHub)
+	}
+	if a.Network.LocalAddr != "10.0.0.1" || a.Schedule.Cron != "0 0 * * * *" {
+		t.Errorf("租户 team-a 应沿用顶层的网络和定时配置: %+v %+v", a.Network, a.Schedule)
+	}
+	if a.DataSuffix() != ".tenant-team-a" || len(a.Tenants) != 0 {
+		t.Errorf("租户 team-a 的数据文件后缀为 %q", a.DataSuffix())
+	}
+
+	b, err := cfg.Tenant("team-b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.GitHub.Token != "b-token" || b.GitHub.CheckDays != 7 || b.Schedule.Cron != "0 30 9 * * *" || b.Schedule.MinQuota != DefaultMinQuota {
+		t.Errorf("租户 team-b 的配置不正确: %+v %+v", b.GitHub, b.Schedule)
+	}
+	if b.Notifications.Slack.Enabled {
+		t.Error("租户的通知渠道不应相互影响")
+	}
+
+	b.Shard = ShardConfig{Index: 1, Total: 2}
+	if b.DataSuffix() != ".tenant-team-b.shard-1-of-2" {
+		t.Errorf("分片运行的租户数据文件后缀为 %q", b.DataSuffix())
+	}
+
+	if _, err := cfg.Tenant("team-c"); err == nil {
+		t.Error("不存在的租户应返回错误")
+	}
+}
+
+// TestValidateTenants 测试无效的租户名称
+func TestValidateTenants(t *testing.T) {
+	for name, tenants := range map[string][]TenantConfig{
+		"名称为空":  {{}},
+		"名称含路径": {{Name: "../a"}},
+		"名称重复":  {{Name: "a"}, {Name: "a"}},
+	} {
+		cfg := &Config{Tenants: tenants}
+		if err := cfg.validateTenants(); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
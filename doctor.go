@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// doctorCmd 检查配置、GitHub Token权限和通知渠道
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "检查配置、GitHub Token权限和通知渠道是否可用",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		fmt.Println("✓ 配置加载成功")
+
+		problems := 0
+
+		// GitHub Token
+		fmt.Println("\n[GitHub]")
+		if cfg.GitHub.Token == "" {
+			fmt.Println("- 未配置Token，将以匿名模式运行（每小时60次请求，自动发现功能不可用）")
+		} else {
+			report, err := github.ValidateToken(cfg)
+			if err != nil {
+				fmt.Printf("✗ %v\n", err)
+				problems++
+			} else {
+				report.Print(true)
+				problems += len(report.Failed())
+			}
+		}
+		if quota, err := github.GetQuota(cfg); err == nil {
+			fmt.Printf("- API配额: %d/%d\n", quota.Remaining, quota.Limit)
+		}
+
+		// 状态文件
+		fmt.Println("\n[状态]")
+		statePath, err := util.ResolvePath(cfg.State.Path, "state.json", cfg.DataSuffix())
+		if err != nil {
+			fmt.Printf("✗ %v\n", err)
+			problems++
+		} else {
+			fmt.Printf("- 状态文件: %s\n", statePath)
+			if cfg.State.Encrypt {
+				if key, err := util.ResolveStateKey(cfg.State.KeyEnv, cfg.State.Keyring); err != nil {
+					fmt.Printf("✗ %v\n", err)
+					problems++
+				} else if key == "" {
+					fmt.Printf("✗ 已启用状态文件加密，但未在环境变量 %s 中找到密钥\n", cfg.State.KeyEnv)
+					problems++
+				} else {
+					fmt.Println("✓ 状态文件加密已启用")
+				}
+			}
+		}
+		if until, paused := notifier.PausedUntil(); paused {
+			fmt.Printf("- 通知已暂停（%s），执行 notify resume 恢复\n", notifier.DescribePause(until))
+		}
+
+		// 通知渠道
+		fmt.Println("\n[通知渠道]")
+		manager, err := notifier.NewManager(cfg)
+		if err != nil {
+			fmt.Printf("✗ 通知渠道配置错误: %v\n", err)
+			problems++
+		} else if len(manager.Notifiers()) == 0 {
+			fmt.Println("✗ 没有启用任何通知渠道")
+			problems++
+		} else {
+			for _, n := range manager.Notifiers() {
+				fmt.Printf("✓ %s\n", n.Name())
+			}
+			for _, channel := range unroutedChannels(cfg, manager) {
+				fmt.Printf("- 路由规则中的渠道 %s 未启用，匹配的版本不会发送到该渠道\n", channel)
+			}
+		}
+
+		if cfg.Network.LocalAddr != "" || cfg.Network.ReportEgressIP {
+			fmt.Println("\n[网络]")
+			reportEgressIP(cfg)
+		}
+
+		if problems > 0 {
+			return fmt.Errorf("发现 %d 个问题", problems)
+		}
+		fmt.Println("\n✓ 检查通过")
+		return nil
+	},
+}
+
+// unroutedChannels 返回路由规则中引用、但没有启用的渠道，渠道类型名称（如 dingtalk）有任意一个实例启用即可
+func unroutedChannels(cfg *config.Config, manager *notifier.Manager) []string {
+	enabled := make(map[string]bool)
+	for _, n := range manager.Notifiers() {
+		enabled[n.Name()] = true
+		if kind, _, ok := strings.Cut(n.Name(), "/"); ok {
+			enabled[kind] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var missing []string
+	for _, route := range cfg.Routes {
+		for _, channel := range cfg.RouteChannels(route) {
+			if !enabled[channel] && !seen[channel] {
+				seen[channel] = true
+				missing = append(missing, channel)
+			}
+		}
+	}
+	return missing
+}
+
+func init() {
+	RootCmd.AddCommand(doctorCmd)
+}
+
+// checkToken 启动时检查GitHub Token是否具备已启用功能所需的权限，只打印有问题的功能
+func checkToken(cfg *config.Config) {
+	if cfg.GitHub.Token == "" {
+		return
+	}
+
+	report, err := github.ValidateToken(cfg)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+	if len(report.Failed()) > 0 {
+		fmt.Printf("⚠️ GitHub Token（%s）权限不足：\n", report.Type)
+		report.Print(false)
+		fmt.Println("提示：运行 notify doctor 查看完整的检查结果")
+	}
+}
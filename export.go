@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat string
+	exportOutput string
+)
+
+// exportWriters 各导出格式的写入函数
+var exportWriters = map[string]func(io.Writer, []github.WatchedRepo) error{
+	"yaml": writeWatchListYAML,
+	"opml": writeWatchListOPML,
+	"csv":  writeWatchListCSV,
+}
+
+// exportCmd 导出实际监控的仓库列表
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "导出实际监控的仓库列表（YAML、OPML、CSV）",
+	Long: `执行与检查时相同的仓库发现和过滤（自动发现、依赖清单、only_with_releases、分片），
+将实际监控的仓库列表按 owner/name 排序导出，便于审查、对比变化或导入其他工具。
+YAML 格式可以直接粘贴到配置文件的 github.repos 中；OPML 格式包含每个仓库的 releases.atom 订阅地址。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		write, ok := exportWriters[exportFormat]
+		if !ok {
+			return fmt.Errorf("不支持的导出格式: %s（可选 yaml、opml、csv）", exportFormat)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		if shardFlag != "" {
+			shard, err := config.ParseShard(shardFlag)
+			if err != nil {
+				return err
+			}
+			if shard.Enabled() {
+				cfg.Shard = shard
+			}
+		}
+
+		// 导出到标准输出时，发现过程中的进度信息输出到标准错误，避免混入导出内容
+		progress := cmd.OutOrStdout()
+		if exportOutput == "" {
+			progress = cmd.ErrOrStderr()
+		}
+		repos, err := github.ListWatchedRepos(cfg, progress)
+		if err != nil {
+			return err
+		}
+
+		if exportOutput == "" {
+			return write(cmd.OutOrStdout(), repos)
+		}
+
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			return fmt.Errorf("创建导出文件失败: %v", err)
+		}
+		defer f.Close()
+
+		if err := write(f, repos); err != nil {
+			return err
+		}
+		fmt.Fprintf(progress, "已导出 %d 个仓库到 %s\n", len(repos), exportOutput)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportFormat, "format", "f", "yaml", "导出格式: yaml、opml、csv")
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "导出文件路径（默认输出到标准输出）")
+	RootCmd.AddCommand(exportCmd)
+}
+
+// writeWatchListYAML 以配置文件 github.repos 的格式导出
+func writeWatchListYAML(w io.Writer, repos []github.WatchedRepo) error {
+	if _, err := fmt.Fprintf(w, "# notify 监控列表（共 %d 个仓库）\nrepos:\n", len(repos)); err != nil {
+		return err
+	}
+	for _, r := range repos {
+		entry := fmt.Sprintf("  # 来源: %s\n  - owner: %s\n    name: %s\n", r.Source, strconv.Quote(r.Owner), strconv.Quote(r.Name))
+		if r.PinnedVersion != "" {
+			entry += fmt.Sprintf("    pinned_version: %s\n", strconv.Quote(r.PinnedVersion))
+		}
+		if r.AssetPattern != "" {
+			entry += fmt.Sprintf("    asset_pattern: %s\n", strconv.Quote(r.AssetPattern))
+		}
+		if r.MinBump != "" {
+			entry += fmt.Sprintf("    min_bump: %s\n", strconv.Quote(r.MinBump))
+		}
+		if r.EveryNthPatch > 0 {
+			entry += fmt.Sprintf("    every_nth_patch: %d\n", r.EveryNthPatch)
+		}
+		if _, err := io.WriteString(w, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// opmlOutline OPML中的一个订阅条目
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// opmlDocument OPML文档
+type opmlDocument struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Title   string   `xml:"head>title"`
+	Body    struct {
+		Outlines []opmlOutline `xml:"outline"`
+	} `xml:"body"`
+}
+
+// writeWatchListOPML 导出为OPML，每个仓库对应其 releases.atom 订阅，按来源分组
+func writeWatchListOPML(w io.Writer, repos []github.WatchedRepo) error {
+	doc := opmlDocument{Version: "2.0", Title: "notify 监控列表"}
+
+	groups := make(map[string]int)
+	for _, r := range repos {
+		i, ok := groups[r.Source]
+		if !ok {
+			i = len(doc.Body.Outlines)
+			groups[r.Source] = i
+			doc.Body.Outlines = append(doc.Body.Outlines, opmlOutline{Text: r.Source})
+		}
+		name := r.Owner + "/" + r.Name
+		doc.Body.Outlines[i].Outlines = append(doc.Body.Outlines[i].Outlines, opmlOutline{
+			Text:    name,
+			Title:   name,
+			Type:    "rss",
+			XMLURL:  r.FeedURL(),
+			HTMLURL: r.HTMLURL(),
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("生成OPML失败: %v", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeWatchListCSV 导出为CSV
+func writeWatchListCSV(w io.Writer, repos []github.WatchedRepo) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"owner", "name", "source", "pinned_version", "asset_pattern", "url"})
+	for _, r := range repos {
+		cw.Write([]string{r.Owner, r.Name, r.Source, r.PinnedVersion, r.AssetPattern, r.HTMLURL()})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// exitCodeNewReleases 使用 --fail-on-new 且发现达到 --fail-level 级别的新版本时的退出码
+const exitCodeNewReleases = 2
+
+// exitError 带退出码的错误
+type exitError struct {
+	code int
+	msg  string
+}
+
+func (e *exitError) Error() string {
+	return e.msg
+}
+
+// gateLevels --fail-level 支持的级别，数值越小要求的升级越大
+var gateLevels = map[string]int{
+	"major": 0,
+	"minor": 1,
+	"patch": 2,
+	"any":   3,
+}
+
+// bumpLevels 升级类型对应的级别
+var bumpLevels = map[version.BumpKind]int{
+	version.BumpMajor:      0,
+	version.BumpMinor:      1,
+	version.BumpPatch:      2,
+	version.BumpPrerelease: 3,
+}
+
+// validateGateLevel 检查 --fail-level 的取值
+func validateGateLevel(level string) error {
+	if _, ok := gateLevels[level]; !ok {
+		return fmt.Errorf("--fail-level 取值无效: %s（可选 any、major、minor、patch）", level)
+	}
+	return nil
+}
+
+// gateReleases 返回达到 --fail-level 级别的新版本
+// any 匹配所有新版本、预发布转正及新推送的标签；其他级别按相对于锁定版本（或之前通知过的版本）的升级类型判断
+func gateReleases(releases []*github.ReleaseInfo, level string) []*github.ReleaseInfo {
+	var matched []*github.ReleaseInfo
+	for _, r := range releases {
+		if r.Event != github.EventRelease && r.Event != github.EventPromoted && r.Event != github.EventTagPushed {
+			continue
+		}
+		if level == "any" {
+			matched = append(matched, r)
+			continue
+		}
+		if bump, ok := bumpLevels[r.UpgradeKind()]; ok && bump <= gateLevels[level] {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+// checkGate 打印达到级别的新版本，并返回带退出码的错误
+func checkGate(releases []*github.ReleaseInfo, level string) error {
+	matched := gateReleases(releases, level)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n发现 %d 个达到 --fail-level=%s 级别的新版本:\n", len(matched), level)
+	for _, r := range matched {
+		if kind := r.UpgradeKind(); kind != version.BumpNone {
+			fmt.Printf("- %s/%s %s（%s 升级）\n", r.Owner, r.Repository, r.TagName, kind)
+		} else {
+			fmt.Printf("- %s/%s %s\n", r.Owner, r.Repository, r.TagName)
+		}
+	}
+
+	return &exitError{
+		code: exitCodeNewReleases,
+		msg:  fmt.Sprintf("发现 %d 个新版本", len(matched)),
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// TestGateReleases 按级别筛选新版本，非版本事件不参与判断
+func TestGateReleases(t *testing.T) {
+	releases := []*github.ReleaseInfo{
+		{Event: github.EventRelease, Repository: "major", TagName: "v2.0.0", PreviousTag: "v1.4.0"},
+		{Event: github.EventRelease, Repository: "minor", TagName: "v1.5.0", PinnedVersion: "v1.4.2"},
+		{Event: github.EventPromoted, Repository: "patch", TagName: "v1.4.3", PreviousTag: "v1.4.2"},
+		{Event: github.EventTagPushed, Repository: "first", TagName: "v0.1.0"},
+		{Event: github.EventNotesUpdated, Repository: "notes", TagName: "v3.0.0", PreviousTag: "v1.0.0"},
+		{Event: github.EventWatchAdded, Repository: "watch"},
+	}
+
+	for _, tc := range []struct {
+		level string
+		want  []string
+	}{
+		{"any", []string{"major", "minor", "patch", "first"}},
+		{"major", []string{"major"}},
+		{"minor", []string{"major", "minor"}},
+		{"patch", []string{"major", "minor", "patch"}},
+	} {
+		var got []string
+		for _, r := range gateReleases(releases, tc.level) {
+			got = append(got, r.Repository)
+		}
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: 匹配 %v，期望 %v", tc.level, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: 匹配 %v，期望 %v", tc.level, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+// TestCheckGate 有达到级别的新版本时返回退出码为2的错误
+func TestCheckGate(t *testing.T) {
+	releases := []*github.ReleaseInfo{
+		{Event: github.EventRelease, Owner: "o", Repository: "r", TagName: "v1.0.1", PreviousTag: "v1.0.0"},
+	}
+
+	if err := checkGate(releases, "minor"); err != nil {
+		t.Errorf("补丁版本不应达到 minor 级别: %v", err)
+	}
+	if err := checkGate(nil, "any"); err != nil {
+		t.Errorf("没有新版本时不应返回错误: %v", err)
+	}
+
+	var exitErr *exitError
+	if err := checkGate(releases, "patch"); !errors.As(err, &exitErr) || exitErr.code != exitCodeNewReleases {
+		t.Errorf("应返回退出码为 %d 的错误: %v", exitCodeNewReleases, err)
+	}
+}
+
+// TestGateFlags --fail-on-new 不带取值，级别通过 --fail-level 指定
+func TestGateFlags(t *testing.T) {
+	flags := RootCmd.PersistentFlags()
+	t.Cleanup(func() {
+		failOnNew, failLevel = false, "any"
+		flags.Lookup("fail-level").Changed = false
+		flags.Lookup("fail-on-new").Changed = false
+	})
+
+	if err := flags.Parse([]string{"--fail-on-new", "--fail-level", "major"}); err != nil {
+		t.Fatalf("解析参数失败: %v", err)
+	}
+	if !failOnNew || failLevel != "major" {
+		t.Errorf("fail-on-new=%v，fail-level=%q", failOnNew, failLevel)
+	}
+	if args := flags.Args(); len(args) != 0 {
+		t.Errorf("级别不应作为位置参数: %v", args)
+	}
+
+	for _, level := range []string{"any", "major", "minor", "patch"} {
+		if err := validateGateLevel(level); err != nil {
+			t.Errorf("%s 应为有效级别: %v", level, err)
+		}
+	}
+	if err := validateGateLevel("huge"); err == nil {
+		t.Error("无效的级别应返回错误")
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// gcInterval 定时运行时自动清理的间隔
+const gcInterval = 24 * time.Hour
+
+// gcCmd 按保留策略清理数据文件
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "按 retention 的保留策略清理确认记录、运行记录、失败队列和日志文件",
+	Long: `按配置文件中 retention 的保留策略清理 ~/.notify 中的数据文件:
+  - 确认记录（acks.json）: 删除 history_days 天前发送的记录
+  - 运行记录（runs.json）: 删除 runs_days 天前结束的运行
+  - 运行指标（metrics.json）: 删除 run.metrics_days（默认90）天前的指标
+  - 失败队列（outbox.json）: 丢弃 outbox_days 天前失败的通知
+  - 日志文件（*.log）: 超过 log_max_mb 时轮转为 .log.1，保留 log_backups 个旧文件
+配置了租户时清理所有租户的数据，--tenant 只清理指定的租户。
+定时运行（schedule.enabled）时每天自动清理一次；定时运行的实例正在运行时本命令会因无法获取进程锁而退出。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig(configFile)
+		if err != nil {
+			return fmt.Errorf("加载配置失败: %v", err)
+		}
+		targets, err := runTargets(cfg)
+		if err != nil {
+			return err
+		}
+
+		// 与检查使用同一个文件锁，避免运行中的实例用内存中的队列覆盖清理结果
+		lockCfg := cfg
+		if tenantFlag != "" {
+			lockCfg = targets[0]
+		}
+		lockPath, err := util.ResolvePath("", "notify.lock", lockCfg.DataSuffix())
+		if err != nil {
+			return err
+		}
+		lock, err := util.NewFileLock(lockPath)
+		if err != nil {
+			return fmt.Errorf("创建文件锁失败: %v", err)
+		}
+		if err := lock.Lock(); err != nil {
+			return fmt.Errorf("⚠️  %v\n提示：定时运行的实例会每天自动清理，无需手动执行", err)
+		}
+		defer lock.Unlock()
+
+		failed := false
+		for _, target := range targets {
+			manager, err := notifier.NewManager(target)
+			if err != nil {
+				return fmt.Errorf("%s创建通知管理器失败: %v", tenantPrefix(target), err)
+			}
+			report, errs := collectGarbage(target, manager, time.Now())
+			for _, err := range errs {
+				fmt.Printf("⚠️ %s%v\n", tenantPrefix(target), err)
+				failed = true
+			}
+			fmt.Printf("✓ %s%s\n", tenantPrefix(target), report)
+		}
+		if failed {
+			return fmt.Errorf("部分数据文件清理失败")
+		}
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(gcCmd)
+}
+
+// gcReport 一次清理删除的记录数
+type gcReport struct {
+	Acks    int
+	Runs    int
+	Metrics int
+	Outbox  int
+	Logs    int
+}
+
+// Empty 是否没有清理任何内容
+func (r gcReport) Empty() bool {
+	return r == gcReport{}
+}
+
+// String 返回清理结果的描述
+func (r gcReport) String() string {
+	if r.Empty() {
+		return "没有需要清理的数据"
+	}
+	return fmt.Sprintf("已清理 %d 条确认记录、%d 条运行记录、%d 条运行指标、%d 条失败队列中的通知，轮转 %d 个日志文件",
+		r.Acks, r.Runs, r.Metrics, r.Outbox, r.Logs)
+}
+
+// collectGarbage 按保留策略清理当前租户和分片的数据文件，并轮转 ~/.notify 下的日志文件
+// 失败队列通过 manager 清理，定时运行复用的通知管理器不会在之后用内存中的队列覆盖清理结果
+func collectGarbage(cfg *config.Config, manager *notifier.Manager, now time.Time) (gcReport, []error) {
+	var report gcReport
+	var errs []error
+	retention := cfg.Retention
+
+	if days := retention.HistoryDays; days > 0 {
+		n, err := manager.PruneAcks(now.AddDate(0, 0, -days))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		report.Acks = n
+	}
+
+	if days := retention.RunsDays; days > 0 {
+		history, err := runHistory(cfg)
+		if err == nil {
+			report.Runs, err = history.Prune(now.AddDate(0, 0, -days))
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	metricsDays := cfg.Run.MetricsDays
+	if metricsDays <= 0 {
+		metricsDays = util.DefaultMetricsDays
+	}
+	metrics, err := metricsHistory(cfg)
+	if err == nil {
+		report.Metrics, err = metrics.Prune(now.AddDate(0, 0, -metricsDays))
+	}
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	if days := retention.OutboxDays; days > 0 {
+		n, err := manager.PruneOutbox(now.AddDate(0, 0, -days))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		report.Outbox = n
+	}
+
+	if retention.LogMaxMB > 0 {
+		files, err := util.LogFiles()
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, path := range files {
+			rotated, err := util.RotateLog(path, int64(retention.LogMaxMB)<<20, retention.LogBackups)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %v", path, err))
+				continue
+			}
+			if rotated {
+				report.Logs++
+			}
+		}
+	}
+
+	return report, errs
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var historyUnacked bool
+
+// historyCmd 查看发送到启用确认跟踪的渠道的版本及查看情况
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "列出发送到启用确认跟踪的渠道的版本及查看情况，--unacked 只列出无人查看的版本",
+	Long: `Telegram 渠道设置 ack: true 后，版本消息下方附带"已查看"按钮，点击后记录查看的人和时间。
+本命令先读取按钮的回调（定时运行开始时也会读取），再按发送顺序列出版本；--unacked 只列出还没有人查看的版本。
+配置了租户时使用 --tenant 指定租户。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manager, err := approvalManager()
+		if err != nil {
+			return err
+		}
+		if !manager.AckEnabled() {
+			fmt.Println("提示: 没有渠道启用确认跟踪（notifications.telegram 中的 ack），不会记录新的版本")
+		}
+		for _, err := range manager.ProcessAcks() {
+			fmt.Printf("⚠️ 读取确认回调失败: %v\n", err)
+		}
+
+		records, err := manager.AckRecords()
+		if err != nil {
+			return err
+		}
+		shown, unacked := 0, 0
+		for _, r := range records {
+			if r.AckedAt == nil {
+				unacked++
+			} else if historyUnacked {
+				continue
+			}
+			shown++
+			status := "未查看"
+			if r.AckedAt != nil {
+				status = fmt.Sprintf("✓ %s 于 %s 查看", r.AckedBy, r.AckedAt.Local().Format(time.DateTime))
+			}
+			fmt.Printf("%s  %-12s %s/%s@%s  %s\n", r.SentAt.Local().Format(time.DateTime), r.Channel, r.Owner, r.Repository, r.TagName, status)
+		}
+
+		if shown == 0 {
+			if historyUnacked {
+				fmt.Println("没有无人查看的版本")
+			} else {
+				fmt.Println("没有确认记录")
+			}
+			return nil
+		}
+		fmt.Printf("共 %d 个版本，%d 个无人查看\n", len(records), unacked)
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().BoolVar(&historyUnacked, "unacked", false, "只列出无人查看的版本")
+	RootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ignoreFor    string
+	ignoreReason string
+	ignoreList   bool
+	ignoreRemove bool
+)
+
+// ignoreCmd 标记版本为已处理或忽略
+var ignoreCmd = &cobra.Command{
+	Use:   "ignore [owner/repo@tag]",
+	Short: "将指定版本标记为已处理/忽略（如 notify ignore owner/repo@v2.0.0 --for 72h），不再通知",
+	Long: `将指定版本标记为已处理或忽略，适用于已经手动通知过或已知有问题的版本。
+忽略的版本不会通知，也不会记录为已通知；设置 --for 时到期后如果该版本仍是检查范围内的最新版本，会照常通知。
+忽略列表保存在 ~/.notify/ignored.json，所有分片和 notify serve 共用；
+配置了租户时每个租户分开保存（如 ignored.tenant-team-a.json），使用 --tenant 指定租户。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		suffix := ""
+		if tenantFlag != "" {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			suffix = cfg.TenantSuffix()
+		}
+		list, err := github.LoadIgnoreList(suffix)
+		if err != nil {
+			return err
+		}
+
+		if ignoreList {
+			printIgnoreList(list)
+			return nil
+		}
+
+		if len(args) == 0 {
+			return fmt.Errorf("请指定要忽略的版本，格式为 owner/repo@tag，或使用 --list 查看忽略列表")
+		}
+		owner, repo, tag, err := github.ParseReleaseRef(args[0])
+		if err != nil {
+			return err
+		}
+
+		if ignoreRemove {
+			if !list.Remove(owner, repo, tag) {
+				fmt.Printf("%s 不在忽略列表中\n", args[0])
+				return nil
+			}
+			if err := list.Save(); err != nil {
+				return err
+			}
+			fmt.Printf("✓ 已取消忽略 %s\n", args[0])
+			return nil
+		}
+
+		entry := github.IgnoredRelease{
+			Owner:      owner,
+			Repository: repo,
+			TagName:    tag,
+			Reason:     ignoreReason,
+			AddedAt:    time.Now(),
+		}
+		if ignoreFor != "" {
+			duration, err := time.ParseDuration(ignoreFor)
+			if err != nil || duration <= 0 {
+				return fmt.Errorf("忽略时长无效: %s（如 24h、72h）", ignoreFor)
+			}
+			entry.ExpiresAt = entry.AddedAt.Add(duration)
+		}
+
+		list.Add(entry)
+		if err := list.Save(); err != nil {
+			return err
+		}
+
+		if entry.ExpiresAt.IsZero() {
+			fmt.Printf("✓ 已忽略 %s\n", entry)
+		} else {
+			fmt.Printf("✓ 已忽略 %s，到 %s\n", entry, entry.ExpiresAt.Format(time.DateTime))
+		}
+		return nil
+	},
+}
+
+// printIgnoreList 打印未到期的忽略版本
+func printIgnoreList(list *github.IgnoreList) {
+	entries := list.Entries()
+	if len(entries) == 0 {
+		fmt.Println("忽略列表为空")
+		return
+	}
+
+	for _, entry := range entries {
+		var details []string
+		if entry.ExpiresAt.IsZero() {
+			details = append(details, "一直忽略")
+		} else {
+			details = append(details, "到 "+entry.ExpiresAt.Format(time.DateTime))
+		}
+		if entry.Reason != "" {
+			details = append(details, entry.Reason)
+		}
+		fmt.Printf("%s（%s）\n", entry, strings.Join(details, "，"))
+	}
+}
+
+func init() {
+	ignoreCmd.Flags().StringVar(&ignoreFor, "for", "", "忽略时长（如 72h），不指定时一直忽略")
+	ignoreCmd.Flags().StringVar(&ignoreReason, "reason", "", "忽略原因，仅用于 --list 展示")
+	ignoreCmd.Flags().BoolVar(&ignoreList, "list", false, "列出忽略的版本")
+	ignoreCmd.Flags().BoolVar(&ignoreRemove, "remove", false, "取消忽略指定版本")
+	RootCmd.AddCommand(ignoreCmd)
+}
@@ -0,0 +1,156 @@
+// Package autostart 将定时运行注册到当前平台的用户级自启动机制：
+// Linux 使用 systemd --user 服务，macOS 使用 launchd LaunchAgent，Windows 使用任务计划程序（登录时运行）
+package autostart
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// labelPrefix launchd 任务标签的前缀
+const labelPrefix = "io.github.orange-juzipi."
+
+// Entry 自启动项
+type Entry struct {
+	// Name 自启动项名称，如 notify、notify-team-a，用作服务名、任务名
+	Name string
+	// Description 服务说明
+	Description string
+	// Executable notify 可执行文件的绝对路径
+	Executable string
+	// Args 启动参数，如 --config /path/to/config.yaml
+	Args []string
+	// WorkDir 工作目录
+	WorkDir string
+	// LogPath 标准输出和标准错误写入的文件（launchd 使用，systemd 写入日志，Windows 不记录）
+	LogPath string
+}
+
+// Status 自启动项的安装状态
+type Status struct {
+	// Installed 是否已注册
+	Installed bool
+	// Location 服务文件路径或任务名
+	Location string
+}
+
+// Enable 注册并立即启动自启动项，已存在时覆盖，返回服务文件路径或任务名
+func Enable(e Entry) (string, error) {
+	if e.Name == "" || e.Executable == "" {
+		return "", fmt.Errorf("自启动项缺少名称或可执行文件路径")
+	}
+	return enable(e)
+}
+
+// Disable 停止并删除自启动项，返回被删除的服务文件路径或任务名，不存在时返回错误
+func Disable(name string) (string, error) {
+	return disable(name)
+}
+
+// Query 返回自启动项的安装状态
+func Query(name string) (Status, error) {
+	return query(name)
+}
+
+// systemdUnit 生成 systemd --user 服务文件，进程异常退出后30秒重启
+func systemdUnit(e Entry) string {
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", e.Description)
+	b.WriteString("Wants=network-online.target\nAfter=network-online.target\n\n")
+	b.WriteString("[Service]\nType=simple\n")
+	fmt.Fprintf(&b, "ExecStart=%s\n", systemdCommand(append([]string{e.Executable}, e.Args...)))
+	if e.WorkDir != "" {
+		fmt.Fprintf(&b, "WorkingDirectory=%s\n", systemdQuote(e.WorkDir))
+	}
+	b.WriteString("Restart=on-failure\nRestartSec=30\n\n")
+	b.WriteString("[Install]\nWantedBy=default.target\n")
+	return b.String()
+}
+
+// systemdCommand 按 systemd 的规则拼接命令行
+func systemdCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = systemdQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// systemdQuote 转义 systemd 的说明符（%）和环境变量（$），为包含空白、引号或反斜杠的参数加双引号
+func systemdQuote(s string) string {
+	s = strings.NewReplacer("%", "%%", "$", "$$").Replace(s)
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// launchdLabel 自启动项对应的 launchd 任务标签
+func launchdLabel(name string) string {
+	return labelPrefix + name
+}
+
+// launchdPlist 生成 LaunchAgent 配置，登录时启动，异常退出后重启
+func launchdPlist(e Entry) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(launchdLabel(e.Name)))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{e.Executable}, e.Args...) {
+		fmt.Fprintf(&b, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	b.WriteString("\t</array>\n")
+	if e.WorkDir != "" {
+		fmt.Fprintf(&b, "\t<key>WorkingDirectory</key>\n\t<string>%s</string>\n", xmlEscape(e.WorkDir))
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	if e.LogPath != "" {
+		fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(e.LogPath))
+		fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(e.LogPath))
+	}
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// xmlEscape 转义XML中的特殊字符
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace(s)
+}
+
+// schtasksCreateArgs 生成创建登录时运行的计划任务的 schtasks 参数，已存在时覆盖
+func schtasksCreateArgs(e Entry) []string {
+	return []string{"/Create", "/F", "/TN", e.Name, "/SC", "ONLOGON", "/RL", "LIMITED",
+		"/TR", windowsCommand(append([]string{e.Executable}, e.Args...))}
+}
+
+// windowsCommand 按 Windows 命令行的规则拼接参数，包含空白或引号的参数加双引号
+func windowsCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
+
+// run 执行命令，失败时返回包含命令输出的错误
+func run(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("执行 %s %s 失败: %v: %s", name, strings.Join(args, " "), err, msg)
+		}
+		return fmt.Errorf("执行 %s %s 失败: %v", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
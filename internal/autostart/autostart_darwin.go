@@ -0,0 +1,75 @@
+//go:build darwin
+
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// plistPath LaunchAgent 配置文件的路径
+func plistPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %v", err)
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel(name)+".plist"), nil
+}
+
+// domain 当前用户的 launchd 域
+func domain() string {
+	return "gui/" + strconv.Itoa(os.Getuid())
+}
+
+// enable 写入 LaunchAgent 配置并加载，已加载时先卸载，使新的启动参数生效
+func enable(e Entry) (string, error) {
+	path, err := plistPath(e.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("创建 LaunchAgents 目录失败: %v", err)
+	}
+	if e.LogPath != "" {
+		if err := os.MkdirAll(filepath.Dir(e.LogPath), 0755); err != nil {
+			return "", fmt.Errorf("创建日志目录失败: %v", err)
+		}
+	}
+	if err := os.WriteFile(path, []byte(launchdPlist(e)), 0644); err != nil {
+		return "", fmt.Errorf("写入 LaunchAgent 配置失败: %v", err)
+	}
+
+	// 尚未加载时卸载会失败，忽略
+	run("launchctl", "bootout", domain()+"/"+launchdLabel(e.Name))
+	return path, run("launchctl", "bootstrap", domain(), path)
+}
+
+// disable 卸载 LaunchAgent 并删除配置文件
+func disable(name string) (string, error) {
+	path, err := plistPath(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("未找到自启动配置 %s", path)
+	}
+
+	// 已经停止运行时卸载会失败，仍然删除配置文件
+	run("launchctl", "bootout", domain()+"/"+launchdLabel(name))
+	if err := os.Remove(path); err != nil {
+		return path, fmt.Errorf("删除 LaunchAgent 配置失败: %v", err)
+	}
+	return path, nil
+}
+
+// query 检查配置文件是否存在
+func query(name string) (Status, error) {
+	path, err := plistPath(name)
+	if err != nil {
+		return Status{}, err
+	}
+	_, err = os.Stat(path)
+	return Status{Installed: err == nil, Location: path}, nil
+}
@@ -0,0 +1,70 @@
+//go:build linux
+
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// unitPath systemd --user 服务文件的路径
+func unitPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户配置目录失败: %v", err)
+	}
+	return filepath.Join(dir, "systemd", "user", name+".service"), nil
+}
+
+// enable 写入 systemd --user 服务文件，启用并立即启动服务
+func enable(e Entry) (string, error) {
+	path, err := unitPath(e.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("创建服务目录失败: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(systemdUnit(e)), 0644); err != nil {
+		return "", fmt.Errorf("写入服务文件失败: %v", err)
+	}
+
+	if err := run("systemctl", "--user", "daemon-reload"); err != nil {
+		return path, err
+	}
+	// 服务已在运行时重启，使新的启动参数生效
+	if err := run("systemctl", "--user", "enable", e.Name+".service"); err != nil {
+		return path, err
+	}
+	return path, run("systemctl", "--user", "restart", e.Name+".service")
+}
+
+// disable 停止并禁用服务，删除服务文件
+func disable(name string) (string, error) {
+	path, err := unitPath(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("未找到自启动服务 %s", path)
+	}
+
+	if err := run("systemctl", "--user", "disable", "--now", name+".service"); err != nil {
+		return path, err
+	}
+	if err := os.Remove(path); err != nil {
+		return path, fmt.Errorf("删除服务文件失败: %v", err)
+	}
+	return path, run("systemctl", "--user", "daemon-reload")
+}
+
+// query 检查服务文件是否存在
+func query(name string) (Status, error) {
+	path, err := unitPath(name)
+	if err != nil {
+		return Status{}, err
+	}
+	_, err = os.Stat(path)
+	return Status{Installed: err == nil, Location: path}, nil
+}
@@ -0,0 +1,23 @@
+//go:build !linux && !darwin && !windows
+
+package autostart
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// enable 其他平台不支持自启动
+func enable(e Entry) (string, error) {
+	return "", fmt.Errorf("不支持当前平台 %s", runtime.GOOS)
+}
+
+// disable 其他平台不支持自启动
+func disable(name string) (string, error) {
+	return "", fmt.Errorf("不支持当前平台 %s", runtime.GOOS)
+}
+
+// query 其他平台不支持自启动
+func query(name string) (Status, error) {
+	return Status{}, fmt.Errorf("不支持当前平台 %s", runtime.GOOS)
+}
@@ -0,0 +1,66 @@
+package autostart
+
+import (
+	"strings"
+	"testing"
+)
+
+var testEntry = Entry{
+	Name:        "notify",
+	Description: "notify 定时检查",
+	Executable:  "/opt/my apps/notify",
+	Args:        []string{"--config", "/home/u/.notify/config.yaml", "--tenant", "100%"},
+	WorkDir:     "/home/u/.notify",
+	LogPath:     "/home/u/.notify/autostart.log",
+}
+
+// TestSystemdUnit 测试服务文件中的命令行转义
+func TestSystemdUnit(t *testing.T) {
+	unit := systemdUnit(testEntry)
+	want := `ExecStart="/opt/my apps/notify" --config /home/u/.notify/config.yaml --tenant 100%%` + "\n"
+	if !strings.Contains(unit, want) {
+		t.Errorf("服务文件中没有 %q:\n%s", want, unit)
+	}
+	for _, line := range []string{"WorkingDirectory=/home/u/.notify\n", "Restart=on-failure\n", "WantedBy=default.target\n"} {
+		if !strings.Contains(unit, line) {
+			t.Errorf("服务文件中没有 %q", line)
+		}
+	}
+
+	if got := systemdQuote(`a"b\c$HOME`); got != `"a\"b\\c$$HOME"` {
+		t.Errorf("systemdQuote = %s", got)
+	}
+}
+
+// TestLaunchdPlist 测试 LaunchAgent 配置的标签、参数和XML转义
+func TestLaunchdPlist(t *testing.T) {
+	e := testEntry
+	e.Args = []string{"--config", "/Users/u/a&b.yaml"}
+	plist := launchdPlist(e)
+	for _, want := range []string{
+		"<string>io.github.orange-juzipi.notify</string>",
+		"<string>/opt/my apps/notify</string>",
+		"<string>/Users/u/a&amp;b.yaml</string>",
+		"<key>RunAtLoad</key>\n\t<true/>",
+		"<key>StandardErrorPath</key>\n\t<string>/home/u/.notify/autostart.log</string>",
+	} {
+		if !strings.Contains(plist, want) {
+			t.Errorf("配置中没有 %q:\n%s", want, plist)
+		}
+	}
+}
+
+// TestSchtasksCreateArgs 测试计划任务的命令行
+func TestSchtasksCreateArgs(t *testing.T) {
+	e := testEntry
+	e.Executable = `C:\Program Files\notify\notify.exe`
+	e.Args = []string{"--config", `C:\Users\u\.notify\config.yaml`}
+	args := schtasksCreateArgs(e)
+	if args[len(args)-2] != "/TR" {
+		t.Fatalf("参数 %v 的最后应为 /TR 命令行", args)
+	}
+	want := `"C:\Program Files\notify\notify.exe" --config C:\Users\u\.notify\config.yaml`
+	if got := args[len(args)-1]; got != want {
+		t.Errorf("/TR 为 %s，期望 %s", got, want)
+	}
+}
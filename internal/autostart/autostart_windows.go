@@ -0,0 +1,32 @@
+//go:build windows
+
+package autostart
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// enable 创建登录时运行的计划任务并立即运行
+func enable(e Entry) (string, error) {
+	if err := run("schtasks", schtasksCreateArgs(e)...); err != nil {
+		return e.Name, err
+	}
+	return e.Name, run("schtasks", "/Run", "/TN", e.Name)
+}
+
+// disable 结束正在运行的任务并删除计划任务
+func disable(name string) (string, error) {
+	if status, _ := query(name); !status.Installed {
+		return "", fmt.Errorf("未找到计划任务 %s", name)
+	}
+	// 任务没有在运行时结束会失败，忽略
+	run("schtasks", "/End", "/TN", name)
+	return name, run("schtasks", "/Delete", "/F", "/TN", name)
+}
+
+// query 查询计划任务是否存在
+func query(name string) (Status, error) {
+	err := exec.Command("schtasks", "/Query", "/TN", name).Run()
+	return Status{Installed: err == nil, Location: name}, nil
+}
@@ -22,11 +22,11 @@ type FileLock struct {
 func NewFileLock(lockPath string) (*FileLock, error) {
 	if lockPath == "" {
 		// 使用默认路径
-		home, err := os.UserHomeDir()
+		var err error
+		lockPath, err = DefaultPath("notify.lock")
 		if err != nil {
-			return nil, fmt.Errorf("获取用户主目录失败: %v", err)
+			return nil, err
 		}
-		lockPath = filepath.Join(home, ".notify", "notify.lock")
 	}
 
 	// 确保目录存在
@@ -98,6 +98,20 @@ func (fl *FileLock) TryLock() (bool, error) {
 	return true, nil
 }
 
+// Wait 获取文件锁，锁被占用时等待释放，不写入进程PID，用于保护短时间内的读写
+func (fl *FileLock) Wait() error {
+	file, err := os.OpenFile(fl.path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("打开锁文件失败: %v", err)
+	}
+	if err := fl.lockFile(file, false); err != nil {
+		file.Close()
+		return err
+	}
+	fl.file = file
+	return nil
+}
+
 // Unlock 释放文件锁
 func (fl *FileLock) Unlock() error {
 	if fl.file == nil {
@@ -0,0 +1,133 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultEgressCheckURL 查询出口IP的默认地址（返回纯文本IP）
+const DefaultEgressCheckURL = "https://api.ipify.org"
+
+// HTTPOptions 出站HTTP请求配置
+type HTTPOptions struct {
+	// Timeout 请求超时时间，为0时不设置超时
+	Timeout time.Duration
+	// LocalAddr 绑定的本地IP或网卡名（如 192.168.1.10、eth1），为空时由系统选择
+	LocalAddr string
+	// Proxy 代理地址，支持 socks5://、socks5h://、http://、https://，为空时按环境变量使用代理
+	Proxy string
+	// SSH 通过SSH跳板机转发连接，与 Proxy 不能同时使用
+	SSH *SSHOptions
+}
+
+// NewHTTPClient 根据配置创建HTTP客户端
+func NewHTTPClient(opts HTTPOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.LocalAddr != "" {
+		ip, err := ResolveLocalAddr(opts.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			LocalAddr: &net.TCPAddr{IP: ip},
+		}
+		transport.DialContext = dialer.DialContext
+	}
+
+	if opts.Proxy != "" && opts.SSH != nil {
+		return nil, fmt.Errorf("代理和SSH跳板机不能同时配置")
+	}
+
+	if opts.Proxy != "" {
+		proxyURL, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("无效的代理地址 %s: %v", opts.Proxy, err)
+		}
+		switch proxyURL.Scheme {
+		case "socks5", "socks5h", "http", "https":
+		default:
+			return nil, fmt.Errorf("不支持的代理协议 %s（可选 socks5、socks5h、http、https）", proxyURL.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.SSH != nil {
+		// 经由跳板机连接时不再使用本地代理
+		dialer, err := newSSHDialer(*opts.SSH)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = nil
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: transport,
+	}, nil
+}
+
+// ResolveLocalAddr 将IP或网卡名解析为本地IP，网卡优先使用IPv4地址
+func ResolveLocalAddr(addr string) (net.IP, error) {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip, nil
+	}
+
+	iface, err := net.InterfaceByName(addr)
+	if err != nil {
+		return nil, fmt.Errorf("无效的本地地址 %s（既不是IP也不是网卡名）: %v", addr, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("获取网卡 %s 的地址失败: %v", addr, err)
+	}
+
+	var fallback net.IP
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			return ip4, nil
+		}
+		if fallback == nil {
+			fallback = ipNet.IP
+		}
+	}
+	if fallback != nil {
+		return fallback, nil
+	}
+	return nil, fmt.Errorf("网卡 %s 没有可用的IP地址", addr)
+}
+
+// DetectEgressIP 通过指定地址查询当前客户端的出口IP
+func DetectEgressIP(client *http.Client, checkURL string) (string, error) {
+	if checkURL == "" {
+		checkURL = DefaultEgressCheckURL
+	}
+
+	resp, err := client.Get(checkURL)
+	if err != nil {
+		return "", fmt.Errorf("查询出口IP失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("查询出口IP失败，状态码: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", fmt.Errorf("读取出口IP失败: %v", err)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
@@ -0,0 +1,82 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// DefaultLogBackups 轮转后默认保留的旧日志文件数
+const DefaultLogBackups = 3
+
+// RotateLog 日志文件超过 maxSize 字节时轮转，返回是否已轮转
+// 依次把 path.1…path.<backups-1> 改名为 path.2…path.<backups>，再把当前内容复制到 path.1 并清空 path，
+// 写日志的进程（如 launchd 启动的定时运行）一直打开着日志文件，复制后清空而不是改名，进程不需要重新打开文件
+// backups<=0 时使用默认值，文件不存在时不轮转
+func RotateLog(path string, maxSize int64, backups int) (bool, error) {
+	if backups <= 0 {
+		backups = DefaultLogBackups
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("读取日志文件失败: %v", err)
+	}
+	if info.Size() <= maxSize {
+		return false, nil
+	}
+
+	backup := func(i int) string { return path + "." + strconv.Itoa(i) }
+	if err := os.Remove(backup(backups)); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("删除旧日志文件失败: %v", err)
+	}
+	for i := backups - 1; i >= 1; i-- {
+		if err := os.Rename(backup(i), backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return false, fmt.Errorf("轮转日志文件失败: %v", err)
+		}
+	}
+
+	if err := copyFile(path, backup(1)); err != nil {
+		return false, fmt.Errorf("轮转日志文件失败: %v", err)
+	}
+	if err := os.Truncate(path, 0); err != nil {
+		return false, fmt.Errorf("清空日志文件失败: %v", err)
+	}
+	return true, nil
+}
+
+// LogFiles 返回 ~/.notify 目录下的日志文件（*.log，不含轮转后的旧文件）
+func LogFiles() ([]string, error) {
+	dir, err := DefaultPath("")
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		return nil, fmt.Errorf("查找日志文件失败: %v", err)
+	}
+	return files, nil
+}
+
+// copyFile 复制文件内容
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
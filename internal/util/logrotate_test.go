@@ -0,0 +1,54 @@
+package util
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRotateLog 测试超过大小时轮转、保留指定数量的旧文件，并保持原文件可以继续写入
+func TestRotateLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notify.log")
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("创建日志文件失败: %v", err)
+	}
+	defer f.Close()
+
+	for i, line := range []string{"first\n", "second\n", "third\n"} {
+		f.WriteString(strings.Repeat(line, 10))
+		rotated, err := RotateLog(path, 20, 2)
+		if err != nil {
+			t.Fatalf("第 %d 次轮转失败: %v", i+1, err)
+		}
+		if !rotated {
+			t.Fatalf("第 %d 次写入后应轮转", i+1)
+		}
+	}
+
+	if rotated, err := RotateLog(path, 20, 2); err != nil || rotated {
+		t.Errorf("日志文件已清空，不应再轮转（%v, %v）", rotated, err)
+	}
+
+	for name, want := range map[string]string{".1": "third", ".2": "second"} {
+		data, err := os.ReadFile(path + name)
+		if err != nil {
+			t.Fatalf("读取 %s 失败: %v", name, err)
+		}
+		if !strings.HasPrefix(string(data), want) {
+			t.Errorf("%s 的内容为 %q，期望以 %q 开头", name, data[:10], want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Error("超过保留数量的旧文件应被删除")
+	}
+
+	// 写日志的进程继续写入原文件
+	f.WriteString("after\n")
+	data, _ := os.ReadFile(path)
+	if string(data) != "after\n" {
+		t.Errorf("轮转后日志文件的内容为 %q", data)
+	}
+}
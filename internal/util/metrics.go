@@ -0,0 +1,159 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultMetricsDays 默认保留的运行指标天数
+const DefaultMetricsDays = 90
+
+// MetricsRecord 一次检查运行的指标
+type MetricsRecord struct {
+	Time time.Time `json:"time"`
+	// DurationMs 运行耗时（毫秒）
+	DurationMs int64 `json:"duration_ms"`
+	// Success 检查和通知是否全部成功
+	Success bool `json:"success"`
+	// Repos 检查的仓库数
+	Repos int `json:"repos"`
+	// CheckErrors 检查失败的仓库数
+	CheckErrors int `json:"check_errors"`
+	// RateLimited 是否因达到速率限制而有仓库未检查
+	RateLimited bool `json:"rate_limited,omitempty"`
+	// Releases 发现的新版本数
+	Releases int `json:"releases"`
+	// Messages 发送成功的消息数
+	Messages int `json:"messages"`
+	// SendFailures 发送失败的消息数
+	SendFailures int `json:"send_failures"`
+	// APIRequests 消耗的GitHub API请求数
+	APIRequests int64 `json:"api_requests"`
+	// QuotaRemaining 运行结束时的剩余配额，未知时为-1
+	QuotaRemaining int `json:"quota_remaining"`
+}
+
+// MetricsHistory 运行指标（metrics.json），按时间顺序保存，超过保留天数的记录在追加时删除
+type MetricsHistory struct {
+	path    string
+	records []MetricsRecord
+}
+
+// LoadMetricsHistory 加载运行指标，文件不存在时返回空历史
+func LoadMetricsHistory(path string) (*MetricsHistory, error) {
+	h := &MetricsHistory{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, fmt.Errorf("读取运行指标失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &h.records); err != nil {
+		return nil, fmt.Errorf("解析运行指标失败: %v", err)
+	}
+	return h, nil
+}
+
+// Since 返回 t 之后的运行指标（从旧到新）
+func (h *MetricsHistory) Since(t time.Time) []MetricsRecord {
+	for i, r := range h.records {
+		if !r.Time.Before(t) {
+			return h.records[i:]
+		}
+	}
+	return nil
+}
+
+// Append 追加一条运行指标并保存，删除早于该记录 days 天的记录（days<=0 时使用默认值）
+func (h *MetricsHistory) Append(record MetricsRecord, days int) error {
+	if days <= 0 {
+		days = DefaultMetricsDays
+	}
+	h.records = append(h.records, record)
+	h.records = h.Since(record.Time.AddDate(0, 0, -days))
+	return h.save()
+}
+
+// Prune 删除 before 之前的运行指标并保存，返回删除的记录数
+func (h *MetricsHistory) Prune(before time.Time) (int, error) {
+	kept := h.Since(before)
+	removed := len(h.records) - len(kept)
+	h.records = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, h.save()
+}
+
+// save 保存运行指标
+func (h *MetricsHistory) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("保存运行指标失败: %v", err)
+	}
+	data, err := json.MarshalIndent(h.records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("保存运行指标失败: %v", err)
+	}
+	if err := os.WriteFile(h.path, data, 0644); err != nil {
+		return fmt.Errorf("保存运行指标失败: %v", err)
+	}
+	return nil
+}
+
+// MetricsSummary 一段时间内运行指标的汇总
+type MetricsSummary struct {
+	Runs         int
+	Failed       int
+	Releases     int
+	Messages     int
+	SendFailures int
+	CheckErrors  int
+	RateLimited  int
+	APIRequests  int64
+	// MinQuota 最低的剩余配额，没有记录配额时为-1
+	MinQuota int
+}
+
+// SummarizeMetrics 汇总运行指标
+func SummarizeMetrics(records []MetricsRecord) MetricsSummary {
+	s := MetricsSummary{MinQuota: -1}
+	for _, r := range records {
+		s.Runs++
+		if !r.Success {
+			s.Failed++
+		}
+		if r.RateLimited {
+			s.RateLimited++
+		}
+		s.Releases += r.Releases
+		s.Messages += r.Messages
+		s.SendFailures += r.SendFailures
+		s.CheckErrors += r.CheckErrors
+		s.APIRequests += r.APIRequests
+		if r.QuotaRemaining >= 0 && (s.MinQuota < 0 || r.QuotaRemaining < s.MinQuota) {
+			s.MinQuota = r.QuotaRemaining
+		}
+	}
+	return s
+}
+
+// SuccessRate 运行成功率（0~1），没有运行时为0
+func (s MetricsSummary) SuccessRate() float64 {
+	if s.Runs == 0 {
+		return 0
+	}
+	return float64(s.Runs-s.Failed) / float64(s.Runs)
+}
+
+// FailureRate 消息发送失败率（0~1），没有发送时为0
+func (s MetricsSummary) FailureRate() float64 {
+	if total := s.Messages + s.SendFailures; total > 0 {
+		return float64(s.SendFailures) / float64(total)
+	}
+	return 0
+}
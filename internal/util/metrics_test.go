@@ -0,0 +1,65 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestMetricsHistory_Append 测试运行指标的保存和按保留天数裁剪
+func TestMetricsHistory_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	history, err := LoadMetricsHistory(path)
+	if err != nil {
+		t.Fatalf("LoadMetricsHistory 失败: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		record := MetricsRecord{Time: start.AddDate(0, 0, i*5), Releases: i}
+		if err := history.Append(record, 10); err != nil {
+			t.Fatalf("Append 失败: %v", err)
+		}
+	}
+
+	loaded, err := LoadMetricsHistory(path)
+	if err != nil {
+		t.Fatalf("LoadMetricsHistory 失败: %v", err)
+	}
+	// 最后一条记录在第20天，保留第10天及之后的记录
+	records := loaded.Since(time.Time{})
+	if len(records) != 3 || records[0].Releases != 2 {
+		t.Fatalf("保留的记录为 %+v，期望从第10天开始的 3 条", records)
+	}
+	if n := len(loaded.Since(start.AddDate(0, 0, 12))); n != 2 {
+		t.Errorf("第12天之后有 %d 条记录，期望 2 条", n)
+	}
+}
+
+// TestSummarizeMetrics 测试成功率、发送失败率和最低剩余配额
+func TestSummarizeMetrics(t *testing.T) {
+	s := SummarizeMetrics([]MetricsRecord{
+		{Success: true, Releases: 3, Messages: 3, APIRequests: 100, QuotaRemaining: 4000},
+		{Success: false, Messages: 1, SendFailures: 1, APIRequests: 80, QuotaRemaining: -1},
+		{Success: true, Releases: 1, Messages: 2, SendFailures: 1, APIRequests: 120, QuotaRemaining: 3500},
+		{Success: true, APIRequests: 90, QuotaRemaining: 3800},
+	})
+
+	if s.Runs != 4 || s.Failed != 1 || s.Releases != 4 || s.APIRequests != 390 {
+		t.Errorf("汇总结果为 %+v", s)
+	}
+	if rate := s.SuccessRate(); rate != 0.75 {
+		t.Errorf("成功率为 %v，期望 0.75", rate)
+	}
+	if rate := s.FailureRate(); rate != 2.0/8 {
+		t.Errorf("发送失败率为 %v，期望 0.25", rate)
+	}
+	if s.MinQuota != 3500 {
+		t.Errorf("最低剩余配额为 %d，期望 3500", s.MinQuota)
+	}
+
+	if empty := SummarizeMetrics(nil); empty.MinQuota != -1 || empty.SuccessRate() != 0 || empty.FailureRate() != 0 {
+		t.Errorf("空汇总为 %+v", empty)
+	}
+}
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultPath 返回 ~/.notify 目录下指定文件的路径
+func DefaultPath(name string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("获取用户主目录失败: %v", err)
+	}
+	return filepath.Join(home, ".notify", name), nil
+}
+
+// ResolvePath 解析数据文件路径：path为空时使用 ~/.notify/defaultName，
+// suffix不为空时（如分片运行）在扩展名前插入后缀，避免多个实例写同一个文件
+func ResolvePath(path, defaultName, suffix string) (string, error) {
+	if path == "" {
+		var err error
+		path, err = DefaultPath(defaultName)
+		if err != nil {
+			return "", err
+		}
+	}
+	if suffix == "" {
+		return path, nil
+	}
+
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + suffix + ext, nil
+}
@@ -0,0 +1,110 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultRunHistorySize 默认保留的运行记录数
+const DefaultRunHistorySize = 50
+
+// RunRecord 一次检查运行的记录
+type RunRecord struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Success 检查和通知是否全部成功
+	Success bool `json:"success"`
+	// Releases 发现的新版本数
+	Releases int `json:"releases"`
+	// Error 失败原因
+	Error string `json:"error,omitempty"`
+}
+
+// Duration 运行耗时
+func (r RunRecord) Duration() time.Duration {
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// RunHistory 运行历史（runs.json），按时间顺序保存最近的运行记录
+type RunHistory struct {
+	path string
+	runs []RunRecord
+}
+
+// LoadRunHistory 加载运行历史，文件不存在时返回空历史
+func LoadRunHistory(path string) (*RunHistory, error) {
+	h := &RunHistory{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return h, nil
+		}
+		return nil, fmt.Errorf("读取运行历史失败: %v", err)
+	}
+	if err := json.Unmarshal(data, &h.runs); err != nil {
+		return nil, fmt.Errorf("解析运行历史失败: %v", err)
+	}
+	return h, nil
+}
+
+// Runs 返回运行记录（从旧到新）
+func (h *RunHistory) Runs() []RunRecord {
+	return h.runs
+}
+
+// LastSuccess 返回最近一次成功的运行
+func (h *RunHistory) LastSuccess() (RunRecord, bool) {
+	for i := len(h.runs) - 1; i >= 0; i-- {
+		if h.runs[i].Success {
+			return h.runs[i], true
+		}
+	}
+	return RunRecord{}, false
+}
+
+// Append 追加一条运行记录并保存，最多保留 max 条（max<=0 时使用默认值）
+func (h *RunHistory) Append(record RunRecord, max int) error {
+	if max <= 0 {
+		max = DefaultRunHistorySize
+	}
+	h.runs = append(h.runs, record)
+	if len(h.runs) > max {
+		h.runs = h.runs[len(h.runs)-max:]
+	}
+	return h.save()
+}
+
+// Prune 删除 before 之前结束的运行记录并保存，返回删除的记录数
+func (h *RunHistory) Prune(before time.Time) (int, error) {
+	kept := h.runs[:0]
+	for _, r := range h.runs {
+		if !r.FinishedAt.Before(before) {
+			kept = append(kept, r)
+		}
+	}
+	removed := len(h.runs) - len(kept)
+	h.runs = kept
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, h.save()
+}
+
+// save 保存运行历史
+func (h *RunHistory) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0755); err != nil {
+		return fmt.Errorf("保存运行历史失败: %v", err)
+	}
+	data, err := json.MarshalIndent(h.runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("保存运行历史失败: %v", err)
+	}
+	if err := os.WriteFile(h.path, data, 0644); err != nil {
+		return fmt.Errorf("保存运行历史失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package util
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRunHistory_Append 测试运行记录的保存、裁剪和最近一次成功的运行
+func TestRunHistory_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.json")
+
+	history, err := LoadRunHistory(path)
+	if err != nil {
+		t.Fatalf("LoadRunHistory 失败: %v", err)
+	}
+	if _, ok := history.LastSuccess(); ok {
+		t.Fatal("空历史不应有成功的运行")
+	}
+
+	start := time.Now().Add(-time.Hour)
+	for i := 0; i < 4; i++ {
+		record := RunRecord{
+			StartedAt:  start.Add(time.Duration(i) * time.Minute),
+			FinishedAt: start.Add(time.Duration(i)*time.Minute + 30*time.Second),
+			Success:    i != 3,
+			Releases:   i,
+		}
+		if err := history.Append(record, 3); err != nil {
+			t.Fatalf("Append 失败: %v", err)
+		}
+	}
+
+	loaded, err := LoadRunHistory(path)
+	if err != nil {
+		t.Fatalf("LoadRunHistory 失败: %v", err)
+	}
+	if n := len(loaded.Runs()); n != 3 {
+		t.Fatalf("保留了 %d 条记录，期望 3 条", n)
+	}
+	if first := loaded.Runs()[0]; first.Releases != 1 {
+		t.Errorf("最早的记录应被裁剪，第一条记录的 Releases 为 %d，期望 1", first.Releases)
+	}
+
+	last, ok := loaded.LastSuccess()
+	if !ok || last.Releases != 2 {
+		t.Errorf("最近一次成功的运行 Releases 为 %d（%v），期望 2", last.Releases, ok)
+	}
+	if last.Duration() != 30*time.Second {
+		t.Errorf("运行耗时为 %v，期望 30s", last.Duration())
+	}
+}
+
+// TestRunHistory_Prune 测试按结束时间删除旧的运行记录
+func TestRunHistory_Prune(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.json")
+	history, err := LoadRunHistory(path)
+	if err != nil {
+		t.Fatalf("LoadRunHistory 失败: %v", err)
+	}
+
+	now := time.Now()
+	for _, age := range []time.Duration{72 * time.Hour, 48 * time.Hour, time.Hour} {
+		if err := history.Append(RunRecord{StartedAt: now.Add(-age), FinishedAt: now.Add(-age), Success: true}, 0); err != nil {
+			t.Fatalf("Append 失败: %v", err)
+		}
+	}
+
+	removed, err := history.Prune(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Prune 失败: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("删除了 %d 条记录，期望 2 条", removed)
+	}
+	loaded, err := LoadRunHistory(path)
+	if err != nil {
+		t.Fatalf("LoadRunHistory 失败: %v", err)
+	}
+	if n := len(loaded.Runs()); n != 1 {
+		t.Errorf("保留了 %d 条记录，期望 1 条", n)
+	}
+}
@@ -0,0 +1,159 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SSHOptions 通过SSH跳板机转发出站连接的配置
+type SSHOptions struct {
+	// Host 跳板机地址，如 jump.example.com:22（未指定端口时使用22）
+	Host string
+	User string
+	// Password 密码认证，与 KeyFile 二选一
+	Password string
+	// KeyFile 私钥文件路径
+	KeyFile string
+	// KeyPassphrase 私钥的口令（可选）
+	KeyPassphrase string
+	// KnownHosts 用于校验跳板机主机密钥的 known_hosts 文件，默认为 ~/.ssh/known_hosts
+	KnownHosts string
+}
+
+// sshDialer 通过SSH跳板机建立TCP连接，SSH连接在首次使用时建立，断开后自动重连
+type sshDialer struct {
+	addr   string
+	config *ssh.ClientConfig
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+// newSSHDialer 根据配置创建SSH拨号器
+func newSSHDialer(opts SSHOptions) (*sshDialer, error) {
+	if opts.Host == "" || opts.User == "" {
+		return nil, fmt.Errorf("SSH跳板机地址和用户名不能为空")
+	}
+
+	addr := opts.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "22")
+	}
+
+	var auth []ssh.AuthMethod
+	if opts.KeyFile != "" {
+		signer, err := loadSSHKey(opts.KeyFile, opts.KeyPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, ssh.PublicKeys(signer))
+	}
+	if opts.Password != "" {
+		auth = append(auth, ssh.Password(opts.Password))
+	}
+	if len(auth) == 0 {
+		return nil, fmt.Errorf("SSH跳板机需要配置密码或私钥")
+	}
+
+	knownHostsPath := opts.KnownHosts
+	if knownHostsPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("获取用户主目录失败: %v", err)
+		}
+		knownHostsPath = filepath.Join(home, ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(expandHome(knownHostsPath))
+	if err != nil {
+		return nil, fmt.Errorf("加载 known_hosts 失败（请先使用 ssh 连接一次跳板机以记录主机密钥）: %v", err)
+	}
+
+	return &sshDialer{
+		addr: addr,
+		config: &ssh.ClientConfig{
+			User:            opts.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         30 * time.Second,
+		},
+	}, nil
+}
+
+// loadSSHKey 读取私钥文件
+func loadSSHKey(path, passphrase string) (ssh.Signer, error) {
+	data, err := os.ReadFile(expandHome(path))
+	if err != nil {
+		return nil, fmt.Errorf("读取SSH私钥失败: %v", err)
+	}
+
+	var signer ssh.Signer
+	if passphrase != "" {
+		signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(passphrase))
+	} else {
+		signer, err = ssh.ParsePrivateKey(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("解析SSH私钥失败: %v", err)
+	}
+	return signer, nil
+}
+
+// expandHome 展开路径开头的 ~
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
+
+// connect 返回可用的SSH连接，必要时重新建立
+func (d *sshDialer) connect(reconnect bool) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.client != nil && !reconnect {
+		return d.client, nil
+	}
+	if d.client != nil {
+		d.client.Close()
+		d.client = nil
+	}
+
+	client, err := ssh.Dial("tcp", d.addr, d.config)
+	if err != nil {
+		return nil, fmt.Errorf("连接SSH跳板机 %s 失败: %v", d.addr, err)
+	}
+	d.client = client
+	return client, nil
+}
+
+// DialContext 通过跳板机连接目标地址，SSH连接断开时重连一次
+func (d *sshDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := d.connect(false)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, network, addr)
+	if err == nil {
+		return conn, nil
+	}
+
+	client, err = d.connect(true)
+	if err != nil {
+		return nil, err
+	}
+	return client.DialContext(ctx, network, addr)
+}
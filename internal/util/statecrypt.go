@@ -0,0 +1,158 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// 加密状态文件的格式参数
+const (
+	stateCipherName = "aes-256-gcm"
+	stateKDFName    = "pbkdf2-sha256"
+	// stateKDFIterations PBKDF2迭代次数，每个进程只在加载时计算一次
+	stateKDFIterations = 600000
+	stateSaltSize      = 16
+)
+
+// 系统密钥环中保存状态文件密钥的条目
+const (
+	keyringService = "notify"
+	keyringAccount = "state"
+)
+
+// StoreOptions 状态存储选项
+type StoreOptions struct {
+	// Encrypt 是否加密保存状态文件
+	Encrypt bool
+	// KeyEnv 读取密钥的环境变量名
+	KeyEnv string
+	// Keyring 环境变量为空时是否从系统密钥环读取密钥
+	Keyring bool
+}
+
+// encryptedState 加密状态文件的内容
+type encryptedState struct {
+	Version    int    `json:"version"`
+	Cipher     string `json:"cipher"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Data       []byte `json:"data"`
+}
+
+// stateCipher 状态文件的加解密器，密钥由口令和盐派生后缓存
+type stateCipher struct {
+	salt []byte
+	aead cipher.AEAD
+}
+
+// ResolveStateKey 按环境变量、系统密钥环的顺序查找状态文件密钥，未找到时返回空字符串
+func ResolveStateKey(keyEnv string, keyring bool) (string, error) {
+	if keyEnv != "" {
+		if key := os.Getenv(keyEnv); key != "" {
+			return key, nil
+		}
+	}
+	if !keyring {
+		return "", nil
+	}
+	return readKeyring()
+}
+
+// readKeyring 从系统密钥环读取密钥（macOS 使用 security，Linux 使用 secret-tool）
+func readKeyring() (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", keyringAccount, "-w")
+	case "linux":
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", keyringAccount)
+	default:
+		return "", fmt.Errorf("当前系统（%s）不支持从密钥环读取密钥，请使用环境变量", runtime.GOOS)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("从系统密钥环读取密钥失败（service=%s, account=%s）: %v", keyringService, keyringAccount, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// newStateCipher 使用口令和盐创建加解密器，salt为空时生成新的随机盐
+func newStateCipher(passphrase string, salt []byte) (*stateCipher, error) {
+	if salt == nil {
+		salt = make([]byte, stateSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("生成随机盐失败: %v", err)
+		}
+	}
+
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, stateKDFIterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("派生加密密钥失败: %v", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stateCipher{salt: salt, aead: aead}, nil
+}
+
+// seal 加密状态数据
+func (c *stateCipher) seal(plain []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("生成随机数失败: %v", err)
+	}
+
+	return json.MarshalIndent(encryptedState{
+		Version:    1,
+		Cipher:     stateCipherName,
+		KDF:        stateKDFName,
+		Iterations: stateKDFIterations,
+		Salt:       c.salt,
+		Nonce:      nonce,
+		Data:       c.aead.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+}
+
+// parseEncryptedState 判断文件内容是否为加密格式，是则返回解析结果
+func parseEncryptedState(data []byte) (*encryptedState, bool) {
+	var env encryptedState
+	if err := json.Unmarshal(data, &env); err != nil || env.Cipher == "" || env.Data == nil {
+		return nil, false
+	}
+	return &env, true
+}
+
+// openEncryptedState 使用口令解密状态文件，返回明文和对应的加解密器（复用文件中的盐）
+func openEncryptedState(env *encryptedState, passphrase string) ([]byte, *stateCipher, error) {
+	if env.Cipher != stateCipherName || env.KDF != stateKDFName || env.Iterations != stateKDFIterations {
+		return nil, nil, fmt.Errorf("不支持的状态文件加密格式: %s/%s", env.Cipher, env.KDF)
+	}
+
+	c, err := newStateCipher(passphrase, env.Salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	plain, err := c.aead.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解密状态文件失败，请检查密钥是否正确")
+	}
+	return plain, c, nil
+}
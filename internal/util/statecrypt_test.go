@@ -0,0 +1,82 @@
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEncryptedStateStore_Migration 测试明文状态文件迁移为加密格式并能重新加载
+func TestEncryptedStateStore_Migration(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "state.json")
+	t.Setenv("TEST_STATE_KEY", "correct horse battery staple")
+
+	plain, err := NewStateStore(storePath)
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+	if _, err := plain.CheckAndUpdateIfNew("private-org", "secret-repo", "v1.0.0"); err != nil {
+		t.Fatalf("更新状态失败: %v", err)
+	}
+
+	opts := StoreOptions{Encrypt: true, KeyEnv: "TEST_STATE_KEY"}
+	store, err := OpenStateStore(storePath, opts)
+	if err != nil {
+		t.Fatalf("打开加密 StateStore 失败: %v", err)
+	}
+	if tag := store.GetLatestTag("private-org", "secret-repo"); tag != "v1.0.0" {
+		t.Fatalf("迁移后标签 = %q, 期望 v1.0.0", tag)
+	}
+
+	data, err := os.ReadFile(storePath)
+	if err != nil {
+		t.Fatalf("读取状态文件失败: %v", err)
+	}
+	if bytes.Contains(data, []byte("secret-repo")) {
+		t.Fatal("加密后的状态文件中仍包含仓库名")
+	}
+	if _, ok := parseEncryptedState(data); !ok {
+		t.Fatal("状态文件未迁移为加密格式")
+	}
+
+	if _, err := store.CheckAndUpdateIfNew("private-org", "secret-repo", "v1.1.0"); err != nil {
+		t.Fatalf("更新状态失败: %v", err)
+	}
+
+	reopened, err := OpenStateStore(storePath, opts)
+	if err != nil {
+		t.Fatalf("重新打开加密 StateStore 失败: %v", err)
+	}
+	if tag := reopened.GetLatestTag("private-org", "secret-repo"); tag != "v1.1.0" {
+		t.Fatalf("重新加载后标签 = %q, 期望 v1.1.0", tag)
+	}
+}
+
+// TestEncryptedStateStore_Key 测试缺少密钥或密钥错误时无法加载
+func TestEncryptedStateStore_Key(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "state.json")
+	t.Setenv("TEST_STATE_KEY", "key-1")
+
+	store, err := OpenStateStore(storePath, StoreOptions{Encrypt: true, KeyEnv: "TEST_STATE_KEY"})
+	if err != nil {
+		t.Fatalf("创建加密 StateStore 失败: %v", err)
+	}
+	if _, err := store.CheckAndUpdateIfNew("owner", "repo", "v1.0.0"); err != nil {
+		t.Fatalf("更新状态失败: %v", err)
+	}
+
+	if _, err := NewStateStore(storePath); err == nil {
+		t.Error("未提供密钥时应无法加载加密的状态文件")
+	}
+
+	t.Setenv("TEST_STATE_KEY", "key-2")
+	if _, err := OpenStateStore(storePath, StoreOptions{Encrypt: true, KeyEnv: "TEST_STATE_KEY"}); err == nil {
+		t.Error("密钥错误时应无法加载加密的状态文件")
+	}
+
+	t.Setenv("TEST_STATE_KEY", "")
+	if _, err := OpenStateStore(storePath, StoreOptions{Encrypt: true, KeyEnv: "TEST_STATE_KEY"}); err == nil {
+		t.Error("启用加密但未配置密钥时应返回错误")
+	}
+}
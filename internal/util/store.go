@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -15,6 +17,14 @@ type ReleaseState struct {
 	Repository   string    `json:"repository"`
 	LatestTag    string    `json:"latest_tag"`
 	LastNotified time.Time `json:"last_notified"`
+	// Body 最近一次通知时的发布说明，仅在启用编辑跟踪时记录
+	Body string `json:"body,omitempty"`
+	// RepoID GitHub仓库ID，用于在仓库重命名或转移后找回状态
+	RepoID int64 `json:"repo_id,omitempty"`
+	// Skipped 自上次通知以来按仓库通知规则跳过的补丁版本数
+	Skipped int `json:"skipped,omitempty"`
+	// PushedTag 通过标签跟踪通知过、当时还没有Release的标签
+	PushedTag string `json:"pushed_tag,omitempty"`
 }
 
 // StateStore 管理已处理的版本状态
@@ -22,17 +32,34 @@ type StateStore struct {
 	storePath string
 	states    map[string]ReleaseState
 	mu        sync.RWMutex
+	// encrypt 为true时以加密格式保存
+	encrypt bool
+	// passphrase 状态文件密钥，未配置时为空
+	passphrase string
+	// cipher 加解密器，首次加载或保存加密文件时创建
+	cipher *stateCipher
+	// lock 状态文件锁，定时运行、serve 模式等多个进程共用同一个状态文件，每次修改前加锁
+	lock *FileLock
+	// modTime、size 最近一次读取或写入后状态文件的修改时间和大小，用于发现其他进程写入的内容
+	modTime time.Time
+	size    int64
 }
 
 // NewStateStore 创建新的状态存储
 func NewStateStore(storePath string) (*StateStore, error) {
+	return OpenStateStore(storePath, StoreOptions{})
+}
+
+// OpenStateStore 按选项创建状态存储
+// 状态文件已加密时使用密钥透明解密；启用加密时，明文状态文件会在加载后立即迁移为加密格式
+func OpenStateStore(storePath string, opts StoreOptions) (*StateStore, error) {
 	if storePath == "" {
 		// 如果没有指定路径，使用默认路径
-		home, err := os.UserHomeDir()
+		var err error
+		storePath, err = DefaultPath("state.json")
 		if err != nil {
-			return nil, fmt.Errorf("获取用户主目录失败: %v", err)
+			return nil, err
 		}
-		storePath = filepath.Join(home, ".notify", "state.json")
 	}
 
 	// 确保目录存在
@@ -41,9 +68,25 @@ func NewStateStore(storePath string) (*StateStore, error) {
 		return nil, fmt.Errorf("创建状态存储目录失败: %v", err)
 	}
 
+	// 未启用加密时也尝试读取密钥，用于读取之前加密保存的状态文件
+	passphrase, err := ResolveStateKey(opts.KeyEnv, opts.Keyring)
+	if err != nil && opts.Encrypt {
+		return nil, err
+	}
+	if opts.Encrypt && passphrase == "" {
+		return nil, fmt.Errorf("已启用状态文件加密，但未在环境变量 %s 中找到密钥", opts.KeyEnv)
+	}
+
+	lock, err := NewFileLock(storePath + ".lock")
+	if err != nil {
+		return nil, err
+	}
 	store := &StateStore{
-		storePath: storePath,
-		states:    make(map[string]ReleaseState),
+		storePath:  storePath,
+		states:     make(map[string]ReleaseState),
+		encrypt:    opts.Encrypt,
+		passphrase: passphrase,
+		lock:       lock,
 	}
 
 	// 尝试加载现有状态
@@ -58,28 +101,128 @@ func NewStateStore(storePath string) (*StateStore, error) {
 
 // 加载状态文件
 func (s *StateStore) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	encrypted, err := s.readLocked()
+	if err != nil {
+		return err
+	}
+
+	// 加密设置与文件格式不一致时立即按当前设置重写
+	if encrypted != s.encrypt {
+		unlock, err := s.beginLocked()
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		if err := s.saveLocked(); err != nil {
+			return err
+		}
+		if s.encrypt {
+			fmt.Println("已将状态文件迁移为加密格式")
+		} else {
+			fmt.Println("未启用状态文件加密，已将状态文件转换为明文格式")
+		}
+		return os.Chmod(s.storePath, s.fileMode())
+	}
+	return nil
+}
+
+// readLocked 读取状态文件并替换内存中的状态，返回文件是否为加密格式
+func (s *StateStore) readLocked() (bool, error) {
+	info, err := os.Stat(s.storePath)
+	if err != nil {
+		return false, err
+	}
 	data, err := os.ReadFile(s.storePath)
 	if err != nil {
+		return false, err
+	}
+
+	env, encrypted := parseEncryptedState(data)
+	if encrypted {
+		if s.passphrase == "" {
+			return false, fmt.Errorf("状态文件已加密，但未提供密钥")
+		}
+		plain, c, err := openEncryptedState(env, s.passphrase)
+		if err != nil {
+			return false, err
+		}
+		s.cipher = c
+		data = plain
+	}
+
+	states := make(map[string]ReleaseState)
+	if err := json.Unmarshal(data, &states); err != nil {
+		return false, err
+	}
+	s.states = states
+	s.modTime, s.size = info.ModTime(), info.Size()
+	return encrypted, nil
+}
+
+// beginLocked 在已持有写锁的情况下获取状态文件锁，状态文件被其他进程修改过时先重新读取，返回释放文件锁的函数
+// 修改总是基于文件中最新的状态，serve 模式等长期运行的进程不会用过期的内存状态覆盖定时运行写入的版本
+func (s *StateStore) beginLocked() (func(), error) {
+	if err := s.lock.Wait(); err != nil {
+		return nil, fmt.Errorf("获取状态文件锁失败: %v", err)
+	}
+	if info, err := os.Stat(s.storePath); err == nil && (!info.ModTime().Equal(s.modTime) || info.Size() != s.size) {
+		if _, err := s.readLocked(); err != nil {
+			s.lock.Unlock()
+			return nil, fmt.Errorf("重新加载状态文件失败: %v", err)
+		}
+	}
+	return func() { s.lock.Unlock() }, nil
+}
+
+// writeLocked 在已持有写锁和状态文件锁的情况下写入状态文件
+func (s *StateStore) writeLocked(data []byte) error {
+	if err := os.WriteFile(s.storePath, data, s.fileMode()); err != nil {
 		return err
 	}
+	if info, err := os.Stat(s.storePath); err == nil {
+		s.modTime, s.size = info.ModTime(), info.Size()
+	}
+	return nil
+}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// encodeLocked 序列化状态，启用加密时返回加密后的内容
+func (s *StateStore) encodeLocked() ([]byte, error) {
+	data, err := json.MarshalIndent(s.states, "", "  ")
+	if err != nil || !s.encrypt {
+		return data, err
+	}
+
+	if s.cipher == nil {
+		s.cipher, err = newStateCipher(s.passphrase, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.cipher.seal(data)
+}
 
-	return json.Unmarshal(data, &s.states)
+// fileMode 状态文件的权限，加密时只允许当前用户读写
+func (s *StateStore) fileMode() os.FileMode {
+	if s.encrypt {
+		return 0600
+	}
+	return 0644
 }
 
 // 保存状态文件
 func (s *StateStore) save() error {
-	s.mu.RLock()
-	data, err := json.MarshalIndent(s.states, "", "  ")
-	s.mu.RUnlock()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	unlock, err := s.beginLocked()
 	if err != nil {
 		return err
 	}
-
-	return os.WriteFile(s.storePath, data, 0644)
+	defer unlock()
+	return s.saveLocked()
 }
 
 // getKey 生成仓库的唯一键
@@ -105,15 +248,107 @@ func (s *StateStore) UpdateState(owner, repo, tag string) error {
 	key := getKey(owner, repo)
 
 	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	s.states[key] = ReleaseState{
 		Owner:        owner,
 		Repository:   repo,
 		LatestTag:    tag,
 		LastNotified: time.Now(),
+		RepoID:       s.states[key].RepoID,
+		Skipped:      s.states[key].Skipped,
+		PushedTag:    s.states[key].PushedTag,
 	}
-	s.mu.Unlock()
+	return s.saveLocked()
+}
 
-	return s.save()
+// Snapshot 返回所有仓库状态的副本，按 owner/repo 排序
+func (s *StateStore) Snapshot() []ReleaseState {
+	s.mu.RLock()
+	states := make([]ReleaseState, 0, len(s.states))
+	for _, state := range s.states {
+		states = append(states, state)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(states, func(i, j int) bool {
+		return getKey(states[i].Owner, states[i].Repository) < getKey(states[j].Owner, states[j].Repository)
+	})
+	return states
+}
+
+// SkippedCount 返回自上次通知以来按通知规则跳过的补丁版本数
+func (s *StateStore) SkippedCount(owner, repo string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.states[getKey(owner, repo)].Skipped
+}
+
+// SetSkipped 记录按通知规则跳过的补丁版本数，通知后重置为0
+func (s *StateStore) SetSkipped(owner, repo string, n int) error {
+	key := getKey(owner, repo)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, exists := s.states[key]
+	if !exists || state.Skipped == n {
+		return nil
+	}
+	state.Skipped = n
+	s.states[key] = state
+	return s.saveLocked()
+}
+
+// PushedTag 返回通过标签跟踪通知过的标签
+func (s *StateStore) PushedTag(owner, repo string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.states[getKey(owner, repo)].PushedTag
+}
+
+// CheckAndUpdatePushedTag 原子性地检查标签是否已通知过，未通知过时记录并保存
+// 只记录标签，不修改最新版本，之后创建的Release仍按新版本检测
+func (s *StateStore) CheckAndUpdatePushedTag(owner, repo, tag string) (bool, error) {
+	key := getKey(owner, repo)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	state, exists := s.states[key]
+	if exists && (state.PushedTag == tag || state.LatestTag == tag) {
+		return false, nil
+	}
+	if !exists {
+		state = ReleaseState{Owner: owner, Repository: repo}
+	}
+	state.PushedTag = tag
+	state.LastNotified = time.Now()
+	s.states[key] = state
+	if err := s.saveLocked(); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // IsNewRelease 检查是否为新版本
@@ -132,6 +367,12 @@ func (s *StateStore) CheckAndUpdateIfNew(owner, repo, tag string) (bool, error)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
 	// 检查是否为新版本
 	currentState, exists := s.states[key]
 
@@ -146,28 +387,225 @@ func (s *StateStore) CheckAndUpdateIfNew(owner, repo, tag string) (bool, error)
 		Repository:   repo,
 		LatestTag:    tag,
 		LastNotified: time.Now(),
+		RepoID:       currentState.RepoID,
+		Skipped:      currentState.Skipped,
+		PushedTag:    currentState.PushedTag,
 	}
 
 	// 立即保存到文件（在锁内完成，确保原子性）
-	// 注意：这里直接序列化和写文件，不使用 save() 方法，避免重复加锁
-	data, err := json.MarshalIndent(s.states, "", "  ")
+	if err := s.commitLocked(key, currentState, exists); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// commitLocked 保存 key 的新状态，调用方持有锁
+// 序列化失败是严重错误，恢复 key 原来的状态并返回错误，调用方稍后重试时仍按未记录处理；
+// 文件写入失败时保留内存状态并打印警告，返回nil，避免本次运行重复通知（重启后可能重复）
+func (s *StateStore) commitLocked(key string, previous ReleaseState, existed bool) error {
+	data, err := s.encodeLocked()
 	if err != nil {
-		// 序列化失败是严重错误，返回错误并记录
+		if existed {
+			s.states[key] = previous
+		} else {
+			delete(s.states, key)
+		}
 		fmt.Printf("错误: 序列化状态失败: %v\n", err)
-		return false, fmt.Errorf("序列化状态失败: %v", err)
+		return fmt.Errorf("序列化状态失败: %v", err)
 	}
 
-	// 写入文件
-	if err := os.WriteFile(s.storePath, data, 0644); err != nil {
-		// 文件写入失败是严重错误
-		// 但因为内存状态已更新，为了避免重复通知，我们返回 true
-		// 同时记录错误日志，方便排查
+	if err := s.writeLocked(data); err != nil {
 		fmt.Printf("警告: 保存状态文件失败: %v (内存状态已更新，本次不会重复通知，但重启后可能重复)\n", err)
-		return true, nil // 返回 true，避免本次运行重复通知
 	}
+	return nil
+}
 
-	// 成功保存
-	return true, nil
+// CheckAndUpdateRelease 与 CheckAndUpdateIfNew 相同，但同时记录发布说明，保存失败时的处理也相同
+// 返回值: (isNew bool, previousBody string, err error)
+// - isNew: true 表示是新版本并已更新状态
+// - previousBody: 同一版本之前记录的发布说明（发布说明发生变化时才返回，首次记录时为空）
+// - err: 序列化状态失败时返回，内存状态已恢复
+func (s *StateStore) CheckAndUpdateRelease(owner, repo, tag, body string) (bool, string, error) {
+	key := getKey(owner, repo)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return false, "", err
+	}
+	defer unlock()
+
+	currentState, exists := s.states[key]
+
+	if exists && currentState.LatestTag == tag {
+		// 同一版本，发布说明未变化
+		if currentState.Body == body {
+			return false, "", nil
+		}
+
+		// 发布说明有变化，记录新的内容
+		updated := currentState
+		updated.Body = body
+		s.states[key] = updated
+		if err := s.commitLocked(key, currentState, exists); err != nil {
+			return false, "", err
+		}
+		return false, currentState.Body, nil
+	}
+
+	s.states[key] = ReleaseState{
+		Owner:        owner,
+		Repository:   repo,
+		LatestTag:    tag,
+		LastNotified: time.Now(),
+		Body:         body,
+		RepoID:       currentState.RepoID,
+		Skipped:      currentState.Skipped,
+		PushedTag:    currentState.PushedTag,
+	}
+	if err := s.commitLocked(key, currentState, exists); err != nil {
+		return false, "", err
+	}
+	return true, "", nil
+}
+
+// MigrateRepo 仓库重命名或转移后，将旧名称下的状态迁移到新名称，返回是否有状态被迁移
+// Issue状态文件中仓库名为 name#编号，同一仓库的所有Issue一起迁移
+// 新名称下已有状态时保留新名称的状态
+func (s *StateStore) MigrateRepo(oldOwner, oldRepo, newOwner, newRepo string) (bool, error) {
+	oldKey := getKey(oldOwner, oldRepo)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	migrated := false
+	for key, state := range s.states {
+		// Issue状态的仓库名为 name#编号
+		suffix, ok := strings.CutPrefix(key, oldKey)
+		if !ok || (suffix != "" && !strings.HasPrefix(suffix, "#")) {
+			continue
+		}
+
+		delete(s.states, key)
+		newKey := getKey(newOwner, newRepo+suffix)
+		if _, exists := s.states[newKey]; exists {
+			continue
+		}
+		state.Owner = newOwner
+		state.Repository = newRepo + suffix
+		s.states[newKey] = state
+		migrated = true
+	}
+
+	if !migrated {
+		return false, nil
+	}
+	return true, s.saveLocked()
+}
+
+// MoveStates 把满足 match 的状态移到 dst 并保存两个文件，返回移动的状态数；dst 中已有同名状态时保留 dst 的状态
+// 先保存 dst 再保存当前文件，中途失败时状态不会丢失
+func (s *StateStore) MoveStates(dst *StateStore, match func(ReleaseState) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	var keys []string
+	for key, state := range s.states {
+		if match(state) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	dst.mu.Lock()
+	defer dst.mu.Unlock()
+
+	unlockDst, err := dst.beginLocked()
+	if err != nil {
+		return 0, err
+	}
+	defer unlockDst()
+
+	for _, key := range keys {
+		if _, exists := dst.states[key]; !exists {
+			dst.states[key] = s.states[key]
+		}
+	}
+	if err := dst.saveLocked(); err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		delete(s.states, key)
+	}
+	return len(keys), s.saveLocked()
+}
+
+// FindRepoByID 按GitHub仓库ID查找状态中记录的仓库名称
+func (s *StateStore) FindRepoByID(id int64) (owner, repo string, ok bool) {
+	if id == 0 {
+		return "", "", false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, state := range s.states {
+		if state.RepoID == id && !strings.Contains(state.Repository, "#") {
+			return state.Owner, state.Repository, true
+		}
+	}
+	return "", "", false
+}
+
+// SetRepoID 记录仓库的GitHub ID，仓库还没有状态或ID未变化时不做任何操作
+func (s *StateStore) SetRepoID(owner, repo string, id int64) error {
+	key := getKey(owner, repo)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.beginLocked()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, exists := s.states[key]
+	if !exists || id == 0 || state.RepoID == id {
+		return nil
+	}
+	state.RepoID = id
+	s.states[key] = state
+	return s.saveLocked()
+}
+
+// saveLocked 在已持有写锁的情况下保存状态文件
+func (s *StateStore) saveLocked() error {
+	data, err := s.encodeLocked()
+	if err != nil {
+		return fmt.Errorf("序列化状态失败: %v", err)
+	}
+	if err := s.writeLocked(data); err != nil {
+		return fmt.Errorf("保存状态文件失败: %v", err)
+	}
+	return nil
 }
 
 // SaveState 显式保存状态到文件
@@ -3,6 +3,7 @@ package util
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -186,3 +187,211 @@ func BenchmarkCheckAndUpdateIfNew(b *testing.B) {
 		}
 	}
 }
+
+// TestMigrateRepo 测试仓库重命名后状态的迁移
+func TestMigrateRepo(t *testing.T) {
+	store, err := NewStateStore(filepath.Join(t.TempDir(), "test_state.json"))
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+
+	if _, err := store.CheckAndUpdateIfNew("old-owner", "repo", "v1.0.0"); err != nil {
+		t.Fatalf("CheckAndUpdateIfNew 失败: %v", err)
+	}
+	if _, err := store.CheckAndUpdateIfNew("old-owner", "repo#12", "open"); err != nil {
+		t.Fatalf("CheckAndUpdateIfNew 失败: %v", err)
+	}
+	if _, err := store.CheckAndUpdateIfNew("old-owner", "repo-cli", "v2.0.0"); err != nil {
+		t.Fatalf("CheckAndUpdateIfNew 失败: %v", err)
+	}
+	if err := store.SetRepoID("old-owner", "repo", 42); err != nil {
+		t.Fatalf("SetRepoID 失败: %v", err)
+	}
+
+	if owner, repo, ok := store.FindRepoByID(42); !ok || owner != "old-owner" || repo != "repo" {
+		t.Fatalf("FindRepoByID 返回 %s/%s %v，期望 old-owner/repo", owner, repo, ok)
+	}
+
+	migrated, err := store.MigrateRepo("old-owner", "repo", "new-owner", "renamed")
+	if err != nil {
+		t.Fatalf("MigrateRepo 失败: %v", err)
+	}
+	if !migrated {
+		t.Fatal("期望状态被迁移")
+	}
+
+	if tag := store.GetLatestTag("new-owner", "renamed"); tag != "v1.0.0" {
+		t.Errorf("新名称的版本为 %q，期望 v1.0.0", tag)
+	}
+	if tag := store.GetLatestTag("new-owner", "renamed#12"); tag != "open" {
+		t.Errorf("新名称的Issue状态为 %q，期望 open", tag)
+	}
+	if tag := store.GetLatestTag("old-owner", "repo"); tag != "" {
+		t.Errorf("旧名称的状态应该已删除，实际为 %q", tag)
+	}
+	if tag := store.GetLatestTag("old-owner", "repo-cli"); tag != "v2.0.0" {
+		t.Errorf("名称前缀相同的其他仓库不应被迁移，实际为 %q", tag)
+	}
+	if owner, repo, ok := store.FindRepoByID(42); !ok || owner != "new-owner" || repo != "renamed" {
+		t.Errorf("迁移后 FindRepoByID 返回 %s/%s %v，期望 new-owner/renamed", owner, repo, ok)
+	}
+
+	// 新版本检查应基于迁移后的状态，不把新名称当作新仓库
+	isNew, err := store.CheckAndUpdateIfNew("new-owner", "renamed", "v1.0.0")
+	if err != nil {
+		t.Fatalf("CheckAndUpdateIfNew 失败: %v", err)
+	}
+	if isNew {
+		t.Error("迁移后同一版本不应被视为新版本")
+	}
+}
+
+// TestCheckAndUpdateIfNew_SharedFile 测试多个进程共用状态文件时，修改前重新读取其他进程写入的状态
+func TestCheckAndUpdateIfNew_SharedFile(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "test_state.json")
+
+	// serve 模式长期持有的状态
+	server, err := NewStateStore(storePath)
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+	// 定时运行打开的状态
+	poller, err := NewStateStore(storePath)
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+
+	if isNew, err := poller.CheckAndUpdateIfNew("owner", "polled", "v1.0.0"); err != nil || !isNew {
+		t.Fatalf("定时运行记录版本返回 %v（%v）", isNew, err)
+	}
+	if isNew, err := server.CheckAndUpdateIfNew("owner", "hooked", "v2.0.0"); err != nil || !isNew {
+		t.Fatalf("serve 模式记录版本返回 %v（%v）", isNew, err)
+	}
+	// 定时运行已记录的版本不再通知
+	if isNew, err := server.CheckAndUpdateIfNew("owner", "polled", "v1.0.0"); err != nil || isNew {
+		t.Errorf("定时运行已记录的版本返回 %v（%v），期望不是新版本", isNew, err)
+	}
+
+	reloaded, err := NewStateStore(storePath)
+	if err != nil {
+		t.Fatalf("从文件加载 StateStore 失败: %v", err)
+	}
+	if tag := reloaded.GetLatestTag("owner", "polled"); tag != "v1.0.0" {
+		t.Errorf("定时运行记录的版本为 %q，期望 v1.0.0（被 serve 模式的旧状态覆盖）", tag)
+	}
+	if tag := reloaded.GetLatestTag("owner", "hooked"); tag != "v2.0.0" {
+		t.Errorf("serve 模式记录的版本为 %q，期望 v2.0.0", tag)
+	}
+}
+
+// TestCheckAndUpdateRelease 与 CheckAndUpdateIfNew 对同样的版本序列返回相同的结果，并在发布说明变化时返回之前的内容
+func TestCheckAndUpdateRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		body string
+		// writeFails 状态文件无法写入
+		writeFails   bool
+		wantNew      bool
+		wantPrevious string
+	}{
+		{name: "首次记录", tag: "v1.0.0", body: "修复", wantNew: true},
+		{name: "同一版本", tag: "v1.0.0", body: "修复"},
+		{name: "发布说明变化", tag: "v1.0.0", body: "修复\n新增", wantPrevious: "修复"},
+		{name: "新版本", tag: "v1.1.0", body: "优化", wantNew: true},
+		{name: "写入失败的新版本", tag: "v1.2.0", body: "重构", writeFails: true, wantNew: true},
+		{name: "写入失败后同一版本不重复通知", tag: "v1.2.0", body: "重构", writeFails: true},
+	}
+
+	dir := t.TempDir()
+	releases, err := NewStateStore(filepath.Join(dir, "releases.json"))
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+	tags, err := NewStateStore(filepath.Join(dir, "tags.json"))
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.writeFails {
+				releases.storePath = filepath.Join(dir, "missing", "releases.json")
+				tags.storePath = filepath.Join(dir, "missing", "tags.json")
+			}
+
+			isNew, previous, err := releases.CheckAndUpdateRelease("owner", "repo", tt.tag, tt.body)
+			if err != nil {
+				t.Fatalf("CheckAndUpdateRelease 返回错误: %v", err)
+			}
+			if isNew != tt.wantNew || previous != tt.wantPrevious {
+				t.Errorf("CheckAndUpdateRelease 返回 (%v, %q)，期望 (%v, %q)", isNew, previous, tt.wantNew, tt.wantPrevious)
+			}
+
+			// 只比较版本号时与 CheckAndUpdateIfNew 的结果一致
+			if tt.wantPrevious == "" {
+				isNew, err := tags.CheckAndUpdateIfNew("owner", "repo", tt.tag)
+				if err != nil || isNew != tt.wantNew {
+					t.Errorf("CheckAndUpdateIfNew 返回 (%v, %v)，期望 (%v, nil)", isNew, err, tt.wantNew)
+				}
+			}
+		})
+	}
+
+	// 写入失败前记录的发布说明已保存到文件
+	reloaded, err := NewStateStore(filepath.Join(dir, "releases.json"))
+	if err != nil {
+		t.Fatalf("从文件加载 StateStore 失败: %v", err)
+	}
+	if state := reloaded.states[getKey("owner", "repo")]; state.LatestTag != "v1.1.0" || state.Body != "优化" {
+		t.Errorf("文件中的状态为 %+v，期望 v1.1.0 和它的发布说明", state)
+	}
+}
+
+// TestMoveStates 满足条件的状态移到另一个文件，目标中已有的状态保留
+func TestMoveStates(t *testing.T) {
+	dir := t.TempDir()
+	src, err := NewStateStore(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+	dst, err := NewStateStore(filepath.Join(dir, "state.issues.json"))
+	if err != nil {
+		t.Fatalf("创建 StateStore 失败: %v", err)
+	}
+	for repo, tag := range map[string]string{"repo": "v1.0.0", "repo#1": "open", "repo#2": "closed"} {
+		if err := src.UpdateState("owner", repo, tag); err != nil {
+			t.Fatalf("更新状态失败: %v", err)
+		}
+	}
+	if err := dst.UpdateState("owner", "repo#2", "open"); err != nil {
+		t.Fatalf("更新状态失败: %v", err)
+	}
+
+	isIssue := func(s ReleaseState) bool { return strings.Contains(s.Repository, "#") }
+	moved, err := src.MoveStates(dst, isIssue)
+	if err != nil || moved != 2 {
+		t.Fatalf("移动了 %d 个状态（%v），期望 2 个", moved, err)
+	}
+	if moved, err := src.MoveStates(dst, isIssue); err != nil || moved != 0 {
+		t.Errorf("再次移动了 %d 个状态（%v），期望 0 个", moved, err)
+	}
+
+	reloaded, err := NewStateStore(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatalf("从文件加载 StateStore 失败: %v", err)
+	}
+	if states := reloaded.Snapshot(); len(states) != 1 || states[0].Repository != "repo" {
+		t.Errorf("版本状态为 %+v，期望只有 owner/repo", states)
+	}
+	issues, err := NewStateStore(filepath.Join(dir, "state.issues.json"))
+	if err != nil {
+		t.Fatalf("从文件加载 StateStore 失败: %v", err)
+	}
+	if tag := issues.GetLatestTag("owner", "repo#1"); tag != "open" {
+		t.Errorf("repo#1 的状态为 %q，期望 open", tag)
+	}
+	if tag := issues.GetLatestTag("owner", "repo#2"); tag != "open" {
+		t.Errorf("repo#2 的状态为 %q，期望保留目标中的 open", tag)
+	}
+}
@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/orange-juzipi/notify/config"
 	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/crates"
 	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/gitlab"
+	"github.com/orange-juzipi/notify/pkg/goproxy"
+	"github.com/orange-juzipi/notify/pkg/maven"
 	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/orange-juzipi/notify/pkg/notifier/fault"
+	"github.com/orange-juzipi/notify/pkg/npm"
+	"github.com/orange-juzipi/notify/pkg/pypi"
+	"github.com/orange-juzipi/notify/pkg/source"
+	"github.com/orange-juzipi/notify/pkg/vscode"
 	"github.com/robfig/cron/v3"
 	"github.com/spf13/cobra"
 )
@@ -18,6 +30,10 @@ import (
 func main() {
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
+		var exitErr *exitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
@@ -26,51 +42,158 @@ var (
 	configFile      string
 	showDescription bool
 	checkDays       int
+	shardFlag       string
+	tenantFlag      string
+	failOnNew       bool
+	failLevel       string
+	faultSpec       string
 )
 
+// sources GitHub以外的版本来源，按顺序检查
+var sources = []source.Source{
+	gitlab.Source{},
+	npm.Source{},
+	pypi.Source{},
+	crates.Source{},
+	goproxy.Source{},
+	maven.Source{},
+	vscode.Source{},
+}
+
 // RootCmd 表示没有子命令时的基础命令
 var RootCmd = &cobra.Command{
 	Use:   "notify",
 	Short: "GitHub仓库版本发布通知工具",
-	Long: `Notify 是一个GitHub仓库版本发布通知工具，支持钉钉和Telegram通知渠道。
+	Long: `Notify 是一个GitHub仓库版本发布通知工具，支持钉钉、企业微信、飞书、Telegram、Slack、Microsoft Teams、邮件、ntfy、桌面通知、MQTT、Rocket.Chat、Google Chat、IRC、Pushbullet、Mastodon、Kafka、PagerDuty、Opsgenie、Webex、syslog和通用webhook通知渠道。
 可以通过配置文件或环境变量设置要监控的仓库和通知方式。`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// 创建文件锁，防止多个实例同时运行
-		lock, err := util.NewFileLock("")
+		if err := validateGateLevel(failLevel); err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("fail-level") && !failOnNew {
+			return fmt.Errorf("--fail-level 需要与 --fail-on-new 一起使用")
+		}
+
+		// 解析故障注入参数
+		if faultSpec != "" {
+			if _, err := fault.ParseSpec(faultSpec); err != nil {
+				return err
+			}
+		}
+
+		// 解析分片参数
+		var shard config.ShardConfig
+		if shardFlag != "" {
+			var err error
+			shard, err = config.ParseShard(shardFlag)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 加载配置，定时运行时收到 SIGHUP 后按相同的命令行参数重新加载
+		load := func() (*config.Config, []*config.Config, error) {
+			return loadTargets(shard, cmd.Flags().Changed("days"))
+		}
+		cfg, targets, err := load()
+		if err != nil {
+			return err
+		}
+
+		// 创建文件锁，防止多个实例同时运行（每个分片、单独运行的租户使用独立的锁）
+		lockCfg := cfg
+		if tenantFlag != "" {
+			lockCfg = targets[0]
+		}
+		lockPath, err := util.ResolvePath("", "notify.lock", lockCfg.DataSuffix())
+		if err != nil {
+			return err
+		}
+		lock, err := util.NewFileLock(lockPath)
 		if err != nil {
 			return fmt.Errorf("创建文件锁失败: %v", err)
 		}
 
 		// 尝试获取锁
-		err = lock.Lock()
+		err = acquireLock(lock, cfg)
 		if err != nil {
 			return fmt.Errorf("⚠️  %v\n提示：请检查是否有其他 notify 进程正在运行", err)
 		}
 		defer lock.Unlock()
 
 		fmt.Printf("✓ 获取进程锁成功 (PID: %d)\n", os.Getpid())
+		if cfg.Shard.Enabled() {
+			fmt.Printf("✓ 分片运行: %s\n", cfg.Shard)
+		}
 
-		// 加载配置
-		cfg, err := config.LoadConfig(configFile)
-		if err != nil {
-			return fmt.Errorf("加载配置失败: %v", err)
+		// 打印出口IP，方便配置钉钉机器人的IP白名单
+		if cfg.Network.LocalAddr != "" || cfg.Network.ReportEgressIP {
+			reportEgressIP(cfg)
 		}
 
-		// 如果命令行参数设置了检查天数，覆盖配置文件中的设置
-		if cmd.Flags().Changed("days") {
-			cfg.GitHub.CheckDays = checkDays
+		for _, target := range targets {
+			if target.TenantName != "" {
+				fmt.Printf("\n[租户 %s]\n", target.TenantName)
+			}
+			// 检查GitHub Token是否具备已启用功能所需的权限
+			checkToken(target)
 		}
 
-		// 如果启用了定时运行
-		if cfg.Schedule.Enabled {
-			return runAsScheduler(cfg)
+		// 收到终止信号后中断正在进行的发送，未发送的通知放入失败队列
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		// 如果启用了定时运行（--fail-on-new 总是只运行一次）
+		if scheduled(targets) && !failOnNew {
+			return runAsScheduler(ctx, targets, func() ([]*config.Config, error) {
+				_, targets, err := load()
+				return targets, err
+			})
 		}
 
 		// 否则只运行一次
-		return runOnce(cfg)
+		return runAll(ctx, targets)
 	},
 }
 
+// loadTargets 加载配置并应用命令行参数，返回配置和需要运行的租户（未配置租户时为配置本身）
+// daysChanged 为true时用 --days 覆盖各租户的检查天数
+func loadTargets(shard config.ShardConfig, daysChanged bool) (*config.Config, []*config.Config, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("加载配置失败: %v", err)
+	}
+	if shard.Enabled() {
+		cfg.Shard = shard
+	}
+
+	// 配置了租户时依次运行各租户，--tenant 只运行指定的租户
+	targets, err := runTargets(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 如果命令行参数设置了检查天数，覆盖配置文件中的设置
+	if daysChanged {
+		for _, target := range targets {
+			target.GitHub.CheckDays = checkDays
+		}
+	}
+	return cfg, targets, nil
+}
+
+// runUnlessFresh 执行一次检查，刚有一次成功的运行完成（如与上一次cron调用重叠）时跳过重复的完整扫描
+func runUnlessFresh(ctx context.Context, cfg *config.Config) error {
+	if !failOnNew {
+		if last, fresh := recentSuccess(cfg); fresh {
+			fmt.Printf("上次运行已于 %s 成功完成（%s 前），在 run.skip_if_fresh 窗口内，跳过本次运行\n",
+				last.FinishedAt.Format(time.DateTime), time.Since(last.FinishedAt).Round(time.Second))
+			return nil
+		}
+	}
+	return runOnce(ctx, cfg)
+}
+
 func init() {
 	// 添加配置文件标志
 	RootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "配置文件路径 (默认为 ./config.yaml 或 ~/.notify/config.yaml)")
@@ -78,25 +201,150 @@ func init() {
 	RootCmd.PersistentFlags().BoolVarP(&showDescription, "show-description", "d", false, "是否在通知中显示仓库版本描述信息")
 	// 添加检查天数的标志
 	RootCmd.PersistentFlags().IntVarP(&checkDays, "days", "n", config.DefaultCheckDays, "检查最近多少天内的版本发布")
+	// 添加分片运行的标志
+	RootCmd.PersistentFlags().StringVar(&shardFlag, "shard", "", "分片运行，格式为 i/n（如 1/4），多个实例各自检查一部分仓库")
+	// 添加选择租户的标志
+	RootCmd.PersistentFlags().StringVar(&tenantFlag, "tenant", "", "只使用指定租户的配置和数据（配置了 tenants 时）")
+	// 添加发现新版本时返回非零退出码的标志，用于CI门禁
+	RootCmd.PersistentFlags().BoolVar(&failOnNew, "fail-on-new", false, "只运行一次，发现新版本时以退出码2退出")
+	RootCmd.PersistentFlags().StringVar(&failLevel, "fail-level", "any", "--fail-on-new 的级别: any、major、minor、patch")
+	// 添加故障注入标志，仅用于测试重试和失败队列，不在帮助中显示
+	RootCmd.PersistentFlags().StringVar(&faultSpec, "fault", "", "启用故障注入通知器，如 fail=0.3,timeout=0.1,ratelimit=0.2,delay=5s,seed=42")
+	RootCmd.PersistentFlags().MarkHidden("fault")
 }
 
-// runOnce 执行一次检查
-func runOnce(cfg *config.Config) error {
+// reportEgressIP 查询并打印出口IP
+func reportEgressIP(cfg *config.Config) {
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: cfg.Network.LocalAddr,
+	})
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+
+	ip, err := util.DetectEgressIP(client, cfg.Network.EgressCheckURL)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return
+	}
+
+	if cfg.Network.LocalAddr != "" {
+		fmt.Printf("✓ 出站请求绑定本地地址 %s，出口IP: %s\n", cfg.Network.LocalAddr, ip)
+	} else {
+		fmt.Printf("✓ 出口IP: %s\n", ip)
+	}
+}
+
+// runOnce 执行一次检查，ctx取消后中断发送
+func runOnce(ctx context.Context, cfg *config.Config) error {
+	return runSession(ctx, cfg, &session{})
+}
+
+// runSession 使用 sess 中的通知管理器和GitHub检查器执行一次检查，尚未创建时先创建
+func runSession(ctx context.Context, cfg *config.Config, sess *session) (err error) {
+	// 记录运行历史
+	started := time.Now()
+	detected := 0
+	// before 复用的通知管理器在本次运行前的发送统计
+	var before notifier.SendStats
+	stats := github.CheckStats{QuotaRemaining: -1}
+	defer func() {
+		var sent notifier.SendStats
+		if sess.manager != nil {
+			sent = sess.manager.Stats().Sub(before)
+		}
+		reportRenderFailures(sess.manager)
+		recordRun(cfg, started, detected, err)
+		recordMetrics(cfg, started, detected, stats, sent, err)
+	}()
+
+	if err := sess.init(cfg); err != nil {
+		return err
+	}
+	manager := sess.manager
+	before = manager.Stats()
+
+	// 优先重发上次运行中发送失败的通知
+	if errs := manager.DrainOutboxContext(ctx); len(errs) > 0 {
+		fmt.Printf("⚠️ %d 条待重发的通知仍然发送失败，将在下次运行时继续重试\n", len(errs))
+	}
+
+	// 发送已批准的版本，补发未发送的审批汇总
+	if errs := manager.ProcessApprovals(ctx); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Printf("⚠️ 处理发送审批失败: %v\n", err)
+		}
+	}
+
+	// 记录"已查看"按钮的确认
+	for _, err := range manager.ProcessAcks() {
+		fmt.Printf("⚠️ 读取确认回调失败: %v\n", err)
+	}
+
 	// 检查新版本
-	releases, err := github.CheckForNewReleases(cfg, showDescription)
+	releases, stats, err := sess.checker.Check(cfg, showDescription)
 	if err != nil {
 		return fmt.Errorf("检查新版本失败: %v", err)
 	}
 
+	// 检查其他来源，某个来源失败时仍然发送其他来源的新版本
+	for _, src := range sources {
+		if !src.Enabled(cfg) {
+			continue
+		}
+		found, err := src.Check(cfg, showDescription)
+		if err != nil {
+			fmt.Printf("⚠️ 检查%s失败: %v\n", src.Name(), err)
+		}
+		releases = append(releases, found...)
+	}
+	detected = len(releases)
+
+	// 定时运行时，合并窗口内的新版本先累积，窗口结束后合并发送
+	if cfg.Schedule.Enabled && !failOnNew {
+		found := len(releases)
+		releases, err = manager.Coalesce(releases)
+		if err != nil {
+			return fmt.Errorf("合并新版本失败: %v", err)
+		}
+		if len(releases) == 0 && found > 0 {
+			sess.commitWatchList()
+			return nil
+		}
+	}
+
 	if len(releases) == 0 {
 		fmt.Println("没有找到新版本")
+		sess.commitWatchList()
 		return nil
 	}
 
-	// 创建通知管理器
-	manager, err := notifier.NewManager(cfg)
-	if err != nil {
-		return fmt.Errorf("创建通知管理器失败: %v", err)
+	// CI门禁模式：通知发送完成后按发现的新版本返回退出码
+	if failOnNew {
+		defer func() {
+			if gateErr := checkGate(releases, failLevel); gateErr != nil {
+				err = gateErr
+			}
+		}()
+	}
+
+	// 暂停期间只记录检测结果，恢复后发送
+	if _, paused := manager.PausedUntil(); paused {
+		manager.NotifyAllContext(ctx, releases)
+		sess.commitWatchList()
+		return nil
+	}
+
+	// 发送审批：只发送汇总到审批渠道，批准后再发送到其他渠道
+	if cfg.Approval.Enabled {
+		if errs := manager.NotifyAllContext(ctx, releases); len(errs) > 0 {
+			return fmt.Errorf("发送审批汇总失败，下次运行时重发: %v", errs[0])
+		}
+		fmt.Printf("找到 %d 个新版本发布，已发送审批汇总，批准后发送通知\n", len(releases))
+		sess.commitWatchList()
+		return nil
 	}
 
 	// 打印发现的版本数量
@@ -107,7 +355,10 @@ func runOnce(cfg *config.Config) error {
 	}
 
 	// 发送通知
-	errors := manager.NotifyAll(releases)
+	errors := manager.NotifyAllContext(ctx, releases)
+	if ctx.Err() != nil {
+		return fmt.Errorf("收到终止信号，发送已中断，未发送的通知将在下次运行时重发")
+	}
 	if len(errors) > 0 {
 		// 分析错误以提供更好的反馈
 		var rateLimitErrors, otherErrors []error
@@ -142,37 +393,105 @@ func runOnce(cfg *config.Config) error {
 	}
 
 	fmt.Printf("成功发送了 %d 个版本发布通知\n", len(releases))
+	sess.commitWatchList()
 	return nil
 }
 
-// runAsScheduler 作为定时任务运行
-func runAsScheduler(cfg *config.Config) error {
-	// 设置信号处理
-	signals := make(chan os.Signal, 1)
-	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+// runAsScheduler 作为定时任务运行，每个租户按各自的cron表达式检查，ctx取消（收到终止信号）后中断正在进行的检查并退出
+// 通知管理器和GitHub客户端在多次检查之间复用，收到 SIGHUP 后调用 reload 重新加载配置
+func runAsScheduler(ctx context.Context, cfgs []*config.Config, reload func() ([]*config.Config, error)) error {
+	c := cron.New(cron.WithSeconds())
+	var scheds []*scheduler
+	for _, cfg := range cfgs {
+		if !cfg.Schedule.Enabled {
+			fmt.Printf("租户 %s 未启用定时运行，跳过\n", cfg.TenantName)
+			continue
+		}
+		if cfg.Schedule.Cron == "" {
+			if cfg.TenantName != "" {
+				return fmt.Errorf("租户 %s 未配置cron表达式，请在配置文件中设置schedule.cron", cfg.TenantName)
+			}
+			return fmt.Errorf("未配置cron表达式，请在配置文件中设置schedule.cron")
+		}
+
+		if cfg.TenantName != "" {
+			fmt.Printf("租户 %s 以cron表达式模式运行，表达式: %s\n", cfg.TenantName, cfg.Schedule.Cron)
+		} else {
+			fmt.Printf("以cron表达式模式运行，表达式: %s\n", cfg.Schedule.Cron)
+		}
+		sched := newScheduler(ctx, cfg)
+		defer sched.stop()
 
-	if cfg.Schedule.Cron != "" {
-		fmt.Printf("以cron表达式模式运行，表达式: %s\n", cfg.Schedule.Cron)
-		c := cron.New(cron.WithSeconds())
 		_, err := c.AddFunc(cfg.Schedule.Cron, func() {
-			err := runOnce(cfg)
+			err := sched.run()
 			if err != nil {
-				fmt.Printf("定时检查失败: %v\n", err)
+				fmt.Printf("%s定时检查失败: %v\n", tenantPrefix(cfg), err)
 			}
 		})
 		if err != nil {
-			return fmt.Errorf("解析cron表达式失败: %v", err)
+			return fmt.Errorf("%s解析cron表达式失败: %v", tenantPrefix(cfg), err)
 		}
-		// 立即进行第一次检查
-		if err := runOnce(cfg); err != nil {
-			fmt.Printf("初始检查失败: %v\n", err)
+		scheds = append(scheds, sched)
+	}
+
+	// 立即进行第一次检查
+	for _, sched := range scheds {
+		if err := sched.run(); err != nil {
+			fmt.Printf("%s初始检查失败: %v\n", tenantPrefix(sched.cfg), err)
 		}
-		c.Start()
-		defer c.Stop()
-		<-signals
-		fmt.Println("收到终止信号，程序退出")
-		return nil
 	}
+	c.Start()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	for {
+		select {
+		case <-hup:
+			fmt.Println("收到 SIGHUP，重新加载配置")
+			reloadSchedulers(scheds, reload)
+		case <-ctx.Done():
+			fmt.Println("收到终止信号，等待正在进行的检查保存后退出")
+			<-c.Stop().Done()
+			return nil
+		}
+	}
+}
+
+// reloadSchedulers 重新加载配置并按租户名替换各调度器的配置，加载失败时继续使用原配置
+// cron表达式和租户的增减在重启后生效
+func reloadSchedulers(scheds []*scheduler, reload func() ([]*config.Config, error)) {
+	cfgs, err := reload()
+	if err != nil {
+		fmt.Printf("⚠️ 重新加载配置失败，继续使用原配置: %v\n", err)
+		return
+	}
+
+	byTenant := make(map[string]*config.Config, len(cfgs))
+	for _, cfg := range cfgs {
+		byTenant[cfg.TenantName] = cfg
+	}
+	for _, sched := range scheds {
+		sched.running.Lock()
+		old := sched.cfg
+		sched.running.Unlock()
 
-	return fmt.Errorf("未配置cron表达式，请在配置文件中设置schedule.cron")
+		cfg, ok := byTenant[old.TenantName]
+		if !ok {
+			fmt.Printf("⚠️ 新配置中没有租户 %s，继续使用原配置，重启后生效\n", old.TenantName)
+			continue
+		}
+		delete(byTenant, old.TenantName)
+		if cfg.Schedule.Cron != old.Schedule.Cron || !cfg.Schedule.Enabled {
+			fmt.Printf("⚠️ %scron表达式或定时运行开关的修改需要重启后生效\n", tenantPrefix(cfg))
+		}
+		checkToken(cfg)
+		sched.reload(cfg)
+		fmt.Printf("✓ %s已加载新配置，下次检查时生效\n", tenantPrefix(cfg))
+	}
+	for name, cfg := range byTenant {
+		if cfg.Schedule.Enabled {
+			fmt.Printf("⚠️ 新增或新启用定时运行的租户 %s 需要重启后生效\n", name)
+		}
+	}
 }
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+// pauseCmd 暂停通知
+var pauseCmd = &cobra.Command{
+	Use:   "pause [时长]",
+	Short: "暂停发送通知（如 notify pause 2h），期间检测到的版本在恢复后发送",
+	Long: `暂停发送通知，适用于维护窗口等不希望打扰群聊的场景。
+暂停期间定时检查照常运行，检测到的版本放入队列，恢复后的第一次运行开始时发送。
+不指定时长时一直暂停到执行 notify resume；也可以直接创建 ~/.notify/paused 文件来暂停。`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var duration time.Duration
+		if len(args) == 1 {
+			var err error
+			duration, err = time.ParseDuration(args[0])
+			if err != nil || duration <= 0 {
+				return fmt.Errorf("暂停时长无效: %s（如 30m、2h）", args[0])
+			}
+		}
+
+		if err := notifier.Pause(duration); err != nil {
+			return err
+		}
+
+		until, _ := notifier.PausedUntil()
+		fmt.Printf("✓ 通知已暂停（%s）\n", notifier.DescribePause(until))
+		return nil
+	},
+}
+
+// resumeCmd 恢复通知
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "恢复发送通知，暂停期间的版本在下次运行时发送",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, paused := notifier.PausedUntil()
+		// 暂停已到期时也删除标记文件
+		if err := notifier.Resume(); err != nil {
+			return err
+		}
+		if !paused {
+			fmt.Println("通知未处于暂停状态")
+			return nil
+		}
+		fmt.Println("✓ 通知已恢复")
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(pauseCmd)
+	RootCmd.AddCommand(resumeCmd)
+}
@@ -0,0 +1,141 @@
+// Package clock 可替换的时间来源
+//
+// 与时间相关的判断（检查的时间窗口、限流冷却、发送速率和每日限额、合并窗口、定时调度）都通过 Clock 获取当前时间和设置定时器，
+// 运行时使用 System，测试中使用 Fake 控制时间，不需要真的等待。
+package clock
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock 时间来源
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// AfterFunc 在 d 之后调用 f，与 time.AfterFunc 相同
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer AfterFunc 返回的定时器
+type Timer interface {
+	// Stop 停止定时器，f 尚未被调用时返回true
+	Stop() bool
+}
+
+// System 系统时钟
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// Sleep 按时钟等待 d，ctx 取消时提前返回 ctx 的错误
+func Sleep(ctx context.Context, c Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	done := make(chan struct{})
+	t := c.AfterFunc(d, func() { close(done) })
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		t.Stop()
+		return ctx.Err()
+	}
+}
+
+// Fake 测试用的时钟，时间只在调用 Set、Advance 时前进，到期的定时器在其中同步调用
+type Fake struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+// NewFake 创建时间为 now 的测试时钟
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now 返回当前时间
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// AfterFunc 添加定时器，d 不大于0时立即在新的 goroutine 中调用 f
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := &fakeTimer{clock: f, when: f.now.Add(d), fn: fn}
+	if d <= 0 {
+		go fn()
+		return t
+	}
+	f.timers = append(f.timers, t)
+	return t
+}
+
+// Advance 时间前进 d
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set 将时间设置为 t，按到期时间依次调用到期的定时器，时间不会倒退
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	if t.After(f.now) {
+		f.now = t
+	}
+	var due, pending []*fakeTimer
+	for _, timer := range f.timers {
+		if timer.when.After(f.now) {
+			pending = append(pending, timer)
+		} else {
+			due = append(due, timer)
+		}
+	}
+	f.timers = pending
+	f.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].when.Before(due[j].when) })
+	for _, timer := range due {
+		timer.fn()
+	}
+}
+
+// Timers 返回尚未到期的定时器数，测试中用于确认等待方已经开始等待
+func (f *Fake) Timers() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.timers)
+}
+
+type fakeTimer struct {
+	clock *Fake
+	when  time.Time
+	fn    func()
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestFake 测试假时钟按到期时间依次调用定时器，已停止的定时器不再调用
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFake(start)
+
+	var fired []string
+	f.AfterFunc(2*time.Minute, func() { fired = append(fired, "2m") })
+	f.AfterFunc(time.Minute, func() { fired = append(fired, "1m") })
+	stopped := f.AfterFunc(90*time.Second, func() { fired = append(fired, "90s") })
+	if !stopped.Stop() {
+		t.Error("未到期的定时器 Stop 应返回true")
+	}
+
+	f.Advance(30 * time.Second)
+	if len(fired) != 0 || f.Timers() != 2 {
+		t.Fatalf("未到期的定时器不应调用: %v", fired)
+	}
+
+	f.Advance(2 * time.Minute)
+	if len(fired) != 2 || fired[0] != "1m" || fired[1] != "2m" {
+		t.Errorf("定时器调用顺序为 %v，期望 [1m 2m]", fired)
+	}
+	if !f.Now().Equal(start.Add(150 * time.Second)) {
+		t.Errorf("当前时间为 %v", f.Now())
+	}
+
+	f.Set(start)
+	if !f.Now().Equal(start.Add(150 * time.Second)) {
+		t.Error("时间不应倒退")
+	}
+}
+
+// TestSleep 测试按时钟等待以及上下文取消
+func TestSleep(t *testing.T) {
+	f := NewFake(time.Now())
+
+	done := make(chan error, 1)
+	go func() { done <- Sleep(context.Background(), f, time.Hour) }()
+	for f.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	f.Advance(time.Hour)
+	if err := <-done; err != nil {
+		t.Errorf("到期后应返回nil: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { done <- Sleep(ctx, f, time.Hour) }()
+	for f.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("取消后应返回 context.Canceled，实际 %v", err)
+	}
+	if f.Timers() != 0 {
+		t.Error("取消后应停止定时器")
+	}
+}
@@ -0,0 +1,211 @@
+package crates
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/source"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// stateOwner 状态文件中的所属空间，加上来源前缀避免与GitHub上的同名仓库冲突
+const stateOwner = github.SourceCrates + ":crates.io"
+
+// checker 一次检查使用的客户端、状态和配置
+type checker struct {
+	*source.Base
+	client          *Client
+	showDescription bool
+}
+
+// Source crates.io版本来源
+type Source struct{}
+
+// Name 来源名称
+func (Source) Name() string { return "crates.io" }
+
+// Enabled 是否配置了crate
+func (Source) Enabled(cfg *config.Config) bool { return len(cfg.Crates.Packages) > 0 }
+
+// Check 检查新版本
+func (Source) Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	return CheckForNewReleases(cfg, showDescription)
+}
+
+// CheckForNewReleases 检查配置的crate是否有新版本，以及之前通知过的版本是否被撤回
+// 检查期限、时区和预发布版本的设置与GitHub仓库相同（github.check_days、timezone、include_prereleases）
+func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	names := source.FilterShard(cfg.Crates.Packages, cfg.Shard, "crate", func(name string) string { return github.SourceCrates + "/" + name })
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	base, err := source.NewBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(cfg.Crates.BaseURL, cfg.Crates.UserAgent, cfg.Network.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{
+		Base:            base,
+		client:          client,
+		showDescription: showDescription,
+	}
+
+	fmt.Printf("正在检查 %d 个crate（%s）...\n", len(names), client.baseURL)
+	var results []*github.ReleaseInfo
+	errorCount := 0
+	for _, name := range names {
+		infos, err := c.check(name)
+		if err != nil {
+			fmt.Printf("检查crate %s 失败: %v\n", name, err)
+			errorCount++
+			continue
+		}
+		for _, info := range infos {
+			if info.Event == github.EventYanked {
+				fmt.Printf("版本已撤回: %s (%s)\n", name, info.TagName)
+			} else {
+				fmt.Printf("发现新版本: %s (%s)\n", name, info.TagName)
+			}
+		}
+		results = append(results, infos...)
+	}
+
+	fmt.Printf("crates.io检查完成: 发现 %d 个新版本或撤回", len(results))
+	if errorCount > 0 {
+		fmt.Printf("，%d 个crate检查失败", errorCount)
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// check 检查一个crate
+// 之前通知过的版本被撤回时返回 EventYanked 事件，并把记录改为当前可用的最新版本；
+// 撤回后可用的版本比记录的版本更高时，同时返回新版本
+func (c *checker) check(name string) ([]*github.ReleaseInfo, error) {
+	if name == "" {
+		return nil, fmt.Errorf("包名不能为空")
+	}
+	resp, err := c.client.crate(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 选出版本号最高的未撤回版本
+	var (
+		latest    *crateVersion
+		latestVer version.Version
+	)
+	yanked := make(map[string]bool)
+	for i := range resp.Versions {
+		v := &resp.Versions[i]
+		if v.Yanked {
+			yanked[v.Num] = true
+			continue
+		}
+		parsed, err := version.Parse(v.Num)
+		if err != nil || (parsed.IsPrerelease() && !c.Config.GitHub.IncludePrereleases) {
+			continue
+		}
+		if latest == nil || version.Compare(parsed, latestVer) > 0 {
+			latest, latestVer = v, parsed
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+	previousTag := c.Store.GetLatestTag(stateOwner, name)
+
+	// 记录的版本被撤回：通知一次撤回，并把记录改为当前可用的最新版本，之后不再重复通知
+	if previousTag != "" && yanked[previousTag] {
+		results := []*github.ReleaseInfo{c.yankedEvent(resp, name, previousTag)}
+		if err := c.Store.UpdateState(stateOwner, name, latest.Num); err != nil {
+			return nil, fmt.Errorf("更新版本状态失败: %v", err)
+		}
+		// 回退到更早的版本时不作为新版本通知
+		prev, err := version.Parse(previousTag)
+		if err == nil && version.Compare(latestVer, prev) > 0 && c.notifiable(name, latest) {
+			results = append(results, c.releaseInfo(resp, name, latest, latestVer, previousTag))
+		}
+		return results, nil
+	}
+
+	if !c.notifiable(name, latest) {
+		return nil, nil
+	}
+	isNew, err := c.Store.CheckAndUpdateIfNew(stateOwner, name, latest.Num)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+	return []*github.ReleaseInfo{c.releaseInfo(resp, name, latest, latestVer, previousTag)}, nil
+}
+
+// notifiable 版本是否在检查期限内且没有被 notify ignore 标记
+func (c *checker) notifiable(name string, v *crateVersion) bool {
+	if v.CreatedAt.Before(c.Since) {
+		return false
+	}
+	// 通过 notify ignore 标记的版本不通知，也不记录状态
+	if entry, ok := c.Ignored.Match(github.SourceCrates, name, v.Num); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return false
+	}
+	return true
+}
+
+// releaseInfo 新版本的通知
+func (c *checker) releaseInfo(resp *crateResponse, name string, latest *crateVersion, latestVer version.Version, previousTag string) *github.ReleaseInfo {
+	info := &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Source:      github.SourceCrates,
+		Owner:       github.SourceCrates,
+		Repository:  crateName(resp, name),
+		TagName:     latest.Num,
+		Name:        latest.Num,
+		HTMLURL:     c.versionURL(name, latest.Num),
+		PublishedAt: latest.CreatedAt.In(c.Loc),
+		Prerelease:  latestVer.IsPrerelease(),
+		PreviousTag: previousTag,
+	}
+	if c.showDescription {
+		info.Description = resp.Crate.Description
+	}
+	return info
+}
+
+// yankedEvent 之前通知过的版本被撤回的事件
+func (c *checker) yankedEvent(resp *crateResponse, name, tag string) *github.ReleaseInfo {
+	return &github.ReleaseInfo{
+		Event:       github.EventYanked,
+		Source:      github.SourceCrates,
+		Owner:       github.SourceCrates,
+		Repository:  crateName(resp, name),
+		TagName:     tag,
+		Name:        "已撤回",
+		HTMLURL:     c.versionURL(name, tag),
+		PublishedAt: time.Now().In(c.Loc),
+	}
+}
+
+// versionURL 版本的页面地址
+func (c *checker) versionURL(name, v string) string {
+	return fmt.Sprintf("%s/crates/%s/%s", c.client.baseURL, url.PathEscape(name), url.PathEscape(v))
+}
+
+// crateName 返回API中的包名，与配置中的写法（- 与 _ 可以互换）可能不同
+func crateName(resp *crateResponse, fallback string) string {
+	if resp.Crate.Name != "" {
+		return resp.Crate.Name
+	}
+	return fallback
+}
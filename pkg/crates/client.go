@@ -0,0 +1,118 @@
+// Package crates 检查 crates.io 上Rust包的新版本
+//
+// 通过 crates.io API（/api/v1/crates/<名称>）读取包的全部版本，跳过已撤回（yanked）的版本，
+// 之前通知过的版本被撤回时发送撤回通知。
+package crates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultBaseURL crates.io 的地址
+const DefaultBaseURL = "https://crates.io"
+
+// DefaultUserAgent crates.io 要求请求带有可以识别调用方的User-Agent
+const DefaultUserAgent = "notify (https://github.com/orange-juzipi/notify)"
+
+// requestInterval crates.io 的爬虫策略要求每秒最多1个请求（测试中修改）
+var requestInterval = time.Second
+
+// timeout 每个请求的超时时间
+const timeout = 15 * time.Second
+
+// crateResponse API返回的包信息中用到的字段
+type crateResponse struct {
+	Crate struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"crate"`
+	Versions []crateVersion `json:"versions"`
+}
+
+// crateVersion 包的一个版本
+type crateVersion struct {
+	Num       string    `json:"num"`
+	CreatedAt time.Time `json:"created_at"`
+	Yanked    bool      `json:"yanked"`
+}
+
+// Client crates.io API客户端，请求之间至少间隔 interval
+type Client struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+	interval  time.Duration
+	last      time.Time
+}
+
+// NewClient 创建crates.io客户端，baseURL 为空时使用 crates.io，userAgent 为空时使用 DefaultUserAgent
+func NewClient(baseURL, userAgent, localAddr string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("无效的crates.io地址 %s: %v", baseURL, err)
+	}
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: timeout, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &Client{
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		userAgent: userAgent,
+		client:    client,
+		interval:  requestInterval,
+	}, nil
+}
+
+// crate 读取包的信息和全部版本
+func (c *Client) crate(name string) (*crateResponse, error) {
+	if wait := c.interval - time.Since(c.last); !c.last.IsZero() && wait > 0 {
+		time.Sleep(wait)
+	}
+	defer func() { c.last = time.Now() }()
+
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/api/v1/crates/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求crates.io失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("包不存在")
+	case http.StatusForbidden:
+		// 没有User-Agent或被识别为不合规的爬虫时返回403
+		return nil, fmt.Errorf("crates.io拒绝了请求，请检查 crates.user_agent 是否包含联系方式")
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("触发crates.io速率限制，请稍后重试")
+	default:
+		return nil, fmt.Errorf("crates.io返回状态码 %d", resp.StatusCode)
+	}
+
+	var body crateResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(&body); err != nil {
+		return nil, fmt.Errorf("解析crates.io响应失败: %v", err)
+	}
+	return &body, nil
+}
@@ -0,0 +1,132 @@
+package crates
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// fakeCrates 模拟crates.io API，versions 为 serde 的版本号和是否已撤回，可在测试中修改
+type fakeCrates struct {
+	mu       sync.Mutex
+	versions map[string]bool
+	agents   []string
+}
+
+func newFakeCrates(t *testing.T, created time.Time, versions map[string]bool) (*httptest.Server, *fakeCrates) {
+	t.Helper()
+	requestInterval = 0
+
+	f := &fakeCrates{versions: versions}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/crates/serde", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.agents = append(f.agents, r.Header.Get("User-Agent"))
+		fmt.Fprint(w, `{"crate": {"name": "serde", "description": "A serialization framework"}, "versions": [`)
+		first := true
+		for num, yanked := range f.versions {
+			if !first {
+				fmt.Fprint(w, ",")
+			}
+			first = false
+			fmt.Fprintf(w, `{"num": %q, "created_at": %q, "yanked": %v}`, num, created.Format(time.RFC3339Nano), yanked)
+		}
+		fmt.Fprint(w, `]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, f
+}
+
+func testConfig(t *testing.T, serverURL string) *config.Config {
+	return &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		Crates: config.CratesConfig{BaseURL: serverURL, Packages: []string{"serde", "missing"}},
+		State:  config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+}
+
+// TestCheckForNewReleases 测试跳过已撤回和预发布的版本、状态去重，以及请求带有User-Agent
+func TestCheckForNewReleases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, fake := newFakeCrates(t, time.Now().Add(-time.Hour), map[string]bool{
+		"1.0.200": false, "1.0.201": true, "1.1.0-alpha.1": false,
+	})
+	cfg := testConfig(t, server.URL)
+
+	releases, err := CheckForNewReleases(cfg, true)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("发现 %d 个新版本，期望 1 个", len(releases))
+	}
+	r := releases[0]
+	if r.Event != github.EventRelease || r.Source != github.SourceCrates || r.Repository != "serde" || r.TagName != "1.0.200" {
+		t.Errorf("版本为 %+v", r)
+	}
+	if r.HTMLURL != server.URL+"/crates/serde/1.0.200" || r.Description != "A serialization framework" {
+		t.Errorf("链接为 %s，说明为 %q", r.HTMLURL, r.Description)
+	}
+	if fake.agents[0] != DefaultUserAgent {
+		t.Errorf("User-Agent 为 %q", fake.agents[0])
+	}
+
+	releases, err = CheckForNewReleases(cfg, true)
+	if err != nil || len(releases) != 0 {
+		t.Errorf("再次检查发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+}
+
+// TestCheckForNewReleases_Yanked 已通知的版本被撤回时通知一次，回退到更早的版本不作为新版本通知
+func TestCheckForNewReleases_Yanked(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, fake := newFakeCrates(t, time.Now().Add(-time.Hour), map[string]bool{"1.0.0": false, "1.0.1": false})
+	cfg := testConfig(t, server.URL)
+
+	if releases, err := CheckForNewReleases(cfg, false); err != nil || len(releases) != 1 || releases[0].TagName != "1.0.1" {
+		t.Fatalf("首次检查结果为 %+v（%v）", releases, err)
+	}
+
+	fake.mu.Lock()
+	fake.versions["1.0.1"] = true
+	fake.mu.Unlock()
+	releases, err := CheckForNewReleases(cfg, false)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 1 || releases[0].Event != github.EventYanked || releases[0].TagName != "1.0.1" {
+		t.Fatalf("撤回后的检查结果为 %+v，期望 1.0.1 的撤回通知", releases)
+	}
+	if releases, _ := CheckForNewReleases(cfg, false); len(releases) != 0 {
+		t.Errorf("撤回只应通知一次，再次检查发现 %+v", releases)
+	}
+
+	// 撤回后发布了修复版本：新版本的上一个版本为回退后的记录
+	fake.mu.Lock()
+	fake.versions["1.0.2"] = false
+	fake.mu.Unlock()
+	releases, err = CheckForNewReleases(cfg, false)
+	if err != nil || len(releases) != 1 || releases[0].TagName != "1.0.2" || releases[0].PreviousTag != "1.0.0" {
+		t.Errorf("发布修复版本后的检查结果为 %+v（%v）", releases, err)
+	}
+}
+
+// TestCheckForNewReleases_CheckDays 发布时间早于检查期限的版本不通知
+func TestCheckForNewReleases_CheckDays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, _ := newFakeCrates(t, time.Now().AddDate(0, 0, -30), map[string]bool{"1.0.0": false})
+
+	releases, err := CheckForNewReleases(testConfig(t, server.URL), false)
+	if err != nil || len(releases) != 0 {
+		t.Errorf("发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+}
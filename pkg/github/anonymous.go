@@ -0,0 +1,66 @@
+package github
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// anonymousConcurrency 匿名模式下的并发请求数
+// 未认证请求每小时只有60次配额，并发过高很容易在中途触发限流
+const anonymousConcurrency = 2
+
+// anonymousConfig 返回匿名模式下使用的配置副本：关闭所有仓库发现功能，只检查手动配置的仓库
+// 匿名模式的说明输出到 out
+func anonymousConfig(cfg *config.Config, out io.Writer) *config.Config {
+	var disabled []string
+	gh := cfg.GitHub
+	if gh.AutoWatchUser {
+		gh.AutoWatchUser = false
+		disabled = append(disabled, "auto_watch_user")
+	}
+	if gh.WatchStarred {
+		gh.WatchStarred = false
+		disabled = append(disabled, "watch_starred")
+	}
+	if len(gh.WatchOrgs) > 0 {
+		gh.WatchOrgs = nil
+		disabled = append(disabled, "watch_orgs")
+	}
+	if len(gh.Manifests) > 0 {
+		gh.Manifests = nil
+		disabled = append(disabled, "manifests")
+	}
+	if gh.MarkContributed {
+		gh.MarkContributed = false
+		disabled = append(disabled, "mark_contributed")
+	}
+	if gh.WatchLabels.AllRepos {
+		gh.WatchLabels.AllRepos = false
+		disabled = append(disabled, "watch_labels.all_repos")
+	}
+
+	fmt.Fprintln(out, "⚠️ 未配置GitHub Token，以匿名模式运行：")
+	fmt.Fprintln(out, "- 未认证请求每小时只有60次配额，每个仓库的检查消耗1次请求")
+	fmt.Fprintln(out, "- 仅检查配置文件中手动指定的仓库，并降低并发数")
+	if len(disabled) > 0 {
+		fmt.Fprintf(out, "- 以下需要Token的功能已关闭: %v\n", disabled)
+	}
+	fmt.Fprintln(out, "- 创建Token后设置 github.token 或环境变量 GITHUB_TOKEN 即可解除限制")
+
+	anon := *cfg
+	anon.GitHub = gh
+	return &anon
+}
+
+// limitToQuota 匿名模式下仓库数量超过剩余配额时，只检查配额允许的部分
+func limitToQuota(repos []config.RepoConfig, remaining int) []config.RepoConfig {
+	if remaining < 0 || len(repos) <= remaining {
+		return repos
+	}
+
+	fmt.Printf("⚠️ 匿名模式剩余配额 %d 次，不足以检查全部 %d 个仓库，本次只检查前 %d 个\n",
+		remaining, len(repos), remaining)
+	return repos[:remaining]
+}
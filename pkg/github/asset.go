@@ -0,0 +1,30 @@
+package github
+
+import (
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+)
+
+// assetPatternFor 返回仓库配置的附件匹配规则，未配置时返回空字符串
+func assetPatternFor(cfg *config.Config, owner, repo string) string {
+	for _, r := range cfg.GitHub.Repos {
+		if strings.EqualFold(r.Owner, owner) && strings.EqualFold(r.Name, repo) {
+			return r.AssetPattern
+		}
+	}
+	return ""
+}
+
+// matchAssets 返回文件名匹配glob规则（如 *linux_amd64.tar.gz）的release附件
+func matchAssets(assets []*github.ReleaseAsset, pattern string) []string {
+	var names []string
+	for _, asset := range assets {
+		if ok, _ := path.Match(pattern, asset.GetName()); ok {
+			names = append(names, asset.GetName())
+		}
+	}
+	return names
+}
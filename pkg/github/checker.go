@@ -0,0 +1,76 @@
+package github
+
+import (
+	"context"
+	"sync"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// Checker 在多次检查之间复用GitHub API客户端和它的连接池，以及Token对应的用户名
+// Token、出口地址或请求设置变化（如重新加载配置）后重新创建；状态文件和忽略列表每次检查时重新加载，
+// 手动修改状态文件后下次检查即生效。同一时间只执行一次检查，可以并发调用
+type Checker struct {
+	mu        sync.Mutex
+	token     string
+	localAddr string
+	httpCfg   config.GitHubHTTPConfig
+	client    *github.Client
+	ctx       context.Context
+	// login Token对应的用户名，获取成功后在之后的检查中复用
+	login string
+	// pendingWatch 上次检查得到的、尚未保存的监控列表快照
+	pendingWatch *pendingWatch
+}
+
+// NewChecker 创建检查器，API客户端在第一次检查时创建
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// newClient 创建本次检查使用的客户端，token、localAddr 和 httpCfg 与上次相同时复用API客户端
+func (k *Checker) newClient(token, storePath string, storeOpts util.StoreOptions, localAddr string, httpCfg config.GitHubHTTPConfig) (*Client, error) {
+	if k.client == nil || token != k.token || localAddr != k.localAddr || httpCfg != k.httpCfg {
+		client, ctx, err := newAPIClient(token, localAddr, httpCfg)
+		if err != nil {
+			return nil, err
+		}
+		k.client, k.ctx = client, ctx
+		k.token, k.localAddr, k.httpCfg = token, localAddr, httpCfg
+		k.login = ""
+	}
+
+	client, err := newClientWith(k.client, k.ctx, storePath, storeOpts)
+	if err != nil {
+		return nil, err
+	}
+	client.login = k.login
+	return client, nil
+}
+
+// CommitWatchList 保存上次检查得到的监控列表快照，应在监控列表变化的通知发送成功后调用
+// 未调用时快照保持不变，下次检查重新对比并再次通知列表变化
+func (k *Checker) CommitWatchList() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.pendingWatch == nil {
+		return nil
+	}
+	if err := saveWatchSnapshot(k.pendingWatch.path, k.pendingWatch.snapshot); err != nil {
+		return err
+	}
+	k.pendingWatch = nil
+	return nil
+}
+
+// keepLogin 保存检查中获取到的用户名
+func (k *Checker) keepLogin(client *Client) {
+	client.loginMu.Lock()
+	defer client.loginMu.Unlock()
+	if client.login != "" {
+		k.login = client.login
+	}
+}
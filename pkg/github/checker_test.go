@@ -0,0 +1,47 @@
+package github
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// TestCheckerReusesClient 测试相同Token时复用API客户端和用户名，Token变化后重新创建
+func TestCheckerReusesClient(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "state.json")
+	k := NewChecker()
+
+	first, err := k.newClient("token-a", storePath, util.StoreOptions{}, "", config.GitHubHTTPConfig{})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	first.login = "octocat"
+	k.keepLogin(first)
+
+	second, err := k.newClient("token-a", storePath, util.StoreOptions{}, "", config.GitHubHTTPConfig{})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if second.client != first.client {
+		t.Error("相同Token应复用API客户端")
+	}
+	if second.store == first.store {
+		t.Error("每次检查应重新加载状态文件")
+	}
+	if second.login != "octocat" {
+		t.Errorf("用户名为 %q，期望复用 octocat", second.login)
+	}
+
+	third, err := k.newClient("token-b", storePath, util.StoreOptions{}, "", config.GitHubHTTPConfig{})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if third.client == first.client {
+		t.Error("Token变化后应重新创建API客户端")
+	}
+	if third.login != "" {
+		t.Errorf("Token变化后用户名应清空，实际为 %q", third.login)
+	}
+}
@@ -3,6 +3,8 @@ package github
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -11,18 +13,67 @@ import (
 	"github.com/google/go-github/v71/github"
 	"github.com/orange-juzipi/notify/config"
 	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
 	"golang.org/x/oauth2"
 )
 
 // ReleaseInfo 包含版本发布信息
 type ReleaseInfo struct {
-	Owner       string
-	Repository  string
-	TagName     string
-	Name        string
-	Description string
-	HTMLURL     string
-	PublishedAt time.Time
+	// Event 事件类型，见 EventRelease 等常量
+	Event string `json:"event"`
+	// Source 版本来源，为空表示GitHub，见 SourceGitLab 等常量
+	Source string `json:"source,omitempty"`
+	// Key 由来源、仓库和标签计算的稳定标识，见 EventKey，发送前由通知管理器设置
+	Key         string    `json:"key,omitempty"`
+	Owner       string    `json:"owner"`
+	Repository  string    `json:"repository"`
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	// SignatureChecked 是否进行了签名检测
+	SignatureChecked bool `json:"signature_checked,omitempty"`
+	// Signed 附件中是否包含签名或来源证明文件
+	Signed bool `json:"signed,omitempty"`
+	// SignatureAssets 检测到的签名/证明文件名
+	SignatureAssets []string `json:"signature_assets,omitempty"`
+	// PinnedVersion 依赖清单或配置中锁定的版本
+	PinnedVersion string `json:"pinned_version,omitempty"`
+	// AffectsPinned 锁定版本是否落后于新版本
+	AffectsPinned bool `json:"affects_pinned,omitempty"`
+	// VersionGap 锁定版本与新版本的差距描述，如 "落后 3 个次版本"
+	VersionGap string `json:"version_gap,omitempty"`
+	// NotesGenerated 发布说明为空，Description 是根据 generate-notes 接口或提交对比生成的（需要开启 generate_notes）
+	NotesGenerated bool `json:"notes_generated,omitempty"`
+	// Highlights 发布说明中命中的高亮关键字
+	Highlights []string `json:"highlights,omitempty"`
+	// NotesDiff 发布说明修改的差异摘要，仅用于 EventNotesUpdated
+	NotesDiff string `json:"notes_diff,omitempty"`
+	// Prerelease 是否为预发布版本（需要开启 include_prereleases）
+	Prerelease bool `json:"prerelease,omitempty"`
+	// PromotedFrom 转为正式版之前通知过的预发布版本，仅用于 EventPromoted
+	PromotedFrom string `json:"promoted_from,omitempty"`
+	// PreviousTag 之前通知过的版本，首次发现该仓库时为空
+	PreviousTag string `json:"previous_tag,omitempty"`
+	// MatchedAssets 匹配仓库附件规则的附件名
+	MatchedAssets []string `json:"matched_assets,omitempty"`
+	// WatchSource 仓库在监控列表中的来源，仅用于 EventWatchAdded / EventWatchRemoved
+	WatchSource string `json:"watch_source,omitempty"`
+	// WatchLabel 监控来源的展示名称（见 WatchSourceLabel），仅用于 EventWatchAdded / EventWatchRemoved，这两种事件没有 TagName
+	WatchLabel string `json:"watch_label,omitempty"`
+	// DeprecationReason 弃用原因（归档、README中的弃用说明或 npm deprecate 的信息），仅用于 EventDeprecated
+	DeprecationReason string `json:"deprecation_reason,omitempty"`
+	// Contributed 授权用户是否向该仓库提交过代码（需要开启 mark_contributed）
+	Contributed bool `json:"contributed,omitempty"`
+	// Stars 仓库的star数（需要开启 scoring 并设置 stars_weight）
+	Stars int `json:"stars,omitempty"`
+	// Score 仓库的重要性分数（需要开启 scoring），分数高的版本优先发送
+	Score float64 `json:"score,omitempty"`
+	// ShortURL 版本页面的短链接（需要配置 shortener）
+	ShortURL string `json:"short_url,omitempty"`
+	// ShortCompareURL 与上一个版本对比页面的短链接（需要配置 shortener）
+	ShortCompareURL string `json:"short_compare_url,omitempty"`
 }
 
 // Client GitHub客户端
@@ -30,31 +81,136 @@ type Client struct {
 	client *github.Client
 	ctx    context.Context
 	store  *util.StateStore
+	// issues 带标签Issue的状态，键为 owner/name#编号，与版本状态分开保存
+	issues *util.StateStore
+	// usage 本次运行的API请求统计
+	usage apiUsage
+	// discoveryErrors 本次仓库发现中失败的来源数，大于0时监控列表不完整
+	discoveryErrors int
+	// pendingWatch 本次检查得到的监控列表快照，通知发送成功后由 Checker.CommitWatchList 保存
+	pendingWatch *pendingWatch
+	// out 仓库发现过程中进度信息的输出位置，为nil时输出到标准输出
+	out io.Writer
+	// ignored 通过 notify ignore 标记的版本，为nil时不忽略
+	ignored *IgnoreList
+	// renames 检测到的仓库重命名或转移，键为旧的 owner/name（小写）
+	renames     map[string]renameRecord
+	renamesPath string
+	renameMu    sync.Mutex
+	// contributed 授权用户是否向仓库提交过代码，键为 owner/name（小写）
+	contributed map[string]bool
+	contribMu   sync.Mutex
+	// login Token对应的用户名，首次使用时获取
+	login    string
+	loginErr error
+	loginMu  sync.Mutex
+	// stars 仓库的star数，键为 owner/name（小写）
+	stars   map[string]int
+	starsMu sync.Mutex
+	// feed 通过 releases.atom 订阅检查版本，启用 feed_fallback 时创建
+	feed *feedReader
+	// clock 计算检查时间窗口使用的时钟，为nil时使用系统时钟
+	clock clock.Clock
 }
 
 // NewClient 创建新的GitHub客户端
-func NewClient(token string, storePath string) (*Client, error) {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	tc := oauth2.NewClient(ctx, ts)
+func NewClient(token string, storePath string, storeOpts util.StoreOptions, localAddr string, httpCfg config.GitHubHTTPConfig) (*Client, error) {
+	client, ctx, err := newAPIClient(token, localAddr, httpCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newClientWith(client, ctx, storePath, storeOpts)
+}
 
-	store, err := util.NewStateStore(storePath)
+// newClientWith 使用已创建的API客户端创建GitHub客户端，状态文件每次重新加载
+func newClientWith(client *github.Client, ctx context.Context, storePath string, storeOpts util.StoreOptions) (*Client, error) {
+	store, err := util.OpenStateStore(storePath, storeOpts)
 	if err != nil {
 		return nil, fmt.Errorf("创建状态存储失败: %v", err)
 	}
+	issues, err := util.OpenStateStore(issueStatePath(storePath), storeOpts)
+	if err != nil {
+		return nil, fmt.Errorf("创建Issue状态存储失败: %v", err)
+	}
+	// 旧版本把Issue状态记录在版本状态中，移到单独的文件
+	if moved, err := store.MoveStates(issues, isIssueState); err != nil {
+		fmt.Printf("警告: 迁移Issue状态失败: %v\n", err)
+	} else if moved > 0 {
+		fmt.Printf("已将 %d 个Issue状态迁移到 %s\n", moved, issueStatePath(storePath))
+	}
 
 	return &Client{
-		client: github.NewClient(tc),
+		client: client,
 		ctx:    ctx,
 		store:  store,
+		issues: issues,
+		clock:  clock.System,
 	}, nil
 }
 
+// now 返回当前时间
+func (c *Client) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
+
+// newAPIClient 创建带认证的GitHub API客户端
+func newAPIClient(token string, localAddr string, httpCfg config.GitHubHTTPConfig) (*github.Client, context.Context, error) {
+	ctx := context.Background()
+
+	// 按配置绑定出口地址，oauth2会使用上下文中的HTTP客户端作为底层传输
+	baseClient, err := util.NewHTTPClient(util.HTTPOptions{LocalAddr: localAddr})
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	if httpCfg.Timeout == "" {
+		httpCfg.Timeout = config.DefaultGitHubTimeout
+	}
+	timeout, err := time.ParseDuration(httpCfg.Timeout)
+	if err != nil {
+		return nil, nil, fmt.Errorf("github.http.timeout 取值无效: %v", err)
+	}
+	// 超时和重试放在认证之下，每次重试都会重新带上Token
+	baseClient.Transport = &retryTransport{
+		base:       baseClient.Transport,
+		timeout:    timeout,
+		maxRetries: httpCfg.MaxRetries,
+		backoff:    retryBackoff,
+		clock:      clock.System,
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, baseClient)
+
+	// 未配置Token时使用匿名请求
+	var client *github.Client
+	if token == "" {
+		client = github.NewClient(baseClient)
+	} else {
+		ts := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: token},
+		)
+		client = github.NewClient(oauth2.NewClient(ctx, ts))
+	}
+	if httpCfg.UserAgent != "" {
+		client.UserAgent = httpCfg.UserAgent
+	}
+	return client, ctx, nil
+}
+
 // GetLatestRelease 获取仓库最新的Release
 func (c *Client) GetLatestRelease(owner, repo string, showDescription bool, checkDays int, cfg *config.Config) (*ReleaseInfo, error) {
-	release, resp, err := c.client.Repositories.GetLatestRelease(c.ctx, owner, repo)
+	c.usage.add(usageChecks)
+	var (
+		release *github.RepositoryRelease
+		resp    *github.Response
+		err     error
+	)
+	if cfg.GitHub.IncludePrereleases {
+		release, resp, err = c.latestReleaseIncludingPrereleases(owner, repo)
+	} else {
+		release, resp, err = c.client.Repositories.GetLatestRelease(c.ctx, owner, repo)
+	}
 	if err != nil {
 		// 检查是否是404错误（没有release）
 		if resp != nil && resp.StatusCode == 404 {
@@ -63,6 +219,15 @@ func (c *Client) GetLatestRelease(owner, repo string, showDescription bool, chec
 		}
 		return nil, fmt.Errorf("获取最新版本失败: %v", err)
 	}
+	if release == nil {
+		return nil, nil
+	}
+
+	// 仓库已重命名或转移时迁移状态，之后按新名称记录和通知
+	if newOwner, newRepo, ok := renamedTo(owner, repo, release.GetHTMLURL()); ok {
+		c.recordRename(owner, repo, newOwner, newRepo)
+		owner, repo = newOwner, newRepo
+	}
 
 	tagName := release.GetTagName()
 	publishedTime := release.GetPublishedAt().Time
@@ -74,13 +239,44 @@ func (c *Client) GetLatestRelease(owner, repo string, showDescription bool, chec
 		loc = time.UTC
 		fmt.Printf("警告: 加载时区 %s 失败，使用UTC: %v\n", cfg.GitHub.Timezone, err)
 	}
-	checkPeriodAgo := time.Now().In(loc).AddDate(0, 0, -checkDays)
+	checkPeriodAgo := c.now().In(loc).AddDate(0, 0, -checkDays)
 
 	if publishedTime.Before(checkPeriodAgo) {
 		// 如果发布时间早于检查期限，则忽略这个版本
 		return nil, nil
 	}
 
+	// 通过 notify ignore 标记的版本不通知，也不记录状态，忽略到期后仍在检查范围内时照常通知
+	if entry, ok := c.ignored.Match(owner, repo, tagName); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+
+	// 配置了附件规则时，等到匹配的附件上传后再通知（在此之前不记录状态）
+	var matchedAssets []string
+	if pattern := assetPatternFor(cfg, owner, repo); pattern != "" {
+		matchedAssets = matchAssets(release.Assets, pattern)
+		if len(matchedAssets) == 0 {
+			fmt.Printf("%s/%s 的 %s 尚未上传匹配 %s 的附件，暂不通知\n", owner, repo, tagName, pattern)
+			return nil, nil
+		}
+	}
+
+	// 记录之前通知过的版本，用于判断预发布版本是否转为正式版
+	previousTag := c.store.GetLatestTag(owner, repo)
+
+	// 启用编辑跟踪时，同时记录发布说明以便发现修改
+	if cfg.GitHub.TrackEdits {
+		releaseInfo, err := c.checkReleaseWithNotes(owner, repo, release, previousTag, showDescription, cfg, loc)
+		if releaseInfo != nil && releaseInfo.Event != EventNotesUpdated {
+			releaseInfo = c.reconcileRelease(cfg, releaseInfo)
+		}
+		if releaseInfo != nil {
+			releaseInfo.MatchedAssets = matchedAssets
+		}
+		return releaseInfo, err
+	}
+
 	// 使用原子性方法检查并更新状态（包括保存到文件），避免并发竞态条件
 	// CheckAndUpdateIfNew 会在内部完成状态检查、内存更新和文件保存的原子操作
 	isNew, err := c.store.CheckAndUpdateIfNew(owner, repo, tagName)
@@ -96,35 +292,119 @@ func (c *Client) GetLatestRelease(owner, repo string, showDescription bool, chec
 	}
 
 	// 是新版本，状态已经在 CheckAndUpdateIfNew 中保存
+	releaseInfo := buildReleaseInfo(owner, repo, release, showDescription, cfg, loc)
+	releaseInfo.MatchedAssets = matchedAssets
+	releaseInfo.PreviousTag = previousTag
+	markPromotion(releaseInfo, previousTag)
+	return c.reconcileRelease(cfg, releaseInfo), nil
+}
+
+// checkReleaseWithNotes 检查新版本，并在已通知版本的发布说明大幅修改时返回更新提醒
+func (c *Client) checkReleaseWithNotes(owner, repo string, release *github.RepositoryRelease, previousTag string, showDescription bool, cfg *config.Config, loc *time.Location) (*ReleaseInfo, error) {
+	isNew, previousBody, err := c.store.CheckAndUpdateRelease(owner, repo, release.GetTagName(), release.GetBody())
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+
+	if isNew {
+		releaseInfo := buildReleaseInfo(owner, repo, release, showDescription, cfg, loc)
+		releaseInfo.PreviousTag = previousTag
+		markPromotion(releaseInfo, previousTag)
+		return releaseInfo, nil
+	}
+
+	// 之前没有记录发布说明（如旧版本的状态文件），只记录不提醒
+	if previousBody == "" {
+		return nil, nil
+	}
+
+	ratio, diff := notesChange(previousBody, release.GetBody())
+	if ratio < cfg.GitHub.EditThreshold {
+		return nil, nil
+	}
 
+	releaseInfo := buildReleaseInfo(owner, repo, release, showDescription, cfg, loc)
+	releaseInfo.Event = EventNotesUpdated
+	releaseInfo.NotesDiff = diff
+	return releaseInfo, nil
+}
+
+// buildReleaseInfo 根据GitHub返回的release构建版本发布信息
+func buildReleaseInfo(owner, repo string, release *github.RepositoryRelease, showDescription bool, cfg *config.Config, loc *time.Location) *ReleaseInfo {
 	releaseInfo := &ReleaseInfo{
+		Event:       EventRelease,
 		Owner:       owner,
 		Repository:  repo,
-		TagName:     tagName,
+		TagName:     release.GetTagName(),
 		Name:        release.GetName(),
 		HTMLURL:     release.GetHTMLURL(),
 		PublishedAt: release.GetPublishedAt().Time.In(loc),
+		Prerelease:  release.GetPrerelease(),
+	}
+
+	// 检测附件中是否包含签名或来源证明文件
+	if cfg.GitHub.DetectSignatures {
+		releaseInfo.SignatureChecked = true
+		releaseInfo.SignatureAssets = findSignatureAssets(release.Assets)
+		releaseInfo.Signed = len(releaseInfo.SignatureAssets) > 0
 	}
 
+	// 检查发布说明中是否包含需要高亮的关键字（不受showDescription影响）
+	releaseInfo.Highlights = FindHighlights(release.GetBody(), cfg.Highlight.Keywords)
+
 	// 根据showDescription参数决定是否包含描述信息
 	if showDescription {
 		releaseInfo.Description = release.GetBody()
 	}
 
-	return releaseInfo, nil
+	return releaseInfo
+}
+
+// CheckForNewReleases 检查所有配置的仓库是否有新版本，同时返回本次检查的统计
+// 监控列表快照在检查完成后直接保存，需要在通知发送成功后保存时使用 Checker
+func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseInfo, CheckStats, error) {
+	k := NewChecker()
+	results, stats, err := k.Check(cfg, showDescription)
+	if err == nil {
+		if err := k.CommitWatchList(); err != nil {
+			fmt.Printf("保存监控列表快照失败: %v\n", err)
+		}
+	}
+	return results, stats, err
 }
 
-// CheckForNewReleases 检查所有配置的仓库是否有新版本
-func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseInfo, error) {
-	client, err := NewClient(cfg.GitHub.Token, "")
+// Check 与 CheckForNewReleases 相同，复用上次检查的API客户端
+func (k *Checker) Check(cfg *config.Config, showDescription bool) ([]*ReleaseInfo, CheckStats, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	anonymous := cfg.GitHub.Token == ""
+	if anonymous {
+		cfg = anonymousConfig(cfg, os.Stdout)
+	}
+
+	storePath, err := util.ResolvePath(cfg.State.Path, "state.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, CheckStats{}, err
+	}
+
+	client, err := k.newClient(cfg.GitHub.Token, storePath, StoreOptions(cfg), cfg.Network.LocalAddr, cfg.GitHub.HTTP)
+	if err != nil {
+		return nil, CheckStats{}, fmt.Errorf("创建GitHub客户端失败: %v", err)
+	}
+	defer k.keepLogin(client)
+
+	client.ignored, err = LoadIgnoreList(cfg.TenantSuffix())
 	if err != nil {
-		return nil, fmt.Errorf("创建GitHub客户端失败: %v", err)
+		return nil, CheckStats{}, err
 	}
 
 	// 尝试获取速率限制信息
+	startRemaining := -1
 	rl, resp, err := client.client.RateLimit.Get(client.ctx)
 	if err == nil && resp != nil && rl != nil && rl.Core != nil {
 		remaining := rl.Core.Remaining
+		startRemaining = remaining
 		resetTime := rl.Core.Reset.Time
 
 		fmt.Printf("GitHub API 速率限制状态: %d/%d，重置时间：%s\n",
@@ -132,11 +412,25 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 			resetTime.Format(time.DateTime))
 
 		// 如果剩余请求数很少，提醒用户
-		if remaining < 50 {
+		// 匿名模式的配额上限只有60次，已在前面单独提示
+		if remaining < 50 && !anonymous {
 			fmt.Printf("⚠️ 警告: GitHub API 请求配额不足，仅剩 %d 次请求\n", remaining)
 		}
 	}
 
+	// 订阅回退：未配置Token或配额已用完时，通过公开仓库的 releases.atom 订阅检查版本
+	var useFeed atomic.Bool
+	if cfg.GitHub.FeedFallback {
+		client.feed, err = newFeedReader(cfg.Network.LocalAddr)
+		if err != nil {
+			return nil, CheckStats{}, err
+		}
+		if anonymous || startRemaining == 0 {
+			useFeed.Store(true)
+			fmt.Println("已启用订阅回退：通过 releases.atom 订阅检查公开仓库，不消耗API配额（不支持附件规则和编辑跟踪）")
+		}
+	}
+
 	// 显示仅检查最近N天的提示
 	loc, err := time.LoadLocation(cfg.GitHub.Timezone)
 	if err != nil {
@@ -144,127 +438,32 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 		loc = time.UTC
 		fmt.Printf("警告: 加载时区 %s 失败，使用UTC: %v\n", cfg.GitHub.Timezone, err)
 	}
-	checkPeriodAgo := time.Now().In(loc).AddDate(0, 0, -cfg.GitHub.CheckDays)
+	checkPeriodAgo := client.now().In(loc).AddDate(0, 0, -cfg.GitHub.CheckDays)
 	fmt.Printf("仅检查最近%d天（%s 之后）发布的版本\n", cfg.GitHub.CheckDays, checkPeriodAgo.Format("2006-01-02"))
 
-	// 使用map去重，避免重复监控同一个仓库
-	repoMap := make(map[string]config.RepoConfig)
-
-	// 如果配置了手动指定的仓库，添加到待检查列表
-	if len(cfg.GitHub.Repos) > 0 {
-		for _, repo := range cfg.GitHub.Repos {
-			key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-			repoMap[key] = repo
-		}
-	}
-
-	// 如果启用了自动监控用户仓库
-	if cfg.GitHub.AutoWatchUser {
-		fmt.Println("正在获取用户仓库列表...")
-		userRepos, err := client.getUserRepositories(cfg.GitHub.OnlyWithReleases)
-		if err != nil {
-			fmt.Printf("获取用户仓库列表失败: %v\n", err)
-		} else {
-			fmt.Printf("找到 %d 个用户仓库\n", len(userRepos))
-			for _, repo := range userRepos {
-				key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-				repoMap[key] = repo
-			}
-		}
+	repoConfigs, sources, err := client.discoverRepos(cfg)
+	if err != nil {
+		return nil, CheckStats{}, err
 	}
 
-	// 如果启用了监控star的仓库
-	if cfg.GitHub.WatchStarred {
-		fmt.Println("正在获取用户已star的仓库列表...")
-		starredRepos, err := client.getUserStarredRepositories(cfg.GitHub.OnlyWithReleases)
-		if err != nil {
-			fmt.Printf("获取用户已star的仓库列表失败: %v\n", err)
-		} else {
-			fmt.Printf("找到 %d 个已star的仓库\n", len(starredRepos))
-			for _, repo := range starredRepos {
-				key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-				repoMap[key] = repo
-			}
-		}
+	// 对比上次的监控列表
+	var watchChanges []*ReleaseInfo
+	if cfg.GitHub.NotifyWatchListChanges && !anonymous {
+		watchChanges = client.checkWatchListChanges(repoConfigs, sources, cfg, loc)
 	}
+	k.pendingWatch = client.pendingWatch
 
-	// 如果配置了需要监控的组织
-	if len(cfg.GitHub.WatchOrgs) > 0 {
-		for _, org := range cfg.GitHub.WatchOrgs {
-			fmt.Printf("正在获取组织 %s 的仓库列表...\n", org)
-			orgRepos, err := client.getOrgRepositories(org, cfg.GitHub.OnlyWithReleases)
-			if err != nil {
-				fmt.Printf("获取组织 %s 的仓库列表失败: %v\n", org, err)
-				continue
-			}
-			fmt.Printf("找到 %d 个组织仓库\n", len(orgRepos))
-			for _, repo := range orgRepos {
-				key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-				repoMap[key] = repo
-			}
-		}
+	if anonymous && !useFeed.Load() {
+		repoConfigs = limitToQuota(repoConfigs, startRemaining)
 	}
 
-	// 将去重后的仓库列表转换为切片
-	var repoConfigs []config.RepoConfig
-	for _, repo := range repoMap {
-		repoConfigs = append(repoConfigs, repo)
-	}
-
-	// 如果没有找到要监控的仓库
-	if len(repoConfigs) == 0 {
-		// 检查是否是因为只过滤了有release的仓库导致的
-		if cfg.GitHub.OnlyWithReleases && (cfg.GitHub.AutoWatchUser || cfg.GitHub.WatchStarred) {
-			fmt.Println("\n注意: 没有找到任何有release的仓库。")
-			fmt.Println("如果您确定要监控没有release的仓库，请在配置中设置 only_with_releases: false")
-
-			// 尝试获取所有仓库（包括没有release的）
-			var allRepos []config.RepoConfig
-
-			if cfg.GitHub.AutoWatchUser {
-				userRepos, err := client.getUserRepositories(false)
-				if err == nil && len(userRepos) > 0 {
-					for _, repo := range userRepos {
-						key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-						if _, exists := repoMap[key]; !exists {
-							repoMap[key] = repo
-							allRepos = append(allRepos, repo)
-						}
-					}
-				}
-			}
-
-			if cfg.GitHub.WatchStarred && len(allRepos) == 0 {
-				starredRepos, err := client.getUserStarredRepositories(false)
-				if err == nil && len(starredRepos) > 0 {
-					for _, repo := range starredRepos {
-						key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
-						if _, exists := repoMap[key]; !exists {
-							repoMap[key] = repo
-							allRepos = append(allRepos, repo)
-						}
-					}
-				}
-			}
-
-			if len(allRepos) > 0 {
-				fmt.Println("\n为了让系统正常运行，将监控所有仓库（不仅限于有release的仓库）:")
-				for i, repo := range allRepos {
-					if i < 5 { // 只显示前5个
-						fmt.Printf("- %s/%s\n", repo.Owner, repo.Name)
-					}
-				}
-				if len(allRepos) > 5 {
-					fmt.Printf("  ...以及其他 %d 个仓库\n", len(allRepos)-5)
-				}
-				repoConfigs = allRepos
-			} else {
-				return nil, fmt.Errorf("未找到任何仓库，请检查GitHub Token权限或在配置文件中手动指定仓库")
-			}
-		} else {
-			return nil, fmt.Errorf("未配置要监控的仓库，请在配置文件中添加仓库或启用自动监控")
-		}
+	// 预热模式：尚未建立基线的仓库按配额预算分多次运行检查
+	warm, err := loadWarmup(cfg)
+	if err != nil {
+		return nil, CheckStats{}, err
 	}
+	allRepos := repoConfigs
+	repoConfigs = warm.selectRepos(repoConfigs, client.store, cfg, startRemaining)
 
 	fmt.Printf("共监控 %d 个仓库，正在并发检查是否有新版本发布...\n", len(repoConfigs))
 
@@ -282,6 +481,9 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 	// GitHub 的二级速率限制：每分钟最多100个并发请求
 	// 设置为50个并发，既快速又安全
 	concurrencyLimit := 50 // 50个并发请求
+	if anonymous {
+		concurrencyLimit = anonymousConcurrency
+	}
 
 	// 对于大量仓库，分批处理
 	// 每批处理500个仓库，加快检查速度
@@ -291,7 +493,40 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 	checkRepo := func(r config.RepoConfig) {
 		defer wg.Done()
 
-		release, err := client.GetLatestRelease(r.Owner, r.Name, showDescription, cfg.GitHub.CheckDays, cfg)
+		client.migrateByRepoID(r)
+		var (
+			release *ReleaseInfo
+			err     error
+		)
+		if useFeed.Load() {
+			release, err = client.checkFeedRelease(r.Owner, r.Name, showDescription, cfg, loc)
+		} else {
+			release, err = client.GetLatestRelease(r.Owner, r.Name, showDescription, cfg.GitHub.CheckDays, cfg)
+			// 检查中途配额用完时，这个仓库和剩余的仓库改为通过订阅检查
+			if err != nil && client.feed != nil && strings.Contains(err.Error(), "rate limit exceeded") {
+				if !useFeed.Swap(true) {
+					fmt.Println("⚠️ GitHub API 配额已用完，剩余仓库改为通过 releases.atom 订阅检查")
+				}
+				release, err = client.checkFeedRelease(r.Owner, r.Name, showDescription, cfg, loc)
+			}
+		}
+		if err == nil && r.ID != 0 {
+			if err := client.store.SetRepoID(r.Owner, r.Name, r.ID); err != nil {
+				fmt.Printf("警告: 记录仓库 %s/%s 的ID失败: %v\n", r.Owner, r.Name, err)
+			}
+		}
+		if err == nil {
+			warm.markChecked(r.Owner, r.Name)
+		}
+		if err == nil && release != nil && showDescription && cfg.GitHub.GenerateNotes != "" && !useFeed.Load() {
+			client.fillEmptyNotes(release, cfg)
+		}
+		if err == nil && release != nil && cfg.GitHub.MarkContributed && !useFeed.Load() {
+			release.Contributed = client.contributesTo(release.Owner, release.Repository)
+		}
+		if err == nil && release != nil && cfg.GitHub.Scoring.Enabled {
+			client.scoreRelease(release, r, cfg.GitHub.Scoring, anonymous || useFeed.Load())
+		}
 
 		mu.Lock()
 		defer mu.Unlock()
@@ -311,6 +546,20 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 
 		// 如果有新版本
 		if release != nil {
+			applyPinnedVersion(release, r)
+
+			// 只在锁定版本落后时通知
+			if cfg.GitHub.OnlyWhenBehind && release.PinnedVersion != "" && !release.AffectsPinned {
+				noReleaseCount++
+				return
+			}
+
+			// 预热中新建立基线的仓库只记录版本，不通知
+			if warm.suppress(r.Owner, r.Name) {
+				noReleaseCount++
+				return
+			}
+
 			fmt.Printf("发现新版本: %s/%s (%s)\n", r.Owner, r.Name, release.TagName)
 			results = append(results, release)
 		} else {
@@ -362,6 +611,44 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 		}
 	}
 
+	// 检查带有关注标签的Issue
+	if len(cfg.GitHub.WatchLabels.Labels) > 0 && !rateLimitHit && !useFeed.Load() {
+		labelRepos := cfg.GitHub.Repos
+		if cfg.GitHub.WatchLabels.AllRepos {
+			labelRepos = repoConfigs
+		}
+		results = append(results, client.checkLabeledIssues(labelRepos, cfg, loc)...)
+	}
+
+	// 检查关注的Gist
+	if len(cfg.GitHub.Gists) > 0 && !rateLimitHit && !useFeed.Load() {
+		results = append(results, client.checkGists(cfg, loc)...)
+	}
+
+	// 检查尚未创建Release的新标签，在版本检查之后进行，本次已通知的Release不会再按标签通知
+	if cfg.GitHub.Tags.Enabled && !rateLimitHit && !useFeed.Load() {
+		tagRepos := cfg.GitHub.Repos
+		if cfg.GitHub.Tags.AllRepos {
+			tagRepos = repoConfigs
+		}
+		results = append(results, client.checkTags(tagRepos, cfg, loc)...)
+	}
+
+	// 检查仓库和npm包的弃用状态，npm包不消耗API配额，达到速率限制时仍然检查
+	if cfg.GitHub.Deprecation.Enabled {
+		deprecationRepos := cfg.GitHub.Repos
+		if cfg.GitHub.Deprecation.AllRepos {
+			deprecationRepos = repoConfigs
+		}
+		if rateLimitHit || useFeed.Load() {
+			deprecationRepos = nil
+		}
+		results = append(results, client.checkDeprecations(deprecationRepos, cfg)...)
+	}
+
+	results = append(results, watchChanges...)
+	warm.save(allRepos)
+
 	fmt.Printf("\n检查完成: 共 %d 个仓库\n", len(repoConfigs))
 	if rateLimitHit {
 		fmt.Printf("- 由于达到GitHub API速率限制，部分仓库未能检查\n")
@@ -372,6 +659,14 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 		fmt.Printf("- %d 个仓库检查失败\n", errorCount)
 	}
 
+	stats := CheckStats{
+		Repos:          len(repoConfigs),
+		Errors:         errorCount,
+		RateLimited:    rateLimitHit,
+		APIRequests:    client.usage.total(),
+		QuotaRemaining: client.reportUsage(startRemaining),
+	}
+
 	if len(results) == 0 {
 		fmt.Printf("\n提示: 未发现任何%d天内发布的新版本。如果您想测试通知功能，可以:\n", cfg.GitHub.CheckDays)
 		fmt.Println("1. 在您的任意GitHub仓库中创建一个新的release")
@@ -379,7 +674,192 @@ func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*ReleaseIn
 		fmt.Println("3. 手动在配置文件中添加要监控的特定仓库")
 	}
 
-	return results, nil
+	return results, stats, nil
+}
+
+// output 返回进度信息的输出位置
+func (c *Client) output() io.Writer {
+	if c.out == nil {
+		return os.Stdout
+	}
+	return c.out
+}
+
+// printf 输出进度信息
+func (c *Client) printf(format string, a ...interface{}) {
+	fmt.Fprintf(c.output(), format, a...)
+}
+
+// println 输出一行进度信息
+func (c *Client) println(a ...interface{}) {
+	fmt.Fprintln(c.output(), a...)
+}
+
+// discoverRepos 汇总手动指定、自动发现和依赖清单中的仓库，去重后按分片过滤，得到实际监控的仓库列表
+// 同时返回每个仓库的来源（键为 owner/name）
+// 同一个仓库出现在多个来源时，自动发现的结果按用户仓库、star、组织的顺序覆盖之前的配置；
+// 依赖清单只补充新的仓库，已在监控列表中的仓库只补充锁定版本
+func (c *Client) discoverRepos(cfg *config.Config) ([]config.RepoConfig, map[string]string, error) {
+	// 使用map去重，避免重复监控同一个仓库
+	repoMap := make(map[string]config.RepoConfig)
+	// 记录每个仓库的来源
+	sources := make(map[string]string)
+
+	// 如果配置了手动指定的仓库，添加到待检查列表（已重命名的仓库使用新名称）
+	c.loadRenames(cfg)
+	if len(cfg.GitHub.Repos) > 0 {
+		for _, repo := range c.applyRenames(cfg.GitHub.Repos) {
+			key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+			repoMap[key] = repo
+			sources[key] = WatchSourceManual
+		}
+	}
+
+	// 如果启用了自动监控用户仓库
+	if cfg.GitHub.AutoWatchUser {
+		c.println("正在获取用户仓库列表...")
+		userRepos, err := c.getUserRepositories(cfg.GitHub.OnlyWithReleases)
+		if err != nil {
+			c.printf("获取用户仓库列表失败: %v\n", err)
+			c.discoveryErrors++
+		} else {
+			c.printf("找到 %d 个用户仓库\n", len(userRepos))
+			for _, repo := range userRepos {
+				key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+				repoMap[key] = repo
+				sources[key] = WatchSourceUser
+			}
+		}
+	}
+
+	// 如果启用了监控star的仓库
+	if cfg.GitHub.WatchStarred {
+		c.println("正在获取用户已star的仓库列表...")
+		starredRepos, err := c.getUserStarredRepositories(cfg.GitHub.OnlyWithReleases)
+		if err != nil {
+			c.printf("获取用户已star的仓库列表失败: %v\n", err)
+			c.discoveryErrors++
+		} else {
+			c.printf("找到 %d 个已star的仓库\n", len(starredRepos))
+			for _, repo := range starredRepos {
+				key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+				repoMap[key] = repo
+				sources[key] = WatchSourceStarred
+			}
+		}
+	}
+
+	// 如果配置了需要监控的组织
+	if len(cfg.GitHub.WatchOrgs) > 0 {
+		for _, org := range cfg.GitHub.WatchOrgs {
+			c.printf("正在获取组织 %s 的仓库列表...\n", org)
+			orgRepos, err := c.getOrgRepositories(org, cfg.GitHub.OnlyWithReleases)
+			if err != nil {
+				c.printf("获取组织 %s 的仓库列表失败: %v\n", org, err)
+				c.discoveryErrors++
+				continue
+			}
+			c.printf("找到 %d 个组织仓库\n", len(orgRepos))
+			for _, repo := range orgRepos {
+				key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+				repoMap[key] = repo
+				sources[key] = WatchSourceOrgPrefix + org
+			}
+		}
+	}
+
+	// 如果配置了依赖清单，从直接依赖中推导要监控的仓库
+	if len(cfg.GitHub.Manifests) > 0 {
+		repos, failures := c.loadManifestRepos(cfg.GitHub.Manifests)
+		// 解析失败的清单或依赖没有加入监控列表，与其他来源的失败一样计入发现错误
+		c.discoveryErrors += failures
+		for _, repo := range repos {
+			key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+			if existing, exists := repoMap[key]; exists {
+				// 仓库已在监控列表中，仅补充锁定版本
+				if existing.PinnedVersion == "" {
+					existing.PinnedVersion = repo.PinnedVersion
+					repoMap[key] = existing
+				}
+				continue
+			}
+			repoMap[key] = repo
+			sources[key] = WatchSourceManifest
+		}
+	}
+
+	// 将去重后的仓库列表转换为切片
+	var repoConfigs []config.RepoConfig
+	for _, repo := range repoMap {
+		repoConfigs = append(repoConfigs, repo)
+	}
+
+	// 如果没有找到要监控的仓库
+	if len(repoConfigs) == 0 {
+		// 检查是否是因为只过滤了有release的仓库导致的
+		if cfg.GitHub.OnlyWithReleases && (cfg.GitHub.AutoWatchUser || cfg.GitHub.WatchStarred) {
+			c.println("\n注意: 没有找到任何有release的仓库。")
+			c.println("如果您确定要监控没有release的仓库，请在配置中设置 only_with_releases: false")
+
+			// 尝试获取所有仓库（包括没有release的）
+			var allRepos []config.RepoConfig
+
+			if cfg.GitHub.AutoWatchUser {
+				userRepos, err := c.getUserRepositories(false)
+				if err == nil && len(userRepos) > 0 {
+					for _, repo := range userRepos {
+						key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+						if _, exists := repoMap[key]; !exists {
+							repoMap[key] = repo
+							sources[key] = WatchSourceUser
+							allRepos = append(allRepos, repo)
+						}
+					}
+				}
+			}
+
+			if cfg.GitHub.WatchStarred && len(allRepos) == 0 {
+				starredRepos, err := c.getUserStarredRepositories(false)
+				if err == nil && len(starredRepos) > 0 {
+					for _, repo := range starredRepos {
+						key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+						if _, exists := repoMap[key]; !exists {
+							repoMap[key] = repo
+							sources[key] = WatchSourceStarred
+							allRepos = append(allRepos, repo)
+						}
+					}
+				}
+			}
+
+			if len(allRepos) > 0 {
+				c.println("\n为了让系统正常运行，将监控所有仓库（不仅限于有release的仓库）:")
+				for i, repo := range allRepos {
+					if i < 5 { // 只显示前5个
+						c.printf("- %s/%s\n", repo.Owner, repo.Name)
+					}
+				}
+				if len(allRepos) > 5 {
+					c.printf("  ...以及其他 %d 个仓库\n", len(allRepos)-5)
+				}
+				repoConfigs = allRepos
+			} else {
+				return nil, nil, fmt.Errorf("未找到任何仓库，请检查GitHub Token权限或在配置文件中手动指定仓库")
+			}
+		} else if len(cfg.GitHub.Gists) == 0 && len(cfg.GitLab.Projects) == 0 && len(cfg.NPM.Packages) == 0 && len(cfg.PyPI.Packages) == 0 && len(cfg.Crates.Packages) == 0 && len(cfg.GoProxy.Modules) == 0 && len(cfg.Maven.Packages) == 0 && len(cfg.VSCode.Extensions) == 0 {
+			// 只关注Gist、GitLab项目、Go模块、Maven构件、VS Code扩展或npm、PyPI、crates.io上的包时没有要检查的仓库
+			return nil, nil, fmt.Errorf("未配置要监控的仓库，请在配置文件中添加仓库或启用自动监控")
+		}
+	}
+
+	// 分片运行时只检查属于当前分片的仓库
+	if cfg.Shard.Enabled() {
+		total := len(repoConfigs)
+		repoConfigs = filterShard(repoConfigs, cfg.Shard)
+		c.printf("分片 %s: 共 %d 个仓库，当前分片负责 %d 个\n", cfg.Shard, total, len(repoConfigs))
+	}
+
+	return repoConfigs, sources, nil
 }
 
 // getUserRepositories 获取授权用户的所有仓库
@@ -391,6 +871,7 @@ func (c *Client) getUserRepositories(onlyWithReleases bool) ([]config.RepoConfig
 	var allRepos []config.RepoConfig
 
 	for {
+		c.usage.add(usageDiscovery)
 		repos, resp, err := c.client.Repositories.ListByAuthenticatedUser(c.ctx, opt)
 		if err != nil {
 			return nil, fmt.Errorf("获取用户仓库列表失败: %v", err)
@@ -405,6 +886,7 @@ func (c *Client) getUserRepositories(onlyWithReleases bool) ([]config.RepoConfig
 			allRepos = append(allRepos, config.RepoConfig{
 				Owner: repo.GetOwner().GetLogin(),
 				Name:  repo.GetName(),
+				ID:    repo.GetID(),
 			})
 		}
 
@@ -432,6 +914,7 @@ func (c *Client) getUserStarredRepositories(onlyWithReleases bool) ([]config.Rep
 	var allRepos []config.RepoConfig
 
 	for {
+		c.usage.add(usageDiscovery)
 		repos, resp, err := c.client.Activity.ListStarred(c.ctx, "", opt)
 		if err != nil {
 			return nil, fmt.Errorf("获取用户已star的仓库列表失败: %v", err)
@@ -444,6 +927,7 @@ func (c *Client) getUserStarredRepositories(onlyWithReleases bool) ([]config.Rep
 				allRepos = append(allRepos, config.RepoConfig{
 					Owner: repository.GetOwner().GetLogin(),
 					Name:  repository.GetName(),
+					ID:    repository.GetID(),
 				})
 			}
 		}
@@ -472,6 +956,7 @@ func (c *Client) getOrgRepositories(org string, onlyWithReleases bool) ([]config
 	var allRepos []config.RepoConfig
 
 	for {
+		c.usage.add(usageDiscovery)
 		repos, resp, err := c.client.Repositories.ListByOrg(c.ctx, org, opt)
 		if err != nil {
 			return nil, fmt.Errorf("获取组织仓库列表失败: %v", err)
@@ -481,6 +966,7 @@ func (c *Client) getOrgRepositories(org string, onlyWithReleases bool) ([]config
 			allRepos = append(allRepos, config.RepoConfig{
 				Owner: org,
 				Name:  repo.GetName(),
+				ID:    repo.GetID(),
 			})
 		}
 
@@ -505,7 +991,7 @@ func (c *Client) filterReposWithReleases(allRepos []config.RepoConfig, repoType
 		return nil, nil
 	}
 
-	fmt.Printf("正在检查 %d 个%s仓库是否有release...\n", len(allRepos), repoType)
+	c.printf("正在检查 %d 个%s仓库是否有release...\n", len(allRepos), repoType)
 
 	var (
 		filteredRepos []config.RepoConfig
@@ -531,7 +1017,7 @@ func (c *Client) filterReposWithReleases(allRepos []config.RepoConfig, repoType
 			case <-ticker.C:
 				current := atomic.LoadInt32(&checked)
 				if current > 0 {
-					fmt.Printf("已检查 %d/%d 个%s仓库...\n", current, len(allRepos), repoType)
+					c.printf("已检查 %d/%d 个%s仓库...\n", current, len(allRepos), repoType)
 				}
 			case <-done:
 				return
@@ -557,6 +1043,7 @@ func (c *Client) filterReposWithReleases(allRepos []config.RepoConfig, repoType
 					wg.Done()
 				}()
 
+				c.usage.add(usagePrefilter)
 				_, resp, _ := c.client.Repositories.GetLatestRelease(c.ctx, r.Owner, r.Name)
 
 				// 如果有release (HTTP 200) 或者API错误但不是404
@@ -574,6 +1061,6 @@ func (c *Client) filterReposWithReleases(allRepos []config.RepoConfig, repoType
 	wg.Wait()
 	close(done) // 通知进度报告协程结束
 
-	fmt.Printf("%s仓库过滤完成: 共 %d 个，有release的 %d 个\n", repoType, len(allRepos), len(filteredRepos))
+	c.printf("%s仓库过滤完成: 共 %d 个，有release的 %d 个\n", repoType, len(allRepos), len(filteredRepos))
 	return filteredRepos, nil
 }
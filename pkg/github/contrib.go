@@ -0,0 +1,67 @@
+package github
+
+import (
+	"fmt"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// ContributorBadge 返回"你参与贡献的仓库"标记，未参与贡献时返回空字符串
+func (r *ReleaseInfo) ContributorBadge() string {
+	if !r.Contributed {
+		return ""
+	}
+	return "🙌 你参与贡献的仓库"
+}
+
+// contributesTo 判断授权用户是否向仓库提交过代码，结果在本次运行中缓存
+// 每个仓库消耗1次API请求（按作者列出1条提交），只对有新版本的仓库检查
+func (c *Client) contributesTo(owner, repo string) bool {
+	login := c.authLogin(usageContributions)
+	if login == "" {
+		return false
+	}
+
+	c.contribMu.Lock()
+	defer c.contribMu.Unlock()
+
+	key := repoKey(owner, repo)
+	if contributed, ok := c.contributed[key]; ok {
+		return contributed
+	}
+
+	c.usage.add(usageContributions)
+	commits, _, err := c.client.Repositories.ListCommits(c.ctx, owner, repo, &github.CommitsListOptions{
+		Author:      login,
+		ListOptions: github.ListOptions{PerPage: 1},
+	})
+	if err != nil {
+		fmt.Printf("警告: 检查 %s/%s 的贡献记录失败: %v\n", owner, repo, err)
+		return false
+	}
+
+	if c.contributed == nil {
+		c.contributed = make(map[string]bool)
+	}
+	c.contributed[key] = len(commits) > 0
+	return c.contributed[key]
+}
+
+// authLogin 返回Token对应的用户名，结果在本次运行中缓存，获取失败时返回空字符串
+// category 为首次获取时计入的API请求类别
+func (c *Client) authLogin(category int) string {
+	c.loginMu.Lock()
+	defer c.loginMu.Unlock()
+
+	if c.login == "" && c.loginErr == nil {
+		c.usage.add(category)
+		user, _, err := c.client.Users.Get(c.ctx, "")
+		if err != nil {
+			c.loginErr = err
+			fmt.Printf("警告: 获取Token对应的用户失败，无法判断参与贡献的仓库和自己的仓库: %v\n", err)
+			return ""
+		}
+		c.login = user.GetLogin()
+	}
+	return c.login
+}
@@ -0,0 +1,246 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultNPMRegistry 默认的npm registry地址
+const DefaultNPMRegistry = "https://registry.npmjs.org"
+
+// deprecationStateOwner 弃用状态在状态文件中使用的 owner 前缀，已通知弃用时记录的版本为 deprecatedMarker
+const deprecationStateOwner = "deprecated"
+
+// deprecatedMarker 已通知弃用的标记，恢复（如取消归档）后清空，之后再次弃用时重新通知
+const deprecatedMarker = "deprecated"
+
+// readmeScanLimit 只检查README开头的内容，弃用说明通常放在最前面
+const readmeScanLimit = 2000
+
+// deprecationPattern 仓库描述和README开头中的弃用说明
+// 只匹配整句的说明，避免README中提到某个API已弃用时误报
+var deprecationPattern = regexp.MustCompile(`(?i)` +
+	`\b(this|the)\s+(project|repository|repo|library|package|module|tool|crate|plugin)\s+(is|has\s+been)\s+(now\s+)?(officially\s+)?(deprecated|archived|discontinued|unmaintained|no\s+longer\s+(actively\s+)?maintained)` +
+	`|\bno\s+longer\s+(actively\s+)?maintained\b` +
+	`|\bdeprecated\s*[:,-]?\s*(please\s+)?use\b` +
+	`|\bdeprecated\s+in\s+favou?r\s+of\b` +
+	`|^\W*(deprecated|unmaintained)\W*$` +
+	`|(本项目|该项目|此项目|本仓库|该仓库)(已|已经)?(弃用|废弃|停止维护|不再维护)`)
+
+// checkDeprecations 检查仓库是否被归档或在描述、README中标注弃用，以及关注的npm包是否被标记为弃用
+// 每个仓库或包弃用时只通知一次
+func (c *Client) checkDeprecations(repos []config.RepoConfig, cfg *config.Config) []*ReleaseInfo {
+	dc := cfg.GitHub.Deprecation
+	if len(repos) == 0 && len(dc.NPM) == 0 {
+		return nil
+	}
+	fmt.Printf("正在检查 %d 个仓库和 %d 个npm包的弃用状态...\n", len(repos), len(dc.NPM))
+
+	var results []*ReleaseInfo
+	for _, repo := range repos {
+		info, err := c.checkRepoDeprecation(repo.Owner, repo.Name, dc.Readme)
+		if err != nil {
+			fmt.Printf("检查仓库 %s/%s 的弃用状态失败: %v\n", repo.Owner, repo.Name, err)
+			continue
+		}
+		if info != nil {
+			fmt.Printf("仓库已弃用: %s/%s（%s）\n", repo.Owner, repo.Name, info.DeprecationReason)
+			results = append(results, info)
+		}
+	}
+
+	if len(dc.NPM) > 0 {
+		registry, err := newNPMRegistry(dc.NPMRegistry, cfg.Network.LocalAddr)
+		if err != nil {
+			fmt.Printf("检查npm包的弃用状态失败: %v\n", err)
+			return results
+		}
+		for _, name := range dc.NPM {
+			if cfg.Shard.Enabled() && !inShard(config.RepoConfig{Owner: SourceNPM, Name: name}, cfg.Shard) {
+				continue
+			}
+			info, err := c.checkNPMDeprecation(registry, name)
+			if err != nil {
+				fmt.Printf("检查npm包 %s 的弃用状态失败: %v\n", name, err)
+				continue
+			}
+			if info != nil {
+				fmt.Printf("npm包已弃用: %s（%s）\n", name, info.DeprecationReason)
+				results = append(results, info)
+			}
+		}
+	}
+	return results
+}
+
+// checkRepoDeprecation 检查仓库的归档状态和弃用说明，新弃用时返回通知
+func (c *Client) checkRepoDeprecation(owner, name string, readme bool) (*ReleaseInfo, error) {
+	c.usage.add(usageDeprecation)
+	repo, _, err := c.client.Repositories.Get(c.ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := ""
+	if repo.GetArchived() {
+		reason = "仓库已归档"
+	} else if line, ok := findDeprecationNotice(repo.GetDescription()); ok {
+		reason = "仓库描述中标注弃用: " + line
+	} else if readme {
+		c.usage.add(usageDeprecation)
+		file, resp, err := c.client.Repositories.GetReadme(c.ctx, owner, name, nil)
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return nil, err
+		}
+		if file != nil {
+			content, err := file.GetContent()
+			if err != nil {
+				return nil, fmt.Errorf("解码README失败: %v", err)
+			}
+			if line, ok := findDeprecationNotice(content); ok {
+				reason = "README中标注弃用: " + line
+			}
+		}
+	}
+
+	isNew, err := c.markDeprecated(owner, name, reason != "")
+	if err != nil || !isNew {
+		return nil, err
+	}
+	return &ReleaseInfo{
+		Event:             EventDeprecated,
+		Owner:             owner,
+		Repository:        name,
+		TagName:           c.store.GetLatestTag(owner, name),
+		Name:              "已弃用",
+		HTMLURL:           repo.GetHTMLURL(),
+		PublishedAt:       c.now(),
+		DeprecationReason: reason,
+	}, nil
+}
+
+// checkNPMDeprecation 检查npm包最新版本的弃用信息，新弃用时返回通知
+func (c *Client) checkNPMDeprecation(registry *npmRegistry, name string) (*ReleaseInfo, error) {
+	latest, message, err := registry.deprecation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	isNew, err := c.markDeprecated(SourceNPM, name, message != "")
+	if err != nil || !isNew {
+		return nil, err
+	}
+	return &ReleaseInfo{
+		Event:             EventDeprecated,
+		Source:            SourceNPM,
+		Owner:             SourceNPM,
+		Repository:        name,
+		TagName:           latest,
+		Name:              "已弃用",
+		HTMLURL:           "https://www.npmjs.com/package/" + name,
+		PublishedAt:       c.now(),
+		DeprecationReason: message,
+	}, nil
+}
+
+// markDeprecated 记录弃用状态，返回是否为新的弃用（需要通知）
+// 不再弃用时清空记录，之后再次弃用时重新通知
+func (c *Client) markDeprecated(owner, name string, deprecated bool) (bool, error) {
+	stateOwner := deprecationStateOwner + ":" + owner
+	if !deprecated {
+		if c.store.GetLatestTag(stateOwner, name) != "" {
+			return false, c.store.UpdateState(stateOwner, name, "")
+		}
+		return false, nil
+	}
+	isNew, err := c.store.CheckAndUpdateIfNew(stateOwner, name, deprecatedMarker)
+	if err != nil {
+		return false, fmt.Errorf("更新弃用状态失败: %v", err)
+	}
+	return isNew, nil
+}
+
+// findDeprecationNotice 在文本开头查找弃用说明，返回匹配的一行
+func findDeprecationNotice(text string) (string, bool) {
+	if len(text) > readmeScanLimit {
+		text = text[:readmeScanLimit]
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || !deprecationPattern.MatchString(line) {
+			continue
+		}
+		// 去掉Markdown标记，过长时截断
+		line = strings.NewReplacer("**", "", "__", "").Replace(line)
+		line = strings.TrimSpace(strings.Trim(line, "#>*_`[]!~ "))
+		if r := []rune(line); len(r) > 120 {
+			line = string(r[:120]) + "…"
+		}
+		return line, true
+	}
+	return "", false
+}
+
+// npmRegistry 读取npm包元数据，不消耗GitHub API配额
+type npmRegistry struct {
+	baseURL string
+	client  *http.Client
+}
+
+// newNPMRegistry 创建npm registry客户端，baseURL 为空时使用 DefaultNPMRegistry
+func newNPMRegistry(baseURL, localAddr string) (*npmRegistry, error) {
+	if baseURL == "" {
+		baseURL = DefaultNPMRegistry
+	}
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: 15 * time.Second, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &npmRegistry{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}, nil
+}
+
+// deprecation 返回包的最新版本和该版本的弃用信息，没有弃用时信息为空
+func (r *npmRegistry) deprecation(name string) (string, string, error) {
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return "", "", fmt.Errorf("创建请求失败: %v", err)
+	}
+	// 精简的元数据同样包含每个版本的 deprecated 字段，体积小得多
+	req.Header.Set("Accept", "application/vnd.npm.install-v1+json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", "", fmt.Errorf("请求npm registry失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", "", fmt.Errorf("包不存在")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("npm registry返回状态码 %d", resp.StatusCode)
+	}
+
+	var meta struct {
+		DistTags struct {
+			Latest string `json:"latest"`
+		} `json:"dist-tags"`
+		Versions map[string]struct {
+			Deprecated string `json:"deprecated"`
+		} `json:"versions"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 32<<20)).Decode(&meta); err != nil {
+		return "", "", fmt.Errorf("解析npm包元数据失败: %v", err)
+	}
+	latest := meta.DistTags.Latest
+	return latest, meta.Versions[latest].Deprecated, nil
+}
@@ -0,0 +1,116 @@
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// TestFindDeprecationNotice 测试弃用说明的识别，README中提到的已弃用API不算
+func TestFindDeprecationNotice(t *testing.T) {
+	for text, want := range map[string]string{
+		"# DEPRECATED\n\nUse foo instead.":                                    "DEPRECATED",
+		"# bar\n\n> **This project is no longer maintained.** See baz.":       "This project is no longer maintained. See baz.",
+		"A tool.\n\nThis repository has been deprecated in favor of qux.":     "This repository has been deprecated in favor of qux.",
+		"> [!WARNING]\n> Deprecated: please use github.com/o/new":             "Deprecated: please use github.com/o/new",
+		"## 说明\n本项目已停止维护，请使用新版本":                                              "本项目已停止维护，请使用新版本",
+		"# lib\n\nThe `Parse` function is deprecated since v2, see `Decode`.": "",
+		"# lib\n\n## Deprecated APIs\n\n- Foo":                                "",
+	} {
+		got, ok := findDeprecationNotice(text)
+		if got != want || ok != (want != "") {
+			t.Errorf("findDeprecationNotice(%q) = %q, %v，期望 %q", text, got, ok, want)
+		}
+	}
+}
+
+// deprecationClient 启动返回仓库信息、README和npm包元数据的模拟服务，archived 控制仓库是否已归档
+func deprecationClient(t *testing.T, archived *bool) (*Client, string) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/old", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name": "old", "archived": *archived, "html_url": "https://github.com/o/old",
+		})
+	})
+	mux.HandleFunc("/repos/o/banner", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": "banner", "description": "A CLI tool"})
+	})
+	mux.HandleFunc("/repos/o/banner/readme", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"encoding": "base64",
+			"content":  base64.StdEncoding.EncodeToString([]byte("# banner\n\n**This project is no longer maintained.**\n")),
+		})
+	})
+	mux.HandleFunc("/npm/request", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"dist-tags":{"latest":"2.88.2"},"versions":{"2.88.1":{},"2.88.2":{"deprecated":"request has been deprecated"}}}`))
+	})
+	mux.HandleFunc("/npm/@scope%2Factive", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"dist-tags":{"latest":"1.0.0"},"versions":{"1.0.0":{}}}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	store, err := util.NewStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := github.NewClient(nil)
+	api.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: api, ctx: t.Context(), store: store}, server.URL + "/npm"
+}
+
+// TestCheckDeprecations 测试归档、README弃用说明和npm弃用各通知一次，取消归档后再次归档时重新通知
+func TestCheckDeprecations(t *testing.T) {
+	archived := true
+	c, registry := deprecationClient(t, &archived)
+
+	cfg := &config.Config{}
+	cfg.GitHub.Deprecation = config.DeprecationConfig{
+		Enabled:     true,
+		Readme:      true,
+		NPM:         []string{"request", "@scope/active"},
+		NPMRegistry: registry,
+	}
+	repos := []config.RepoConfig{{Owner: "o", Name: "old"}, {Owner: "o", Name: "banner"}}
+
+	got := c.checkDeprecations(repos, cfg)
+	if len(got) != 3 {
+		t.Fatalf("发现 %d 个弃用，期望 3 个: %+v", len(got), got)
+	}
+	if got[0].Event != EventDeprecated || got[0].DeprecationReason != "仓库已归档" {
+		t.Errorf("归档的仓库: %+v", got[0])
+	}
+	if got[1].DeprecationReason != "README中标注弃用: This project is no longer maintained." {
+		t.Errorf("README弃用原因为 %q", got[1].DeprecationReason)
+	}
+	if npm := got[2]; npm.Source != SourceNPM || npm.Repository != "request" || npm.TagName != "2.88.2" || npm.DeprecationReason != "request has been deprecated" {
+		t.Errorf("npm包: %+v", npm)
+	}
+	if label := got[0].EventLabel(); label != "⚠️ 已弃用: 仓库已归档" {
+		t.Errorf("事件标签为 %q", label)
+	}
+
+	if again := c.checkDeprecations(repos, cfg); len(again) != 0 {
+		t.Errorf("已通知的弃用不应重复通知: %+v", again)
+	}
+
+	cfg.GitHub.Deprecation.NPM = nil
+	archived = false
+	if got := c.checkDeprecations(repos[:1], cfg); len(got) != 0 {
+		t.Errorf("取消归档的仓库不应通知: %+v", got)
+	}
+	archived = true
+	if got := c.checkDeprecations(repos[:1], cfg); len(got) != 1 {
+		t.Errorf("再次归档时应重新通知，实际 %d 条", len(got))
+	}
+}
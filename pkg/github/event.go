@@ -0,0 +1,130 @@
+package github
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// 版本事件类型
+const (
+	// EventRelease 新版本发布
+	EventRelease = "release"
+	// EventNotesUpdated 已通知版本的发布说明被大幅修改
+	EventNotesUpdated = "notes_updated"
+	// EventIssueOpened 带有关注标签的Issue被创建
+	EventIssueOpened = "issue_opened"
+	// EventIssueClosed 带有关注标签的Issue被关闭
+	EventIssueClosed = "issue_closed"
+	// EventPromoted 之前通知过的预发布版本转为正式版
+	EventPromoted = "promoted"
+	// EventWatchAdded 仓库加入监控列表
+	EventWatchAdded = "watch_added"
+	// EventWatchRemoved 仓库移出监控列表
+	EventWatchRemoved = "watch_removed"
+	// EventSummary 由 notify summary 生成的一段时间内的版本汇总
+	EventSummary = "summary"
+	// EventGistUpdated 关注的Gist有新的修订
+	EventGistUpdated = "gist_updated"
+	// EventTagPushed 仓库推送了新标签，尚未创建Release
+	EventTagPushed = "tag_pushed"
+	// EventTagReleased 此前通知过的标签创建了Release
+	EventTagReleased = "tag_released"
+	// EventDeprecated 仓库被归档或标记为弃用，或npm包被标记为弃用
+	EventDeprecated = "deprecated"
+	// EventYanked 之前通知过的crate版本被撤回（yanked）
+	EventYanked = "yanked"
+	// EventAlertFiring serve 模式收到的Alertmanager告警触发
+	EventAlertFiring = "alert_firing"
+	// EventAlertResolved serve 模式收到的Alertmanager告警恢复
+	EventAlertResolved = "alert_resolved"
+)
+
+// 版本来源
+const (
+	// SourceGitHub GitHub（默认）
+	SourceGitHub = "github"
+	// SourceGitLab GitLab
+	SourceGitLab = "gitlab"
+	// SourceGitea Gitea / Forgejo
+	SourceGitea = "gitea"
+	// SourceGist GitHub Gist
+	SourceGist = "gist"
+	// SourceNPM npm包
+	SourceNPM = "npm"
+	// SourcePyPI PyPI包
+	SourcePyPI = "pypi"
+	// SourceCrates crates.io 上的Rust包
+	SourceCrates = "crates"
+	// SourceGo 通过Go模块代理检查的Go模块
+	SourceGo = "go"
+	// SourceMaven Maven仓库中的构件
+	SourceMaven = "maven"
+	// SourceVSCode VS Code扩展市场中的扩展
+	SourceVSCode = "vscode"
+	// SourceAlertmanager serve 模式收到的Prometheus Alertmanager告警
+	SourceAlertmanager = "alertmanager"
+)
+
+// EventKey 返回版本的稳定标识：来源、仓库（不区分大小写）和标签的SHA-256的前16字节（十六进制）
+// 同一版本的重发、重复检测以及之后的事件（如发布说明更新、转为正式版）得到相同的值，
+// 下游系统可以用它去重和关联同一版本的消息，需要区分事件时与 Event 一起使用
+func (r *ReleaseInfo) EventKey() string {
+	source := r.Source
+	if source == "" {
+		source = SourceGitHub
+	}
+	sum := sha256.Sum256([]byte(source + "\n" + strings.ToLower(r.Owner+"/"+r.Repository) + "\n" + r.TagName))
+	return hex.EncodeToString(sum[:16])
+}
+
+// EventLabel 返回事件类型的展示标签，普通新版本返回空字符串
+func (r *ReleaseInfo) EventLabel() string {
+	switch r.Event {
+	case EventNotesUpdated:
+		return "📝 发布说明已更新"
+	case EventIssueOpened:
+		return "🗺️ 新的路线图Issue"
+	case EventIssueClosed:
+		return "✅ 路线图Issue已关闭"
+	case EventPromoted:
+		return fmt.Sprintf("🎉 已转为正式版（此前通知过 %s）", r.PromotedFrom)
+	case EventWatchAdded:
+		return fmt.Sprintf("➕ 加入监控列表（%s）", r.WatchLabel)
+	case EventWatchRemoved:
+		return fmt.Sprintf("➖ 移出监控列表（此前来源: %s）", r.WatchLabel)
+	case EventSummary:
+		return fmt.Sprintf("🗓️ 版本汇总: %s", r.Name)
+	case EventGistUpdated:
+		return "📄 Gist已更新"
+	case EventTagPushed:
+		return "🏷️ 新标签（尚未创建Release）"
+	case EventTagReleased:
+		return "📦 已创建Release（此前通知过该标签）"
+	case EventDeprecated:
+		return fmt.Sprintf("⚠️ 已弃用: %s", r.DeprecationReason)
+	case EventYanked:
+		return "🚫 已撤回（yanked），请避免使用该版本"
+	case EventAlertFiring:
+		return "🔥 告警触发"
+	case EventAlertResolved:
+		return "✅ 告警已恢复"
+	default:
+		if r.Prerelease {
+			return "🧪 预发布版本"
+		}
+		return ""
+	}
+}
+
+// OpenGraphImageURL 返回仓库的OpenGraph预览图地址，非GitHub来源的版本返回空字符串
+// 第一段路径仅用于区分缓存，这里使用标签名，使每个版本获取最新的预览图
+func (r *ReleaseInfo) OpenGraphImageURL() string {
+	if r.Source != "" && r.Source != SourceGitHub {
+		return ""
+	}
+	return fmt.Sprintf("https://opengraph.githubassets.com/%s/%s/%s",
+		url.PathEscape(r.TagName), r.Owner, r.Repository)
+}
@@ -0,0 +1,33 @@
+package github
+
+import "testing"
+
+// TestEventKey 测试版本标识不区分仓库名大小写，与事件类型无关，来源或标签不同时不同
+func TestEventKey(t *testing.T) {
+	base := &ReleaseInfo{Event: EventRelease, Owner: "Foo", Repository: "Bar", TagName: "v1.0.0"}
+	key := base.EventKey()
+	if len(key) != 32 {
+		t.Fatalf("标识 %q 的长度应为32", key)
+	}
+
+	same := []*ReleaseInfo{
+		{Event: EventRelease, Source: SourceGitHub, Owner: "foo", Repository: "bar", TagName: "v1.0.0"},
+		{Event: EventNotesUpdated, Owner: "FOO", Repository: "bar", TagName: "v1.0.0"},
+	}
+	for _, r := range same {
+		if got := r.EventKey(); got != key {
+			t.Errorf("%s %s/%s %s 的标识 %s，期望 %s", r.Event, r.Owner, r.Repository, r.TagName, got, key)
+		}
+	}
+
+	different := []*ReleaseInfo{
+		{Source: SourceGitLab, Owner: "foo", Repository: "bar", TagName: "v1.0.0"},
+		{Owner: "foo", Repository: "bar", TagName: "v1.0.1"},
+		{Owner: "foo", Repository: "bar", TagName: "V1.0.0"},
+	}
+	for _, r := range different {
+		if r.EventKey() == key {
+			t.Errorf("%s %s/%s %s 的标识不应与 Foo/Bar v1.0.0 相同", r.Source, r.Owner, r.Repository, r.TagName)
+		}
+	}
+}
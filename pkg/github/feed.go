@@ -0,0 +1,200 @@
+package github
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// feedConcurrency 同时读取的订阅数，订阅地址不受API配额限制，但过多并发仍可能被github.com限流
+const feedConcurrency = 4
+
+// feedBaseURL 仓库订阅地址的前缀，测试时替换
+var feedBaseURL = "https://github.com"
+
+// atomFeed releases.atom 订阅中用到的字段
+type atomFeed struct {
+	Entries []atomEntry `xml:"entry"`
+}
+
+// atomEntry 订阅中的一个版本
+type atomEntry struct {
+	Updated string `xml:"updated"`
+	Title   string `xml:"title"`
+	Link    struct {
+		Href string `xml:"href,attr"`
+	} `xml:"link"`
+	Content string `xml:"content"`
+}
+
+// feedRelease 从订阅中解析出的版本
+type feedRelease struct {
+	TagName     string
+	Name        string
+	HTMLURL     string
+	PublishedAt time.Time
+	Body        string
+}
+
+// feedReader 通过公开的 releases.atom 订阅检查版本，不消耗API配额，只能检查公开仓库
+type feedReader struct {
+	client *http.Client
+	sem    chan struct{}
+}
+
+// newFeedReader 创建订阅读取器
+func newFeedReader(localAddr string) (*feedReader, error) {
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   15 * time.Second,
+		LocalAddr: localAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &feedReader{client: client, sem: make(chan struct{}, feedConcurrency)}, nil
+}
+
+// latest 返回订阅中最新的版本，没有版本时返回nil
+// 订阅中不区分预发布版本，未启用 include_prereleases 时按标签名判断并跳过预发布版本
+func (f *feedReader) latest(owner, repo string, includePrereleases bool) (*feedRelease, error) {
+	f.sem <- struct{}{}
+	defer func() { <-f.sem }()
+
+	feedURL := fmt.Sprintf("%s/%s/%s/releases.atom", feedBaseURL, url.PathEscape(owner), url.PathEscape(repo))
+	resp, err := f.client.Get(feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("读取版本订阅失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("版本订阅不存在（私有仓库无法通过订阅检查）")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("读取版本订阅失败，状态码: %d", resp.StatusCode)
+	}
+
+	releases, err := parseFeed(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *feedRelease
+	for _, r := range releases {
+		if !includePrereleases && isPrereleaseTag(r.TagName) {
+			continue
+		}
+		if latest == nil || r.PublishedAt.After(latest.PublishedAt) {
+			latest = r
+		}
+	}
+	return latest, nil
+}
+
+// parseFeed 解析 releases.atom 订阅
+func parseFeed(r io.Reader) ([]*feedRelease, error) {
+	var feed atomFeed
+	if err := xml.NewDecoder(r).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("解析版本订阅失败: %v", err)
+	}
+
+	var releases []*feedRelease
+	for _, e := range feed.Entries {
+		// 链接形如 https://github.com/owner/repo/releases/tag/v1.0.0
+		tag, err := url.PathUnescape(path.Base(e.Link.Href))
+		if err != nil || tag == "" || !strings.Contains(e.Link.Href, "/releases/tag/") {
+			continue
+		}
+		updated, err := time.Parse(time.RFC3339, e.Updated)
+		if err != nil {
+			continue
+		}
+		releases = append(releases, &feedRelease{
+			TagName:     tag,
+			Name:        strings.TrimSpace(e.Title),
+			HTMLURL:     e.Link.Href,
+			PublishedAt: updated,
+			Body:        htmlToText(e.Content),
+		})
+	}
+	return releases, nil
+}
+
+var (
+	htmlBlock = regexp.MustCompile(`(?i)</?(p|div|br|li|ul|ol|h[1-6]|pre|blockquote)[^>]*>`)
+	htmlTag   = regexp.MustCompile(`<[^>]+>`)
+	blankRuns = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToText 将订阅中的HTML发布说明转换为纯文本
+func htmlToText(s string) string {
+	s = htmlBlock.ReplaceAllString(s, "\n")
+	s = htmlTag.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	return strings.TrimSpace(blankRuns.ReplaceAllString(s, "\n\n"))
+}
+
+// checkFeedRelease 通过订阅检查仓库的新版本，判断和记录方式与 GetLatestRelease 相同
+// 订阅中没有附件和发布说明之外的信息，配置了附件规则的仓库等到可以使用API时再检查
+func (c *Client) checkFeedRelease(owner, repo string, showDescription bool, cfg *config.Config, loc *time.Location) (*ReleaseInfo, error) {
+	if pattern := assetPatternFor(cfg, owner, repo); pattern != "" {
+		return nil, nil
+	}
+
+	release, err := c.feed.latest(owner, repo, cfg.GitHub.IncludePrereleases)
+	if err != nil || release == nil {
+		return nil, err
+	}
+
+	if release.PublishedAt.Before(c.now().In(loc).AddDate(0, 0, -cfg.GitHub.CheckDays)) {
+		return nil, nil
+	}
+	if entry, ok := c.ignored.Match(owner, repo, release.TagName); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+
+	previousTag := c.store.GetLatestTag(owner, repo)
+	isNew, err := c.store.CheckAndUpdateIfNew(owner, repo, release.TagName)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+
+	releaseInfo := &ReleaseInfo{
+		Event:       EventRelease,
+		Owner:       owner,
+		Repository:  repo,
+		TagName:     release.TagName,
+		Name:        release.Name,
+		HTMLURL:     release.HTMLURL,
+		PublishedAt: release.PublishedAt.In(loc),
+		Prerelease:  cfg.GitHub.IncludePrereleases && isPrereleaseTag(release.TagName),
+		PreviousTag: previousTag,
+	}
+	releaseInfo.Highlights = FindHighlights(release.Body, cfg.Highlight.Keywords)
+	if showDescription {
+		releaseInfo.Description = release.Body
+	}
+	markPromotion(releaseInfo, previousTag)
+	return c.reconcileRelease(cfg, releaseInfo), nil
+}
+
+// isPrereleaseTag 按标签名判断是否为预发布版本
+func isPrereleaseTag(tag string) bool {
+	v, err := version.Parse(tag)
+	return err == nil && v.IsPrerelease()
+}
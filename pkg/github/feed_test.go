@@ -0,0 +1,66 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry>
+    <id>tag:github.com,2008:Repository/1/v1.3.0-rc.1</id>
+    <updated>2024-07-03T08:00:00Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/foo/bar/releases/tag/v1.3.0-rc.1"/>
+    <title>v1.3.0-rc.1</title>
+    <content type="html">&lt;p&gt;候选版本&lt;/p&gt;</content>
+  </entry>
+  <entry>
+    <id>tag:github.com,2008:Repository/1/v1.2.0</id>
+    <updated>2024-07-01T08:00:00Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/foo/bar/releases/tag/v1.2.0"/>
+    <title>Release 1.2.0</title>
+    <content type="html">&lt;h2&gt;Changes&lt;/h2&gt;&lt;ul&gt;&lt;li&gt;修复 &amp;amp; 改进&lt;/li&gt;&lt;/ul&gt;</content>
+  </entry>
+</feed>`
+
+// TestFeedLatest 测试读取订阅中的最新版本
+func TestFeedLatest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/foo/bar/releases.atom" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testFeed))
+	}))
+	defer server.Close()
+
+	original := feedBaseURL
+	feedBaseURL = server.URL
+	defer func() { feedBaseURL = original }()
+
+	f, err := newFeedReader("")
+	if err != nil {
+		t.Fatalf("创建订阅读取器失败: %v", err)
+	}
+
+	release, err := f.latest("foo", "bar", false)
+	if err != nil {
+		t.Fatalf("读取订阅失败: %v", err)
+	}
+	if release.TagName != "v1.2.0" || release.Name != "Release 1.2.0" {
+		t.Errorf("未启用预发布版本时应返回 v1.2.0，实际 %+v", release)
+	}
+	if release.Body != "Changes\n\n修复 & 改进" {
+		t.Errorf("发布说明转换不正确: %q", release.Body)
+	}
+
+	release, err = f.latest("foo", "bar", true)
+	if err != nil || release.TagName != "v1.3.0-rc.1" {
+		t.Errorf("启用预发布版本时应返回 v1.3.0-rc.1，实际 %+v %v", release, err)
+	}
+
+	if _, err := f.latest("foo", "private", false); err == nil {
+		t.Error("订阅不存在时应返回错误")
+	}
+}
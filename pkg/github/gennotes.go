@@ -0,0 +1,124 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+)
+
+// maxGeneratedCommits 根据提交对比生成的说明最多列出的提交数，更早的提交只列出数量
+const maxGeneratedCommits = 30
+
+// fillEmptyNotes 发布说明为空时生成说明，让通知中仍然有可读的变更摘要
+// api 模式调用 generate-notes 接口（需要仓库的写权限），失败时与 compare 模式相同，按与上一个版本之间的提交生成；
+// 生成失败时保持说明为空，不影响通知
+func (c *Client) fillEmptyNotes(release *ReleaseInfo, cfg *config.Config) {
+	if strings.TrimSpace(release.Description) != "" || (release.Event != EventRelease && release.Event != EventPromoted) {
+		return
+	}
+
+	previous := release.PreviousTag
+	if previous == "" {
+		previous = c.previousReleaseTag(release.Owner, release.Repository, release.TagName)
+	}
+
+	var (
+		notes string
+		err   error
+	)
+	if cfg.GitHub.GenerateNotes == config.GenerateNotesAPI {
+		notes, err = c.generatedNotes(release.Owner, release.Repository, release.TagName, previous)
+		if err != nil {
+			fmt.Printf("警告: 为 %s/%s %s 生成发布说明失败，改为按提交对比生成: %v\n", release.Owner, release.Repository, release.TagName, err)
+		}
+	}
+	if notes == "" && previous != "" {
+		notes, err = c.compareNotes(release.Owner, release.Repository, previous, release.TagName)
+		if err != nil {
+			fmt.Printf("警告: 对比 %s/%s 的 %s...%s 失败: %v\n", release.Owner, release.Repository, previous, release.TagName, err)
+		}
+	}
+	if notes == "" {
+		return
+	}
+
+	release.Description = notes
+	release.NotesGenerated = true
+	release.Highlights = FindHighlights(notes, cfg.Highlight.Keywords)
+}
+
+// previousReleaseTag 首次发现仓库时没有已通知的版本，使用仓库中上一个Release的标签，没有时返回空字符串
+func (c *Client) previousReleaseTag(owner, repo, tag string) string {
+	c.usage.add(usageNotes)
+	releases, _, err := c.client.Repositories.ListReleases(c.ctx, owner, repo, &github.ListOptions{PerPage: 10})
+	if err != nil {
+		return ""
+	}
+	for i, r := range releases {
+		if r.GetTagName() != tag {
+			continue
+		}
+		for _, prev := range releases[i+1:] {
+			if !prev.GetDraft() {
+				return prev.GetTagName()
+			}
+		}
+	}
+	return ""
+}
+
+// generatedNotes 调用 generate-notes 接口生成发布说明，previous 为空时由GitHub选择上一个版本
+func (c *Client) generatedNotes(owner, repo, tag, previous string) (string, error) {
+	opts := &github.GenerateNotesOptions{TagName: tag}
+	if previous != "" {
+		opts.PreviousTagName = github.Ptr(previous)
+	}
+	c.usage.add(usageNotes)
+	notes, _, err := c.client.Repositories.GenerateReleaseNotes(c.ctx, owner, repo, opts)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(notes.Body), nil
+}
+
+// compareNotes 列出两个标签之间的提交（新的在前，跳过合并提交），每个提交取说明的第一行
+func (c *Client) compareNotes(owner, repo, base, head string) (string, error) {
+	c.usage.add(usageNotes)
+	comparison, _, err := c.client.Repositories.CompareCommits(c.ctx, owner, repo, base, head, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	skipped := 0
+	commits := comparison.Commits
+	for i := len(commits) - 1; i >= 0; i-- {
+		commit := commits[i]
+		if len(commit.Parents) > 1 {
+			continue
+		}
+		if len(lines) == maxGeneratedCommits {
+			skipped++
+			continue
+		}
+		message, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+		line := fmt.Sprintf("- %s (%.7s)", strings.TrimSpace(message), commit.GetSHA())
+		if login := commit.GetAuthor().GetLogin(); login != "" {
+			line += " @" + login
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	// 对比接口最多返回250个提交，总数以 total_commits 为准
+	if total := comparison.GetTotalCommits(); total > len(commits) {
+		skipped += total - len(commits)
+	}
+	if skipped > 0 {
+		lines = append(lines, fmt.Sprintf("- ……以及其他 %d 个提交", skipped))
+	}
+	return strings.Join(lines, "\n"), nil
+}
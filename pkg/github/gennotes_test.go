@@ -0,0 +1,91 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// notesClient 启动模拟API：generate-notes 接口返回 status（200时返回生成的说明），v1.0.0...v1.1.0 之间有三个提交
+func notesClient(t *testing.T, status int) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/releases/generate-notes", func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"name": "v1.1.0", "body": "## What's Changed\n* Add feature by @a"})
+	})
+	mux.HandleFunc("/repos/o/r/releases", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"tag_name": "v1.1.0"}, {"tag_name": "v1.1.0-draft", "draft": true}, {"tag_name": "v1.0.0"},
+		})
+	})
+	mux.HandleFunc("/repos/o/r/compare/v1.0.0...v1.1.0", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"total_commits": 3,
+			"commits": []map[string]interface{}{
+				{"sha": "1111111aaaa", "commit": map[string]string{"message": "修复崩溃\n\n详细说明"}, "author": map[string]string{"login": "a"}},
+				{"sha": "2222222bbbb", "commit": map[string]string{"message": "Merge pull request #2"}, "parents": []map[string]string{{"sha": "1"}, {"sha": "2"}}},
+				{"sha": "3333333cccc", "commit": map[string]string{"message": "security: 升级依赖"}},
+			},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	store, err := util.NewStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := github.NewClient(nil)
+	api.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: api, ctx: t.Context(), store: store}
+}
+
+// TestFillEmptyNotes 测试按提交对比生成说明、generate-notes 接口失败时回退，以及已有说明时不生成
+func TestFillEmptyNotes(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Highlight.Keywords = []string{"security"}
+
+	// 首次发现的仓库使用上一个Release（跳过草稿）作为对比的起点
+	cfg.GitHub.GenerateNotes = config.GenerateNotesCompare
+	release := &ReleaseInfo{Event: EventRelease, Owner: "o", Repository: "r", TagName: "v1.1.0"}
+	notesClient(t, http.StatusOK).fillEmptyNotes(release, cfg)
+	want := "- security: 升级依赖 (3333333)\n- 修复崩溃 (1111111) @a"
+	if release.Description != want || !release.NotesGenerated {
+		t.Errorf("生成的说明为 %q，期望 %q", release.Description, want)
+	}
+	if len(release.Highlights) != 1 {
+		t.Errorf("高亮关键字为 %v", release.Highlights)
+	}
+
+	cfg.GitHub.GenerateNotes = config.GenerateNotesAPI
+	release = &ReleaseInfo{Event: EventRelease, Owner: "o", Repository: "r", TagName: "v1.1.0", PreviousTag: "v1.0.0"}
+	notesClient(t, http.StatusOK).fillEmptyNotes(release, cfg)
+	if release.Description != "## What's Changed\n* Add feature by @a" {
+		t.Errorf("generate-notes 生成的说明为 %q", release.Description)
+	}
+
+	release = &ReleaseInfo{Event: EventRelease, Owner: "o", Repository: "r", TagName: "v1.1.0", PreviousTag: "v1.0.0"}
+	notesClient(t, http.StatusForbidden).fillEmptyNotes(release, cfg)
+	if release.Description != want {
+		t.Errorf("generate-notes 失败后生成的说明为 %q，期望按提交生成", release.Description)
+	}
+
+	release = &ReleaseInfo{Event: EventRelease, Owner: "o", Repository: "r", TagName: "v1.1.0", PreviousTag: "v1.0.0", Description: "原有说明"}
+	notesClient(t, http.StatusOK).fillEmptyNotes(release, cfg)
+	if release.Description != "原有说明" || release.NotesGenerated {
+		t.Errorf("已有说明时不应生成: %q", release.Description)
+	}
+}
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+)
+
+// gistStateOwner Gist在状态文件中使用的 owner，记录的版本为Gist最新的修订号
+const gistStateOwner = "gist"
+
+// ParseGistID 从Gist ID或地址（如 https://gist.github.com/user/abc123）中解析出Gist ID
+func ParseGistID(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if u, err := url.Parse(s); err == nil && u.Host != "" {
+		s = path.Base(strings.TrimSuffix(u.Path, "/"))
+	}
+	if s == "" || s == "." || s == "/" || strings.ContainsAny(s, "/?#") {
+		return "", fmt.Errorf("无效的Gist: %s", s)
+	}
+	return s, nil
+}
+
+// checkGists 检查配置中的Gist是否有新的修订，每个Gist以最新的修订号去重
+// 分片运行时按Gist ID划分到各个分片
+func (c *Client) checkGists(cfg *config.Config, loc *time.Location) []*ReleaseInfo {
+	since := c.now().In(loc).AddDate(0, 0, -cfg.GitHub.CheckDays)
+
+	var ids []string
+	for _, entry := range cfg.GitHub.Gists {
+		id, err := ParseGistID(entry)
+		if err != nil {
+			fmt.Printf("跳过Gist: %v\n", err)
+			continue
+		}
+		if cfg.Shard.Enabled() && !inShard(config.RepoConfig{Owner: gistStateOwner, Name: id}, cfg.Shard) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	fmt.Printf("正在检查 %d 个Gist...\n", len(ids))
+
+	var results []*ReleaseInfo
+	for _, id := range ids {
+		info, err := c.checkGist(id, since, loc)
+		if err != nil {
+			fmt.Printf("检查Gist %s 失败: %v\n", id, err)
+			continue
+		}
+		if info != nil {
+			fmt.Printf("发现Gist更新: %s (%s)\n", info.HTMLURL, info.TagName)
+			results = append(results, info)
+		}
+	}
+	return results
+}
+
+// checkGist 判断Gist最新的修订是否需要通知，没有新修订或修订早于检查期限时返回nil
+func (c *Client) checkGist(id string, since time.Time, loc *time.Location) (*ReleaseInfo, error) {
+	c.usage.add(usageGists)
+	commits, _, err := c.client.Gists.ListCommits(c.ctx, id, &github.ListOptions{PerPage: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	latest := commits[0]
+	revision := latest.GetVersion()
+	committedAt := latest.GetCommittedAt().Time
+	if revision == "" || committedAt.Before(since) || c.store.GetLatestTag(gistStateOwner, id) == revision {
+		return nil, nil
+	}
+
+	// 先获取文件列表，再记录修订号，避免获取失败时丢失通知
+	c.usage.add(usageGists)
+	gist, _, err := c.client.Gists.Get(c.ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	isNew, err := c.store.CheckAndUpdateIfNew(gistStateOwner, id, revision)
+	if err != nil || !isNew {
+		return nil, err
+	}
+
+	return buildGistInfo(gist, latest, loc), nil
+}
+
+// buildGistInfo 构建Gist更新的通知，仓库名称为Gist的第一个文件名，版本为修订号的前7位
+func buildGistInfo(gist *github.Gist, commit *github.GistCommit, loc *time.Location) *ReleaseInfo {
+	files := make([]string, 0, len(gist.Files))
+	for name := range gist.Files {
+		files = append(files, string(name))
+	}
+	sort.Strings(files)
+
+	name := gist.GetID()
+	if len(files) > 0 {
+		name = files[0]
+	}
+
+	revision := commit.GetVersion()
+	if len(revision) > 7 {
+		revision = revision[:7]
+	}
+
+	title := strings.TrimSpace(gist.GetDescription())
+	if title == "" {
+		title = name
+	}
+
+	status := commit.GetChangeStatus()
+	description := fmt.Sprintf("共 %d 个文件: %s", len(files), strings.Join(files, ", "))
+	if status != nil {
+		description += fmt.Sprintf("（+%d -%d）", status.GetAdditions(), status.GetDeletions())
+	}
+
+	return &ReleaseInfo{
+		Event:       EventGistUpdated,
+		Source:      SourceGist,
+		Owner:       gist.GetOwner().GetLogin(),
+		Repository:  name,
+		TagName:     revision,
+		Name:        title,
+		Description: description,
+		HTMLURL:     gist.GetHTMLURL() + "/revisions",
+		PublishedAt: commit.GetCommittedAt().Time.In(loc),
+	}
+}
@@ -0,0 +1,106 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+)
+
+// TestParseGistID 测试从ID和地址中解析Gist ID
+func TestParseGistID(t *testing.T) {
+	for input, want := range map[string]string{
+		"aa5a315d61ae9438b18d":                                  "aa5a315d61ae9438b18d",
+		"https://gist.github.com/octocat/aa5a315d61ae9438b18d":  "aa5a315d61ae9438b18d",
+		"https://gist.github.com/octocat/aa5a315d61ae9438b18d/": "aa5a315d61ae9438b18d",
+	} {
+		got, err := ParseGistID(input)
+		if err != nil || got != want {
+			t.Errorf("ParseGistID(%q) = %q, %v，期望 %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseGistID(""); err == nil {
+		t.Error("空的Gist应返回错误")
+	}
+}
+
+// gistClient 启动返回一个Gist的模拟API，返回使用该API和临时状态文件的客户端
+func gistClient(t *testing.T, committed time.Time) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/gists/abc/commits", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]interface{}{{
+			"version":       "57a7f021a713b1c5a6a199b54cc514735d2d462f",
+			"committed_at":  committed.UTC().Format(time.RFC3339),
+			"change_status": map[string]int{"additions": 3, "deletions": 1},
+		}})
+	})
+	mux.HandleFunc("/gists/abc", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":          "abc",
+			"description": "部署脚本",
+			"html_url":    "https://gist.github.com/octocat/abc",
+			"owner":       map[string]string{"login": "octocat"},
+			"files":       map[string]interface{}{"install.sh": map[string]string{}, "README.md": map[string]string{}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	store, err := util.NewStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := github.NewClient(nil)
+	api.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: api, ctx: t.Context(), store: store}
+}
+
+// TestCheckGist 测试新修订通知一次，相同修订不再通知
+func TestCheckGist(t *testing.T) {
+	c := gistClient(t, time.Now().Add(-time.Hour))
+
+	since := time.Now().AddDate(0, 0, -3)
+	info, err := c.checkGist("abc", since, time.UTC)
+	if err != nil || info == nil {
+		t.Fatalf("新修订应通知: %v", err)
+	}
+	if info.Event != EventGistUpdated || info.Owner != "octocat" || info.Repository != "README.md" || info.TagName != "57a7f02" || info.Name != "部署脚本" {
+		t.Errorf("通知内容不正确: %+v", info)
+	}
+	if info.Description != "共 2 个文件: README.md, install.sh（+3 -1）" {
+		t.Errorf("变更说明不正确: %s", info.Description)
+	}
+
+	if info, err := c.checkGist("abc", since, time.UTC); err != nil || info != nil {
+		t.Errorf("相同修订不应重复通知: %+v, %v", info, err)
+	}
+}
+
+// TestCheckGists_Window 测试按客户端的时钟计算检查时间窗口
+func TestCheckGists_Window(t *testing.T) {
+	committed := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	c := gistClient(t, committed)
+	cfg := &config.Config{}
+	cfg.GitHub.CheckDays = 7
+	cfg.GitHub.Gists = []string{"abc"}
+
+	c.clock = clock.NewFake(committed.AddDate(0, 0, 8))
+	if got := c.checkGists(cfg, time.UTC); len(got) != 0 {
+		t.Errorf("超出检查时间窗口的修订不应通知: %+v", got)
+	}
+
+	c.clock = clock.NewFake(committed.AddDate(0, 0, 6))
+	if got := c.checkGists(cfg, time.UTC); len(got) != 1 {
+		t.Errorf("检查时间窗口内的修订应通知，实际 %d 条", len(got))
+	}
+}
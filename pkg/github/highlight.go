@@ -0,0 +1,38 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindHighlights 返回在发布说明中出现的高亮关键字（不区分大小写）
+func FindHighlights(body string, keywords []string) []string {
+	if body == "" || len(keywords) == 0 {
+		return nil
+	}
+
+	lower := strings.ToLower(body)
+	var matched []string
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		if strings.Contains(lower, strings.ToLower(keyword)) {
+			matched = append(matched, keyword)
+		}
+	}
+	return matched
+}
+
+// IsHighlighted 发布说明中是否包含高亮关键字
+func (r *ReleaseInfo) IsHighlighted() bool {
+	return len(r.Highlights) > 0
+}
+
+// HighlightBanner 返回高亮提示文本，未命中关键字时返回空字符串
+func (r *ReleaseInfo) HighlightBanner() string {
+	if !r.IsHighlighted() {
+		return ""
+	}
+	return fmt.Sprintf("⚠️ 注意: 发布说明包含关键字 %s", strings.Join(r.Highlights, ", "))
+}
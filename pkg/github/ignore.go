@@ -0,0 +1,152 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// ignoreFile 忽略列表文件名，位于 ~/.notify 目录，每个租户一个文件，同一租户的所有分片共用
+const ignoreFile = "ignored.json"
+
+// IgnoredRelease 被标记为已处理或忽略的版本
+type IgnoredRelease struct {
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	TagName    string `json:"tag"`
+	// Reason 忽略原因，仅用于展示
+	Reason  string    `json:"reason,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+	// ExpiresAt 到期时间，零值表示一直忽略；到期后如果版本仍在检查范围内会照常通知
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// String 返回 owner/repo@tag 形式
+func (r IgnoredRelease) String() string {
+	return fmt.Sprintf("%s/%s@%s", r.Owner, r.Repository, r.TagName)
+}
+
+// Expired 是否已到期
+func (r IgnoredRelease) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
+// IgnoreList 持久化的版本忽略列表
+type IgnoreList struct {
+	path    string
+	entries []IgnoredRelease
+}
+
+// ParseReleaseRef 解析 owner/repo@tag 形式的版本引用
+func ParseReleaseRef(ref string) (owner, repo, tag string, err error) {
+	name, tag, ok := strings.Cut(ref, "@")
+	owner, repo, slash := strings.Cut(name, "/")
+	if !ok || !slash || owner == "" || repo == "" || tag == "" || strings.Contains(repo, "/") {
+		return "", "", "", fmt.Errorf("无效的版本引用 %s，格式应为 owner/repo@tag", ref)
+	}
+	return owner, repo, tag, nil
+}
+
+// LoadIgnoreList 加载 ~/.notify/ignored.json，文件不存在时返回空列表
+// suffix 为租户后缀（config.Config.TenantSuffix），不为空时加载该租户的 ignored.tenant-<名称>.json
+func LoadIgnoreList(suffix string) (*IgnoreList, error) {
+	path, err := util.ResolvePath("", ignoreFile, suffix)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &IgnoreList{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return list, nil
+		}
+		return nil, fmt.Errorf("读取忽略列表失败: %v", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &list.entries); err != nil {
+			return nil, fmt.Errorf("解析忽略列表失败: %v", err)
+		}
+	}
+
+	return list, nil
+}
+
+// Match 返回版本是否在忽略列表中且未到期，列表为nil时返回false
+func (l *IgnoreList) Match(owner, repo, tag string) (IgnoredRelease, bool) {
+	if l == nil {
+		return IgnoredRelease{}, false
+	}
+
+	now := time.Now()
+	for _, entry := range l.entries {
+		if strings.EqualFold(entry.Owner, owner) &&
+			strings.EqualFold(entry.Repository, repo) &&
+			entry.TagName == tag &&
+			!entry.Expired(now) {
+			return entry, true
+		}
+	}
+	return IgnoredRelease{}, false
+}
+
+// Add 添加或更新忽略的版本
+func (l *IgnoreList) Add(entry IgnoredRelease) {
+	l.Remove(entry.Owner, entry.Repository, entry.TagName)
+	l.entries = append(l.entries, entry)
+}
+
+// Remove 移除忽略的版本，返回是否存在
+func (l *IgnoreList) Remove(owner, repo, tag string) bool {
+	kept := l.entries[:0]
+	removed := false
+	for _, entry := range l.entries {
+		if strings.EqualFold(entry.Owner, owner) &&
+			strings.EqualFold(entry.Repository, repo) &&
+			entry.TagName == tag {
+			removed = true
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	l.entries = kept
+	return removed
+}
+
+// Entries 返回未到期的忽略版本，按 owner/repo@tag 排序
+func (l *IgnoreList) Entries() []IgnoredRelease {
+	now := time.Now()
+	var entries []IgnoredRelease
+	for _, entry := range l.entries {
+		if !entry.Expired(now) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].String() < entries[j].String()
+	})
+	return entries
+}
+
+// Save 保存忽略列表，同时清理已到期的条目
+func (l *IgnoreList) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	data, err := json.MarshalIndent(l.Entries(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化忽略列表失败: %v", err)
+	}
+	if err := os.WriteFile(l.path, data, 0644); err != nil {
+		return fmt.Errorf("保存忽略列表失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package github
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// TestParseReleaseRef 测试版本引用解析
+func TestParseReleaseRef(t *testing.T) {
+	owner, repo, tag, err := ParseReleaseRef("orange-juzipi/notify@v2.0.0")
+	if err != nil || owner != "orange-juzipi" || repo != "notify" || tag != "v2.0.0" {
+		t.Errorf("解析结果 %s %s %s %v 不正确", owner, repo, tag, err)
+	}
+
+	for _, ref := range []string{"notify@v1", "a/b", "a/b@", "/b@v1", "a/b/c@v1"} {
+		if _, _, _, err := ParseReleaseRef(ref); err == nil {
+			t.Errorf("ParseReleaseRef(%q) 应返回错误", ref)
+		}
+	}
+}
+
+// TestIgnoreListMatch 测试忽略列表匹配和到期
+func TestIgnoreListMatch(t *testing.T) {
+	list := &IgnoreList{}
+	list.Add(IgnoredRelease{Owner: "Foo", Repository: "Bar", TagName: "v2.0.0"})
+	list.Add(IgnoredRelease{Owner: "foo", Repository: "old", TagName: "v1.0.0", ExpiresAt: time.Now().Add(-time.Minute)})
+
+	if _, ok := list.Match("foo", "bar", "v2.0.0"); !ok {
+		t.Error("仓库名应不区分大小写匹配")
+	}
+	if _, ok := list.Match("foo", "bar", "v2.0.1"); ok {
+		t.Error("其他版本不应被忽略")
+	}
+	if _, ok := list.Match("foo", "old", "v1.0.0"); ok {
+		t.Error("已到期的条目不应生效")
+	}
+	if len(list.Entries()) != 1 {
+		t.Errorf("Entries 应只返回未到期的条目，实际 %d 条", len(list.Entries()))
+	}
+
+	var none *IgnoreList
+	if _, ok := none.Match("foo", "bar", "v2.0.0"); ok {
+		t.Error("nil 列表不应匹配")
+	}
+}
+
+// TestLoadIgnoreList_Tenant 每个租户的忽略列表分开保存，同一租户的分片共用
+func TestLoadIgnoreList_Tenant(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	teamA := &config.Config{TenantName: "team-a", Shard: config.ShardConfig{Index: 1, Total: 2}}
+	list, err := LoadIgnoreList(teamA.TenantSuffix())
+	if err != nil {
+		t.Fatalf("加载忽略列表失败: %v", err)
+	}
+	list.Add(IgnoredRelease{Owner: "foo", Repository: "bar", TagName: "v2.0.0"})
+	if err := list.Save(); err != nil {
+		t.Fatalf("保存忽略列表失败: %v", err)
+	}
+
+	for _, tc := range []struct {
+		cfg  *config.Config
+		want bool
+	}{
+		{&config.Config{TenantName: "team-a", Shard: config.ShardConfig{Index: 2, Total: 2}}, true},
+		{&config.Config{TenantName: "team-b"}, false},
+		{&config.Config{}, false},
+	} {
+		list, err := LoadIgnoreList(tc.cfg.TenantSuffix())
+		if err != nil {
+			t.Fatalf("加载忽略列表失败: %v", err)
+		}
+		if _, ok := list.Match("foo", "bar", "v2.0.0"); ok != tc.want {
+			t.Errorf("租户 %q 分片 %s: 匹配结果为 %v，期望 %v", tc.cfg.TenantName, tc.cfg.Shard, ok, tc.want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+)
+
+// checkLabeledIssues 检查仓库中带有指定标签的Issue是否有新建或关闭
+func (c *Client) checkLabeledIssues(repos []config.RepoConfig, cfg *config.Config, loc *time.Location) []*ReleaseInfo {
+	since := c.now().In(loc).AddDate(0, 0, -cfg.GitHub.CheckDays)
+	labels := cfg.GitHub.WatchLabels.Labels
+
+	fmt.Printf("正在检查 %d 个仓库中带有标签 %v 的Issue...\n", len(repos), labels)
+
+	var results []*ReleaseInfo
+	for _, repo := range repos {
+		// GitHub的labels过滤条件是"同时包含"，因此每个标签单独查询
+		seen := make(map[int]bool)
+		for _, label := range labels {
+			issues, err := c.listIssuesWithLabel(repo.Owner, repo.Name, label, since)
+			if err != nil {
+				fmt.Printf("获取仓库 %s/%s 的Issue失败: %v\n", repo.Owner, repo.Name, err)
+				break
+			}
+
+			for _, issue := range issues {
+				if seen[issue.GetNumber()] {
+					continue
+				}
+				seen[issue.GetNumber()] = true
+
+				info, err := c.checkIssue(repo, issue, since, loc)
+				if err != nil {
+					fmt.Printf("检查Issue %s/%s#%d 状态失败: %v\n", repo.Owner, repo.Name, issue.GetNumber(), err)
+					continue
+				}
+				if info != nil {
+					fmt.Printf("发现Issue变化: %s/%s #%d (%s)\n", repo.Owner, repo.Name, issue.GetNumber(), issue.GetState())
+					results = append(results, info)
+				}
+			}
+		}
+	}
+
+	return results
+}
+
+// listIssuesWithLabel 获取指定时间后更新过的、带有指定标签的Issue（不含PR）
+func (c *Client) listIssuesWithLabel(owner, repo, label string, since time.Time) ([]*github.Issue, error) {
+	opt := &github.IssueListByRepoOptions{
+		State:       "all",
+		Labels:      []string{label},
+		Since:       since,
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	var all []*github.Issue
+	for {
+		c.usage.add(usageIssues)
+		issues, resp, err := c.client.Issues.ListByRepo(c.ctx, owner, repo, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if !issue.IsPullRequest() {
+				all = append(all, issue)
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return all, nil
+}
+
+// checkIssue 判断Issue的新建/关闭是否需要通知
+func (c *Client) checkIssue(repo config.RepoConfig, issue *github.Issue, since time.Time, loc *time.Location) (*ReleaseInfo, error) {
+	state := issue.GetState()
+
+	event, at := EventIssueOpened, issue.GetCreatedAt().Time
+	if state == "closed" {
+		event, at = EventIssueClosed, issue.GetClosedAt().Time
+	}
+
+	// 只通知检查期限内发生的新建/关闭
+	if at.Before(since) {
+		return nil, nil
+	}
+
+	// 每个Issue以 "repo#编号" 在Issue状态中记录最近通知过的状态
+	isNew, err := c.issues.CheckAndUpdateIfNew(repo.Owner, fmt.Sprintf("%s#%d", repo.Name, issue.GetNumber()), state)
+	if err != nil || !isNew {
+		return nil, err
+	}
+
+	return &ReleaseInfo{
+		Event:       event,
+		Owner:       repo.Owner,
+		Repository:  repo.Name,
+		TagName:     fmt.Sprintf("#%d %s", issue.GetNumber(), issue.GetTitle()),
+		Name:        issue.GetTitle(),
+		HTMLURL:     issue.GetHTMLURL(),
+		PublishedAt: at.In(loc),
+	}, nil
+}
@@ -0,0 +1,54 @@
+package github
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// TestCheckIssue_SeparateState Issue状态记录在单独的文件中，旧版本记录在版本状态中的Issue状态在创建客户端时迁移
+func TestCheckIssue_SeparateState(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "state.json")
+	legacy, err := util.NewStateStore(storePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := legacy.UpdateState("o", "r", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := legacy.UpdateState("o", "r#1", "open"); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := newClientWith(nil, t.Context(), storePath, util.StoreOptions{})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	if states := c.store.Snapshot(); len(states) != 1 || states[0].Repository != "r" {
+		t.Errorf("版本状态为 %+v，期望只有 o/r", states)
+	}
+
+	now := time.Now()
+	repo := config.RepoConfig{Owner: "o", Name: "r"}
+	since := now.Add(-time.Hour)
+	opened := &github.Issue{Number: github.Ptr(1), State: github.Ptr("open"), Title: github.Ptr("崩溃"), CreatedAt: &github.Timestamp{Time: now}}
+	if info, err := c.checkIssue(repo, opened, since, time.UTC); err != nil || info != nil {
+		t.Errorf("迁移过的Issue状态应不再通知，返回 %v（%v）", info, err)
+	}
+
+	closed := &github.Issue{Number: github.Ptr(2), State: github.Ptr("closed"), Title: github.Ptr("文档"), ClosedAt: &github.Timestamp{Time: now}}
+	info, err := c.checkIssue(repo, closed, since, time.UTC)
+	if err != nil || info == nil || info.Event != EventIssueClosed {
+		t.Fatalf("关闭的Issue返回 %+v（%v），期望 %s 事件", info, err, EventIssueClosed)
+	}
+	if tag := c.store.GetLatestTag("o", "r#2"); tag != "" {
+		t.Errorf("Issue状态不应记录在版本状态中，实际为 %q", tag)
+	}
+	if tag := c.issues.GetLatestTag("o", "r#2"); tag != "closed" {
+		t.Errorf("Issue状态为 %q，期望 closed", tag)
+	}
+}
@@ -0,0 +1,39 @@
+package github
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// CompareURL 返回与上一个通知过的版本的对比页面地址，非GitHub来源、非新版本事件或没有上一个版本时返回空字符串
+func (r *ReleaseInfo) CompareURL() string {
+	if r.Source != "" && r.Source != SourceGitHub {
+		return ""
+	}
+	switch r.Event {
+	case EventRelease, EventPromoted, EventTagPushed, EventTagReleased:
+	default:
+		return ""
+	}
+	if r.PreviousTag == "" || r.PreviousTag == r.TagName {
+		return ""
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/compare/%s...%s",
+		r.Owner, r.Repository, url.PathEscape(r.PreviousTag), url.PathEscape(r.TagName))
+}
+
+// Link 返回版本页面地址，配置了短链接服务时返回短链接
+func (r *ReleaseInfo) Link() string {
+	if r.ShortURL != "" {
+		return r.ShortURL
+	}
+	return r.HTMLURL
+}
+
+// CompareLink 返回对比页面地址，配置了短链接服务时返回短链接
+func (r *ReleaseInfo) CompareLink() string {
+	if r.ShortCompareURL != "" {
+		return r.ShortCompareURL
+	}
+	return r.CompareURL()
+}
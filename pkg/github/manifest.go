@@ -0,0 +1,101 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/manifest"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// loadManifestRepos 解析依赖清单，将直接依赖映射为要监控的GitHub仓库
+// 同时返回解析失败的清单和依赖数
+func (c *Client) loadManifestRepos(paths []string) ([]config.RepoConfig, int) {
+	var repos []config.RepoConfig
+	failures := 0
+	resolver := manifest.NewResolver(c.output())
+
+	for _, path := range paths {
+		c.printf("正在解析依赖清单 %s...\n", path)
+		deps, err := manifest.Parse(path)
+		if err != nil {
+			c.printf("解析依赖清单 %s 失败: %v\n", path, err)
+			failures++
+			continue
+		}
+
+		resolved, failed := resolver.Resolve(deps)
+		failures += failed
+		c.printf("依赖清单 %s: 共 %d 个直接依赖，解析到 %d 个GitHub仓库\n", path, len(deps), len(resolved))
+
+		for _, dep := range resolved {
+			repos = append(repos, config.RepoConfig{
+				Owner:         dep.Owner,
+				Name:          dep.Repo,
+				PinnedVersion: dep.Version,
+			})
+		}
+	}
+
+	return repos, failures
+}
+
+// normalizeVersion 去掉版本号前缀的 v，便于比较
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+// applyPinnedVersion 将仓库配置中的锁定版本信息写入版本发布信息
+func applyPinnedVersion(release *ReleaseInfo, repo config.RepoConfig) {
+	if repo.PinnedVersion == "" {
+		return
+	}
+	release.PinnedVersion = repo.PinnedVersion
+
+	pinned, errPinned := version.Parse(repo.PinnedVersion)
+	latest, errLatest := version.Parse(release.TagName)
+	if errPinned != nil || errLatest != nil {
+		// 无法按语义化版本比较时，退化为字符串比较
+		release.AffectsPinned = normalizeVersion(release.TagName) != normalizeVersion(repo.PinnedVersion)
+		return
+	}
+
+	release.VersionGap = version.Gap(pinned, latest)
+	release.AffectsPinned = release.VersionGap != ""
+}
+
+// PinnedStatus 返回锁定版本的展示文本，未锁定时返回空字符串
+func (r *ReleaseInfo) PinnedStatus() string {
+	if r.PinnedVersion == "" {
+		return ""
+	}
+	if r.VersionGap != "" {
+		return fmt.Sprintf("%s（%s）", r.PinnedVersion, r.VersionGap)
+	}
+	if r.AffectsPinned {
+		return fmt.Sprintf("%s（需要升级）", r.PinnedVersion)
+	}
+	return fmt.Sprintf("%s（无需升级）", r.PinnedVersion)
+}
+
+// UpgradeKind 返回新版本相对于锁定版本（未锁定时为之前通知过的版本）的升级类型
+// 没有可比较的版本或无法解析时返回 version.BumpNone
+func (r *ReleaseInfo) UpgradeKind() version.BumpKind {
+	base := r.PinnedVersion
+	if base == "" {
+		base = r.PreviousTag
+	}
+	if base == "" {
+		return version.BumpNone
+	}
+
+	from, errFrom := version.Parse(base)
+	to, errTo := version.Parse(r.TagName)
+	if errFrom != nil || errTo != nil {
+		return version.BumpNone
+	}
+
+	kind, _ := version.Bump(from, to)
+	return kind
+}
@@ -0,0 +1,74 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// TestDiscoverRepos_Precedence 自动发现的仓库覆盖配置中的同名仓库，依赖清单只补充锁定版本和新的仓库
+func TestDiscoverRepos_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/user/starred", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"repo": map[string]interface{}{"id": 42, "name": "r", "owner": map[string]string{"login": "o"}}},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	gomod := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(gomod, []byte("module example.com/app\n\nrequire (\n\tgithub.com/o/r v1.2.3\n\tgithub.com/o/m v0.1.0\n)\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := util.NewStateStore(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := github.NewClient(nil)
+	api.BaseURL, _ = url.Parse(server.URL + "/")
+	c := &Client{client: api, ctx: t.Context(), store: store}
+
+	cfg := &config.Config{}
+	cfg.GitHub.Repos = []config.RepoConfig{{Owner: "o", Name: "r"}, {Owner: "o", Name: "manual"}}
+	cfg.GitHub.WatchStarred = true
+	cfg.GitHub.Manifests = []string{gomod}
+
+	repos, sources, err := c.discoverRepos(cfg)
+	if err != nil {
+		t.Fatalf("发现仓库失败: %v", err)
+	}
+	byKey := make(map[string]config.RepoConfig)
+	for _, repo := range repos {
+		byKey[repo.Owner+"/"+repo.Name] = repo
+	}
+	if len(byKey) != 3 {
+		t.Fatalf("发现 %d 个仓库，期望 3 个: %+v", len(byKey), repos)
+	}
+
+	for _, tc := range []struct {
+		key, source, pinned string
+		id                  int64
+	}{
+		{"o/r", WatchSourceStarred, "1.2.3", 42},
+		{"o/manual", WatchSourceManual, "", 0},
+		{"o/m", WatchSourceManifest, "0.1.0", 0},
+	} {
+		repo := byKey[tc.key]
+		if sources[tc.key] != tc.source || repo.ID != tc.id || normalizeVersion(repo.PinnedVersion) != tc.pinned {
+			t.Errorf("%s: 来源 %q、ID %d、锁定版本 %q，期望 %q、%d、%q", tc.key, sources[tc.key], repo.ID, repo.PinnedVersion, tc.source, tc.id, tc.pinned)
+		}
+	}
+}
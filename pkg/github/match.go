@@ -0,0 +1,26 @@
+package github
+
+import (
+	"path"
+	"strings"
+)
+
+// IsNewVersion 是否为新版本事件（新版本、预发布转正式版、新推送的标签）
+func (r *ReleaseInfo) IsNewVersion() bool {
+	switch r.Event {
+	case EventRelease, EventPromoted, EventTagPushed:
+		return true
+	}
+	return false
+}
+
+// MatchesRepo 仓库是否匹配 owner/repo 形式的规则之一，支持 * 通配（如 kubernetes/*），不区分大小写
+func (r *ReleaseInfo) MatchesRepo(patterns []string) bool {
+	name := strings.ToLower(r.Owner + "/" + r.Repository)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
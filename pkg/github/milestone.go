@@ -0,0 +1,89 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// milestoneLevels 升级类型对应的级别，级别越高升级幅度越大
+var milestoneLevels = map[version.BumpKind]int{
+	version.BumpPrerelease: 0,
+	version.BumpPatch:      1,
+	version.BumpMinor:      2,
+	version.BumpMajor:      3,
+}
+
+// milestoneRuleFor 返回仓库配置的通知规则，未配置时返回空规则
+func milestoneRuleFor(cfg *config.Config, owner, repo string) (minBump string, everyNthPatch int) {
+	for _, r := range cfg.GitHub.Repos {
+		if strings.EqualFold(r.Owner, owner) && strings.EqualFold(r.Name, repo) {
+			return r.MinBump, r.EveryNthPatch
+		}
+	}
+	return "", 0
+}
+
+// milestoneAllows 判断新版本是否满足仓库的通知规则
+// kind 为相对于上一个检测到的版本的升级类型，skipped 为自上次通知以来跳过的补丁版本数
+// 无法判断升级类型（如首次检测或标签不是语义化版本）时照常通知
+func milestoneAllows(kind version.BumpKind, minBump string, everyNthPatch, skipped int) bool {
+	if minBump == "" && everyNthPatch <= 0 {
+		return true
+	}
+	level, ok := milestoneLevels[kind]
+	if !ok {
+		return true
+	}
+
+	// 配置了 every_nth_patch 时补丁版本累计到第N个才通知，不受 min_bump 限制
+	if kind == version.BumpPatch && everyNthPatch > 0 {
+		return skipped+1 >= everyNthPatch
+	}
+
+	if minBump == "" {
+		return true
+	}
+	return level >= milestoneLevels[version.BumpKind(minBump)]
+}
+
+// applyMilestoneRule 按仓库的通知规则判断是否通知新版本，并维护跳过的补丁版本计数
+// 被跳过的版本已经记录在状态中，不会在之后的运行中重复检测
+func (c *Client) applyMilestoneRule(cfg *config.Config, info *ReleaseInfo) bool {
+	minBump, everyNthPatch := milestoneRuleFor(cfg, info.Owner, info.Repository)
+	if minBump == "" && everyNthPatch <= 0 {
+		return true
+	}
+	// 预发布版本转为正式版属于之前版本的跟进通知，不受规则限制
+	if info.Event == EventPromoted {
+		return true
+	}
+
+	kind := version.BumpNone
+	if info.PreviousTag != "" {
+		from, errFrom := version.Parse(info.PreviousTag)
+		to, errTo := version.Parse(info.TagName)
+		if errFrom == nil && errTo == nil {
+			kind, _ = version.Bump(from, to)
+		}
+	}
+
+	skipped := c.store.SkippedCount(info.Owner, info.Repository)
+	if milestoneAllows(kind, minBump, everyNthPatch, skipped) {
+		if err := c.store.SetSkipped(info.Owner, info.Repository, 0); err != nil {
+			fmt.Printf("警告: 保存 %s/%s 的跳过计数失败: %v\n", info.Owner, info.Repository, err)
+		}
+		return true
+	}
+
+	if kind == version.BumpPatch {
+		skipped++
+		if err := c.store.SetSkipped(info.Owner, info.Repository, skipped); err != nil {
+			fmt.Printf("警告: 保存 %s/%s 的跳过计数失败: %v\n", info.Owner, info.Repository, err)
+		}
+	}
+	fmt.Printf("%s/%s 的 %s（%s 升级）未达到仓库的通知规则，跳过通知\n", info.Owner, info.Repository, info.TagName, kind)
+	return false
+}
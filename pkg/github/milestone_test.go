@@ -0,0 +1,39 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// TestMilestoneAllows 测试仓库通知规则的判断
+func TestMilestoneAllows(t *testing.T) {
+	cases := []struct {
+		kind          version.BumpKind
+		minBump       string
+		everyNthPatch int
+		skipped       int
+		want          bool
+	}{
+		{version.BumpPatch, "", 0, 0, true},
+		{version.BumpPatch, "minor", 0, 0, false},
+		{version.BumpMinor, "minor", 0, 0, true},
+		{version.BumpMajor, "minor", 0, 0, true},
+		{version.BumpMinor, "major", 0, 0, false},
+		{version.BumpPrerelease, "patch", 0, 0, false},
+		// 无法判断升级类型时照常通知
+		{version.BumpNone, "major", 0, 0, true},
+		// 每3个补丁版本通知一次
+		{version.BumpPatch, "", 3, 0, false},
+		{version.BumpPatch, "", 3, 1, false},
+		{version.BumpPatch, "", 3, 2, true},
+		{version.BumpPatch, "minor", 3, 2, true},
+		{version.BumpMinor, "", 3, 0, true},
+		{version.BumpMinor, "major", 3, 0, false},
+	}
+	for _, c := range cases {
+		if got := milestoneAllows(c.kind, c.minBump, c.everyNthPatch, c.skipped); got != c.want {
+			t.Errorf("milestoneAllows(%q, %q, %d, %d) = %v, 期望 %v", c.kind, c.minBump, c.everyNthPatch, c.skipped, got, c.want)
+		}
+	}
+}
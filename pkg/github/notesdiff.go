@@ -0,0 +1,71 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxDiffLines 发布说明差异摘要中最多展示的新增/删除行数
+const maxDiffLines = 5
+
+// notesChange 计算两版发布说明之间按行的变化比例（0~1）以及差异摘要
+func notesChange(oldBody, newBody string) (float64, string) {
+	oldLines := splitNonEmptyLines(oldBody)
+	newLines := splitNonEmptyLines(newBody)
+
+	oldSet := make(map[string]bool, len(oldLines))
+	for _, line := range oldLines {
+		oldSet[line] = true
+	}
+	newSet := make(map[string]bool, len(newLines))
+	for _, line := range newLines {
+		newSet[line] = true
+	}
+
+	var added, removed []string
+	for _, line := range newLines {
+		if !oldSet[line] {
+			added = append(added, line)
+		}
+	}
+	for _, line := range oldLines {
+		if !newSet[line] {
+			removed = append(removed, line)
+		}
+	}
+
+	total := len(oldLines) + len(newLines)
+	if total == 0 {
+		return 0, ""
+	}
+	ratio := float64(len(added)+len(removed)) / float64(total)
+
+	var summary strings.Builder
+	writeDiffLines(&summary, "+ ", added)
+	writeDiffLines(&summary, "- ", removed)
+
+	return ratio, strings.TrimRight(summary.String(), "\n")
+}
+
+// splitNonEmptyLines 按行拆分并去掉空行和首尾空白
+func splitNonEmptyLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// writeDiffLines 写入带前缀的差异行，超出上限时只写入数量提示
+func writeDiffLines(b *strings.Builder, prefix string, lines []string) {
+	for i, line := range lines {
+		if i == maxDiffLines {
+			fmt.Fprintf(b, "%s...（另有 %d 行）\n", prefix, len(lines)-maxDiffLines)
+			return
+		}
+		b.WriteString(prefix + line + "\n")
+	}
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// latestReleaseIncludingPrereleases 获取仓库最新发布的release（包括预发布版本，不包括草稿）
+// GetLatestRelease 接口会跳过预发布版本，这里改为读取最近的release列表
+func (c *Client) latestReleaseIncludingPrereleases(owner, repo string) (*github.RepositoryRelease, *github.Response, error) {
+	releases, resp, err := c.client.Repositories.ListReleases(c.ctx, owner, repo, &github.ListOptions{PerPage: 10})
+	if err != nil {
+		return nil, resp, err
+	}
+
+	var latest *github.RepositoryRelease
+	for _, r := range releases {
+		if r.GetDraft() {
+			continue
+		}
+		if latest == nil || r.GetPublishedAt().After(latest.GetPublishedAt().Time) {
+			latest = r
+		}
+	}
+	// 没有release时返回nil
+	return latest, resp, nil
+}
+
+// isPromotion 判断新标签是否为之前预发布版本的正式版，如 v1.2.0-rc.1 -> v1.2.0
+func isPromotion(previousTag, tag string) bool {
+	if previousTag == "" {
+		return false
+	}
+	prev, err := version.Parse(previousTag)
+	if err != nil || !prev.IsPrerelease() {
+		return false
+	}
+	cur, err := version.Parse(tag)
+	if err != nil || cur.IsPrerelease() {
+		return false
+	}
+	return prev.Core() == cur.Core()
+}
+
+// markPromotion 新版本是之前已通知的预发布版本转正时，改为发送"已转为正式版"的跟进通知
+func markPromotion(info *ReleaseInfo, previousTag string) {
+	if isPromotion(previousTag, info.TagName) {
+		info.Event = EventPromoted
+		info.PromotedFrom = previousTag
+	}
+}
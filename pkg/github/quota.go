@@ -0,0 +1,37 @@
+package github
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// Quota GitHub API核心配额状态
+type Quota struct {
+	Remaining int
+	Limit     int
+	Reset     time.Time
+}
+
+// GetQuota 查询token当前的API配额（查询速率限制本身不消耗配额）
+func GetQuota(cfg *config.Config) (*Quota, error) {
+	client, ctx, err := newAPIClient(cfg.GitHub.Token, cfg.Network.LocalAddr, cfg.GitHub.HTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	rl, _, err := client.RateLimit.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取API速率限制失败: %v", err)
+	}
+	if rl == nil || rl.Core == nil {
+		return nil, fmt.Errorf("获取API速率限制失败: 响应中没有配额信息")
+	}
+
+	return &Quota{
+		Remaining: rl.Core.Remaining,
+		Limit:     rl.Core.Limit,
+		Reset:     rl.Core.Reset.Time,
+	}, nil
+}
@@ -0,0 +1,154 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// renameRecord 检测到的一次仓库重命名或转移
+type renameRecord struct {
+	// To 新的 owner/name
+	To         string    `json:"to"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// repoKey 仓库的比较键，GitHub的仓库名不区分大小写
+func repoKey(owner, repo string) string {
+	return strings.ToLower(owner + "/" + repo)
+}
+
+// loadRenames 加载之前检测到的仓库重命名记录（renames.json，键为旧的 owner/name，小写）
+func (c *Client) loadRenames(cfg *config.Config) {
+	c.renameMu.Lock()
+	defer c.renameMu.Unlock()
+
+	if c.renames != nil {
+		return
+	}
+	c.renames = make(map[string]renameRecord)
+
+	path, err := util.ResolvePath("", "renames.json", cfg.DataSuffix())
+	if err != nil {
+		c.printf("警告: %v\n", err)
+		return
+	}
+	c.renamesPath = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			c.printf("警告: 读取仓库重命名记录失败: %v\n", err)
+		}
+		return
+	}
+	if err := json.Unmarshal(data, &c.renames); err != nil {
+		c.printf("警告: 解析仓库重命名记录失败: %v\n", err)
+	}
+}
+
+// applyRenames 将配置中已重命名或转移的仓库替换为新名称，并提示更新配置文件
+func (c *Client) applyRenames(repos []config.RepoConfig) []config.RepoConfig {
+	c.renameMu.Lock()
+	defer c.renameMu.Unlock()
+
+	if len(c.renames) == 0 {
+		return repos
+	}
+
+	result := make([]config.RepoConfig, 0, len(repos))
+	for _, repo := range repos {
+		if record, ok := c.renames[repoKey(repo.Owner, repo.Name)]; ok {
+			if owner, name, ok := strings.Cut(record.To, "/"); ok {
+				c.printf("提示: 配置中的仓库 %s/%s 已重命名或转移为 %s，本次按新名称检查，请更新配置文件\n",
+					repo.Owner, repo.Name, record.To)
+				repo.Owner, repo.Name = owner, name
+			}
+		}
+		result = append(result, repo)
+	}
+	return result
+}
+
+// recordRename 记录仓库重命名或转移，并把旧名称下的状态迁移到新名称，避免把新名称当作新仓库重复通知
+func (c *Client) recordRename(oldOwner, oldRepo, newOwner, newRepo string) {
+	migrated, err := c.store.MigrateRepo(oldOwner, oldRepo, newOwner, newRepo)
+	if err != nil {
+		fmt.Printf("警告: 迁移仓库 %s/%s 的状态失败: %v\n", oldOwner, oldRepo, err)
+	}
+	if c.issues != nil {
+		if _, err := c.issues.MigrateRepo(oldOwner, oldRepo, newOwner, newRepo); err != nil {
+			fmt.Printf("警告: 迁移仓库 %s/%s 的Issue状态失败: %v\n", oldOwner, oldRepo, err)
+		}
+	}
+
+	c.renameMu.Lock()
+	defer c.renameMu.Unlock()
+
+	if c.renames == nil {
+		c.renames = make(map[string]renameRecord)
+	}
+	to := newOwner + "/" + newRepo
+	if record, ok := c.renames[repoKey(oldOwner, oldRepo)]; ok && record.To == to {
+		return
+	}
+	c.renames[repoKey(oldOwner, oldRepo)] = renameRecord{To: to, DetectedAt: time.Now()}
+
+	if migrated {
+		fmt.Printf("⚠️ 仓库 %s/%s 已重命名或转移为 %s，已迁移其状态\n", oldOwner, oldRepo, to)
+	} else {
+		fmt.Printf("⚠️ 仓库 %s/%s 已重命名或转移为 %s\n", oldOwner, oldRepo, to)
+	}
+
+	if c.renamesPath == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.renamesPath), 0755); err != nil {
+		fmt.Printf("警告: 保存仓库重命名记录失败: %v\n", err)
+		return
+	}
+	data, err := json.MarshalIndent(c.renames, "", "  ")
+	if err == nil {
+		err = os.WriteFile(c.renamesPath, data, 0644)
+	}
+	if err != nil {
+		fmt.Printf("警告: 保存仓库重命名记录失败: %v\n", err)
+	}
+}
+
+// renamedTo 根据release的页面地址判断仓库是否已重命名或转移
+// 使用旧名称请求时GitHub返回301并重定向到新仓库，返回的release地址中是新的 owner/name
+func renamedTo(owner, repo, htmlURL string) (string, string, bool) {
+	u, err := url.Parse(htmlURL)
+	if err != nil {
+		return "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 3 || parts[2] != "releases" {
+		return "", "", false
+	}
+	if repoKey(parts[0], parts[1]) == repoKey(owner, repo) {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// migrateByRepoID 自动发现的仓库按仓库ID找回重命名前的状态
+// 新名称下还没有状态、但状态中有相同ID的旧名称时，视为同一个仓库
+func (c *Client) migrateByRepoID(repo config.RepoConfig) {
+	if repo.ID == 0 || c.store.GetLatestTag(repo.Owner, repo.Name) != "" {
+		return
+	}
+	oldOwner, oldRepo, ok := c.store.FindRepoByID(repo.ID)
+	if !ok || repoKey(oldOwner, oldRepo) == repoKey(repo.Owner, repo.Name) {
+		return
+	}
+	c.recordRename(oldOwner, oldRepo, repo.Owner, repo.Name)
+}
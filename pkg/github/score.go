@@ -0,0 +1,72 @@
+package github
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// scoreRelease 计算版本所属仓库的重要性分数，offline 为true时（匿名模式、订阅回退）不请求API，
+// 只按仓库权重和 owners 计算
+func (c *Client) scoreRelease(release *ReleaseInfo, r config.RepoConfig, cfg config.ScoringConfig, offline bool) {
+	if cfg.StarsWeight != 0 && !offline {
+		release.Stars = c.repoStars(release.Owner, release.Repository)
+	}
+
+	var login string
+	if cfg.OwnBonus != 0 && !offline {
+		login = c.authLogin(usageScoring)
+	}
+	release.Score = score(r.Weight, release.Stars, isOwnRepo(release.Owner, login, cfg.Owners), cfg)
+}
+
+// score 分数 = 仓库权重 + star数的数量级 × stars_weight + 自己的仓库的加分
+func score(weight float64, stars int, own bool, cfg config.ScoringConfig) float64 {
+	s := weight
+	if stars > 0 {
+		s += math.Log10(float64(stars)) * cfg.StarsWeight
+	}
+	if own {
+		s += cfg.OwnBonus
+	}
+	return s
+}
+
+// isOwnRepo 仓库是否属于Token对应的用户或配置的用户/组织
+func isOwnRepo(owner, login string, owners []string) bool {
+	if login != "" && strings.EqualFold(owner, login) {
+		return true
+	}
+	for _, o := range owners {
+		if strings.EqualFold(owner, o) {
+			return true
+		}
+	}
+	return false
+}
+
+// repoStars 返回仓库的star数，结果在本次运行中缓存，读取失败时返回0
+func (c *Client) repoStars(owner, repo string) int {
+	c.starsMu.Lock()
+	defer c.starsMu.Unlock()
+
+	key := repoKey(owner, repo)
+	if stars, ok := c.stars[key]; ok {
+		return stars
+	}
+
+	c.usage.add(usageScoring)
+	repository, _, err := c.client.Repositories.Get(c.ctx, owner, repo)
+	if err != nil {
+		fmt.Printf("警告: 读取 %s/%s 的star数失败: %v\n", owner, repo, err)
+		return 0
+	}
+
+	if c.stars == nil {
+		c.stars = make(map[string]int)
+	}
+	c.stars[key] = repository.GetStargazersCount()
+	return c.stars[key]
+}
@@ -0,0 +1,43 @@
+package github
+
+import (
+	"math"
+	"testing"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// TestScore 测试仓库权重、star数的数量级和自己的仓库的加分
+func TestScore(t *testing.T) {
+	cfg := config.ScoringConfig{Enabled: true, StarsWeight: 1, OwnBonus: 5}
+
+	for name, tc := range map[string]struct {
+		weight float64
+		stars  int
+		own    bool
+		want   float64
+	}{
+		"没有star":    {want: 0},
+		"1000 star": {stars: 1000, want: 3},
+		"权重和star":   {weight: 2, stars: 100, want: 4},
+		"负权重":       {weight: -10, stars: 10, want: -9},
+		"自己的仓库":     {stars: 10, own: true, want: 6},
+	} {
+		if got := score(tc.weight, tc.stars, tc.own, cfg); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("%s: 分数为 %v，期望 %v", name, got, tc.want)
+		}
+	}
+}
+
+// TestIsOwnRepo 测试按Token对应的用户和配置的 owners 判断自己的仓库（不区分大小写）
+func TestIsOwnRepo(t *testing.T) {
+	if !isOwnRepo("Alice", "alice", nil) {
+		t.Error("Token对应用户的仓库应视为自己的仓库")
+	}
+	if !isOwnRepo("my-org", "", []string{"My-Org"}) {
+		t.Error("owners 中组织的仓库应视为自己的仓库")
+	}
+	if isOwnRepo("golang", "alice", []string{"my-org"}) {
+		t.Error("其他用户的仓库不应视为自己的仓库")
+	}
+}
@@ -0,0 +1,26 @@
+package github
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// inShard 判断仓库是否属于当前分片（按 owner/repo 的哈希确定性划分）
+func inShard(repo config.RepoConfig, shard config.ShardConfig) bool {
+	h := fnv.New32a()
+	h.Write([]byte(fmt.Sprintf("%s/%s", repo.Owner, repo.Name)))
+	return int(h.Sum32()%uint32(shard.Total)) == shard.Index-1
+}
+
+// filterShard 返回属于当前分片的仓库
+func filterShard(repos []config.RepoConfig, shard config.ShardConfig) []config.RepoConfig {
+	var filtered []config.RepoConfig
+	for _, repo := range repos {
+		if inShard(repo, shard) {
+			filtered = append(filtered, repo)
+		}
+	}
+	return filtered
+}
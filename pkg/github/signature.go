@@ -0,0 +1,64 @@
+package github
+
+import (
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// signatureSuffixes 签名/证明类文件的常见后缀
+var signatureSuffixes = []string{
+	".sig",
+	".asc",
+	".pem",
+	".crt",
+	".minisig",
+	".sigstore",
+	".sigstore.json",
+	".bundle",
+	".intoto.jsonl",
+}
+
+// signatureKeywords 文件名中表示签名或来源证明的关键字
+var signatureKeywords = []string{
+	"provenance",
+	"attestation",
+}
+
+// isSignatureAsset 判断附件名是否为签名或证明文件
+func isSignatureAsset(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range signatureSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	for _, keyword := range signatureKeywords {
+		if strings.Contains(lower, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// findSignatureAssets 返回release附件中的签名/证明文件名
+func findSignatureAssets(assets []*github.ReleaseAsset) []string {
+	var names []string
+	for _, asset := range assets {
+		if isSignatureAsset(asset.GetName()) {
+			names = append(names, asset.GetName())
+		}
+	}
+	return names
+}
+
+// SignatureStatus 返回签名检测结果的展示文本，未检测时返回空字符串
+func (r *ReleaseInfo) SignatureStatus() string {
+	if !r.SignatureChecked {
+		return ""
+	}
+	if r.Signed {
+		return "✅ 是"
+	}
+	return "❌ 否"
+}
@@ -0,0 +1,27 @@
+package github
+
+import "testing"
+
+// TestIsSignatureAsset 测试签名文件识别
+func TestIsSignatureAsset(t *testing.T) {
+	cases := map[string]bool{
+		"notify_linux_amd64.tar.gz":        false,
+		"checksums.txt":                    false,
+		"checksums.txt.sig":                true,
+		"notify_linux_amd64.tar.gz.pem":    true,
+		"SHA256SUMS.asc":                   true,
+		"multiple.intoto.jsonl":            true,
+		"notify-v1.0.0.sigstore.json":      true,
+		"build-provenance.json":            true,
+		"notify_darwin_arm64.zip.minisig":  true,
+		"Notify_Windows_AMD64.ZIP.SIG":     true,
+		"attestation-bundle-v1.0.0.json":   true,
+		"notify_linux_amd64.tar.gz.sha256": false,
+	}
+
+	for name, want := range cases {
+		if got := isSignatureAsset(name); got != want {
+			t.Errorf("isSignatureAsset(%q) = %v，期望 %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package github
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// StoreOptions 根据配置生成状态存储选项
+func StoreOptions(cfg *config.Config) util.StoreOptions {
+	return util.StoreOptions{
+		Encrypt: cfg.State.Encrypt,
+		KeyEnv:  cfg.State.KeyEnv,
+		Keyring: cfg.State.Keyring,
+	}
+}
+
+// issueStatePath 返回Issue状态文件的路径，与版本状态文件同目录，如 state.issues.json
+// Issue状态单独保存，不会被当作仓库的版本显示或迁移
+func issueStatePath(storePath string) string {
+	ext := filepath.Ext(storePath)
+	return strings.TrimSuffix(storePath, ext) + ".issues" + ext
+}
+
+// isIssueState 是否为旧版本记录在版本状态中的Issue状态（仓库名为 name#编号）
+func isIssueState(state util.ReleaseState) bool {
+	return strings.Contains(state.Repository, "#")
+}
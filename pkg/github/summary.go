@@ -0,0 +1,121 @@
+package github
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+)
+
+// maxSummaryNotes 汇总中每个版本发布说明的最大字符数
+const maxSummaryNotes = 500
+
+// maxSummaryLength 汇总发布说明的最大字符数，超出的较早版本只列出数量
+const maxSummaryLength = 6000
+
+// FetchReleasesSince 获取仓库在指定时间之后发布的全部版本，按发布时间从新到旧排列
+// 草稿不会返回，预发布版本按 github.include_prereleases 配置决定是否包含
+func FetchReleasesSince(cfg *config.Config, owner, repo string, since time.Time) ([]*ReleaseInfo, error) {
+	client, ctx, err := newAPIClient(cfg.GitHub.Token, cfg.Network.LocalAddr, cfg.GitHub.HTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(cfg.GitHub.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	var releases []*ReleaseInfo
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		page, resp, err := client.Repositories.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("获取 %s/%s 的版本列表失败: %v", owner, repo, err)
+		}
+
+		older := false
+		for _, r := range page {
+			if r.GetDraft() || (r.GetPrerelease() && !cfg.GitHub.IncludePrereleases) {
+				continue
+			}
+			if r.GetPublishedAt().Before(since) {
+				older = true
+				continue
+			}
+			releases = append(releases, buildReleaseInfo(owner, repo, r, true, cfg, loc))
+		}
+
+		// 版本列表按创建时间从新到旧排列，出现早于起始时间的版本后不再翻页
+		if older || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].PublishedAt.After(releases[j].PublishedAt)
+	})
+	return releases, nil
+}
+
+// BuildSummary 将一段时间内的多个版本合并为一条汇总，发布说明按版本从新到旧依次排列
+// 返回的版本信息的 TagName 为最新的版本，链接指向仓库的版本列表页
+func BuildSummary(owner, repo string, releases []*ReleaseInfo, window string) *ReleaseInfo {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	newest := releases[0]
+	oldest := releases[len(releases)-1]
+	name := fmt.Sprintf("最近%s共 %d 个版本", window, len(releases))
+	if len(releases) > 1 {
+		name += fmt.Sprintf("（%s ~ %s）", oldest.TagName, newest.TagName)
+	}
+
+	var notes strings.Builder
+	length := 0
+	for i, release := range releases {
+		var entry strings.Builder
+		entry.WriteString(fmt.Sprintf("### %s（%s）\n", release.TagName, release.PublishedAt.Format("2006-01-02")))
+		if body := strings.TrimSpace(release.Description); body != "" {
+			entry.WriteString(truncateNotes(body, maxSummaryNotes))
+			entry.WriteString("\n")
+		}
+		entry.WriteString("\n")
+
+		entryLength := utf8.RuneCountInString(entry.String())
+		if length+entryLength > maxSummaryLength {
+			notes.WriteString(fmt.Sprintf("...以及更早的 %d 个版本\n", len(releases)-i))
+			break
+		}
+		notes.WriteString(entry.String())
+		length += entryLength
+	}
+
+	return &ReleaseInfo{
+		Event:       EventSummary,
+		Source:      newest.Source,
+		Owner:       owner,
+		Repository:  repo,
+		TagName:     newest.TagName,
+		Name:        name,
+		HTMLURL:     fmt.Sprintf("https://github.com/%s/%s/releases", owner, repo),
+		PublishedAt: newest.PublishedAt,
+		Prerelease:  newest.Prerelease,
+		Description: strings.TrimSpace(notes.String()),
+	}
+}
+
+// truncateNotes 按字符数截断发布说明
+func truncateNotes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:n])) + "..."
+}
@@ -0,0 +1,35 @@
+package github
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestBuildSummary 测试版本汇总的生成
+func TestBuildSummary(t *testing.T) {
+	now := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+	releases := []*ReleaseInfo{
+		{TagName: "v1.2.0", PublishedAt: now, Description: "新增功能"},
+		{TagName: "v1.1.1", PublishedAt: now.AddDate(0, 0, -3)},
+		{TagName: "v1.1.0", PublishedAt: now.AddDate(0, 0, -10), Description: strings.Repeat("修", maxSummaryNotes+10)},
+	}
+
+	summary := BuildSummary("foo", "bar", releases, "30天")
+	if summary.Event != EventSummary || summary.TagName != "v1.2.0" {
+		t.Errorf("汇总应以最新版本为准: %+v", summary)
+	}
+	if !strings.Contains(summary.Name, "3 个版本") || !strings.Contains(summary.Name, "v1.1.0 ~ v1.2.0") {
+		t.Errorf("汇总标题不正确: %s", summary.Name)
+	}
+	if strings.Index(summary.Description, "v1.2.0") > strings.Index(summary.Description, "v1.1.0") {
+		t.Error("汇总应按版本从新到旧排列")
+	}
+	if !strings.Contains(summary.Description, "新增功能") || !strings.Contains(summary.Description, "...") {
+		t.Errorf("汇总应包含发布说明并截断过长的部分: %s", summary.Description)
+	}
+
+	if BuildSummary("foo", "bar", nil, "30天") != nil {
+		t.Error("没有版本时应返回nil")
+	}
+}
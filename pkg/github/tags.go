@@ -0,0 +1,165 @@
+package github
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// checkTags 检查仓库是否推送了尚未创建Release的新版本标签
+func (c *Client) checkTags(repos []config.RepoConfig, cfg *config.Config, loc *time.Location) []*ReleaseInfo {
+	fmt.Printf("正在检查 %d 个仓库的标签...\n", len(repos))
+
+	var results []*ReleaseInfo
+	for _, repo := range repos {
+		info, err := c.checkLatestTag(repo.Owner, repo.Name, cfg, loc)
+		if err != nil {
+			fmt.Printf("检查仓库 %s/%s 的标签失败: %v\n", repo.Owner, repo.Name, err)
+			continue
+		}
+		if info == nil {
+			continue
+		}
+
+		applyPinnedVersion(info, repo)
+		if cfg.GitHub.OnlyWhenBehind && info.PinnedVersion != "" && !info.AffectsPinned {
+			continue
+		}
+		fmt.Printf("发现新标签: %s/%s (%s)\n", repo.Owner, repo.Name, info.TagName)
+		results = append(results, info)
+	}
+	return results
+}
+
+// checkLatestTag 检查仓库版本号最高的标签，比已通知的版本新、还没有Release且在检查期限内提交时通知
+// 标签只记录为已推送，不修改最新版本，之后创建的Release由 reconcileRelease 合并或不再通知
+func (c *Client) checkLatestTag(owner, repo string, cfg *config.Config, loc *time.Location) (*ReleaseInfo, error) {
+	// 附件规则和通知规则需要根据Release判断，配置了规则的仓库只按Release通知
+	if assetPatternFor(cfg, owner, repo) != "" {
+		return nil, nil
+	}
+	if minBump, everyNthPatch := milestoneRuleFor(cfg, owner, repo); minBump != "" || everyNthPatch > 0 {
+		return nil, nil
+	}
+
+	// 只读取第一页，新推送的标签通常在其中
+	c.usage.add(usageTags)
+	tags, _, err := c.client.Repositories.ListTags(c.ctx, owner, repo, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("获取标签失败: %v", err)
+	}
+	tag, ok := latestVersionTag(tags, cfg.GitHub.IncludePrereleases)
+	if !ok {
+		return nil, nil
+	}
+
+	tagName := tag.GetName()
+	latestTag := c.store.GetLatestTag(owner, repo)
+	if tagName == latestTag || tagName == c.store.PushedTag(owner, repo) {
+		return nil, nil
+	}
+	// 只通知比已通知的版本更新的标签，避免给旧版本补推的标签发送通知
+	if latestTag != "" && !tagNewer(tagName, latestTag) {
+		return nil, nil
+	}
+
+	if entry, ok := c.ignored.Match(owner, repo, tagName); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+
+	// 已经有Release的标签由版本检查处理
+	c.usage.add(usageTags)
+	_, resp, err := c.client.Repositories.GetReleaseByTag(c.ctx, owner, repo, tagName)
+	if err == nil {
+		return nil, nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return nil, fmt.Errorf("获取标签 %s 的Release失败: %v", tagName, err)
+	}
+
+	// 标签没有推送时间，按标签指向的提交时间判断是否在检查期限内
+	c.usage.add(usageTags)
+	commit, _, err := c.client.Git.GetCommit(c.ctx, owner, repo, tag.GetCommit().GetSHA())
+	if err != nil {
+		return nil, fmt.Errorf("获取标签 %s 的提交失败: %v", tagName, err)
+	}
+	committedAt := commit.GetCommitter().GetDate().Time
+	if committedAt.Before(c.now().In(loc).AddDate(0, 0, -cfg.GitHub.CheckDays)) {
+		return nil, nil
+	}
+
+	isNew, err := c.store.CheckAndUpdatePushedTag(owner, repo, tagName)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新标签状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+
+	return &ReleaseInfo{
+		Event:       EventTagPushed,
+		Owner:       owner,
+		Repository:  repo,
+		TagName:     tagName,
+		Name:        tagName,
+		HTMLURL:     fmt.Sprintf("https://github.com/%s/%s/tree/%s", owner, repo, url.PathEscape(tagName)),
+		PublishedAt: committedAt.In(loc),
+		Prerelease:  isPrereleaseTag(tagName),
+		PreviousTag: latestTag,
+	}, nil
+}
+
+// latestVersionTag 返回版本号最高的标签，无法解析为版本号的标签被忽略
+// 未开启 include_prereleases 时跳过预发布版本的标签
+func latestVersionTag(tags []*github.RepositoryTag, includePrereleases bool) (*github.RepositoryTag, bool) {
+	var (
+		latest    *github.RepositoryTag
+		latestVer version.Version
+	)
+	for _, tag := range tags {
+		v, err := version.Parse(tag.GetName())
+		if err != nil || (v.IsPrerelease() && !includePrereleases) {
+			continue
+		}
+		if latest == nil || version.Compare(v, latestVer) > 0 {
+			latest, latestVer = tag, v
+		}
+	}
+	return latest, latest != nil
+}
+
+// tagNewer 判断标签的版本号是否比 than 更新，无法解析的版本号视为更新
+func tagNewer(tag, than string) bool {
+	v, err := version.Parse(tag)
+	if err != nil {
+		return true
+	}
+	prev, err := version.Parse(than)
+	if err != nil {
+		return true
+	}
+	return version.Compare(v, prev) > 0
+}
+
+// reconcileRelease 对新版本依次处理已通知过的标签和仓库的通知规则，返回nil表示不通知
+// 标签此前已经通知过时，按 tags.on_release 发送"已创建Release"的跟进通知或不再通知，跟进通知不受通知规则限制
+func (c *Client) reconcileRelease(cfg *config.Config, info *ReleaseInfo) *ReleaseInfo {
+	if info.TagName == c.store.PushedTag(info.Owner, info.Repository) {
+		if cfg.GitHub.Tags.OnRelease == config.TagsOnReleaseSuppress {
+			fmt.Printf("%s/%s 的 %s 已在推送标签时通知过，跳过Release通知\n", info.Owner, info.Repository, info.TagName)
+			return nil
+		}
+		info.Event = EventTagReleased
+		return info
+	}
+	if !c.applyMilestoneRule(cfg, info) {
+		return nil
+	}
+	return info
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// tagsClient 启动返回仓库 o/r 标签的模拟API，所有标签都还没有Release
+func tagsClient(t *testing.T, committed time.Time, tags ...string) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/repos/o/r/tags", func(w http.ResponseWriter, r *http.Request) {
+		var list []map[string]interface{}
+		for _, tag := range tags {
+			list = append(list, map[string]interface{}{"name": tag, "commit": map[string]string{"sha": "sha-" + tag}})
+		}
+		json.NewEncoder(w).Encode(list)
+	})
+	mux.HandleFunc("/repos/o/r/releases/tags/", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/repos/o/r/git/commits/", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"committer": map[string]string{"date": committed.UTC().Format(time.RFC3339)},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	store, err := util.NewStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := github.NewClient(nil)
+	api.BaseURL, _ = url.Parse(server.URL + "/")
+	return &Client{client: api, ctx: t.Context(), store: store}
+}
+
+// TestCheckLatestTag 测试版本号最高的新标签通知一次，比已通知版本旧的标签不通知
+func TestCheckLatestTag(t *testing.T) {
+	c := tagsClient(t, time.Now().Add(-time.Hour), "v1.2.0-rc.1", "v1.1.0", "v1.0.0", "nightly")
+	cfg := &config.Config{}
+	cfg.GitHub.CheckDays = 3
+
+	if _, err := c.store.CheckAndUpdateIfNew("o", "r", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := c.checkLatestTag("o", "r", cfg, time.UTC)
+	if err != nil || info == nil {
+		t.Fatalf("新标签应通知: %v", err)
+	}
+	if info.Event != EventTagPushed || info.TagName != "v1.1.0" || info.PreviousTag != "v1.0.0" || info.HTMLURL != "https://github.com/o/r/tree/v1.1.0" {
+		t.Errorf("通知内容不正确: %+v", info)
+	}
+
+	if info, err := c.checkLatestTag("o", "r", cfg, time.UTC); err != nil || info != nil {
+		t.Errorf("相同标签不应重复通知: %+v, %v", info, err)
+	}
+
+	// 创建Release后已通知的标签保持记录，不会再作为新标签通知
+	if _, err := c.store.CheckAndUpdateIfNew("o", "r", "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.store.PushedTag("o", "r"); got != "v1.1.0" {
+		t.Errorf("记录的标签为 %q，期望 v1.1.0", got)
+	}
+
+	old := tagsClient(t, time.Now().Add(-time.Hour), "v0.9.0")
+	if _, err := old.store.CheckAndUpdateIfNew("o", "r", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := old.checkLatestTag("o", "r", cfg, time.UTC); err != nil || info != nil {
+		t.Errorf("比已通知版本旧的标签不应通知: %+v, %v", info, err)
+	}
+}
+
+// TestCheckLatestTag_Window 测试提交时间超出检查期限的标签不通知也不记录
+func TestCheckLatestTag_Window(t *testing.T) {
+	c := tagsClient(t, time.Now().AddDate(0, 0, -10), "v2.0.0")
+	cfg := &config.Config{}
+	cfg.GitHub.CheckDays = 3
+
+	if info, err := c.checkLatestTag("o", "r", cfg, time.UTC); err != nil || info != nil {
+		t.Errorf("超出检查期限的标签不应通知: %+v, %v", info, err)
+	}
+	if got := c.store.PushedTag("o", "r"); got != "" {
+		t.Errorf("超出检查期限的标签不应记录: %q", got)
+	}
+}
+
+// TestReconcileRelease 测试已通知过的标签创建Release时按配置合并或不再通知
+func TestReconcileRelease(t *testing.T) {
+	c := tagsClient(t, time.Now())
+	if _, err := c.store.CheckAndUpdatePushedTag("o", "r", "v1.1.0"); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{}
+
+	info := c.reconcileRelease(cfg, &ReleaseInfo{Event: EventRelease, Owner: "o", Repository: "r", TagName: "v1.1.0"})
+	if info == nil || info.Event != EventTagReleased {
+		t.Errorf("默认应合并为跟进通知: %+v", info)
+	}
+
+	if info := c.reconcileRelease(cfg, &ReleaseInfo{Event: EventRelease, Owner: "o", Repository: "r", TagName: "v1.2.0"}); info == nil || info.Event != EventRelease {
+		t.Errorf("未通知过标签的版本应照常通知: %+v", info)
+	}
+
+	cfg.GitHub.Tags.OnRelease = config.TagsOnReleaseSuppress
+	if info := c.reconcileRelease(cfg, &ReleaseInfo{Event: EventRelease, Owner: "o", Repository: "r", TagName: "v1.1.0"}); info != nil {
+		t.Errorf("suppress 时不应再通知: %+v", info)
+	}
+}
@@ -0,0 +1,213 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v71/github"
+	"github.com/orange-juzipi/notify/config"
+)
+
+// 令牌类型
+const (
+	TokenClassic     = "classic"      // 经典个人访问令牌（ghp_）
+	TokenFineGrained = "fine-grained" // 细粒度个人访问令牌（github_pat_）
+	TokenOAuth       = "oauth"        // OAuth应用令牌（gho_）
+	TokenApp         = "app"          // GitHub App令牌（ghs_、ghu_）
+	TokenUnknown     = "unknown"
+)
+
+// TokenType 根据前缀判断令牌类型
+func TokenType(token string) string {
+	switch {
+	case strings.HasPrefix(token, "github_pat_"):
+		return TokenFineGrained
+	case strings.HasPrefix(token, "ghp_"):
+		return TokenClassic
+	case strings.HasPrefix(token, "gho_"):
+		return TokenOAuth
+	case strings.HasPrefix(token, "ghs_"), strings.HasPrefix(token, "ghu_"):
+		return TokenApp
+	default:
+		return TokenUnknown
+	}
+}
+
+// PermissionCheck 单个功能的权限检查结果
+type PermissionCheck struct {
+	// Feature 功能对应的配置项
+	Feature string
+	// Permission 该功能需要的权限
+	Permission string
+	// Err 检查失败的原因，为nil表示权限满足
+	Err error
+}
+
+// TokenReport 令牌权限检查报告
+type TokenReport struct {
+	Type  string
+	Login string
+	// Scopes 经典令牌和OAuth令牌的授权范围（X-OAuth-Scopes）
+	Scopes []string
+	Checks []PermissionCheck
+}
+
+// Failed 返回检查失败的功能
+func (r *TokenReport) Failed() []PermissionCheck {
+	var failed []PermissionCheck
+	for _, c := range r.Checks {
+		if c.Err != nil {
+			failed = append(failed, c)
+		}
+	}
+	return failed
+}
+
+// Print 打印检查报告，verbose为false时只打印失败的功能
+func (r *TokenReport) Print(verbose bool) {
+	if verbose {
+		fmt.Printf("GitHub Token: %s 类型，用户 %s\n", r.Type, r.Login)
+		if r.Scopes != nil {
+			fmt.Printf("授权范围: %s\n", strings.Join(r.Scopes, ", "))
+		}
+	}
+
+	for _, c := range r.Checks {
+		if c.Err == nil {
+			if verbose {
+				fmt.Printf("✓ %s\n", c.Feature)
+			}
+			continue
+		}
+		fmt.Printf("✗ %s 将无法使用，缺少权限: %s (%v)\n", c.Feature, c.Permission, c.Err)
+	}
+}
+
+// ValidateToken 检查令牌类型以及已启用功能所需的权限
+// 每个功能通过一次最小的API调用验证实际权限，细粒度令牌没有授权范围可供读取
+func ValidateToken(cfg *config.Config) (*TokenReport, error) {
+	client, ctx, err := newAPIClient(cfg.GitHub.Token, cfg.Network.LocalAddr, cfg.GitHub.HTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	user, resp, err := client.Users.Get(ctx, "")
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return nil, fmt.Errorf("GitHub Token无效或已过期")
+		}
+		return nil, fmt.Errorf("获取Token对应的用户失败: %v", err)
+	}
+
+	report := &TokenReport{
+		Type:  TokenType(cfg.GitHub.Token),
+		Login: user.GetLogin(),
+	}
+	// 经典令牌和OAuth令牌的响应中带有授权范围头
+	if values := resp.Header.Values("X-OAuth-Scopes"); values != nil {
+		report.Scopes = []string{}
+		for _, s := range strings.Split(strings.Join(values, ","), ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				report.Scopes = append(report.Scopes, s)
+			}
+		}
+		// 兼容不带前缀的旧版经典令牌
+		if report.Type == TokenUnknown {
+			report.Type = TokenClassic
+		}
+	}
+
+	fine := report.Type == TokenFineGrained || report.Type == TokenApp
+	need := func(classic, fineGrained string) string {
+		if fine {
+			return fineGrained
+		}
+		return classic
+	}
+	probe := func(feature, permission string, call func() (*github.Response, error)) {
+		_, err := call()
+		report.Checks = append(report.Checks, PermissionCheck{
+			Feature:    feature,
+			Permission: permission,
+			Err:        probeError(err),
+		})
+	}
+	one := github.ListOptions{PerPage: 1}
+
+	gh := cfg.GitHub
+	if len(gh.Repos) > 0 {
+		r := gh.Repos[0]
+		probe("repos（读取Release）", need("repo（私有仓库）", "Contents: Read-only"), func() (*github.Response, error) {
+			_, resp, err := client.Repositories.ListReleases(ctx, r.Owner, r.Name, &one)
+			return resp, err
+		})
+	}
+	if gh.AutoWatchUser {
+		probe("auto_watch_user", need("repo（私有仓库）", "Metadata: Read-only，并在仓库访问范围中选择仓库"), func() (*github.Response, error) {
+			_, resp, err := client.Repositories.ListByAuthenticatedUser(ctx, &github.RepositoryListByAuthenticatedUserOptions{ListOptions: one})
+			return resp, err
+		})
+	}
+	if gh.WatchStarred {
+		probe("watch_starred", need("无需额外授权范围", "账户权限 Starring: Read-only"), func() (*github.Response, error) {
+			_, resp, err := client.Activity.ListStarred(ctx, "", &github.ActivityListStarredOptions{ListOptions: one})
+			return resp, err
+		})
+	}
+	for _, org := range gh.WatchOrgs {
+		probe("watch_orgs: "+org, need("read:org，私有仓库还需要 repo", "资源所有者选择组织 "+org+"，Metadata: Read-only"), func() (*github.Response, error) {
+			_, resp, err := client.Repositories.ListByOrg(ctx, org, &github.RepositoryListByOrgOptions{ListOptions: one})
+			return resp, err
+		})
+	}
+	if len(gh.WatchLabels.Labels) > 0 && len(gh.Repos) > 0 {
+		r := gh.Repos[0]
+		probe("watch_labels", need("repo（私有仓库）", "Issues: Read-only"), func() (*github.Response, error) {
+			_, resp, err := client.Issues.ListByRepo(ctx, r.Owner, r.Name, &github.IssueListByRepoOptions{ListOptions: one})
+			return resp, err
+		})
+	}
+
+	// 经典令牌可以直接从授权范围判断组织权限
+	if report.Type == TokenClassic && len(gh.WatchOrgs) > 0 && !hasScope(report.Scopes, "read:org", "admin:org", "write:org") {
+		report.Checks = append(report.Checks, PermissionCheck{
+			Feature:    "watch_orgs（私有成员组织）",
+			Permission: "read:org",
+			Err:        fmt.Errorf("授权范围中没有 read:org，只能看到组织的公开仓库"),
+		})
+	}
+
+	return report, nil
+}
+
+// probeError 将权限探测的错误转换为易读的原因
+func probeError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var errResp *github.ErrorResponse
+	if errors.As(err, &errResp) && errResp.Response != nil {
+		switch errResp.Response.StatusCode {
+		case http.StatusForbidden:
+			return fmt.Errorf("无权访问: %s", errResp.Message)
+		case http.StatusNotFound:
+			return fmt.Errorf("资源不存在或令牌无权访问")
+		}
+	}
+	return err
+}
+
+// hasScope 授权范围中是否包含任意一个指定范围
+func hasScope(scopes []string, want ...string) bool {
+	for _, s := range scopes {
+		for _, w := range want {
+			if s == w {
+				return true
+			}
+		}
+	}
+	return false
+}
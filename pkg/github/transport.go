@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+)
+
+// retryBackoff 第一次重试前的等待时间，之后每次翻倍
+const retryBackoff = time.Second
+
+// maxRetryWait 等待时间的上限，Retry-After 超过该值时不再重试
+const maxRetryWait = 30 * time.Second
+
+// retryTransport 为每次请求设置超时，网络错误和5xx响应按指数退避重试
+// 只重试GET、HEAD请求，速率限制（403、429）直接返回，由调用方按配额处理
+type retryTransport struct {
+	base       http.RoundTripper
+	timeout    time.Duration
+	maxRetries int
+	backoff    time.Duration
+	clock      clock.Clock
+}
+
+// RoundTrip 发送请求，超时只作用于单次请求，响应体读取完成或关闭前不会取消
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retries := t.maxRetries
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTrip(req)
+		if attempt >= retries || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		wait := t.backoff << attempt
+		switch {
+		case err != nil:
+			fmt.Printf("GitHub API请求失败（%v），%s 后重试（%d/%d）\n", err, wait, attempt+1, retries)
+		case retryableStatus(resp.StatusCode):
+			if after, ok := retryAfter(resp); ok {
+				if after > maxRetryWait {
+					return resp, nil
+				}
+				wait = after
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+			fmt.Printf("GitHub API返回状态码 %d，%s 后重试（%d/%d）\n", resp.StatusCode, wait, attempt+1, retries)
+		default:
+			return resp, nil
+		}
+		if err := clock.Sleep(req.Context(), t.clock, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTrip 发送一次请求
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retryableStatus 可以重试的状态码（服务端的临时错误）
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter 解析以秒为单位的 Retry-After 响应头
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// cancelBody 关闭响应体时取消单次请求的超时上下文
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
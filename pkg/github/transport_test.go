@@ -0,0 +1,109 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/clock"
+)
+
+// TestRetryTransport 测试5xx响应按次数重试、请求超时作用于单次请求，以及非GET请求不重试
+func TestRetryTransport(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := calls.Add(1); {
+		case n == 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case n == 2:
+			time.Sleep(200 * time.Millisecond) // 超过单次请求的超时
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	transport := &retryTransport{base: http.DefaultTransport, timeout: 100 * time.Millisecond, maxRetries: 2, backoff: time.Millisecond, clock: clock.System}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	body := make([]byte, 2)
+	resp.Body.Read(body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" || calls.Load() != 3 {
+		t.Errorf("状态码为 %d，响应为 %q，请求了 %d 次，期望重试两次后成功", resp.StatusCode, body, calls.Load())
+	}
+
+	// 用完重试次数后返回最后一次的响应
+	calls.Store(0)
+	transport.maxRetries = 0
+	resp, err = client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || calls.Load() != 1 {
+		t.Errorf("状态码为 %d，请求了 %d 次，期望不重试", resp.StatusCode, calls.Load())
+	}
+
+	calls.Store(0)
+	transport.maxRetries = 2
+	resp, err = client.Post(server.URL, "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("POST请求了 %d 次，期望不重试", calls.Load())
+	}
+}
+
+// TestRetryTransport_RetryAfter Retry-After 超过等待上限时直接返回响应
+func TestRetryTransport_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, maxRetries: 3, backoff: time.Millisecond, clock: clock.System}}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable || calls.Load() != 1 {
+		t.Errorf("状态码为 %d，请求了 %d 次，期望不重试", resp.StatusCode, calls.Load())
+	}
+}
+
+// TestNewAPIClient_UserAgent 测试自定义User-Agent
+func TestNewAPIClient_UserAgent(t *testing.T) {
+	var agent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		agent = r.Header.Get("User-Agent")
+		w.Write([]byte(`{"login": "octocat"}`))
+	}))
+	defer server.Close()
+
+	client, ctx, err := newAPIClient("token", "", config.GitHubHTTPConfig{Timeout: "5s", UserAgent: "notify (+ops@example.com)"})
+	if err != nil {
+		t.Fatalf("创建客户端失败: %v", err)
+	}
+	client, _ = client.WithEnterpriseURLs(server.URL, server.URL)
+	if _, _, err := client.Users.Get(ctx, ""); err != nil {
+		t.Fatalf("请求失败: %v", err)
+	}
+	if agent != "notify (+ops@example.com)" {
+		t.Errorf("User-Agent 为 %q", agent)
+	}
+}
@@ -0,0 +1,97 @@
+package github
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// API请求类别
+const (
+	usageDiscovery     = iota // 仓库发现（用户/star/组织仓库列表）
+	usagePrefilter            // release预筛选
+	usageChecks               // 版本检查
+	usageIssues               // Issue标签检查
+	usageContributions        // 贡献记录检查
+	usageGists                // Gist检查
+	usageTags                 // 标签检查
+	usageScoring              // 仓库评分（读取star数）
+	usageDeprecation          // 弃用状态检查
+	usageNotes                // 生成空的发布说明
+	usageCategories
+)
+
+// usageNames API请求类别的展示名称
+var usageNames = [usageCategories]string{
+	usageDiscovery:     "仓库发现",
+	usagePrefilter:     "release预筛选",
+	usageChecks:        "版本检查",
+	usageIssues:        "Issue检查",
+	usageContributions: "贡献检查",
+	usageGists:         "Gist检查",
+	usageTags:          "标签检查",
+	usageScoring:       "仓库评分",
+	usageDeprecation:   "弃用检查",
+	usageNotes:         "生成发布说明",
+}
+
+// apiUsage 统计一次运行中各类别消耗的API请求数
+type apiUsage struct {
+	counts [usageCategories]int64
+}
+
+// add 记录一次API请求
+func (u *apiUsage) add(category int) {
+	atomic.AddInt64(&u.counts[category], 1)
+}
+
+// total 返回API请求总数
+func (u *apiUsage) total() int64 {
+	var sum int64
+	for i := range u.counts {
+		sum += atomic.LoadInt64(&u.counts[i])
+	}
+	return sum
+}
+
+// CheckStats 一次检查的统计，用于记录运行指标
+type CheckStats struct {
+	// Repos 检查的仓库数
+	Repos int
+	// Errors 检查失败的仓库数
+	Errors int
+	// RateLimited 是否因达到速率限制而有仓库未检查
+	RateLimited bool
+	// APIRequests 消耗的API请求数
+	APIRequests int64
+	// QuotaRemaining 检查结束时的剩余配额，未知时为-1
+	QuotaRemaining int
+}
+
+// reportUsage 打印本次运行的API使用情况，并根据剩余配额估算重置前还能运行的次数，返回剩余配额（未知时为-1）
+func (c *Client) reportUsage(startRemaining int) int {
+	fmt.Println("\nGitHub API 使用情况:")
+	for i, name := range usageNames {
+		if n := atomic.LoadInt64(&c.usage.counts[i]); n > 0 {
+			fmt.Printf("- %s: %d 次\n", name, n)
+		}
+	}
+	total := c.usage.total()
+	fmt.Printf("- 合计: %d 次\n", total)
+
+	rl, _, err := c.client.RateLimit.Get(c.ctx)
+	if err != nil || rl == nil || rl.Core == nil {
+		return -1
+	}
+
+	remaining := rl.Core.Remaining
+	fmt.Printf("- 剩余配额: %d/%d，重置时间：%s\n", remaining, rl.Core.Limit, rl.Core.Reset.Time.Format(time.DateTime))
+	if startRemaining >= 0 && startRemaining >= remaining {
+		// 配额变化可能包含同一token下其他程序的请求
+		fmt.Printf("- 本次运行期间配额减少: %d\n", startRemaining-remaining)
+	}
+	if total > 0 {
+		fmt.Printf("- 按本次消耗估算，配额重置前还可以运行 %d 次\n", int64(remaining)/total)
+	}
+	return remaining
+}
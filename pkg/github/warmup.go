@@ -0,0 +1,184 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// defaultWarmupBudget 预热模式默认每次运行最多使用的剩余配额比例
+const defaultWarmupBudget = 0.5
+
+// defaultWarmupBatch 无法获取剩余配额时，预热模式每次运行检查的新仓库数
+const defaultWarmupBatch = 500
+
+// warmupProgress 预热进度（warmup.json）
+type warmupProgress struct {
+	StartedAt time.Time `json:"started_at"`
+	// CompletedAt 所有仓库都已建立基线的时间，之后不再限制
+	CompletedAt time.Time `json:"completed_at,omitzero"`
+	// Checked 已建立基线的仓库（owner/name）
+	Checked map[string]bool `json:"checked"`
+}
+
+// warmup 首次扫描大量仓库时，按配额预算把检查分散到多次运行中，逐步建立状态基线
+type warmup struct {
+	path     string
+	silent   bool
+	mu       sync.Mutex
+	progress warmupProgress
+	// pending 本次运行中检查的尚未建立基线的仓库
+	pending map[string]bool
+}
+
+// loadWarmup 加载预热进度，未启用预热时返回nil
+func loadWarmup(cfg *config.Config) (*warmup, error) {
+	if !cfg.GitHub.Warmup.Enabled {
+		return nil, nil
+	}
+
+	path, err := util.ResolvePath("", "warmup.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+
+	w := &warmup{path: path, silent: cfg.GitHub.Warmup.Silent, pending: make(map[string]bool)}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("读取预热进度失败: %v", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &w.progress); err != nil {
+			return nil, fmt.Errorf("解析预热进度失败: %v", err)
+		}
+	}
+	if w.progress.Checked == nil {
+		w.progress.Checked = make(map[string]bool)
+	}
+	if w.progress.StartedAt.IsZero() {
+		w.progress.StartedAt = time.Now()
+	}
+	return w, nil
+}
+
+// selectRepos 返回本次运行要检查的仓库：已建立基线的仓库全部检查，尚未建立基线的仓库按配额预算只检查一部分
+// 已有状态记录的仓库视为已建立基线
+func (w *warmup) selectRepos(repos []config.RepoConfig, store *util.StateStore, cfg *config.Config, remaining int) []config.RepoConfig {
+	if w == nil || !w.progress.CompletedAt.IsZero() {
+		return repos
+	}
+
+	var known, pending []config.RepoConfig
+	for _, repo := range repos {
+		key := repoKey(repo.Owner, repo.Name)
+		if w.progress.Checked[key] || store.GetLatestTag(repo.Owner, repo.Name) != "" {
+			w.progress.Checked[key] = true
+			known = append(known, repo)
+		} else {
+			pending = append(pending, repo)
+		}
+	}
+
+	limit := w.batchSize(cfg, remaining, len(known))
+	if len(pending) <= limit {
+		limit = len(pending)
+	}
+	for _, repo := range pending[:limit] {
+		w.pending[repoKey(repo.Owner, repo.Name)] = true
+	}
+
+	if len(pending) > 0 {
+		fmt.Printf("预热模式: 本次检查 %d/%d 个尚未建立基线的仓库", limit, len(pending))
+		if limit > 0 {
+			fmt.Printf("，预计还需 %d 次运行", (len(pending)+limit-1)/limit)
+		}
+		fmt.Println()
+		if w.silent {
+			fmt.Println("预热模式: 新建立基线的仓库只记录当前版本，不发送通知")
+		}
+	}
+
+	return append(known, pending[:limit]...)
+}
+
+// batchSize 本次运行最多检查的新仓库数
+// 配置了 batch_size 时直接使用，否则按剩余配额乘以预算比例、扣除已建立基线的仓库的检查次数计算
+func (w *warmup) batchSize(cfg *config.Config, remaining, known int) int {
+	if cfg.GitHub.Warmup.BatchSize > 0 {
+		return cfg.GitHub.Warmup.BatchSize
+	}
+	if remaining < 0 {
+		return defaultWarmupBatch
+	}
+
+	budget := cfg.GitHub.Warmup.Budget
+	if budget <= 0 || budget > 1 {
+		budget = defaultWarmupBudget
+	}
+	limit := int(float64(remaining)*budget) - known
+	if limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// suppress 仓库是否为本次新建立基线的仓库且配置了只记录不通知
+func (w *warmup) suppress(owner, repo string) bool {
+	if w == nil || !w.silent {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.pending[repoKey(owner, repo)]
+}
+
+// markChecked 记录仓库已建立基线
+func (w *warmup) markChecked(owner, repo string) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.progress.Checked[repoKey(owner, repo)] = true
+}
+
+// save 保存预热进度，所有仓库都已建立基线时标记预热完成
+func (w *warmup) save(repos []config.RepoConfig) {
+	if w == nil || !w.progress.CompletedAt.IsZero() {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	done := 0
+	for _, repo := range repos {
+		if w.progress.Checked[repoKey(repo.Owner, repo.Name)] {
+			done++
+		}
+	}
+	if done == len(repos) {
+		w.progress.CompletedAt = time.Now()
+		fmt.Printf("预热完成: 全部 %d 个仓库已建立基线，之后每次运行检查所有仓库\n", len(repos))
+	} else {
+		fmt.Printf("预热进度: %d/%d 个仓库已建立基线\n", done, len(repos))
+	}
+
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		fmt.Printf("警告: 保存预热进度失败: %v\n", err)
+		return
+	}
+	data, err := json.MarshalIndent(w.progress, "", "  ")
+	if err == nil {
+		err = os.WriteFile(w.path, data, 0644)
+	}
+	if err != nil {
+		fmt.Printf("警告: 保存预热进度失败: %v\n", err)
+	}
+}
@@ -0,0 +1,146 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// watchSnapshot 上次发现的监控列表
+type watchSnapshot struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	// Repos 键为 owner/name，值为来源
+	Repos map[string]string `json:"repos"`
+}
+
+// pendingWatch 等待保存的监控列表快照
+type pendingWatch struct {
+	path     string
+	snapshot watchSnapshot
+}
+
+// WatchSourceLabel 返回监控来源的展示名称
+func WatchSourceLabel(source string) string {
+	switch {
+	case source == WatchSourceManual:
+		return "手动指定"
+	case source == WatchSourceUser:
+		return "自己的仓库"
+	case source == WatchSourceStarred:
+		return "已star"
+	case source == WatchSourceManifest:
+		return "依赖清单"
+	case strings.HasPrefix(source, WatchSourceOrgPrefix):
+		return "组织 " + strings.TrimPrefix(source, WatchSourceOrgPrefix)
+	default:
+		return source
+	}
+}
+
+// diffWatchList 对比两次的监控列表，返回新增和移除的仓库（已排序）
+func diffWatchList(prev, curr map[string]string) (added, removed []string) {
+	for key := range curr {
+		if _, ok := prev[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	for key := range prev {
+		if _, ok := curr[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+// checkWatchListChanges 将本次发现的监控列表与上次保存的快照对比，为加入或移出的仓库生成通知
+// 首次运行只记录快照；仓库发现中有来源失败时跳过对比，避免把获取失败的仓库误判为移除
+// 本次的快照记录在 c.pendingWatch 中，通知发送成功后才保存，发送失败时下次运行重新对比
+func (c *Client) checkWatchListChanges(repos []config.RepoConfig, sources map[string]string, cfg *config.Config, loc *time.Location) []*ReleaseInfo {
+	if c.discoveryErrors > 0 {
+		fmt.Println("仓库发现过程中有错误，本次跳过监控列表变化对比")
+		return nil
+	}
+
+	path, err := util.ResolvePath("", "watchlist.json", cfg.DataSuffix())
+	if err != nil {
+		fmt.Printf("解析监控列表快照路径失败: %v\n", err)
+		return nil
+	}
+
+	curr := make(map[string]string, len(repos))
+	for _, repo := range repos {
+		key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+		curr[key] = sources[key]
+	}
+
+	var prev watchSnapshot
+	data, err := os.ReadFile(path)
+	firstRun := os.IsNotExist(err)
+	if err == nil {
+		if err := json.Unmarshal(data, &prev); err != nil {
+			fmt.Printf("解析监控列表快照失败: %v\n", err)
+			return nil
+		}
+	} else if !firstRun {
+		fmt.Printf("读取监控列表快照失败: %v\n", err)
+		return nil
+	}
+
+	c.pendingWatch = &pendingWatch{path: path, snapshot: watchSnapshot{UpdatedAt: time.Now(), Repos: curr}}
+
+	if firstRun {
+		fmt.Printf("首次记录监控列表快照（%d 个仓库），下次运行起通知列表变化\n", len(curr))
+		return nil
+	}
+
+	added, removed := diffWatchList(prev.Repos, curr)
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+	fmt.Printf("监控列表变化: 新增 %d 个，移除 %d 个\n", len(added), len(removed))
+
+	now := time.Now().In(loc)
+	var results []*ReleaseInfo
+	build := func(event, key, source string) {
+		owner, name, _ := strings.Cut(key, "/")
+		results = append(results, &ReleaseInfo{
+			Event:       event,
+			Owner:       owner,
+			Repository:  name,
+			Name:        key,
+			HTMLURL:     fmt.Sprintf("https://github.com/%s", key),
+			PublishedAt: now,
+			WatchSource: source,
+			WatchLabel:  WatchSourceLabel(source),
+		})
+	}
+	for _, key := range added {
+		build(EventWatchAdded, key, curr[key])
+	}
+	for _, key := range removed {
+		build(EventWatchRemoved, key, prev.Repos[key])
+	}
+
+	return results
+}
+
+// saveWatchSnapshot 保存监控列表快照
+func saveWatchSnapshot(path string, snapshot watchSnapshot) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
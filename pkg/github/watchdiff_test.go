@@ -0,0 +1,122 @@
+package github
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// readWatchSnapshot 读取保存的监控列表快照中的仓库，文件不存在时返回nil
+func readWatchSnapshot(t *testing.T, path string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot watchSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	return snapshot.Repos
+}
+
+// TestCheckWatchListChanges 监控列表快照在调用 CommitWatchList 后才保存，未保存时下次检查重新通知列表变化
+func TestCheckWatchListChanges(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	path := filepath.Join(dir, ".notify", "watchlist.json")
+	cfg := &config.Config{}
+	k := NewChecker()
+
+	check := func(repos []config.RepoConfig, sources map[string]string) []*ReleaseInfo {
+		c := &Client{}
+		results := c.checkWatchListChanges(repos, sources, cfg, time.UTC)
+		k.pendingWatch = c.pendingWatch
+		return results
+	}
+
+	manual := []config.RepoConfig{{Owner: "a", Name: "x"}}
+	manualSources := map[string]string{"a/x": WatchSourceManual}
+	if results := check(manual, manualSources); len(results) != 0 {
+		t.Fatalf("首次运行不应通知列表变化: %+v", results)
+	}
+	if repos := readWatchSnapshot(t, path); repos != nil {
+		t.Fatalf("提交前不应保存快照: %v", repos)
+	}
+	if err := k.CommitWatchList(); err != nil {
+		t.Fatalf("保存快照失败: %v", err)
+	}
+	if repos := readWatchSnapshot(t, path); len(repos) != 1 || repos["a/x"] != WatchSourceManual {
+		t.Fatalf("快照 %v，期望只包含 a/x", repos)
+	}
+
+	starred := []config.RepoConfig{{Owner: "b", Name: "y"}}
+	starredSources := map[string]string{"b/y": WatchSourceStarred}
+	// 通知未发送成功（未提交）时，下次检查应再次通知同样的变化
+	for range 2 {
+		results := check(starred, starredSources)
+		if len(results) != 2 {
+			t.Fatalf("发现 %d 个变化，期望 2 个: %+v", len(results), results)
+		}
+		added, removed := results[0], results[1]
+		if added.Event != EventWatchAdded || added.Owner != "b" || added.Repository != "y" || added.WatchLabel != "已star" || added.TagName != "" {
+			t.Errorf("新增事件错误: %+v", added)
+		}
+		if removed.Event != EventWatchRemoved || removed.Repository != "x" || removed.WatchLabel != "手动指定" {
+			t.Errorf("移除事件错误: %+v", removed)
+		}
+		if got := added.EventLabel(); got != "➕ 加入监控列表（已star）" {
+			t.Errorf("事件标签 %q", got)
+		}
+		if repos := readWatchSnapshot(t, path); len(repos) != 1 || repos["a/x"] == "" {
+			t.Fatalf("提交前快照不应改变: %v", repos)
+		}
+	}
+
+	if err := k.CommitWatchList(); err != nil {
+		t.Fatalf("保存快照失败: %v", err)
+	}
+	if results := check(starred, starredSources); len(results) != 0 {
+		t.Errorf("提交后不应再通知同样的变化: %+v", results)
+	}
+}
+
+// TestCheckWatchListChanges_ManifestError 依赖清单解析失败计入发现错误，本次跳过对比
+func TestCheckWatchListChanges_ManifestError(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	if err := saveWatchSnapshot(filepath.Join(dir, ".notify", "watchlist.json"), watchSnapshot{Repos: map[string]string{"a/x": WatchSourceManual, "o/dep": WatchSourceManifest}}); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := util.NewStateStore(filepath.Join(dir, "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{ctx: t.Context(), store: store}
+	cfg := &config.Config{}
+	cfg.GitHub.Repos = []config.RepoConfig{{Owner: "a", Name: "x"}}
+	cfg.GitHub.Manifests = []string{filepath.Join(dir, "missing", "go.mod")}
+
+	repos, sources, err := c.discoverRepos(cfg)
+	if err != nil {
+		t.Fatalf("发现仓库失败: %v", err)
+	}
+	if c.discoveryErrors != 1 {
+		t.Fatalf("发现错误数 %d，期望 1", c.discoveryErrors)
+	}
+	if results := c.checkWatchListChanges(repos, sources, cfg, time.UTC); len(results) != 0 {
+		t.Errorf("依赖清单解析失败时不应把清单中的仓库当作移除: %+v", results)
+	}
+	if c.pendingWatch != nil {
+		t.Error("跳过对比时不应记录快照")
+	}
+}
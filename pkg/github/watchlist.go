@@ -0,0 +1,79 @@
+package github
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// 监控仓库的来源
+const (
+	// WatchSourceManual 配置文件中手动指定
+	WatchSourceManual = "manual"
+	// WatchSourceUser 用户自己的仓库（auto_watch_user）
+	WatchSourceUser = "user"
+	// WatchSourceStarred 用户已star的仓库（watch_starred）
+	WatchSourceStarred = "starred"
+	// WatchSourceOrgPrefix 组织仓库（watch_orgs），后接组织名
+	WatchSourceOrgPrefix = "org:"
+	// WatchSourceManifest 从依赖清单推导（manifests）
+	WatchSourceManifest = "manifest"
+)
+
+// WatchedRepo 实际监控的仓库及其来源
+type WatchedRepo struct {
+	config.RepoConfig
+	Source string
+}
+
+// HTMLURL 仓库主页地址
+func (r WatchedRepo) HTMLURL() string {
+	return fmt.Sprintf("https://github.com/%s/%s", r.Owner, r.Name)
+}
+
+// FeedURL 仓库release的Atom订阅地址
+func (r WatchedRepo) FeedURL() string {
+	return r.HTMLURL() + "/releases.atom"
+}
+
+// ListWatchedRepos 执行与检查时相同的仓库发现和过滤，返回实际监控的仓库列表（按 owner/name 排序）
+// 发现过程中的进度信息输出到 progress
+func ListWatchedRepos(cfg *config.Config, progress io.Writer) ([]WatchedRepo, error) {
+	if cfg.GitHub.Token == "" {
+		cfg = anonymousConfig(cfg, progress)
+	}
+
+	storePath, err := util.ResolvePath(cfg.State.Path, "state.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := NewClient(cfg.GitHub.Token, storePath, StoreOptions(cfg), cfg.Network.LocalAddr, cfg.GitHub.HTTP)
+	if err != nil {
+		return nil, fmt.Errorf("创建GitHub客户端失败: %v", err)
+	}
+	client.out = progress
+
+	repoConfigs, sources, err := client.discoverRepos(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	repos := make([]WatchedRepo, 0, len(repoConfigs))
+	for _, repo := range repoConfigs {
+		key := fmt.Sprintf("%s/%s", repo.Owner, repo.Name)
+		repos = append(repos, WatchedRepo{RepoConfig: repo, Source: sources[key]})
+	}
+
+	sort.Slice(repos, func(i, j int) bool {
+		if repos[i].Owner != repos[j].Owner {
+			return repos[i].Owner < repos[j].Owner
+		}
+		return repos[i].Name < repos[j].Name
+	})
+
+	return repos, nil
+}
@@ -0,0 +1,55 @@
+package github
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// TestListWatchedRepos 导出的仓库按 owner/name 排序，发现过程中的进度信息输出到指定的位置
+func TestListWatchedRepos(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+
+	gomod := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(gomod, []byte("module example.com/app\n\nrequire github.com/o/dep v1.0.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.GitHub.Token = "token"
+	cfg.State.Path = filepath.Join(dir, "state.json")
+	cfg.GitHub.Repos = []config.RepoConfig{{Owner: "z", Name: "last"}, {Owner: "a", Name: "first"}}
+	cfg.GitHub.Manifests = []string{gomod}
+
+	var progress bytes.Buffer
+	repos, err := ListWatchedRepos(cfg, &progress)
+	if err != nil {
+		t.Fatalf("获取监控列表失败: %v", err)
+	}
+
+	var got []string
+	for _, r := range repos {
+		got = append(got, r.Owner+"/"+r.Name+":"+r.Source)
+	}
+	if want := "a/first:manual o/dep:manifest z/last:manual"; strings.Join(got, " ") != want {
+		t.Errorf("监控列表为 %v，期望 %s", got, want)
+	}
+	if !strings.Contains(progress.String(), "正在解析依赖清单") {
+		t.Errorf("进度信息未输出到指定位置: %q", progress.String())
+	}
+
+	// 匿名模式的说明同样输出到指定位置
+	progress.Reset()
+	cfg.GitHub.Token = ""
+	if _, err := ListWatchedRepos(cfg, &progress); err != nil {
+		t.Fatalf("获取监控列表失败: %v", err)
+	}
+	if !strings.Contains(progress.String(), "匿名模式") {
+		t.Errorf("匿名模式的说明未输出到指定位置: %q", progress.String())
+	}
+}
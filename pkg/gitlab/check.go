@@ -0,0 +1,222 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/source"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// stateOwner 状态文件中的所属空间，加上来源前缀避免与GitHub上的同名仓库冲突，与webhook服务记录的状态一致
+func stateOwner(namespace string) string {
+	return github.SourceGitLab + ":" + namespace
+}
+
+// splitPath 将 group/subgroup/project 拆分为所属空间和项目名
+func splitPath(project string) (string, string) {
+	i := strings.LastIndex(project, "/")
+	if i < 0 {
+		return "", project
+	}
+	return project[:i], project[i+1:]
+}
+
+// checker 一次检查使用的客户端、状态和配置
+type checker struct {
+	*source.Base
+	client          *Client
+	showDescription bool
+}
+
+// Source GitLab项目版本来源
+type Source struct{}
+
+// Name 来源名称
+func (Source) Name() string { return "GitLab项目" }
+
+// Enabled 是否配置了GitLab项目
+func (Source) Enabled(cfg *config.Config) bool { return len(cfg.GitLab.Projects) > 0 }
+
+// Check 检查新版本
+func (Source) Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	return CheckForNewReleases(cfg, showDescription)
+}
+
+// CheckForNewReleases 检查配置的GitLab项目是否有新版本
+// 检查期限、时区和预发布版本的设置与GitHub仓库相同（github.check_days、timezone、include_prereleases）
+func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	projects := source.FilterShard(cfg.GitLab.Projects, cfg.Shard, "GitLab项目", func(p config.GitLabProjectConfig) string { return stateOwner(p.Path) })
+	if len(projects) == 0 {
+		return nil, nil
+	}
+
+	base, err := source.NewBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(cfg.GitLab.BaseURL, cfg.GitLab.Token, cfg.Network.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{
+		Base:            base,
+		client:          client,
+		showDescription: showDescription,
+	}
+
+	fmt.Printf("正在检查 %d 个GitLab项目（%s）...\n", len(projects), client.baseURL)
+	var results []*github.ReleaseInfo
+	errorCount := 0
+	for _, p := range projects {
+		info, err := c.check(p)
+		if err != nil {
+			fmt.Printf("检查GitLab项目 %s 失败: %v\n", p.Path, err)
+			errorCount++
+			continue
+		}
+		if info != nil {
+			fmt.Printf("发现新版本: %s (%s)\n", p.Path, info.TagName)
+			results = append(results, info)
+		}
+	}
+
+	fmt.Printf("GitLab检查完成: 发现 %d 个新版本", len(results))
+	if errorCount > 0 {
+		fmt.Printf("，%d 个项目检查失败", errorCount)
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// candidate 项目最新的版本，来自Release或标签
+type candidate struct {
+	tag         string
+	name        string
+	description string
+	url         string
+	published   time.Time
+}
+
+// check 检查一个项目，有需要通知的新版本时返回版本信息
+func (c *checker) check(p config.GitLabProjectConfig) (*github.ReleaseInfo, error) {
+	project := strings.Trim(p.Path, "/")
+	if project == "" {
+		return nil, fmt.Errorf("项目路径不能为空")
+	}
+
+	var (
+		latest *candidate
+		err    error
+	)
+	if p.Tags {
+		latest, err = c.latestTag(project)
+	} else {
+		latest, err = c.latestRelease(project)
+	}
+	if err != nil || latest == nil {
+		return nil, err
+	}
+
+	prerelease := false
+	if v, err := version.Parse(latest.tag); err == nil {
+		prerelease = v.IsPrerelease()
+	}
+	if prerelease && !c.Config.GitHub.IncludePrereleases {
+		return nil, nil
+	}
+	if latest.published.Before(c.Since) {
+		return nil, nil
+	}
+
+	namespace, name := splitPath(project)
+	// 通过 notify ignore 标记的版本不通知，也不记录状态
+	if entry, ok := c.Ignored.Match(namespace, name, latest.tag); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+
+	previousTag := c.Store.GetLatestTag(stateOwner(namespace), name)
+	isNew, err := c.Store.CheckAndUpdateIfNew(stateOwner(namespace), name, latest.tag)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+
+	info := &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Source:      github.SourceGitLab,
+		Owner:       namespace,
+		Repository:  name,
+		TagName:     latest.tag,
+		Name:        latest.name,
+		HTMLURL:     latest.url,
+		PublishedAt: latest.published.In(c.Loc),
+		Prerelease:  prerelease,
+		PreviousTag: previousTag,
+		Highlights:  github.FindHighlights(latest.description, c.Config.Highlight.Keywords),
+	}
+	if c.showDescription {
+		info.Description = latest.description
+	}
+	return info, nil
+}
+
+// latestRelease 项目最新的Release
+func (c *checker) latestRelease(project string) (*candidate, error) {
+	r, err := c.client.latestRelease(project)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	published := r.ReleasedAt
+	if published.IsZero() {
+		published = r.CreatedAt
+	}
+	link := r.Links.Self
+	if link == "" {
+		link = fmt.Sprintf("%s/%s/-/releases/%s", c.client.baseURL, project, url.PathEscape(r.TagName))
+	}
+	name := r.Name
+	if name == "" {
+		name = r.TagName
+	}
+	return &candidate{tag: r.TagName, name: name, description: r.Description, url: link, published: published}, nil
+}
+
+// latestTag 项目版本号最高的标签，无法解析为版本号的标签被跳过
+func (c *checker) latestTag(project string) (*candidate, error) {
+	tags, err := c.client.tags(project)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		latest    *tag
+		latestVer version.Version
+	)
+	for i := range tags {
+		v, err := version.Parse(tags[i].Name)
+		if err != nil || (v.IsPrerelease() && !c.Config.GitHub.IncludePrereleases) {
+			continue
+		}
+		if latest == nil || version.Compare(v, latestVer) > 0 {
+			latest, latestVer = &tags[i], v
+		}
+	}
+	if latest == nil {
+		return nil, nil
+	}
+	return &candidate{
+		tag:         latest.Name,
+		name:        latest.Name,
+		description: latest.Message,
+		url:         fmt.Sprintf("%s/%s/-/tags/%s", c.client.baseURL, project, url.PathEscape(latest.Name)),
+		published:   latest.Commit.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,154 @@
+// Package gitlab 检查 GitLab.com 和自建 GitLab 实例上项目的新版本
+//
+// 通过 REST API（/api/v4）读取项目最新的 Release 或版本号最高的标签，
+// 私有项目使用 gitlab.token 认证。
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultBaseURL GitLab.com 的地址
+const DefaultBaseURL = "https://gitlab.com"
+
+// timeout 每个API请求的超时时间
+const timeout = 15 * time.Second
+
+// release API返回的Release中用到的字段
+type release struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	ReleasedAt  time.Time `json:"released_at"`
+	// Upcoming 发布时间在未来的Release
+	Upcoming bool `json:"upcoming_release"`
+	Links    struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+}
+
+// tag API返回的标签中用到的字段
+type tag struct {
+	Name    string `json:"name"`
+	Message string `json:"message"`
+	Commit  struct {
+		CreatedAt time.Time `json:"created_at"`
+	} `json:"commit"`
+}
+
+// Client GitLab API客户端
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewClient 创建GitLab API客户端，baseURL 为空时使用 GitLab.com，token 为空时只能访问公开项目
+func NewClient(baseURL, token, localAddr string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("无效的GitLab地址 %s: %v", baseURL, err)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: timeout, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  client,
+	}, nil
+}
+
+// latestRelease 返回项目最新发布的Release（跳过发布时间在未来的Release），没有Release时返回nil
+func (c *Client) latestRelease(project string) (*release, error) {
+	var releases []release
+	if err := c.get(project, "/releases?per_page=10", &releases); err != nil {
+		return nil, err
+	}
+	// 接口按 released_at 倒序返回
+	for i := range releases {
+		if !releases[i].Upcoming {
+			return &releases[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// tags 返回项目最近更新的标签
+func (c *Client) tags(project string) ([]tag, error) {
+	var tags []tag
+	if err := c.get(project, "/repository/tags?per_page=100&order_by=updated", &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// get 读取项目的API接口，project 为 group/subgroup/project 形式的路径
+func (c *Client) get(project, endpoint string, v any) error {
+	u := c.baseURL + "/api/v4/projects/" + url.PathEscape(project) + endpoint
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求GitLab API失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return fmt.Errorf("读取GitLab API响应失败: %v", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return fmt.Errorf("GitLab令牌无效或已过期")
+	case http.StatusNotFound:
+		// 私有项目在没有权限时同样返回404
+		return fmt.Errorf("项目不存在或没有访问权限")
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("触发GitLab API速率限制，请稍后重试")
+	default:
+		return fmt.Errorf("GitLab API返回状态码 %d: %s", resp.StatusCode, apiMessage(body))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("解析GitLab API响应失败: %v", err)
+	}
+	return nil
+}
+
+// apiMessage 提取错误响应中的 message 或 error 字段，无法解析时返回原始内容
+func apiMessage(body []byte) string {
+	var payload struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		if payload.Message != nil {
+			return fmt.Sprint(payload.Message)
+		}
+		if payload.Error != "" {
+			return payload.Error
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,123 @@
+package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// fakeGitLab 模拟GitLab API，返回 group/sub/app 的Release和 group/tools 的标签，记录收到的令牌
+func fakeGitLab(t *testing.T, released time.Time) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var tokens []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		tokens = append(tokens, r.Header.Get("PRIVATE-TOKEN"))
+		switch r.URL.RawPath {
+		case "/api/v4/projects/group%2Fsub%2Fapp/releases":
+			fmt.Fprintf(w, `[
+				{"tag_name": "v2.0.0", "name": "2.0", "released_at": %q, "upcoming_release": true},
+				{"tag_name": "v1.4.0", "name": "1.4", "description": "修复安全漏洞", "released_at": %q,
+				 "_links": {"self": "https://gitlab.example.com/group/sub/app/-/releases/v1.4.0"}}
+			]`, released.Add(48*time.Hour).Format(time.RFC3339), released.Format(time.RFC3339))
+		case "/api/v4/projects/group%2Ftools/repository/tags":
+			fmt.Fprintf(w, `[
+				{"name": "v0.10.0-rc.1", "commit": {"created_at": %q}},
+				{"name": "v0.9.1", "message": "补丁版本", "commit": {"created_at": %q}},
+				{"name": "nightly", "commit": {"created_at": %q}},
+				{"name": "v0.9.0", "commit": {"created_at": %q}}
+			]`, released.Format(time.RFC3339), released.Format(time.RFC3339), released.Format(time.RFC3339), released.Format(time.RFC3339))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "404 Project Not Found"}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &tokens
+}
+
+// TestCheckForNewReleases 测试按Release和标签检查项目、跳过未来的Release和预发布标签，以及状态去重
+func TestCheckForNewReleases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, tokens := fakeGitLab(t, time.Now().Add(-time.Hour))
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		GitLab: config.GitLabConfig{
+			Token:   "glpat-test",
+			BaseURL: server.URL + "/",
+			Projects: []config.GitLabProjectConfig{
+				{Path: "group/sub/app"},
+				{Path: "group/tools", Tags: true},
+				{Path: "group/missing"},
+			},
+		},
+		State: config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+
+	releases, err := CheckForNewReleases(cfg, true)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 2 {
+		t.Fatalf("发现 %d 个新版本，期望 2 个", len(releases))
+	}
+
+	app := releases[0]
+	if app.Source != github.SourceGitLab || app.Owner != "group/sub" || app.Repository != "app" || app.TagName != "v1.4.0" {
+		t.Errorf("Release为 %+v", app)
+	}
+	if app.HTMLURL != "https://gitlab.example.com/group/sub/app/-/releases/v1.4.0" || app.Description != "修复安全漏洞" {
+		t.Errorf("Release链接为 %s，说明为 %q", app.HTMLURL, app.Description)
+	}
+
+	tools := releases[1]
+	if tools.TagName != "v0.9.1" || tools.HTMLURL != server.URL+"/group/tools/-/tags/v0.9.1" {
+		t.Errorf("标签为 %s（%s），期望 v0.9.1", tools.TagName, tools.HTMLURL)
+	}
+
+	for _, token := range *tokens {
+		if token != "glpat-test" {
+			t.Fatalf("请求的令牌为 %q", token)
+		}
+	}
+
+	// 已通知的版本不再通知
+	releases, err = CheckForNewReleases(cfg, true)
+	if err != nil {
+		t.Fatalf("再次检查失败: %v", err)
+	}
+	if len(releases) != 0 {
+		t.Errorf("再次检查发现 %d 个新版本，期望 0 个", len(releases))
+	}
+}
+
+// TestCheckForNewReleases_CheckDays 发布时间早于检查期限的版本不通知
+func TestCheckForNewReleases_CheckDays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, _ := fakeGitLab(t, time.Now().AddDate(0, 0, -30))
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		GitLab: config.GitLabConfig{
+			BaseURL:  server.URL,
+			Projects: []config.GitLabProjectConfig{{Path: "group/sub/app"}, {Path: "group/tools", Tags: true}},
+		},
+		State: config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+	releases, err := CheckForNewReleases(cfg, false)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 0 {
+		t.Errorf("发现 %d 个新版本，期望 0 个", len(releases))
+	}
+}
@@ -0,0 +1,169 @@
+package goproxy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/source"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// pseudoVersion 伪版本（如 v0.0.0-20240101120000-abcdef123456），没有标签的模块每次提交都会变化，不通知
+var pseudoVersion = regexp.MustCompile(`\d{14}-[0-9a-f]{12}$`)
+
+// stateOwner 状态文件中的所属空间，加上来源前缀避免与GitHub上的同名仓库冲突
+const stateOwner = github.SourceGo + ":module"
+
+// checker 一次检查使用的客户端、状态和配置
+type checker struct {
+	*source.Base
+	client *Client
+}
+
+// Source Go模块版本来源
+type Source struct{}
+
+// Name 来源名称
+func (Source) Name() string { return "Go模块" }
+
+// Enabled 是否配置了Go模块
+func (Source) Enabled(cfg *config.Config) bool { return len(cfg.GoProxy.Modules) > 0 }
+
+// Check 检查新版本
+func (Source) Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	return CheckForNewReleases(cfg)
+}
+
+// CheckForNewReleases 检查配置的Go模块是否有新版本
+// 检查期限、时区和预发布版本的设置与GitHub仓库相同（github.check_days、timezone、include_prereleases）
+func CheckForNewReleases(cfg *config.Config) ([]*github.ReleaseInfo, error) {
+	modules := source.FilterShard(cfg.GoProxy.Modules, cfg.Shard, "Go模块", func(m string) string { return github.SourceGo + "/" + m })
+	if len(modules) == 0 {
+		return nil, nil
+	}
+
+	base, err := source.NewBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(cfg.GoProxy.Proxy, cfg.Network.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{
+		Base:   base,
+		client: client,
+	}
+
+	fmt.Printf("正在检查 %d 个Go模块（%s）...\n", len(modules), client.baseURL)
+	var results []*github.ReleaseInfo
+	errorCount := 0
+	for _, m := range modules {
+		info, err := c.check(strings.TrimSpace(m))
+		if err != nil {
+			fmt.Printf("检查Go模块 %s 失败: %v\n", m, err)
+			errorCount++
+			continue
+		}
+		if info != nil {
+			fmt.Printf("发现新版本: %s (%s)\n", m, info.TagName)
+			results = append(results, info)
+		}
+	}
+
+	fmt.Printf("Go模块检查完成: 发现 %d 个新版本", len(results))
+	if errorCount > 0 {
+		fmt.Printf("，%d 个模块检查失败", errorCount)
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// check 检查一个模块，有需要通知的新版本时返回版本信息
+func (c *checker) check(module string) (*github.ReleaseInfo, error) {
+	if module == "" {
+		return nil, fmt.Errorf("模块路径不能为空")
+	}
+	latest, latestVer, err := c.latestVersion(module)
+	if err != nil || latest == "" {
+		return nil, err
+	}
+
+	// 通过 notify ignore 标记的版本不通知，也不记录状态
+	if entry, ok := c.Ignored.Match(github.SourceGo, module, latest); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+	previousTag := c.Store.GetLatestTag(stateOwner, module)
+	if previousTag == latest {
+		return nil, nil
+	}
+
+	// 版本变化时才读取提交时间，超过检查期限的版本只记录状态
+	vi, err := c.client.versionInfo(module, latest)
+	if err != nil {
+		return nil, err
+	}
+	if vi.Time.Before(c.Since) {
+		if err := c.Store.UpdateState(stateOwner, module, latest); err != nil {
+			return nil, fmt.Errorf("更新版本状态失败: %v", err)
+		}
+		return nil, nil
+	}
+
+	isNew, err := c.Store.CheckAndUpdateIfNew(stateOwner, module, latest)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+	return &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Source:      github.SourceGo,
+		Owner:       github.SourceGo,
+		Repository:  module,
+		TagName:     latest,
+		Name:        latest,
+		HTMLURL:     "https://pkg.go.dev/" + module + "@" + latest,
+		PublishedAt: vi.Time.In(c.Loc),
+		Prerelease:  latestVer.IsPrerelease(),
+		PreviousTag: previousTag,
+	}, nil
+}
+
+// latestVersion 返回版本号最高的标签版本；模块没有标签版本时使用 @latest，为伪版本时返回空字符串
+func (c *checker) latestVersion(module string) (string, version.Version, error) {
+	versions, err := c.client.list(module)
+	if err != nil {
+		return "", version.Version{}, err
+	}
+	if len(versions) == 0 {
+		li, err := c.client.latest(module)
+		if err != nil {
+			return "", version.Version{}, err
+		}
+		versions = []string{li.Version}
+	}
+
+	var (
+		latest    string
+		latestVer version.Version
+	)
+	for _, v := range versions {
+		if pseudoVersion.MatchString(strings.TrimSuffix(v, "+incompatible")) {
+			continue
+		}
+		parsed, err := version.Parse(v)
+		if err != nil || (parsed.IsPrerelease() && !c.Config.GitHub.IncludePrereleases) {
+			continue
+		}
+		if latest == "" || version.Compare(parsed, latestVer) > 0 {
+			latest, latestVer = v, parsed
+		}
+	}
+	return latest, latestVer, nil
+}
@@ -0,0 +1,136 @@
+// Package goproxy 通过Go模块代理检查Go模块的新版本
+//
+// 读取 @v/list 中的版本列表，没有标签版本时读取 @latest，不依赖上游仓库是否创建GitHub Release；
+// 伪版本不通知。
+package goproxy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultProxy 默认的Go模块代理
+const DefaultProxy = "https://proxy.golang.org"
+
+// timeout 每个请求的超时时间
+const timeout = 15 * time.Second
+
+// info 版本信息（@latest、@v/<版本>.info）
+type info struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+// Client Go模块代理客户端（GOPROXY协议）
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient 创建模块代理客户端，baseURL 为空时使用 proxy.golang.org
+func NewClient(baseURL, localAddr string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultProxy
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("无效的Go模块代理地址 %s: %v", baseURL, err)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: timeout, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}, nil
+}
+
+// list 返回模块的标签版本（不含伪版本），顺序不固定
+func (c *Client) list(module string) ([]string, error) {
+	body, err := c.get(module, "/@v/list")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var versions []string
+	scanner := bufio.NewScanner(io.LimitReader(body, 4<<20))
+	for scanner.Scan() {
+		if v := strings.TrimSpace(scanner.Text()); v != "" {
+			versions = append(versions, v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取版本列表失败: %v", err)
+	}
+	return versions, nil
+}
+
+// latest 返回代理认为的最新版本，模块没有标签时为伪版本
+func (c *Client) latest(module string) (*info, error) {
+	return c.info(module, "/@latest")
+}
+
+// versionInfo 返回版本的提交时间
+func (c *Client) versionInfo(module, version string) (*info, error) {
+	return c.info(module, "/@v/"+escapePath(version)+".info")
+}
+
+func (c *Client) info(module, endpoint string) (*info, error) {
+	body, err := c.get(module, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	var v info
+	if err := json.NewDecoder(io.LimitReader(body, 1<<20)).Decode(&v); err != nil {
+		return nil, fmt.Errorf("解析版本信息失败: %v", err)
+	}
+	return &v, nil
+}
+
+// get 请求模块的代理接口，调用方负责关闭返回的响应体
+func (c *Client) get(module, endpoint string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/"+escapePath(module)+endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求Go模块代理失败: %v", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound, http.StatusGone:
+		// 代理对不存在的模块返回404或410，响应体为原因说明
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("模块不存在或无法获取: %s", strings.TrimSpace(string(msg)))
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("Go模块代理返回状态码 %d", resp.StatusCode)
+	}
+}
+
+// escapePath 按GOPROXY协议转义模块路径和版本：大写字母转为 ! 加小写字母
+func escapePath(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			b.WriteRune(r + ('a' - 'A'))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
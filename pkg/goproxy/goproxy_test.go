@@ -0,0 +1,102 @@
+package goproxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// newFakeProxy 模拟Go模块代理：github.com/!burnt!sushi/toml 有标签版本，example.com/untagged 只有伪版本
+func newFakeProxy(t *testing.T, published time.Time, versions []string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/github.com/!burnt!sushi/toml/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, strings.Join(versions, "\n")+"\n")
+	})
+	mux.HandleFunc("/github.com/!burnt!sushi/toml/@v/", func(w http.ResponseWriter, r *http.Request) {
+		v := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/github.com/!burnt!sushi/toml/@v/"), ".info")
+		fmt.Fprintf(w, `{"Version": %q, "Time": %q}`, v, published.Format(time.RFC3339))
+	})
+	mux.HandleFunc("/example.com/untagged/@v/list", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/example.com/untagged/@latest", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"Version": "v0.0.0-20240101120000-abcdef123456", "Time": %q}`, published.Format(time.RFC3339))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testConfig(t *testing.T, proxy string) *config.Config {
+	return &config.Config{
+		GitHub:  config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		GoProxy: config.GoProxyConfig{Proxy: proxy, Modules: []string{"github.com/BurntSushi/toml", "example.com/untagged", "example.com/missing"}},
+		State:   config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+}
+
+// TestCheckForNewReleases 测试转义模块路径、按版本号选择最新版本、跳过伪版本和预发布版本，以及状态去重
+func TestCheckForNewReleases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newFakeProxy(t, time.Now().Add(-time.Hour), []string{"v1.3.2", "v1.10.0", "v1.4.0", "v2.0.0-rc.1"})
+	cfg := testConfig(t, server.URL)
+
+	releases, err := CheckForNewReleases(cfg)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("发现 %d 个新版本，期望 1 个", len(releases))
+	}
+	r := releases[0]
+	if r.Source != github.SourceGo || r.Repository != "github.com/BurntSushi/toml" || r.TagName != "v1.10.0" || r.Prerelease {
+		t.Errorf("版本为 %+v", r)
+	}
+	if r.HTMLURL != "https://pkg.go.dev/github.com/BurntSushi/toml@v1.10.0" {
+		t.Errorf("链接为 %s", r.HTMLURL)
+	}
+
+	// 版本没有变化时不重复通知
+	releases, err = CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 0 {
+		t.Errorf("第二次检查发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+
+	// 包含预发布版本时通知 v2.0.0-rc.1
+	cfg.GitHub.IncludePrereleases = true
+	releases, err = CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 1 || releases[0].TagName != "v2.0.0-rc.1" || releases[0].PreviousTag != "v1.10.0" {
+		t.Fatalf("包含预发布版本时的结果为 %+v（%v）", releases, err)
+	}
+}
+
+// TestCheckForNewReleases_Old 超过检查期限的版本只记录状态，不通知
+func TestCheckForNewReleases_Old(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newFakeProxy(t, time.Now().AddDate(0, 0, -30), []string{"v1.4.0"})
+	cfg := testConfig(t, server.URL)
+
+	releases, err := CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 0 {
+		t.Fatalf("发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	for in, want := range map[string]string{
+		"golang.org/x/net":           "golang.org/x/net",
+		"github.com/BurntSushi/toml": "github.com/!burnt!sushi/toml",
+		"v1.0.0-RC1":                 "v1.0.0-!r!c1",
+	} {
+		if got := escapePath(in); got != want {
+			t.Errorf("escapePath(%q) = %q，期望 %q", in, got, want)
+		}
+	}
+}
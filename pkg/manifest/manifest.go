@@ -0,0 +1,192 @@
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// exactNpmVersion package.json中精确锁定的版本，如 1.2.0、=1.2.0、v1.2.0-rc.1
+// ^1.2.0、~1.2、1.x、>=1.0.0、latest 等范围和标签不是锁定版本
+var exactNpmVersion = regexp.MustCompile(`^\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// Ecosystem 依赖所属的生态
+type Ecosystem string
+
+const (
+	// EcosystemGo Go模块（go.mod）
+	EcosystemGo Ecosystem = "go"
+	// EcosystemNpm npm包（package.json）
+	EcosystemNpm Ecosystem = "npm"
+	// EcosystemPyPI Python包（requirements.txt）
+	EcosystemPyPI Ecosystem = "pypi"
+)
+
+// Dependency 依赖清单中的一条直接依赖
+type Dependency struct {
+	Ecosystem Ecosystem
+	// Name 模块路径或包名
+	Name string
+	// Version 清单中锁定的版本，可能为空（未锁定）
+	Version string
+	// Owner/Repo 解析得到的GitHub仓库，未解析时为空
+	Owner string
+	Repo  string
+}
+
+// Parse 根据文件名识别清单类型并解析其中的直接依赖
+func Parse(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取依赖清单失败: %v", err)
+	}
+
+	base := strings.ToLower(filepath.Base(path))
+	switch {
+	case base == "go.mod":
+		return ParseGoMod(data)
+	case base == "package.json":
+		return ParsePackageJSON(data)
+	case strings.HasSuffix(base, ".txt") && strings.Contains(base, "requirements"):
+		return ParseRequirements(data)
+	default:
+		return nil, fmt.Errorf("不支持的依赖清单类型: %s", filepath.Base(path))
+	}
+}
+
+// ParseGoMod 解析go.mod中的直接依赖（忽略 // indirect）
+func ParseGoMod(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+	inRequire := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "require (":
+			inRequire = true
+			continue
+		case inRequire && line == ")":
+			inRequire = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		case !inRequire:
+			continue
+		}
+
+		// 跳过间接依赖
+		if strings.Contains(line, "// indirect") {
+			continue
+		}
+		if idx := strings.Index(line, "//"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Ecosystem: EcosystemGo,
+			Name:      fields[0],
+			Version:   fields[1],
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("解析go.mod失败: %v", err)
+	}
+	return deps, nil
+}
+
+// ParsePackageJSON 解析package.json中的dependencies和devDependencies
+// 只有精确版本才作为锁定版本，版本范围、标签和 git/file 等地址的依赖没有锁定版本
+func ParsePackageJSON(data []byte) ([]Dependency, error) {
+	var pkg struct {
+		Dependencies    map[string]string `json:"dependencies"`
+		DevDependencies map[string]string `json:"devDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("解析package.json失败: %v", err)
+	}
+
+	var deps []Dependency
+	for _, group := range []map[string]string{pkg.Dependencies, pkg.DevDependencies} {
+		for name, version := range group {
+			deps = append(deps, Dependency{
+				Ecosystem: EcosystemNpm,
+				Name:      name,
+				Version:   npmPinnedVersion(version),
+			})
+		}
+	}
+	return deps, nil
+}
+
+// npmPinnedVersion 返回package.json中精确锁定的版本，不是精确版本时返回空字符串
+func npmPinnedVersion(spec string) string {
+	v := strings.TrimLeft(strings.TrimSpace(spec), "=v ")
+	if !exactNpmVersion.MatchString(v) {
+		return ""
+	}
+	return v
+}
+
+// ParseRequirements 解析requirements.txt中的依赖
+func ParseRequirements(data []byte) ([]Dependency, error) {
+	var deps []Dependency
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+		// 跳过空行以及 -r/-e/--index-url 等选项
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		// 去掉环境标记，如 ; python_version < "3.8"
+		if idx := strings.Index(line, ";"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		name, version := line, ""
+		for _, op := range []string{"===", "==", "~=", ">=", "<=", "!=", ">", "<"} {
+			if idx := strings.Index(line, op); idx >= 0 {
+				name = strings.TrimSpace(line[:idx])
+				version = strings.TrimSpace(line[idx+len(op):])
+				// 只有精确锁定（==）才认为是锁定版本
+				if op != "==" && op != "===" {
+					version = ""
+				}
+				break
+			}
+		}
+		// 去掉extras，如 requests[socks]
+		if idx := strings.Index(name, "["); idx >= 0 {
+			name = name[:idx]
+		}
+		if idx := strings.Index(version, ","); idx >= 0 {
+			version = version[:idx]
+		}
+
+		deps = append(deps, Dependency{
+			Ecosystem: EcosystemPyPI,
+			Name:      name,
+			Version:   version,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("解析requirements.txt失败: %v", err)
+	}
+	return deps, nil
+}
@@ -0,0 +1,123 @@
+package manifest
+
+import "testing"
+
+// TestParseGoMod 测试go.mod直接依赖解析
+func TestParseGoMod(t *testing.T) {
+	data := []byte(`module example.com/app
+
+go 1.25
+
+require github.com/spf13/cobra v1.10.1
+
+require (
+	github.com/google/go-github/v71 v71.0.0
+	golang.org/x/oauth2 v0.33.0 // 注释
+	github.com/fsnotify/fsnotify v1.9.0 // indirect
+)
+`)
+
+	deps, err := ParseGoMod(data)
+	if err != nil {
+		t.Fatalf("ParseGoMod 失败: %v", err)
+	}
+
+	want := map[string]string{
+		"github.com/spf13/cobra":          "v1.10.1",
+		"github.com/google/go-github/v71": "v71.0.0",
+		"golang.org/x/oauth2":             "v0.33.0",
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("期望 %d 个直接依赖，实际 %d 个: %+v", len(want), len(deps), deps)
+	}
+	for _, dep := range deps {
+		if want[dep.Name] != dep.Version {
+			t.Errorf("依赖 %s 的版本为 %s，期望 %s", dep.Name, dep.Version, want[dep.Name])
+		}
+	}
+}
+
+// TestParseRequirements 测试requirements.txt解析
+func TestParseRequirements(t *testing.T) {
+	data := []byte(`# 注释
+-r base.txt
+requests[socks]==2.31.0
+django>=4.2
+numpy==1.26.4 ; python_version >= "3.9"
+`)
+
+	deps, err := ParseRequirements(data)
+	if err != nil {
+		t.Fatalf("ParseRequirements 失败: %v", err)
+	}
+
+	want := map[string]string{"requests": "2.31.0", "django": "", "numpy": "1.26.4"}
+	if len(deps) != len(want) {
+		t.Fatalf("期望 %d 个依赖，实际 %d 个: %+v", len(want), len(deps), deps)
+	}
+	for _, dep := range deps {
+		if v, ok := want[dep.Name]; !ok || v != dep.Version {
+			t.Errorf("依赖 %s 的版本为 %q，期望 %q", dep.Name, dep.Version, v)
+		}
+	}
+}
+
+// TestParsePackageJSON 测试package.json解析，只有精确版本作为锁定版本
+func TestParsePackageJSON(t *testing.T) {
+	data := []byte(`{
+  "dependencies": {
+    "exact": "1.2.0",
+    "equals": "=2.0.1",
+    "prefixed": "v3.1.0-rc.1",
+    "caret": "^1.2.0",
+    "tilde": "~1.2",
+    "wildcard": "1.x",
+    "range": ">=1.0.0 <2.0.0",
+    "union": "1.2.0 || 2.0.0",
+    "tag": "latest",
+    "git": "github:owner/repo#v1.0.0"
+  },
+  "devDependencies": {"dev": "4.5.6"}
+}`)
+
+	deps, err := ParsePackageJSON(data)
+	if err != nil {
+		t.Fatalf("ParsePackageJSON 失败: %v", err)
+	}
+
+	want := map[string]string{
+		"exact": "1.2.0", "equals": "2.0.1", "prefixed": "3.1.0-rc.1",
+		"caret": "", "tilde": "", "wildcard": "", "range": "", "union": "", "tag": "", "git": "",
+		"dev": "4.5.6",
+	}
+	if len(deps) != len(want) {
+		t.Fatalf("期望 %d 个依赖，实际 %d 个: %+v", len(want), len(deps), deps)
+	}
+	for _, dep := range deps {
+		if v, ok := want[dep.Name]; !ok || v != dep.Version {
+			t.Errorf("依赖 %s 的版本为 %q，期望 %q", dep.Name, dep.Version, v)
+		}
+	}
+}
+
+// TestParseGitHubURL 测试仓库地址解析
+func TestParseGitHubURL(t *testing.T) {
+	cases := map[string][2]string{
+		"https://github.com/spf13/cobra":               {"spf13", "cobra"},
+		"git+https://github.com/expressjs/express.git": {"expressjs", "express"},
+		"git@github.com:lodash/lodash.git":             {"lodash", "lodash"},
+		"github:facebook/react":                        {"facebook", "react"},
+		"https://github.com/psf/requests/tree/main":    {"psf", "requests"},
+	}
+
+	for raw, want := range cases {
+		owner, repo, ok := ParseGitHubURL(raw)
+		if !ok || owner != want[0] || repo != want[1] {
+			t.Errorf("ParseGitHubURL(%q) = %s/%s (%v)，期望 %s/%s", raw, owner, repo, ok, want[0], want[1])
+		}
+	}
+
+	if _, _, ok := ParseGitHubURL("https://gitlab.com/a/b"); ok {
+		t.Error("非GitHub地址不应该解析成功")
+	}
+}
@@ -0,0 +1,215 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// goImportPattern 匹配 go-get 页面中的 go-import meta 标签
+var goImportPattern = regexp.MustCompile(`<meta\s+name="go-import"\s+content="([^"]+)"`)
+
+// Resolver 将依赖解析为GitHub仓库
+type Resolver struct {
+	client *http.Client
+	// out 解析失败的警告的输出位置
+	out io.Writer
+}
+
+// NewResolver 创建依赖解析器，解析失败的警告输出到 out
+func NewResolver(out io.Writer) *Resolver {
+	return &Resolver{
+		out: out,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Resolve 为每个依赖填充Owner/Repo，返回成功解析的依赖和解析失败的依赖数
+// 不在GitHub上托管的依赖不计为失败
+func (r *Resolver) Resolve(deps []Dependency) ([]Dependency, int) {
+	var resolved []Dependency
+	failed := 0
+	for _, dep := range deps {
+		owner, repo, err := r.resolveOne(dep)
+		if err != nil {
+			fmt.Fprintf(r.out, "警告: 解析依赖 %s (%s) 对应的GitHub仓库失败: %v\n", dep.Name, dep.Ecosystem, err)
+			failed++
+			continue
+		}
+		if owner == "" || repo == "" {
+			continue
+		}
+		dep.Owner = owner
+		dep.Repo = repo
+		resolved = append(resolved, dep)
+	}
+	return resolved, failed
+}
+
+// resolveOne 解析单个依赖
+func (r *Resolver) resolveOne(dep Dependency) (string, string, error) {
+	switch dep.Ecosystem {
+	case EcosystemGo:
+		return r.resolveGoModule(dep.Name)
+	case EcosystemNpm:
+		return r.resolveNpmPackage(dep.Name)
+	case EcosystemPyPI:
+		return r.resolvePyPIPackage(dep.Name)
+	default:
+		return "", "", fmt.Errorf("未知的依赖生态: %s", dep.Ecosystem)
+	}
+}
+
+// resolveGoModule 解析Go模块路径
+func (r *Resolver) resolveGoModule(path string) (string, string, error) {
+	parts := strings.Split(path, "/")
+
+	switch {
+	case parts[0] == "github.com" && len(parts) >= 3:
+		return parts[1], parts[2], nil
+	case parts[0] == "golang.org" && len(parts) >= 3 && parts[1] == "x":
+		return "golang", parts[2], nil
+	case parts[0] == "gopkg.in":
+		// gopkg.in/yaml.v3 -> go-yaml/yaml，gopkg.in/user/pkg.v1 -> user/pkg
+		if len(parts) == 2 {
+			name := strings.SplitN(parts[1], ".", 2)[0]
+			return "go-" + name, name, nil
+		}
+		if len(parts) >= 3 {
+			return parts[1], strings.SplitN(parts[2], ".", 2)[0], nil
+		}
+	}
+
+	// 其他自定义域名通过 go-get=1 查询 go-import 元信息
+	resp, err := r.client.Get("https://" + path + "?go-get=1")
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", "", err
+	}
+
+	for _, match := range goImportPattern.FindAllStringSubmatch(string(body), -1) {
+		fields := strings.Fields(match[1])
+		if len(fields) != 3 || !strings.HasPrefix(path, fields[0]) {
+			continue
+		}
+		if owner, repo, ok := ParseGitHubURL(fields[2]); ok {
+			return owner, repo, nil
+		}
+	}
+	return "", "", nil
+}
+
+// resolveNpmPackage 通过npm registry查询包的仓库地址
+func (r *Resolver) resolveNpmPackage(name string) (string, string, error) {
+	var pkg struct {
+		Repository json.RawMessage `json:"repository"`
+	}
+	apiURL := "https://registry.npmjs.org/" + strings.Replace(name, "/", "%2F", 1) + "/latest"
+	if err := r.getJSON(apiURL, &pkg); err != nil {
+		return "", "", err
+	}
+
+	// repository 可能是字符串或 {type, url} 对象
+	var repoURL string
+	if err := json.Unmarshal(pkg.Repository, &repoURL); err != nil {
+		var obj struct {
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal(pkg.Repository, &obj); err == nil {
+			repoURL = obj.URL
+		}
+	}
+
+	if owner, repo, ok := ParseGitHubURL(repoURL); ok {
+		return owner, repo, nil
+	}
+	return "", "", nil
+}
+
+// resolvePyPIPackage 通过PyPI JSON API查询包的仓库地址
+func (r *Resolver) resolvePyPIPackage(name string) (string, string, error) {
+	var pkg struct {
+		Info struct {
+			HomePage    string            `json:"home_page"`
+			ProjectURLs map[string]string `json:"project_urls"`
+		} `json:"info"`
+	}
+	if err := r.getJSON("https://pypi.org/pypi/"+url.PathEscape(name)+"/json", &pkg); err != nil {
+		return "", "", err
+	}
+
+	// 优先使用 Source/Repository 等明确的源码地址
+	for _, key := range []string{"Source", "Source Code", "Repository", "Code", "GitHub", "Homepage"} {
+		if owner, repo, ok := ParseGitHubURL(pkg.Info.ProjectURLs[key]); ok {
+			return owner, repo, nil
+		}
+	}
+	for _, u := range pkg.Info.ProjectURLs {
+		if owner, repo, ok := ParseGitHubURL(u); ok {
+			return owner, repo, nil
+		}
+	}
+	if owner, repo, ok := ParseGitHubURL(pkg.Info.HomePage); ok {
+		return owner, repo, nil
+	}
+	return "", "", nil
+}
+
+// getJSON 请求URL并解析JSON响应
+func (r *Resolver) getJSON(apiURL string, v interface{}) error {
+	resp, err := r.client.Get(apiURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// ParseGitHubURL 从各种形式的仓库地址中提取GitHub的owner和repo
+// 支持 https://github.com/a/b、git+https://github.com/a/b.git、git@github.com:a/b.git、github:a/b 等形式
+func ParseGitHubURL(raw string) (string, string, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", false
+	}
+
+	var rest string
+	switch {
+	case strings.HasPrefix(raw, "github:"):
+		rest = strings.TrimPrefix(raw, "github:")
+	case strings.HasPrefix(raw, "git@github.com:"):
+		rest = strings.TrimPrefix(raw, "git@github.com:")
+	default:
+		idx := strings.Index(raw, "github.com/")
+		if idx < 0 {
+			return "", "", false
+		}
+		rest = raw[idx+len("github.com/"):]
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	repo := strings.TrimSuffix(parts[1], ".git")
+	if idx := strings.IndexAny(repo, "#?"); idx >= 0 {
+		repo = repo[:idx]
+	}
+	return parts[0], repo, repo != ""
+}
@@ -0,0 +1,207 @@
+package maven
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/source"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// prereleasePattern Maven版本中表示预发布的限定符，如 2.0.0-M1、1.0-beta-2、6.0.0.RC1
+// jre、android、Final、GA 等限定符不是预发布
+var prereleasePattern = regexp.MustCompile(`(?i)[.\-](alpha|beta|milestone|m|rc|cr|preview|ea|a|b)[.\-]?\d*(?:$|[.\-])`)
+
+// isSnapshot 是否为快照版本，快照版本不通知
+func isSnapshot(v string) bool {
+	return strings.HasSuffix(strings.ToUpper(v), "-SNAPSHOT")
+}
+
+// isPrerelease 是否为预发布版本
+func isPrerelease(v string) bool {
+	return prereleasePattern.MatchString(v)
+}
+
+// stateOwner 状态文件中的所属空间，加上来源前缀和仓库地址，避免与GitHub上的同名仓库以及其他仓库中的同名构件冲突
+func stateOwner(baseURL string) string {
+	host := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		host = u.Host + u.Path
+	}
+	return github.SourceMaven + ":" + host
+}
+
+// parseCoordinate 解析 groupId:artifactId 形式的坐标
+func parseCoordinate(s string) (groupID, artifactID string, err error) {
+	groupID, artifactID, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || groupID == "" || artifactID == "" || strings.ContainsAny(artifactID, ":/") || strings.Contains(groupID, "/") {
+		return "", "", fmt.Errorf("无效的Maven坐标: %s（应为 groupId:artifactId）", s)
+	}
+	return groupID, artifactID, nil
+}
+
+// checker 一次检查使用的客户端、状态和配置
+type checker struct {
+	*source.Base
+	client *Client
+}
+
+// Source Maven构件版本来源
+type Source struct{}
+
+// Name 来源名称
+func (Source) Name() string { return "Maven构件" }
+
+// Enabled 是否配置了Maven构件
+func (Source) Enabled(cfg *config.Config) bool { return len(cfg.Maven.Packages) > 0 }
+
+// Check 检查新版本
+func (Source) Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	return CheckForNewReleases(cfg)
+}
+
+// CheckForNewReleases 检查配置的Maven构件是否有新版本
+// 检查期限、时区和预发布版本的设置与GitHub仓库相同（github.check_days、timezone、include_prereleases）
+func CheckForNewReleases(cfg *config.Config) ([]*github.ReleaseInfo, error) {
+	packages := source.FilterShard(cfg.Maven.Packages, cfg.Shard, "Maven构件", func(p string) string { return github.SourceMaven + "/" + p })
+	if len(packages) == 0 {
+		return nil, nil
+	}
+
+	base, err := source.NewBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(cfg.Maven.Repository, cfg.Maven.Username, cfg.Maven.Password, cfg.Network.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{
+		Base:   base,
+		client: client,
+	}
+
+	fmt.Printf("正在检查 %d 个Maven构件（%s）...\n", len(packages), client.baseURL)
+	var results []*github.ReleaseInfo
+	errorCount := 0
+	for _, p := range packages {
+		info, err := c.check(p)
+		if err != nil {
+			fmt.Printf("检查Maven构件 %s 失败: %v\n", p, err)
+			errorCount++
+			continue
+		}
+		if info != nil {
+			fmt.Printf("发现新版本: %s (%s)\n", p, info.TagName)
+			results = append(results, info)
+		}
+	}
+
+	fmt.Printf("Maven构件检查完成: 发现 %d 个新版本", len(results))
+	if errorCount > 0 {
+		fmt.Printf("，%d 个构件检查失败", errorCount)
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// check 检查一个构件，有需要通知的新版本时返回版本信息
+func (c *checker) check(coordinate string) (*github.ReleaseInfo, error) {
+	groupID, artifactID, err := parseCoordinate(coordinate)
+	if err != nil {
+		return nil, err
+	}
+	name := groupID + ":" + artifactID
+
+	m, err := c.client.metadata(groupID, artifactID)
+	if err != nil {
+		return nil, err
+	}
+	latest := c.latestVersion(m)
+	if latest == "" {
+		return nil, nil
+	}
+
+	// 通过 notify ignore 标记的版本不通知，也不记录状态
+	if entry, ok := c.Ignored.Match(github.SourceMaven, name, latest); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+	owner := stateOwner(c.client.baseURL)
+	previousTag := c.Store.GetLatestTag(owner, name)
+	if previousTag == latest {
+		return nil, nil
+	}
+
+	// 版本变化时才读取POM的发布时间，仓库没有返回时使用元数据的更新时间；超过检查期限的版本只记录状态
+	published, err := c.client.published(groupID, artifactID, latest)
+	if err != nil || published.IsZero() {
+		published = m.updated()
+	}
+	if !published.IsZero() && published.Before(c.Since) {
+		if err := c.Store.UpdateState(owner, name, latest); err != nil {
+			return nil, fmt.Errorf("更新版本状态失败: %v", err)
+		}
+		return nil, nil
+	}
+	if published.IsZero() {
+		published = time.Now()
+	}
+
+	isNew, err := c.Store.CheckAndUpdateIfNew(owner, name, latest)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+	return &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Source:      github.SourceMaven,
+		Owner:       github.SourceMaven,
+		Repository:  name,
+		TagName:     latest,
+		Name:        latest,
+		HTMLURL:     c.versionURL(groupID, artifactID, latest),
+		PublishedAt: published.In(c.Loc),
+		Prerelease:  isPrerelease(latest),
+		PreviousTag: previousTag,
+	}, nil
+}
+
+// latestVersion 返回版本号最高的版本，跳过快照版本，未包含预发布版本时跳过预发布版本
+// 无法按语义化版本解析的版本按 maven-metadata.xml 中的发布顺序比较
+func (c *checker) latestVersion(m *metadata) string {
+	var (
+		latest    string
+		latestVer version.Version
+		parsedOK  bool
+	)
+	for _, v := range m.Versioning.Versions {
+		v = strings.TrimSpace(v)
+		if v == "" || isSnapshot(v) || (isPrerelease(v) && !c.Config.GitHub.IncludePrereleases) {
+			continue
+		}
+		parsed, err := version.Parse(v)
+		switch {
+		case latest == "":
+		case err == nil && parsedOK && version.Compare(parsed, latestVer) <= 0:
+			continue
+		}
+		latest, latestVer, parsedOK = v, parsed, err == nil
+	}
+	return latest
+}
+
+// versionURL 返回版本页面：Maven Central 使用 central.sonatype.com，其他仓库使用版本目录
+func (c *checker) versionURL(groupID, artifactID, v string) string {
+	if c.client.baseURL == DefaultRepository {
+		return "https://central.sonatype.com/artifact/" + groupID + "/" + artifactID + "/" + v
+	}
+	return c.client.artifactURL(groupID, artifactID) + "/" + v + "/"
+}
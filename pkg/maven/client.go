@@ -0,0 +1,131 @@
+// Package maven 检查Maven仓库中构件的新版本
+//
+// 读取构件的 maven-metadata.xml 中的版本列表，支持 Maven Central 以及 Nexus、Artifactory 等私有仓库；
+// 快照版本不通知。
+package maven
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultRepository Maven Central 的地址
+const DefaultRepository = "https://repo1.maven.org/maven2"
+
+// timeout 每个请求的超时时间
+const timeout = 15 * time.Second
+
+// metadata maven-metadata.xml 中用到的字段
+type metadata struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Versioning struct {
+		Latest      string   `xml:"latest"`
+		Release     string   `xml:"release"`
+		Versions    []string `xml:"versions>version"`
+		LastUpdated string   `xml:"lastUpdated"`
+	} `xml:"versioning"`
+}
+
+// updated 返回元数据的最后更新时间（yyyyMMddHHmmss，UTC），无法解析时返回零值
+func (m *metadata) updated() time.Time {
+	t, _ := time.Parse("20060102150405", m.Versioning.LastUpdated)
+	return t
+}
+
+// Client Maven仓库客户端
+type Client struct {
+	baseURL  string
+	username string
+	password string
+	client   *http.Client
+}
+
+// NewClient 创建Maven仓库客户端，baseURL 为空时使用 Maven Central，设置了 username 时使用Basic认证
+func NewClient(baseURL, username, password, localAddr string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultRepository
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("无效的Maven仓库地址 %s: %v", baseURL, err)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: timeout, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &Client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+		client:   client,
+	}, nil
+}
+
+// artifactURL 返回构件目录的地址，groupId 中的 . 转换为路径分隔符
+func (c *Client) artifactURL(groupID, artifactID string) string {
+	return c.baseURL + "/" + strings.ReplaceAll(groupID, ".", "/") + "/" + artifactID
+}
+
+// metadata 读取构件的 maven-metadata.xml
+func (c *Client) metadata(groupID, artifactID string) (*metadata, error) {
+	resp, err := c.do(http.MethodGet, c.artifactURL(groupID, artifactID)+"/maven-metadata.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var m metadata
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, 8<<20)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("解析maven-metadata.xml失败: %v", err)
+	}
+	return &m, nil
+}
+
+// published 返回版本POM文件的 Last-Modified 时间，作为版本的发布时间；仓库没有返回时为零值
+func (c *Client) published(groupID, artifactID, version string) (time.Time, error) {
+	resp, err := c.do(http.MethodHead, c.artifactURL(groupID, artifactID)+"/"+version+"/"+artifactID+"-"+version+".pom")
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp.Body.Close()
+
+	t, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return t, nil
+}
+
+// do 发送请求，状态码不是200时返回错误，调用方负责关闭响应体
+func (c *Client) do(method, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求Maven仓库失败: %v", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("构件不存在")
+	case http.StatusUnauthorized, http.StatusForbidden:
+		resp.Body.Close()
+		return nil, fmt.Errorf("Maven仓库拒绝访问（状态码 %d），请检查 maven.username 和 maven.password", resp.StatusCode)
+	default:
+		resp.Body.Close()
+		return nil, fmt.Errorf("Maven仓库返回状态码 %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,117 @@
+package maven
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// newFakeRepository 模拟Maven仓库，com.google.guava:guava 的版本为 versions，POM的发布时间为 published
+func newFakeRepository(t *testing.T, published time.Time, versions ...string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/com/google/guava/guava/maven-metadata.xml", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "u" || pass != "p" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><metadata><groupId>com.google.guava</groupId><artifactId>guava</artifactId><versioning><versions>`)
+		for _, v := range versions {
+			fmt.Fprintf(w, "<version>%s</version>", v)
+		}
+		fmt.Fprint(w, `</versions><lastUpdated>20240701090000</lastUpdated></versioning></metadata>`)
+	})
+	mux.HandleFunc("/com/google/guava/guava/", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ".pom") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Last-Modified", published.UTC().Format(http.TimeFormat))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testConfig(t *testing.T, repository string) *config.Config {
+	return &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		Maven:  config.MavenConfig{Repository: repository, Username: "u", Password: "p", Packages: []string{"com.google.guava:guava", "com.example:missing", "invalid"}},
+		State:  config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+}
+
+// TestCheckForNewReleases 测试按版本号选择最新版本、跳过快照和预发布版本、Basic认证以及状态去重
+func TestCheckForNewReleases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newFakeRepository(t, time.Now().Add(-time.Hour), "33.1.0-jre", "33.2.0-jre", "32.1.3-jre", "34.0.0-rc1", "34.0.0-SNAPSHOT")
+	cfg := testConfig(t, server.URL)
+
+	releases, err := CheckForNewReleases(cfg)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("发现 %d 个新版本，期望 1 个", len(releases))
+	}
+	r := releases[0]
+	if r.Source != github.SourceMaven || r.Repository != "com.google.guava:guava" || r.TagName != "33.2.0-jre" || r.Prerelease {
+		t.Errorf("版本为 %+v", r)
+	}
+	if r.HTMLURL != server.URL+"/com/google/guava/guava/33.2.0-jre/" {
+		t.Errorf("链接为 %s", r.HTMLURL)
+	}
+
+	// 版本没有变化时不重复通知
+	releases, err = CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 0 {
+		t.Errorf("第二次检查发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+
+	// 包含预发布版本时通知 34.0.0-rc1
+	cfg.GitHub.IncludePrereleases = true
+	releases, err = CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 1 || releases[0].TagName != "34.0.0-rc1" || !releases[0].Prerelease {
+		t.Fatalf("包含预发布版本时的结果为 %+v（%v）", releases, err)
+	}
+}
+
+// TestCheckForNewReleases_Old 超过检查期限的版本只记录状态，不通知
+func TestCheckForNewReleases_Old(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newFakeRepository(t, time.Now().AddDate(0, 0, -30), "1.0.0")
+	cfg := testConfig(t, server.URL)
+
+	releases, err := CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 0 {
+		t.Fatalf("发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+}
+
+func TestIsPrerelease(t *testing.T) {
+	for v, want := range map[string]bool{
+		"2.0.0-M1":        true,
+		"1.0-beta-2":      true,
+		"6.0.0.RC1":       true,
+		"5.0.0-alpha1":    true,
+		"21-ea":           true,
+		"33.2.0-jre":      false,
+		"33.2.0-android":  false,
+		"5.3.9.Final":     false,
+		"2.17.0":          false,
+		"1.0.0.GA":        false,
+		"3.0.0-milestone": true,
+	} {
+		if got := isPrerelease(v); got != want {
+			t.Errorf("isPrerelease(%q) = %v，期望 %v", v, got, want)
+		}
+	}
+}
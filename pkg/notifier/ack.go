@@ -0,0 +1,204 @@
+package notifier
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// maxAckRecords 确认记录最多保留的条数，超过时丢弃最早发送的记录
+const maxAckRecords = 1000
+
+// Acker 可选接口，渠道支持在版本消息中附带"已查看"按钮并读取按钮的回调
+type Acker interface {
+	// PollAcks 读取上次读取以来的按钮回调，返回确认标识到确认人的映射
+	PollAcks() (map[string]string, error)
+}
+
+// AckRecord 发送到启用确认跟踪的渠道的一个版本及其查看情况
+type AckRecord struct {
+	// ID 消息的确认标识，同一条消息中的版本相同，点击一次"已查看"确认整条消息
+	ID         string    `json:"id"`
+	Channel    string    `json:"channel"`
+	Source     string    `json:"source,omitempty"`
+	Owner      string    `json:"owner"`
+	Repository string    `json:"repository"`
+	TagName    string    `json:"tag_name"`
+	Event      string    `json:"event,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	SentAt     time.Time `json:"sent_at"`
+	// AckedAt 第一次点击"已查看"的时间，尚未查看时为nil
+	AckedAt *time.Time `json:"acked_at,omitempty"`
+	AckedBy string     `json:"acked_by,omitempty"`
+}
+
+// loadAcks 读取确认记录，文件不存在时返回空列表
+// 确认记录会被 notify history 等命令从其他进程修改，每次操作都重新读取文件
+func loadAcks(path string) ([]AckRecord, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取确认记录失败: %v", err)
+	}
+	var records []AckRecord
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &records); err != nil {
+			return nil, fmt.Errorf("解析确认记录失败: %v", err)
+		}
+	}
+	return records, nil
+}
+
+// saveAcks 保存确认记录
+func saveAcks(path string, records []AckRecord) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建确认记录目录失败: %v", err)
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化确认记录失败: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("保存确认记录失败: %v", err)
+	}
+	return nil
+}
+
+// newAckID 生成消息的确认标识，由渠道、版本列表和时间计算，长度满足Telegram按钮回调数据的限制
+func newAckID(channel string, releases []*github.ReleaseInfo, now time.Time) string {
+	h := sha1.New()
+	fmt.Fprintln(h, channel)
+	for _, release := range releases {
+		fmt.Fprintln(h, release.Key)
+	}
+	fmt.Fprint(h, now.UnixNano())
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// ackID 返回发送到渠道的消息的确认标识，渠道未启用确认跟踪时返回空字符串
+func (m *Manager) ackID(channel string, releases []*github.ReleaseInfo) string {
+	if !m.ackChannels[channel] {
+		return ""
+	}
+	return newAckID(channel, releases, m.clock.Now())
+}
+
+// recordAcks 记录发送成功的消息中的版本，等待确认；id 为空（渠道未启用确认跟踪）时不记录
+func (m *Manager) recordAcks(channel, id string, releases []*github.ReleaseInfo) {
+	if id == "" {
+		return
+	}
+	records, err := loadAcks(m.acksPath)
+	if err != nil {
+		log.Printf("警告: %v", err)
+		return
+	}
+	now := m.clock.Now()
+	for _, release := range releases {
+		records = append(records, AckRecord{
+			ID:         id,
+			Channel:    channel,
+			Source:     release.Source,
+			Owner:      release.Owner,
+			Repository: release.Repository,
+			TagName:    release.TagName,
+			Event:      release.Event,
+			URL:        release.Link(),
+			SentAt:     now,
+		})
+	}
+	if len(records) > maxAckRecords {
+		records = records[len(records)-maxAckRecords:]
+	}
+	if err := saveAcks(m.acksPath, records); err != nil {
+		log.Printf("警告: %v", err)
+	}
+}
+
+// ProcessAcks 读取启用确认跟踪的渠道中"已查看"按钮的回调，记录确认人和时间
+func (m *Manager) ProcessAcks() []error {
+	if len(m.ackChannels) == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var errors []error
+	acks := make(map[string]string)
+	for _, n := range m.notifiers {
+		acker, ok := n.(Acker)
+		if !ok || !m.ackChannels[n.Name()] {
+			continue
+		}
+		polled, err := acker.PollAcks()
+		if err != nil {
+			errors = append(errors, fmt.Errorf("%s: %v", n.Name(), err))
+			continue
+		}
+		for id, by := range polled {
+			acks[id] = by
+		}
+	}
+	if len(acks) == 0 {
+		return errors
+	}
+
+	records, err := loadAcks(m.acksPath)
+	if err != nil {
+		return append(errors, err)
+	}
+	now := m.clock.Now()
+	for i := range records {
+		by, ok := acks[records[i].ID]
+		if !ok || records[i].AckedAt != nil {
+			continue
+		}
+		records[i].AckedAt = &now
+		records[i].AckedBy = by
+	}
+	if err := saveAcks(m.acksPath, records); err != nil {
+		return append(errors, err)
+	}
+	return errors
+}
+
+// AckRecords 返回确认记录，按发送顺序排列
+func (m *Manager) AckRecords() ([]AckRecord, error) {
+	return loadAcks(m.acksPath)
+}
+
+// AckEnabled 是否有渠道启用了确认跟踪
+func (m *Manager) AckEnabled() bool {
+	return len(m.ackChannels) > 0
+}
+
+// PruneAcks 删除 before 之前发送的确认记录，返回删除的记录数
+func (m *Manager) PruneAcks(before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records, err := loadAcks(m.acksPath)
+	if err != nil {
+		return 0, err
+	}
+	kept := records[:0]
+	for _, r := range records {
+		if !r.SentAt.Before(before) {
+			kept = append(kept, r)
+		}
+	}
+	removed := len(records) - len(kept)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, saveAcks(m.acksPath, kept)
+}
@@ -0,0 +1,306 @@
+package notifier
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// Approver 可选接口，审批渠道支持在汇总消息中附带"批准""拒绝"按钮并读取按钮的回调
+type Approver interface {
+	// SendApprovalRequest 发送一批待审批版本的汇总，按钮中带有批次ID
+	SendApprovalRequest(id string, releases []*github.ReleaseInfo, run render.RunContext) error
+	// PollApprovals 读取上次读取以来的按钮回调，返回被批准和被拒绝的批次ID
+	// approvers 不为空时只接受其中用户的操作
+	PollApprovals(approvers []string) (approved, rejected []string, err error)
+}
+
+// ApprovalBatch 一批等待审批的版本
+type ApprovalBatch struct {
+	ID        string                `json:"id"`
+	Releases  []*github.ReleaseInfo `json:"releases"`
+	CreatedAt time.Time             `json:"created_at"`
+	// Requested 汇总是否已发送到审批渠道，暂停期间或发送失败时为false，下次运行开始时补发
+	Requested bool `json:"requested"`
+}
+
+// loadApprovals 读取审批队列，文件不存在时返回空队列
+// 审批队列会被 notify approve 等命令从其他进程修改，每次操作都重新读取文件
+func loadApprovals(path string) ([]ApprovalBatch, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("读取审批队列失败: %v", err)
+	}
+	var batches []ApprovalBatch
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &batches); err != nil {
+			return nil, fmt.Errorf("解析审批队列失败: %v", err)
+		}
+	}
+	return batches, nil
+}
+
+// saveApprovals 保存审批队列
+func saveApprovals(path string, batches []ApprovalBatch) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建队列目录失败: %v", err)
+	}
+	data, err := json.MarshalIndent(batches, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化审批队列失败: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("保存审批队列失败: %v", err)
+	}
+	return nil
+}
+
+// updateApprovals 持有审批队列的文件锁读取队列，调用 update 修改，update 返回 true 时保存
+// 定时运行、serve 模式和 notify approve、notify reject 命令可能在不同进程中同时修改队列，
+// 读取和保存之间持有文件锁，一方的修改不会被另一方覆盖
+func updateApprovals(path string, update func([]ApprovalBatch) ([]ApprovalBatch, bool)) error {
+	lock, err := util.NewFileLock(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("创建审批队列锁失败: %v", err)
+	}
+	if err := lock.Wait(); err != nil {
+		return fmt.Errorf("获取审批队列锁失败: %v", err)
+	}
+	defer lock.Unlock()
+
+	batches, err := loadApprovals(path)
+	if err != nil {
+		return err
+	}
+	batches, changed := update(batches)
+	if !changed {
+		return nil
+	}
+	return saveApprovals(path, batches)
+}
+
+// setupApproval 将审批渠道从正式通知的渠道中移出，之后检测到的版本先发送汇总到该渠道
+func (m *Manager) setupApproval(cfg config.ApprovalConfig) error {
+	for i, n := range m.notifiers {
+		if n.Name() != cfg.Channel {
+			continue
+		}
+		m.approval = n
+		m.approvers = cfg.Approvers
+		m.notifiers = append(m.notifiers[:i:i], m.notifiers[i+1:]...)
+		if _, ok := n.(Approver); !ok {
+			log.Printf("审批渠道 %s 不支持审批按钮，请使用 notify approve 命令审批", cfg.Channel)
+		}
+		return nil
+	}
+	return fmt.Errorf("审批渠道 %s 未启用，请在 notifications 中启用该渠道", cfg.Channel)
+}
+
+// newApprovalID 生成批次ID，由版本列表和时间计算，足够短便于在命令行中输入
+func newApprovalID(releases []*github.ReleaseInfo, now time.Time) string {
+	h := sha1.New()
+	for _, release := range releases {
+		fmt.Fprintf(h, "%s/%s@%s\n", release.Owner, release.Repository, release.TagName)
+	}
+	fmt.Fprint(h, now.UnixNano())
+	return hex.EncodeToString(h.Sum(nil))[:8]
+}
+
+// requestApproval 将版本加入审批队列并发送汇总到审批渠道，暂停期间只加入队列，恢复后补发汇总
+func (m *Manager) requestApproval(ctx context.Context, releases []*github.ReleaseInfo) []error {
+	batch := ApprovalBatch{
+		ID:        newApprovalID(releases, m.clock.Now()),
+		Releases:  releases,
+		CreatedAt: m.clock.Now(),
+	}
+
+	var errors []error
+	if until, paused := m.PausedUntil(); paused {
+		log.Printf("通知已暂停（%s），%d 个仓库更新已加入审批队列，恢复后发送审批汇总", DescribePause(until), len(releases))
+	} else {
+		m.pacer.SetContext(ctx)
+		defer m.pacer.SetContext(nil)
+		if err := m.sendApprovalRequest(batch); err != nil {
+			log.Printf("发送审批汇总到 %s 失败，下次运行时重发 - %v", m.approval.Name(), err)
+			errors = append(errors, err)
+		} else {
+			batch.Requested = true
+			log.Printf("%d 个仓库更新等待审批（批次 %s），已发送汇总到 %s", len(releases), batch.ID, m.approval.Name())
+		}
+	}
+
+	err := updateApprovals(m.approvalsPath, func(batches []ApprovalBatch) ([]ApprovalBatch, bool) {
+		return append(batches, batch), true
+	})
+	if err != nil {
+		return append(errors, err)
+	}
+	return errors
+}
+
+// sendApprovalRequest 发送一批版本的审批汇总，渠道不支持按钮时按普通的合并消息发送
+func (m *Manager) sendApprovalRequest(batch ApprovalBatch) error {
+	run := m.newRunContext(len(batch.Releases), 0)
+	run.Channel = m.approval.Name()
+	run.Locale = m.localeFor(run.Channel)
+	if approver, ok := m.approval.(Approver); ok {
+		return approver.SendApprovalRequest(batch.ID, batch.Releases, run)
+	}
+	return m.approval.SendBatch(batch.Releases, run)
+}
+
+// ProcessApprovals 在运行开始时处理审批：补发未发送的审批汇总，读取审批按钮的回调，
+// 将被批准的版本发送到正式通知的渠道，丢弃被拒绝的版本
+func (m *Manager) ProcessApprovals(ctx context.Context) []error {
+	if m.approval == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	batches, err := loadApprovals(m.approvalsPath)
+	if err != nil || len(batches) == 0 {
+		if err != nil {
+			return []error{err}
+		}
+		return nil
+	}
+
+	var errors []error
+	if _, paused := m.PausedUntil(); !paused {
+		m.pacer.SetContext(ctx)
+		resent := make(map[string]bool)
+		for _, batch := range batches {
+			if batch.Requested || ctx.Err() != nil {
+				continue
+			}
+			if err := m.sendApprovalRequest(batch); err != nil {
+				log.Printf("补发审批汇总（批次 %s）失败 - %v", batch.ID, err)
+				errors = append(errors, err)
+				continue
+			}
+			resent[batch.ID] = true
+		}
+		m.pacer.SetContext(nil)
+		// 发送期间队列可能已被其他进程修改，只在最新的队列中标记补发成功的批次
+		if len(resent) > 0 {
+			err := updateApprovals(m.approvalsPath, func(latest []ApprovalBatch) ([]ApprovalBatch, bool) {
+				for i := range latest {
+					if resent[latest[i].ID] {
+						latest[i].Requested = true
+					}
+				}
+				return latest, true
+			})
+			if err != nil {
+				return append(errors, err)
+			}
+		}
+	}
+
+	approver, ok := m.approval.(Approver)
+	if !ok {
+		return errors
+	}
+	approved, rejected, err := approver.PollApprovals(m.approvers)
+	if err != nil {
+		return append(errors, err)
+	}
+	if len(rejected) > 0 {
+		if _, err := m.reject(rejected); err != nil {
+			errors = append(errors, err)
+		}
+	}
+	if len(approved) > 0 {
+		_, errs := m.approve(ctx, approved)
+		errors = append(errors, errs...)
+	}
+	return errors
+}
+
+// PendingApprovals 返回等待审批的批次，按加入队列的顺序排列
+func (m *Manager) PendingApprovals() ([]ApprovalBatch, error) {
+	return loadApprovals(m.approvalsPath)
+}
+
+// Approve 批准指定的批次，将其中的版本发送到正式通知的渠道，ids 为空时批准全部批次
+// 返回实际批准的批次，队列中不存在的ID会被忽略
+func (m *Manager) Approve(ctx context.Context, ids []string) ([]ApprovalBatch, []error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.approve(ctx, ids)
+}
+
+// approve 与 Approve 相同，调用方需持有 m.mu
+func (m *Manager) approve(ctx context.Context, ids []string) ([]ApprovalBatch, []error) {
+	taken, err := m.takeApprovals(ids)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var releases []*github.ReleaseInfo
+	for _, batch := range taken {
+		releases = append(releases, batch.Releases...)
+	}
+	if len(releases) == 0 {
+		return taken, nil
+	}
+	log.Printf("已批准 %d 个批次，发送 %d 个仓库更新", len(taken), len(releases))
+	return taken, m.broadcast(ctx, releases)
+}
+
+// Reject 拒绝指定的批次，其中的版本不再发送，ids 为空时拒绝全部批次
+// 返回实际拒绝的批次，队列中不存在的ID会被忽略
+func (m *Manager) Reject(ids []string) ([]ApprovalBatch, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reject(ids)
+}
+
+// reject 与 Reject 相同，调用方需持有 m.mu
+func (m *Manager) reject(ids []string) ([]ApprovalBatch, error) {
+	taken, err := m.takeApprovals(ids)
+	for _, batch := range taken {
+		log.Printf("已拒绝批次 %s，%d 个仓库更新不再发送", batch.ID, len(batch.Releases))
+	}
+	return taken, err
+}
+
+// takeApprovals 从审批队列中取出指定的批次，ids 为空时取出全部
+// 读取和保存在同一个文件锁内完成，同一批次不会被两个进程同时取出
+func (m *Manager) takeApprovals(ids []string) ([]ApprovalBatch, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	var taken []ApprovalBatch
+	err := updateApprovals(m.approvalsPath, func(batches []ApprovalBatch) ([]ApprovalBatch, bool) {
+		var kept []ApprovalBatch
+		for _, batch := range batches {
+			if len(ids) == 0 || wanted[batch.ID] {
+				taken = append(taken, batch)
+			} else {
+				kept = append(kept, batch)
+			}
+		}
+		return kept, len(taken) > 0
+	})
+	if err != nil {
+		return nil, err
+	}
+	return taken, nil
+}
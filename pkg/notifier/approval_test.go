@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// hookNotifier 发送时调用 onSend，模拟发送期间其他进程修改审批队列
+type hookNotifier struct {
+	fakeNotifier
+	onSend func()
+}
+
+func (n *hookNotifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if n.onSend != nil {
+		n.onSend()
+	}
+	return n.fakeNotifier.SendBatch(releases, run)
+}
+
+// newApprovalManagers 创建共用同一个审批队列文件的多个通知管理器，模拟多个进程
+func newApprovalManagers(t *testing.T, count int) []*Manager {
+	t.Helper()
+	clk := clock.NewFake(time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC))
+	first := newTestManager(t, &config.Config{}, clk)
+	managers := []*Manager{first}
+	for len(managers) < count {
+		m, err := NewManagerWithClock(&config.Config{GitHub: config.GitHubConfig{Timezone: "UTC"}}, clk)
+		if err != nil {
+			t.Fatalf("创建通知管理器失败: %v", err)
+		}
+		if m.approvalsPath != first.approvalsPath {
+			t.Fatalf("审批队列路径不同: %s、%s", m.approvalsPath, first.approvalsPath)
+		}
+		managers = append(managers, m)
+	}
+	return managers
+}
+
+// TestRequestApproval_Concurrent 多个进程同时加入审批队列时，所有批次都保存在队列中
+func TestRequestApproval_Concurrent(t *testing.T) {
+	managers := newApprovalManagers(t, 8)
+	for _, m := range managers {
+		m.approval = &fakeNotifier{name: "approval"}
+	}
+
+	var wg sync.WaitGroup
+	for i, m := range managers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release := &github.ReleaseInfo{Event: github.EventRelease, Owner: "o", Repository: fmt.Sprintf("repo%d", i), TagName: "v1.0.0"}
+			if errs := m.requestApproval(context.Background(), []*github.ReleaseInfo{release}); len(errs) > 0 {
+				t.Errorf("加入审批队列失败: %v", errs)
+			}
+		}()
+	}
+	wg.Wait()
+
+	batches, err := managers[0].PendingApprovals()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batches) != len(managers) {
+		t.Errorf("审批队列中有 %d 个批次，期望 %d 个", len(batches), len(managers))
+	}
+}
+
+// TestProcessApprovals_RejectedWhileResending 补发审批汇总期间被其他进程拒绝的批次不会重新写回队列
+func TestProcessApprovals_RejectedWhileResending(t *testing.T) {
+	managers := newApprovalManagers(t, 2)
+	run, cli := managers[0], managers[1]
+
+	approval := &hookNotifier{fakeNotifier: fakeNotifier{name: "approval", err: fmt.Errorf("网络错误")}}
+	run.approval = approval
+	for i := 1; i <= 2; i++ {
+		run.requestApproval(context.Background(), testReleases(i)[i-1:])
+	}
+	batches, _ := run.PendingApprovals()
+	if len(batches) != 2 || batches[0].Requested || batches[1].Requested {
+		t.Fatalf("审批汇总发送失败时应保留未发送的批次: %+v", batches)
+	}
+
+	// 补发第一个批次的汇总时，notify reject 拒绝了第二个批次
+	approval.err = nil
+	rejectedID := batches[1].ID
+	approval.onSend = func() {
+		approval.onSend = nil
+		if _, err := cli.Reject([]string{rejectedID}); err != nil {
+			t.Errorf("拒绝批次失败: %v", err)
+		}
+	}
+	if errs := run.ProcessApprovals(context.Background()); len(errs) > 0 {
+		t.Fatalf("处理审批失败: %v", errs)
+	}
+
+	batches, _ = run.PendingApprovals()
+	if len(batches) != 1 || batches[0].ID == rejectedID || !batches[0].Requested {
+		t.Errorf("审批队列应只剩已补发汇总的批次: %+v", batches)
+	}
+}
@@ -0,0 +1,216 @@
+package atom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultTitle 默认订阅标题
+const DefaultTitle = "Notify 版本发布"
+
+// DefaultMaxEntries 订阅中默认保留的条目数
+const DefaultMaxEntries = 50
+
+// Config Atom订阅输出配置
+type Config struct {
+	Enabled bool
+	// Path 订阅文件路径
+	Path string
+	// Title 订阅标题，为空时使用 DefaultTitle
+	Title string
+	// MaxEntries 保留最近的条目数，为0时使用 DefaultMaxEntries
+	MaxEntries int
+	// SelfURL 订阅的公开地址（可选），写入 rel="self" 链接，方便阅读器识别
+	SelfURL string
+}
+
+// Notifier Atom订阅通知器，将版本通知写入本地的Atom订阅文件，可以用任意阅读器订阅
+type Notifier struct {
+	config   Config
+	template *template.Template
+	mu       sync.Mutex // 保护订阅文件的读写
+}
+
+// New 创建Atom订阅通知器
+// 条目内容使用消息模板渲染
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.Path == "" {
+		return nil, fmt.Errorf("Atom订阅文件路径不能为空")
+	}
+	if config.Title == "" {
+		config.Title = DefaultTitle
+	}
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = DefaultMaxEntries
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0755); err != nil {
+		return nil, fmt.Errorf("创建Atom订阅目录失败: %v", err)
+	}
+
+	return &Notifier{config: config, template: tmpl}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "atom"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 写入单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.SendBatch([]*github.ReleaseInfo{release}, run)
+}
+
+// SendBatch 每个版本写入一个条目，订阅中每个版本单独显示
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	entries := make([]entry, 0, len(releases))
+	for _, release := range releases {
+		content := render.ExecuteOrFallback(n.template, release, run)
+		entries = append(entries, newEntry(release, content, now))
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := n.load()
+	if err != nil {
+		return err
+	}
+	f.add(entries, n.config.MaxEntries)
+	f.Updated = now.UTC().Format(time.RFC3339)
+	return n.save(f)
+}
+
+// load 读取已有的订阅文件，文件不存在时返回空订阅
+func (n *Notifier) load() (*feed, error) {
+	f := &feed{}
+	data, err := os.ReadFile(n.config.Path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取Atom订阅文件失败: %v", err)
+	}
+	if err := xml.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("解析Atom订阅文件失败: %v", err)
+	}
+	return f, nil
+}
+
+// save 先写入临时文件再替换，避免阅读器读到写了一半的订阅
+func (n *Notifier) save(f *feed) error {
+	f.ID = "urn:notify:feed"
+	f.Title = n.config.Title
+	f.Author = author{Name: "notify"}
+	f.Links = nil
+	if n.config.SelfURL != "" {
+		f.Links = []link{{Rel: "self", Href: n.config.SelfURL}}
+	}
+
+	data, err := xml.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化Atom订阅失败: %v", err)
+	}
+
+	tmp := n.config.Path + ".tmp"
+	if err := os.WriteFile(tmp, append([]byte(xml.Header), data...), 0644); err != nil {
+		return fmt.Errorf("写入Atom订阅文件失败: %v", err)
+	}
+	if err := os.Rename(tmp, n.config.Path); err != nil {
+		return fmt.Errorf("写入Atom订阅文件失败: %v", err)
+	}
+	return nil
+}
+
+// feed Atom订阅
+type feed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Author  author   `xml:"author"`
+	Links   []link   `xml:"link"`
+	Entries []entry  `xml:"entry"`
+}
+
+type author struct {
+	Name string `xml:"name"`
+}
+
+type link struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+// entry 订阅中的一个版本通知
+type entry struct {
+	ID        string  `xml:"id"`
+	Title     string  `xml:"title"`
+	Updated   string  `xml:"updated"`
+	Published string  `xml:"published,omitempty"`
+	Link      link    `xml:"link"`
+	Content   content `xml:"content"`
+}
+
+type content struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// newEntry 根据版本信息创建订阅条目
+// 条目ID由仓库、版本和事件类型组成，同一版本的同一事件重复写入时替换原条目
+func newEntry(release *github.ReleaseInfo, body string, now time.Time) entry {
+	title := fmt.Sprintf("%s/%s %s", release.Owner, release.Repository, release.TagName)
+	if label := release.EventLabel(); label != "" {
+		title += " · " + label
+	}
+
+	e := entry{
+		ID:      fmt.Sprintf("urn:notify:%s/%s:%s:%s", release.Owner, release.Repository, release.TagName, release.Event),
+		Title:   title,
+		Updated: now.UTC().Format(time.RFC3339),
+		Link:    link{Rel: "alternate", Href: release.Link()},
+		Content: content{Type: "text", Body: strings.TrimSpace(body)},
+	}
+	if !release.PublishedAt.IsZero() {
+		e.Published = release.PublishedAt.UTC().Format(time.RFC3339)
+	}
+	return e
+}
+
+// add 将新条目加到最前面，替换ID相同的旧条目，只保留最近的 max 个
+func (f *feed) add(entries []entry, max int) {
+	ids := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		ids[e.ID] = true
+	}
+
+	merged := entries
+	for _, e := range f.Entries {
+		if !ids[e.ID] {
+			merged = append(merged, e)
+		}
+	}
+	if len(merged) > max {
+		merged = merged[:max]
+	}
+	f.Entries = merged
+}
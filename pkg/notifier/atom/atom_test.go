@@ -0,0 +1,64 @@
+package atom
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSendBatch 测试写入订阅、替换重复条目和条目数上限
+func TestSendBatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "releases.atom")
+	tmpl := template.Must(template.New("t").Parse("{{.Repository}} {{.TagName}} 发布"))
+	n, err := New(Config{Enabled: true, Path: path, MaxEntries: 2}, tmpl)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := func(tag string) *github.ReleaseInfo {
+		return &github.ReleaseInfo{
+			Event:       github.EventRelease,
+			Owner:       "foo",
+			Repository:  "bar",
+			TagName:     tag,
+			HTMLURL:     "https://github.com/foo/bar/releases/tag/" + tag,
+			PublishedAt: time.Now(),
+		}
+	}
+
+	if err := n.SendBatch([]*github.ReleaseInfo{release("v1.0.0"), release("v1.1.0")}, render.RunContext{}); err != nil {
+		t.Fatalf("写入订阅失败: %v", err)
+	}
+	if err := n.Send(release("v1.2.0"), render.RunContext{}); err != nil {
+		t.Fatalf("写入订阅失败: %v", err)
+	}
+	// 同一版本重复写入时替换原条目
+	if err := n.Send(release("v1.2.0"), render.RunContext{}); err != nil {
+		t.Fatalf("写入订阅失败: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取订阅文件失败: %v", err)
+	}
+	var f feed
+	if err := xml.Unmarshal(data, &f); err != nil {
+		t.Fatalf("订阅文件不是有效的Atom: %v", err)
+	}
+	if f.Title != DefaultTitle || len(f.Entries) != 2 {
+		t.Fatalf("订阅应包含2个条目，实际 %d 个", len(f.Entries))
+	}
+	if !strings.Contains(f.Entries[0].Title, "v1.2.0") || !strings.Contains(f.Entries[1].Title, "v1.0.0") {
+		t.Errorf("条目顺序不正确: %s, %s", f.Entries[0].Title, f.Entries[1].Title)
+	}
+	if f.Entries[0].Content.Body != "bar v1.2.0 发布" {
+		t.Errorf("条目内容应使用模板渲染: %q", f.Entries[0].Content.Body)
+	}
+}
@@ -0,0 +1,131 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// coalesceQueue 持久化的合并队列，合并窗口内检测到的版本先放入队列，窗口结束后一起发送
+type coalesceQueue struct {
+	path string
+
+	// Since 队列中第一个版本的加入时间，合并窗口从该时间开始计算
+	Since    time.Time             `json:"since"`
+	Releases []*github.ReleaseInfo `json:"releases"`
+}
+
+// newCoalesceQueue 加载合并队列
+func newCoalesceQueue(path string) (*coalesceQueue, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建队列目录失败: %v", err)
+	}
+
+	q := &coalesceQueue{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return q, nil
+		}
+		return nil, fmt.Errorf("读取合并队列失败: %v", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, q); err != nil {
+			return nil, fmt.Errorf("解析合并队列失败: %v", err)
+		}
+	}
+
+	return q, nil
+}
+
+// add 将版本加入队列，已在队列中的版本忽略，队列为空时合并窗口从 now 开始
+func (q *coalesceQueue) add(release *github.ReleaseInfo, now time.Time) {
+	if q.contains(release) {
+		return
+	}
+	if len(q.Releases) == 0 {
+		q.Since = now
+	}
+	q.Releases = append(q.Releases, release)
+}
+
+// contains 版本是否已在队列中
+func (q *coalesceQueue) contains(release *github.ReleaseInfo) bool {
+	for _, queued := range q.Releases {
+		if queued.Owner == release.Owner &&
+			queued.Repository == release.Repository &&
+			queued.TagName == release.TagName &&
+			queued.Event == release.Event {
+			return true
+		}
+	}
+	return false
+}
+
+// take 取出队列中的所有版本
+func (q *coalesceQueue) take() []*github.ReleaseInfo {
+	releases := q.Releases
+	q.Releases = nil
+	q.Since = time.Time{}
+	return releases
+}
+
+// save 保存合并队列
+func (q *coalesceQueue) save() error {
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化合并队列失败: %v", err)
+	}
+	if err := os.WriteFile(q.path, data, 0644); err != nil {
+		return fmt.Errorf("保存合并队列失败: %v", err)
+	}
+	return nil
+}
+
+// Coalesce 将新检测到的版本放入合并队列，合并窗口结束后返回队列中的全部版本，窗口未结束时返回nil
+// 启用escalate时，命中高亮关键字的版本不等待窗口结束，直接返回
+// 未配置合并窗口时原样返回
+func (m *Manager) Coalesce(releases []*github.ReleaseInfo) ([]*github.ReleaseInfo, error) {
+	if m.coalesce == nil {
+		return releases, nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.redact(releases)
+	var urgent []*github.ReleaseInfo
+	for _, release := range releases {
+		if m.escalate && release.IsHighlighted() {
+			urgent = append(urgent, release)
+			continue
+		}
+		m.coalesce.add(release, m.clock.Now())
+	}
+
+	if len(m.coalesce.Releases) == 0 {
+		return urgent, nil
+	}
+
+	flushAt := m.coalesce.Since.Add(m.coalesceWindow)
+	if m.clock.Now().Before(flushAt) {
+		if err := m.coalesce.save(); err != nil {
+			return nil, err
+		}
+		log.Printf("合并窗口内已累积 %d 个新版本，将在 %s 后合并发送",
+			len(m.coalesce.Releases), flushAt.Format(time.DateTime))
+		return urgent, nil
+	}
+
+	due := m.coalesce.take()
+	if err := m.coalesce.save(); err != nil {
+		return nil, err
+	}
+	log.Printf("合并窗口已结束，合并发送 %d 个新版本", len(due))
+	return append(urgent, due...), nil
+}
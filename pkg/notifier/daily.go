@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+)
+
+// errDailyLimit 渠道今天的消息数已达到上限，且渠道不支持摘要消息
+var errDailyLimit = errors.New("今天的消息数已达到每日上限")
+
+// dailyCounter 持久化的每渠道每日消息计数，跨运行累计，换日后自动清零
+type dailyCounter struct {
+	path  string
+	loc   *time.Location
+	clock clock.Clock
+	mu    sync.Mutex
+
+	Day    string         `json:"day"`
+	Counts map[string]int `json:"counts"`
+}
+
+// newDailyCounter 加载每日消息计数
+func newDailyCounter(path string, loc *time.Location, clk clock.Clock) (*dailyCounter, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建计数目录失败: %v", err)
+	}
+
+	c := &dailyCounter{path: path, loc: loc, clock: clk, Counts: make(map[string]int)}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("读取每日消息计数失败: %v", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, c); err != nil {
+			return nil, fmt.Errorf("解析每日消息计数失败: %v", err)
+		}
+	}
+	if c.Counts == nil {
+		c.Counts = make(map[string]int)
+	}
+
+	return c, nil
+}
+
+// rolloverLocked 换日时清零计数
+func (c *dailyCounter) rolloverLocked() {
+	today := c.clock.Now().In(c.loc).Format("2006-01-02")
+	if c.Day != today {
+		c.Day = today
+		c.Counts = make(map[string]int)
+	}
+}
+
+// Count 返回渠道今天已发送的消息数
+func (c *dailyCounter) Count(channel string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rolloverLocked()
+	return c.Counts[channel]
+}
+
+// Add 记录渠道发送了一条消息
+func (c *dailyCounter) Add(channel string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rolloverLocked()
+	c.Counts[channel]++
+}
+
+// Save 保存每日消息计数
+func (c *dailyCounter) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化每日消息计数失败: %v", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("保存每日消息计数失败: %v", err)
+	}
+	return nil
+}
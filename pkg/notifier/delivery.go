@@ -0,0 +1,66 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/window"
+)
+
+// errOutsideWindow 不在渠道的发送时间段内
+var errOutsideWindow = errors.New("不在发送时间段内")
+
+// newWindows 按配置解析各渠道的发送时间段
+func newWindows(configured map[string][]string) (map[string]window.Schedule, error) {
+	windows := make(map[string]window.Schedule, len(configured))
+	for channel, specs := range configured {
+		if len(specs) == 0 {
+			continue
+		}
+		schedule, err := window.Parse(specs)
+		if err != nil {
+			return nil, fmt.Errorf("渠道 %s 的%v", channel, err)
+		}
+		windows[channel] = schedule
+	}
+	return windows, nil
+}
+
+// windowFor 返回渠道的发送时间段，渠道实例（如 dingtalk/ops）未单独配置时使用该类型的时间段
+func (m *Manager) windowFor(channel string) window.Schedule {
+	if schedule, ok := m.windows[channel]; ok {
+		return schedule
+	}
+	if kind, _, isInstance := strings.Cut(channel, "/"); isInstance {
+		return m.windows[kind]
+	}
+	return nil
+}
+
+// outsideWindow 渠道当前是否不在发送时间段内，不在时同时返回下一个时间段的开始时间
+func (m *Manager) outsideWindow(channel string) (time.Time, bool) {
+	schedule := m.windowFor(channel)
+	now := m.clock.Now().In(m.loc)
+	if schedule.Contains(now) {
+		return time.Time{}, false
+	}
+	return schedule.Next(now), true
+}
+
+// NextDelivery 返回队列中因不在发送时间段内而等待的通知最早可以发送的时间，没有等待的通知时返回零值
+// 定时运行和 serve 模式在该时间调用 DrainOutbox 发送排队的通知
+func (m *Manager) NextDelivery() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var next time.Time
+	for _, channel := range m.outbox.Channels() {
+		opens, outside := m.outsideWindow(channel)
+		if outside && (next.IsZero() || opens.Before(next)) {
+			next = opens
+		}
+	}
+	return next
+}
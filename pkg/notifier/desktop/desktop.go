@@ -0,0 +1,108 @@
+package desktop
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultAppName 通知中显示的应用名称
+const DefaultAppName = "Notify"
+
+// maxBatchNotifications 一次批量发送时最多弹出的通知数，其余版本合并为一条
+const maxBatchNotifications = 5
+
+// commandTimeout 调用系统通知命令的超时时间
+const commandTimeout = 10 * time.Second
+
+// Config 桌面通知配置
+type Config struct {
+	Enabled bool
+	// AppName 通知中显示的应用名称，默认为 Notify
+	AppName string
+}
+
+// Notifier 桌面通知器，通过系统命令弹出原生通知
+// Linux 使用 notify-send，macOS 使用 osascript，Windows 使用 PowerShell 调用 Toast 通知
+type Notifier struct {
+	config Config
+}
+
+// New 创建桌面通知器
+// 桌面通知使用固定的简短格式（标题+一行说明），不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.AppName == "" {
+		config.AppName = DefaultAppName
+	}
+
+	if err := checkSupported(); err != nil {
+		return nil, fmt.Errorf("桌面通知不可用: %v", err)
+	}
+
+	return &Notifier{config: config}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "desktop"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 为单个版本弹出一条通知
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	title, body := releaseNotification(release, run)
+	return n.notify(title, body)
+}
+
+// SendBatch 每个版本弹出一条通知，超过 maxBatchNotifications 个时其余版本合并为一条
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	var failed int
+	var firstErr error
+	record := func(err error) {
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	for i, release := range releases {
+		if i == maxBatchNotifications {
+			title, body := summaryNotification(releases[i:], run, false)
+			record(n.notify(title, body))
+			break
+		}
+		record(n.Send(release, run))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d 条桌面通知发送失败: %v", failed, firstErr)
+	}
+	return nil
+}
+
+// SendDigest 将超过每日上限的版本合并为一条通知
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	title, body := summaryNotification(releases, run, true)
+	return n.notify(title, body)
+}
+
+// notify 调用当前平台的通知命令
+func (n *Notifier) notify(title, body string) error {
+	if err := showNotification(n.config.AppName, title, body); err != nil {
+		return fmt.Errorf("弹出桌面通知失败: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+//go:build darwin
+
+package desktop
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// checkSupported 检查是否可以使用 osascript
+func checkSupported() error {
+	if _, err := exec.LookPath("osascript"); err != nil {
+		return fmt.Errorf("未找到 osascript")
+	}
+	return nil
+}
+
+// showNotification 使用 osascript 弹出通知中心通知（macOS 平台）
+func showNotification(appName, title, body string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	script := fmt.Sprintf("display notification %s with title %s subtitle %s",
+		appleScriptString(body), appleScriptString(appName), appleScriptString(title))
+	cmd := exec.CommandContext(ctx, "osascript", "-e", script)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// appleScriptString 生成AppleScript字符串字面量
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,30 @@
+//go:build linux
+
+package desktop
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// checkSupported 检查是否安装了 notify-send
+func checkSupported() error {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return fmt.Errorf("未找到 notify-send，请安装 libnotify（如 apt install libnotify-bin）")
+	}
+	return nil
+}
+
+// showNotification 使用 notify-send 弹出通知（Linux 平台）
+func showNotification(appName, title, body string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "notify-send", "--app-name", appName, "--", title, body)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
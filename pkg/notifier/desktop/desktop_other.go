@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !windows
+
+package desktop
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// checkSupported 其他平台不支持桌面通知
+func checkSupported() error {
+	return fmt.Errorf("不支持当前平台 %s", runtime.GOOS)
+}
+
+// showNotification 其他平台不支持桌面通知
+func showNotification(appName, title, body string) error {
+	return checkSupported()
+}
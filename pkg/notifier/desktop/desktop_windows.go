@@ -0,0 +1,69 @@
+//go:build windows
+
+package desktop
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os/exec"
+	"strings"
+	"unicode/utf16"
+)
+
+// checkSupported 检查是否可以使用 PowerShell
+func checkSupported() error {
+	if _, err := exec.LookPath("powershell.exe"); err != nil {
+		return fmt.Errorf("未找到 powershell.exe")
+	}
+	return nil
+}
+
+// powerShellAppID PowerShell 的 AppUserModelID，未注册的应用ID在新版本Windows中不会显示通知
+const powerShellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastScript 通过 Windows.UI.Notifications 弹出Toast通知的PowerShell脚本
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml(%s)
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier(%s).Show($toast)`
+
+// showNotification 使用 PowerShell 弹出Toast通知（Windows 平台）
+func showNotification(appName, title, body string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	xml := fmt.Sprintf(`<toast><visual><binding template="ToastGeneric"><text>%s</text><text>%s</text><text placement="attribution">%s</text></binding></visual></toast>`,
+		xmlEscape(title), xmlEscape(body), xmlEscape(appName))
+	script := fmt.Sprintf(toastScript, powerShellString(xml), powerShellString(powerShellAppID))
+
+	// 使用 -EncodedCommand 传递脚本，避免命令行转义问题
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-EncodedCommand", encodeCommand(script))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// xmlEscape 转义XML文本
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;", "'", "&apos;").Replace(s)
+}
+
+// powerShellString 生成PowerShell单引号字符串字面量
+func powerShellString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// encodeCommand 将脚本编码为 -EncodedCommand 需要的 UTF-16LE Base64
+func encodeCommand(script string) string {
+	units := utf16.Encode([]rune(script))
+	buf := make([]byte, len(units)*2)
+	for i, u := range units {
+		buf[i*2] = byte(u)
+		buf[i*2+1] = byte(u >> 8)
+	}
+	return base64.StdEncoding.EncodeToString(buf)
+}
@@ -0,0 +1,90 @@
+package desktop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxSummaryItems 合并通知中最多列出的版本数，桌面通知只显示几行
+const maxSummaryItems = 5
+
+// labels 通知中的固定文字
+type labels struct {
+	NewRelease  string
+	Published   string
+	BatchTitle  string
+	DigestTitle string
+	More        string
+}
+
+var labelsZH = labels{
+	NewRelease:  "%s/%s 发布新版本 %s",
+	Published:   "发布时间",
+	BatchTitle:  "还有 %d 个新版本",
+	DigestTitle: "今天还有 %d 个新版本",
+	More:        "...以及其他 %d 个版本",
+}
+
+var labelsEN = labels{
+	NewRelease:  "%s/%s released %s",
+	Published:   "Published",
+	BatchTitle:  "%d more releases",
+	DigestTitle: "%d more releases today",
+	More:        "...and %d more",
+}
+
+// labelsFor 按渠道语言选择通知文字
+func labelsFor(run render.RunContext) labels {
+	if run.Locale == render.LocaleEN {
+		return labelsEN
+	}
+	return labelsZH
+}
+
+// releaseNotification 构建单个版本的通知标题和正文
+func releaseNotification(release *github.ReleaseInfo, run render.RunContext) (string, string) {
+	l := labelsFor(run)
+	title := fmt.Sprintf(l.NewRelease, release.Owner, release.Repository, release.TagName)
+
+	var lines []string
+	if label := release.EventLabel(); label != "" {
+		lines = append(lines, label)
+	}
+	if release.IsHighlighted() {
+		lines = append(lines, release.HighlightBanner())
+	}
+	if release.Contributed {
+		lines = append(lines, release.ContributorBadge())
+	}
+	if release.Name != "" && release.Name != release.TagName {
+		lines = append(lines, release.Name)
+	}
+	if !release.PublishedAt.IsZero() {
+		lines = append(lines, fmt.Sprintf("%s: %s", l.Published, run.FormatTime(release.PublishedAt)))
+	}
+
+	return title, strings.Join(lines, "\n")
+}
+
+// summaryNotification 构建多个版本的合并通知，digest 为true时用于超过每日上限的摘要
+func summaryNotification(releases []*github.ReleaseInfo, run render.RunContext, digest bool) (string, string) {
+	l := labelsFor(run)
+	title := fmt.Sprintf(l.BatchTitle, len(releases))
+	if digest {
+		title = fmt.Sprintf(l.DigestTitle, len(releases))
+	}
+
+	var lines []string
+	for i, release := range releases {
+		if i == maxSummaryItems {
+			lines = append(lines, fmt.Sprintf(l.More, len(releases)-maxSummaryItems))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s/%s %s", release.Owner, release.Repository, release.TagName))
+	}
+
+	return title, strings.Join(lines, "\n")
+}
@@ -0,0 +1,190 @@
+package dingtalk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAPIBaseURL 应用模式的API地址
+const DefaultAPIBaseURL = "https://api.dingtalk.com"
+
+// maxUsersPerRequest 单聊批量发送接口每次最多的用户数
+const maxUsersPerRequest = 20
+
+// tokenRefreshMargin 访问令牌过期前提前刷新的时间
+const tokenRefreshMargin = 5 * time.Minute
+
+// appClient 通过企业内部应用的机器人接口发送消息
+// 使用 AppKey/AppSecret 获取访问令牌，发送到群会话（openConversationId）或单聊用户（userid）
+type appClient struct {
+	config Config
+	client *http.Client
+
+	mu          sync.Mutex // 保护访问令牌
+	accessToken string
+	expiresAt   time.Time
+}
+
+// appError 应用接口返回的错误
+type appError struct {
+	Status  int
+	Code    string
+	Message string
+	// hint 面向用户的排查建议
+	hint string
+	// fatal 为true时表示配置错误，继续发送也不会成功，需要停用该渠道
+	fatal bool
+}
+
+func (e *appError) Error() string {
+	msg := fmt.Sprintf("钉钉应用接口错误: %s (HTTP %d, code: %s)", e.Message, e.Status, e.Code)
+	if e.hint != "" {
+		msg += "，" + e.hint
+	}
+	return msg
+}
+
+// sendMarkdown 发送markdown消息到配置的群会话和用户
+func (a *appClient) sendMarkdown(title, text string) error {
+	param, err := json.Marshal(map[string]string{"title": title, "text": text})
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	if a.config.ConversationID != "" {
+		err := a.call("/v1.0/robot/groupMessages/send", map[string]interface{}{
+			"robotCode":          a.config.RobotCode,
+			"openConversationId": a.config.ConversationID,
+			"msgKey":             "sampleMarkdown",
+			"msgParam":           string(param),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(a.config.UserIDs); i += maxUsersPerRequest {
+		end := i + maxUsersPerRequest
+		if end > len(a.config.UserIDs) {
+			end = len(a.config.UserIDs)
+		}
+		err := a.call("/v1.0/robot/oToMessages/batchSend", map[string]interface{}{
+			"robotCode": a.config.RobotCode,
+			"userIds":   a.config.UserIDs[i:end],
+			"msgKey":    "sampleMarkdown",
+			"msgParam":  string(param),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// call 使用访问令牌调用应用接口
+func (a *appClient) call(path string, body interface{}) error {
+	token, err := a.token()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, a.config.APIBaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-acs-dingtalk-access-token", token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	apiErr := parseAppError(resp)
+	if resp.StatusCode == http.StatusUnauthorized {
+		// 令牌可能被提前作废，下次发送时重新获取
+		a.mu.Lock()
+		a.accessToken = ""
+		a.mu.Unlock()
+	}
+	return apiErr
+}
+
+// token 返回访问令牌，过期前重新获取
+func (a *appClient) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.accessToken != "" && a.config.Clock.Now().Before(a.expiresAt.Add(-tokenRefreshMargin)) {
+		return a.accessToken, nil
+	}
+
+	payload, _ := json.Marshal(map[string]string{"appKey": a.config.AppKey, "appSecret": a.config.AppSecret})
+	resp, err := a.client.Post(a.config.APIBaseURL+"/v1.0/oauth2/accessToken", "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("获取钉钉应用访问令牌失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := parseAppError(resp)
+		if resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+			apiErr.hint = "获取访问令牌失败，请检查 app_key 和 app_secret"
+			apiErr.fatal = true
+		}
+		return "", apiErr
+	}
+
+	var result struct {
+		AccessToken string `json:"accessToken"`
+		ExpireIn    int    `json:"expireIn"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || result.AccessToken == "" {
+		return "", fmt.Errorf("解析钉钉应用访问令牌失败: %v", err)
+	}
+	a.accessToken = result.AccessToken
+	a.expiresAt = a.config.Clock.Now().Add(time.Duration(result.ExpireIn) * time.Second)
+	return a.accessToken, nil
+}
+
+// parseAppError 解析应用接口的错误响应 {"code": "...", "message": "..."}
+func parseAppError(resp *http.Response) *appError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var result struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &result)
+	if result.Message == "" {
+		result.Message = strings.TrimSpace(string(body))
+	}
+
+	err := &appError{Status: resp.StatusCode, Code: result.Code, Message: result.Message}
+	lower := strings.ToLower(result.Code)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || strings.Contains(lower, "qpslimit") || strings.Contains(lower, "throttl"):
+		// 不设置冷却期，由通知管理器稍后重试
+		err.hint = "频率超过限制，请降低 pacing 中的发送速率"
+	case resp.StatusCode == http.StatusForbidden:
+		err.hint = "应用没有机器人发送消息的权限，或机器人不在该群中，请检查应用权限和 conversation_id"
+		err.fatal = true
+	case strings.Contains(lower, "robotcode") || strings.Contains(lower, "openconversationid") || strings.Contains(lower, "userid"):
+		err.hint = "请检查 robot_code、conversation_id 和 user_ids"
+		err.fatal = true
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+package dingtalk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// appRequest 模拟服务器收到的发送请求
+type appRequest struct {
+	Path               string
+	RobotCode          string   `json:"robotCode"`
+	OpenConversationID string   `json:"openConversationId"`
+	UserIDs            []string `json:"userIds"`
+	MsgKey             string   `json:"msgKey"`
+	MsgParam           string   `json:"msgParam"`
+}
+
+// TestAppMode 测试应用模式获取并复用访问令牌，发送到群会话并按每批20个用户单聊发送
+func TestAppMode(t *testing.T) {
+	var (
+		tokens   int
+		requests []appRequest
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1.0/oauth2/accessToken" {
+			var creds struct{ AppKey, AppSecret string }
+			json.NewDecoder(r.Body).Decode(&creds)
+			if creds.AppKey != "key" || creds.AppSecret != "secret" {
+				t.Errorf("应用凭证为 %+v", creds)
+			}
+			tokens++
+			fmt.Fprint(w, `{"accessToken":"t1","expireIn":7200}`)
+			return
+		}
+		if token := r.Header.Get("x-acs-dingtalk-access-token"); token != "t1" {
+			t.Errorf("访问令牌为 %q", token)
+		}
+		req := appRequest{Path: r.URL.Path}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req)
+		fmt.Fprint(w, `{"processQueryKey":"q"}`)
+	}))
+	defer srv.Close()
+
+	users := make([]string, 25)
+	for i := range users {
+		users[i] = fmt.Sprintf("user%d", i)
+	}
+	n, err := New(Config{
+		Enabled:        true,
+		AppKey:         "key",
+		AppSecret:      "secret",
+		ConversationID: "cid",
+		UserIDs:        users,
+		FeedCard:       true,
+		APIBaseURL:     srv.URL,
+		Bucket:         pacing.NewBucket("dingtalk", pacing.Limit{Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Owner: "o", Repository: "a", TagName: "v1.0.0", HTMLURL: "https://github.com/o/a/releases/tag/v1.0.0"},
+		{Owner: "o", Repository: "b", TagName: "v2.0.0", HTMLURL: "https://github.com/o/b/releases/tag/v2.0.0"},
+	}
+	for i := 0; i < 2; i++ {
+		if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 2}); err != nil {
+			t.Fatalf("发送失败: %v", err)
+		}
+	}
+
+	if tokens != 1 {
+		t.Errorf("获取了 %d 次访问令牌，期望 1 次", tokens)
+	}
+	if len(requests) != 6 {
+		t.Fatalf("收到 %d 个发送请求，期望 6 个", len(requests))
+	}
+	group, first, second := requests[0], requests[1], requests[2]
+	if group.Path != "/v1.0/robot/groupMessages/send" || group.OpenConversationID != "cid" || group.RobotCode != "key" {
+		t.Errorf("群消息请求不正确: %+v", group)
+	}
+	if first.Path != "/v1.0/robot/oToMessages/batchSend" || len(first.UserIDs) != 20 || len(second.UserIDs) != 5 {
+		t.Errorf("单聊请求不正确: %+v %+v", first, second)
+	}
+
+	// 应用模式不支持FeedCard，使用markdown
+	var param struct{ Title, Text string }
+	if err := json.Unmarshal([]byte(group.MsgParam), &param); err != nil || group.MsgKey != "sampleMarkdown" {
+		t.Fatalf("消息类型为 %s，参数为 %s", group.MsgKey, group.MsgParam)
+	}
+	if !strings.Contains(param.Text, "o/b") {
+		t.Errorf("消息内容不正确: %s", param.Text)
+	}
+}
+
+// TestAppMode_Forbidden 测试应用没有发送权限时停用渠道
+func TestAppMode_Forbidden(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1.0/oauth2/accessToken" {
+			fmt.Fprint(w, `{"accessToken":"t1","expireIn":7200}`)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"code":"Forbidden.AccessDenied.AccessTokenPermissionDenied","message":"没有调用该接口的权限"}`)
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled:        true,
+		AppKey:         "key",
+		AppSecret:      "secret",
+		ConversationID: "cid",
+		APIBaseURL:     srv.URL,
+		Bucket:         pacing.NewBucket("dingtalk", pacing.Limit{Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "o", Repository: "a", TagName: "v1.0.0"}
+	if err := n.SendDigest([]*github.ReleaseInfo{release}, render.RunContext{Timestamp: time.Now()}); err == nil {
+		t.Fatal("期望返回错误")
+	}
+	if n.IsEnabled() {
+		t.Error("没有发送权限时应停用渠道")
+	}
+}
+
+// TestNew_AppConfig 测试应用模式的配置检查
+func TestNew_AppConfig(t *testing.T) {
+	for name, config := range map[string]Config{
+		"缺少webhook": {},
+		"缺少密钥":      {AppKey: "key", ConversationID: "cid"},
+		"缺少接收方":     {AppKey: "key", AppSecret: "secret"},
+	} {
+		if _, err := New(config, nil); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
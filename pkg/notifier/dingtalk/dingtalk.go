@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
@@ -15,49 +16,117 @@ import (
 	"text/template"
 	"time"
 
-	"golang.org/x/time/rate"
-
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
 	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
 )
 
+// DefaultPace 默认发送速率
+// 钉钉API限制为每分钟20条消息
+// 为了保险起见，设置为每4秒一条（15条/分钟），突发允许3条
+// 这样即使有突发，也不会超过20条/分钟的限制
+var DefaultPace = pacing.Limit{Interval: 4 * time.Second, Burst: 3}
+
+// AppPace 应用模式的默认发送速率
+// 应用机器人接口没有webhook每分钟20条的限制，只受应用的接口调用频率限制
+var AppPace = pacing.Limit{Interval: 200 * time.Millisecond, Burst: 5}
+
 // Config 钉钉通知配置
 type Config struct {
+	// Name 渠道名称，同时配置多个钉钉机器人时为 dingtalk/<实例名>，为空时为 dingtalk
+	Name       string
 	Enabled    bool
 	WebhookURL string
 	Secret     string
+	// FeedCard 批量消息包含多个版本时，使用FeedCard（每个版本一张卡片）代替长markdown
+	FeedCard bool
+	// Keyword 机器人使用"自定义关键词"安全设置时的关键词，会自动追加到消息中
+	Keyword string
+	// AppKey、AppSecret 企业内部应用的凭证，配置后通过应用机器人的接口发送，不使用 WebhookURL
+	AppKey    string
+	AppSecret string
+	// RobotCode 应用机器人的robotCode，为空时使用 AppKey
+	RobotCode string
+	// ConversationID 应用模式下接收消息的群会话ID（openConversationId）
+	ConversationID string
+	// UserIDs 应用模式下以单聊接收消息的用户ID（userid）
+	UserIDs []string
+	// APIBaseURL 应用模式的API地址，为空时使用 DefaultAPIBaseURL
+	APIBaseURL string
+	// LocalAddr 绑定的本地IP或网卡名，用于IP白名单
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 Pace 返回的速率
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Pace 默认发送速率，应用模式为 AppPace，webhook模式为 DefaultPace
+func (c Config) Pace() pacing.Limit {
+	if c.AppKey != "" {
+		return AppPace
+	}
+	return DefaultPace
 }
 
 // Notifier 钉钉通知器
 type Notifier struct {
 	config   Config
 	template *template.Template
-	limiter  *rate.Limiter // 速率限制器
-	client   *http.Client  // 复用HTTP客户端，提高性能
-	mu       sync.Mutex    // 用于保护冷却状态
+	limiter  *pacing.Bucket // 速率限制器
+	client   *http.Client   // 复用HTTP客户端，提高性能
+	app      *appClient     // 应用模式的客户端，webhook模式为nil
+	mu       sync.Mutex     // 用于保护冷却状态
 	cooldown struct {
 		active bool
 		until  time.Time
 	}
+	// disabledReason 因配置错误被停用时的原因，为空表示未停用
+	disabledReason string
 }
 
 // New 创建钉钉通知器
 func New(config Config, tmpl *template.Template) (*Notifier, error) {
-	if config.WebhookURL == "" {
-		return nil, fmt.Errorf("钉钉webhook URL不能为空")
+	if config.AppKey != "" {
+		if config.AppSecret == "" {
+			return nil, fmt.Errorf("钉钉应用模式需要配置 app_secret")
+		}
+		if config.ConversationID == "" && len(config.UserIDs) == 0 {
+			return nil, fmt.Errorf("钉钉应用模式需要配置 conversation_id 或 user_ids")
+		}
+		if config.RobotCode == "" {
+			config.RobotCode = config.AppKey
+		}
+		if config.APIBaseURL == "" {
+			config.APIBaseURL = DefaultAPIBaseURL
+		}
+		config.APIBaseURL = strings.TrimRight(config.APIBaseURL, "/")
+	} else if config.WebhookURL == "" {
+		return nil, fmt.Errorf("钉钉webhook URL不能为空（或配置 app_key、app_secret 使用应用模式）")
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket(channelName(config), config.Pace())
 	}
 
-	// 创建速率限制器
-	// 钉钉API限制为每分钟20条消息
-	// 为了保险起见，设置为每4秒一条（15条/分钟），突发允许3条
-	// 这样即使有突发，也不会超过20条/分钟的限制
-	limiter := rate.NewLimiter(rate.Every(4*time.Second), 3)
+	// 创建带超时的HTTP客户端，按配置绑定出口地址
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
 
-	// 创建带超时的HTTP客户端
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	if config.Clock == nil {
+		config.Clock = clock.System
 	}
 
-	return &Notifier{
+	n := &Notifier{
 		config:   config,
 		template: tmpl,
 		limiter:  limiter,
@@ -68,21 +137,45 @@ func New(config Config, tmpl *template.Template) (*Notifier, error) {
 		}{
 			active: false,
 		},
-	}, nil
+	}
+	if config.AppKey != "" {
+		n.app = &appClient{config: config, client: client}
+	}
+	return n, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return channelName(n.config)
 }
 
-// IsEnabled 是否启用
+// channelName 配置中的渠道名称，未设置时为 dingtalk
+func channelName(config Config) string {
+	if config.Name == "" {
+		return "dingtalk"
+	}
+	return config.Name
+}
+
+// IsEnabled 是否启用（因配置错误被停用后返回false）
 func (n *Notifier) IsEnabled() bool {
-	return n.config.Enabled
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.config.Enabled && n.disabledReason == ""
 }
 
-// renderTemplate 渲染通知模板
-func (n *Notifier) renderTemplate(release *github.ReleaseInfo) (string, error) {
-	var buf bytes.Buffer
-	if err := n.template.Execute(&buf, release); err != nil {
-		return "", err
+// disableOnFatal 遇到配置类错误时停用该渠道，避免继续发送无效请求
+func (n *Notifier) disableOnFatal(err error) {
+	if !isFatal(err) {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.disabledReason == "" {
+		n.disabledReason = err.Error()
+		log.Printf("已停用钉钉通知渠道 %s: %s", n.Name(), n.disabledReason)
 	}
-	return buf.String(), nil
 }
 
 // canSendMessage 检查是否可以发送消息
@@ -90,7 +183,7 @@ func (n *Notifier) canSendMessage() (bool, time.Duration) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	now := time.Now()
+	now := n.config.Clock.Now()
 
 	// 检查是否在冷却期
 	if n.cooldown.active && now.Before(n.cooldown.until) {
@@ -108,11 +201,11 @@ func (n *Notifier) setCooldown(duration time.Duration) {
 	defer n.mu.Unlock()
 
 	n.cooldown.active = true
-	n.cooldown.until = time.Now().Add(duration)
+	n.cooldown.until = n.config.Clock.Now().Add(duration)
 }
 
 // Send 发送钉钉通知
-func (n *Notifier) Send(release *github.ReleaseInfo) error {
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
 	// 检查是否可以发送消息
 	canSend, remaining := n.canSendMessage()
 	if !canSend {
@@ -125,13 +218,11 @@ func (n *Notifier) Send(release *github.ReleaseInfo) error {
 		return fmt.Errorf("速率限制等待错误: %v", err)
 	}
 
-	content, err := n.renderTemplate(release)
-	if err != nil {
-		return err
-	}
+	content := render.ExecuteOrFallback(n.template, release, run)
 
 	title := fmt.Sprintf("仓库 %s/%s 发布新版本 %s", release.Owner, release.Repository, release.TagName)
-	err = n.sendMarkdown(title, content)
+	err := n.sendMarkdown(title, content)
+	n.disableOnFatal(err)
 
 	// 检查是否需要触发冷却期
 	if err != nil && (err.Error() == "频率超过限制" ||
@@ -146,7 +237,7 @@ func (n *Notifier) Send(release *github.ReleaseInfo) error {
 }
 
 // SendBatch 批量发送钉钉通知（合并成一条消息）
-func (n *Notifier) SendBatch(releases []*github.ReleaseInfo) error {
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
 	if len(releases) == 0 {
 		return nil
 	}
@@ -163,34 +254,15 @@ func (n *Notifier) SendBatch(releases []*github.ReleaseInfo) error {
 		return fmt.Errorf("速率限制等待错误: %v", err)
 	}
 
-	// 构建批量消息内容
-	var content bytes.Buffer
-	content.WriteString("## 📦 新版本发布汇总\n\n")
-	content.WriteString(fmt.Sprintf("共 %d 个仓库发布了新版本：\n\n", len(releases)))
-
-	for i, release := range releases {
-		content.WriteString(fmt.Sprintf("### %d. [%s/%s](%s)\n\n",
-			i+1, release.Owner, release.Repository, release.HTMLURL))
-		content.WriteString(fmt.Sprintf("**版本**: %s\n\n", release.TagName))
-		content.WriteString(fmt.Sprintf("**发布时间**: %s\n\n",
-			release.PublishedAt.Format("2006-01-02 15:04:05")))
-
-		// 如果有描述信息，添加部分描述（限制长度）
-		if release.Description != "" {
-			desc := release.Description
-			if len(desc) > 100 {
-				desc = desc[:100] + "..."
-			}
-			// 移除换行符，避免格式混乱
-			desc = strings.ReplaceAll(desc, "\n", " ")
-			content.WriteString(fmt.Sprintf("**说明**: %s\n\n", desc))
-		}
-
-		content.WriteString("---\n\n")
+	var err error
+	// 应用机器人接口不支持FeedCard，应用模式始终使用markdown
+	if n.config.FeedCard && len(releases) > 1 && n.app == nil {
+		err = n.sendFeedCard(releases)
+	} else {
+		title := fmt.Sprintf("GitHub 版本更新汇总（%d 个仓库）", len(releases))
+		err = n.sendMarkdown(title, buildBatchMarkdown(releases, run))
 	}
-
-	title := fmt.Sprintf("GitHub 版本更新汇总（%d 个仓库）", len(releases))
-	err := n.sendMarkdown(title, content.String())
+	n.disableOnFatal(err)
 
 	// 检查是否需要触发冷却期
 	if err != nil && (err.Error() == "频率超过限制" ||
@@ -204,6 +276,33 @@ func (n *Notifier) SendBatch(releases []*github.ReleaseInfo) error {
 	return err
 }
 
+// SendDigest 将超过每日上限的版本合并为一条摘要消息发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	// 检查是否可以发送消息
+	canSend, remaining := n.canSendMessage()
+	if !canSend {
+		return fmt.Errorf("钉钉消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	title := fmt.Sprintf("今天还有 %d 个新版本", len(releases))
+	err := n.sendMarkdown(title, buildDigestMarkdown(releases, run))
+	n.disableOnFatal(err)
+	if err != nil && err.Error() == "频率超过限制" {
+		n.setCooldown(10 * time.Minute)
+		return fmt.Errorf("触发钉钉API限流，已设置10分钟冷却期: %v", err)
+	}
+
+	return err
+}
+
 // 发送markdown消息
 func (n *Notifier) sendMarkdown(title, text string) error {
 	type markdownMsg struct {
@@ -216,14 +315,70 @@ func (n *Notifier) sendMarkdown(title, text string) error {
 		Markdown markdownMsg `json:"markdown"`
 	}
 
-	msg := dingMsg{
+	if n.app != nil {
+		return n.app.sendMarkdown(title, text)
+	}
+
+	return n.post(dingMsg{
 		Msgtype: "markdown",
 		Markdown: markdownMsg{
-			Title: title,
-			Text:  text,
+			Title: n.withKeyword(title, " "),
+			Text:  n.withKeyword(text, "\n\n"),
 		},
+	})
+}
+
+// sendFeedCard 发送FeedCard消息，每个版本一张卡片
+func (n *Notifier) sendFeedCard(releases []*github.ReleaseInfo) error {
+	type feedLink struct {
+		Title      string `json:"title"`
+		MessageURL string `json:"messageURL"`
+		PicURL     string `json:"picURL"`
+	}
+
+	type feedCardMsg struct {
+		Msgtype  string `json:"msgtype"`
+		FeedCard struct {
+			Links []feedLink `json:"links"`
+		} `json:"feedCard"`
+	}
+
+	msg := feedCardMsg{Msgtype: "feedCard"}
+	for i, release := range releases {
+		title := fmt.Sprintf("%s/%s 发布 %s", release.Owner, release.Repository, release.TagName)
+		if label := release.EventLabel(); label != "" {
+			title = label + " " + title
+		}
+		if release.IsHighlighted() {
+			title = "⚠️ " + title
+		}
+		if release.Contributed {
+			title = "🙌 " + title
+		}
+		// 关键词只需出现在消息中一次，追加到第一张卡片的标题
+		if i == 0 {
+			title = n.withKeyword(title, " ")
+		}
+		msg.FeedCard.Links = append(msg.FeedCard.Links, feedLink{
+			Title:      title,
+			MessageURL: release.Link(),
+			PicURL:     release.OpenGraphImageURL(),
+		})
 	}
 
+	return n.post(msg)
+}
+
+// withKeyword 在消息不包含关键词时追加关键词
+func (n *Notifier) withKeyword(text, sep string) string {
+	if n.config.Keyword == "" || strings.Contains(text, n.config.Keyword) {
+		return text
+	}
+	return text + sep + n.config.Keyword
+}
+
+// post 发送消息到钉钉webhook并检查返回结果
+func (n *Notifier) post(msg interface{}) error {
 	msgBytes, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("序列化消息失败: %v", err)
@@ -257,11 +412,11 @@ func (n *Notifier) sendMarkdown(title, text string) error {
 	}
 
 	if response.ErrCode != 0 {
-		// 错误码88和660026都表示频率超过限制
-		if response.ErrCode == 88 || response.ErrCode == 660026 {
+		// 频率超过限制，由调用方设置冷却期
+		if isRateLimitCode(response.ErrCode) {
 			return fmt.Errorf("频率超过限制")
 		}
-		return fmt.Errorf("钉钉API错误: %s (code: %d)", response.ErrMsg, response.ErrCode)
+		return classifyError(response.ErrCode, response.ErrMsg)
 	}
 
 	return nil
@@ -0,0 +1,56 @@
+package dingtalk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSend_Cooldown 测试频率超过限制后按配置的时钟计算10分钟冷却期，冷却期内不发送请求
+func TestSend_Cooldown(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprintf(w, `{"errcode":%d,"errmsg":"send too fast"}`, errCodeSendTooFast)
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Now())
+	n, err := New(Config{
+		Enabled:    true,
+		WebhookURL: srv.URL,
+		Bucket:     pacing.NewBucket("dingtalk", pacing.Limit{Burst: 10}),
+		Clock:      fake,
+	}, template.Must(template.New("dingtalk").Parse("{{.Owner}}/{{.Repository}} {{.TagName}}")))
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "o", Repository: "a", TagName: "v1.0.0"}
+	run := render.RunContext{Timestamp: time.Now(), Total: 1}
+	if err := n.Send(release, run); err == nil || !strings.Contains(err.Error(), "冷却期") {
+		t.Fatalf("限流时应设置冷却期: %v", err)
+	}
+	fake.Advance(9 * time.Minute)
+	if err := n.Send(release, run); err == nil || !strings.Contains(err.Error(), "冷却中") {
+		t.Errorf("冷却期内应直接返回错误: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("发送了 %d 次请求，期望 1 次", requests)
+	}
+
+	fake.Advance(time.Minute)
+	n.Send(release, run)
+	if requests != 2 {
+		t.Errorf("冷却期结束后应重新发送，共 %d 次请求", requests)
+	}
+}
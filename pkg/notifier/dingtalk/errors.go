@@ -0,0 +1,74 @@
+package dingtalk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 钉钉机器人常见错误码
+const (
+	errCodeRateLimit        = 88
+	errCodeRateLimitGroup   = 660026
+	errCodeSendTooFast      = 130101
+	errCodeTokenNotExist    = 300001
+	errCodeTokenInvalid     = 400101
+	errCodeSecurityMismatch = 310000
+	errCodeSignInvalid      = 310001
+	errCodeSignExpired      = 310002
+)
+
+// apiError 钉钉API返回的错误
+type apiError struct {
+	Code int
+	Msg  string
+	// hint 面向用户的排查建议
+	hint string
+	// fatal 为true时表示配置错误，继续发送也不会成功，需要停用该渠道
+	fatal bool
+}
+
+func (e *apiError) Error() string {
+	if e.hint != "" {
+		return fmt.Sprintf("钉钉API错误: %s (code: %d)，%s", e.Msg, e.Code, e.hint)
+	}
+	return fmt.Sprintf("钉钉API错误: %s (code: %d)", e.Msg, e.Code)
+}
+
+// isFatal 是否为配置错误（webhook或应用接口），继续发送也不会成功
+func isFatal(err error) bool {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) {
+		return apiErr.fatal
+	}
+	var appErr *appError
+	return errors.As(err, &appErr) && appErr.fatal
+}
+
+// isRateLimitCode 是否为频率超过限制的错误码
+func isRateLimitCode(code int) bool {
+	return code == errCodeRateLimit || code == errCodeRateLimitGroup || code == errCodeSendTooFast
+}
+
+// classifyError 根据错误码和错误信息生成带有排查建议的错误
+func classifyError(code int, msg string) *apiError {
+	err := &apiError{Code: code, Msg: msg}
+	lower := strings.ToLower(msg)
+
+	switch {
+	case code == errCodeTokenNotExist || code == errCodeTokenInvalid:
+		err.hint = "webhook中的access_token无效或机器人已被删除，请检查webhook_url"
+		err.fatal = true
+	case code == errCodeSignInvalid || code == errCodeSignExpired || strings.Contains(lower, "sign"):
+		err.hint = "签名校验失败，请检查secret是否与机器人的加签密钥一致，以及服务器时间是否准确"
+		err.fatal = true
+	case strings.Contains(lower, "whitelist"):
+		err.hint = "出口IP不在机器人的IP白名单中，请将出口IP加入白名单或使用绑定的出口地址"
+		err.fatal = true
+	case code == errCodeSecurityMismatch || strings.Contains(lower, "keyword"):
+		err.hint = "消息不包含机器人设置的自定义关键词，请配置keyword"
+		err.fatal = true
+	}
+
+	return err
+}
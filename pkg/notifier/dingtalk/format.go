@@ -0,0 +1,96 @@
+package dingtalk
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// buildBatchMarkdown 构建批量markdown消息内容
+func buildBatchMarkdown(releases []*github.ReleaseInfo, run render.RunContext) string {
+	// 构建批量消息内容
+	var content bytes.Buffer
+	content.WriteString("## 📦 新版本发布汇总\n\n")
+	content.WriteString(fmt.Sprintf("共 %d 个仓库发布了新版本：\n\n", len(releases)))
+
+	for i, release := range releases {
+		content.WriteString(fmt.Sprintf("### %d. [%s/%s](%s)\n\n",
+			i+1, release.Owner, release.Repository, release.Link()))
+		if label := release.EventLabel(); label != "" {
+			content.WriteString(fmt.Sprintf("**%s**\n\n", label))
+		}
+		if release.IsHighlighted() {
+			content.WriteString(fmt.Sprintf("> **%s**\n\n", release.HighlightBanner()))
+		}
+		if release.Contributed {
+			content.WriteString(fmt.Sprintf("**%s**\n\n", release.ContributorBadge()))
+		}
+		content.WriteString(fmt.Sprintf("**版本**: %s\n\n", release.TagName))
+		content.WriteString(fmt.Sprintf("**发布时间**: %s\n\n",
+			run.FormatTime(release.PublishedAt)))
+		if release.SignatureChecked {
+			content.WriteString(fmt.Sprintf("**签名**: %s\n\n", release.SignatureStatus()))
+		}
+		if release.PinnedVersion != "" {
+			content.WriteString(fmt.Sprintf("**锁定版本**: %s\n\n", release.PinnedStatus()))
+		}
+		if len(release.MatchedAssets) > 0 {
+			content.WriteString(fmt.Sprintf("**附件**: %s\n\n", strings.Join(release.MatchedAssets, ", ")))
+		}
+
+		// 发布说明修改的差异摘要
+		if release.NotesDiff != "" {
+			for _, line := range strings.Split(release.NotesDiff, "\n") {
+				content.WriteString(fmt.Sprintf("> %s\n", line))
+			}
+			content.WriteString("\n")
+		}
+
+		// 如果有描述信息，添加部分描述（限制长度）
+		if release.Description != "" {
+			desc := release.Description
+			if len(desc) > 100 {
+				desc = desc[:100] + "..."
+			}
+			// 移除换行符，避免格式混乱
+			desc = strings.ReplaceAll(desc, "\n", " ")
+			content.WriteString(fmt.Sprintf("**说明**: %s\n\n", desc))
+		}
+
+		content.WriteString("---\n\n")
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString(fmt.Sprintf("*%s*\n", footer))
+	}
+
+	return content.String()
+}
+
+// maxDigestItems 摘要消息中最多列出的版本数
+const maxDigestItems = 50
+
+// buildDigestMarkdown 构建超过每日上限后的摘要消息，每个版本只占一行
+func buildDigestMarkdown(releases []*github.ReleaseInfo, run render.RunContext) string {
+	var content bytes.Buffer
+	content.WriteString(fmt.Sprintf("## 📦 今天还有 %d 个新版本\n\n", len(releases)))
+	content.WriteString("今天的消息数已达到上限，以下版本合并发送：\n\n")
+
+	for i, release := range releases {
+		if i == maxDigestItems {
+			content.WriteString(fmt.Sprintf("- ...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		content.WriteString(fmt.Sprintf("- [%s/%s](%s) %s\n",
+			release.Owner, release.Repository, release.Link(), release.TagName))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString(fmt.Sprintf("\n*%s*\n", footer))
+	}
+
+	return content.String()
+}
@@ -0,0 +1,253 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// 连接加密方式
+const (
+	// SecurityTLS 直接使用TLS连接（通常为465端口）
+	SecurityTLS = "tls"
+	// SecuritySTARTTLS 明文连接后升级为TLS（通常为587端口）
+	SecuritySTARTTLS = "starttls"
+	// SecurityNone 不加密（通常为25端口，仅用于内网中继）
+	SecurityNone = "none"
+)
+
+// defaultPorts 各加密方式的默认端口
+var defaultPorts = map[string]int{
+	SecurityTLS:      465,
+	SecuritySTARTTLS: 587,
+	SecurityNone:     25,
+}
+
+// DefaultPace 默认发送速率
+// 大多数邮件服务商对发送频率有限制，每2秒最多发送一封
+var DefaultPace = pacing.Limit{Interval: 2 * time.Second, Burst: 1}
+
+// Config 邮件通知配置
+type Config struct {
+	Enabled  bool
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	// Security 连接加密方式: starttls（默认）、tls、none
+	Security string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+}
+
+// Notifier 邮件通知器
+type Notifier struct {
+	config   Config
+	template *template.Template
+	limiter  *pacing.Bucket // 速率限制器
+	dialer   *net.Dialer
+}
+
+// New 创建邮件通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.Host == "" {
+		return nil, fmt.Errorf("SMTP服务器地址不能为空")
+	}
+
+	if config.From == "" {
+		return nil, fmt.Errorf("发件人不能为空")
+	}
+
+	if len(config.To) == 0 {
+		return nil, fmt.Errorf("收件人不能为空")
+	}
+
+	if config.Security == "" {
+		config.Security = SecuritySTARTTLS
+	}
+	port, ok := defaultPorts[config.Security]
+	if !ok {
+		return nil, fmt.Errorf("不支持的SMTP加密方式: %s（可选 starttls、tls、none）", config.Security)
+	}
+	if config.Port == 0 {
+		config.Port = port
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	if config.LocalAddr != "" {
+		ip, err := util.ResolveLocalAddr(config.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("email", DefaultPace)
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		limiter:  limiter,
+		dialer:   dialer,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "email"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送邮件通知，邮件正文为渲染后的通知模板
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	subject := fmt.Sprintf("[notify] %s/%s 发布新版本 %s", release.Owner, release.Repository, release.TagName)
+	return n.deliver(subject, content, buildHTML(subject, []string{content}, run))
+}
+
+// SendBatch 将多个版本合并为一封摘要邮件，每个版本使用通知模板渲染
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("[notify] GitHub 版本更新汇总（%d 个仓库）", len(releases))
+	return n.sendReleases(subject, releases, run)
+}
+
+// SendDigest 将超过每日上限的版本合并为一封摘要邮件
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("[notify] 今天还有 %d 个新版本", len(releases))
+	return n.sendReleases(subject, releases, run)
+}
+
+// sendReleases 渲染多个版本并发送一封邮件
+func (n *Notifier) sendReleases(subject string, releases []*github.ReleaseInfo, run render.RunContext) error {
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	sections := make([]string, 0, len(releases))
+	for _, release := range releases {
+		content := render.ExecuteOrFallback(n.template, release, run)
+		sections = append(sections, content)
+	}
+
+	plain := strings.Join(sections, "\n\n---\n\n")
+	return n.deliver(subject, plain, buildHTML(subject, sections, run))
+}
+
+// deliver 连接SMTP服务器并发送邮件
+func (n *Notifier) deliver(subject, plain, html string) error {
+	msg, err := buildMessage(n.config.From, n.config.To, subject, plain, html)
+	if err != nil {
+		return err
+	}
+
+	host := n.config.Host
+	addr := net.JoinHostPort(host, strconv.Itoa(n.config.Port))
+	tlsConfig := &tls.Config{ServerName: host}
+
+	var conn net.Conn
+	if n.config.Security == SecurityTLS {
+		conn, err = tls.DialWithDialer(n.dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = n.dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("连接SMTP服务器失败: %v", err)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("连接SMTP服务器失败: %v", err)
+	}
+	defer c.Close()
+
+	if n.config.Security == SecuritySTARTTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS失败: %v", err)
+		}
+	}
+
+	if n.config.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, host)); err != nil {
+			return fmt.Errorf("SMTP认证失败: %v", err)
+		}
+	}
+
+	if err := c.Mail(addressOf(n.config.From)); err != nil {
+		return fmt.Errorf("设置发件人失败: %v", err)
+	}
+	for _, to := range n.config.To {
+		if err := c.Rcpt(addressOf(to)); err != nil {
+			return fmt.Errorf("设置收件人 %s 失败: %v", to, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("发送邮件失败: %v", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("发送邮件失败: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("发送邮件失败: %v", err)
+	}
+
+	return c.Quit()
+}
+
+// addressOf 从 "名称 <地址>" 中取出邮件地址
+func addressOf(s string) string {
+	if i := strings.LastIndex(s, "<"); i >= 0 {
+		if j := strings.LastIndex(s, ">"); j > i {
+			return s[i+1 : j]
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// buildMessage 构建包含纯文本和HTML两个版本的邮件
+func buildMessage(from string, to []string, subject, plain, html string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeMessage(&buf, from, to, subject, plain, html); err != nil {
+		return nil, fmt.Errorf("构建邮件失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,82 @@
+package email
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+// TestBuildMessage 测试发件人和收件人名称、主题的MIME编码，以及纯文本和HTML两个正文
+func TestBuildMessage(t *testing.T) {
+	data, err := buildMessage("版本通知 <bot@example.com>", []string{"ops@example.com", "Doe, John <john@example.com>"},
+		"o/a 发布新版本 v1.0.0", "纯文本正文", "<p>HTML正文</p>")
+	if err != nil {
+		t.Fatalf("构建邮件失败: %v", err)
+	}
+	for _, line := range strings.Split(string(data), "\r\n") {
+		if line == "" {
+			break
+		}
+		for _, r := range line {
+			if r > 127 {
+				t.Fatalf("邮件头包含未编码的非ASCII字符: %s", line)
+			}
+		}
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("解析邮件失败: %v", err)
+	}
+	from, err := mail.ParseAddress(msg.Header.Get("From"))
+	if err != nil || from.Name != "版本通知" || from.Address != "bot@example.com" {
+		t.Errorf("发件人为 %q: %v", msg.Header.Get("From"), err)
+	}
+	to, err := mail.ParseAddressList(msg.Header.Get("To"))
+	if err != nil || len(to) != 2 || to[0].Address != "ops@example.com" || to[1].Name != "Doe, John" {
+		t.Errorf("收件人为 %q: %v", msg.Header.Get("To"), err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "o/a 发布新版本 v1.0.0" {
+		t.Errorf("主题为 %q: %v", subject, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type 为 %q: %v", msg.Header.Get("Content-Type"), err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("读取正文失败: %v", err)
+		}
+		body, _ := io.ReadAll(part)
+		bodies = append(bodies, part.Header.Get("Content-Type")+"|"+string(body))
+	}
+	if len(bodies) != 2 || bodies[0] != "text/plain; charset=utf-8|纯文本正文" || bodies[1] != "text/html; charset=utf-8|<p>HTML正文</p>" {
+		t.Errorf("正文不正确: %q", bodies)
+	}
+}
+
+// TestEncodeAddress 测试只有地址、名称带引号和ASCII名称的情况
+func TestEncodeAddress(t *testing.T) {
+	for _, tc := range []struct{ in, want string }{
+		{"bot@example.com", "bot@example.com"},
+		{"<bot@example.com>", "<bot@example.com>"},
+		{"Notify <bot@example.com>", `"Notify" <bot@example.com>`},
+		{`"通知" <bot@example.com>`, "=?utf-8?q?=E9=80=9A=E7=9F=A5?= <bot@example.com>"},
+	} {
+		if got := encodeAddress(tc.in); got != tc.want {
+			t.Errorf("encodeAddress(%q) = %q，期望 %q", tc.in, got, tc.want)
+		}
+	}
+}
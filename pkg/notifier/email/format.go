@@ -0,0 +1,182 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// writeMessage 写入邮件头和 multipart/alternative 正文
+func writeMessage(w io.Writer, from string, to []string, subject, plain, htmlBody string) error {
+	mw := multipart.NewWriter(w)
+
+	headers := []string{
+		"From: " + encodeAddress(from),
+		"To: " + strings.Join(encodeAddresses(to), ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", mw.Boundary()),
+	}
+	if _, err := io.WriteString(w, strings.Join(headers, "\r\n")+"\r\n\r\n"); err != nil {
+		return err
+	}
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", plain},
+		{"text/html; charset=utf-8", htmlBody},
+	} {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := io.WriteString(qw, part.body); err != nil {
+			return err
+		}
+		if err := qw.Close(); err != nil {
+			return err
+		}
+	}
+
+	return mw.Close()
+}
+
+// encodeAddress 对 "名称 <地址>" 中的名称编码：非ASCII的名称使用MIME Q编码（RFC 2047），
+// 避免中文名称显示为乱码；含有逗号等特殊字符的ASCII名称加引号
+func encodeAddress(s string) string {
+	i := strings.LastIndex(s, "<")
+	if i < 0 {
+		return strings.TrimSpace(s)
+	}
+	name := strings.Trim(strings.TrimSpace(s[:i]), `"`)
+	return (&mail.Address{Name: name, Address: addressOf(s)}).String()
+}
+
+// encodeAddresses 对每个收件人调用 encodeAddress
+func encodeAddresses(list []string) []string {
+	encoded := make([]string, len(list))
+	for i, s := range list {
+		encoded[i] = encodeAddress(s)
+	}
+	return encoded
+}
+
+// buildHTML 将渲染后的模板内容（Markdown）转换为HTML邮件，多个版本之间使用分隔线
+func buildHTML(title string, sections []string, run render.RunContext) string {
+	var b bytes.Buffer
+	b.WriteString(`<!DOCTYPE html><html><head><meta charset="utf-8"><title>`)
+	b.WriteString(html.EscapeString(title))
+	b.WriteString(`</title></head>`)
+	b.WriteString(`<body style="font-family:-apple-system,'Segoe UI',Helvetica,Arial,sans-serif;font-size:14px;line-height:1.6;color:#24292f;max-width:720px;margin:0 auto;padding:16px">`)
+
+	for i, section := range sections {
+		if i > 0 {
+			b.WriteString(`<hr style="border:none;border-top:1px solid #d0d7de;margin:24px 0">`)
+		}
+		b.WriteString(markdownToHTML(section))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		b.WriteString(`<p style="color:#57606a;font-size:12px">`)
+		b.WriteString(html.EscapeString(footer))
+		b.WriteString(`</p>`)
+	}
+
+	b.WriteString(`</body></html>`)
+	return b.String()
+}
+
+var (
+	mdHeading = regexp.MustCompile(`^(#{1,6})\s+(.+)$`)
+	mdBold    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdCode    = regexp.MustCompile("`([^`]+)`")
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// inline 转换行内的粗体、代码和链接，输入需要已经转义
+func inline(s string) string {
+	s = mdLink.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = mdBold.ReplaceAllString(s, `<strong>$1</strong>`)
+	s = mdCode.ReplaceAllString(s, `<code>$1</code>`)
+	return s
+}
+
+// markdownToHTML 将模板中常用的Markdown语法（标题、粗体、代码、链接、引用、列表、分隔线）转换为HTML
+// 其他内容按段落输出，所有文本都会先转义
+func markdownToHTML(md string) string {
+	var b bytes.Buffer
+	var para []string
+	inList := false
+
+	flush := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + strings.Join(para, "<br>") + "</p>")
+			para = nil
+		}
+	}
+	closeList := func() {
+		if inList {
+			b.WriteString("</ul>")
+			inList = false
+		}
+	}
+
+	for _, raw := range strings.Split(md, "\n") {
+		line := strings.TrimSpace(raw)
+		escaped := html.EscapeString(line)
+
+		switch {
+		case line == "":
+			flush()
+			closeList()
+		case line == "---":
+			flush()
+			closeList()
+			b.WriteString(`<hr style="border:none;border-top:1px solid #d0d7de">`)
+		case mdHeading.MatchString(line):
+			flush()
+			closeList()
+			m := mdHeading.FindStringSubmatch(line)
+			level := len(m[1])
+			b.WriteString(fmt.Sprintf("<h%d>%s</h%d>", level, inline(html.EscapeString(m[2])), level))
+		case strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* "):
+			flush()
+			if !inList {
+				b.WriteString("<ul>")
+				inList = true
+			}
+			b.WriteString("<li>" + inline(html.EscapeString(line[2:])) + "</li>")
+		case strings.HasPrefix(line, ">"):
+			flush()
+			closeList()
+			quote := strings.TrimSpace(strings.TrimPrefix(line, ">"))
+			b.WriteString(`<blockquote style="margin:0;padding-left:12px;border-left:3px solid #d0d7de;color:#57606a">` +
+				inline(html.EscapeString(quote)) + "</blockquote>")
+		default:
+			closeList()
+			para = append(para, inline(escaped))
+		}
+	}
+	flush()
+	closeList()
+
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package fault
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// defaultDelay 模拟超时时默认阻塞的时间
+const defaultDelay = 10 * time.Second
+
+// Config 故障注入配置，各比例之和不能超过1，其余情况模拟发送成功
+type Config struct {
+	// FailRate 返回普通发送失败的比例
+	FailRate float64
+	// TimeoutRate 阻塞 Delay 后返回超时错误的比例
+	TimeoutRate float64
+	// RateLimitRate 返回限流错误的比例
+	RateLimitRate float64
+	// Delay 模拟超时时阻塞的时间，默认10秒
+	Delay time.Duration
+	// Seed 随机数种子，为0时使用当前时间，固定种子可以复现同样的故障序列
+	Seed int64
+}
+
+// ParseSpec 解析故障注入参数，格式如 fail=0.3,timeout=0.1,ratelimit=0.2,delay=5s,seed=42
+func ParseSpec(spec string) (Config, error) {
+	var config Config
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("无效的故障注入参数: %s（格式为 key=value）", part)
+		}
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case "fail":
+			config.FailRate, err = parseRate(value)
+		case "timeout":
+			config.TimeoutRate, err = parseRate(value)
+		case "ratelimit":
+			config.RateLimitRate, err = parseRate(value)
+		case "delay":
+			config.Delay, err = time.ParseDuration(value)
+		case "seed":
+			config.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			return Config{}, fmt.Errorf("未知的故障注入参数: %s（可选 fail、timeout、ratelimit、delay、seed）", key)
+		}
+		if err != nil {
+			return Config{}, fmt.Errorf("无效的故障注入参数 %s: %v", part, err)
+		}
+	}
+
+	if total := config.FailRate + config.TimeoutRate + config.RateLimitRate; total > 1 {
+		return Config{}, fmt.Errorf("故障比例之和不能超过1，当前为 %.2f", total)
+	}
+	return config, nil
+}
+
+// parseRate 解析0~1之间的比例
+func parseRate(value string) (float64, error) {
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("比例必须在0~1之间")
+	}
+	return rate, nil
+}
+
+// Notifier 故障注入通知器，不发送任何消息，按配置的比例随机返回失败、超时或限流错误
+// 用于端到端验证重试、失败队列和每日上限等行为，只能通过命令行参数 --fault 启用
+type Notifier struct {
+	config Config
+	mu     sync.Mutex
+	rand   *rand.Rand
+}
+
+// New 创建故障注入通知器
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.Delay <= 0 {
+		config.Delay = defaultDelay
+	}
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	return &Notifier{config: config, rand: rand.New(rand.NewSource(seed))}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "fault"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return true
+}
+
+// Send 模拟发送单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.inject("Send", 1)
+}
+
+// SendBatch 模拟批量发送
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	return n.inject("SendBatch", len(releases))
+}
+
+// SendDigest 模拟发送摘要
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	return n.inject("SendDigest", len(releases))
+}
+
+// inject 按比例随机决定本次发送的结果
+func (n *Notifier) inject(method string, count int) error {
+	n.mu.Lock()
+	r := n.rand.Float64()
+	n.mu.Unlock()
+
+	switch {
+	case r < n.config.FailRate:
+		log.Printf("[fault] %s: 模拟发送失败（%d 个版本）", method, count)
+		return fmt.Errorf("模拟发送失败")
+	case r < n.config.FailRate+n.config.TimeoutRate:
+		log.Printf("[fault] %s: 模拟请求超时，阻塞 %v（%d 个版本）", method, n.config.Delay, count)
+		time.Sleep(n.config.Delay)
+		return fmt.Errorf("模拟请求超时: context deadline exceeded")
+	case r < n.config.FailRate+n.config.TimeoutRate+n.config.RateLimitRate:
+		log.Printf("[fault] %s: 模拟触发限流（%d 个版本）", method, count)
+		return fmt.Errorf("模拟触发限流: rate limit exceeded")
+	default:
+		log.Printf("[fault] %s: 模拟发送成功（%d 个版本）", method, count)
+		return nil
+	}
+}
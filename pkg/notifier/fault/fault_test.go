@@ -0,0 +1,44 @@
+package fault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestParseSpec 测试故障注入参数解析
+func TestParseSpec(t *testing.T) {
+	config, err := ParseSpec("fail=0.3, timeout=0.1,ratelimit=0.2,delay=5s,seed=42")
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	want := Config{FailRate: 0.3, TimeoutRate: 0.1, RateLimitRate: 0.2, Delay: 5 * time.Second, Seed: 42}
+	if config != want {
+		t.Errorf("解析结果为 %+v，期望 %+v", config, want)
+	}
+
+	for _, spec := range []string{"fail", "fail=2", "fail=0.6,ratelimit=0.6", "unknown=1", "delay=abc"} {
+		if _, err := ParseSpec(spec); err == nil {
+			t.Errorf("%q: 期望返回错误", spec)
+		}
+	}
+}
+
+// TestInject 测试比例为1时总是返回对应的错误
+func TestInject(t *testing.T) {
+	releases := []*github.ReleaseInfo{{Owner: "o", Repository: "a", TagName: "v1.0.0"}}
+
+	n, _ := New(Config{RateLimitRate: 1, Seed: 1}, nil)
+	for i := 0; i < 10; i++ {
+		if err := n.SendBatch(releases, render.RunContext{}); err == nil {
+			t.Fatal("期望返回限流错误")
+		}
+	}
+
+	n, _ = New(Config{Seed: 1}, nil)
+	if err := n.SendBatch(releases, render.RunContext{}); err != nil {
+		t.Errorf("未配置故障时期望发送成功: %v", err)
+	}
+}
@@ -0,0 +1,223 @@
+package feishu
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// 飞书自定义机器人常见错误码
+const (
+	errCodeRateLimit     = 11232 // 发送频率超过限制
+	errCodeSignInvalid   = 19021 // 签名校验失败
+	errCodeIPNotAllowed  = 19022 // 出口IP不在白名单中
+	errCodeKeywordFailed = 19024 // 消息不包含自定义关键词
+)
+
+// DefaultPace 默认发送速率
+// 飞书自定义机器人限制为每分钟100次、每秒5次
+// 设置为每秒一条，突发允许3条
+var DefaultPace = pacing.Limit{Interval: time.Second, Burst: 3}
+
+// Config 飞书机器人配置
+type Config struct {
+	Enabled    bool
+	WebhookURL string
+	// Secret 签名校验密钥，为空时不签名
+	Secret string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier 飞书机器人通知器
+type Notifier struct {
+	config   Config
+	template *template.Template
+	limiter  *pacing.Bucket // 速率限制器
+	client   *http.Client
+	mu       sync.Mutex // 保护冷却和停用状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+	// disabledReason 因配置错误被停用时的原因，为空表示未停用
+	disabledReason string
+}
+
+// New 创建飞书机器人通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("飞书webhook URL不能为空")
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("feishu", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		limiter:  limiter,
+		client:   client,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "feishu"
+}
+
+// IsEnabled 是否启用（因配置错误被停用后返回false）
+func (n *Notifier) IsEnabled() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.config.Enabled && n.disabledReason == ""
+}
+
+// Send 发送飞书卡片消息，正文为渲染后的通知模板
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	return n.sendCard(releaseCard(release, content, run))
+}
+
+// SendBatch 批量发送飞书通知（合并成一张卡片）
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.sendCard(batchCard(releases, run))
+}
+
+// SendDigest 将超过每日上限的版本合并为一张摘要卡片发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.sendCard(digestCard(releases, run))
+}
+
+// sendCard 发送交互式卡片消息
+func (n *Notifier) sendCard(c card) error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+	if remaining > 0 {
+		return fmt.Errorf("飞书消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	msg := struct {
+		Timestamp string `json:"timestamp,omitempty"`
+		Sign      string `json:"sign,omitempty"`
+		MsgType   string `json:"msg_type"`
+		Card      card   `json:"card"`
+	}{
+		MsgType: "interactive",
+		Card:    c,
+	}
+	if n.config.Secret != "" {
+		msg.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+		msg.Sign = sign(msg.Timestamp, n.config.Secret)
+	}
+
+	return n.post(msg)
+}
+
+// sign 计算飞书签名：以 "timestamp\nsecret" 为密钥对空消息做HMAC-SHA256，再进行Base64编码
+func sign(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// post 发送消息到飞书webhook并检查返回结果
+func (n *Notifier) post(msg interface{}) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	resp, err := n.client.Post(n.config.WebhookURL, "application/json", bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+
+	var hint string
+	switch response.Code {
+	case 0:
+		return nil
+	case errCodeRateLimit:
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(time.Minute)
+		n.mu.Unlock()
+		return fmt.Errorf("触发飞书API限流，已设置1分钟冷却期: %s", response.Msg)
+	case errCodeSignInvalid:
+		hint = "签名校验失败，请检查secret是否与机器人的签名密钥一致，以及服务器时间是否准确"
+	case errCodeIPNotAllowed:
+		hint = "出口IP不在机器人的IP白名单中，请将出口IP加入白名单或使用绑定的出口地址"
+	case errCodeKeywordFailed:
+		hint = "消息不包含机器人设置的自定义关键词，请在模板中加入关键词"
+	default:
+		return fmt.Errorf("飞书API错误: %s (code: %d)", response.Msg, response.Code)
+	}
+
+	// 配置类错误，继续发送也不会成功，停用该渠道
+	err = fmt.Errorf("飞书API错误: %s (code: %d)，%s", response.Msg, response.Code, hint)
+	n.mu.Lock()
+	if n.disabledReason == "" {
+		n.disabledReason = err.Error()
+		log.Printf("已停用飞书通知渠道: %s", n.disabledReason)
+	}
+	n.mu.Unlock()
+	return err
+}
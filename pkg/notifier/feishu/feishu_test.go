@@ -0,0 +1,169 @@
+package feishu
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// sentMessage 飞书webhook收到的消息
+type sentMessage struct {
+	Timestamp string `json:"timestamp"`
+	Sign      string `json:"sign"`
+	MsgType   string `json:"msg_type"`
+	Card      card   `json:"card"`
+}
+
+// newTestServer 启动模拟飞书webhook的服务器，记录收到的消息并返回指定的响应
+func newTestServer(t *testing.T, response string) (*httptest.Server, *[]sentMessage) {
+	t.Helper()
+	var messages []sentMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var msg sentMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Errorf("无效的消息: %s", body)
+		}
+		messages = append(messages, msg)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &messages
+}
+
+func newTestNotifier(t *testing.T, url, secret string) *Notifier {
+	t.Helper()
+	n, err := New(Config{
+		Enabled:    true,
+		WebhookURL: url,
+		Secret:     secret,
+		Bucket:     pacing.NewBucket("feishu", pacing.Limit{Burst: 10}),
+	}, template.Must(template.New("feishu").Parse("# {{.Owner}}/{{.Repository}}\n版本 {{.TagName}}")))
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	return n
+}
+
+// TestSign 测试签名与飞书文档的算法一致
+func TestSign(t *testing.T) {
+	if got := sign("1700000000", "secret-key"); got != "N0RtTzqTfsgQdjVuqGL9DLGyeHEtDrLoj1ceiswVRDE=" {
+		t.Errorf("签名不正确: %s", got)
+	}
+}
+
+// TestSend_Card 测试单个版本的卡片结构和签名字段
+func TestSend_Card(t *testing.T) {
+	srv, messages := newTestServer(t, `{"code":0,"msg":"success"}`)
+	n := newTestNotifier(t, srv.URL, "secret-key")
+
+	release := &github.ReleaseInfo{
+		Event:      github.EventRelease,
+		Owner:      "o",
+		Repository: "r",
+		TagName:    "v1.0.0",
+		HTMLURL:    "https://github.com/o/r/releases/tag/v1.0.0",
+	}
+	if err := n.Send(release, render.RunContext{Timestamp: time.Now(), Total: 1}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if len(*messages) != 1 {
+		t.Fatalf("收到 %d 条消息，期望 1 条", len(*messages))
+	}
+	msg := (*messages)[0]
+
+	ts, err := strconv.ParseInt(msg.Timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(ts, 0)) > time.Minute {
+		t.Errorf("时间戳不正确: %q", msg.Timestamp)
+	}
+	if msg.Sign != sign(msg.Timestamp, "secret-key") {
+		t.Errorf("签名与时间戳不匹配: %s", msg.Sign)
+	}
+
+	if msg.MsgType != "interactive" || !msg.Card.Config.WideScreenMode {
+		t.Errorf("消息类型不正确: %+v", msg)
+	}
+	if msg.Card.Header.Title.Content != "o/r 发布新版本 v1.0.0" || msg.Card.Header.Template != "blue" {
+		t.Errorf("卡片标题不正确: %+v", msg.Card.Header)
+	}
+	elements := msg.Card.Elements
+	if len(elements) < 2 || elements[0].Tag != "div" || elements[0].Text.Tag != "lark_md" {
+		t.Fatalf("卡片元素不正确: %+v", elements)
+	}
+	if elements[0].Text.Content != "**o/r**\n版本 v1.0.0" {
+		t.Errorf("正文应将Markdown标题转换为粗体: %q", elements[0].Text.Content)
+	}
+	if elements[1].Tag != "action" || len(elements[1].Actions) != 1 || elements[1].Actions[0].URL != release.HTMLURL {
+		t.Errorf("查看详情按钮不正确: %+v", elements[1])
+	}
+}
+
+// TestSend_NoSecret 测试未配置签名密钥时不发送签名字段
+func TestSend_NoSecret(t *testing.T) {
+	srv, messages := newTestServer(t, `{"code":0,"msg":"success"}`)
+	n := newTestNotifier(t, srv.URL, "")
+
+	if err := n.SendBatch(testReleases(2), render.RunContext{Timestamp: time.Now(), Total: 2}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	msg := (*messages)[0]
+	if msg.Timestamp != "" || msg.Sign != "" {
+		t.Errorf("未配置密钥时不应签名: %+v", msg)
+	}
+	// 批量卡片：概要、每个版本一个分隔线和正文
+	if len(msg.Card.Elements) != 5 || msg.Card.Elements[1].Tag != "hr" {
+		t.Errorf("批量卡片元素不正确: %+v", msg.Card.Elements)
+	}
+}
+
+// TestSend_ErrorCode 测试飞书返回非零错误码时发送失败，配置类错误停用渠道
+func TestSend_ErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		disabled bool
+	}{
+		{"未知错误", `{"code":9499,"msg":"Bad Request"}`, false},
+		{"限流", `{"code":11232,"msg":"frequency limited"}`, false},
+		{"签名校验失败", `{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := newTestServer(t, tt.response)
+			n := newTestNotifier(t, srv.URL, "secret-key")
+
+			err := n.SendBatch(testReleases(1), render.RunContext{Timestamp: time.Now(), Total: 1})
+			if err == nil || !strings.Contains(err.Error(), "飞书API") {
+				t.Fatalf("期望返回飞书API错误，得到: %v", err)
+			}
+			if n.IsEnabled() == tt.disabled {
+				t.Errorf("渠道启用状态不正确: %v", n.IsEnabled())
+			}
+		})
+	}
+}
+
+func testReleases(count int) []*github.ReleaseInfo {
+	var releases []*github.ReleaseInfo
+	for i := 0; i < count; i++ {
+		releases = append(releases, &github.ReleaseInfo{
+			Event:      github.EventRelease,
+			Owner:      "o",
+			Repository: "r" + strconv.Itoa(i),
+			TagName:    "v1.0.0",
+			HTMLURL:    "https://github.com/o/r/releases/tag/v1.0.0",
+		})
+	}
+	return releases
+}
@@ -0,0 +1,202 @@
+package feishu
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxBatchItems 批量卡片中最多展示的版本数，避免超过卡片大小限制
+const maxBatchItems = 30
+
+// maxDigestItems 摘要卡片中最多列出的版本数
+const maxDigestItems = 50
+
+// card 飞书交互式卡片
+type card struct {
+	Config   cardConfig    `json:"config"`
+	Header   cardHeader    `json:"header"`
+	Elements []cardElement `json:"elements"`
+}
+
+type cardConfig struct {
+	WideScreenMode bool `json:"wide_screen_mode"`
+}
+
+type cardHeader struct {
+	Title    cardText `json:"title"`
+	Template string   `json:"template"`
+}
+
+// cardText 文本对象，tag 为 plain_text 或 lark_md
+type cardText struct {
+	Tag     string `json:"tag"`
+	Content string `json:"content"`
+}
+
+// cardElement 卡片元素（div、hr、action、note）
+type cardElement struct {
+	Tag      string       `json:"tag"`
+	Text     *cardText    `json:"text,omitempty"`
+	Actions  []cardButton `json:"actions,omitempty"`
+	Elements []cardText   `json:"elements,omitempty"`
+}
+
+type cardButton struct {
+	Tag  string   `json:"tag"`
+	Text cardText `json:"text"`
+	URL  string   `json:"url"`
+	Type string   `json:"type"`
+}
+
+func div(content string) cardElement {
+	return cardElement{Tag: "div", Text: &cardText{Tag: "lark_md", Content: content}}
+}
+
+func hr() cardElement {
+	return cardElement{Tag: "hr"}
+}
+
+func button(label, url string) cardElement {
+	return cardElement{Tag: "action", Actions: []cardButton{{
+		Tag:  "button",
+		Text: cardText{Tag: "plain_text", Content: label},
+		URL:  url,
+		Type: "primary",
+	}}}
+}
+
+func note(content string) cardElement {
+	return cardElement{Tag: "note", Elements: []cardText{{Tag: "plain_text", Content: content}}}
+}
+
+func header(title, template string) cardHeader {
+	return cardHeader{Title: cardText{Tag: "plain_text", Content: title}, Template: template}
+}
+
+var mdHeading = regexp.MustCompile(`(?m)^#{1,6}\s+(.+)$`)
+
+// toLarkMD 将模板中的Markdown标题转换为粗体，lark_md 不支持标题语法
+func toLarkMD(s string) string {
+	return mdHeading.ReplaceAllString(s, "**$1**")
+}
+
+// headerTemplate 根据版本选择卡片标题颜色
+func headerTemplate(release *github.ReleaseInfo) string {
+	switch {
+	case release.IsHighlighted():
+		return "red"
+	case release.Event == github.EventRelease && release.Prerelease:
+		return "orange"
+	case release.Event != github.EventRelease:
+		return "wathet"
+	default:
+		return "blue"
+	}
+}
+
+// releaseCard 构建单个版本的卡片，正文为渲染后的模板
+func releaseCard(release *github.ReleaseInfo, content string, run render.RunContext) card {
+	title := fmt.Sprintf("%s/%s 发布新版本 %s", release.Owner, release.Repository, release.TagName)
+	if label := release.EventLabel(); label != "" {
+		title = label + " " + title
+	}
+
+	elements := []cardElement{div(toLarkMD(content))}
+	if release.Link() != "" {
+		elements = append(elements, button("查看详情", release.Link()))
+	}
+	if footer := run.Footer(); footer != "" {
+		elements = append(elements, note(footer))
+	}
+
+	return card{
+		Config:   cardConfig{WideScreenMode: true},
+		Header:   header(title, headerTemplate(release)),
+		Elements: elements,
+	}
+}
+
+// batchCard 构建批量卡片，每个版本一段，版本之间使用分隔线
+func batchCard(releases []*github.ReleaseInfo, run render.RunContext) card {
+	elements := []cardElement{div(fmt.Sprintf("共 %d 个仓库发布了新版本：", len(releases)))}
+	template := "blue"
+
+	for i, release := range releases {
+		if i == maxBatchItems {
+			elements = append(elements, hr(), div(fmt.Sprintf("...以及其他 %d 个版本", len(releases)-maxBatchItems)))
+			break
+		}
+		if release.IsHighlighted() {
+			template = "red"
+		}
+
+		var b strings.Builder
+		b.WriteString(fmt.Sprintf("**%d. [%s/%s](%s)**\n", i+1, release.Owner, release.Repository, release.Link()))
+		if label := release.EventLabel(); label != "" {
+			b.WriteString(label + "\n")
+		}
+		if release.IsHighlighted() {
+			b.WriteString(fmt.Sprintf("<font color='red'>**%s**</font>\n", release.HighlightBanner()))
+		}
+		if release.Contributed {
+			b.WriteString(fmt.Sprintf("**%s**\n", release.ContributorBadge()))
+		}
+		b.WriteString(fmt.Sprintf("**版本**: %s\n", release.TagName))
+		b.WriteString(fmt.Sprintf("**发布时间**: %s", run.FormatTime(release.PublishedAt)))
+		if release.SignatureChecked {
+			b.WriteString(fmt.Sprintf("\n**签名**: %s", release.SignatureStatus()))
+		}
+		if release.PinnedVersion != "" {
+			b.WriteString(fmt.Sprintf("\n**锁定版本**: %s", release.PinnedStatus()))
+		}
+		if len(release.MatchedAssets) > 0 {
+			b.WriteString(fmt.Sprintf("\n**附件**: %s", strings.Join(release.MatchedAssets, ", ")))
+		}
+		if release.NotesDiff != "" {
+			b.WriteString("\n" + release.NotesDiff)
+		}
+
+		elements = append(elements, hr(), div(b.String()))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		elements = append(elements, note(footer))
+	}
+
+	return card{
+		Config:   cardConfig{WideScreenMode: true},
+		Header:   header(fmt.Sprintf("📦 GitHub 版本更新汇总（%d 个仓库）", len(releases)), template),
+		Elements: elements,
+	}
+}
+
+// digestCard 构建超过每日上限后的摘要卡片，每个版本只占一行
+func digestCard(releases []*github.ReleaseInfo, run render.RunContext) card {
+	var lines strings.Builder
+	for i, release := range releases {
+		if i == maxDigestItems {
+			lines.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		lines.WriteString(fmt.Sprintf("- [%s/%s](%s) %s\n",
+			release.Owner, release.Repository, release.Link(), release.TagName))
+	}
+
+	elements := []cardElement{
+		div("今天的消息数已达到上限，以下版本合并发送："),
+		div(lines.String()),
+	}
+	if footer := run.Footer(); footer != "" {
+		elements = append(elements, note(footer))
+	}
+
+	return card{
+		Config:   cardConfig{WideScreenMode: true},
+		Header:   header(fmt.Sprintf("📦 今天还有 %d 个新版本", len(releases)), "blue"),
+		Elements: elements,
+	}
+}
@@ -0,0 +1,267 @@
+package googlechat
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxBatchItems 批量卡片中最多展示的版本数，避免超过Google Chat消息32KB的大小限制
+const maxBatchItems = 20
+
+// maxDigestItems 摘要卡片中最多列出的版本数
+const maxDigestItems = 50
+
+// maxDescription 卡片中发布说明的最大字节数
+const maxDescription = 1000
+
+// labels 卡片中的固定文字
+type labels struct {
+	NewRelease  string
+	Version     string
+	Published   string
+	Signed      string
+	Pinned      string
+	Assets      string
+	ViewRelease string
+	BatchTitle  string
+	BatchIntro  string
+	DigestTitle string
+	DigestIntro string
+	More        string
+	Contributor string
+}
+
+var labelsZH = labels{
+	NewRelease:  "%s/%s 发布新版本",
+	Version:     "版本",
+	Published:   "发布时间",
+	Signed:      "签名",
+	Pinned:      "锁定版本",
+	Assets:      "附件",
+	ViewRelease: "查看详情",
+	BatchTitle:  "📦 GitHub 版本更新汇总",
+	BatchIntro:  "共 %d 个仓库发布了新版本",
+	DigestTitle: "📦 今天还有 %d 个新版本",
+	DigestIntro: "今天的消息数已达到上限，以下版本合并发送",
+	More:        "...以及其他 %d 个版本",
+	Contributor: "🙌 你参与贡献的仓库",
+}
+
+var labelsEN = labels{
+	NewRelease:  "New release of %s/%s",
+	Version:     "Version",
+	Published:   "Published",
+	Signed:      "Signed",
+	Pinned:      "Pinned",
+	Assets:      "Assets",
+	ViewRelease: "View release",
+	BatchTitle:  "📦 GitHub release summary",
+	BatchIntro:  "%d repositories published new releases",
+	DigestTitle: "📦 %d more releases today",
+	DigestIntro: "The daily message limit has been reached, the remaining releases are combined",
+	More:        "...and %d more",
+	Contributor: "🙌 You contribute here",
+}
+
+// labelsFor 按渠道语言选择卡片文字
+func labelsFor(run render.RunContext) labels {
+	if run.Locale == render.LocaleEN {
+		return labelsEN
+	}
+	return labelsZH
+}
+
+// message Google Chat 消息，使用 Cards v2
+type message struct {
+	CardsV2 []cardWithID `json:"cardsV2"`
+}
+
+type cardWithID struct {
+	CardID string `json:"cardId"`
+	Card   card   `json:"card"`
+}
+
+type card struct {
+	Header   *cardHeader `json:"header,omitempty"`
+	Sections []section   `json:"sections"`
+}
+
+type cardHeader struct {
+	Title    string `json:"title"`
+	Subtitle string `json:"subtitle,omitempty"`
+}
+
+type section struct {
+	Header  string   `json:"header,omitempty"`
+	Widgets []widget `json:"widgets"`
+}
+
+// widget 卡片组件，每个组件只设置其中一个字段
+type widget struct {
+	DecoratedText *decoratedText `json:"decoratedText,omitempty"`
+	TextParagraph *textParagraph `json:"textParagraph,omitempty"`
+	ButtonList    *buttonList    `json:"buttonList,omitempty"`
+}
+
+type decoratedText struct {
+	TopLabel string `json:"topLabel,omitempty"`
+	Text     string `json:"text"`
+	WrapText bool   `json:"wrapText,omitempty"`
+}
+
+type textParagraph struct {
+	Text string `json:"text"`
+}
+
+type buttonList struct {
+	Buttons []button `json:"buttons"`
+}
+
+type button struct {
+	Text    string  `json:"text"`
+	OnClick onClick `json:"onClick"`
+}
+
+type onClick struct {
+	OpenLink openLink `json:"openLink"`
+}
+
+type openLink struct {
+	URL string `json:"url"`
+}
+
+// truncate 按字节数截断文本，不截断多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	n -= len("...")
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
+
+func field(label, value string) widget {
+	return widget{DecoratedText: &decoratedText{TopLabel: label, Text: html.EscapeString(value), WrapText: true}}
+}
+
+func paragraph(text string) widget {
+	return widget{TextParagraph: &textParagraph{Text: text}}
+}
+
+func linkButton(text, url string) widget {
+	return widget{ButtonList: &buttonList{Buttons: []button{{Text: text, OnClick: onClick{OpenLink: openLink{URL: url}}}}}}
+}
+
+// releaseWidgets 版本的事件标签、版本号、发布时间等信息和链接按钮
+func releaseWidgets(release *github.ReleaseInfo, run render.RunContext, l labels) []widget {
+	var widgets []widget
+	if label := release.EventLabel(); label != "" {
+		widgets = append(widgets, paragraph("<b>"+html.EscapeString(label)+"</b>"))
+	}
+	if release.IsHighlighted() {
+		widgets = append(widgets, paragraph(`<font color="#d93025">`+html.EscapeString(release.HighlightBanner())+"</font>"))
+	}
+	if release.Contributed {
+		widgets = append(widgets, paragraph(`<font color="#188038">`+html.EscapeString(l.Contributor)+"</font>"))
+	}
+
+	widgets = append(widgets,
+		field(l.Version, release.TagName),
+		field(l.Published, run.FormatTime(release.PublishedAt)),
+	)
+	if release.SignatureChecked {
+		widgets = append(widgets, field(l.Signed, release.SignatureStatus()))
+	}
+	if release.PinnedVersion != "" {
+		widgets = append(widgets, field(l.Pinned, release.PinnedStatus()))
+	}
+	if len(release.MatchedAssets) > 0 {
+		widgets = append(widgets, field(l.Assets, strings.Join(release.MatchedAssets, ", ")))
+	}
+	return append(widgets, linkButton(l.ViewRelease, release.Link()))
+}
+
+// footerSection 批次信息，没有批次信息时返回nil
+func footerSection(run render.RunContext) []section {
+	footer := run.Footer()
+	if footer == "" {
+		return nil
+	}
+	return []section{{Widgets: []widget{paragraph(`<font color="#80868b">` + html.EscapeString(footer) + "</font>")}}}
+}
+
+// releaseMessage 单个版本的卡片
+func releaseMessage(release *github.ReleaseInfo, run render.RunContext) message {
+	l := labelsFor(run)
+	repo := fmt.Sprintf("%s/%s", release.Owner, release.Repository)
+
+	widgets := releaseWidgets(release, run, l)
+	if release.Description != "" {
+		// 发布说明放在按钮之前
+		desc := paragraph(html.EscapeString(truncate(release.Description, maxDescription)))
+		widgets = append(widgets[:len(widgets)-1], desc, widgets[len(widgets)-1])
+	}
+
+	return message{CardsV2: []cardWithID{{
+		CardID: "release",
+		Card: card{
+			Header:   &cardHeader{Title: fmt.Sprintf(l.NewRelease, release.Owner, release.Repository), Subtitle: release.TagName},
+			Sections: append([]section{{Header: html.EscapeString(repo), Widgets: widgets}}, footerSection(run)...),
+		},
+	}}}
+}
+
+// batchMessage 多个版本的卡片，每个版本一个分区
+func batchMessage(releases []*github.ReleaseInfo, run render.RunContext) message {
+	l := labelsFor(run)
+
+	var sections []section
+	for i, release := range releases {
+		if i == maxBatchItems {
+			sections = append(sections, section{Widgets: []widget{paragraph(fmt.Sprintf(l.More, len(releases)-maxBatchItems))}})
+			break
+		}
+		sections = append(sections, section{
+			Header:  html.EscapeString(fmt.Sprintf("%s/%s", release.Owner, release.Repository)),
+			Widgets: releaseWidgets(release, run, l),
+		})
+	}
+
+	return message{CardsV2: []cardWithID{{
+		CardID: "batch",
+		Card: card{
+			Header:   &cardHeader{Title: l.BatchTitle, Subtitle: fmt.Sprintf(l.BatchIntro, len(releases))},
+			Sections: append(sections, footerSection(run)...),
+		},
+	}}}
+}
+
+// digestMessage 超过每日上限后的摘要卡片，每个版本只占一行
+func digestMessage(releases []*github.ReleaseInfo, run render.RunContext) message {
+	l := labelsFor(run)
+
+	var lines []string
+	for i, release := range releases {
+		if i == maxDigestItems {
+			lines = append(lines, fmt.Sprintf(l.More, len(releases)-maxDigestItems))
+			break
+		}
+		lines = append(lines, fmt.Sprintf(`<a href="%s">%s/%s</a> %s`, html.EscapeString(release.Link()),
+			html.EscapeString(release.Owner), html.EscapeString(release.Repository), html.EscapeString(release.TagName)))
+	}
+
+	return message{CardsV2: []cardWithID{{
+		CardID: "digest",
+		Card: card{
+			Header:   &cardHeader{Title: fmt.Sprintf(l.DigestTitle, len(releases)), Subtitle: l.DigestIntro},
+			Sections: append([]section{{Widgets: []widget{paragraph(strings.Join(lines, "<br>"))}}}, footerSection(run)...),
+		},
+	}}}
+}
@@ -0,0 +1,235 @@
+package googlechat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// defaultCooldown 429响应没有给出 Retry-After 时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// DefaultPace 默认发送速率
+// Google Chat 每个聊天室每分钟最多60条消息（每秒1条），不允许突发
+var DefaultPace = pacing.Limit{Interval: time.Second, Burst: 1}
+
+// Config Google Chat 聊天室webhook配置
+type Config struct {
+	Enabled bool
+	// WebhookURL 聊天室"应用和集成"中创建的webhook地址，包含 key 和 token 参数
+	WebhookURL string
+	// ThreadByRepo 按仓库归入会话（threadKey），同一仓库的更新始终回复在同一个会话中
+	// 开启后批量消息按仓库拆分发送
+	ThreadByRepo bool
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier Google Chat 通知器
+type Notifier struct {
+	config  Config
+	client  *http.Client
+	limiter *pacing.Bucket // 速率限制器
+	mu      sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// New 创建Google Chat通知器
+// 消息使用固定的卡片格式展示仓库、版本、发布时间和链接按钮，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("Google Chat webhook URL不能为空")
+	}
+	if _, err := url.Parse(config.WebhookURL); err != nil {
+		return nil, fmt.Errorf("无效的Google Chat webhook URL: %v", err)
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("googlechat", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:  config,
+		client:  client,
+		limiter: limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "googlechat"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.post(releaseMessage(release, run), threadKey(release))
+}
+
+// SendBatch 批量发送（合并成一张卡片），按仓库归入会话时每个仓库单独发送到自己的会话
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	if !n.config.ThreadByRepo {
+		return n.post(batchMessage(releases, run), "")
+	}
+
+	var errs []string
+	for _, group := range groupByRepo(releases) {
+		var err error
+		if len(group) == 1 {
+			err = n.post(releaseMessage(group[0], run), threadKey(group[0]))
+		} else {
+			err = n.post(batchMessage(group, run), threadKey(group[0]))
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s/%s: %v", group[0].Owner, group[0].Repository, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d 个仓库发送失败: %s", len(errs), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// SendDigest 将超过每日上限的版本合并为一张摘要卡片发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.post(digestMessage(releases, run), "")
+}
+
+// threadKey 版本所属仓库的会话标识
+func threadKey(release *github.ReleaseInfo) string {
+	source := release.Source
+	if source == "" {
+		source = github.SourceGitHub
+	}
+	return strings.ToLower(fmt.Sprintf("%s/%s/%s", source, release.Owner, release.Repository))
+}
+
+// groupByRepo 按仓库分组，保持首次出现的顺序
+func groupByRepo(releases []*github.ReleaseInfo) [][]*github.ReleaseInfo {
+	index := make(map[string]int)
+	var groups [][]*github.ReleaseInfo
+	for _, release := range releases {
+		key := threadKey(release)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], release)
+	}
+	return groups
+}
+
+// messageURL 发送消息的地址，指定会话时加上 threadKey，会话不存在时自动创建
+func (n *Notifier) messageURL(thread string) string {
+	if !n.config.ThreadByRepo || thread == "" {
+		return n.config.WebhookURL
+	}
+
+	u, _ := url.Parse(n.config.WebhookURL)
+	query := u.Query()
+	query.Set("threadKey", thread)
+	query.Set("messageReplyOption", "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// post 发送消息到Google Chat webhook
+func (n *Notifier) post(msg message, thread string) error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+	if remaining > 0 {
+		return fmt.Errorf("Google Chat消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	resp, err := n.client.Post(n.messageURL(thread), "application/json; charset=UTF-8", bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("触发Google Chat限流，已设置%v冷却期: rate limit exceeded", wait)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		// 错误响应格式: {"error": {"code": 400, "message": "...", "status": "INVALID_ARGUMENT"}}
+		var response struct {
+			Error struct {
+				Message string `json:"message"`
+				Status  string `json:"status"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(body, &response) == nil && response.Error.Message != "" {
+			return fmt.Errorf("Google Chat API错误: %s (%s)", response.Error.Message, response.Error.Status)
+		}
+		return fmt.Errorf("Google Chat请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package googlechat
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSendBatch_ThreadByRepo 测试按仓库归入会话时批量消息按仓库拆分，并带上 threadKey
+func TestSendBatch_ThreadByRepo(t *testing.T) {
+	var mu sync.Mutex
+	threads := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil || len(msg.CardsV2) != 1 {
+			t.Errorf("无效的消息: %s", body)
+		}
+		if r.URL.Query().Get("key") != "k" || r.URL.Query().Get("messageReplyOption") != "REPLY_MESSAGE_FALLBACK_TO_NEW_THREAD" {
+			t.Errorf("请求参数不正确: %s", r.URL.RawQuery)
+		}
+		mu.Lock()
+		threads[r.URL.Query().Get("threadKey")]++
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled:      true,
+		WebhookURL:   srv.URL + "/v1/spaces/x/messages?key=k&token=t",
+		ThreadByRepo: true,
+		Bucket:       pacing.NewBucket("googlechat", pacing.Limit{Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Owner: "o", Repository: "A", TagName: "v1.0.0", HTMLURL: "https://github.com/o/A/releases/tag/v1.0.0"},
+		{Owner: "o", Repository: "b", TagName: "v2.0.0", HTMLURL: "https://github.com/o/b/releases/tag/v2.0.0"},
+		{Owner: "o", Repository: "a", TagName: "v1.1.0", HTMLURL: "https://github.com/o/a/releases/tag/v1.1.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 3}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if len(threads) != 2 || threads["github/o/a"] != 1 || threads["github/o/b"] != 1 {
+		t.Errorf("会话分组不正确: %v", threads)
+	}
+}
@@ -0,0 +1,100 @@
+package irc
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxLineBytes IRC协议规定一行消息最长512字节（包括结尾的CRLF）
+const maxLineBytes = 510
+
+// prefixReserve 服务器转发消息时会加上 ":nick!user@host " 前缀，为用户名和主机名预留的长度
+const prefixReserve = 100
+
+// maxDigestItems 摘要中最多列出的版本数
+const maxDigestItems = 50
+
+// bold IRC 粗体控制字符
+const bold = "\x02"
+
+// lineBudget 计算一行消息文本的最大字节数
+func lineBudget(nick, command, channel string) int {
+	// ":" + nick + prefix + " " + command + " " + channel + " :"
+	return maxLineBytes - prefixReserve - len(nick) - len(command) - len(channel) - 5
+}
+
+// releaseLine 构建单个版本的一行公告，如 "📦 owner/repo 发布新版本 v1.2.0 · https://..."
+func releaseLine(release *github.ReleaseInfo, run render.RunContext) string {
+	var b strings.Builder
+	if run.Locale == render.LocaleEN {
+		fmt.Fprintf(&b, "📦 %s%s/%s%s released %s", bold, release.Owner, release.Repository, bold, release.TagName)
+	} else {
+		fmt.Fprintf(&b, "📦 %s%s/%s%s 发布新版本 %s", bold, release.Owner, release.Repository, bold, release.TagName)
+	}
+	if release.Name != "" && release.Name != release.TagName {
+		fmt.Fprintf(&b, " (%s)", release.Name)
+	}
+	if label := release.EventLabel(); label != "" {
+		fmt.Fprintf(&b, " [%s]", label)
+	}
+	if banner := release.HighlightBanner(); banner != "" {
+		fmt.Fprintf(&b, " %s", banner)
+	}
+	fmt.Fprintf(&b, " · %s", release.Link())
+	return oneLine(b.String())
+}
+
+// digestLines 构建超过每日上限后的摘要，版本依次排列，由发送时按长度拆分为多行
+func digestLines(releases []*github.ReleaseInfo, run render.RunContext) []string {
+	header := fmt.Sprintf("📦 今天还有 %d 个新版本（今天的消息数已达到上限，以下版本合并发送）", len(releases))
+	if run.Locale == render.LocaleEN {
+		header = fmt.Sprintf("📦 %d more releases today (the daily message limit has been reached)", len(releases))
+	}
+
+	items := make([]string, 0, len(releases))
+	for i, release := range releases {
+		if i == maxDigestItems {
+			if run.Locale == render.LocaleEN {
+				items = append(items, fmt.Sprintf("...and %d more", len(releases)-maxDigestItems))
+			} else {
+				items = append(items, fmt.Sprintf("...以及其他 %d 个版本", len(releases)-maxDigestItems))
+			}
+			break
+		}
+		items = append(items, fmt.Sprintf("%s/%s %s", release.Owner, release.Repository, release.TagName))
+	}
+	return []string{header, oneLine(strings.Join(items, " · "))}
+}
+
+// oneLine 将换行替换为空格，IRC消息不能包含换行
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(strings.NewReplacer("\r", " ", "\n", " ").Replace(s)), " ")
+}
+
+// splitLine 按字节数拆分过长的行，尽量在空格处断开，且不会拆开UTF-8字符
+func splitLine(s string, max int) []string {
+	if max < 16 {
+		max = 16
+	}
+
+	var lines []string
+	for len(s) > max {
+		cut := max
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if i := strings.LastIndexByte(s[:cut], ' '); i > max/2 {
+			cut = i
+		}
+		lines = append(lines, strings.TrimRight(s[:cut], " "))
+		s = strings.TrimLeft(s[cut:], " ")
+	}
+	if s != "" {
+		lines = append(lines, s)
+	}
+	return lines
+}
@@ -0,0 +1,409 @@
+package irc
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultNick 默认昵称
+const DefaultNick = "notify-bot"
+
+// timeout 连接、注册和加入频道的超时
+const timeout = 30 * time.Second
+
+// identifyWait NickServ 认证后等待确认的时间，超时后照常加入频道
+const identifyWait = 5 * time.Second
+
+// DefaultPace 默认发送速率，IRC按行消耗令牌
+// 大多数IRC服务器在短时间内收到过多消息时会断开连接（Excess Flood），这里设置为每2秒1行，突发允许4行
+var DefaultPace = pacing.Limit{Interval: 2 * time.Second, Burst: 4}
+
+// Config IRC通知配置
+type Config struct {
+	Enabled bool
+	// Server 服务器地址 host:port，如 irc.libera.chat:6697，未指定端口时TLS使用6697，否则使用6667
+	Server string
+	// TLS 是否使用TLS加密连接
+	TLS bool
+	// InsecureSkipVerify 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool
+	// Nick 昵称，为空时使用 notify-bot，昵称被占用时自动追加下划线
+	Nick string
+	// Password 服务器密码（PASS），可选
+	Password string
+	// NickServPassword NickServ 认证密码，为空时不认证
+	NickServPassword string
+	// Channel 发送到的频道，如 #releases
+	Channel string
+	// ChannelKey 频道密码（+k），可选
+	ChannelKey string
+	// Notice 使用NOTICE而不是PRIVMSG发送，避免其他机器人响应
+	Notice bool
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+}
+
+// Notifier IRC通知器，每个版本发送一行公告，过长的行自动拆分
+type Notifier struct {
+	config    Config
+	address   string
+	tlsConfig *tls.Config // 为nil时使用明文连接
+	dialer    *net.Dialer
+	limiter   *pacing.Bucket // 速率限制器，防止触发服务器的刷屏保护
+}
+
+// New 创建IRC通知器
+// IRC消息使用固定的单行格式，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.Server == "" {
+		return nil, fmt.Errorf("IRC服务器地址不能为空")
+	}
+	if config.Channel == "" {
+		return nil, fmt.Errorf("IRC频道不能为空")
+	}
+	if !strings.ContainsAny(config.Channel[:1], "#&+!") {
+		config.Channel = "#" + config.Channel
+	}
+	if strings.ContainsAny(config.Channel, " ,\x07") {
+		return nil, fmt.Errorf("无效的IRC频道: %s", config.Channel)
+	}
+	if config.Nick == "" {
+		config.Nick = DefaultNick
+	}
+
+	host, port, err := net.SplitHostPort(config.Server)
+	if err != nil {
+		// 未指定端口
+		host = config.Server
+		port = "6667"
+		if config.TLS {
+			port = "6697"
+		}
+	}
+	if host == "" {
+		return nil, fmt.Errorf("无效的IRC服务器地址: %s", config.Server)
+	}
+
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("irc", DefaultPace)
+	}
+
+	n := &Notifier{
+		config:  config,
+		address: net.JoinHostPort(host, port),
+		dialer:  &net.Dialer{Timeout: timeout},
+		limiter: limiter,
+	}
+	if config.TLS {
+		n.tlsConfig = &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: config.InsecureSkipVerify,
+			MinVersion:         tls.VersionTLS12,
+		}
+	}
+
+	if config.LocalAddr != "" {
+		ip, err := util.ResolveLocalAddr(config.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		n.dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+
+	return n, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "irc"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.deliver([]string{releaseLine(release, run)})
+}
+
+// SendBatch 每个版本发送一行
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(releases))
+	for _, release := range releases {
+		lines = append(lines, releaseLine(release, run))
+	}
+	return n.deliver(lines)
+}
+
+// SendDigest 将超过每日上限的版本合并为几行摘要发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+	return n.deliver(digestLines(releases, run))
+}
+
+// deliver 连接服务器，加入频道发送消息后退出
+// 通知发送的频率很低，每次发送使用短连接，不需要维护长连接和重连
+func (n *Notifier) deliver(texts []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := n.dialer.DialContext(ctx, "tcp", n.address)
+	if err != nil {
+		return fmt.Errorf("连接IRC服务器失败: %v", err)
+	}
+	defer conn.Close()
+
+	if n.tlsConfig != nil {
+		tlsConn := tls.Client(conn, n.tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return fmt.Errorf("IRC TLS握手失败: %v", err)
+		}
+		conn = tlsConn
+	}
+
+	s := &session{conn: conn, r: bufio.NewReader(conn)}
+	nick, err := n.register(s)
+	if err != nil {
+		return fmt.Errorf("IRC注册失败: %v", err)
+	}
+	if n.config.NickServPassword != "" {
+		n.identify(s)
+	}
+	if err := n.join(s); err != nil {
+		return fmt.Errorf("加入IRC频道 %s 失败: %v", n.config.Channel, err)
+	}
+
+	command := "PRIVMSG"
+	if n.config.Notice {
+		command = "NOTICE"
+	}
+	budget := lineBudget(nick, command, n.config.Channel)
+	for _, text := range texts {
+		for _, line := range splitLine(text, budget) {
+			// 每行等待一个令牌，避免触发服务器的刷屏保护被断开连接
+			if err := n.limiter.Wait(context.Background()); err != nil {
+				return fmt.Errorf("速率限制等待错误: %v", err)
+			}
+			if err := s.send("%s %s :%s", command, n.config.Channel, line); err != nil {
+				return fmt.Errorf("发送IRC消息失败: %v", err)
+			}
+		}
+	}
+
+	// 退出失败不影响已发送的消息
+	s.send("QUIT :bye")
+	return nil
+}
+
+// register 注册连接，返回服务器接受的昵称
+func (n *Notifier) register(s *session) (string, error) {
+	if n.config.Password != "" {
+		if err := s.send("PASS %s", n.config.Password); err != nil {
+			return "", err
+		}
+	}
+	nick := n.config.Nick
+	if err := s.send("NICK %s", nick); err != nil {
+		return "", err
+	}
+	if err := s.send("USER %s 0 * :Notify", n.config.Nick); err != nil {
+		return "", err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for attempts := 0; ; {
+		msg, err := s.read(deadline)
+		if err != nil {
+			return "", err
+		}
+		switch msg.Command {
+		case "001":
+			return nick, nil
+		case "433", "436":
+			// 昵称被占用时追加下划线重试
+			attempts++
+			if attempts > 3 {
+				return "", fmt.Errorf("昵称 %s 已被占用", n.config.Nick)
+			}
+			nick += "_"
+			if err := s.send("NICK %s", nick); err != nil {
+				return "", err
+			}
+		case "432":
+			return "", fmt.Errorf("无效的昵称: %s", nick)
+		case "464":
+			return "", fmt.Errorf("服务器密码错误")
+		case "465":
+			return "", fmt.Errorf("已被服务器禁止连接: %s", msg.Trailing())
+		}
+	}
+}
+
+// identify 向 NickServ 认证，等待认证成功的确认
+// 认证失败不影响发送（未要求注册用户的频道仍可发送），只打印警告
+func (n *Notifier) identify(s *session) {
+	if err := s.send("PRIVMSG NickServ :IDENTIFY %s %s", n.config.Nick, n.config.NickServPassword); err != nil {
+		return
+	}
+
+	deadline := time.Now().Add(identifyWait)
+	for {
+		msg, err := s.read(deadline)
+		if err != nil {
+			fmt.Printf("警告: 未收到 NickServ 认证确认，继续加入频道: %v\n", err)
+			return
+		}
+		// 900 RPL_LOGGEDIN，部分服务只回复 NickServ 的 NOTICE
+		if msg.Command == "900" || (msg.Command == "NOTICE" && strings.EqualFold(msg.Nick(), "NickServ")) {
+			return
+		}
+	}
+}
+
+// join 加入频道并等待服务器确认
+func (n *Notifier) join(s *session) error {
+	var err error
+	if n.config.ChannelKey != "" {
+		err = s.send("JOIN %s %s", n.config.Channel, n.config.ChannelKey)
+	} else {
+		err = s.send("JOIN %s", n.config.Channel)
+	}
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		msg, err := s.read(deadline)
+		if err != nil {
+			return err
+		}
+		switch msg.Command {
+		case "366":
+			// 名单结束，加入完成
+			return nil
+		case "403", "405", "471", "473", "474", "475", "477":
+			return fmt.Errorf("%s", msg.Trailing())
+		}
+	}
+}
+
+// session 一个IRC连接
+type session struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// send 发送一行命令
+func (s *session) send(format string, args ...interface{}) error {
+	s.conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err := fmt.Fprintf(s.conn, format+"\r\n", args...)
+	return err
+}
+
+// read 读取一条消息，自动响应 PING，服务器返回 ERROR 时返回错误
+func (s *session) read(deadline time.Time) (message, error) {
+	for {
+		s.conn.SetReadDeadline(deadline)
+		line, err := s.r.ReadString('\n')
+		if err != nil {
+			return message{}, fmt.Errorf("读取服务器响应失败: %v", err)
+		}
+		msg := parseMessage(strings.TrimRight(line, "\r\n"))
+		switch msg.Command {
+		case "PING":
+			if err := s.send("PONG :%s", msg.Trailing()); err != nil {
+				return message{}, err
+			}
+		case "ERROR":
+			return message{}, fmt.Errorf("服务器断开连接: %s", msg.Trailing())
+		case "":
+		default:
+			return msg, nil
+		}
+	}
+}
+
+// message 解析后的IRC消息
+type message struct {
+	Prefix  string
+	Command string
+	Params  []string
+}
+
+// Nick 消息来源的昵称
+func (m message) Nick() string {
+	if i := strings.IndexByte(m.Prefix, '!'); i >= 0 {
+		return m.Prefix[:i]
+	}
+	return m.Prefix
+}
+
+// Trailing 最后一个参数，通常是消息文本
+func (m message) Trailing() string {
+	if len(m.Params) == 0 {
+		return ""
+	}
+	return m.Params[len(m.Params)-1]
+}
+
+// parseMessage 解析一行IRC消息，如 ":server 001 nick :Welcome"
+func parseMessage(line string) message {
+	var msg message
+	if strings.HasPrefix(line, "@") {
+		// 忽略IRCv3消息标签
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			line = line[i+1:]
+		} else {
+			return msg
+		}
+	}
+	if strings.HasPrefix(line, ":") {
+		i := strings.IndexByte(line, ' ')
+		if i < 0 {
+			return msg
+		}
+		msg.Prefix = line[1:i]
+		line = line[i+1:]
+	}
+	for line != "" {
+		if strings.HasPrefix(line, ":") {
+			msg.Params = append(msg.Params, line[1:])
+			break
+		}
+		var field string
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			field, line = line[:i], strings.TrimLeft(line[i+1:], " ")
+		} else {
+			field, line = line, ""
+		}
+		if msg.Command == "" {
+			msg.Command = strings.ToUpper(field)
+		} else {
+			msg.Params = append(msg.Params, field)
+		}
+	}
+	return msg
+}
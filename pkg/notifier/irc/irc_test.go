@@ -0,0 +1,157 @@
+package irc
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// fakeServer 启动一个只处理一个连接的IRC服务器，第一次 NICK 返回昵称被占用，记录收到的全部命令
+func fakeServer(t *testing.T) (string, <-chan []string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+		var lines []string
+		nicks := 0
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+
+			msg := parseMessage(line)
+			switch msg.Command {
+			case "NICK":
+				nicks++
+				if nicks == 1 {
+					fmt.Fprintf(conn, ":irc.test 433 * %s :Nickname is already in use\r\n", msg.Params[0])
+				} else {
+					fmt.Fprintf(conn, "PING :irc.test\r\n:irc.test 001 %s :Welcome\r\n", msg.Params[0])
+				}
+			case "PRIVMSG":
+				if msg.Params[0] == "NickServ" {
+					fmt.Fprintf(conn, ":NickServ!NickServ@services. NOTICE notify :You are now identified\r\n")
+				}
+			case "JOIN":
+				fmt.Fprintf(conn, ":irc.test 353 notify = %s :notify\r\n:irc.test 366 notify %s :End of /NAMES list.\r\n", msg.Params[0], msg.Params[0])
+			}
+			if msg.Command == "QUIT" {
+				break
+			}
+		}
+		received <- lines
+	}()
+
+	return ln.Addr().String(), received
+}
+
+// TestSendBatch 测试注册、昵称冲突、NickServ认证、加入频道和发送
+func TestSendBatch(t *testing.T) {
+	addr, received := fakeServer(t)
+
+	n, err := New(Config{
+		Enabled:          true,
+		Server:           addr,
+		Nick:             "notify",
+		NickServPassword: "secret",
+		Channel:          "releases",
+		Bucket:           pacing.NewBucket("irc", pacing.Limit{Interval: time.Millisecond, Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Owner: "foo", Repository: "bar", TagName: "v1.0.0", HTMLURL: "https://github.com/foo/bar/releases/tag/v1.0.0"},
+		{Owner: "foo", Repository: "baz", TagName: "v2.0.0", HTMLURL: "https://github.com/foo/baz/releases/tag/v2.0.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	lines := <-received
+	joined := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"NICK notify_",
+		"PONG :irc.test",
+		"PRIVMSG NickServ :IDENTIFY notify secret",
+		"JOIN #releases",
+		"QUIT",
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("服务器应收到 %q，实际收到:\n%s", want, joined)
+		}
+	}
+
+	var messages []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, "PRIVMSG #releases :") {
+			messages = append(messages, line)
+		}
+	}
+	if len(messages) != 2 {
+		t.Fatalf("应发送2行消息，实际 %d 行", len(messages))
+	}
+	if !strings.Contains(messages[1], "foo/baz") || !strings.Contains(messages[1], "v2.0.0") {
+		t.Errorf("消息内容不正确: %s", messages[1])
+	}
+}
+
+// TestSplitLine 测试长行拆分
+func TestSplitLine(t *testing.T) {
+	text := strings.Repeat("版本发布 ", 100)
+	lines := splitLine(oneLine(text), 100)
+	if len(lines) < 2 {
+		t.Fatalf("长行应拆分为多行，实际 %d 行", len(lines))
+	}
+	for _, line := range lines {
+		if len(line) > 100 {
+			t.Errorf("拆分后的行超过100字节: %d", len(line))
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("拆分后的行包含不完整的UTF-8字符: %q", line)
+		}
+	}
+
+	if got := splitLine("short", 100); len(got) != 1 || got[0] != "short" {
+		t.Errorf("短行不应拆分: %v", got)
+	}
+}
+
+// TestParseMessage 测试IRC消息解析
+func TestParseMessage(t *testing.T) {
+	msg := parseMessage("@time=2024-01-01T00:00:00Z :nick!user@host PRIVMSG #chan :hello world")
+	if msg.Command != "PRIVMSG" || msg.Nick() != "nick" || len(msg.Params) != 2 || msg.Trailing() != "hello world" {
+		t.Errorf("解析结果不正确: %+v", msg)
+	}
+
+	msg = parseMessage("PING :server")
+	if msg.Command != "PING" || msg.Trailing() != "server" {
+		t.Errorf("解析结果不正确: %+v", msg)
+	}
+}
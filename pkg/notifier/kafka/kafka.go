@@ -0,0 +1,275 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultClientID 默认的客户端ID
+const DefaultClientID = "notify"
+
+// KeyHeader 携带版本稳定标识的消息头
+const KeyHeader = "notify-key"
+
+// defaultPort 服务器地址中没有端口时使用的端口
+const defaultPort = "9092"
+
+// timeout 连接超时，同时作为写入请求中 broker 等待副本确认的时长
+const timeout = 10 * time.Second
+
+// 支持的SASL机制
+const (
+	mechanismPlain    = "PLAIN"
+	mechanismSCRAM256 = "SCRAM-SHA-256"
+	mechanismSCRAM512 = "SCRAM-SHA-512"
+)
+
+// Config Kafka通知配置
+type Config struct {
+	Enabled bool
+	// Brokers 引导服务器地址，如 kafka-1:9092，依次尝试直到连接成功，未指定端口时使用9092
+	Brokers []string
+	// Topic 写入的主题
+	Topic string
+	// ClientID 客户端ID，为空时使用 DefaultClientID
+	ClientID string
+	// Acks 确认级别: all（默认，等待全部同步副本）、leader（只等待分区首领）
+	Acks string
+	// TLS 加密连接配置
+	TLS TLSConfig
+	// SASL 认证配置
+	SASL SASLConfig
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+}
+
+// TLSConfig Kafka加密连接配置
+type TLSConfig struct {
+	Enabled bool
+	// CAFile 自签名服务器证书的CA证书文件
+	CAFile string
+	// CertFile、KeyFile 客户端证书，用于双向认证
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool
+}
+
+// SASLConfig Kafka SASL认证配置
+type SASLConfig struct {
+	// Mechanism 认证机制: PLAIN、SCRAM-SHA-256、SCRAM-SHA-512，为空时不认证
+	Mechanism string
+	Username  string
+	Password  string
+}
+
+// Notifier Kafka通知器，将每个版本以JSON格式写入主题，消息键为 owner/repo
+// 同一仓库的消息写入同一分区，下游消费方可以按仓库顺序处理后自行分发通知；
+// 消息头 KeyHeader 携带版本的稳定标识（ReleaseInfo.Key），用于去重重发的消息
+type Notifier struct {
+	config Config
+	opts   []kgo.Opt
+}
+
+// New 创建Kafka通知器
+// Kafka写入结构化数据，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("Kafka服务器地址不能为空")
+	}
+	if config.Topic == "" {
+		return nil, fmt.Errorf("Kafka主题不能为空")
+	}
+	if config.ClientID == "" {
+		config.ClientID = DefaultClientID
+	}
+	brokers := make([]string, 0, len(config.Brokers))
+	for _, addr := range config.Brokers {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			addr = net.JoinHostPort(addr, defaultPort)
+		}
+		brokers = append(brokers, addr)
+	}
+	config.Brokers = brokers
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(config.Brokers...),
+		kgo.ClientID(config.ClientID),
+		kgo.DefaultProduceTopic(config.Topic),
+		kgo.ProduceRequestTimeout(timeout),
+		kgo.DialTimeout(timeout),
+		// 与Java客户端一致按消息键的 murmur2 哈希选择分区
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(nil)),
+	}
+	switch config.Acks {
+	case "", "all":
+		opts = append(opts, kgo.RequiredAcks(kgo.AllISRAcks()))
+	case "leader":
+		// 幂等写入要求等待全部同步副本
+		opts = append(opts, kgo.RequiredAcks(kgo.LeaderAck()), kgo.DisableIdempotentWrite())
+	default:
+		return nil, fmt.Errorf("不支持的Kafka确认级别: %s（可选 all、leader）", config.Acks)
+	}
+
+	var mechanism sasl.Mechanism
+	switch config.SASL.Mechanism {
+	case "":
+	case mechanismPlain:
+		mechanism = plain.Auth{User: config.SASL.Username, Pass: config.SASL.Password}.AsMechanism()
+	case mechanismSCRAM256:
+		mechanism = scram.Auth{User: config.SASL.Username, Pass: config.SASL.Password}.AsSha256Mechanism()
+	case mechanismSCRAM512:
+		mechanism = scram.Auth{User: config.SASL.Username, Pass: config.SASL.Password}.AsSha512Mechanism()
+	default:
+		return nil, fmt.Errorf("不支持的SASL机制: %s（可选 %s、%s、%s）", config.SASL.Mechanism, mechanismPlain, mechanismSCRAM256, mechanismSCRAM512)
+	}
+	if mechanism != nil {
+		if config.SASL.Username == "" {
+			return nil, fmt.Errorf("Kafka SASL认证需要用户名")
+		}
+		opts = append(opts, kgo.SASL(mechanism))
+	}
+
+	var tlsConfig *tls.Config
+	if config.TLS.Enabled {
+		var err error
+		tlsConfig, err = newTLSConfig(config.TLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if config.LocalAddr != "" {
+		ip, err := util.ResolveLocalAddr(config.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, kgo.Dialer(dialer(&net.TCPAddr{IP: ip}, tlsConfig)))
+	} else if tlsConfig != nil {
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+
+	return &Notifier{config: config, opts: opts}, nil
+}
+
+// newTLSConfig 构建TLS配置，服务器名称在连接每个 broker 时设置
+func newTLSConfig(config TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取Kafka CA证书失败: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("解析Kafka CA证书失败: %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载Kafka客户端证书失败: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "kafka"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 写入单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.produce([]*github.ReleaseInfo{release})
+}
+
+// SendBatch 每个版本写入一条消息，消费方按版本处理
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+	return n.produce(releases)
+}
+
+// produce 写入全部版本，等待 broker 确认后断开连接
+// 通知发送的频率很低，每次发送新建客户端，不需要维护长连接和元数据缓存
+func (n *Notifier) produce(releases []*github.ReleaseInfo) error {
+	records := make([]*kgo.Record, 0, len(releases))
+	for _, release := range releases {
+		value, err := json.Marshal(release)
+		if err != nil {
+			return fmt.Errorf("序列化版本信息失败: %v", err)
+		}
+		rec := &kgo.Record{Key: []byte(release.Owner + "/" + release.Repository), Value: value}
+		if release.Key != "" {
+			rec.Headers = []kgo.RecordHeader{{Key: KeyHeader, Value: []byte(release.Key)}}
+		}
+		records = append(records, rec)
+	}
+
+	client, err := kgo.NewClient(n.opts...)
+	if err != nil {
+		return fmt.Errorf("创建Kafka客户端失败: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*timeout)
+	defer cancel()
+	// 写入失败会在超时前一直重试，先确认能够连接并通过认证，尽早返回错误
+	if err := client.Ping(ctx); err != nil {
+		return fmt.Errorf("连接Kafka服务器失败: %v", err)
+	}
+	if err := client.ProduceSync(ctx, records...).FirstErr(); err != nil {
+		return fmt.Errorf("写入Kafka主题 %s 失败: %v", n.config.Topic, err)
+	}
+	return nil
+}
+
+// dialer 绑定本地地址建立连接，启用TLS时完成握手，服务器名称为 broker 的主机名
+func dialer(local net.Addr, tlsConfig *tls.Config) func(ctx context.Context, network, host string) (net.Conn, error) {
+	d := &net.Dialer{Timeout: timeout, LocalAddr: local}
+	return func(ctx context.Context, network, host string) (net.Conn, error) {
+		conn, err := d.DialContext(ctx, network, host)
+		if err != nil || tlsConfig == nil {
+			return conn, err
+		}
+		c := tlsConfig.Clone()
+		c.ServerName, _, _ = net.SplitHostPort(host)
+		tlsConn := tls.Client(conn, c)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return tlsConn, nil
+	}
+}
@@ -0,0 +1,151 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// fakeCluster 启动一个单节点的Kafka集群，主题有 partitions 个分区，启用SASL认证
+func fakeCluster(t *testing.T, topic string, partitions int32, mechanism string) string {
+	t.Helper()
+	c, err := kfake.NewCluster(
+		kfake.NumBrokers(1),
+		kfake.SeedTopics(partitions, topic),
+		kfake.EnableSASL(),
+		kfake.Superuser(mechanism, "notify", "secret"),
+	)
+	if err != nil {
+		t.Fatalf("启动Kafka集群失败: %v", err)
+	}
+	t.Cleanup(c.Close)
+	return c.ListenAddrs()[0]
+}
+
+// consume 读取主题中的 count 条消息
+func consume(t *testing.T, addr, topic, mechanism string, count int) []*kgo.Record {
+	t.Helper()
+	n, err := New(Config{Brokers: []string{addr}, Topic: topic, SASL: SASLConfig{Mechanism: mechanism, Username: "notify", Password: "secret"}}, nil)
+	if err != nil {
+		t.Fatalf("创建消费者配置失败: %v", err)
+	}
+	client, err := kgo.NewClient(append(n.opts, kgo.ConsumeTopics(topic), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))...)
+	if err != nil {
+		t.Fatalf("创建消费者失败: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	var records []*kgo.Record
+	for len(records) < count {
+		fetches := client.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("读取消息超时，已读取 %d 条", len(records))
+		}
+		records = append(records, fetches.Records()...)
+	}
+	return records
+}
+
+// TestSendBatch 测试SASL认证、按消息键选择分区并写入JSON
+func TestSendBatch(t *testing.T) {
+	for _, mechanism := range []string{mechanismPlain, mechanismSCRAM256, mechanismSCRAM512} {
+		t.Run(mechanism, func(t *testing.T) {
+			addr := fakeCluster(t, "releases", 3, mechanism)
+			n, err := New(Config{
+				Enabled: true,
+				Brokers: []string{addr},
+				Topic:   "releases",
+				SASL:    SASLConfig{Mechanism: mechanism, Username: "notify", Password: "secret"},
+			}, nil)
+			if err != nil {
+				t.Fatalf("创建通知器失败: %v", err)
+			}
+
+			releases := []*github.ReleaseInfo{
+				{Event: github.EventRelease, Owner: "o", Repository: "a", TagName: "v1.0.0", Key: "k1"},
+				{Event: github.EventRelease, Owner: "o", Repository: "b", TagName: "v2.0.0"},
+				{Event: github.EventRelease, Owner: "o", Repository: "a", TagName: "v1.1.0"},
+			}
+			if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 3}); err != nil {
+				t.Fatalf("发送失败: %v", err)
+			}
+
+			got := consume(t, addr, "releases", mechanism, 3)
+			if len(got) != 3 {
+				t.Fatalf("收到 %d 条消息，期望 3 条", len(got))
+			}
+			tags := make(map[string][]string)
+			partitions := make(map[string]int32)
+			for _, rec := range got {
+				key := string(rec.Key)
+				if p, ok := partitions[key]; ok && p != rec.Partition {
+					t.Errorf("%s 写入了分区 %d 和 %d", key, p, rec.Partition)
+				}
+				partitions[key] = rec.Partition
+
+				var release github.ReleaseInfo
+				if err := json.Unmarshal(rec.Value, &release); err != nil {
+					t.Fatalf("解析消息失败: %v", err)
+				}
+				if want := release.Owner + "/" + release.Repository; key != want {
+					t.Errorf("消息键为 %s，期望 %s", key, want)
+				}
+				tags[key] = append(tags[key], release.TagName)
+
+				var header string
+				for _, h := range rec.Headers {
+					if h.Key == KeyHeader {
+						header = string(h.Value)
+					}
+				}
+				if header != release.Key {
+					t.Errorf("%s %s 的消息头 %s 为 %q，期望 %q", key, release.TagName, KeyHeader, header, release.Key)
+				}
+			}
+			// 同一分区内保持版本顺序
+			if a := tags["o/a"]; len(a) != 2 || a[0] != "v1.0.0" || a[1] != "v1.1.0" {
+				t.Errorf("o/a 的消息顺序为 %v", a)
+			}
+		})
+	}
+}
+
+// TestSend_AuthFailed 测试认证失败时返回错误
+func TestSend_AuthFailed(t *testing.T) {
+	addr := fakeCluster(t, "releases", 1, mechanismPlain)
+	n, err := New(Config{
+		Brokers: []string{addr},
+		Topic:   "releases",
+		SASL:    SASLConfig{Mechanism: mechanismPlain, Username: "notify", Password: "wrong"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	if err := n.Send(&github.ReleaseInfo{Owner: "o", Repository: "a", TagName: "v1.0.0"}, render.RunContext{}); err == nil {
+		t.Error("密码错误时应返回错误")
+	}
+}
+
+// TestNew_InvalidConfig 测试无效配置
+func TestNew_InvalidConfig(t *testing.T) {
+	for name, config := range map[string]Config{
+		"缺少地址":     {Topic: "releases"},
+		"缺少主题":     {Brokers: []string{"localhost:9092"}},
+		"不支持的确认级别": {Brokers: []string{"localhost"}, Topic: "releases", Acks: "none"},
+		"不支持的SASL": {Brokers: []string{"localhost"}, Topic: "releases", SASL: SASLConfig{Mechanism: "GSSAPI", Username: "u"}},
+		"SASL缺少用户": {Brokers: []string{"localhost"}, Topic: "releases", SASL: SASLConfig{Mechanism: mechanismPlain}},
+	} {
+		if _, err := New(config, nil); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
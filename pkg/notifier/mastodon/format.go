@@ -0,0 +1,116 @@
+package mastodon
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// urlLength Mastodon 计算字数时每个链接固定计为23个字符
+const urlLength = 23
+
+var (
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdHeading = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+	mdEmpty   = regexp.MustCompile(`\n{3,}`)
+	anyURL    = regexp.MustCompile(`https?://\S+`)
+)
+
+// toPlainText 将模板渲染的markdown转换为纯文本，嘟文不支持markdown
+// 正文中的链接去掉，版本链接统一放在正文之后
+func toPlainText(s string) string {
+	s = mdLink.ReplaceAllString(s, "$1")
+	s = anyURL.ReplaceAllString(s, "")
+	s = mdHeading.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "**", "")
+	s = strings.ReplaceAll(s, "`", "")
+
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " :：")
+	}
+	return strings.TrimSpace(mdEmpty.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// parseHashtags 将渲染后的话题标签拆分并规范化，如 "#release #my-lib.js" 得到 #release、#my_lib_js
+// Mastodon 的话题标签只能包含字母、数字和下划线，重复的标签只保留一个
+func parseHashtags(s string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(s) {
+		tag := strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) || r == '_' {
+				return r
+			}
+			return '_'
+		}, strings.TrimLeft(field, "#"))
+		tag = strings.Trim(tag, "_")
+		if tag == "" || seen[strings.ToLower(tag)] {
+			continue
+		}
+		seen[strings.ToLower(tag)] = true
+		tags = append(tags, "#"+tag)
+	}
+	return tags
+}
+
+// composeStatus 组合嘟文：正文、版本链接和话题标签，正文过长时截断，保证链接和标签完整
+func composeStatus(body, link string, tags []string, maxChars int) string {
+	tail := ""
+	reserved := 0
+	if link != "" {
+		tail += "\n\n" + link
+		reserved += 2 + urlLength
+	}
+	if len(tags) > 0 {
+		joined := strings.Join(tags, " ")
+		tail += "\n\n" + joined
+		reserved += 2 + utf8.RuneCountInString(joined)
+	}
+
+	return truncate(body, maxChars-reserved) + tail
+}
+
+// truncate 按字符数截断文本
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if n <= 1 {
+		return string(runes[:n])
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
+
+// buildDigestStatus 构建摘要嘟文，只列出仓库和版本，超出字数的版本只列出数量
+func buildDigestStatus(releases []*github.ReleaseInfo, maxChars int) string {
+	var b strings.Builder
+	header := fmt.Sprintf("📦 今天还有 %d 个新版本：\n", len(releases))
+	b.WriteString(header)
+	length := utf8.RuneCountInString(header)
+
+	for i, release := range releases {
+		line := fmt.Sprintf("• %s/%s %s\n", release.Owner, release.Repository, release.TagName)
+		more := fmt.Sprintf("...以及其他 %d 个版本", len(releases)-i)
+		// 不是最后一个版本时，为"以及其他"预留位置
+		need := utf8.RuneCountInString(line)
+		if i < len(releases)-1 {
+			need += utf8.RuneCountInString(more)
+		}
+		if length+need > maxChars {
+			b.WriteString(more)
+			break
+		}
+		b.WriteString(line)
+		length += utf8.RuneCountInString(line)
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,275 @@
+package mastodon
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultVisibility 默认的嘟文可见性，机器人账号使用"不公开"避免刷屏公共时间线
+const DefaultVisibility = "unlisted"
+
+// DefaultMaxChars 实例允许的嘟文最大字符数，Mastodon 默认为500
+const DefaultMaxChars = 500
+
+// defaultCooldown 限流响应没有给出重置时间时的冷却期
+const defaultCooldown = 5 * time.Minute
+
+// DefaultPace 默认发送速率
+// Mastodon 默认每个账号每3小时最多发布300条嘟文，这里设置为每30秒1条，突发允许5条
+var DefaultPace = pacing.Limit{Interval: 30 * time.Second, Burst: 5}
+
+// visibilities 支持的可见性
+var visibilities = map[string]bool{"public": true, "unlisted": true, "private": true, "direct": true}
+
+// Config Mastodon 发布配置
+type Config struct {
+	Enabled bool
+	// InstanceURL 实例地址，如 https://mastodon.social
+	InstanceURL string
+	// AccessToken 应用的访问令牌，需要 write:statuses 权限
+	AccessToken string
+	// Visibility 可见性: public、unlisted、private、direct，为空时使用 DefaultVisibility
+	Visibility string
+	// Hashtags 话题标签模板，如 "#release #{{.Repository}}"，标签中的非法字符会替换为下划线
+	Hashtags string
+	// Language 嘟文语言（ISO 639-1，如 zh、en），可选
+	Language string
+	// MaxChars 嘟文最大字符数，为0时使用 DefaultMaxChars
+	MaxChars int
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier Mastodon 通知器，每个版本发布一条嘟文
+type Notifier struct {
+	config   Config
+	template *template.Template
+	hashtags *template.Template // 为nil时不添加话题标签
+	client   *http.Client
+	limiter  *pacing.Bucket // 速率限制器
+	mu       sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// New 创建Mastodon通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	config.InstanceURL = strings.TrimRight(config.InstanceURL, "/")
+	if config.InstanceURL == "" {
+		return nil, fmt.Errorf("Mastodon实例地址不能为空")
+	}
+	if config.AccessToken == "" {
+		return nil, fmt.Errorf("Mastodon访问令牌不能为空")
+	}
+	if config.Visibility == "" {
+		config.Visibility = DefaultVisibility
+	}
+	if !visibilities[config.Visibility] {
+		return nil, fmt.Errorf("无效的Mastodon可见性: %s（应为 public、unlisted、private 或 direct）", config.Visibility)
+	}
+	if config.MaxChars <= 0 {
+		config.MaxChars = DefaultMaxChars
+	}
+
+	var hashtags *template.Template
+	if strings.TrimSpace(config.Hashtags) != "" {
+		var err error
+		hashtags, err = template.New("hashtags").Parse(config.Hashtags)
+		if err != nil {
+			return nil, fmt.Errorf("解析Mastodon话题标签模板失败: %v", err)
+		}
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("mastodon", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   15 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		hashtags: hashtags,
+		client:   client,
+		limiter:  limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "mastodon"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 为单个版本发布一条嘟文
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	tags, err := n.renderHashtags(release)
+	if err != nil {
+		return err
+	}
+
+	return n.post(status{
+		Status:     composeStatus(toPlainText(content), release.Link(), tags, n.config.MaxChars),
+		Visibility: n.config.Visibility,
+		Language:   n.config.Language,
+	}, idempotencyKey(release))
+}
+
+// SendBatch 每个版本单独发布一条嘟文，关注者的时间线中每条嘟文对应一个版本
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	var failed int
+	var firstErr error
+	for _, release := range releases {
+		if err := n.Send(release, run); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d 条Mastodon嘟文发布失败: %v", failed, len(releases), firstErr)
+	}
+	return nil
+}
+
+// SendDigest 将超过每日上限的版本合并为一条摘要嘟文
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.post(status{
+		Status:     buildDigestStatus(releases, n.config.MaxChars),
+		Visibility: n.config.Visibility,
+		Language:   n.config.Language,
+	}, "")
+}
+
+// renderHashtags 渲染话题标签模板
+func (n *Notifier) renderHashtags(release *github.ReleaseInfo) ([]string, error) {
+	if n.hashtags == nil {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	if err := n.hashtags.Execute(&buf, release); err != nil {
+		return nil, fmt.Errorf("渲染Mastodon话题标签失败: %v", err)
+	}
+	return parseHashtags(buf.String()), nil
+}
+
+// status 发布嘟文的请求
+type status struct {
+	Status     string `json:"status"`
+	Visibility string `json:"visibility"`
+	Language   string `json:"language,omitempty"`
+}
+
+// idempotencyKey 同一版本的同一事件使用相同的幂等键，重发失败队列时实例不会重复发布
+func idempotencyKey(release *github.ReleaseInfo) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s/%s@%s#%s", release.Owner, release.Repository, release.TagName, release.Event)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// post 发布嘟文并检查返回结果
+func (n *Notifier) post(s status, key string) error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+	if remaining > 0 {
+		return fmt.Errorf("Mastodon触发限流，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	body, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.config.InstanceURL+"/api/v1/statuses", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.config.AccessToken)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发布Mastodon嘟文失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 限流时 X-RateLimit-Reset 给出配额重置的时间（ISO 8601）
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if reset, err := time.Parse(time.RFC3339, resp.Header.Get("X-RateLimit-Reset")); err == nil {
+			if until := reset.Sub(n.config.Clock.Now()); until > 0 {
+				wait = until
+			}
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("Mastodon触发限流，已设置%v冷却期: rate limit exceeded", wait.Round(time.Second))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		// 错误时返回 {"error":"..."}
+		var response struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(respBody, &response) == nil && response.Error != "" {
+			return fmt.Errorf("Mastodon API返回错误，状态码: %d (%s)", resp.StatusCode, response.Error)
+		}
+		return fmt.Errorf("Mastodon请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSend 测试嘟文内容、可见性、话题标签和幂等键
+func TestSend(t *testing.T) {
+	var got status
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/statuses" || r.Header.Get("Authorization") != "Bearer token" {
+			t.Errorf("请求不正确: %s %v", r.URL.Path, r.Header)
+		}
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("无效的请求: %v", err)
+		}
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	tmpl := template.Must(template.New("t").Parse("## 📦 {{.Owner}}/{{.Repository}} 新版本\n**版本**: {{.TagName}}\n**[查看详情]({{.Link}})**"))
+	n, err := New(Config{
+		Enabled:     true,
+		InstanceURL: srv.URL + "/",
+		AccessToken: "token",
+		Hashtags:    "#release #{{.Repository}} #release",
+		Bucket:      pacing.NewBucket("mastodon", pacing.Limit{Burst: 10}),
+	}, tmpl)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "foo", Repository: "my-lib.js", TagName: "v1.0.0", HTMLURL: "https://github.com/foo/my-lib.js/releases/tag/v1.0.0"}
+	for i := 0; i < 2; i++ {
+		if err := n.Send(release, render.RunContext{}); err != nil {
+			t.Fatalf("发送失败: %v", err)
+		}
+	}
+
+	want := "📦 foo/my-lib.js 新版本\n版本: v1.0.0\n查看详情\n\nhttps://github.com/foo/my-lib.js/releases/tag/v1.0.0\n\n#release #my_lib_js"
+	if got.Status != want {
+		t.Errorf("嘟文内容为:\n%s\n期望:\n%s", got.Status, want)
+	}
+	if got.Visibility != "unlisted" {
+		t.Errorf("默认可见性应为 unlisted，实际为 %s", got.Visibility)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("同一版本应使用相同的幂等键: %v", keys)
+	}
+}
+
+// TestComposeStatus 测试正文过长时截断，链接和话题标签保持完整
+func TestComposeStatus(t *testing.T) {
+	link := "https://github.com/foo/bar/releases/tag/v1.0.0"
+	s := composeStatus(strings.Repeat("更新说明", 200), link, []string{"#release"}, 500)
+	if !strings.HasSuffix(s, "…\n\n"+link+"\n\n#release") {
+		t.Errorf("截断后应保留链接和标签: %s", s)
+	}
+	// 链接按23个字符计算
+	counted := utf8.RuneCountInString(strings.Replace(s, link, strings.Repeat("x", urlLength), 1))
+	if counted > 500 {
+		t.Errorf("嘟文超过500字: %d", counted)
+	}
+}
+
+// TestSend_RateLimited 测试限流后进入冷却期
+func TestSend_RateLimited(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-RateLimit-Reset", time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{Enabled: true, InstanceURL: srv.URL, AccessToken: "token", Bucket: pacing.NewBucket("mastodon", pacing.Limit{Burst: 10})},
+		template.Must(template.New("t").Parse("{{.TagName}}")))
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "foo", Repository: "bar", TagName: "v1.0.0"}
+	if err := n.SendBatch([]*github.ReleaseInfo{release, release}, render.RunContext{}); err == nil {
+		t.Fatal("限流时应返回错误")
+	}
+	if calls != 1 {
+		t.Errorf("冷却期内不应继续请求，实际请求 %d 次", calls)
+	}
+}
@@ -0,0 +1,128 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// DefaultDiscoveryPrefix Home Assistant 默认的MQTT自动发现前缀
+const DefaultDiscoveryPrefix = "homeassistant"
+
+// HomeAssistantConfig Home Assistant MQTT自动发现配置
+type HomeAssistantConfig struct {
+	Enabled bool
+	// DiscoveryPrefix 自动发现前缀，与 Home Assistant 中MQTT集成的设置一致，默认为 homeassistant
+	DiscoveryPrefix string
+	// Repos 作为传感器出现的仓库，owner/repo 形式，支持 * 通配，为空时包括全部仓库
+	Repos []string
+}
+
+// message 一条待发布的消息
+type message struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// haDiscovery 传感器的自动发现配置（https://www.home-assistant.io/integrations/sensor.mqtt/）
+type haDiscovery struct {
+	Name                string   `json:"name"`
+	UniqueID            string   `json:"unique_id"`
+	ObjectID            string   `json:"object_id"`
+	StateTopic          string   `json:"state_topic"`
+	ValueTemplate       string   `json:"value_template"`
+	JSONAttributesTopic string   `json:"json_attributes_topic"`
+	Icon                string   `json:"icon"`
+	Device              haDevice `json:"device"`
+}
+
+// haDevice 所有传感器归入同一个设备
+type haDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+}
+
+// haState 传感器的状态和属性，状态为版本标签
+type haState struct {
+	TagName     string    `json:"tag_name"`
+	Name        string    `json:"name"`
+	Repository  string    `json:"repository"`
+	Event       string    `json:"event"`
+	Prerelease  bool      `json:"prerelease,omitempty"`
+	HTMLURL     string    `json:"html_url"`
+	PublishedAt time.Time `json:"published_at"`
+	Highlights  []string  `json:"highlights,omitempty"`
+	Key         string    `json:"key,omitempty"`
+}
+
+// haObjectID 仓库对应的实体ID，只包含小写字母、数字和下划线
+func haObjectID(release *github.ReleaseInfo) string {
+	id := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, strings.ToLower(release.Owner+"_"+release.Repository))
+	return "notify_" + id
+}
+
+// homeAssistantMessages 返回版本对应的自动发现配置和传感器状态，两者都是保留消息，
+// Home Assistant 重启后也能恢复传感器；不在 Repos 中的仓库和非新版本事件返回nil
+func (n *Notifier) homeAssistantMessages(release *github.ReleaseInfo) ([]message, error) {
+	ha := n.config.HomeAssistant
+	if !ha.Enabled || !release.IsNewVersion() {
+		return nil, nil
+	}
+	if len(ha.Repos) > 0 && !release.MatchesRepo(ha.Repos) {
+		return nil, nil
+	}
+
+	objectID := haObjectID(release)
+	base := ha.DiscoveryPrefix + "/sensor/" + objectID
+	stateTopic := base + "/state"
+
+	discovery, err := json.Marshal(haDiscovery{
+		Name:                release.Owner + "/" + release.Repository,
+		UniqueID:            objectID,
+		ObjectID:            objectID,
+		StateTopic:          stateTopic,
+		ValueTemplate:       "{{ value_json.tag_name }}",
+		JSONAttributesTopic: stateTopic,
+		Icon:                "mdi:tag-arrow-up",
+		Device: haDevice{
+			Identifiers:  []string{"notify"},
+			Name:         "notify",
+			Manufacturer: "orange-juzipi/notify",
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化Home Assistant自动发现配置失败: %v", err)
+	}
+
+	state, err := json.Marshal(haState{
+		TagName:     release.TagName,
+		Name:        release.Name,
+		Repository:  release.Owner + "/" + release.Repository,
+		Event:       release.Event,
+		Prerelease:  release.Prerelease,
+		HTMLURL:     release.HTMLURL,
+		PublishedAt: release.PublishedAt,
+		Highlights:  release.Highlights,
+		Key:         release.Key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("序列化Home Assistant传感器状态失败: %v", err)
+	}
+
+	return []message{
+		{topic: base + "/config", payload: discovery, retain: true},
+		{topic: stateTopic, payload: state, retain: true},
+	}, nil
+}
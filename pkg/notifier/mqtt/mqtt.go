@@ -0,0 +1,284 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultTopic 默认发布的主题
+const DefaultTopic = "notify/releases"
+
+// timeout 连接和每个报文的读写超时
+const timeout = 10 * time.Second
+
+// Config MQTT通知配置
+type Config struct {
+	Enabled bool
+	// BrokerURL 服务器地址，如 tcp://localhost:1883、mqtts://broker.example.com:8883
+	BrokerURL string
+	// ClientID 客户端ID，为空时自动生成
+	ClientID string
+	// Topic 发布的主题，支持 {owner}、{repo} 占位符，默认为 notify/releases
+	Topic string
+	// QoS 服务质量等级，支持 0 和 1
+	QoS int
+	// Retain 是否保留消息，新订阅者会立即收到每个主题的最后一条消息
+	Retain   bool
+	Username string
+	Password string
+	// TLS 加密连接配置，使用 mqtts://、ssl:// 或 tls:// 地址时生效
+	TLS TLSConfig
+	// HomeAssistant 同时发布 Home Assistant 自动发现配置，选定的仓库作为传感器出现
+	HomeAssistant HomeAssistantConfig
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+}
+
+// TLSConfig MQTT加密连接配置
+type TLSConfig struct {
+	// CAFile 自签名服务器证书的CA证书文件
+	CAFile string
+	// CertFile、KeyFile 客户端证书，用于双向认证
+	CertFile string
+	KeyFile  string
+	// InsecureSkipVerify 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool
+}
+
+// Notifier MQTT通知器，将每个版本以JSON格式发布到MQTT主题，供 Home Assistant、Node-RED 等订阅
+type Notifier struct {
+	config    Config
+	address   string
+	tlsConfig *tls.Config // 为nil时使用明文连接
+	dialer    *net.Dialer
+}
+
+// New 创建MQTT通知器
+// MQTT发布结构化数据，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.BrokerURL == "" {
+		return nil, fmt.Errorf("MQTT服务器地址不能为空")
+	}
+	if config.QoS != 0 && config.QoS != 1 {
+		return nil, fmt.Errorf("不支持的MQTT QoS: %d（可选 0、1）", config.QoS)
+	}
+	if config.Topic == "" {
+		config.Topic = DefaultTopic
+	}
+	if strings.ContainsAny(config.Topic, "+#") {
+		return nil, fmt.Errorf("MQTT发布主题不能包含通配符: %s", config.Topic)
+	}
+	if config.HomeAssistant.DiscoveryPrefix == "" {
+		config.HomeAssistant.DiscoveryPrefix = DefaultDiscoveryPrefix
+	}
+	if strings.ContainsAny(config.HomeAssistant.DiscoveryPrefix, "+#") {
+		return nil, fmt.Errorf("Home Assistant自动发现前缀不能包含通配符: %s", config.HomeAssistant.DiscoveryPrefix)
+	}
+	if config.ClientID == "" {
+		suffix := make([]byte, 4)
+		rand.Read(suffix)
+		config.ClientID = "notify-" + hex.EncodeToString(suffix)
+	}
+
+	u, err := url.Parse(config.BrokerURL)
+	if err != nil || u.Hostname() == "" {
+		return nil, fmt.Errorf("无效的MQTT服务器地址: %s", config.BrokerURL)
+	}
+
+	n := &Notifier{config: config, dialer: &net.Dialer{Timeout: timeout}}
+	port := u.Port()
+	switch u.Scheme {
+	case "tcp", "mqtt":
+		if port == "" {
+			port = "1883"
+		}
+	case "ssl", "tls", "mqtts":
+		if port == "" {
+			port = "8883"
+		}
+		n.tlsConfig, err = newTLSConfig(config.TLS, u.Hostname())
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("不支持的MQTT服务器地址协议: %s（可选 tcp、mqtt、ssl、tls、mqtts）", u.Scheme)
+	}
+	n.address = net.JoinHostPort(u.Hostname(), port)
+
+	// 使用 URL 中的用户信息作为默认的用户名和密码
+	if n.config.Username == "" && u.User != nil {
+		n.config.Username = u.User.Username()
+		n.config.Password, _ = u.User.Password()
+	}
+
+	if config.LocalAddr != "" {
+		ip, err := util.ResolveLocalAddr(config.LocalAddr)
+		if err != nil {
+			return nil, err
+		}
+		n.dialer.LocalAddr = &net.TCPAddr{IP: ip}
+	}
+
+	return n, nil
+}
+
+// newTLSConfig 构建TLS配置
+func newTLSConfig(config TLSConfig, serverName string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("读取MQTT CA证书失败: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("解析MQTT CA证书失败: %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.CertFile != "" || config.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载MQTT客户端证书失败: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "mqtt"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发布单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.publish([]*github.ReleaseInfo{release})
+}
+
+// SendBatch 每个版本单独发布一条消息，订阅方按版本处理
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+	return n.publish(releases)
+}
+
+// topicFor 替换主题中的占位符
+func (n *Notifier) topicFor(release *github.ReleaseInfo) string {
+	return strings.NewReplacer("{owner}", release.Owner, "{repo}", release.Repository).Replace(n.config.Topic)
+}
+
+// publish 连接服务器，逐个发布版本后断开连接
+// 通知发送的频率很低，每次发送使用短连接，不需要维护长连接和重连
+func (n *Notifier) publish(releases []*github.ReleaseInfo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := n.dialer.DialContext(ctx, "tcp", n.address)
+	if err != nil {
+		return fmt.Errorf("连接MQTT服务器失败: %v", err)
+	}
+	defer conn.Close()
+
+	if n.tlsConfig != nil {
+		tlsConn := tls.Client(conn, n.tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return fmt.Errorf("MQTT TLS握手失败: %v", err)
+		}
+		conn = tlsConn
+	}
+
+	r := bufio.NewReader(conn)
+	if err := n.roundTrip(conn, r, connectPacket(n.config.ClientID, n.config.Username, n.config.Password), packetConnack, parseConnack); err != nil {
+		return fmt.Errorf("MQTT连接失败: %v", err)
+	}
+
+	var packetID uint16
+	for _, release := range releases {
+		payload, err := json.Marshal(release)
+		if err != nil {
+			return fmt.Errorf("序列化版本信息失败: %v", err)
+		}
+		messages := []message{{topic: n.topicFor(release), payload: payload, retain: n.config.Retain}}
+		ha, err := n.homeAssistantMessages(release)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, ha...)
+
+		for _, msg := range messages {
+			packetID++
+			if err := n.publishOne(conn, r, msg, packetID); err != nil {
+				return fmt.Errorf("发布 %s/%s 到MQTT失败: %v", release.Owner, release.Repository, err)
+			}
+		}
+	}
+
+	// 断开连接失败不影响已发布的消息
+	n.write(conn, disconnectPacket())
+	return nil
+}
+
+// publishOne 发布一条消息，QoS 1 时等待服务器确认
+func (n *Notifier) publishOne(conn net.Conn, r *bufio.Reader, msg message, packetID uint16) error {
+	packet := publishPacket(msg.topic, msg.payload, byte(n.config.QoS), msg.retain, packetID)
+	if n.config.QoS == 0 {
+		return n.write(conn, packet)
+	}
+	return n.roundTrip(conn, r, packet, packetPuback, func(body []byte) error {
+		return parsePuback(body, packetID)
+	})
+}
+
+// write 写入一个报文
+func (n *Notifier) write(conn net.Conn, packet []byte) error {
+	conn.SetWriteDeadline(time.Now().Add(timeout))
+	_, err := conn.Write(packet)
+	return err
+}
+
+// roundTrip 写入一个报文并等待指定类型的响应
+func (n *Notifier) roundTrip(conn net.Conn, r *bufio.Reader, packet []byte, want byte, check func([]byte) error) error {
+	if err := n.write(conn, packet); err != nil {
+		return err
+	}
+
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	packetType, _, body, err := readPacket(r)
+	if err != nil {
+		return fmt.Errorf("读取响应失败: %v", err)
+	}
+	if packetType != want {
+		return fmt.Errorf("意外的响应报文类型: %d", packetType)
+	}
+	return check(body)
+}
@@ -0,0 +1,218 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// published 模拟服务器收到的PUBLISH报文
+type published struct {
+	topic   string
+	qos     byte
+	retain  bool
+	payload []byte
+}
+
+// fakeBroker 启动一个只处理一个连接的MQTT服务器，记录CONNECT中的用户名和收到的消息
+func fakeBroker(t *testing.T, returnCode byte) (string, <-chan string, <-chan []published) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	users := make(chan string, 1)
+	messages := make(chan []published, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+
+		packetType, _, body, err := readPacket(r)
+		if err != nil || packetType != packetConnect {
+			return
+		}
+		// 协议名(6) + 级别(1) + 标志(1) + 保活(2) + 客户端ID
+		flags := body[7]
+		rest := body[10:]
+		clientIDLen := binary.BigEndian.Uint16(rest)
+		rest = rest[2+clientIDLen:]
+		var user string
+		if flags&flagUsername != 0 {
+			userLen := binary.BigEndian.Uint16(rest)
+			user = string(rest[2 : 2+userLen])
+		}
+		users <- user
+
+		conn.Write([]byte{packetConnack << 4, 2, 0, returnCode})
+		if returnCode != 0 {
+			return
+		}
+
+		var got []published
+		for {
+			packetType, flags, body, err := readPacket(r)
+			if err != nil || packetType == packetDisconnect {
+				break
+			}
+			topicLen := binary.BigEndian.Uint16(body)
+			msg := published{
+				topic:  string(body[2 : 2+topicLen]),
+				qos:    (flags >> 1) & 0x03,
+				retain: flags&0x01 != 0,
+			}
+			body = body[2+topicLen:]
+			if msg.qos > 0 {
+				conn.Write([]byte{packetPuback << 4, 2, body[0], body[1]})
+				body = body[2:]
+			}
+			msg.payload = body
+			got = append(got, msg)
+		}
+		messages <- got
+	}()
+
+	return "tcp://" + ln.Addr().String(), users, messages
+}
+
+// TestSendBatch_QoS1 测试按仓库替换主题占位符并以QoS 1逐个发布版本
+func TestSendBatch_QoS1(t *testing.T) {
+	brokerURL, users, messages := fakeBroker(t, 0)
+
+	n, err := New(Config{
+		Enabled:   true,
+		BrokerURL: brokerURL,
+		Topic:     "notify/{owner}/{repo}",
+		QoS:       1,
+		Retain:    true,
+		Username:  "ha",
+		Password:  "secret",
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Event: github.EventRelease, Owner: "o", Repository: "a", TagName: "v1.0.0"},
+		{Event: github.EventRelease, Owner: "o", Repository: "b", TagName: "v2.0.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 2}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if user := <-users; user != "ha" {
+		t.Errorf("用户名为 %q，期望 ha", user)
+	}
+
+	got := <-messages
+	if len(got) != 2 {
+		t.Fatalf("收到 %d 条消息，期望 2 条", len(got))
+	}
+	for i, msg := range got {
+		wantTopic := "notify/o/" + releases[i].Repository
+		if msg.topic != wantTopic || msg.qos != 1 || !msg.retain {
+			t.Errorf("第 %d 条消息: 主题 %q QoS %d retain %v，期望 %q QoS 1 retain true", i+1, msg.topic, msg.qos, msg.retain, wantTopic)
+		}
+		var release github.ReleaseInfo
+		if err := json.Unmarshal(msg.payload, &release); err != nil {
+			t.Fatalf("解析消息失败: %v", err)
+		}
+		if release.TagName != releases[i].TagName {
+			t.Errorf("第 %d 条消息的版本为 %s，期望 %s", i+1, release.TagName, releases[i].TagName)
+		}
+	}
+}
+
+// TestSendBatch_HomeAssistant 测试为选定仓库的新版本发布自动发现配置和传感器状态
+func TestSendBatch_HomeAssistant(t *testing.T) {
+	brokerURL, _, messages := fakeBroker(t, 0)
+
+	n, err := New(Config{
+		Enabled:       true,
+		BrokerURL:     brokerURL,
+		HomeAssistant: HomeAssistantConfig{Enabled: true, Repos: []string{"home-assistant/*"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Event: github.EventRelease, Owner: "home-assistant", Repository: "core", TagName: "2026.10.1", Key: "k"},
+		{Event: github.EventRelease, Owner: "o", Repository: "a", TagName: "v1.0.0"},
+		{Event: github.EventNotesUpdated, Owner: "home-assistant", Repository: "core", TagName: "2026.10.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 3}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	got := <-messages
+	// 每个版本一条普通消息，只有 home-assistant/core 的新版本额外发布配置和状态
+	if len(got) != 5 {
+		t.Fatalf("收到 %d 条消息，期望 5 条", len(got))
+	}
+
+	config, state := got[1], got[2]
+	if config.topic != "homeassistant/sensor/notify_home_assistant_core/config" || !config.retain {
+		t.Errorf("自动发现配置: 主题 %q retain %v", config.topic, config.retain)
+	}
+	var discovery haDiscovery
+	if err := json.Unmarshal(config.payload, &discovery); err != nil {
+		t.Fatalf("解析自动发现配置失败: %v", err)
+	}
+	if discovery.StateTopic != state.topic || discovery.UniqueID != "notify_home_assistant_core" || discovery.Name != "home-assistant/core" {
+		t.Errorf("自动发现配置不符合预期: %s", config.payload)
+	}
+
+	if !state.retain {
+		t.Error("传感器状态应为保留消息")
+	}
+	var attrs haState
+	if err := json.Unmarshal(state.payload, &attrs); err != nil {
+		t.Fatalf("解析传感器状态失败: %v", err)
+	}
+	if attrs.TagName != "2026.10.1" || attrs.Key != "k" {
+		t.Errorf("传感器状态不符合预期: %s", state.payload)
+	}
+}
+
+// TestSend_ConnectionRefused 测试服务器拒绝连接时返回错误
+func TestSend_ConnectionRefused(t *testing.T) {
+	brokerURL, _, _ := fakeBroker(t, 4)
+
+	n, err := New(Config{Enabled: true, BrokerURL: brokerURL}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	err = n.Send(&github.ReleaseInfo{Owner: "o", Repository: "a", TagName: "v1.0.0"}, render.RunContext{})
+	if err == nil {
+		t.Fatal("期望返回错误")
+	}
+}
+
+// TestNew_InvalidConfig 测试无效配置
+func TestNew_InvalidConfig(t *testing.T) {
+	for name, config := range map[string]Config{
+		"缺少地址":      {},
+		"不支持的QoS":   {BrokerURL: "tcp://localhost", QoS: 2},
+		"主题通配符":     {BrokerURL: "tcp://localhost", Topic: "notify/#"},
+		"自动发现前缀通配符": {BrokerURL: "tcp://localhost", HomeAssistant: HomeAssistantConfig{DiscoveryPrefix: "ha/+"}},
+		"不支持的协议":    {BrokerURL: "ws://localhost"},
+	} {
+		if _, err := New(config, nil); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// MQTT 3.1.1 控制报文类型
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetDisconnect = 14
+)
+
+// connectFlags CONNECT报文的连接标志
+const (
+	flagCleanSession = 0x02
+	flagPassword     = 0x40
+	flagUsername     = 0x80
+)
+
+// keepAlive CONNECT报文中的保活时间（秒），每次发送都是短连接，只需覆盖一次发送的时长
+const keepAlive = 60
+
+// connackErrors CONNACK返回码对应的错误说明
+var connackErrors = map[byte]string{
+	1: "不支持的协议版本",
+	2: "客户端ID被拒绝",
+	3: "服务不可用",
+	4: "用户名或密码错误",
+	5: "未授权",
+}
+
+// appendString 追加带两字节长度前缀的UTF-8字符串
+func appendString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// appendRemainingLength 追加剩余长度（变长编码，每字节7位）
+func appendRemainingLength(b []byte, n int) []byte {
+	for {
+		digit := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			digit |= 0x80
+		}
+		b = append(b, digit)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+// encodePacket 组装固定报头和报文内容
+func encodePacket(packetType, flags byte, body []byte) []byte {
+	packet := []byte{packetType<<4 | flags}
+	packet = appendRemainingLength(packet, len(body))
+	return append(packet, body...)
+}
+
+// connectPacket 构建CONNECT报文
+func connectPacket(clientID, username, password string) []byte {
+	flags := byte(flagCleanSession)
+	if username != "" {
+		flags |= flagUsername
+		if password != "" {
+			flags |= flagPassword
+		}
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags) // 协议级别 4 即 MQTT 3.1.1
+	body = binary.BigEndian.AppendUint16(body, keepAlive)
+	body = appendString(body, clientID)
+	if flags&flagUsername != 0 {
+		body = appendString(body, username)
+	}
+	if flags&flagPassword != 0 {
+		body = appendString(body, password)
+	}
+	return encodePacket(packetConnect, 0, body)
+}
+
+// publishPacket 构建PUBLISH报文，QoS为0时不带报文标识符
+func publishPacket(topic string, payload []byte, qos byte, retain bool, packetID uint16) []byte {
+	flags := qos << 1
+	if retain {
+		flags |= 0x01
+	}
+
+	body := appendString(nil, topic)
+	if qos > 0 {
+		body = binary.BigEndian.AppendUint16(body, packetID)
+	}
+	body = append(body, payload...)
+	return encodePacket(packetPublish, flags, body)
+}
+
+// disconnectPacket 构建DISCONNECT报文
+func disconnectPacket() []byte {
+	return encodePacket(packetDisconnect, 0, nil)
+}
+
+// readPacket 读取一个控制报文，返回报文类型、标志和内容
+func readPacket(r *bufio.Reader) (byte, byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, 0, nil, fmt.Errorf("无效的剩余长度")
+		}
+		digit, err := r.ReadByte()
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		if digit&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, 0, nil, err
+	}
+	return header >> 4, header & 0x0f, body, nil
+}
+
+// parseConnack 检查CONNACK报文的返回码
+func parseConnack(body []byte) error {
+	if len(body) != 2 {
+		return fmt.Errorf("无效的CONNACK报文")
+	}
+	if code := body[1]; code != 0 {
+		if msg, ok := connackErrors[code]; ok {
+			return fmt.Errorf("MQTT服务器拒绝连接: %s", msg)
+		}
+		return fmt.Errorf("MQTT服务器拒绝连接，返回码: %d", code)
+	}
+	return nil
+}
+
+// parsePuback 检查PUBACK报文的报文标识符
+func parsePuback(body []byte, packetID uint16) error {
+	if len(body) != 2 || !bytes.Equal(body, binary.BigEndian.AppendUint16(nil, packetID)) {
+		return fmt.Errorf("PUBACK报文标识符不匹配")
+	}
+	return nil
+}
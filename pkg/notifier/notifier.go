@@ -1,80 +1,725 @@
 package notifier
 
 import (
-	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"time"
 
-	"golang.org/x/time/rate"
-
 	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
 	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier/atom"
+	"github.com/orange-juzipi/notify/pkg/notifier/desktop"
 	"github.com/orange-juzipi/notify/pkg/notifier/dingtalk"
+	"github.com/orange-juzipi/notify/pkg/notifier/email"
+	"github.com/orange-juzipi/notify/pkg/notifier/fault"
+	"github.com/orange-juzipi/notify/pkg/notifier/feishu"
+	"github.com/orange-juzipi/notify/pkg/notifier/googlechat"
+	"github.com/orange-juzipi/notify/pkg/notifier/irc"
+	"github.com/orange-juzipi/notify/pkg/notifier/kafka"
+	"github.com/orange-juzipi/notify/pkg/notifier/mastodon"
+	"github.com/orange-juzipi/notify/pkg/notifier/mqtt"
+	"github.com/orange-juzipi/notify/pkg/notifier/ntfy"
+	"github.com/orange-juzipi/notify/pkg/notifier/opsgenie"
+	"github.com/orange-juzipi/notify/pkg/notifier/pagerduty"
+	"github.com/orange-juzipi/notify/pkg/notifier/pushbullet"
+	"github.com/orange-juzipi/notify/pkg/notifier/rocketchat"
+	"github.com/orange-juzipi/notify/pkg/notifier/slack"
+	"github.com/orange-juzipi/notify/pkg/notifier/stdout"
+	"github.com/orange-juzipi/notify/pkg/notifier/syslog"
+	"github.com/orange-juzipi/notify/pkg/notifier/teams"
 	"github.com/orange-juzipi/notify/pkg/notifier/telegram"
+	"github.com/orange-juzipi/notify/pkg/notifier/webex"
+	"github.com/orange-juzipi/notify/pkg/notifier/webhook"
+	"github.com/orange-juzipi/notify/pkg/notifier/wecom"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/redact"
+	"github.com/orange-juzipi/notify/pkg/render"
+	"github.com/orange-juzipi/notify/pkg/shortener"
+	"github.com/orange-juzipi/notify/pkg/window"
 )
 
+// errChannelDisabled 渠道在运行中被停用
+var errChannelDisabled = errors.New("通知渠道已停用")
+
 // Notifier 通知器接口
 type Notifier interface {
+	// Name 通知渠道名称，用于日志和失败队列
+	Name() string
 	// Send 发送通知
-	Send(release *github.ReleaseInfo) error
+	Send(release *github.ReleaseInfo, run render.RunContext) error
 	// SendBatch 批量发送通知（合并成一条消息）
-	SendBatch(releases []*github.ReleaseInfo) error
+	SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error
 	// IsEnabled 是否启用
 	IsEnabled() bool
 }
 
 // Manager 通知管理器
+// 定时运行和 serve 模式在多次发送之间复用同一个管理器，保留各渠道的发送速率和冷却状态，
+// 发送入口（NotifyAll、DrainOutbox、ProcessApprovals、ProcessAcks、Approve、Reject、Coalesce）依次执行，可以并发调用
 type Manager struct {
+	// mu 串行化发送入口，保护 overflow、stats 和发送期间的速率上下文
+	mu        sync.Mutex
 	notifiers []Notifier
-	template  *template.Template
-	limiter   *rate.Limiter
+	// template 默认语言的模板
+	template *template.Template
+	// templates 按语言索引的模板
+	templates map[string]*template.Template
+	// langs 各渠道使用的语言
+	langs map[string]string
+	// pacer 各渠道的发送速率令牌桶，交给对应的通知器使用
+	pacer *pacing.Pacer
+	// shortener 短链接服务，未配置时为nil
+	shortener *shortener.Client
+	// redactor 发送前隐藏敏感内容的过滤规则，未配置时为nil
+	redactor *redact.Redactor
+	// escalate 为true时，命中高亮关键字的版本单独优先发送
+	escalate bool
+	// outbox 发送失败的通知队列，在下次运行开始时重发
+	outbox *Outbox
+	// timezone 模板中运行时间使用的时区
+	timezone string
+	// format 消息中的时间格式和语言
+	format config.FormatConfig
+	// dailyLimits 各渠道每天最多发送的消息数，0表示不限制
+	dailyLimits map[string]int
+	// daily 各渠道今天已发送的消息数
+	daily *dailyCounter
+	// overflow 本次运行中超过每日上限、等待合并为摘要发送的版本
+	overflow map[string][]*github.ReleaseInfo
+	// coalesce 合并窗口内等待发送的版本，未配置合并窗口时为nil
+	coalesce       *coalesceQueue
+	coalesceWindow time.Duration
+	// clock 冷却期、发送速率、每日限额和合并窗口使用的时钟
+	clock clock.Clock
+	// approval 接收待审批汇总的渠道，未启用发送审批时为nil
+	approval  Notifier
+	approvers []string
+	// approvalsPath 等待审批的版本队列文件
+	approvalsPath string
+	// ackChannels 启用确认跟踪（"已查看"按钮）的渠道
+	ackChannels map[string]bool
+	// acksPath 确认记录文件
+	acksPath string
+	// routes 按仓库选择渠道的路由规则，未配置时发送到所有渠道
+	routes routeTable
+	// windows 各渠道的发送时间段，未配置的渠道随时发送
+	windows map[string]window.Schedule
+	// loc 判断每日限额和发送时间段使用的时区
+	loc *time.Location
+	// stats 发送成功和失败的消息数
+	stats SendStats
+	// renderFailures 模板渲染失败、改用简化内容发送的版本，由 TakeRenderFailures 按运行取出
+	renderFailures *render.Failures
+}
+
+// SendStats 通知管理器创建以来的发送统计，用于记录运行指标
+type SendStats struct {
+	// Messages 发送成功的消息数，包括重发和摘要
+	Messages int
+	// Failures 发送失败的消息数，失败的通知已放入失败队列
+	Failures int
+}
+
+// Sub 返回从 prev 到 s 之间的发送统计，复用管理器时用于计算单次运行的发送数
+func (s SendStats) Sub(prev SendStats) SendStats {
+	return SendStats{Messages: s.Messages - prev.Messages, Failures: s.Failures - prev.Failures}
+}
+
+// DigestSender 可选接口，支持把超过每日上限的版本合并为一条摘要消息发送
+type DigestSender interface {
+	SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error
 }
 
 // NewManager 创建通知管理器
 func NewManager(cfg *config.Config) (*Manager, error) {
-	// 解析模板
-	tmpl, err := template.New("release").Parse(cfg.Template)
+	return NewManagerWithClock(cfg, clock.System)
+}
+
+// NewManagerWithClock 创建使用指定时钟的通知管理器，测试中传入 clock.Fake 控制冷却期和合并窗口的到期
+func NewManagerWithClock(cfg *config.Config, clk clock.Clock) (*Manager, error) {
+	// 解析各渠道所用语言的模板
+	langs := channelLangs(cfg)
+	templates, err := parseTemplates(cfg, langs)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := templates[defaultLang(cfg)]
+
+	// 各渠道的发送速率，配置中的 pacing 覆盖通知器的默认速率
+	pacer, err := newPacer(cfg.Pacing)
+	if err != nil {
+		return nil, err
+	}
+	pacer.SetClock(clk)
+
+	// 各渠道的发送时间段
+	windows, err := newWindows(cfg.DeliveryWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	// 短链接服务
+	var links *shortener.Client
+	if cfg.Shortener.Provider != "" {
+		links, err = shortener.New(shortener.Config{
+			Provider:  cfg.Shortener.Provider,
+			URL:       cfg.Shortener.URL,
+			APIKey:    cfg.Shortener.APIKey,
+			Domain:    cfg.Shortener.Domain,
+			LocalAddr: cfg.Network.LocalAddr,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 发送前的内容过滤规则
+	rules := make([]redact.Rule, 0, len(cfg.Redact))
+	for _, rule := range cfg.Redact {
+		rules = append(rules, redact.Rule{Pattern: rule.Pattern, Replacement: rule.Replacement})
+	}
+	redactor, err := redact.New(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// 加载失败通知队列（分片运行时每个分片使用独立的队列文件）
+	outboxPath, err := util.ResolvePath("", "outbox.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+	outbox, err := NewOutbox(outboxPath)
+	if err != nil {
+		return nil, fmt.Errorf("加载失败通知队列失败: %v", err)
+	}
+
+	// 加载每渠道每日消息计数
+	loc, err := time.LoadLocation(cfg.GitHub.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	dailyPath, err := util.ResolvePath("", "daily.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+	daily, err := newDailyCounter(dailyPath, loc, clk)
+	if err != nil {
+		return nil, err
+	}
+
+	// 等待审批的版本队列
+	approvalsPath, err := util.ResolvePath("", "approvals.json", cfg.DataSuffix())
 	if err != nil {
 		return nil, err
 	}
 
-	// 使用标准库的速率限制器
-	// 设置为 1条/4秒（15条/分钟），突发容量为3条
-	// 这样配合钉钉的限制器，确保不会超过每分钟20条的硬性限制
-	limiter := rate.NewLimiter(rate.Every(4*time.Second), 3)
+	// 版本消息的确认记录
+	acksPath, err := util.ResolvePath("", "acks.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
 
 	// 创建通知器
 	manager := &Manager{
-		template: tmpl,
-		limiter:  limiter,
+		template:       tmpl,
+		templates:      templates,
+		langs:          langs,
+		pacer:          pacer,
+		shortener:      links,
+		redactor:       redactor,
+		escalate:       cfg.Highlight.Escalate,
+		outbox:         outbox,
+		timezone:       cfg.GitHub.Timezone,
+		format:         cfg.Format,
+		renderFailures: &render.Failures{},
+		dailyLimits: map[string]int{
+			"slack":      cfg.Notifications.Slack.DailyLimit,
+			"email":      cfg.Notifications.Email.DailyLimit,
+			"wecom":      cfg.Notifications.WeCom.DailyLimit,
+			"feishu":     cfg.Notifications.Feishu.DailyLimit,
+			"webhook":    cfg.Notifications.Webhook.DailyLimit,
+			"ntfy":       cfg.Notifications.Ntfy.DailyLimit,
+			"teams":      cfg.Notifications.Teams.DailyLimit,
+			"desktop":    cfg.Notifications.Desktop.DailyLimit,
+			"rocketchat": cfg.Notifications.RocketChat.DailyLimit,
+			"googlechat": cfg.Notifications.GoogleChat.DailyLimit,
+			"irc":        cfg.Notifications.IRC.DailyLimit,
+			"pushbullet": cfg.Notifications.Pushbullet.DailyLimit,
+			"mastodon":   cfg.Notifications.Mastodon.DailyLimit,
+			"webex":      cfg.Notifications.Webex.DailyLimit,
+		},
+		daily:    daily,
+		overflow: make(map[string][]*github.ReleaseInfo),
+		clock:    clk,
+
+		approvalsPath: approvalsPath,
+		ackChannels:   make(map[string]bool),
+		acksPath:      acksPath,
+		routes:        newRoutes(cfg),
+		windows:       windows,
+		loc:           loc,
+	}
+
+	for _, c := range cfg.Notifications.DingTalk {
+		manager.dailyLimits[c.Channel()] = c.DailyLimit
+	}
+	for _, c := range cfg.Notifications.Telegram {
+		manager.dailyLimits[c.Channel()] = c.DailyLimit
+		if c.Enabled && c.Ack {
+			manager.ackChannels[c.Channel()] = true
+		}
+	}
+
+	// 定时运行的合并窗口
+	if cfg.Schedule.CoalesceWindow != "" {
+		window, err := time.ParseDuration(cfg.Schedule.CoalesceWindow)
+		if err != nil || window < 0 {
+			return nil, fmt.Errorf("无效的合并窗口 %s: 请使用如 30m、1h 的格式", cfg.Schedule.CoalesceWindow)
+		}
+		if window > 0 {
+			coalescePath, err := util.ResolvePath("", "coalesce.json", cfg.DataSuffix())
+			if err != nil {
+				return nil, err
+			}
+			manager.coalesce, err = newCoalesceQueue(coalescePath)
+			if err != nil {
+				return nil, err
+			}
+			manager.coalesceWindow = window
+		}
 	}
 
-	// 添加钉钉通知器
-	if cfg.Notifications.DingTalk.Enabled {
+	// 添加钉钉通知器，每个实例一个
+	for _, c := range cfg.Notifications.DingTalk {
+		if !c.Enabled {
+			continue
+		}
 		dingTalkConfig := dingtalk.Config{
-			Enabled:    cfg.Notifications.DingTalk.Enabled,
-			WebhookURL: cfg.Notifications.DingTalk.WebhookURL,
-			Secret:     cfg.Notifications.DingTalk.Secret,
+			Name:           c.Channel(),
+			Enabled:        c.Enabled,
+			WebhookURL:     c.WebhookURL,
+			Secret:         c.Secret,
+			FeedCard:       c.FeedCard,
+			Keyword:        c.Keyword,
+			AppKey:         c.AppKey,
+			AppSecret:      c.AppSecret,
+			RobotCode:      c.RobotCode,
+			UserIDs:        c.UserIDs,
+			APIBaseURL:     c.APIBaseURL,
+			ConversationID: c.ConversationID,
+			LocalAddr:      cfg.Network.LocalAddr,
 		}
 		err = manager.AddDingTalkNotifier(dingTalkConfig)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%s: %v", c.Channel(), err)
 		}
 	}
 
-	// 添加Telegram通知器
-	if cfg.Notifications.Telegram.Enabled {
+	// 添加Telegram通知器，每个实例一个
+	for _, c := range cfg.Notifications.Telegram {
+		if !c.Enabled {
+			continue
+		}
 		telegramConfig := telegram.Config{
-			Enabled:  cfg.Notifications.Telegram.Enabled,
-			BotToken: cfg.Notifications.Telegram.BotToken,
-			ChatID:   cfg.Notifications.Telegram.ChatID,
+			Name:       c.Channel(),
+			Enabled:    c.Enabled,
+			BotToken:   c.BotToken,
+			ChatID:     c.ChatID,
+			ParseMode:  c.ParseMode,
+			SendPhoto:  c.SendPhoto,
+			Ack:        c.Ack,
+			APIBaseURL: c.APIBaseURL,
+			LocalAddr:  cfg.Network.LocalAddr,
+			Proxy:      c.Proxy,
+		}
+		if ssh := c.SSH; ssh.Host != "" {
+			telegramConfig.SSH = &util.SSHOptions{
+				Host:          ssh.Host,
+				User:          ssh.User,
+				Password:      ssh.Password,
+				KeyFile:       ssh.KeyFile,
+				KeyPassphrase: ssh.KeyPassphrase,
+				KnownHosts:    ssh.KnownHosts,
+			}
 		}
 		err = manager.AddTelegramNotifier(telegramConfig)
 		if err != nil {
+			return nil, fmt.Errorf("%s: %v", c.Channel(), err)
+		}
+	}
+
+	// 添加Slack通知器
+	if cfg.Notifications.Slack.Enabled {
+		slackConfig := slack.Config{
+			Enabled:      cfg.Notifications.Slack.Enabled,
+			WebhookURL:   cfg.Notifications.Slack.WebhookURL,
+			BotToken:     cfg.Notifications.Slack.BotToken,
+			Channel:      cfg.Notifications.Slack.Channel,
+			PreviewImage: cfg.Notifications.Slack.PreviewImage,
+			LocalAddr:    cfg.Network.LocalAddr,
+		}
+		err = manager.AddSlackNotifier(slackConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加邮件通知器
+	if cfg.Notifications.Email.Enabled {
+		emailConfig := email.Config{
+			Enabled:   cfg.Notifications.Email.Enabled,
+			Host:      cfg.Notifications.Email.Host,
+			Port:      cfg.Notifications.Email.Port,
+			Username:  cfg.Notifications.Email.Username,
+			Password:  cfg.Notifications.Email.Password,
+			From:      cfg.Notifications.Email.From,
+			To:        cfg.Notifications.Email.To,
+			Security:  cfg.Notifications.Email.Security,
+			LocalAddr: cfg.Network.LocalAddr,
+		}
+		err = manager.AddEmailNotifier(emailConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加企业微信通知器
+	if cfg.Notifications.WeCom.Enabled {
+		weComConfig := wecom.Config{
+			Enabled:    cfg.Notifications.WeCom.Enabled,
+			WebhookURL: cfg.Notifications.WeCom.WebhookURL,
+			LocalAddr:  cfg.Network.LocalAddr,
+		}
+		err = manager.AddWeComNotifier(weComConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加飞书通知器
+	if cfg.Notifications.Feishu.Enabled {
+		feishuConfig := feishu.Config{
+			Enabled:    cfg.Notifications.Feishu.Enabled,
+			WebhookURL: cfg.Notifications.Feishu.WebhookURL,
+			Secret:     cfg.Notifications.Feishu.Secret,
+			LocalAddr:  cfg.Network.LocalAddr,
+		}
+		err = manager.AddFeishuNotifier(feishuConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加通用webhook通知器
+	if cfg.Notifications.Webhook.Enabled {
+		webhookConfig := webhook.Config{
+			Enabled:         cfg.Notifications.Webhook.Enabled,
+			URL:             cfg.Notifications.Webhook.URL,
+			Method:          cfg.Notifications.Webhook.Method,
+			Headers:         cfg.Notifications.Webhook.Headers,
+			BodyTemplate:    cfg.Notifications.Webhook.BodyTemplate,
+			Secret:          cfg.Notifications.Webhook.Secret,
+			SignatureHeader: cfg.Notifications.Webhook.SignatureHeader,
+			LocalAddr:       cfg.Network.LocalAddr,
+		}
+		err = manager.AddWebhookNotifier(webhookConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加ntfy通知器
+	if cfg.Notifications.Ntfy.Enabled {
+		ntfyConfig := ntfy.Config{
+			Enabled:      cfg.Notifications.Ntfy.Enabled,
+			ServerURL:    cfg.Notifications.Ntfy.ServerURL,
+			Topic:        cfg.Notifications.Ntfy.Topic,
+			Token:        cfg.Notifications.Ntfy.Token,
+			Priority:     cfg.Notifications.Ntfy.Priority,
+			Tags:         cfg.Notifications.Ntfy.Tags,
+			PreviewImage: cfg.Notifications.Ntfy.PreviewImage,
+			LocalAddr:    cfg.Network.LocalAddr,
+		}
+		err = manager.AddNtfyNotifier(ntfyConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Microsoft Teams通知器
+	if cfg.Notifications.Teams.Enabled {
+		teamsConfig := teams.Config{
+			Enabled:      cfg.Notifications.Teams.Enabled,
+			WebhookURL:   cfg.Notifications.Teams.WebhookURL,
+			Format:       cfg.Notifications.Teams.Format,
+			PreviewImage: cfg.Notifications.Teams.PreviewImage,
+			LocalAddr:    cfg.Network.LocalAddr,
+		}
+		err = manager.AddTeamsNotifier(teamsConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加桌面通知器
+	if cfg.Notifications.Desktop.Enabled {
+		desktopConfig := desktop.Config{
+			Enabled: cfg.Notifications.Desktop.Enabled,
+			AppName: cfg.Notifications.Desktop.AppName,
+		}
+		err = manager.AddDesktopNotifier(desktopConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加MQTT通知器
+	if cfg.Notifications.MQTT.Enabled {
+		mqttConfig := mqtt.Config{
+			Enabled:   cfg.Notifications.MQTT.Enabled,
+			BrokerURL: cfg.Notifications.MQTT.BrokerURL,
+			ClientID:  cfg.Notifications.MQTT.ClientID,
+			Topic:     cfg.Notifications.MQTT.Topic,
+			QoS:       cfg.Notifications.MQTT.QoS,
+			Retain:    cfg.Notifications.MQTT.Retain,
+			Username:  cfg.Notifications.MQTT.Username,
+			Password:  cfg.Notifications.MQTT.Password,
+			TLS: mqtt.TLSConfig{
+				CAFile:             cfg.Notifications.MQTT.TLS.CAFile,
+				CertFile:           cfg.Notifications.MQTT.TLS.CertFile,
+				KeyFile:            cfg.Notifications.MQTT.TLS.KeyFile,
+				InsecureSkipVerify: cfg.Notifications.MQTT.TLS.InsecureSkipVerify,
+			},
+			HomeAssistant: mqtt.HomeAssistantConfig{
+				Enabled:         cfg.Notifications.MQTT.HomeAssistant.Enabled,
+				DiscoveryPrefix: cfg.Notifications.MQTT.HomeAssistant.DiscoveryPrefix,
+				Repos:           cfg.Notifications.MQTT.HomeAssistant.Repos,
+			},
+			LocalAddr: cfg.Network.LocalAddr,
+		}
+		err = manager.AddMQTTNotifier(mqttConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Rocket.Chat通知器
+	if cfg.Notifications.RocketChat.Enabled {
+		rocketChatConfig := rocketchat.Config{
+			Enabled:    cfg.Notifications.RocketChat.Enabled,
+			WebhookURL: cfg.Notifications.RocketChat.WebhookURL,
+			Alias:      cfg.Notifications.RocketChat.Alias,
+			Emoji:      cfg.Notifications.RocketChat.Emoji,
+			Channel:    cfg.Notifications.RocketChat.Channel,
+			LocalAddr:  cfg.Network.LocalAddr,
+		}
+		err = manager.AddRocketChatNotifier(rocketChatConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Google Chat通知器
+	if cfg.Notifications.GoogleChat.Enabled {
+		googleChatConfig := googlechat.Config{
+			Enabled:      cfg.Notifications.GoogleChat.Enabled,
+			WebhookURL:   cfg.Notifications.GoogleChat.WebhookURL,
+			ThreadByRepo: cfg.Notifications.GoogleChat.ThreadByRepo,
+			LocalAddr:    cfg.Network.LocalAddr,
+		}
+		err = manager.AddGoogleChatNotifier(googleChatConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加IRC通知器
+	if cfg.Notifications.IRC.Enabled {
+		ircConfig := irc.Config{
+			Enabled:            cfg.Notifications.IRC.Enabled,
+			Server:             cfg.Notifications.IRC.Server,
+			TLS:                cfg.Notifications.IRC.TLS,
+			InsecureSkipVerify: cfg.Notifications.IRC.InsecureSkipVerify,
+			Nick:               cfg.Notifications.IRC.Nick,
+			Password:           cfg.Notifications.IRC.Password,
+			NickServPassword:   cfg.Notifications.IRC.NickServPassword,
+			Channel:            cfg.Notifications.IRC.Channel,
+			ChannelKey:         cfg.Notifications.IRC.ChannelKey,
+			Notice:             cfg.Notifications.IRC.Notice,
+			LocalAddr:          cfg.Network.LocalAddr,
+		}
+		err = manager.AddIRCNotifier(ircConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Atom订阅输出
+	if cfg.Notifications.Atom.Enabled {
+		feedPath, err := AtomFeedPath(cfg)
+		if err != nil {
+			return nil, err
+		}
+		atomConfig := atom.Config{
+			Enabled:    cfg.Notifications.Atom.Enabled,
+			Path:       feedPath,
+			Title:      cfg.Notifications.Atom.Title,
+			MaxEntries: cfg.Notifications.Atom.MaxEntries,
+			SelfURL:    cfg.Notifications.Atom.SelfURL,
+		}
+		err = manager.AddAtomNotifier(atomConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Pushbullet通知器
+	if cfg.Notifications.Pushbullet.Enabled {
+		pushbulletConfig := pushbullet.Config{
+			Enabled:     cfg.Notifications.Pushbullet.Enabled,
+			AccessToken: cfg.Notifications.Pushbullet.AccessToken,
+			DeviceIden:  cfg.Notifications.Pushbullet.DeviceIden,
+			ChannelTag:  cfg.Notifications.Pushbullet.ChannelTag,
+			LocalAddr:   cfg.Network.LocalAddr,
+		}
+		err = manager.AddPushbulletNotifier(pushbulletConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加syslog输出
+	if cfg.Notifications.Syslog.Enabled {
+		syslogConfig := syslog.Config{
+			Enabled:            cfg.Notifications.Syslog.Enabled,
+			Network:            cfg.Notifications.Syslog.Network,
+			Address:            cfg.Notifications.Syslog.Address,
+			InsecureSkipVerify: cfg.Notifications.Syslog.InsecureSkipVerify,
+			Framing:            cfg.Notifications.Syslog.Framing,
+			Facility:           cfg.Notifications.Syslog.Facility,
+			Severity:           cfg.Notifications.Syslog.Severity,
+			AppName:            cfg.Notifications.Syslog.AppName,
+			Hostname:           cfg.Notifications.Syslog.Hostname,
+			LocalAddr:          cfg.Network.LocalAddr,
+		}
+		err = manager.AddSyslogNotifier(syslogConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加标准输出
+	if cfg.Notifications.Stdout.Enabled {
+		err = manager.AddStdoutNotifier(stdout.Config{Enabled: cfg.Notifications.Stdout.Enabled})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Mastodon通知器
+	if cfg.Notifications.Mastodon.Enabled {
+		mastodonConfig := mastodon.Config{
+			Enabled:     cfg.Notifications.Mastodon.Enabled,
+			InstanceURL: cfg.Notifications.Mastodon.InstanceURL,
+			AccessToken: cfg.Notifications.Mastodon.AccessToken,
+			Visibility:  cfg.Notifications.Mastodon.Visibility,
+			Hashtags:    cfg.Notifications.Mastodon.Hashtags,
+			Language:    cfg.Notifications.Mastodon.Language,
+			MaxChars:    cfg.Notifications.Mastodon.MaxChars,
+			LocalAddr:   cfg.Network.LocalAddr,
+		}
+		err = manager.AddMastodonNotifier(mastodonConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Kafka通知器
+	if cfg.Notifications.Kafka.Enabled {
+		kafkaConfig := kafka.Config{
+			Enabled:  cfg.Notifications.Kafka.Enabled,
+			Brokers:  cfg.Notifications.Kafka.Brokers,
+			Topic:    cfg.Notifications.Kafka.Topic,
+			ClientID: cfg.Notifications.Kafka.ClientID,
+			Acks:     cfg.Notifications.Kafka.Acks,
+			TLS: kafka.TLSConfig{
+				Enabled:            cfg.Notifications.Kafka.TLS.Enabled,
+				CAFile:             cfg.Notifications.Kafka.TLS.CAFile,
+				CertFile:           cfg.Notifications.Kafka.TLS.CertFile,
+				KeyFile:            cfg.Notifications.Kafka.TLS.KeyFile,
+				InsecureSkipVerify: cfg.Notifications.Kafka.TLS.InsecureSkipVerify,
+			},
+			SASL: kafka.SASLConfig{
+				Mechanism: cfg.Notifications.Kafka.SASL.Mechanism,
+				Username:  cfg.Notifications.Kafka.SASL.Username,
+				Password:  cfg.Notifications.Kafka.SASL.Password,
+			},
+			LocalAddr: cfg.Network.LocalAddr,
+		}
+		err = manager.AddKafkaNotifier(kafkaConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加PagerDuty通知器
+	if cfg.Notifications.PagerDuty.Enabled {
+		pagerdutyConfig := pagerduty.Config{
+			Enabled:    cfg.Notifications.PagerDuty.Enabled,
+			RoutingKey: cfg.Notifications.PagerDuty.RoutingKey,
+			Repos:      cfg.Notifications.PagerDuty.Repos,
+			Severity:   cfg.Notifications.PagerDuty.Severity,
+			LocalAddr:  cfg.Network.LocalAddr,
+		}
+		err = manager.AddPagerDutyNotifier(pagerdutyConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Opsgenie通知器
+	if cfg.Notifications.Opsgenie.Enabled {
+		opsgenieConfig := opsgenie.Config{
+			Enabled:   cfg.Notifications.Opsgenie.Enabled,
+			APIKey:    cfg.Notifications.Opsgenie.APIKey,
+			Repos:     cfg.Notifications.Opsgenie.Repos,
+			Priority:  cfg.Notifications.Opsgenie.Priority,
+			Region:    cfg.Notifications.Opsgenie.Region,
+			Tags:      cfg.Notifications.Opsgenie.Tags,
+			LocalAddr: cfg.Network.LocalAddr,
+		}
+		err = manager.AddOpsgenieNotifier(opsgenieConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 添加Webex通知器
+	if cfg.Notifications.Webex.Enabled {
+		webexConfig := webex.Config{
+			Enabled:    cfg.Notifications.Webex.Enabled,
+			BotToken:   cfg.Notifications.Webex.BotToken,
+			RoomID:     cfg.Notifications.Webex.RoomID,
+			APIBaseURL: cfg.Notifications.Webex.APIBaseURL,
+			LocalAddr:  cfg.Network.LocalAddr,
+		}
+		err = manager.AddWebexNotifier(webexConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 启用发送审批时，审批渠道只接收待审批的汇总
+	if cfg.Approval.Enabled {
+		if err := manager.setupApproval(cfg.Approval); err != nil {
 			return nil, err
 		}
 	}
@@ -82,118 +727,464 @@ func NewManager(cfg *config.Config) (*Manager, error) {
 	return manager, nil
 }
 
+// Notifiers 返回已配置的通知器
+func (m *Manager) Notifiers() []Notifier {
+	return m.notifiers
+}
+
+// Stats 返回发送统计
+func (m *Manager) Stats() SendStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.stats
+}
+
 // NotifyAll 向所有启用的通知器发送通知
 // 每10个仓库合并成一条消息发送
 func (m *Manager) NotifyAll(releases []*github.ReleaseInfo) []error {
-	var errors []error
-	ctx := context.Background()
+	return m.NotifyAllContext(context.Background(), releases)
+}
 
-	// 每条消息包含10个仓库的更新
-	const releasesPerMessage = 10
-	// 钉钉限制：每分钟最多20条消息
-	// 每批发送15条消息（包含150个仓库的更新），然后等待1分钟
-	const messagesPerBatch = 15
-	const batchTimeout = 2 * time.Minute
-	const waitBetweenBatches = 65 * time.Second
+// NotifyAllContext 与 NotifyAll 相同，ctx取消后不再等待发送速率，
+// 尚未发出的通知放入失败队列，与已发送的消息计数一起保存，下次运行开始时重发
+// 启用发送审批时只发送汇总到审批渠道，批准后才发送到其他渠道
+func (m *Manager) NotifyAllContext(ctx context.Context, releases []*github.ReleaseInfo) []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// 计算需要发送的消息数量
-	totalMessages := (len(releases) + releasesPerMessage - 1) / releasesPerMessage
-	log.Printf("开始发送通知: %d 个仓库更新，合并为 %d 条消息", len(releases), totalMessages)
+	assignKeys(releases)
+	m.redact(releases)
 
-	messagesSent := 0
+	if m.approval != nil {
+		return m.requestApproval(ctx, releases)
+	}
+	return m.broadcast(ctx, releases)
+}
 
-	// 按每10个仓库一组进行分组
-	for i := 0; i < len(releases); i += releasesPerMessage {
-		end := i + releasesPerMessage
-		if end > len(releases) {
-			end = len(releases)
+// assignKeys 为尚未设置稳定标识的版本设置 Key，下游系统据此去重和关联同一版本的消息
+func assignKeys(releases []*github.ReleaseInfo) {
+	for _, release := range releases {
+		if release.Key == "" {
+			release.Key = release.EventKey()
 		}
+	}
+}
 
-		group := releases[i:end]
-		messagesSent++
+// broadcast 发送到所有正式通知的渠道
+func (m *Manager) broadcast(ctx context.Context, releases []*github.ReleaseInfo) []error {
+	// 暂停期间不发送，放入队列等待恢复
+	if until, paused := m.PausedUntil(); paused {
+		log.Printf("通知已暂停（%s），%d 个仓库更新已加入队列，恢复后发送", DescribePause(until), len(releases))
+		m.hold(releases)
+		return nil
+	}
 
-		batchCtx, cancel := context.WithTimeout(ctx, batchTimeout)
-		batchErrors := m.sendBatchMessage(batchCtx, group)
-		cancel()
+	var errors []error
+	m.shorten(releases)
 
-		errors = append(errors, batchErrors...)
+	// 每条消息包含10个仓库的更新，发送速率由各渠道的令牌桶控制
+	const releasesPerMessage = 10
 
-		// 每发送 messagesPerBatch 条消息后，等待一段时间
-		if messagesSent%messagesPerBatch == 0 && messagesSent < totalMessages {
-			log.Printf("已发送 %d/%d 条消息，等待后继续...", messagesSent, totalMessages)
-			time.Sleep(waitBetweenBatches)
-		} else if messagesSent < totalMessages {
-			// 消息之间的间隔（避免过快）
-			time.Sleep(4 * time.Second)
+	// 先按路由规则选出每个渠道的版本，再按每10个仓库一组分组，渠道只收到分给它的版本时不会拆成多条零散的消息
+	parts := m.partitionReleases(releases)
+	batches := make(map[string][][]*github.ReleaseInfo, len(m.notifiers))
+	totalMessages := 0
+	for _, n := range m.notifiers {
+		var groups [][]*github.ReleaseInfo
+		for _, part := range parts {
+			groups = append(groups, chunkReleases(m.routeReleases(n.Name(), part), releasesPerMessage)...)
 		}
+		batches[n.Name()] = groups
+		totalMessages = max(totalMessages, len(groups))
+	}
+	log.Printf("开始发送通知: %d 个仓库更新，每个渠道最多合并为 %d 条消息", len(releases), totalMessages)
+
+	m.pacer.SetContext(ctx)
+	defer m.pacer.SetContext(nil)
+
+	// 各渠道轮流发送第 i 条消息
+	run := m.newRunContext(len(releases), totalMessages)
+	for i := 0; i < totalMessages; i++ {
+		errors = append(errors, m.sendBatchMessage(ctx, batches, i, run)...)
 	}
 
+	// 超过每日上限的版本合并为一条摘要发送
+	errors = append(errors, m.sendDigests(ctx, run)...)
+
+	if err := ctx.Err(); err != nil {
+		log.Printf("发送已中断，未发送的通知已放入失败队列（%d 条），下次运行时重发", m.outbox.Len())
+		errors = append(errors, fmt.Errorf("发送已中断: %v", err))
+	}
+
+	m.saveOutbox()
+	m.saveDaily()
 	return errors
 }
 
-// sendBatchMessage 发送一条合并消息（包含多个仓库更新）
-func (m *Manager) sendBatchMessage(ctx context.Context, releases []*github.ReleaseInfo) []error {
+// overDailyLimit 渠道今天发送的消息是否已达到上限
+func (m *Manager) overDailyLimit(channel string) bool {
+	limit := m.dailyLimits[channel]
+	return limit > 0 && m.daily.Count(channel) >= limit
+}
+
+// sendDigests 将各渠道超过每日上限的版本合并为一条"今天还有 N 个新版本"的摘要发送
+func (m *Manager) sendDigests(ctx context.Context, run render.RunContext) []error {
 	var errors []error
 
 	for _, n := range m.notifiers {
-		if !n.IsEnabled() {
+		releases := m.overflow[n.Name()]
+		if len(releases) == 0 {
 			continue
 		}
+		delete(m.overflow, n.Name())
 
-		// 使用标准库限流器等待令牌
-		err := m.limiter.Wait(ctx)
-		if err != nil {
-			errors = append(errors, fmt.Errorf("限流等待错误: %v", err))
+		log.Printf("渠道 %s 今天已发送 %d 条消息，达到每日上限，剩余 %d 个版本合并为摘要发送",
+			n.Name(), m.daily.Count(n.Name()), len(releases))
+
+		// 不支持摘要的渠道放入失败队列，明天的限额内重发，这些版本已记录为通知过，不能丢弃
+		sender, ok := n.(DigestSender)
+		if !ok {
+			log.Printf("渠道 %s 不支持摘要消息，%d 个版本已加入队列，明天重发", n.Name(), len(releases))
+			m.outbox.Add(n.Name(), releases, errDailyLimit)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			m.outbox.Add(n.Name(), releases, err)
 			continue
 		}
 
-		// 发送批量通知
-		if err := n.SendBatch(releases); err != nil {
-			// 检查是否是速率限制错误
-			if isRateLimitError(err) {
-				log.Printf("警告: 遇到速率限制 - %v", err)
-				time.Sleep(5 * time.Second)
-				errors = append(errors, fmt.Errorf("速率限制: %v", err))
-			} else {
-				log.Printf("发送失败 - %v", err)
-				errors = append(errors, err)
-			}
+		run.Channel = n.Name()
+		run.Locale = m.localeFor(n.Name())
+		run.Index, run.Of = 0, 0
+		if err := sender.SendDigest(releases, run); err != nil {
+			log.Printf("发送摘要失败 - %v", err)
+			errors = append(errors, err)
+			m.outbox.Add(n.Name(), releases, err)
+			m.stats.Failures++
+			continue
 		}
+		m.daily.Add(n.Name())
+		m.stats.Messages++
 	}
 
 	return errors
 }
 
-// isRateLimitError 判断是否是速率限制错误
-func isRateLimitError(err error) bool {
-	errMsg := err.Error()
-	return strings.Contains(errMsg, "频率超过限制") ||
-		strings.Contains(errMsg, "rate limit") ||
-		strings.Contains(errMsg, "too many requests")
+// saveDaily 保存每日消息计数
+func (m *Manager) saveDaily() {
+	if err := m.daily.Save(); err != nil {
+		log.Printf("警告: %v", err)
+	}
 }
 
-// RenderTemplate 渲染通知模板
-func RenderTemplate(tmpl *template.Template, release *github.ReleaseInfo) (string, error) {
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, release); err != nil {
-		return "", err
-	}
-	return buf.String(), nil
+// DrainOutbox 优先重发上次运行中发送失败的通知
+// 按渠道分组，每个渠道仍然遵守合并发送和速率限制，重发失败的通知会重新放回队列
+func (m *Manager) DrainOutbox() []error {
+	return m.DrainOutboxContext(context.Background())
 }
 
-// AddDingTalkNotifier 添加钉钉通知器
-func (m *Manager) AddDingTalkNotifier(config dingtalk.Config) error {
-	if !config.Enabled {
+// DrainOutboxContext 与 DrainOutbox 相同，ctx取消后尚未重发的通知原样保留在队列中
+func (m *Manager) DrainOutboxContext(ctx context.Context) []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// 暂停期间保留队列，恢复后再发送
+	if _, paused := m.PausedUntil(); paused {
 		return nil
 	}
 
-	notifier, err := dingtalk.New(config, m.template)
-	if err != nil {
-		return err
+	entries := m.outbox.Take()
+	if len(entries) == 0 {
+		return nil
 	}
 
-	m.notifiers = append(m.notifiers, notifier)
-	return nil
+	log.Printf("发现 %d 条上次发送失败的通知，优先重发...", len(entries))
+
+	byChannel := make(map[string][]OutboxEntry)
+	for _, entry := range entries {
+		byChannel[entry.Channel] = append(byChannel[entry.Channel], entry)
+	}
+
+	var errors []error
+	const releasesPerMessage = 10
+
+	m.pacer.SetContext(ctx)
+	defer m.pacer.SetContext(nil)
+
+	for _, n := range m.notifiers {
+		pending := byChannel[n.Name()]
+		delete(byChannel, n.Name())
+		if len(pending) == 0 {
+			continue
+		}
+		// 渠道暂时不可用或发送已中断，保留到下次运行
+		if !n.IsEnabled() || ctx.Err() != nil {
+			m.outbox.Restore(pending)
+			continue
+		}
+		// 不在渠道的发送时间段内，继续排队
+		if opens, outside := m.outsideWindow(n.Name()); outside {
+			log.Printf("渠道 %s 不在发送时间段内，%d 条通知继续排队到 %s", n.Name(), len(pending), opens.Format(time.DateTime))
+			m.outbox.Restore(pending)
+			continue
+		}
+
+		run := m.newRunContext(len(pending), (len(pending)+releasesPerMessage-1)/releasesPerMessage)
+		run.Channel = n.Name()
+		run.Locale = m.localeFor(n.Name())
+
+		for i := 0; i < len(pending); i += releasesPerMessage {
+			run.Index = i/releasesPerMessage + 1
+			end := i + releasesPerMessage
+			if end > len(pending) {
+				end = len(pending)
+			}
+			group := pending[i:end]
+			if ctx.Err() != nil {
+				m.outbox.Restore(group)
+				continue
+			}
+
+			releases := make([]*github.ReleaseInfo, 0, len(group))
+			for _, entry := range group {
+				releases = append(releases, entry.Release)
+			}
+			assignKeys(releases)
+			m.redact(releases)
+
+			// 与正常发送相同，达到每日上限后合并为摘要，不支持摘要的渠道继续排队到明天
+			if m.overDailyLimit(n.Name()) {
+				m.overflow[n.Name()] = append(m.overflow[n.Name()], releases...)
+				continue
+			}
+			m.shorten(releases)
+
+			run.AckID = m.ackID(n.Name(), releases)
+			if err := n.SendBatch(releases, run); err != nil {
+				// 等待发送速率时被中断，不计入重试次数
+				if ctx.Err() != nil {
+					m.outbox.Restore(group)
+					continue
+				}
+				log.Printf("重发失败 [%s] - %v", n.Name(), err)
+				m.outbox.Requeue(group, err)
+				errors = append(errors, err)
+				m.stats.Failures++
+				continue
+			}
+			log.Printf("已重发 %d 条通知到 %s", len(releases), n.Name())
+			m.recordAcks(n.Name(), run.AckID, releases)
+			m.daily.Add(n.Name())
+			m.stats.Messages++
+		}
+	}
+
+	// 渠道已不再启用，丢弃对应的通知
+	for channel, pending := range byChannel {
+		log.Printf("渠道 %s 已不再启用，丢弃 %d 条待重发的通知", channel, len(pending))
+	}
+
+	// 超过每日上限的通知合并为一条摘要发送
+	errors = append(errors, m.sendDigests(ctx, m.newRunContext(len(entries), 0))...)
+
+	if err := ctx.Err(); err != nil {
+		log.Printf("重发已中断，%d 条通知保留在失败队列中", m.outbox.Len())
+		errors = append(errors, fmt.Errorf("重发已中断: %v", err))
+	}
+
+	m.saveOutbox()
+	m.saveDaily()
+	return errors
+}
+
+// newRunContext 创建本次发送的运行上下文
+func (m *Manager) newRunContext(total, messages int) render.RunContext {
+	loc, err := time.LoadLocation(m.timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return render.RunContext{
+		Timestamp:  m.clock.Now().In(loc),
+		Total:      total,
+		Of:         messages,
+		Timezone:   m.timezone,
+		TimeFormat: m.format.TimeFormat,
+		Locale:     m.format.Locale,
+		Failures:   m.renderFailures,
+	}
+}
+
+// TakeRenderFailures 返回并清空上次取出以来模板渲染失败、改用简化内容发送的版本
+func (m *Manager) TakeRenderFailures() []render.Failure {
+	return m.renderFailures.Take()
+}
+
+// PruneOutbox 丢弃失败队列中 before 之前加入或最后一次重发失败的通知并保存，返回丢弃的通知数
+func (m *Manager) PruneOutbox(before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := m.outbox.Prune(before)
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, m.outbox.Save()
+}
+
+// saveOutbox 保存失败通知队列
+func (m *Manager) saveOutbox() {
+	if err := m.outbox.Save(); err != nil {
+		log.Printf("警告: %v", err)
+	}
+}
+
+// partitionReleases 将版本列表分为按顺序发送的几部分，每部分单独分组
+// 启用escalate时，命中高亮关键字的版本排在最前面；参与贡献的仓库的版本随后
+// 每一部分内按仓库的重要性分数从高到低排列，达到每日上限时分数低的版本进入摘要
+func (m *Manager) partitionReleases(releases []*github.ReleaseInfo) [][]*github.ReleaseInfo {
+	var highlighted, contributed, normal []*github.ReleaseInfo
+	for _, release := range releases {
+		switch {
+		case m.escalate && release.IsHighlighted():
+			highlighted = append(highlighted, release)
+		case release.Contributed:
+			contributed = append(contributed, release)
+		default:
+			normal = append(normal, release)
+		}
+	}
+	if len(highlighted) > 0 {
+		log.Printf("%d 个版本命中高亮关键字，优先发送", len(highlighted))
+	}
+	if len(contributed) > 0 {
+		log.Printf("%d 个版本来自你参与贡献的仓库，优先发送", len(contributed))
+	}
+	sortByScore(highlighted)
+	sortByScore(contributed)
+	sortByScore(normal)
+	return [][]*github.ReleaseInfo{highlighted, contributed, normal}
+}
+
+// sortByScore 按仓库的重要性分数从高到低排列，分数相同（如未开启评分）时保持原有顺序
+func sortByScore(releases []*github.ReleaseInfo) {
+	sort.SliceStable(releases, func(i, j int) bool { return releases[i].Score > releases[j].Score })
+}
+
+// chunkReleases 按固定大小切分版本列表
+func chunkReleases(releases []*github.ReleaseInfo, size int) [][]*github.ReleaseInfo {
+	var groups [][]*github.ReleaseInfo
+	for i := 0; i < len(releases); i += size {
+		end := i + size
+		if end > len(releases) {
+			end = len(releases)
+		}
+		groups = append(groups, releases[i:end])
+	}
+	return groups
+}
+
+// sendBatchMessage 向每个渠道发送它的第 index 条合并消息（包含多个仓库更新），batches 为按路由规则分给各渠道的消息
+// ctx已取消时不再发送，直接放入失败队列
+func (m *Manager) sendBatchMessage(ctx context.Context, batches map[string][][]*github.ReleaseInfo, index int, run render.RunContext) []error {
+	var errors []error
+
+	for _, n := range m.notifiers {
+		// 路由规则分给该渠道的消息已发送完
+		groups := batches[n.Name()]
+		if index >= len(groups) {
+			continue
+		}
+		releases := groups[index]
+		if !n.IsEnabled() {
+			// 渠道在运行中被停用（如配置错误），放入失败队列，修复配置后重发
+			m.outbox.Add(n.Name(), releases, errChannelDisabled)
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			m.outbox.Add(n.Name(), releases, err)
+			continue
+		}
+
+		// 不在渠道的发送时间段内，放入队列，时间段开始后发送
+		if opens, outside := m.outsideWindow(n.Name()); outside {
+			log.Printf("渠道 %s 不在发送时间段内，%d 个版本已加入队列，将在 %s 发送",
+				n.Name(), len(releases), opens.Format(time.DateTime))
+			m.outbox.Add(n.Name(), releases, errOutsideWindow)
+			continue
+		}
+
+		// 今天的消息数已达到上限，留到本次运行结束时合并为摘要
+		if m.overDailyLimit(n.Name()) {
+			m.overflow[n.Name()] = append(m.overflow[n.Name()], releases...)
+			continue
+		}
+
+		// 发送批量通知，消息序号和总数按该渠道实际收到的消息计算
+		total := 0
+		for _, group := range groups {
+			total += len(group)
+		}
+		run.Total, run.Index, run.Of = total, index+1, len(groups)
+		run.Channel = n.Name()
+		run.Locale = m.localeFor(n.Name())
+		run.AckID = m.ackID(n.Name(), releases)
+		if err := n.SendBatch(releases, run); err != nil {
+			// 检查是否是速率限制错误
+			if ctx.Err() != nil {
+				// 等待发送速率时被中断，不再单独报告
+			} else if isRateLimitError(err) {
+				log.Printf("警告: 遇到速率限制 - %v", err)
+				clock.Sleep(ctx, m.clock, 5*time.Second)
+				errors = append(errors, fmt.Errorf("速率限制: %v", err))
+			} else {
+				log.Printf("发送失败 - %v", err)
+				errors = append(errors, err)
+			}
+			// 放入失败队列，下次运行开始时重发
+			m.outbox.Add(n.Name(), releases, err)
+			m.stats.Failures++
+			continue
+		}
+		m.daily.Add(n.Name())
+		m.stats.Messages++
+		m.recordAcks(n.Name(), run.AckID, releases)
+	}
+
+	return errors
+}
+
+// isRateLimitError 判断是否是速率限制错误
+func isRateLimitError(err error) bool {
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "频率超过限制") ||
+		strings.Contains(errMsg, "rate limit") ||
+		strings.Contains(errMsg, "too many requests")
+}
+
+// RenderTemplate 渲染通知模板
+func RenderTemplate(tmpl *template.Template, release *github.ReleaseInfo, run render.RunContext) (string, error) {
+	return render.Execute(tmpl, release, run)
+}
+
+// AddDingTalkNotifier 添加钉钉通知器
+func (m *Manager) AddDingTalkNotifier(config dingtalk.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	name := config.Name
+	if name == "" {
+		name = "dingtalk"
+	}
+	config.Bucket = m.pacer.Bucket(name, config.Pace())
+	config.Clock = m.clock
+	notifier, err := dingtalk.New(config, m.templateFor(name))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
 }
 
 // AddTelegramNotifier 添加Telegram通知器
@@ -202,7 +1193,368 @@ func (m *Manager) AddTelegramNotifier(config telegram.Config) error {
 		return nil
 	}
 
-	notifier, err := telegram.New(config, m.template)
+	name := config.Name
+	if name == "" {
+		name = "telegram"
+	}
+	config.Bucket = m.pacer.Bucket(name, telegram.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := telegram.New(config, m.templateFor(name))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddSlackNotifier 添加Slack通知器
+func (m *Manager) AddSlackNotifier(config slack.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("slack", slack.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := slack.New(config, m.templateFor("slack"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddEmailNotifier 添加邮件通知器
+func (m *Manager) AddEmailNotifier(config email.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("email", email.DefaultPace)
+	notifier, err := email.New(config, m.templateFor("email"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddWeComNotifier 添加企业微信通知器
+func (m *Manager) AddWeComNotifier(config wecom.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("wecom", wecom.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := wecom.New(config, m.templateFor("wecom"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddFeishuNotifier 添加飞书通知器
+func (m *Manager) AddFeishuNotifier(config feishu.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("feishu", feishu.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := feishu.New(config, m.templateFor("feishu"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddWebhookNotifier 添加通用webhook通知器
+func (m *Manager) AddWebhookNotifier(config webhook.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("webhook", webhook.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := webhook.New(config, m.template)
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddNtfyNotifier 添加ntfy通知器
+func (m *Manager) AddNtfyNotifier(config ntfy.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("ntfy", ntfy.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := ntfy.New(config, m.templateFor("ntfy"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddTeamsNotifier 添加Microsoft Teams通知器
+func (m *Manager) AddTeamsNotifier(config teams.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("teams", teams.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := teams.New(config, m.templateFor("teams"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddDesktopNotifier 添加桌面通知器
+func (m *Manager) AddDesktopNotifier(config desktop.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := desktop.New(config, m.templateFor("desktop"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddMQTTNotifier 添加MQTT通知器
+func (m *Manager) AddMQTTNotifier(config mqtt.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := mqtt.New(config, m.templateFor("mqtt"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddFaultNotifier 添加故障注入通知器，仅用于测试重试和失败队列
+func (m *Manager) AddFaultNotifier(config fault.Config) error {
+	notifier, err := fault.New(config, m.templateFor("fault"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddRocketChatNotifier 添加Rocket.Chat通知器
+func (m *Manager) AddRocketChatNotifier(config rocketchat.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("rocketchat", rocketchat.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := rocketchat.New(config, m.templateFor("rocketchat"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddGoogleChatNotifier 添加Google Chat通知器
+func (m *Manager) AddGoogleChatNotifier(config googlechat.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("googlechat", googlechat.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := googlechat.New(config, m.templateFor("googlechat"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddIRCNotifier 添加IRC通知器
+func (m *Manager) AddIRCNotifier(config irc.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("irc", irc.DefaultPace)
+	notifier, err := irc.New(config, m.templateFor("irc"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AtomFeedPath 返回Atom订阅文件的路径
+func AtomFeedPath(cfg *config.Config) (string, error) {
+	return util.ResolvePath(cfg.Notifications.Atom.Path, "releases.atom", cfg.DataSuffix())
+}
+
+// AddAtomNotifier 添加Atom订阅输出
+func (m *Manager) AddAtomNotifier(config atom.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := atom.New(config, m.templateFor("atom"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddPushbulletNotifier 添加Pushbullet通知器
+func (m *Manager) AddPushbulletNotifier(config pushbullet.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("pushbullet", pushbullet.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := pushbullet.New(config, m.templateFor("pushbullet"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddSyslogNotifier 添加syslog输出
+func (m *Manager) AddSyslogNotifier(config syslog.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := syslog.New(config, m.templateFor("syslog"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddStdoutNotifier 添加标准输出
+func (m *Manager) AddStdoutNotifier(config stdout.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := stdout.New(config, nil)
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddMastodonNotifier 添加Mastodon通知器
+func (m *Manager) AddMastodonNotifier(config mastodon.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("mastodon", mastodon.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := mastodon.New(config, m.templateFor("mastodon"))
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddKafkaNotifier 添加Kafka通知器
+func (m *Manager) AddKafkaNotifier(config kafka.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := kafka.New(config, nil)
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddPagerDutyNotifier 添加PagerDuty通知器
+func (m *Manager) AddPagerDutyNotifier(config pagerduty.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := pagerduty.New(config, nil)
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddOpsgenieNotifier 添加Opsgenie通知器
+func (m *Manager) AddOpsgenieNotifier(config opsgenie.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	notifier, err := opsgenie.New(config, nil)
+	if err != nil {
+		return err
+	}
+
+	m.notifiers = append(m.notifiers, notifier)
+	return nil
+}
+
+// AddWebexNotifier 添加Webex通知器
+func (m *Manager) AddWebexNotifier(config webex.Config) error {
+	if !config.Enabled {
+		return nil
+	}
+
+	config.Bucket = m.pacer.Bucket("webex", webex.DefaultPace)
+	config.Clock = m.clock
+	notifier, err := webex.New(config, m.templateFor("webex"))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,190 @@
+package notifier
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// fakeNotifier 记录收到的批量消息，err 不为nil时发送失败
+type fakeNotifier struct {
+	name    string
+	batches [][]*github.ReleaseInfo
+	runs    []render.RunContext
+	err     error
+}
+
+func (n *fakeNotifier) Name() string    { return n.name }
+func (n *fakeNotifier) IsEnabled() bool { return true }
+
+func (n *fakeNotifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.SendBatch([]*github.ReleaseInfo{release}, run)
+}
+
+func (n *fakeNotifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.batches = append(n.batches, releases)
+	n.runs = append(n.runs, run)
+	return nil
+}
+
+// tags 返回收到的全部版本标签
+func (n *fakeNotifier) tags() []string {
+	var tags []string
+	for _, batch := range n.batches {
+		for _, r := range batch {
+			tags = append(tags, r.Repository+"@"+r.TagName)
+		}
+	}
+	return tags
+}
+
+// digestNotifier 支持摘要消息的 fakeNotifier
+type digestNotifier struct {
+	fakeNotifier
+	digests [][]*github.ReleaseInfo
+}
+
+func (n *digestNotifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	n.digests = append(n.digests, releases)
+	return nil
+}
+
+// newTestManager 创建不含真实渠道的通知管理器，数据文件写入临时目录
+func newTestManager(t *testing.T, cfg *config.Config, clk clock.Clock, notifiers ...Notifier) *Manager {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	cfg.GitHub.Timezone = "UTC"
+	m, err := NewManagerWithClock(cfg, clk)
+	if err != nil {
+		t.Fatalf("创建通知管理器失败: %v", err)
+	}
+	m.notifiers = append(m.notifiers, notifiers...)
+	return m
+}
+
+// testReleases 返回 o/repo1..o/repoN 的 v1.0.0 版本
+func testReleases(n int) []*github.ReleaseInfo {
+	releases := make([]*github.ReleaseInfo, 0, n)
+	for i := 1; i <= n; i++ {
+		releases = append(releases, &github.ReleaseInfo{Event: github.EventRelease, Owner: "o", Repository: fmt.Sprintf("repo%d", i), TagName: "v1.0.0"})
+	}
+	return releases
+}
+
+// TestDailyLimit_Overflow 不支持摘要的渠道超过每日上限的版本排队到第二天，重发同样遵守每日上限
+func TestDailyLimit_Overflow(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC))
+	plain := &fakeNotifier{name: "plain"}
+	m := newTestManager(t, &config.Config{}, clk, plain)
+	m.dailyLimits["plain"] = 1
+
+	m.NotifyAll(testReleases(12))
+	if len(plain.batches) != 1 || len(plain.batches[0]) != 10 {
+		t.Fatalf("收到 %d 条消息，期望 1 条包含10个版本的消息", len(plain.batches))
+	}
+	if n := m.outbox.Len(); n != 2 {
+		t.Fatalf("失败队列中有 %d 个版本，期望超过上限的 2 个", n)
+	}
+
+	// 当天重发不超过每日上限
+	if errs := m.DrainOutbox(); len(errs) != 0 {
+		t.Fatalf("重发失败: %v", errs)
+	}
+	if len(plain.batches) != 1 || m.outbox.Len() != 2 {
+		t.Fatalf("当天重发了 %d 条消息，队列中剩余 %d 个版本，期望不重发", len(plain.batches)-1, m.outbox.Len())
+	}
+
+	// 第二天的限额内重发
+	clk.Advance(24 * time.Hour)
+	m.DrainOutbox()
+	if len(plain.batches) != 2 || len(plain.batches[1]) != 2 || m.outbox.Len() != 0 {
+		t.Fatalf("第二天收到 %d 条消息，队列中剩余 %d 个版本，期望重发剩余的 2 个版本", len(plain.batches), m.outbox.Len())
+	}
+}
+
+// TestDailyLimit_DrainDigest 重发时达到每日上限的渠道改为发送摘要
+func TestDailyLimit_DrainDigest(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC))
+	digest := &digestNotifier{fakeNotifier: fakeNotifier{name: "digest"}}
+	m := newTestManager(t, &config.Config{}, clk, digest)
+	m.dailyLimits["digest"] = 1
+
+	m.outbox.Add("digest", testReleases(12), fmt.Errorf("网络错误"))
+	m.DrainOutbox()
+	if len(digest.batches) != 1 || len(digest.batches[0]) != 10 {
+		t.Fatalf("收到 %d 条消息，期望 1 条包含10个版本的消息", len(digest.batches))
+	}
+	if len(digest.digests) != 1 || len(digest.digests[0]) != 2 {
+		t.Fatalf("收到 %d 条摘要，期望 1 条包含剩余 2 个版本的摘要", len(digest.digests))
+	}
+	if n := m.outbox.Len(); n != 0 {
+		t.Errorf("失败队列中剩余 %d 个版本", n)
+	}
+}
+
+// TestRouting 测试第一条匹配的规则生效、类型名称包含所有实例、没有规则匹配的版本发送到所有渠道
+func TestRouting(t *testing.T) {
+	ops := &fakeNotifier{name: "dingtalk/ops"}
+	dev := &fakeNotifier{name: "dingtalk/dev"}
+	slack := &fakeNotifier{name: "slack"}
+	cfg := &config.Config{
+		ChannelGroups: map[string][]string{"chat": {"slack"}},
+		Routes: []config.RouteConfig{
+			{Repos: []string{"o/critical*"}, Channels: []string{"dingtalk"}},
+			{Repos: []string{"o/*"}, Channels: []string{"chat"}},
+		},
+	}
+	m := newTestManager(t, cfg, clock.NewFake(time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)), ops, dev, slack)
+
+	releases := []*github.ReleaseInfo{
+		{Event: github.EventRelease, Owner: "o", Repository: "critical-api", TagName: "v1.0.0"},
+		{Event: github.EventRelease, Owner: "o", Repository: "web", TagName: "v2.0.0"},
+		{Event: github.EventRelease, Owner: "x", Repository: "lib", TagName: "v3.0.0"},
+	}
+	if errs := m.NotifyAll(releases); len(errs) != 0 {
+		t.Fatalf("发送失败: %v", errs)
+	}
+
+	for _, tc := range []struct {
+		n    *fakeNotifier
+		want string
+	}{
+		{ops, "[critical-api@v1.0.0 lib@v3.0.0]"},
+		{dev, "[critical-api@v1.0.0 lib@v3.0.0]"},
+		{slack, "[web@v2.0.0 lib@v3.0.0]"},
+	} {
+		if got := fmt.Sprint(tc.n.tags()); got != tc.want {
+			t.Errorf("%s 收到 %s，期望 %s", tc.n.name, got, tc.want)
+		}
+	}
+}
+
+// TestRouting_Batches 先按路由选出渠道的版本再分组，分散在多个分组中的少量版本合并为一条消息
+func TestRouting_Batches(t *testing.T) {
+	all := &fakeNotifier{name: "slack"}
+	few := &fakeNotifier{name: "ntfy"}
+	cfg := &config.Config{Routes: []config.RouteConfig{{Repos: []string{"o/repo1", "o/repo15", "o/repo25"}, Channels: []string{"slack", "ntfy"}}, {Repos: []string{"o/*"}, Channels: []string{"slack"}}}}
+	m := newTestManager(t, cfg, clock.NewFake(time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)), all, few)
+
+	m.NotifyAll(testReleases(25))
+	if len(all.batches) != 3 {
+		t.Errorf("slack 收到 %d 条消息，期望 3 条", len(all.batches))
+	}
+	if len(few.batches) != 1 || len(few.batches[0]) != 3 {
+		t.Fatalf("ntfy 收到 %d 条消息，期望 1 条包含3个版本的消息", len(few.batches))
+	}
+	if run := few.runs[0]; run.Index != 1 || run.Of != 1 || run.Total != 3 {
+		t.Errorf("ntfy 的消息序号为 %d/%d（共 %d 个版本），期望 1/1（共 3 个版本）", run.Index, run.Of, run.Total)
+	}
+	if run := all.runs[2]; run.Index != 3 || run.Of != 3 || run.Total != 25 {
+		t.Errorf("slack 的最后一条消息序号为 %d/%d（共 %d 个版本），期望 3/3（共 25 个版本）", run.Index, run.Of, run.Total)
+	}
+}
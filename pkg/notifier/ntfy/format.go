@@ -0,0 +1,49 @@
+package ntfy
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxMessageBytes ntfy消息正文的最大字节数，超出后服务端会将正文转为附件
+const maxMessageBytes = 4096
+
+// maxDigestItems 摘要消息中最多列出的版本数
+const maxDigestItems = 50
+
+// truncate 按字节数截断文本，不截断多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	n -= len("...")
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
+
+// buildDigestMessage 构建摘要消息正文
+func buildDigestMessage(releases []*github.ReleaseInfo, run render.RunContext) string {
+	var content bytes.Buffer
+	content.WriteString("今天的消息数已达到上限，以下版本合并发送：\n\n")
+
+	for i, release := range releases {
+		if i == maxDigestItems {
+			content.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		content.WriteString(fmt.Sprintf("- [%s/%s](%s) %s\n",
+			release.Owner, release.Repository, release.Link(), release.TagName))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString(fmt.Sprintf("\n%s", footer))
+	}
+
+	return truncate(content.String(), maxMessageBytes)
+}
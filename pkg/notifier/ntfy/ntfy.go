@@ -0,0 +1,278 @@
+package ntfy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultServerURL 默认的ntfy服务地址
+const DefaultServerURL = "https://ntfy.sh"
+
+// defaultCooldown 429响应没有给出 Retry-After 时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// 消息优先级，与ntfy的 1-5 对应
+var priorities = map[string]int{
+	"min":     1,
+	"low":     2,
+	"default": 3,
+	"high":    4,
+	"max":     5,
+	"urgent":  5,
+}
+
+// DefaultPace 默认发送速率
+// ntfy.sh 允许突发60条，之后每5秒补充1条；自建服务的限制通常更宽松
+var DefaultPace = pacing.Limit{Interval: 5 * time.Second, Burst: 10}
+
+// Config ntfy通知配置
+type Config struct {
+	Enabled bool
+	// ServerURL ntfy服务地址，默认为 https://ntfy.sh
+	ServerURL string
+	Topic     string
+	// Token 访问令牌，用于需要认证的主题
+	Token string
+	// Priority 消息优先级: min、low、default、high、max 或 1-5
+	Priority string
+	// Tags 消息标签，可以是emoji短代码（如 package）
+	Tags []string
+	// PreviewImage 是否附带仓库的OpenGraph预览图（作为外部附件）
+	PreviewImage bool
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier ntfy通知器
+type Notifier struct {
+	config   Config
+	priority int
+	template *template.Template
+	client   *http.Client
+	limiter  *pacing.Bucket // 速率限制器
+	mu       sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// message ntfy的JSON发布格式
+type message struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title,omitempty"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Click    string   `json:"click,omitempty"`
+	Attach   string   `json:"attach,omitempty"`
+	Filename string   `json:"filename,omitempty"`
+	Markdown bool     `json:"markdown,omitempty"`
+}
+
+// New 创建ntfy通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.Topic == "" {
+		return nil, fmt.Errorf("ntfy主题不能为空")
+	}
+
+	if config.ServerURL == "" {
+		config.ServerURL = DefaultServerURL
+	}
+	config.ServerURL = strings.TrimRight(config.ServerURL, "/")
+
+	priority, err := parsePriority(config.Priority)
+	if err != nil {
+		return nil, err
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("ntfy", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		priority: priority,
+		template: tmpl,
+		client:   client,
+		limiter:  limiter,
+	}, nil
+}
+
+// parsePriority 解析优先级名称或数字，为空时使用服务端默认优先级
+func parsePriority(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if p, ok := priorities[strings.ToLower(value)]; ok {
+		return p, nil
+	}
+	if p, err := strconv.Atoi(value); err == nil && p >= 1 && p <= 5 {
+		return p, nil
+	}
+	return 0, fmt.Errorf("不支持的ntfy优先级: %s（可选 min、low、default、high、max 或 1-5）", value)
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "ntfy"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送单个版本，以仓库名作为标题
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	return n.publish(n.releaseMessage(release, content))
+}
+
+// SendBatch 每个版本单独发送一条消息，手机上每条推送对应一个仓库
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	var failed int
+	var firstErr error
+	for _, release := range releases {
+		if err := n.Send(release, run); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d 条ntfy消息发送失败: %v", failed, len(releases), firstErr)
+	}
+	return nil
+}
+
+// SendDigest 将超过每日上限的版本合并为一条摘要消息发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.publish(message{
+		Topic:    n.config.Topic,
+		Title:    fmt.Sprintf("📦 今天还有 %d 个新版本", len(releases)),
+		Message:  buildDigestMessage(releases, run),
+		Priority: n.priority,
+		Tags:     n.config.Tags,
+		Markdown: true,
+	})
+}
+
+// releaseMessage 构建单个版本的消息，命中高亮关键字或参与贡献的仓库的版本至少使用 high 优先级
+func (n *Notifier) releaseMessage(release *github.ReleaseInfo, content string) message {
+	priority := n.priority
+	if (release.IsHighlighted() || release.Contributed) && priority < priorities["high"] {
+		priority = priorities["high"]
+	}
+
+	msg := message{
+		Topic:    n.config.Topic,
+		Title:    fmt.Sprintf("%s/%s", release.Owner, release.Repository),
+		Message:  truncate(content, maxMessageBytes),
+		Priority: priority,
+		Tags:     n.config.Tags,
+		Click:    release.Link(),
+		Markdown: true,
+	}
+	if image := release.OpenGraphImageURL(); n.config.PreviewImage && image != "" {
+		msg.Attach = image
+		msg.Filename = release.Repository + ".png"
+	}
+	return msg
+}
+
+// publish 发布消息到ntfy服务
+func (n *Notifier) publish(msg message) error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+	if remaining > 0 {
+		return fmt.Errorf("ntfy触发限流，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	// 发布到服务根地址时由JSON中的topic字段指定主题
+	req, err := http.NewRequest(http.MethodPost, n.config.ServerURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.Token)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送ntfy消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("ntfy触发限流，已设置%v冷却期: rate limit exceeded", wait)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		switch resp.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Errorf("ntfy认证失败，状态码: %d，请检查token和主题权限 (%s)", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		default:
+			return fmt.Errorf("ntfy请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		}
+	}
+
+	return nil
+}
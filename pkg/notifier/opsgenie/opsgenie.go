@@ -0,0 +1,237 @@
+package opsgenie
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// 各区域的告警接口地址
+const (
+	DefaultAPIURL = "https://api.opsgenie.com/v2/alerts"
+	EUAPIURL      = "https://api.eu.opsgenie.com/v2/alerts"
+)
+
+// DefaultPriority 默认告警优先级（最低），只提醒值班人员安排升级，不触发紧急呼叫
+const DefaultPriority = "P5"
+
+// maxMessageLength 告警标题的最大字符数（Opsgenie限制）
+const maxMessageLength = 130
+
+// source 告警的来源，显示在告警详情中
+const source = "notify"
+
+// Config Opsgenie 告警配置
+type Config struct {
+	Enabled bool
+	// APIKey API集成的密钥
+	APIKey string
+	// Repos 需要创建告警的仓库（owner/repo，支持 * 通配），只有这些仓库的新版本会创建告警
+	Repos []string
+	// Priority 告警优先级: P1~P5，默认 P5
+	Priority string
+	// Region 账号所在区域: us（默认）、eu
+	Region string
+	// Tags 附加到告警的标签
+	Tags []string
+	// APIURL 告警接口地址，为空时按区域选择
+	APIURL string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+}
+
+// Notifier Opsgenie 通知器，关键依赖发布新版本时创建告警，提醒值班人员安排升级
+// 同一版本使用相同的别名，告警未关闭时重复发送只会增加计数
+type Notifier struct {
+	config Config
+	client *http.Client
+}
+
+// New 创建Opsgenie通知器
+// 告警内容按版本信息生成，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("Opsgenie的 api_key 不能为空")
+	}
+	if len(config.Repos) == 0 {
+		return nil, fmt.Errorf("Opsgenie需要配置创建告警的仓库（repos）")
+	}
+	switch config.Priority {
+	case "":
+		config.Priority = DefaultPriority
+	case "P1", "P2", "P3", "P4", "P5":
+	default:
+		return nil, fmt.Errorf("不支持的Opsgenie告警优先级: %s（可选 P1~P5）", config.Priority)
+	}
+	if config.APIURL == "" {
+		switch config.Region {
+		case "", "us":
+			config.APIURL = DefaultAPIURL
+		case "eu":
+			config.APIURL = EUAPIURL
+		default:
+			return nil, fmt.Errorf("不支持的Opsgenie区域: %s（可选 us、eu）", config.Region)
+		}
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	return &Notifier{config: config, client: client}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "opsgenie"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 仓库匹配时为新版本创建告警，其他版本和事件类型直接跳过
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	if !release.IsNewVersion() || !release.MatchesRepo(n.config.Repos) {
+		return nil
+	}
+	return n.create(n.buildAlert(release))
+}
+
+// SendBatch 每个匹配的版本单独创建一个告警
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	var sent, failed int
+	var firstErr error
+	for _, release := range releases {
+		if !release.IsNewVersion() || !release.MatchesRepo(n.config.Repos) {
+			continue
+		}
+		sent++
+		if err := n.create(n.buildAlert(release)); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d 个Opsgenie告警创建失败: %v", failed, sent, firstErr)
+	}
+	return nil
+}
+
+// alert 创建告警请求
+type alert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Entity      string            `json:"entity"`
+	Source      string            `json:"source"`
+	Priority    string            `json:"priority"`
+}
+
+// buildAlert 根据版本信息构建告警，别名为 notify/owner/repo@tag
+func (n *Notifier) buildAlert(release *github.ReleaseInfo) alert {
+	repo := release.Owner + "/" + release.Repository
+
+	var desc strings.Builder
+	if label := release.EventLabel(); label != "" {
+		fmt.Fprintf(&desc, "%s\n", label)
+	}
+	fmt.Fprintf(&desc, "%s 发布了新版本 %s，需要安排升级。\n", repo, release.TagName)
+	if release.PinnedVersion != "" {
+		fmt.Fprintf(&desc, "当前使用版本: %s", release.PinnedVersion)
+		if release.VersionGap != "" {
+			fmt.Fprintf(&desc, "（%s）", release.VersionGap)
+		}
+		desc.WriteString("\n")
+	}
+	fmt.Fprintf(&desc, "发布页面: %s\n", release.Link())
+	if compare := release.CompareLink(); compare != "" {
+		fmt.Fprintf(&desc, "版本对比: %s\n", compare)
+	}
+
+	details := map[string]string{"repository": repo, "version": release.TagName, "url": release.Link()}
+	if release.PreviousTag != "" {
+		details["previous_version"] = release.PreviousTag
+	}
+	if len(release.Highlights) > 0 {
+		details["highlights"] = strings.Join(release.Highlights, ", ")
+	}
+
+	return alert{
+		Message:     truncate(fmt.Sprintf("%s %s 需要升级", repo, release.TagName), maxMessageLength),
+		Alias:       fmt.Sprintf("notify/%s@%s", repo, release.TagName),
+		Description: desc.String(),
+		Tags:        n.config.Tags,
+		Details:     details,
+		Entity:      repo,
+		Source:      source,
+		Priority:    n.config.Priority,
+	}
+}
+
+// create 发送创建告警请求，Opsgenie异步处理请求，接受后返回 202
+func (n *Notifier) create(a alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("序列化告警失败: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.config.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+n.config.APIKey)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送Opsgenie告警失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("Opsgenie触发限流: rate limit exceeded")
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		// 错误时返回 {"message":"...","took":0.001,"requestId":"..."}
+		var response struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(respBody, &response) == nil && response.Message != "" {
+			return fmt.Errorf("Opsgenie返回错误，状态码: %d (%s)", resp.StatusCode, response.Message)
+		}
+		return fmt.Errorf("Opsgenie请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// truncate 按字符数截断文本
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-3]) + "..."
+}
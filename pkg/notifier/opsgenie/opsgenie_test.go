@@ -0,0 +1,86 @@
+package opsgenie
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSend 测试告警内容、认证和只为匹配仓库的新版本创建告警
+func TestSend(t *testing.T) {
+	var got []alert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "GenieKey key" {
+			t.Errorf("缺少API密钥: %v", r.Header)
+		}
+		var a alert
+		if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+			t.Errorf("无效的请求: %v", err)
+		}
+		got = append(got, a)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"result":"Request will be processed","took":0.1,"requestId":"x"}`))
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled: true,
+		APIKey:  "key",
+		Repos:   []string{"golang/go"},
+		Tags:    []string{"upgrade"},
+		APIURL:  srv.URL,
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Event: github.EventRelease, Owner: "golang", Repository: "go", TagName: "go1.22.0",
+		HTMLURL: "https://github.com/golang/go/releases/tag/go1.22.0", PinnedVersion: "go1.21.5", VersionGap: "落后 1 个次版本"}
+	if err := n.Send(release, render.RunContext{}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if err := n.Send(&github.ReleaseInfo{Event: github.EventRelease, Owner: "foo", Repository: "bar", TagName: "v1.0.0"}, render.RunContext{}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("创建了 %d 个告警，期望 1 个", len(got))
+	}
+	a := got[0]
+	if a.Message != "golang/go go1.22.0 需要升级" || a.Alias != "notify/golang/go@go1.22.0" || a.Priority != DefaultPriority || a.Entity != "golang/go" {
+		t.Errorf("告警内容不正确: %+v", a)
+	}
+	if len(a.Tags) != 1 || a.Tags[0] != "upgrade" {
+		t.Errorf("告警标签不正确: %v", a.Tags)
+	}
+	if !strings.Contains(a.Description, "当前使用版本: go1.21.5（落后 1 个次版本）") {
+		t.Errorf("告警说明不正确: %q", a.Description)
+	}
+}
+
+// TestNew_Region 测试按区域选择接口地址
+func TestNew_Region(t *testing.T) {
+	n, err := New(Config{APIKey: "key", Repos: []string{"foo/bar"}, Region: "eu"}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	if n.config.APIURL != EUAPIURL {
+		t.Errorf("接口地址为 %s，期望 %s", n.config.APIURL, EUAPIURL)
+	}
+
+	for name, config := range map[string]Config{
+		"缺少api_key": {Repos: []string{"foo/bar"}},
+		"缺少仓库":      {APIKey: "key"},
+		"不支持的优先级":   {APIKey: "key", Repos: []string{"foo/bar"}, Priority: "P0"},
+		"不支持的区域":    {APIKey: "key", Repos: []string{"foo/bar"}, Region: "cn"},
+	} {
+		if _, err := New(config, nil); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
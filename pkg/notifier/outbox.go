@@ -0,0 +1,195 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// maxOutboxAttempts 单条通知最多重试的次数，超过后丢弃
+const maxOutboxAttempts = 5
+
+// OutboxEntry 一条发送失败、等待重发的通知
+type OutboxEntry struct {
+	// Channel 通知渠道名称
+	Channel   string              `json:"channel"`
+	Release   *github.ReleaseInfo `json:"release"`
+	Attempts  int                 `json:"attempts"`
+	LastError string              `json:"last_error"`
+	FailedAt  time.Time           `json:"failed_at"`
+}
+
+// Outbox 持久化的失败通知队列
+type Outbox struct {
+	path    string
+	entries []OutboxEntry
+	mu      sync.Mutex
+}
+
+// NewOutbox 创建失败通知队列，路径为空时使用 ~/.notify/outbox.json
+func NewOutbox(path string) (*Outbox, error) {
+	if path == "" {
+		var err error
+		path, err = util.DefaultPath("outbox.json")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("创建队列目录失败: %v", err)
+	}
+
+	outbox := &Outbox{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return outbox, nil
+		}
+		return nil, fmt.Errorf("读取失败通知队列失败: %v", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &outbox.entries); err != nil {
+			return nil, fmt.Errorf("解析失败通知队列失败: %v", err)
+		}
+	}
+
+	return outbox, nil
+}
+
+// Add 将发送失败的通知加入队列
+func (o *Outbox) Add(channel string, releases []*github.ReleaseInfo, sendErr error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, release := range releases {
+		if o.containsLocked(channel, release) {
+			continue
+		}
+		o.entries = append(o.entries, OutboxEntry{
+			Channel:   channel,
+			Release:   release,
+			Attempts:  1,
+			LastError: sendErr.Error(),
+			FailedAt:  time.Now(),
+		})
+	}
+}
+
+// Requeue 将重发失败的通知放回队列，超过最大重试次数的通知会被丢弃
+func (o *Outbox) Requeue(entries []OutboxEntry, sendErr error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, entry := range entries {
+		entry.Attempts++
+		entry.LastError = sendErr.Error()
+		entry.FailedAt = time.Now()
+		if entry.Attempts > maxOutboxAttempts {
+			fmt.Printf("警告: 通知 %s/%s (%s) 在渠道 %s 重试 %d 次仍失败，已丢弃: %v\n",
+				entry.Release.Owner, entry.Release.Repository, entry.Release.TagName,
+				entry.Channel, maxOutboxAttempts, sendErr)
+			continue
+		}
+		if !o.containsLocked(entry.Channel, entry.Release) {
+			o.entries = append(o.entries, entry)
+		}
+	}
+}
+
+// Restore 将取出但未尝试发送的通知原样放回队列
+func (o *Outbox) Restore(entries []OutboxEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, entry := range entries {
+		if !o.containsLocked(entry.Channel, entry.Release) {
+			o.entries = append(o.entries, entry)
+		}
+	}
+}
+
+// Prune 丢弃 before 之前最后一次失败（或加入队列）的通知，返回丢弃的通知数
+func (o *Outbox) Prune(before time.Time) int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	kept := o.entries[:0]
+	for _, entry := range o.entries {
+		if !entry.FailedAt.Before(before) {
+			kept = append(kept, entry)
+		}
+	}
+	removed := len(o.entries) - len(kept)
+	o.entries = kept
+	return removed
+}
+
+// Take 取出队列中的所有通知
+func (o *Outbox) Take() []OutboxEntry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	entries := o.entries
+	o.entries = nil
+	return entries
+}
+
+// Channels 返回队列中有通知的渠道
+func (o *Outbox) Channels() []string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	seen := make(map[string]bool)
+	var channels []string
+	for _, entry := range o.entries {
+		if !seen[entry.Channel] {
+			seen[entry.Channel] = true
+			channels = append(channels, entry.Channel)
+		}
+	}
+	return channels
+}
+
+// Len 返回队列中的通知数量
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Save 将队列保存到文件
+func (o *Outbox) Save() error {
+	o.mu.Lock()
+	data, err := json.MarshalIndent(o.entries, "", "  ")
+	o.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("序列化失败通知队列失败: %v", err)
+	}
+	if err := os.WriteFile(o.path, data, 0644); err != nil {
+		return fmt.Errorf("保存失败通知队列失败: %v", err)
+	}
+	return nil
+}
+
+// containsLocked 判断同一渠道的同一版本是否已在队列中（调用方需持有锁）
+func (o *Outbox) containsLocked(channel string, release *github.ReleaseInfo) bool {
+	for _, entry := range o.entries {
+		if entry.Channel == channel &&
+			entry.Release.Owner == release.Owner &&
+			entry.Release.Repository == release.Repository &&
+			entry.Release.TagName == release.TagName &&
+			entry.Release.Event == release.Event {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package notifier
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// TestOutbox_AddAndReload 同一渠道的同一版本只加入一次，保存后重新加载得到相同的队列
+func TestOutbox_AddAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.json")
+	outbox, err := NewOutbox(path)
+	if err != nil {
+		t.Fatalf("创建队列失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "o", Repository: "r", TagName: "v1.0.0"}
+	notes := &github.ReleaseInfo{Owner: "o", Repository: "r", TagName: "v1.0.0", Event: github.EventNotesUpdated}
+	sendErr := errors.New("连接超时")
+	outbox.Add("钉钉", []*github.ReleaseInfo{release, notes}, sendErr)
+	outbox.Add("钉钉", []*github.ReleaseInfo{release}, sendErr)
+	outbox.Add("Slack", []*github.ReleaseInfo{release}, sendErr)
+	if outbox.Len() != 3 {
+		t.Fatalf("队列中有 %d 条通知，期望 3 条", outbox.Len())
+	}
+	if channels := outbox.Channels(); len(channels) != 2 || channels[0] != "钉钉" || channels[1] != "Slack" {
+		t.Errorf("渠道 %v，期望 [钉钉 Slack]", channels)
+	}
+
+	if err := outbox.Save(); err != nil {
+		t.Fatalf("保存队列失败: %v", err)
+	}
+	reloaded, err := NewOutbox(path)
+	if err != nil {
+		t.Fatalf("重新加载队列失败: %v", err)
+	}
+	entries := reloaded.Take()
+	if len(entries) != 3 {
+		t.Fatalf("重新加载后有 %d 条通知，期望 3 条", len(entries))
+	}
+	if entry := entries[0]; entry.Channel != "钉钉" || entry.Attempts != 1 || entry.LastError != "连接超时" || entry.Release.TagName != "v1.0.0" {
+		t.Errorf("通知内容错误: %+v", entry)
+	}
+	if reloaded.Len() != 0 {
+		t.Error("取出后队列应为空")
+	}
+}
+
+// TestOutbox_Requeue 重发失败的通知增加重试次数后放回队列，超过最大重试次数后丢弃
+func TestOutbox_Requeue(t *testing.T) {
+	outbox, err := NewOutbox(filepath.Join(t.TempDir(), "outbox.json"))
+	if err != nil {
+		t.Fatalf("创建队列失败: %v", err)
+	}
+	outbox.Add("钉钉", []*github.ReleaseInfo{{Owner: "o", Repository: "r", TagName: "v1.0.0"}}, errors.New("第1次失败"))
+
+	for attempt := 2; attempt <= maxOutboxAttempts; attempt++ {
+		entries := outbox.Take()
+		outbox.Requeue(entries, errors.New("仍然失败"))
+		if outbox.Len() != 1 {
+			t.Fatalf("第 %d 次失败后队列中有 %d 条通知，期望 1 条", attempt, outbox.Len())
+		}
+		entries = outbox.Take()
+		if entries[0].Attempts != attempt || entries[0].LastError != "仍然失败" {
+			t.Fatalf("第 %d 次失败后的记录错误: %+v", attempt, entries[0])
+		}
+		outbox.Restore(entries)
+	}
+
+	outbox.Requeue(outbox.Take(), errors.New("超过次数"))
+	if outbox.Len() != 0 {
+		t.Errorf("重试 %d 次仍失败的通知应被丢弃", maxOutboxAttempts)
+	}
+}
+
+// TestOutbox_Restore 放回的通知保持原样，不增加重试次数，也不与队列中的通知重复
+func TestOutbox_Restore(t *testing.T) {
+	outbox, err := NewOutbox(filepath.Join(t.TempDir(), "outbox.json"))
+	if err != nil {
+		t.Fatalf("创建队列失败: %v", err)
+	}
+	release := &github.ReleaseInfo{Owner: "o", Repository: "r", TagName: "v1.0.0"}
+	outbox.Add("钉钉", []*github.ReleaseInfo{release}, errors.New("失败"))
+
+	entries := outbox.Take()
+	// 取出期间同一版本再次发送失败
+	outbox.Add("钉钉", []*github.ReleaseInfo{release}, errors.New("再次失败"))
+	outbox.Restore(entries)
+
+	if outbox.Len() != 1 {
+		t.Fatalf("队列中有 %d 条通知，期望 1 条", outbox.Len())
+	}
+	if entry := outbox.Take()[0]; entry.Attempts != 1 || entry.LastError != "再次失败" {
+		t.Errorf("放回后的记录错误: %+v", entry)
+	}
+}
+
+// TestOutbox_Prune 丢弃最后一次失败早于指定时间的通知
+func TestOutbox_Prune(t *testing.T) {
+	outbox, err := NewOutbox(filepath.Join(t.TempDir(), "outbox.json"))
+	if err != nil {
+		t.Fatalf("创建队列失败: %v", err)
+	}
+	now := time.Now()
+	outbox.Restore([]OutboxEntry{
+		{Channel: "钉钉", Release: &github.ReleaseInfo{Owner: "o", Repository: "r", TagName: "v1"}, FailedAt: now.Add(-48 * time.Hour)},
+		{Channel: "钉钉", Release: &github.ReleaseInfo{Owner: "o", Repository: "r", TagName: "v2"}, FailedAt: now},
+	})
+
+	if removed := outbox.Prune(now.Add(-24 * time.Hour)); removed != 1 {
+		t.Errorf("丢弃了 %d 条通知，期望 1 条", removed)
+	}
+	if entries := outbox.Take(); len(entries) != 1 || entries[0].Release.TagName != "v2" {
+		t.Errorf("剩余通知错误: %+v", entries)
+	}
+}
@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+)
+
+// newPacer 按配置创建各渠道的令牌桶
+func newPacer(configured map[string]config.PacingConfig) (*pacing.Pacer, error) {
+	overrides := make(map[string]pacing.Limit, len(configured))
+	for channel, c := range configured {
+		var limit pacing.Limit
+		if c.Interval != "" {
+			interval, err := time.ParseDuration(c.Interval)
+			if err != nil || interval < 0 {
+				return nil, fmt.Errorf("渠道 %s 的发送间隔 %s 无效: 请使用如 4s、1m 的格式", channel, c.Interval)
+			}
+			limit.Interval = interval
+		}
+		if c.Burst < 0 {
+			return nil, fmt.Errorf("渠道 %s 的突发数 %d 无效", channel, c.Burst)
+		}
+		limit.Burst = c.Burst
+		overrides[channel] = limit
+	}
+	return pacing.New(overrides), nil
+}
+
+// Pacing 返回各渠道令牌桶的当前状态
+func (m *Manager) Pacing() []pacing.Status {
+	return m.pacer.Status()
+}
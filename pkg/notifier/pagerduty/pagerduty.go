@@ -0,0 +1,222 @@
+package pagerduty
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultAPIURL PagerDuty Events API v2 地址
+const DefaultAPIURL = "https://events.pagerduty.com/v2/enqueue"
+
+// DefaultSeverity 默认事件级别，配合服务的事件规则按低紧急程度处理，不会在夜间呼叫值班人员
+const DefaultSeverity = "info"
+
+// source 事件的来源，显示在事件详情中
+const source = "notify"
+
+// severities Events API v2 支持的事件级别
+var severities = map[string]bool{"critical": true, "error": true, "warning": true, "info": true}
+
+// Config PagerDuty 事件配置
+type Config struct {
+	Enabled bool
+	// RoutingKey 服务集成（Events API v2）的 Integration Key
+	RoutingKey string
+	// Repos 需要创建事件的仓库（owner/repo，支持 * 通配），只有这些仓库的新版本会创建事件
+	Repos []string
+	// Severity 事件级别: info（默认）、warning、error、critical
+	Severity string
+	// APIURL 事件接口地址，为空时使用 DefaultAPIURL
+	APIURL string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+}
+
+// Notifier PagerDuty 通知器，关键依赖发布新版本时创建事件，提醒值班人员安排升级
+// 同一版本使用相同的去重键，重复发送时 PagerDuty 不会创建新的告警
+type Notifier struct {
+	config Config
+	client *http.Client
+}
+
+// New 创建PagerDuty通知器
+// 事件内容按版本信息生成，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.RoutingKey == "" {
+		return nil, fmt.Errorf("PagerDuty的 routing_key 不能为空")
+	}
+	if len(config.Repos) == 0 {
+		return nil, fmt.Errorf("PagerDuty需要配置创建事件的仓库（repos）")
+	}
+	if config.Severity == "" {
+		config.Severity = DefaultSeverity
+	}
+	if !severities[config.Severity] {
+		return nil, fmt.Errorf("不支持的PagerDuty事件级别: %s（可选 info、warning、error、critical）", config.Severity)
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	return &Notifier{config: config, client: client}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "pagerduty"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 仓库匹配时为新版本创建事件，其他版本和事件类型直接跳过
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	if !release.IsNewVersion() || !release.MatchesRepo(n.config.Repos) {
+		return nil
+	}
+	return n.enqueue(n.buildEvent(release))
+}
+
+// SendBatch 每个匹配的版本单独创建一个事件
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	var sent, failed int
+	var firstErr error
+	for _, release := range releases {
+		if !release.IsNewVersion() || !release.MatchesRepo(n.config.Repos) {
+			continue
+		}
+		sent++
+		if err := n.enqueue(n.buildEvent(release)); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d 个PagerDuty事件创建失败: %v", failed, sent, firstErr)
+	}
+	return nil
+}
+
+// event Events API v2 的事件
+type event struct {
+	RoutingKey  string  `json:"routing_key"`
+	EventAction string  `json:"event_action"`
+	DedupKey    string  `json:"dedup_key"`
+	Payload     payload `json:"payload"`
+	Links       []link  `json:"links,omitempty"`
+}
+
+type payload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Timestamp     string            `json:"timestamp,omitempty"`
+	Component     string            `json:"component"`
+	Class         string            `json:"class"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type link struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
+// buildEvent 根据版本信息构建事件，去重键为 notify/owner/repo@tag
+func (n *Notifier) buildEvent(release *github.ReleaseInfo) event {
+	repo := release.Owner + "/" + release.Repository
+	summary := fmt.Sprintf("%s 发布了新版本 %s，需要安排升级", repo, release.TagName)
+	if label := release.EventLabel(); label != "" {
+		summary = fmt.Sprintf("%s（%s）", summary, label)
+	}
+
+	details := map[string]string{"repository": repo, "version": release.TagName}
+	if release.PreviousTag != "" {
+		details["previous_version"] = release.PreviousTag
+	}
+	if release.PinnedVersion != "" {
+		details["pinned_version"] = release.PinnedVersion
+	}
+	if release.VersionGap != "" {
+		details["version_gap"] = release.VersionGap
+	}
+	if len(release.Highlights) > 0 {
+		details["highlights"] = strings.Join(release.Highlights, ", ")
+	}
+
+	e := event{
+		RoutingKey:  n.config.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    fmt.Sprintf("notify/%s@%s", repo, release.TagName),
+		Payload: payload{
+			Summary:       summary,
+			Source:        source,
+			Severity:      n.config.Severity,
+			Component:     repo,
+			Class:         release.Event,
+			CustomDetails: details,
+		},
+		Links: []link{{Href: release.Link(), Text: "发布页面"}},
+	}
+	if !release.PublishedAt.IsZero() {
+		e.Payload.Timestamp = release.PublishedAt.Format(time.RFC3339)
+	}
+	if compare := release.CompareLink(); compare != "" {
+		e.Links = append(e.Links, link{Href: compare, Text: "版本对比"})
+	}
+	return e
+}
+
+// enqueue 发送事件并检查返回结果，接受后返回 202
+func (n *Notifier) enqueue(e event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("序列化事件失败: %v", err)
+	}
+
+	resp, err := n.client.Post(n.config.APIURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("发送PagerDuty事件失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("PagerDuty触发限流: rate limit exceeded")
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		// 错误时返回 {"status":"invalid event","message":"...","errors":["..."]}
+		var response struct {
+			Message string   `json:"message"`
+			Errors  []string `json:"errors"`
+		}
+		if json.Unmarshal(respBody, &response) == nil && response.Message != "" {
+			return fmt.Errorf("PagerDuty返回错误，状态码: %d (%s: %s)", resp.StatusCode, response.Message, strings.Join(response.Errors, "; "))
+		}
+		return fmt.Errorf("PagerDuty请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
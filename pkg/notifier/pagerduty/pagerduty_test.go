@@ -0,0 +1,92 @@
+package pagerduty
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSendBatch 测试只为匹配仓库的新版本创建事件
+func TestSendBatch(t *testing.T) {
+	var got []event
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("无效的请求: %v", err)
+		}
+		got = append(got, e)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"status":"success","dedup_key":"x"}`))
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled:    true,
+		RoutingKey: "key",
+		Repos:      []string{"Kubernetes/*", "golang/go"},
+		APIURL:     srv.URL,
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Event: github.EventRelease, Owner: "kubernetes", Repository: "kubernetes", TagName: "v1.30.0", PreviousTag: "v1.29.0",
+			HTMLURL: "https://github.com/kubernetes/kubernetes/releases/tag/v1.30.0", PublishedAt: time.Date(2024, 4, 17, 0, 0, 0, 0, time.UTC)},
+		{Event: github.EventRelease, Owner: "foo", Repository: "bar", TagName: "v1.0.0"},
+		{Event: github.EventNotesUpdated, Owner: "golang", Repository: "go", TagName: "go1.22.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("创建了 %d 个事件，期望 1 个", len(got))
+	}
+	e := got[0]
+	if e.RoutingKey != "key" || e.EventAction != "trigger" || e.DedupKey != "notify/kubernetes/kubernetes@v1.30.0" {
+		t.Errorf("事件内容不正确: %+v", e)
+	}
+	if e.Payload.Severity != DefaultSeverity || e.Payload.Component != "kubernetes/kubernetes" || e.Payload.Timestamp != "2024-04-17T00:00:00Z" {
+		t.Errorf("事件内容不正确: %+v", e.Payload)
+	}
+	if len(e.Links) != 2 || e.Links[1].Href != "https://github.com/kubernetes/kubernetes/compare/v1.29.0...v1.30.0" {
+		t.Errorf("事件链接不正确: %+v", e.Links)
+	}
+}
+
+// TestSend_Error 测试返回错误信息
+func TestSend_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"status":"invalid event","message":"Event object is invalid","errors":["Length of 'routing_key' is incorrect"]}`))
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{Enabled: true, RoutingKey: "key", Repos: []string{"foo/bar"}, APIURL: srv.URL}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	err = n.Send(&github.ReleaseInfo{Event: github.EventRelease, Owner: "foo", Repository: "bar", TagName: "v1.0.0"}, render.RunContext{})
+	if err == nil || err.Error() != "PagerDuty返回错误，状态码: 400 (Event object is invalid: Length of 'routing_key' is incorrect)" {
+		t.Errorf("错误信息不正确: %v", err)
+	}
+}
+
+// TestNew_InvalidConfig 测试无效配置
+func TestNew_InvalidConfig(t *testing.T) {
+	for name, config := range map[string]Config{
+		"缺少routing_key": {Repos: []string{"foo/bar"}},
+		"缺少仓库":          {RoutingKey: "key"},
+		"不支持的级别":        {RoutingKey: "key", Repos: []string{"foo/bar"}, Severity: "low"},
+	} {
+		if _, err := New(config, nil); err == nil {
+			t.Errorf("%s: 期望返回错误", name)
+		}
+	}
+}
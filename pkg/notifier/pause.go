@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// errPaused 通知已暂停
+var errPaused = errors.New("通知已暂停")
+
+// pauseFile 暂停标记文件名，位于 ~/.notify 目录
+// 文件存在即表示暂停；内容为RFC3339时间时暂停到该时间，为空时一直暂停到手动恢复
+const pauseFile = "paused"
+
+// PausePath 返回暂停标记文件的路径
+func PausePath() (string, error) {
+	return util.DefaultPath(pauseFile)
+}
+
+// Pause 暂停通知，duration为0时一直暂停到手动恢复
+func Pause(duration time.Duration) error {
+	path, err := PausePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建目录失败: %v", err)
+	}
+
+	var content string
+	if duration > 0 {
+		content = time.Now().Add(duration).Format(time.RFC3339)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("写入暂停标记失败: %v", err)
+	}
+	return nil
+}
+
+// Resume 恢复通知
+func Resume() error {
+	path, err := PausePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("删除暂停标记失败: %v", err)
+	}
+	return nil
+}
+
+// PausedUntil 返回当前是否处于暂停状态及暂停截止时间（零值表示一直暂停到手动恢复）
+// 暂停已到期时返回false
+func PausedUntil() (time.Time, bool) {
+	return pausedUntil(clock.System)
+}
+
+// PausedUntil 与包级函数 PausedUntil 相同，按通知管理器的时钟判断暂停是否到期
+func (m *Manager) PausedUntil() (time.Time, bool) {
+	return pausedUntil(m.clock)
+}
+
+// pausedUntil 读取暂停标记，按 c 的当前时间判断暂停是否到期
+func pausedUntil(c clock.Clock) (time.Time, bool) {
+	path, err := PausePath()
+	if err != nil {
+		return time.Time{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	content := strings.TrimSpace(string(data))
+	if content == "" {
+		return time.Time{}, true
+	}
+	until, err := time.Parse(time.RFC3339, content)
+	if err != nil {
+		// 无法解析时按一直暂停处理，避免在维护期间误发通知
+		fmt.Printf("警告: 无法解析暂停标记 %s 的内容，按一直暂停处理: %v\n", path, err)
+		return time.Time{}, true
+	}
+	return until, c.Now().Before(until)
+}
+
+// DescribePause 返回暂停截止时间的描述，如 "到 2024-07-01 12:00:00"
+func DescribePause(until time.Time) string {
+	if until.IsZero() {
+		return "直到执行 notify resume"
+	}
+	return fmt.Sprintf("到 %s", until.Format(time.DateTime))
+}
+
+// hold 暂停期间将版本放入队列，恢复后的第一次运行开始时发送
+func (m *Manager) hold(releases []*github.ReleaseInfo) {
+	for _, n := range m.notifiers {
+		if routed := m.routeReleases(n.Name(), releases); len(routed) > 0 {
+			m.outbox.Add(n.Name(), routed, errPaused)
+		}
+	}
+	m.saveOutbox()
+}
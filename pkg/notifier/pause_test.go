@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/clock"
+)
+
+// TestPausedUntil_ManagerClock 暂停是否到期按通知管理器的时钟判断，到期后发送的版本不再排队
+func TestPausedUntil_ManagerClock(t *testing.T) {
+	start := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	n := &fakeNotifier{name: "fake"}
+	m := newTestManager(t, &config.Config{}, fake, n)
+
+	path, err := PausePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	until := start.Add(time.Hour)
+	if err := os.WriteFile(path, []byte(until.Format(time.RFC3339)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, paused := m.PausedUntil(); !paused || !got.Equal(until) {
+		t.Fatalf("应暂停到 %s，得到 %s、%v", until, got, paused)
+	}
+	m.NotifyAll(testReleases(1))
+	if len(n.batches) != 0 || m.outbox.Len() != 1 {
+		t.Fatalf("暂停期间应放入队列: 发送 %d 批，队列 %d 条", len(n.batches), m.outbox.Len())
+	}
+
+	fake.Advance(2 * time.Hour)
+	if _, paused := m.PausedUntil(); paused {
+		t.Fatal("管理器时钟超过截止时间后暂停应到期")
+	}
+	if errs := m.DrainOutbox(); len(errs) != 0 || len(n.batches) != 1 {
+		t.Errorf("暂停到期后应发送排队的版本: %v，发送 %d 批", errs, len(n.batches))
+	}
+}
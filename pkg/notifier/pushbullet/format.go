@@ -0,0 +1,63 @@
+package pushbullet
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxBodyLength 推送正文的最大字符数，过长的正文在手机通知中也无法完整显示
+const maxBodyLength = 2000
+
+// maxDigestItems 摘要推送中最多列出的版本数
+const maxDigestItems = 50
+
+var (
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdHeading = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+	mdEmpty   = regexp.MustCompile(`\n{3,}`)
+)
+
+// toPlainText 将模板渲染的markdown转换为纯文本，Pushbullet不支持markdown
+func toPlainText(s string) string {
+	s = mdLink.ReplaceAllString(s, "$1: $2")
+	s = mdHeading.ReplaceAllString(s, "")
+	s = strings.ReplaceAll(s, "**", "")
+	s = strings.ReplaceAll(s, "`", "")
+	return strings.TrimSpace(mdEmpty.ReplaceAllString(s, "\n\n"))
+}
+
+// truncate 按字符数截断文本
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-3]) + "..."
+}
+
+// buildDigestBody 构建摘要推送正文
+func buildDigestBody(releases []*github.ReleaseInfo, run render.RunContext) string {
+	var content bytes.Buffer
+	content.WriteString("今天的消息数已达到上限，以下版本合并发送：\n\n")
+
+	for i, release := range releases {
+		if i == maxDigestItems {
+			content.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		content.WriteString(fmt.Sprintf("• %s/%s %s\n  %s\n",
+			release.Owner, release.Repository, release.TagName, release.Link()))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString(fmt.Sprintf("\n%s", footer))
+	}
+
+	return truncate(content.String(), maxBodyLength)
+}
@@ -0,0 +1,227 @@
+package pushbullet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultAPIURL Pushbullet 推送接口地址
+const DefaultAPIURL = "https://api.pushbullet.com/v2/pushes"
+
+// defaultCooldown 限流响应没有给出重置时间时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// DefaultPace 默认发送速率
+// Pushbullet 按账号计算每月的推送配额，这里只避免短时间内连续推送，设置为每秒1条，突发允许3条
+var DefaultPace = pacing.Limit{Interval: time.Second, Burst: 3}
+
+// Config Pushbullet 推送配置
+type Config struct {
+	Enabled bool
+	// AccessToken 账号设置中创建的访问令牌
+	AccessToken string
+	// DeviceIden 只推送到指定设备（可选），为空时推送到账号的所有设备
+	DeviceIden string
+	// ChannelTag 推送到自己创建的频道（可选），订阅该频道的用户都会收到
+	ChannelTag string
+	// APIURL 推送接口地址，为空时使用 DefaultAPIURL
+	APIURL string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier Pushbullet 通知器，每个版本推送一条链接
+type Notifier struct {
+	config   Config
+	template *template.Template
+	client   *http.Client
+	limiter  *pacing.Bucket // 速率限制器
+	mu       sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// New 创建Pushbullet通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.AccessToken == "" {
+		return nil, fmt.Errorf("Pushbullet访问令牌不能为空")
+	}
+	if config.DeviceIden != "" && config.ChannelTag != "" {
+		return nil, fmt.Errorf("Pushbullet的 device_iden 和 channel_tag 只能设置一个")
+	}
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("pushbullet", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		client:   client,
+		limiter:  limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "pushbullet"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 推送单个版本，以仓库名和版本作为标题，点击打开发布页面
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	return n.push(push{
+		Type:  "link",
+		Title: fmt.Sprintf("%s/%s %s", release.Owner, release.Repository, release.TagName),
+		Body:  truncate(toPlainText(content), maxBodyLength),
+		URL:   release.Link(),
+	})
+}
+
+// SendBatch 每个版本单独推送一条，手机上每条推送对应一个仓库
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	var failed int
+	var firstErr error
+	for _, release := range releases {
+		if err := n.Send(release, run); err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d/%d 条Pushbullet推送失败: %v", failed, len(releases), firstErr)
+	}
+	return nil
+}
+
+// SendDigest 将超过每日上限的版本合并为一条摘要推送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.push(push{
+		Type:  "note",
+		Title: fmt.Sprintf("📦 今天还有 %d 个新版本", len(releases)),
+		Body:  buildDigestBody(releases, run),
+	})
+}
+
+// push Pushbullet 推送请求
+type push struct {
+	Type       string `json:"type"`
+	Title      string `json:"title"`
+	Body       string `json:"body,omitempty"`
+	URL        string `json:"url,omitempty"`
+	DeviceIden string `json:"device_iden,omitempty"`
+	ChannelTag string `json:"channel_tag,omitempty"`
+}
+
+// push 发送推送并检查返回结果
+func (n *Notifier) push(p push) error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+	if remaining > 0 {
+		return fmt.Errorf("Pushbullet触发限流，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	p.DeviceIden = n.config.DeviceIden
+	p.ChannelTag = n.config.ChannelTag
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.config.APIURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Access-Token", n.config.AccessToken)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送Pushbullet推送失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 限流时 X-Ratelimit-Reset 给出配额重置的Unix时间戳
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-Ratelimit-Reset"), 10, 64); err == nil {
+			if until := time.Unix(reset, 0).Sub(n.config.Clock.Now()); until > 0 {
+				wait = until
+			}
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("Pushbullet触发限流，已设置%v冷却期: rate limit exceeded", wait.Round(time.Second))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		// 错误时返回 {"error":{"message":"..."}}
+		var response struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &response) == nil && response.Error.Message != "" {
+			return fmt.Errorf("Pushbullet API返回错误，状态码: %d (%s)", resp.StatusCode, response.Error.Message)
+		}
+		return fmt.Errorf("Pushbullet请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package pushbullet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSend 测试链接推送的内容、认证和频道参数
+func TestSend(t *testing.T) {
+	var got push
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Access-Token") != "token" {
+			t.Errorf("缺少访问令牌: %v", r.Header)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("无效的请求: %v", err)
+		}
+		w.Write([]byte(`{"active":true}`))
+	}))
+	defer srv.Close()
+
+	tmpl := template.Must(template.New("t").Parse("## 📦 新版本\n**版本**: {{.TagName}}\n**[查看详情]({{.Link}})**"))
+	n, err := New(Config{
+		Enabled:     true,
+		AccessToken: "token",
+		ChannelTag:  "releases",
+		APIURL:      srv.URL,
+		Bucket:      pacing.NewBucket("pushbullet", pacing.Limit{Burst: 10}),
+	}, tmpl)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "foo", Repository: "bar", TagName: "v1.0.0", HTMLURL: "https://github.com/foo/bar/releases/tag/v1.0.0"}
+	if err := n.Send(release, render.RunContext{}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if got.Type != "link" || got.Title != "foo/bar v1.0.0" || got.URL != release.HTMLURL || got.ChannelTag != "releases" {
+		t.Errorf("推送内容不正确: %+v", got)
+	}
+	if strings.Contains(got.Body, "**") || !strings.Contains(got.Body, "查看详情: https://github.com/foo/bar") {
+		t.Errorf("正文应转换为纯文本: %q", got.Body)
+	}
+}
+
+// TestSend_RateLimited 测试限流后进入冷却期
+func TestSend_RateLimited(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-Ratelimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{Enabled: true, AccessToken: "token", APIURL: srv.URL}, template.Must(template.New("t").Parse("{{.TagName}}")))
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "foo", Repository: "bar", TagName: "v1.0.0"}
+	if err := n.Send(release, render.RunContext{}); err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Fatalf("应返回限流错误: %v", err)
+	}
+	if err := n.Send(release, render.RunContext{}); err == nil || calls != 1 {
+		t.Errorf("冷却期内不应再次请求，实际请求 %d 次", calls)
+	}
+}
@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"log"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// redact 按过滤规则隐藏版本名称、发布说明、说明变更和高亮内容中的敏感内容
+// 在渲染和持久化之前处理，所有渠道和失败队列中都不会出现被过滤的内容
+func (m *Manager) redact(releases []*github.ReleaseInfo) {
+	if m.redactor == nil {
+		return
+	}
+
+	for _, release := range releases {
+		total := 0
+		apply := func(s *string) {
+			var n int
+			*s, n = m.redactor.Apply(*s)
+			total += n
+		}
+
+		apply(&release.Name)
+		apply(&release.Description)
+		apply(&release.NotesDiff)
+		for i := range release.Highlights {
+			apply(&release.Highlights[i])
+		}
+
+		if total > 0 {
+			log.Printf("已隐藏 %s/%s %s 中的 %d 处敏感内容", release.Owner, release.Repository, release.TagName, total)
+		}
+	}
+}
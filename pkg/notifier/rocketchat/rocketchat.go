@@ -0,0 +1,200 @@
+package rocketchat
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// defaultCooldown 429响应没有给出 Retry-After 时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// DefaultPace 默认发送速率
+// Rocket.Chat 默认按IP限制API调用频率，每秒1条足以避免触发限流
+var DefaultPace = pacing.Limit{Interval: 1 * time.Second, Burst: 3}
+
+// Config Rocket.Chat incoming webhook 配置
+type Config struct {
+	Enabled bool
+	// WebhookURL 集成设置中生成的webhook地址，形如 https://chat.example.com/hooks/xxx/yyy
+	WebhookURL string
+	// Alias 消息显示的发送者名称，为空时使用集成中设置的名称
+	Alias string
+	// Emoji 发送者头像使用的emoji，如 :package:
+	Emoji string
+	// Channel 覆盖集成中设置的默认频道，如 #releases 或 @username
+	Channel string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier Rocket.Chat通知器
+type Notifier struct {
+	config   Config
+	template *template.Template
+	client   *http.Client
+	limiter  *pacing.Bucket // 速率限制器
+	mu       sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// New 创建Rocket.Chat通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("Rocket.Chat webhook URL不能为空")
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("rocketchat", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		client:   client,
+		limiter:  limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "rocketchat"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送Rocket.Chat通知，模板渲染结果转换为Rocket.Chat的markdown格式
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	return n.post(truncate(toMarkdown(content), maxTextLength))
+}
+
+// SendBatch 批量发送Rocket.Chat通知（合并成一条消息）
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.post(buildBatchMarkdown(releases, run))
+}
+
+// SendDigest 将超过每日上限的版本合并为一条摘要消息发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.post(buildDigestMarkdown(releases, run))
+}
+
+// wait 等待冷却期结束和速率限制
+func (n *Notifier) wait() error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+
+	if remaining > 0 {
+		return fmt.Errorf("Rocket.Chat消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+	return nil
+}
+
+// message Rocket.Chat incoming webhook 消息
+type message struct {
+	Text    string `json:"text"`
+	Alias   string `json:"alias,omitempty"`
+	Emoji   string `json:"emoji,omitempty"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// post 发送消息到Rocket.Chat webhook并检查返回结果
+func (n *Notifier) post(text string) error {
+	if err := n.wait(); err != nil {
+		return err
+	}
+
+	msgBytes, err := json.Marshal(message{
+		Text:    text,
+		Alias:   n.config.Alias,
+		Emoji:   n.config.Emoji,
+		Channel: n.config.Channel,
+	})
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	resp, err := n.client.Post(n.config.WebhookURL, "application/json", bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 触发限流时按照 Retry-After 设置冷却期
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("触发Rocket.Chat API限流，已设置%v冷却期: rate limit exceeded", wait)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	// 成功时返回 {"success":true}，失败时 success 为false并在 error 字段给出原因（如集成已禁用、频道不存在）
+	var response struct {
+		Success bool   `json:"success"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	if !response.Success {
+		return fmt.Errorf("Rocket.Chat API返回错误: %s", response.Error)
+	}
+
+	return nil
+}
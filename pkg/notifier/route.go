@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// route 一条路由规则，渠道组已展开
+type route struct {
+	repos    []string
+	channels map[string]bool
+}
+
+// routeTable 按顺序匹配的路由规则
+type routeTable []route
+
+// newRoutes 按配置创建路由规则
+func newRoutes(cfg *config.Config) routeTable {
+	routes := make(routeTable, 0, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		channels := make(map[string]bool)
+		for _, channel := range cfg.RouteChannels(r) {
+			channels[channel] = true
+		}
+		routes = append(routes, route{repos: r.Repos, channels: channels})
+	}
+	return routes
+}
+
+// includes 渠道是否在规则的渠道中，规则中的类型名称（如 dingtalk）包含该类型的所有实例
+func (r route) includes(channel string) bool {
+	if r.channels[channel] {
+		return true
+	}
+	kind, _, isInstance := strings.Cut(channel, "/")
+	return isInstance && r.channels[kind]
+}
+
+// allows 版本是否应发送到渠道，没有规则匹配的版本发送到所有渠道
+func (t routeTable) allows(channel string, release *github.ReleaseInfo) bool {
+	for _, r := range t {
+		if release.MatchesRepo(r.repos) {
+			return r.includes(channel)
+		}
+	}
+	return true
+}
+
+// routeReleases 返回 releases 中应发送到渠道的版本：版本按第一条匹配的路由规则选择渠道，没有规则匹配时发送到所有渠道
+func (m *Manager) routeReleases(channel string, releases []*github.ReleaseInfo) []*github.ReleaseInfo {
+	if len(m.routes) == 0 {
+		return releases
+	}
+	routed := make([]*github.ReleaseInfo, 0, len(releases))
+	for _, release := range releases {
+		if m.routes.allows(channel, release) {
+			routed = append(routed, release)
+		}
+	}
+	return routed
+}
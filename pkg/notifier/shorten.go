@@ -0,0 +1,36 @@
+package notifier
+
+import (
+	"log"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// shorten 为版本链接和对比链接生成短链接，未配置短链接服务时不做任何处理
+// 短链接服务不可用时保留原链接，不影响发送
+func (m *Manager) shorten(releases []*github.ReleaseInfo) {
+	if m.shortener == nil {
+		return
+	}
+
+	for _, release := range releases {
+		if release.ShortURL == "" && release.HTMLURL != "" {
+			short, err := m.shortener.Shorten(release.HTMLURL)
+			if err != nil {
+				log.Printf("警告: 生成 %s/%s %s 的短链接失败，使用原链接: %v", release.Owner, release.Repository, release.TagName, err)
+				// 服务不可用时不再逐个重试
+				return
+			}
+			release.ShortURL = short
+		}
+
+		if compare := release.CompareURL(); release.ShortCompareURL == "" && compare != "" {
+			short, err := m.shortener.Shorten(compare)
+			if err != nil {
+				log.Printf("警告: 生成 %s/%s %s 对比链接的短链接失败，使用原链接: %v", release.Owner, release.Repository, release.TagName, err)
+				return
+			}
+			release.ShortCompareURL = short
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package slack
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxSectionText Slack section 块文本的最大长度
+const maxSectionText = 3000
+
+// maxDigestItems 摘要消息中最多列出的版本数
+const maxDigestItems = 50
+
+// block Slack Block Kit 块
+type block struct {
+	Type     string  `json:"type"`
+	Text     *text   `json:"text,omitempty"`
+	Fields   []*text `json:"fields,omitempty"`
+	Elements []*text `json:"elements,omitempty"`
+	ImageURL string  `json:"image_url,omitempty"`
+	AltText  string  `json:"alt_text,omitempty"`
+}
+
+// text Slack文本对象
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func plain(s string) *text {
+	return &text{Type: "plain_text", Text: s}
+}
+
+func mrkdwn(s string) *text {
+	return &text{Type: "mrkdwn", Text: s}
+}
+
+// imageBlock 图片块
+func imageBlock(url, alt string) block {
+	return block{Type: "image", ImageURL: url, AltText: alt}
+}
+
+// escape 转义Slack文本中的控制字符
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// link 生成Slack格式的链接
+func link(url, label string) string {
+	return fmt.Sprintf("<%s|%s>", url, escape(label))
+}
+
+var (
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdBold    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	mdHeading = regexp.MustCompile(`(?m)^#{1,6}\s+(.+)$`)
+)
+
+// toMrkdwn 将模板中常用的Markdown语法（标题、粗体、链接）转换为Slack mrkdwn
+func toMrkdwn(s string) string {
+	s = mdHeading.ReplaceAllString(s, "**$1**")
+	s = mdBold.ReplaceAllString(s, "*$1*")
+	s = mdLink.ReplaceAllString(s, "<$2|$1>")
+	return s
+}
+
+// maxMessageText 消息 text 字段的最大长度
+const maxMessageText = 40000
+
+// blocksText 将块中的文字按顺序合并为纯文本，Slack拒绝消息中的块时作为消息正文重新发送
+// 图片块没有文字，不包含在内；没有任何文字时使用 fallback
+func blocksText(blocks []block, fallback string) string {
+	var parts []string
+	for _, b := range blocks {
+		if b.Text != nil {
+			parts = append(parts, b.Text.Text)
+		}
+		for _, t := range b.Fields {
+			parts = append(parts, t.Text)
+		}
+		for _, t := range b.Elements {
+			parts = append(parts, t.Text)
+		}
+	}
+	if len(parts) == 0 {
+		return fallback
+	}
+	return truncate(strings.Join(parts, "\n"), maxMessageText)
+}
+
+// templateBlocks 将模板渲染结果转换为section块
+func templateBlocks(content string) []block {
+	return sections(toMrkdwn(content), "\n\n")
+}
+
+// sections 将文本按分隔符拆分为多个section块，保证每块不超过长度限制
+func sections(content, sep string) []block {
+	var blocks []block
+	var current strings.Builder
+
+	flush := func() {
+		if strings.TrimSpace(current.String()) != "" {
+			blocks = append(blocks, block{Type: "section", Text: mrkdwn(current.String())})
+		}
+		current.Reset()
+	}
+
+	for _, part := range strings.Split(content, sep) {
+		if current.Len()+len(part)+len(sep) > maxSectionText {
+			flush()
+		}
+		if len(part) > maxSectionText {
+			part = truncate(part, maxSectionText-3) + "..."
+		}
+		if current.Len() > 0 {
+			current.WriteString(sep)
+		}
+		current.WriteString(part)
+	}
+	flush()
+
+	return blocks
+}
+
+// truncate 按字节数截断文本，不截断多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// batchBlocks 构建批量消息，每个版本一个section块，字段以两列显示
+func batchBlocks(releases []*github.ReleaseInfo, run render.RunContext) []block {
+	blocks := []block{
+		{Type: "header", Text: plain("📦 GitHub 版本更新汇总")},
+		{Type: "section", Text: mrkdwn(fmt.Sprintf("共 %d 个仓库发布了新版本：", len(releases)))},
+	}
+
+	for i, release := range releases {
+		var title strings.Builder
+		title.WriteString(fmt.Sprintf("*%d. %s*", i+1,
+			link(release.Link(), release.Owner+"/"+release.Repository)))
+		if label := release.EventLabel(); label != "" {
+			title.WriteString("\n" + escape(label))
+		}
+		if release.IsHighlighted() {
+			title.WriteString("\n*" + escape(release.HighlightBanner()) + "*")
+		}
+		if release.Contributed {
+			title.WriteString("\n" + escape(release.ContributorBadge()))
+		}
+
+		fields := []*text{
+			mrkdwn(fmt.Sprintf("*版本*\n`%s`", escape(release.TagName))),
+			mrkdwn(fmt.Sprintf("*发布时间*\n%s", run.FormatTime(release.PublishedAt))),
+		}
+		if release.SignatureChecked {
+			fields = append(fields, mrkdwn(fmt.Sprintf("*签名*\n%s", release.SignatureStatus())))
+		}
+		if release.PinnedVersion != "" {
+			fields = append(fields, mrkdwn(fmt.Sprintf("*锁定版本*\n%s", escape(release.PinnedStatus()))))
+		}
+		if len(release.MatchedAssets) > 0 {
+			fields = append(fields, mrkdwn(fmt.Sprintf("*附件*\n%s", escape(strings.Join(release.MatchedAssets, ", ")))))
+		}
+
+		blocks = append(blocks, block{Type: "divider"})
+		blocks = append(blocks, block{Type: "section", Text: mrkdwn(title.String()), Fields: fields})
+
+		if release.NotesDiff != "" {
+			blocks = append(blocks, block{Type: "section", Text: mrkdwn("```" + escape(release.NotesDiff) + "```")})
+		}
+	}
+
+	if footer := run.Footer(); footer != "" {
+		blocks = append(blocks, block{Type: "context", Elements: []*text{mrkdwn(escape(footer))}})
+	}
+
+	return blocks
+}
+
+// digestBlocks 构建超过每日上限后的摘要消息，每个版本只占一行
+func digestBlocks(releases []*github.ReleaseInfo, run render.RunContext) []block {
+	var lines strings.Builder
+	for i, release := range releases {
+		if i == maxDigestItems {
+			lines.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		lines.WriteString(fmt.Sprintf("• %s `%s`\n",
+			link(release.Link(), release.Owner+"/"+release.Repository), escape(release.TagName)))
+	}
+
+	blocks := []block{
+		{Type: "header", Text: plain(fmt.Sprintf("📦 今天还有 %d 个新版本", len(releases)))},
+		{Type: "section", Text: mrkdwn("今天的消息数已达到上限，以下版本合并发送：")},
+	}
+	blocks = append(blocks, sections(lines.String(), "\n")...)
+
+	if footer := run.Footer(); footer != "" {
+		blocks = append(blocks, block{Type: "context", Elements: []*text{mrkdwn(escape(footer))}})
+	}
+
+	return blocks
+}
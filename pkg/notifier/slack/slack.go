@@ -0,0 +1,291 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// postMessageURL Slack chat.postMessage 接口地址
+const postMessageURL = "https://slack.com/api/chat.postMessage"
+
+// defaultCooldown 429响应没有给出 Retry-After 时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// DefaultPace 默认发送速率
+// Slack incoming webhook 和 chat.postMessage 限制: 每个频道每秒约1条消息
+var DefaultPace = pacing.Limit{Interval: 1 * time.Second, Burst: 3}
+
+// Config Slack通知配置
+// WebhookURL 与 BotToken 二选一：配置了 BotToken 时使用 chat.postMessage 发送到 Channel
+type Config struct {
+	Enabled    bool
+	WebhookURL string
+	BotToken   string
+	// Channel 使用 BotToken 时发送的频道ID或名称，如 C0123456789、#releases
+	Channel string
+	// PreviewImage 单个版本的消息是否附带仓库的OpenGraph预览图
+	PreviewImage bool
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier Slack通知器
+type Notifier struct {
+	config   Config
+	template *template.Template
+	client   *http.Client
+	limiter  *pacing.Bucket // 速率限制器
+	mu       sync.Mutex     // 保护冷却状态
+	cooldown struct {
+		active bool
+		until  time.Time
+	}
+}
+
+// New 创建Slack通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.BotToken == "" && config.WebhookURL == "" {
+		return nil, fmt.Errorf("Slack webhook URL和Bot Token不能同时为空")
+	}
+
+	if config.BotToken != "" && config.Channel == "" {
+		return nil, fmt.Errorf("使用Slack Bot Token时频道不能为空")
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("slack", DefaultPace)
+	}
+
+	// 创建带超时的HTTP客户端，按配置绑定出口地址
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		client:   client,
+		limiter:  limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "slack"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// canSendMessage 检查是否可以发送消息
+func (n *Notifier) canSendMessage() (bool, time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	now := n.config.Clock.Now()
+
+	// 检查是否在冷却期
+	if n.cooldown.active && now.Before(n.cooldown.until) {
+		return false, n.cooldown.until.Sub(now)
+	}
+
+	// 冷却期已过或未激活
+	n.cooldown.active = false
+	return true, 0
+}
+
+// setCooldown 设置冷却期
+func (n *Notifier) setCooldown(duration time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.cooldown.active = true
+	n.cooldown.until = n.config.Clock.Now().Add(duration)
+}
+
+// wait 检查冷却期并等待速率限制
+func (n *Notifier) wait() error {
+	canSend, remaining := n.canSendMessage()
+	if !canSend {
+		return fmt.Errorf("Slack消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+	return nil
+}
+
+// Send 发送Slack通知，模板渲染结果转换为Slack mrkdwn格式
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	if err := n.wait(); err != nil {
+		return err
+	}
+
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	fallback := fmt.Sprintf("%s/%s 发布新版本 %s", release.Owner, release.Repository, release.TagName)
+	blocks := templateBlocks(content)
+	if image := release.OpenGraphImageURL(); n.config.PreviewImage && image != "" {
+		blocks = append(blocks, imageBlock(image, release.Owner+"/"+release.Repository))
+	}
+	return n.send(message{Text: fallback, Blocks: blocks})
+}
+
+// SendBatch 批量发送Slack通知（合并成一条消息）
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	if err := n.wait(); err != nil {
+		return err
+	}
+
+	fallback := fmt.Sprintf("GitHub 版本更新汇总（%d 个仓库）", len(releases))
+	return n.send(message{Text: fallback, Blocks: batchBlocks(releases, run)})
+}
+
+// SendDigest 将超过每日上限的版本合并为一条摘要消息发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	if err := n.wait(); err != nil {
+		return err
+	}
+
+	fallback := fmt.Sprintf("今天还有 %d 个新版本", len(releases))
+	return n.send(message{Text: fallback, Blocks: digestBlocks(releases, run)})
+}
+
+// message Slack消息，Text 用于通知预览和不支持Block Kit的客户端
+type message struct {
+	Channel string  `json:"channel,omitempty"`
+	Text    string  `json:"text"`
+	Blocks  []block `json:"blocks,omitempty"`
+}
+
+// blocksError Slack拒绝了消息中的块，如格式无效或超出长度限制（invalid_blocks、invalid_blocks_format）
+type blocksError struct {
+	code string
+}
+
+func (e *blocksError) Error() string {
+	return fmt.Sprintf("Slack拒绝了消息格式: %s", e.code)
+}
+
+// send 发送消息，Slack拒绝消息中的块时去掉块，将块中的文字合并为纯文本重新发送一次，不会因为格式问题丢失通知
+func (n *Notifier) send(msg message) error {
+	err := n.post(msg)
+
+	var blocksErr *blocksError
+	if !errors.As(err, &blocksErr) || len(msg.Blocks) == 0 {
+		return err
+	}
+	log.Printf("Slack拒绝了消息格式（%s），去掉格式后以纯文本重新发送", blocksErr.code)
+	return n.post(message{Text: blocksText(msg.Blocks, msg.Text)})
+}
+
+// post 发送消息，配置了Bot Token时使用 chat.postMessage，否则使用incoming webhook
+func (n *Notifier) post(msg message) error {
+	apiURL := n.config.WebhookURL
+	if n.config.BotToken != "" {
+		apiURL = postMessageURL
+		msg.Channel = n.config.Channel
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, apiURL, bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if n.config.BotToken != "" {
+		req.Header.Set("Authorization", "Bearer "+n.config.BotToken)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 触发限流时按照 Retry-After 设置冷却期
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		n.setCooldown(wait)
+		return fmt.Errorf("触发Slack API限流，已设置%v冷却期: rate limit exceeded", wait)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+
+	// incoming webhook 成功时返回纯文本 ok，失败时返回错误码文本（如 invalid_payload、no_service）
+	if n.config.BotToken == "" {
+		if resp.StatusCode != http.StatusOK {
+			if code := strings.TrimSpace(string(body)); strings.HasPrefix(code, "invalid_blocks") {
+				return &blocksError{code: code}
+			}
+			return fmt.Errorf("请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return nil
+	}
+
+	// chat.postMessage 返回JSON，错误信息在 error 字段中
+	var response struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
+	}
+	if !response.OK {
+		if strings.HasPrefix(response.Error, "invalid_blocks") {
+			return &blocksError{code: response.Error}
+		}
+		return fmt.Errorf("Slack API返回错误: %s", response.Error)
+	}
+
+	return nil
+}
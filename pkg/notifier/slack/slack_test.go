@@ -0,0 +1,185 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// newTestNotifier 创建发送到测试服务器的通知器，使用Bot Token时将 chat.postMessage 请求转发到测试服务器
+func newTestNotifier(t *testing.T, srv *httptest.Server, config Config, tmpl *template.Template) *Notifier {
+	t.Helper()
+	config.Enabled = true
+	config.Bucket = pacing.NewBucket("slack", pacing.Limit{Burst: 10})
+	if config.BotToken == "" {
+		config.WebhookURL = srv.URL + "/hook"
+	}
+	n, err := New(config, tmpl)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	target, _ := url.Parse(srv.URL)
+	n.client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		req.URL.Scheme, req.URL.Host = target.Scheme, target.Host
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	return n
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+// TestSend 测试单个版本通过incoming webhook发送，模板中的Markdown转换为mrkdwn
+func TestSend(t *testing.T) {
+	var got message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hook" {
+			t.Errorf("请求路径为 %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("webhook 不应带认证头: %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("无效的消息: %v", err)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	tmpl := template.Must(template.New("slack").Parse("## {{.Owner}}/{{.Repository}}\n**版本**: [{{.TagName}}]({{.HTMLURL}})"))
+	n := newTestNotifier(t, srv, Config{PreviewImage: true}, tmpl)
+
+	release := &github.ReleaseInfo{Owner: "o", Repository: "a", TagName: "v1.0.0", HTMLURL: "https://github.com/o/a/releases/tag/v1.0.0"}
+	if err := n.Send(release, render.RunContext{Timestamp: time.Now(), Total: 1}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if got.Text != "o/a 发布新版本 v1.0.0" || got.Channel != "" {
+		t.Errorf("消息预览为 %q，频道为 %q", got.Text, got.Channel)
+	}
+	if len(got.Blocks) != 2 || got.Blocks[0].Text == nil || got.Blocks[1].Type != "image" {
+		t.Fatalf("消息块不正确: %+v", got.Blocks)
+	}
+	if content := got.Blocks[0].Text.Text; !strings.Contains(content, "*o/a*") || !strings.Contains(content, "<https://github.com/o/a/releases/tag/v1.0.0|v1.0.0>") {
+		t.Errorf("消息内容不正确: %s", content)
+	}
+}
+
+// TestSendBatch 测试使用Bot Token通过 chat.postMessage 发送到配置的频道
+func TestSendBatch(t *testing.T) {
+	var got message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat.postMessage" {
+			t.Errorf("请求路径为 %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer xoxb-token" {
+			t.Errorf("认证头为 %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("无效的消息: %v", err)
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	n := newTestNotifier(t, srv, Config{BotToken: "xoxb-token", Channel: "#releases"}, nil)
+
+	releases := []*github.ReleaseInfo{
+		{Owner: "o", Repository: "a", TagName: "v1.0.0", HTMLURL: "https://github.com/o/a/releases/tag/v1.0.0"},
+		{Owner: "o", Repository: "b", TagName: "v2.0.0", HTMLURL: "https://github.com/o/b/releases/tag/v2.0.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 2}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if got.Channel != "#releases" || got.Text != "GitHub 版本更新汇总（2 个仓库）" {
+		t.Errorf("频道为 %q，消息预览为 %q", got.Channel, got.Text)
+	}
+	data, _ := json.Marshal(got.Blocks)
+	if !strings.Contains(string(data), "\\u003chttps://github.com/o/a/releases/tag/v1.0.0|o/a\\u003e") || !strings.Contains(string(data), "`v2.0.0`") {
+		t.Errorf("消息块不正确: %s", data)
+	}
+}
+
+// TestSend_InvalidBlocks 测试Slack拒绝消息块时以纯文本重新发送一次，其他错误直接返回
+func TestSend_InvalidBlocks(t *testing.T) {
+	var got []message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg message
+		json.NewDecoder(r.Body).Decode(&msg)
+		got = append(got, msg)
+		switch {
+		case msg.Channel == "#missing":
+			w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+		case len(msg.Blocks) > 0:
+			w.Write([]byte(`{"ok":false,"error":"invalid_blocks"}`))
+		default:
+			w.Write([]byte(`{"ok":true}`))
+		}
+	}))
+	defer srv.Close()
+
+	releases := []*github.ReleaseInfo{{Owner: "o", Repository: "a", TagName: "v1.0.0", HTMLURL: "https://github.com/o/a/releases/tag/v1.0.0"}}
+	run := render.RunContext{Timestamp: time.Now(), Total: 1}
+
+	n := newTestNotifier(t, srv, Config{BotToken: "xoxb-token", Channel: "#releases"}, nil)
+	if err := n.SendBatch(releases, run); err != nil {
+		t.Fatalf("纯文本重发应成功: %v", err)
+	}
+	if len(got) != 2 || len(got[1].Blocks) != 0 || !strings.Contains(got[1].Text, "o/a") {
+		t.Fatalf("请求不正确: %+v", got)
+	}
+
+	got = nil
+	n = newTestNotifier(t, srv, Config{BotToken: "xoxb-token", Channel: "#missing"}, nil)
+	if err := n.SendBatch(releases, run); err == nil || !strings.Contains(err.Error(), "channel_not_found") {
+		t.Errorf("应返回Slack API的错误: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("其他错误不应重发，共 %d 次请求", len(got))
+	}
+}
+
+// TestSend_RateLimit 测试429响应按 Retry-After 设置冷却期，冷却期内不发送请求
+func TestSend_RateLimit(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Now())
+	n := newTestNotifier(t, srv, Config{Clock: fake}, nil)
+
+	release := []*github.ReleaseInfo{{Owner: "o", Repository: "a", TagName: "v1.0.0"}}
+	run := render.RunContext{Timestamp: time.Now(), Total: 1}
+	if err := n.SendBatch(release, run); err == nil {
+		t.Fatal("限流时应返回错误")
+	}
+	fake.Advance(10 * time.Second)
+	if err := n.SendBatch(release, run); err == nil || !strings.Contains(err.Error(), "冷却中") {
+		t.Errorf("冷却期内应直接返回错误: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("发送了 %d 次请求，期望 1 次", requests)
+	}
+
+	fake.Advance(30 * time.Second)
+	n.SendBatch(release, run)
+	if requests != 2 {
+		t.Errorf("冷却期结束后应重新发送，共 %d 次请求", requests)
+	}
+}
@@ -0,0 +1,70 @@
+package stdout
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"text/template"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// output 程序启动时的标准输出，进度信息转到标准错误后，版本仍写入这里
+var output io.Writer = os.Stdout
+
+// Config 标准输出配置
+type Config struct {
+	Enabled bool
+	// Writer 写入的目标，为nil时使用标准输出
+	Writer io.Writer
+}
+
+// Notifier 标准输出通知器，每个版本输出一行JSON（NDJSON），便于用 jq 等工具在脚本中处理
+type Notifier struct {
+	config Config
+	mu     sync.Mutex // 保证多行输出不交错
+}
+
+// New 创建标准输出通知器
+// 输出格式与MQTT消息体相同（见 notify schema 的 $defs.release），不使用消息模板
+// 写入标准输出时，其他进度信息改为输出到标准错误，标准输出中只有版本JSON
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.Writer == nil {
+		config.Writer = output
+		os.Stdout = os.Stderr
+	}
+	return &Notifier{config: config}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "stdout"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 输出单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.SendBatch([]*github.ReleaseInfo{release}, run)
+}
+
+// SendBatch 每个版本输出一行
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, _ render.RunContext) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	enc := json.NewEncoder(n.config.Writer)
+	enc.SetEscapeHTML(false)
+	for _, release := range releases {
+		if err := enc.Encode(release); err != nil {
+			return fmt.Errorf("输出版本信息失败: %v", err)
+		}
+	}
+	return nil
+}
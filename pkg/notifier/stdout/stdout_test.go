@@ -0,0 +1,47 @@
+package stdout
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSendBatch 测试每个版本输出一行JSON
+func TestSendBatch(t *testing.T) {
+	var buf bytes.Buffer
+	n, err := New(Config{Enabled: true, Writer: &buf}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Owner: "foo", Repository: "bar", TagName: "v1.0.0", HTMLURL: "https://github.com/foo/bar/releases/tag/v1.0.0?a=1&b=2"},
+		{Owner: "foo", Repository: "baz", TagName: "v2.0.0", Description: "多行\n说明"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{}); err != nil {
+		t.Fatalf("输出失败: %v", err)
+	}
+
+	var lines []github.ReleaseInfo
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var release github.ReleaseInfo
+		if err := json.Unmarshal(scanner.Bytes(), &release); err != nil {
+			t.Fatalf("每行应为一个JSON对象: %v\n%s", err, scanner.Text())
+		}
+		lines = append(lines, release)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("应输出2行，实际 %d 行", len(lines))
+	}
+	if lines[1].Repository != "baz" || lines[1].Description != "多行\n说明" {
+		t.Errorf("第二行内容不正确: %+v", lines[1])
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`\u0026`)) {
+		t.Errorf("链接中的 & 不应转义: %s", buf.String())
+	}
+}
@@ -0,0 +1,132 @@
+package syslog
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// sdID 结构化数据的ID，32473 是 RFC 5612 中保留给示例和文档的企业编号
+const sdID = "release@32473"
+
+// 头部字段的最大长度（RFC 5424 第6节）
+const (
+	maxHostname = 255
+	maxAppName  = 48
+	maxMsgID    = 32
+)
+
+// facilities 设施名称对应的编号
+var facilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// severities 级别名称对应的编号
+var severities = map[string]int{
+	"emerg": 0, "alert": 1, "crit": 2, "err": 3, "error": 3,
+	"warning": 4, "warn": 4, "notice": 5, "info": 6, "debug": 7,
+}
+
+// format 构建单个版本的 RFC 5424 日志，如
+// <133>1 2024-01-02T03:04:05.000000Z host notify 1234 release [release@32473 owner="foo" repo="bar" tag="v1.0.0" ...] foo/bar 发布新版本 v1.0.0 · https://...
+func (n *Notifier) format(release *github.ReleaseInfo, run render.RunContext, now time.Time) string {
+	msgID := release.Event
+	if msgID == "" {
+		msgID = github.EventRelease
+	}
+
+	return fmt.Sprintf("<%d>1 %s %s %s %d %s %s %s",
+		n.priority,
+		now.UTC().Format("2006-01-02T15:04:05.000000Z"),
+		headerField(n.hostname, maxHostname),
+		headerField(n.config.AppName, maxAppName),
+		os.Getpid(),
+		headerField(msgID, maxMsgID),
+		structuredData(release),
+		messageLine(release, run),
+	)
+}
+
+// structuredData 将版本信息写入结构化数据，日志平台可以直接按字段检索
+func structuredData(release *github.ReleaseInfo) string {
+	params := [][2]string{
+		{"owner", release.Owner},
+		{"repo", release.Repository},
+		{"tag", release.TagName},
+		{"name", release.Name},
+		{"url", release.Link()},
+		{"prerelease", strconv.FormatBool(release.Prerelease)},
+	}
+	if release.Event != "" {
+		params = append(params, [2]string{"event", release.Event})
+	}
+	if release.Source != "" {
+		params = append(params, [2]string{"source", release.Source})
+	}
+	if release.PreviousTag != "" {
+		params = append(params, [2]string{"previous_tag", release.PreviousTag})
+	}
+	if !release.PublishedAt.IsZero() {
+		params = append(params, [2]string{"published", release.PublishedAt.UTC().Format(time.RFC3339)})
+	}
+	if release.IsHighlighted() {
+		params = append(params, [2]string{"highlights", strings.Join(release.Highlights, ",")})
+	}
+
+	var b strings.Builder
+	b.WriteString("[" + sdID)
+	for _, p := range params {
+		fmt.Fprintf(&b, ` %s="%s"`, p[0], escapeParam(p[1]))
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// messageLine 日志正文，如 "foo/bar 发布新版本 v1.2.0 (名称) · https://..."
+func messageLine(release *github.ReleaseInfo, run render.RunContext) string {
+	var b strings.Builder
+	if run.Locale == render.LocaleEN {
+		fmt.Fprintf(&b, "%s/%s released %s", release.Owner, release.Repository, release.TagName)
+	} else {
+		fmt.Fprintf(&b, "%s/%s 发布新版本 %s", release.Owner, release.Repository, release.TagName)
+	}
+	if release.Name != "" && release.Name != release.TagName {
+		fmt.Fprintf(&b, " (%s)", release.Name)
+	}
+	if label := release.EventLabel(); label != "" {
+		fmt.Fprintf(&b, " [%s]", label)
+	}
+	fmt.Fprintf(&b, " · %s", release.Link())
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// escapeParam 转义结构化数据参数值中的 "、\ 和 ]
+func escapeParam(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(s)
+}
+
+// headerField 头部字段只允许可打印的ASCII字符，为空时使用 "-"
+func headerField(s string, max int) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r > 32 && r < 127 {
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	if len(out) > max {
+		out = out[:max]
+	}
+	if out == "" {
+		return "-"
+	}
+	return out
+}
@@ -0,0 +1,237 @@
+package syslog
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultAppName 默认的应用名称（APP-NAME）
+const DefaultAppName = "notify"
+
+// timeout 连接和写入的超时
+const timeout = 10 * time.Second
+
+// localSockets 本机syslog守护进程常用的套接字路径
+var localSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Config syslog输出配置
+type Config struct {
+	Enabled bool
+	// Network 传输方式: udp、tcp、tls，为空（或 unix）时写入本机的syslog套接字
+	Network string
+	// Address 远程服务器地址 host:port，未指定端口时UDP和TCP使用514，TLS使用6514
+	// 写入本机时可以指定套接字路径，为空时依次尝试 /dev/log、/var/run/syslog、/var/run/log
+	Address string
+	// InsecureSkipVerify 不校验服务器证书（仅用于测试）
+	InsecureSkipVerify bool
+	// Framing TCP和TLS的分帧方式: octet（RFC 6587 的长度前缀，默认）、newline（每条消息以换行结尾）
+	Framing string
+	// Facility 设施名称，如 user、daemon、local0，为空时使用 local0
+	Facility string
+	// Severity 级别名称，如 info、notice、warning，为空时使用 notice
+	Severity string
+	// AppName 应用名称，为空时使用 notify
+	AppName string
+	// Hostname 主机名，为空时使用本机主机名
+	Hostname string
+	// LocalAddr 绑定的本地IP或网卡名（仅远程传输）
+	LocalAddr string
+}
+
+// Notifier syslog通知器，每个版本写入一条 RFC 5424 格式的日志，版本信息放在结构化数据中，方便日志平台解析
+type Notifier struct {
+	config   Config
+	priority int
+	hostname string
+	address  string
+	dialer   *net.Dialer
+}
+
+// New 创建syslog通知器
+// 日志使用固定的结构化格式，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	facility, ok := facilities[strings.ToLower(config.Facility)]
+	if config.Facility == "" {
+		facility, ok = facilities["local0"], true
+	}
+	if !ok {
+		return nil, fmt.Errorf("无效的syslog设施: %s", config.Facility)
+	}
+	severity, ok := severities[strings.ToLower(config.Severity)]
+	if config.Severity == "" {
+		severity, ok = severities["notice"], true
+	}
+	if !ok {
+		return nil, fmt.Errorf("无效的syslog级别: %s", config.Severity)
+	}
+
+	config.Network = strings.ToLower(config.Network)
+	switch config.Framing {
+	case "":
+		config.Framing = "octet"
+	case "octet", "newline":
+	default:
+		return nil, fmt.Errorf("无效的syslog分帧方式: %s（应为 octet 或 newline）", config.Framing)
+	}
+	if config.AppName == "" {
+		config.AppName = DefaultAppName
+	}
+
+	n := &Notifier{
+		config:   config,
+		priority: facility*8 + severity,
+		hostname: config.Hostname,
+		dialer:   &net.Dialer{Timeout: timeout},
+	}
+	if n.hostname == "" {
+		n.hostname, _ = os.Hostname()
+	}
+
+	switch config.Network {
+	case "", "unix":
+		n.address = config.Address
+	case "udp", "tcp", "tls":
+		if config.Address == "" {
+			return nil, fmt.Errorf("syslog服务器地址不能为空")
+		}
+		host, port, err := net.SplitHostPort(config.Address)
+		if err != nil {
+			// 未指定端口
+			host = config.Address
+			port = "514"
+			if config.Network == "tls" {
+				port = "6514"
+			}
+		}
+		if host == "" {
+			return nil, fmt.Errorf("无效的syslog服务器地址: %s", config.Address)
+		}
+		n.address = net.JoinHostPort(host, port)
+
+		if config.LocalAddr != "" {
+			ip, err := util.ResolveLocalAddr(config.LocalAddr)
+			if err != nil {
+				return nil, err
+			}
+			if config.Network == "udp" {
+				n.dialer.LocalAddr = &net.UDPAddr{IP: ip}
+			} else {
+				n.dialer.LocalAddr = &net.TCPAddr{IP: ip}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("无效的syslog传输方式: %s（应为 udp、tcp 或 tls，为空时写入本机）", config.Network)
+	}
+
+	return n, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "syslog"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 写入单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.SendBatch([]*github.ReleaseInfo{release}, run)
+}
+
+// SendBatch 每个版本写入一条日志，日志平台中每条记录对应一个版本
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	messages := make([]string, 0, len(releases))
+	for _, release := range releases {
+		messages = append(messages, n.format(release, run, now))
+	}
+	return n.deliver(messages)
+}
+
+// deliver 连接syslog服务器或本机套接字，写入全部日志后关闭连接
+func (n *Notifier) deliver(messages []string) error {
+	conn, framing, err := n.dial()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(timeout))
+	for _, msg := range messages {
+		frame := msg
+		switch framing {
+		case "octet":
+			frame = fmt.Sprintf("%d %s", len(msg), msg)
+		case "newline":
+			frame = msg + "\n"
+		}
+		if _, err := conn.Write([]byte(frame)); err != nil {
+			return fmt.Errorf("写入syslog失败: %v", err)
+		}
+	}
+	return nil
+}
+
+// dial 建立连接，同时返回流式连接使用的分帧方式，数据报连接每条日志单独发送，分帧方式为空
+func (n *Notifier) dial() (net.Conn, string, error) {
+	switch n.config.Network {
+	case "udp":
+		conn, err := n.dialer.Dial("udp", n.address)
+		if err != nil {
+			return nil, "", fmt.Errorf("连接syslog服务器失败: %v", err)
+		}
+		return conn, "", nil
+	case "tcp":
+		conn, err := n.dialer.Dial("tcp", n.address)
+		if err != nil {
+			return nil, "", fmt.Errorf("连接syslog服务器失败: %v", err)
+		}
+		return conn, n.config.Framing, nil
+	case "tls":
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		host, _, _ := net.SplitHostPort(n.address)
+		d := &tls.Dialer{NetDialer: n.dialer, Config: &tls.Config{
+			ServerName:         host,
+			InsecureSkipVerify: n.config.InsecureSkipVerify,
+			MinVersion:         tls.VersionTLS12,
+		}}
+		conn, err := d.DialContext(ctx, "tcp", n.address)
+		if err != nil {
+			return nil, "", fmt.Errorf("连接syslog服务器失败: %v", err)
+		}
+		return conn, n.config.Framing, nil
+	}
+
+	// 本机套接字，优先使用数据报，守护进程只监听流式套接字时每条日志以换行结尾
+	paths := localSockets
+	if n.address != "" {
+		paths = []string{n.address}
+	}
+	for _, path := range paths {
+		if conn, err := net.DialTimeout("unixgram", path, timeout); err == nil {
+			return conn, "", nil
+		}
+		if conn, err := net.DialTimeout("unix", path, timeout); err == nil {
+			return conn, "newline", nil
+		}
+	}
+	return nil, "", fmt.Errorf("连接本机syslog失败: 找不到可用的套接字（%s）", strings.Join(paths, "、"))
+}
@@ -0,0 +1,123 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestFormat 测试优先级、头部字段和结构化数据的转义
+func TestFormat(t *testing.T) {
+	n, err := New(Config{Enabled: true, Network: "udp", Address: "127.0.0.1", Facility: "daemon", Severity: "info", Hostname: "build host"}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	if n.address != "127.0.0.1:514" {
+		t.Errorf("未指定端口时应使用514: %s", n.address)
+	}
+
+	release := &github.ReleaseInfo{
+		Owner:      "foo",
+		Repository: "bar",
+		TagName:    "v1.0.0",
+		Name:       `say "hi" [beta]`,
+		HTMLURL:    "https://github.com/foo/bar/releases/tag/v1.0.0",
+	}
+	msg := n.format(release, render.RunContext{}, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	// daemon(3)*8 + info(6) = 30
+	if !strings.HasPrefix(msg, "<30>1 2024-01-02T03:04:05.000000Z buildhost notify ") {
+		t.Errorf("头部不正确: %s", msg)
+	}
+	for _, want := range []string{
+		` release [release@32473 owner="foo" repo="bar" tag="v1.0.0"`,
+		`name="say \"hi\" [beta\]"`,
+		`prerelease="false"`,
+		"] foo/bar 发布新版本 v1.0.0 (say \"hi\" [beta]) · https://github.com/foo/bar/releases/tag/v1.0.0",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("日志中应包含 %q，实际为:\n%s", want, msg)
+		}
+	}
+}
+
+// TestSendBatchTCP 测试TCP的长度前缀分帧
+func TestSendBatchTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("监听失败: %v", err)
+	}
+	defer ln.Close()
+
+	received := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+		var frames []string
+		r := bufio.NewReader(conn)
+		for {
+			prefix, err := r.ReadString(' ')
+			if err != nil {
+				break
+			}
+			length, err := strconv.Atoi(strings.TrimSpace(prefix))
+			if err != nil {
+				break
+			}
+			buf := make([]byte, length)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				break
+			}
+			frames = append(frames, string(buf))
+		}
+		received <- frames
+	}()
+
+	n, err := New(Config{Enabled: true, Network: "tcp", Address: ln.Addr().String()}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	releases := []*github.ReleaseInfo{
+		{Owner: "foo", Repository: "bar", TagName: "v1.0.0"},
+		{Owner: "foo", Repository: "baz", TagName: "v2.0.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	frames := <-received
+	if len(frames) != 2 {
+		t.Fatalf("应收到2条日志，实际 %d 条", len(frames))
+	}
+	// local0(16)*8 + notice(5) = 133
+	if !strings.HasPrefix(frames[1], "<133>1 ") || !strings.Contains(frames[1], `repo="baz"`) {
+		t.Errorf("日志内容不正确: %s", frames[1])
+	}
+}
+
+// TestNewInvalid 测试无效配置
+func TestNewInvalid(t *testing.T) {
+	for _, config := range []Config{
+		{Facility: "nope"},
+		{Severity: "loud"},
+		{Network: "tcp"},
+		{Network: "sctp", Address: "127.0.0.1"},
+		{Network: "tcp", Address: "127.0.0.1", Framing: "zero"},
+	} {
+		if _, err := New(config, nil); err == nil {
+			t.Errorf("配置 %+v 应返回错误", config)
+		}
+	}
+}
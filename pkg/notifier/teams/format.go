@@ -0,0 +1,458 @@
+package teams
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxBatchItems 批量卡片中最多展示的版本数，避免超过Teams消息28KB的大小限制
+const maxBatchItems = 20
+
+// maxDigestItems 摘要卡片中最多列出的版本数
+const maxDigestItems = 50
+
+// maxDescription 卡片中发布说明的最大字节数
+const maxDescription = 1000
+
+// labels 卡片中的固定文字
+type labels struct {
+	NewRelease  string
+	Repository  string
+	Version     string
+	Published   string
+	Signed      string
+	Pinned      string
+	Assets      string
+	ViewRelease string
+	BatchTitle  string
+	BatchIntro  string
+	DigestTitle string
+	DigestIntro string
+	More        string
+	Contributor string
+}
+
+var labelsZH = labels{
+	NewRelease:  "%s/%s 发布新版本 %s",
+	Repository:  "仓库",
+	Version:     "版本",
+	Published:   "发布时间",
+	Signed:      "签名",
+	Pinned:      "锁定版本",
+	Assets:      "附件",
+	ViewRelease: "查看详情",
+	BatchTitle:  "📦 GitHub 版本更新汇总（%d 个仓库）",
+	BatchIntro:  "共 %d 个仓库发布了新版本：",
+	DigestTitle: "📦 今天还有 %d 个新版本",
+	DigestIntro: "今天的消息数已达到上限，以下版本合并发送：",
+	More:        "...以及其他 %d 个版本",
+	Contributor: "🙌 你参与贡献的仓库",
+}
+
+var labelsEN = labels{
+	NewRelease:  "%s/%s released %s",
+	Repository:  "Repository",
+	Version:     "Version",
+	Published:   "Published",
+	Signed:      "Signed",
+	Pinned:      "Pinned",
+	Assets:      "Assets",
+	ViewRelease: "View release",
+	BatchTitle:  "📦 GitHub release summary (%d repositories)",
+	BatchIntro:  "%d repositories published new releases:",
+	DigestTitle: "📦 %d more releases today",
+	DigestIntro: "The daily message limit has been reached, the remaining releases are combined:",
+	More:        "...and %d more",
+	Contributor: "🙌 You contribute here",
+}
+
+// labelsFor 按渠道语言选择卡片文字
+func labelsFor(run render.RunContext) labels {
+	if run.Locale == render.LocaleEN {
+		return labelsEN
+	}
+	return labelsZH
+}
+
+// truncate 按字节数截断文本，不截断多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	n -= len("...")
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
+
+// releaseTitle 卡片标题，非普通新版本时加上事件标签
+func releaseTitle(release *github.ReleaseInfo, l labels) string {
+	title := fmt.Sprintf(l.NewRelease, release.Owner, release.Repository, release.TagName)
+	if label := release.EventLabel(); label != "" {
+		title = label + " " + title
+	}
+	return title
+}
+
+// releaseFacts 版本的仓库、版本号、发布时间等信息
+func releaseFacts(release *github.ReleaseInfo, run render.RunContext, l labels) []fact {
+	facts := []fact{
+		{Title: l.Repository, Value: fmt.Sprintf("%s/%s", release.Owner, release.Repository)},
+		{Title: l.Version, Value: release.TagName},
+		{Title: l.Published, Value: run.FormatTime(release.PublishedAt)},
+	}
+	if release.SignatureChecked {
+		facts = append(facts, fact{Title: l.Signed, Value: release.SignatureStatus()})
+	}
+	if release.PinnedVersion != "" {
+		facts = append(facts, fact{Title: l.Pinned, Value: release.PinnedStatus()})
+	}
+	if len(release.MatchedAssets) > 0 {
+		facts = append(facts, fact{Title: l.Assets, Value: strings.Join(release.MatchedAssets, ", ")})
+	}
+	return facts
+}
+
+// fact 键值对，Adaptive Card 的 FactSet 和 MessageCard 的 facts 共用
+type fact struct {
+	Title string `json:"title,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value"`
+}
+
+// asMessageCardFacts MessageCard 的键名字段为 name
+func asMessageCardFacts(facts []fact) []fact {
+	converted := make([]fact, len(facts))
+	for i, f := range facts {
+		converted[i] = fact{Name: f.Title, Value: f.Value}
+	}
+	return converted
+}
+
+// element Adaptive Card 元素（TextBlock、FactSet、Container）
+type element struct {
+	Type      string    `json:"type"`
+	Text      string    `json:"text,omitempty"`
+	Size      string    `json:"size,omitempty"`
+	Weight    string    `json:"weight,omitempty"`
+	Color     string    `json:"color,omitempty"`
+	IsSubtle  bool      `json:"isSubtle,omitempty"`
+	Wrap      bool      `json:"wrap,omitempty"`
+	Separator bool      `json:"separator,omitempty"`
+	Facts     []fact    `json:"facts,omitempty"`
+	Items     []element `json:"items,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	AltText   string    `json:"altText,omitempty"`
+}
+
+// action Adaptive Card 操作
+type action struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// adaptiveCard Adaptive Card 内容
+type adaptiveCard struct {
+	Schema  string    `json:"$schema"`
+	Type    string    `json:"type"`
+	Version string    `json:"version"`
+	Body    []element `json:"body"`
+	Actions []action  `json:"actions,omitempty"`
+	MSTeams struct {
+		Width string `json:"width"`
+	} `json:"msteams"`
+}
+
+func textBlock(text string) element {
+	return element{Type: "TextBlock", Text: text, Wrap: true}
+}
+
+func heading(text string) element {
+	return element{Type: "TextBlock", Text: text, Size: "Large", Weight: "Bolder", Wrap: true}
+}
+
+func subtle(text string) element {
+	return element{Type: "TextBlock", Text: text, Size: "Small", IsSubtle: true, Wrap: true}
+}
+
+func newAdaptiveCard(body []element, actions []action) adaptiveCard {
+	card := adaptiveCard{
+		Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:    "AdaptiveCard",
+		Version: "1.4",
+		Body:    body,
+		Actions: actions,
+	}
+	card.MSTeams.Width = "Full"
+	return card
+}
+
+// wrapAdaptive 将 Adaptive Card 包装为webhook消息
+func wrapAdaptive(card adaptiveCard) interface{} {
+	type attachment struct {
+		ContentType string       `json:"contentType"`
+		ContentURL  *string      `json:"contentUrl"`
+		Content     adaptiveCard `json:"content"`
+	}
+
+	return struct {
+		Type        string       `json:"type"`
+		Attachments []attachment `json:"attachments"`
+	}{
+		Type: "message",
+		Attachments: []attachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content:     card,
+		}},
+	}
+}
+
+// releaseCard 构建单个版本的 Adaptive Card，image 不为空时在卡片中附带预览图
+func releaseCard(release *github.ReleaseInfo, run render.RunContext, image string) adaptiveCard {
+	l := labelsFor(run)
+	body := []element{heading(releaseTitle(release, l))}
+	if release.IsHighlighted() {
+		body = append(body, element{Type: "TextBlock", Text: release.HighlightBanner(), Color: "Attention", Weight: "Bolder", Wrap: true})
+	}
+	if release.Contributed {
+		body = append(body, element{Type: "TextBlock", Text: l.Contributor, Color: "Good", Wrap: true})
+	}
+	body = append(body, element{Type: "FactSet", Facts: releaseFacts(release, run, l)})
+	if image != "" {
+		body = append(body, element{Type: "Image", URL: image, AltText: release.Owner + "/" + release.Repository, Size: "Stretch"})
+	}
+	if release.NotesDiff != "" {
+		body = append(body, textBlock(release.NotesDiff))
+	}
+	if release.Description != "" {
+		body = append(body, element{Type: "TextBlock", Text: truncate(release.Description, maxDescription), Wrap: true, Separator: true})
+	}
+	if footer := run.Footer(); footer != "" {
+		body = append(body, subtle(footer))
+	}
+
+	var actions []action
+	if release.Link() != "" {
+		actions = append(actions, action{Type: "Action.OpenUrl", Title: l.ViewRelease, URL: release.Link()})
+	}
+	return newAdaptiveCard(body, actions)
+}
+
+// batchCard 构建批量 Adaptive Card，每个版本一段
+func batchCard(releases []*github.ReleaseInfo, run render.RunContext) adaptiveCard {
+	l := labelsFor(run)
+	body := []element{
+		heading(fmt.Sprintf(l.BatchTitle, len(releases))),
+		textBlock(fmt.Sprintf(l.BatchIntro, len(releases))),
+	}
+
+	for i, release := range releases {
+		if i == maxBatchItems {
+			body = append(body, element{Type: "TextBlock", Text: fmt.Sprintf(l.More, len(releases)-maxBatchItems), Wrap: true, Separator: true})
+			break
+		}
+
+		items := []element{{
+			Type:   "TextBlock",
+			Text:   fmt.Sprintf("%d. [%s/%s](%s)", i+1, release.Owner, release.Repository, release.Link()),
+			Weight: "Bolder",
+			Wrap:   true,
+		}}
+		if label := release.EventLabel(); label != "" {
+			items = append(items, textBlock(label))
+		}
+		if release.IsHighlighted() {
+			items = append(items, element{Type: "TextBlock", Text: release.HighlightBanner(), Color: "Attention", Wrap: true})
+		}
+		if release.Contributed {
+			items = append(items, element{Type: "TextBlock", Text: l.Contributor, Color: "Good", Wrap: true})
+		}
+		items = append(items, element{Type: "FactSet", Facts: releaseFacts(release, run, l)[1:]})
+
+		body = append(body, element{Type: "Container", Items: items, Separator: true})
+	}
+
+	if footer := run.Footer(); footer != "" {
+		body = append(body, subtle(footer))
+	}
+	return newAdaptiveCard(body, nil)
+}
+
+// digestCard 构建超过每日上限后的摘要 Adaptive Card，每个版本只占一行
+func digestCard(releases []*github.ReleaseInfo, run render.RunContext) adaptiveCard {
+	l := labelsFor(run)
+	body := []element{
+		heading(fmt.Sprintf(l.DigestTitle, len(releases))),
+		textBlock(l.DigestIntro),
+		textBlock(digestLines(releases, l)),
+	}
+	if footer := run.Footer(); footer != "" {
+		body = append(body, subtle(footer))
+	}
+	return newAdaptiveCard(body, nil)
+}
+
+// digestLines 摘要中的版本列表
+func digestLines(releases []*github.ReleaseInfo, l labels) string {
+	var lines strings.Builder
+	for i, release := range releases {
+		if i == maxDigestItems {
+			lines.WriteString(fmt.Sprintf(l.More, len(releases)-maxDigestItems))
+			break
+		}
+		lines.WriteString(fmt.Sprintf("- [%s/%s](%s) %s\n",
+			release.Owner, release.Repository, release.Link(), release.TagName))
+	}
+	return lines.String()
+}
+
+// messageCard 旧版连接器的 MessageCard
+type messageCard struct {
+	Type            string           `json:"@type"`
+	Context         string           `json:"@context"`
+	Summary         string           `json:"summary"`
+	ThemeColor      string           `json:"themeColor"`
+	Title           string           `json:"title"`
+	Text            string           `json:"text,omitempty"`
+	Sections        []messageSection `json:"sections,omitempty"`
+	PotentialAction []messageAction  `json:"potentialAction,omitempty"`
+}
+
+type messageSection struct {
+	ActivityTitle    string `json:"activityTitle,omitempty"`
+	ActivitySubtitle string `json:"activitySubtitle,omitempty"`
+	Text             string `json:"text,omitempty"`
+	Facts            []fact `json:"facts,omitempty"`
+	// HeroImage 卡片中的大图
+	HeroImage *heroImage `json:"heroImage,omitempty"`
+}
+
+type heroImage struct {
+	Image string `json:"image"`
+}
+
+type messageAction struct {
+	Type    string          `json:"@type"`
+	Name    string          `json:"name"`
+	Targets []messageTarget `json:"targets"`
+}
+
+type messageTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+func newMessageCard(title, themeColor string) messageCard {
+	return messageCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		Summary:    title,
+		ThemeColor: themeColor,
+		Title:      title,
+	}
+}
+
+// themeColor 根据版本选择卡片颜色
+func themeColor(release *github.ReleaseInfo) string {
+	switch {
+	case release.IsHighlighted():
+		return "D13438"
+	case release.Event == github.EventRelease && release.Prerelease:
+		return "CA5010"
+	default:
+		return "0078D7"
+	}
+}
+
+// releaseMessageCard 构建单个版本的 MessageCard，image 不为空时在卡片中附带预览图
+func releaseMessageCard(release *github.ReleaseInfo, run render.RunContext, image string) messageCard {
+	l := labelsFor(run)
+	card := newMessageCard(releaseTitle(release, l), themeColor(release))
+
+	section := messageSection{Facts: asMessageCardFacts(releaseFacts(release, run, l))}
+	if release.IsHighlighted() {
+		section.ActivityTitle = release.HighlightBanner()
+	}
+	if release.Contributed {
+		section.ActivitySubtitle = l.Contributor
+	}
+	if image != "" {
+		section.HeroImage = &heroImage{Image: image}
+	}
+	var text []string
+	if release.NotesDiff != "" {
+		text = append(text, release.NotesDiff)
+	}
+	if release.Description != "" {
+		text = append(text, truncate(release.Description, maxDescription))
+	}
+	if footer := run.Footer(); footer != "" {
+		text = append(text, footer)
+	}
+	section.Text = strings.Join(text, "\n\n")
+	card.Sections = []messageSection{section}
+
+	if release.Link() != "" {
+		card.PotentialAction = []messageAction{{
+			Type:    "OpenUri",
+			Name:    l.ViewRelease,
+			Targets: []messageTarget{{OS: "default", URI: release.Link()}},
+		}}
+	}
+	return card
+}
+
+// batchMessageCard 构建批量 MessageCard，每个版本一个分段
+func batchMessageCard(releases []*github.ReleaseInfo, run render.RunContext) messageCard {
+	l := labelsFor(run)
+	card := newMessageCard(fmt.Sprintf(l.BatchTitle, len(releases)), "0078D7")
+	card.Text = fmt.Sprintf(l.BatchIntro, len(releases))
+
+	for i, release := range releases {
+		if i == maxBatchItems {
+			card.Sections = append(card.Sections, messageSection{Text: fmt.Sprintf(l.More, len(releases)-maxBatchItems)})
+			break
+		}
+		if release.IsHighlighted() {
+			card.ThemeColor = "D13438"
+		}
+
+		section := messageSection{
+			ActivityTitle: fmt.Sprintf("%d. [%s/%s](%s)", i+1, release.Owner, release.Repository, release.Link()),
+			Facts:         asMessageCardFacts(releaseFacts(release, run, l)[1:]),
+		}
+		if label := release.EventLabel(); label != "" {
+			section.Text = label
+		}
+		if release.IsHighlighted() {
+			section.Text = strings.TrimSpace(section.Text + "\n\n" + release.HighlightBanner())
+		}
+		if release.Contributed {
+			section.Text = strings.TrimSpace(section.Text + "\n\n" + l.Contributor)
+		}
+		card.Sections = append(card.Sections, section)
+	}
+
+	if footer := run.Footer(); footer != "" {
+		card.Sections = append(card.Sections, messageSection{Text: footer})
+	}
+	return card
+}
+
+// digestMessageCard 构建超过每日上限后的摘要 MessageCard
+func digestMessageCard(releases []*github.ReleaseInfo, run render.RunContext) messageCard {
+	l := labelsFor(run)
+	card := newMessageCard(fmt.Sprintf(l.DigestTitle, len(releases)), "0078D7")
+	card.Text = l.DigestIntro
+	card.Sections = []messageSection{{Text: digestLines(releases, l)}}
+	if footer := run.Footer(); footer != "" {
+		card.Sections = append(card.Sections, messageSection{Text: footer})
+	}
+	return card
+}
@@ -0,0 +1,198 @@
+package teams
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// 消息格式
+const (
+	// FormatAdaptive Adaptive Card（默认），适用于Workflows和新版传入webhook
+	FormatAdaptive = "adaptive"
+	// FormatMessageCard 旧版 Office 365 连接器的 MessageCard
+	FormatMessageCard = "messagecard"
+)
+
+// defaultCooldown 限流响应没有给出 Retry-After 时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// DefaultPace 默认发送速率
+// Teams传入webhook限制为每秒4条、每30秒60条，这里设置为每秒1条，突发允许3条
+var DefaultPace = pacing.Limit{Interval: time.Second, Burst: 3}
+
+// Config Microsoft Teams 传入webhook配置
+type Config struct {
+	Enabled    bool
+	WebhookURL string
+	// Format 消息格式: adaptive（默认）或 messagecard
+	Format string
+	// PreviewImage 单个版本的卡片是否附带仓库的OpenGraph预览图
+	PreviewImage bool
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier Microsoft Teams 通知器
+type Notifier struct {
+	config  Config
+	client  *http.Client
+	limiter *pacing.Bucket // 速率限制器
+	mu      sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// New 创建Teams通知器
+// Teams消息使用固定的卡片格式展示仓库、版本和发布时间，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("Teams webhook URL不能为空")
+	}
+
+	switch strings.ToLower(config.Format) {
+	case "":
+		config.Format = FormatAdaptive
+	case FormatAdaptive, FormatMessageCard:
+		config.Format = strings.ToLower(config.Format)
+	default:
+		return nil, fmt.Errorf("不支持的Teams消息格式: %s（可选 adaptive、messagecard）", config.Format)
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("teams", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:  config,
+		client:  client,
+		limiter: limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "teams"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	var image string
+	if n.config.PreviewImage {
+		image = release.OpenGraphImageURL()
+	}
+	if n.config.Format == FormatMessageCard {
+		return n.post(releaseMessageCard(release, run, image))
+	}
+	return n.post(wrapAdaptive(releaseCard(release, run, image)))
+}
+
+// SendBatch 批量发送（合并成一张卡片）
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	if n.config.Format == FormatMessageCard {
+		return n.post(batchMessageCard(releases, run))
+	}
+	return n.post(wrapAdaptive(batchCard(releases, run)))
+}
+
+// SendDigest 将超过每日上限的版本合并为一张摘要卡片发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	if n.config.Format == FormatMessageCard {
+		return n.post(digestMessageCard(releases, run))
+	}
+	return n.post(wrapAdaptive(digestCard(releases, run)))
+}
+
+// post 发送消息到Teams webhook
+func (n *Notifier) post(msg interface{}) error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+	if remaining > 0 {
+		return fmt.Errorf("Teams消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	resp, err := n.client.Post(n.config.WebhookURL, "application/json", bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	text := strings.TrimSpace(string(body))
+
+	// 旧版连接器在限流时可能返回200，并在响应正文中说明错误
+	if resp.StatusCode == http.StatusTooManyRequests || strings.Contains(text, "HTTP error 429") {
+		wait := defaultCooldown
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("触发Teams限流，已设置%v冷却期: rate limit exceeded", wait)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Teams请求失败，状态码: %d (%s)", resp.StatusCode, text)
+	}
+	if strings.HasPrefix(text, "Webhook message delivery failed") {
+		return fmt.Errorf("Teams消息投递失败: %s", text)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package teams
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// sentMessage Teams webhook收到的消息，同时包含 Adaptive Card 和 MessageCard 的字段
+type sentMessage struct {
+	// Adaptive Card
+	Type        string `json:"type"`
+	Attachments []struct {
+		ContentType string       `json:"contentType"`
+		Content     adaptiveCard `json:"content"`
+	} `json:"attachments"`
+	// MessageCard
+	CardType        string           `json:"@type"`
+	Context         string           `json:"@context"`
+	Title           string           `json:"title"`
+	Sections        []messageSection `json:"sections"`
+	PotentialAction []messageAction  `json:"potentialAction"`
+}
+
+// TestSend_Format 测试各消息格式发送的JSON结构
+func TestSend_Format(t *testing.T) {
+	tests := []struct {
+		format   string
+		adaptive bool
+	}{
+		{"", true},
+		{"adaptive", true},
+		{"messagecard", false},
+		{"MessageCard", false},
+	}
+
+	release := &github.ReleaseInfo{
+		Event:      github.EventRelease,
+		Owner:      "o",
+		Repository: "r",
+		TagName:    "v1.0.0",
+		HTMLURL:    "https://github.com/o/r/releases/tag/v1.0.0",
+	}
+	run := render.RunContext{Timestamp: time.Now(), Total: 2}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var messages []sentMessage
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				var msg sentMessage
+				if err := json.Unmarshal(body, &msg); err != nil {
+					t.Errorf("无效的消息: %s", body)
+				}
+				messages = append(messages, msg)
+			}))
+			defer srv.Close()
+
+			n, err := New(Config{
+				Enabled:    true,
+				WebhookURL: srv.URL,
+				Format:     tt.format,
+				Bucket:     pacing.NewBucket("teams", pacing.Limit{Burst: 10}),
+			}, nil)
+			if err != nil {
+				t.Fatalf("创建通知器失败: %v", err)
+			}
+
+			if err := n.Send(release, run); err != nil {
+				t.Fatalf("发送失败: %v", err)
+			}
+			if err := n.SendBatch([]*github.ReleaseInfo{release, release}, run); err != nil {
+				t.Fatalf("批量发送失败: %v", err)
+			}
+			if err := n.SendDigest([]*github.ReleaseInfo{release}, run); err != nil {
+				t.Fatalf("发送摘要失败: %v", err)
+			}
+			if len(messages) != 3 {
+				t.Fatalf("收到 %d 条消息，期望 3 条", len(messages))
+			}
+
+			for i, msg := range messages {
+				if tt.adaptive {
+					if msg.Type != "message" || len(msg.Attachments) != 1 || msg.CardType != "" {
+						t.Fatalf("第 %d 条消息不是 Adaptive Card 消息: %+v", i+1, msg)
+					}
+					att := msg.Attachments[0]
+					if att.ContentType != "application/vnd.microsoft.card.adaptive" ||
+						att.Content.Type != "AdaptiveCard" || att.Content.Version != "1.4" || len(att.Content.Body) == 0 {
+						t.Errorf("第 %d 条消息的 Adaptive Card 不正确: %+v", i+1, att)
+					}
+				} else {
+					if msg.CardType != "MessageCard" || msg.Context != "https://schema.org/extensions" || len(msg.Attachments) != 0 {
+						t.Fatalf("第 %d 条消息不是 MessageCard: %+v", i+1, msg)
+					}
+					if msg.Title == "" || len(msg.Sections) == 0 {
+						t.Errorf("第 %d 条消息的 MessageCard 不正确: %+v", i+1, msg)
+					}
+				}
+			}
+
+			// 单个版本的卡片带查看详情按钮，分别使用两种格式的字段
+			single := messages[0]
+			if tt.adaptive {
+				actions := single.Attachments[0].Content.Actions
+				if len(actions) != 1 || actions[0].Type != "Action.OpenUrl" || actions[0].URL != release.HTMLURL {
+					t.Errorf("Adaptive Card 查看详情按钮不正确: %+v", actions)
+				}
+				facts := single.Attachments[0].Content.Body[1].Facts
+				if len(facts) == 0 || facts[0].Title != "仓库" || facts[0].Name != "" {
+					t.Errorf("Adaptive Card 的 FactSet 应使用 title 字段: %+v", facts)
+				}
+			} else {
+				if len(single.PotentialAction) != 1 || single.PotentialAction[0].Type != "OpenUri" ||
+					single.PotentialAction[0].Targets[0].URI != release.HTMLURL {
+					t.Errorf("MessageCard 查看详情按钮不正确: %+v", single.PotentialAction)
+				}
+				facts := single.Sections[0].Facts
+				if len(facts) == 0 || facts[0].Name != "仓库" || facts[0].Title != "" {
+					t.Errorf("MessageCard 的 facts 应使用 name 字段: %+v", facts)
+				}
+			}
+		})
+	}
+}
+
+// TestNew_InvalidFormat 测试不支持的消息格式在创建时报错
+func TestNew_InvalidFormat(t *testing.T) {
+	if _, err := New(Config{Enabled: true, WebhookURL: "https://example.com", Format: "text"}, nil); err == nil {
+		t.Error("不支持的消息格式应返回错误")
+	}
+}
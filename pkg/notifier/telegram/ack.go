@@ -0,0 +1,47 @@
+package telegram
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// callbackAck "已查看"按钮的回调数据，形如 ack:<确认标识>
+const callbackAck = "ack:"
+
+// ackMarkup 返回版本消息下方的"已查看"按钮，未启用确认或没有确认标识时返回nil
+func (n *Notifier) ackMarkup(run render.RunContext) *replyMarkup {
+	if !n.config.Ack || run.AckID == "" {
+		return nil
+	}
+	return &replyMarkup{InlineKeyboard: [][]inlineButton{{
+		{Text: "👀 已查看", CallbackData: callbackAck + run.AckID},
+	}}}
+}
+
+// PollAcks 通过 getUpdates 读取"已查看"按钮的回调，返回确认标识到确认人（@用户名或用户ID）的映射
+// 同一条消息只记录第一个确认的人，确认后移除按钮；与审批按钮一样，机器人不要同时用于其他需要接收消息的用途
+func (n *Notifier) PollAcks() (map[string]string, error) {
+	queries, err := n.readCallbacks()
+	if err != nil {
+		return nil, fmt.Errorf("读取Telegram确认回调失败: %v", err)
+	}
+
+	acks := make(map[string]string)
+	for _, query := range queries {
+		if !strings.HasPrefix(query.Data, callbackAck) {
+			continue
+		}
+		id := strings.TrimPrefix(query.Data, callbackAck)
+		user := describeUser(query.From.ID, query.From.Username)
+		if _, ok := acks[id]; !ok {
+			acks[id] = user
+			log.Printf("Telegram用户 %s 已查看消息 %s", user, id)
+		}
+		n.answerCallback(query.ID, "已记录为已查看")
+		n.removeButtons(query.Message.Chat.ID, query.Message.MessageID)
+	}
+	return acks, nil
+}
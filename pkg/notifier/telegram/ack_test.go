@@ -0,0 +1,85 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestAck 测试启用确认时版本消息附带"已查看"按钮，以及读取按钮回调：只记录第一个确认的人，忽略审批按钮和其他聊天
+func TestAck(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string][]map[string]interface{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		calls[method] = append(calls[method], body)
+		first := len(calls[method]) == 1
+		mu.Unlock()
+
+		if method == "getUpdates" && first {
+			w.Write([]byte(`{"ok":true,"result":[
+				{"update_id":20,"callback_query":{"id":"q1","from":{"id":1,"username":"alice"},"message":{"message_id":5,"chat":{"id":-100}},"data":"ack:abc"}},
+				{"update_id":21,"callback_query":{"id":"q2","from":{"id":2},"message":{"message_id":5,"chat":{"id":-100}},"data":"ack:abc"}},
+				{"update_id":22,"callback_query":{"id":"q3","from":{"id":2},"message":{"message_id":6,"chat":{"id":-100}},"data":"ack:def"}},
+				{"update_id":23,"callback_query":{"id":"q4","from":{"id":1},"message":{"message_id":7,"chat":{"id":-100}},"data":"approve:xyz"}},
+				{"update_id":24,"callback_query":{"id":"q5","from":{"id":1},"message":{"message_id":8,"chat":{"id":-200}},"data":"ack:ghi"}}
+			]}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled:    true,
+		BotToken:   "t",
+		ChatID:     "-100",
+		Ack:        true,
+		APIBaseURL: srv.URL,
+		Bucket:     pacing.NewBucket("telegram", pacing.Limit{Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{{Owner: "o", Repository: "r", TagName: "v1.0.0"}}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 1, AckID: "abc"}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 1}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	sent := calls["sendMessage"]
+	buttons, _ := json.Marshal(sent[0]["reply_markup"])
+	if !strings.Contains(string(buttons), `"callback_data":"ack:abc"`) {
+		t.Errorf("消息的按钮为 %s，期望带有 ack:abc", buttons)
+	}
+	if _, ok := sent[1]["reply_markup"]; ok {
+		t.Errorf("没有确认标识的消息不应附带按钮: %v", sent[1])
+	}
+
+	acks, err := n.PollAcks()
+	if err != nil {
+		t.Fatalf("读取确认回调失败: %v", err)
+	}
+	if len(acks) != 2 || acks["abc"] != "@alice" || acks["def"] != "2" {
+		t.Errorf("确认结果为 %v", acks)
+	}
+	if got := calls["getUpdates"]; len(got) != 2 || got[1]["offset"] != float64(25) {
+		t.Errorf("应使用 offset=25 确认已读取的更新: %v", got)
+	}
+	if got := len(calls["answerCallbackQuery"]); got != 3 {
+		t.Errorf("回复了 %d 个回调，期望 3 个", got)
+	}
+}
@@ -0,0 +1,263 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// 审批按钮的回调数据，形如 approve:<批次ID>
+const (
+	callbackApprove = "approve:"
+	callbackReject  = "reject:"
+)
+
+// inlineButton 消息下方的按钮
+type inlineButton struct {
+	Text         string `json:"text"`
+	CallbackData string `json:"callback_data"`
+}
+
+// replyMarkup 消息下方的按钮布局，InlineKeyboard 为空时移除按钮
+type replyMarkup struct {
+	InlineKeyboard [][]inlineButton `json:"inline_keyboard"`
+}
+
+// SendApprovalRequest 发送带"批准""拒绝"按钮的待审批汇总
+func (n *Notifier) SendApprovalRequest(id string, releases []*github.ReleaseInfo, run render.RunContext) error {
+	// 检查是否可以发送消息
+	canSend, remaining := n.canSendMessage()
+	if !canSend {
+		return fmt.Errorf("Telegram消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	// 控制发送频率
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	type messageRequest struct {
+		ChatID      string      `json:"chat_id"`
+		Text        string      `json:"text"`
+		ParseMode   string      `json:"parse_mode,omitempty"`
+		ReplyMarkup replyMarkup `json:"reply_markup"`
+	}
+	buttons := replyMarkup{InlineKeyboard: [][]inlineButton{{
+		{Text: "✅ 批准", CallbackData: callbackApprove + id},
+		{Text: "❌ 拒绝", CallbackData: callbackReject + id},
+	}}}
+	return n.sendWithRetry(func() error {
+		return n.withPlainFallback(n.buildApproval(id, releases, run), func(text, parseMode string) error {
+			return n.callAPI("sendMessage", messageRequest{
+				ChatID:      n.config.ChatID,
+				Text:        text,
+				ParseMode:   parseMode,
+				ReplyMarkup: buttons,
+			}, nil)
+		})
+	})
+}
+
+// buildApproval 构建待审批汇总，每个版本只占一行
+func (n *Notifier) buildApproval(id string, releases []*github.ReleaseInfo, run render.RunContext) string {
+	isHTML := n.config.ParseMode == ParseModeHTML
+	esc := func(s string) string { return s }
+	if isHTML {
+		esc = html.EscapeString
+	}
+
+	var content bytes.Buffer
+	if isHTML {
+		content.WriteString(fmt.Sprintf("📝 <b>待审批：%d 个新版本</b>\n\n", len(releases)))
+	} else {
+		content.WriteString(fmt.Sprintf("📝 *待审批：%d 个新版本*\n\n", len(releases)))
+	}
+
+	for i, release := range releases {
+		if i == maxDigestItems {
+			content.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		name := esc(release.Owner + "/" + release.Repository)
+		if isHTML {
+			content.WriteString(fmt.Sprintf("• <a href=\"%s\">%s</a> <code>%s</code>",
+				esc(release.Link()), name, esc(release.TagName)))
+		} else {
+			content.WriteString(fmt.Sprintf("• [%s](%s) `%s`", name, release.Link(), release.TagName))
+		}
+		if label := release.EventLabel(); label != "" {
+			content.WriteString(" " + esc(label))
+		}
+		content.WriteString("\n")
+	}
+
+	if isHTML {
+		content.WriteString(fmt.Sprintf("\n批次 <code>%s</code>：批准后发送到其他通知渠道，也可以执行 <code>notify approve %s</code>\n", esc(id), esc(id)))
+	} else {
+		content.WriteString(fmt.Sprintf("\n批次 `%s`：批准后发送到其他通知渠道，也可以执行 `notify approve %s`\n", id, id))
+	}
+	if footer := run.Footer(); footer != "" {
+		content.WriteString("\n" + esc(footer) + "\n")
+	}
+
+	return content.String()
+}
+
+// callbackQuery 按钮回调
+type callbackQuery struct {
+	ID   string `json:"id"`
+	From struct {
+		ID       int64  `json:"id"`
+		Username string `json:"username"`
+	} `json:"from"`
+	Message *struct {
+		MessageID int64 `json:"message_id"`
+		Chat      struct {
+			ID       int64  `json:"id"`
+			Username string `json:"username"`
+		} `json:"chat"`
+	} `json:"message"`
+	Data string `json:"data"`
+}
+
+// PollApprovals 通过 getUpdates 读取审批按钮的回调，返回被批准和被拒绝的批次ID
+// approvers 不为空时只接受其中用户（用户名或用户ID）的操作；读取后确认这些更新，下次不会重复返回
+// 机器人设置了webhook时 getUpdates 不可用，审批机器人不要同时用于其他需要接收消息的用途（包括"已查看"按钮）
+func (n *Notifier) PollApprovals(approvers []string) (approved, rejected []string, err error) {
+	queries, err := n.readCallbacks()
+	if err != nil {
+		return nil, nil, fmt.Errorf("读取Telegram审批回调失败: %v", err)
+	}
+
+	for _, query := range queries {
+		var id string
+		var approve bool
+		switch {
+		case strings.HasPrefix(query.Data, callbackApprove):
+			id, approve = strings.TrimPrefix(query.Data, callbackApprove), true
+		case strings.HasPrefix(query.Data, callbackReject):
+			id = strings.TrimPrefix(query.Data, callbackReject)
+		default:
+			continue
+		}
+
+		if !isApprover(approvers, query.From.ID, query.From.Username) {
+			n.answerCallback(query.ID, "你没有审批权限")
+			continue
+		}
+
+		action := "拒绝"
+		if approve {
+			approved = append(approved, id)
+			action = "批准"
+		} else {
+			rejected = append(rejected, id)
+		}
+		log.Printf("Telegram用户 %s %s了审批批次 %s", describeUser(query.From.ID, query.From.Username), action, id)
+		n.answerCallback(query.ID, "已"+action)
+		n.removeButtons(query.Message.Chat.ID, query.Message.MessageID)
+	}
+
+	return approved, rejected, nil
+}
+
+// readCallbacks 通过 getUpdates 读取上次读取以来来自配置的聊天的按钮回调，并确认这些更新，下次不会重复返回
+func (n *Notifier) readCallbacks() ([]*callbackQuery, error) {
+	type updatesRequest struct {
+		Offset         int64    `json:"offset,omitempty"`
+		Limit          int      `json:"limit,omitempty"`
+		Timeout        int      `json:"timeout"`
+		AllowedUpdates []string `json:"allowed_updates"`
+	}
+	var updates []struct {
+		UpdateID      int64          `json:"update_id"`
+		CallbackQuery *callbackQuery `json:"callback_query"`
+	}
+	if err := n.callAPI("getUpdates", updatesRequest{AllowedUpdates: []string{"callback_query"}}, &updates); err != nil {
+		return nil, err
+	}
+
+	var lastID int64
+	var queries []*callbackQuery
+	for _, update := range updates {
+		if update.UpdateID > lastID {
+			lastID = update.UpdateID
+		}
+		query := update.CallbackQuery
+		if query == nil || query.Message == nil || !n.isConfiguredChat(query.Message.Chat.ID, query.Message.Chat.Username) {
+			continue
+		}
+		queries = append(queries, query)
+	}
+
+	// 确认已读取的更新
+	if lastID > 0 {
+		if err := n.callAPI("getUpdates", updatesRequest{Offset: lastID + 1, Limit: 1, AllowedUpdates: []string{"callback_query"}}, nil); err != nil {
+			log.Printf("警告: 确认Telegram更新失败，下次可能重复读取: %v", err)
+		}
+	}
+	return queries, nil
+}
+
+// isConfiguredChat 回调是否来自配置的聊天，ChatID 可以是数字ID或 @频道名
+func (n *Notifier) isConfiguredChat(id int64, username string) bool {
+	if strconv.FormatInt(id, 10) == n.config.ChatID {
+		return true
+	}
+	return username != "" && strings.EqualFold("@"+username, n.config.ChatID)
+}
+
+// isApprover 用户是否有审批权限，approvers 为空时所有人都有权限
+func isApprover(approvers []string, id int64, username string) bool {
+	if len(approvers) == 0 {
+		return true
+	}
+	for _, approver := range approvers {
+		approver = strings.TrimPrefix(approver, "@")
+		if approver == strconv.FormatInt(id, 10) || (username != "" && strings.EqualFold(approver, username)) {
+			return true
+		}
+	}
+	return false
+}
+
+// describeUser 日志中显示的用户
+func describeUser(id int64, username string) string {
+	if username != "" {
+		return "@" + username
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+// answerCallback 回复按钮回调，在用户的客户端上显示提示
+// 回调超过一段时间后不能再回复，审批结果不受影响，失败时只记录日志
+func (n *Notifier) answerCallback(id, text string) {
+	type answerRequest struct {
+		CallbackQueryID string `json:"callback_query_id"`
+		Text            string `json:"text"`
+	}
+	if err := n.callAPI("answerCallbackQuery", answerRequest{CallbackQueryID: id, Text: text}, nil); err != nil {
+		log.Printf("回复Telegram按钮回调失败: %v", err)
+	}
+}
+
+// removeButtons 移除已处理的消息上的按钮，避免重复审批或确认
+func (n *Notifier) removeButtons(chatID, messageID int64) {
+	type editRequest struct {
+		ChatID      int64       `json:"chat_id"`
+		MessageID   int64       `json:"message_id"`
+		ReplyMarkup replyMarkup `json:"reply_markup"`
+	}
+	req := editRequest{ChatID: chatID, MessageID: messageID, ReplyMarkup: replyMarkup{InlineKeyboard: [][]inlineButton{}}}
+	if err := n.callAPI("editMessageReplyMarkup", req, nil); err != nil {
+		log.Printf("移除Telegram审批按钮失败: %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestPollApprovals 测试读取审批按钮回调：只接受配置的聊天和审批人，处理后确认更新并移除按钮
+func TestPollApprovals(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string][]map[string]interface{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		calls[method] = append(calls[method], body)
+		first := len(calls[method]) == 1
+		mu.Unlock()
+
+		if method == "getUpdates" && first {
+			w.Write([]byte(`{"ok":true,"result":[
+				{"update_id":10,"callback_query":{"id":"q1","from":{"id":1,"username":"alice"},"message":{"message_id":5,"chat":{"id":-100}},"data":"approve:abc"}},
+				{"update_id":11,"callback_query":{"id":"q2","from":{"id":2,"username":"mallory"},"message":{"message_id":5,"chat":{"id":-100}},"data":"approve:abc"}},
+				{"update_id":12,"callback_query":{"id":"q3","from":{"id":3,"username":"bob"},"message":{"message_id":6,"chat":{"id":-100}},"data":"reject:def"}},
+				{"update_id":13,"callback_query":{"id":"q4","from":{"id":1,"username":"alice"},"message":{"message_id":7,"chat":{"id":-200}},"data":"approve:xyz"}}
+			]}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{Enabled: true, BotToken: "t", ChatID: "-100", APIBaseURL: srv.URL}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	approved, rejected, err := n.PollApprovals([]string{"@alice", "3"})
+	if err != nil {
+		t.Fatalf("读取审批回调失败: %v", err)
+	}
+	if len(approved) != 1 || approved[0] != "abc" {
+		t.Errorf("批准的批次为 %v，期望 [abc]", approved)
+	}
+	if len(rejected) != 1 || rejected[0] != "def" {
+		t.Errorf("拒绝的批次为 %v，期望 [def]", rejected)
+	}
+
+	if got := calls["getUpdates"]; len(got) != 2 || got[1]["offset"] != float64(14) {
+		t.Errorf("应使用 offset=14 确认已读取的更新: %v", got)
+	}
+	if got := len(calls["answerCallbackQuery"]); got != 3 {
+		t.Errorf("回复了 %d 个回调，期望 3 个（包括无权限的用户）", got)
+	}
+	if got := len(calls["editMessageReplyMarkup"]); got != 2 {
+		t.Errorf("移除了 %d 条消息的按钮，期望 2 条", got)
+	}
+}
@@ -0,0 +1,176 @@
+package telegram
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// Telegram支持的消息解析模式
+const (
+	ParseModeMarkdown = "Markdown"
+	ParseModeHTML     = "HTML"
+)
+
+var (
+	mdLink    = regexp.MustCompile(`\[([^\]]+)\]\(([^)]+)\)`)
+	mdBold    = regexp.MustCompile(`\*+([^*\n]+)\*+`)
+	mdHeading = regexp.MustCompile(`(?m)^#{1,6}\s+`)
+	htmlLink  = regexp.MustCompile(`(?is)<a\s[^>]*href="([^"]*)"[^>]*>(.*?)</a>`)
+	htmlTag   = regexp.MustCompile(`<[^>]+>`)
+)
+
+// toPlainText 去掉消息中的格式，用于Telegram无法解析格式时以纯文本重新发送
+// 链接转换为"文字 (地址)"；下划线可能是仓库名或版本号的一部分，保持原样
+func toPlainText(s, parseMode string) string {
+	if parseMode == ParseModeHTML {
+		s = htmlLink.ReplaceAllString(s, "$2 ($1)")
+		s = htmlTag.ReplaceAllString(s, "")
+		return html.UnescapeString(s)
+	}
+	s = mdLink.ReplaceAllString(s, "$1 ($2)")
+	s = mdHeading.ReplaceAllString(s, "")
+	s = mdBold.ReplaceAllString(s, "$1")
+	return strings.ReplaceAll(s, "`", "")
+}
+
+// buildBatch 根据解析模式构建批量消息内容
+func (n *Notifier) buildBatch(releases []*github.ReleaseInfo, run render.RunContext) string {
+	if n.config.ParseMode == ParseModeHTML {
+		return buildBatchHTML(releases, run)
+	}
+	return buildBatchMarkdown(releases, run)
+}
+
+// buildBatchMarkdown 构建Markdown格式的批量消息
+func buildBatchMarkdown(releases []*github.ReleaseInfo, run render.RunContext) string {
+	var content bytes.Buffer
+	content.WriteString("📦 *GitHub 版本更新汇总*\n\n")
+	content.WriteString(fmt.Sprintf("共 %d 个仓库发布了新版本：\n\n", len(releases)))
+
+	for i, release := range releases {
+		content.WriteString(fmt.Sprintf("*%d. %s/%s*\n",
+			i+1, release.Owner, release.Repository))
+		if label := release.EventLabel(); label != "" {
+			content.WriteString(label + "\n")
+		}
+		if release.IsHighlighted() {
+			content.WriteString(release.HighlightBanner() + "\n")
+		}
+		if release.Contributed {
+			content.WriteString(release.ContributorBadge() + "\n")
+		}
+		content.WriteString(fmt.Sprintf("版本: `%s`\n", release.TagName))
+		content.WriteString(fmt.Sprintf("时间: %s\n",
+			run.FormatTime(release.PublishedAt)))
+		if release.SignatureChecked {
+			content.WriteString(fmt.Sprintf("签名: %s\n", release.SignatureStatus()))
+		}
+		if release.PinnedVersion != "" {
+			content.WriteString(fmt.Sprintf("锁定版本: %s\n", release.PinnedStatus()))
+		}
+		if len(release.MatchedAssets) > 0 {
+			content.WriteString(fmt.Sprintf("附件: %s\n", strings.Join(release.MatchedAssets, ", ")))
+		}
+		if release.NotesDiff != "" {
+			content.WriteString(fmt.Sprintf("```\n%s\n```\n", release.NotesDiff))
+		}
+		content.WriteString(fmt.Sprintf("[查看详情](%s)\n\n", release.Link()))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString(fmt.Sprintf("_%s_\n", footer))
+	}
+
+	return content.String()
+}
+
+// buildBatchHTML 构建HTML格式的批量消息，所有动态内容都会转义
+func buildBatchHTML(releases []*github.ReleaseInfo, run render.RunContext) string {
+	esc := html.EscapeString
+
+	var content bytes.Buffer
+	content.WriteString("📦 <b>GitHub 版本更新汇总</b>\n\n")
+	content.WriteString(fmt.Sprintf("共 %d 个仓库发布了新版本：\n\n", len(releases)))
+
+	for i, release := range releases {
+		content.WriteString(fmt.Sprintf("<b>%d. %s/%s</b>\n",
+			i+1, esc(release.Owner), esc(release.Repository)))
+		if label := release.EventLabel(); label != "" {
+			content.WriteString(esc(label) + "\n")
+		}
+		if release.IsHighlighted() {
+			content.WriteString("<b>" + esc(release.HighlightBanner()) + "</b>\n")
+		}
+		if release.Contributed {
+			content.WriteString(esc(release.ContributorBadge()) + "\n")
+		}
+		content.WriteString(fmt.Sprintf("版本: <code>%s</code>\n", esc(release.TagName)))
+		content.WriteString(fmt.Sprintf("时间: %s\n",
+			run.FormatTime(release.PublishedAt)))
+		if release.SignatureChecked {
+			content.WriteString(fmt.Sprintf("签名: %s\n", release.SignatureStatus()))
+		}
+		if release.PinnedVersion != "" {
+			content.WriteString(fmt.Sprintf("锁定版本: %s\n", esc(release.PinnedStatus())))
+		}
+		if len(release.MatchedAssets) > 0 {
+			content.WriteString(fmt.Sprintf("附件: %s\n", esc(strings.Join(release.MatchedAssets, ", "))))
+		}
+		if release.NotesDiff != "" {
+			content.WriteString(fmt.Sprintf("<pre>%s</pre>\n", esc(release.NotesDiff)))
+		}
+		content.WriteString(fmt.Sprintf("<a href=\"%s\">查看详情</a>\n\n", esc(release.Link())))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString(fmt.Sprintf("<i>%s</i>\n", esc(footer)))
+	}
+
+	return content.String()
+}
+
+// maxDigestItems 摘要消息中最多列出的版本数
+const maxDigestItems = 50
+
+// buildDigest 根据解析模式构建超过每日上限后的摘要消息，每个版本只占一行
+func (n *Notifier) buildDigest(releases []*github.ReleaseInfo, run render.RunContext) string {
+	isHTML := n.config.ParseMode == ParseModeHTML
+	esc := func(s string) string { return s }
+	if isHTML {
+		esc = html.EscapeString
+	}
+
+	var content bytes.Buffer
+	if isHTML {
+		content.WriteString(fmt.Sprintf("📦 <b>今天还有 %d 个新版本</b>\n\n", len(releases)))
+	} else {
+		content.WriteString(fmt.Sprintf("📦 *今天还有 %d 个新版本*\n\n", len(releases)))
+	}
+	content.WriteString("今天的消息数已达到上限，以下版本合并发送：\n\n")
+
+	for i, release := range releases {
+		if i == maxDigestItems {
+			content.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		name := esc(release.Owner + "/" + release.Repository)
+		if isHTML {
+			content.WriteString(fmt.Sprintf("• <a href=\"%s\">%s</a> <code>%s</code>\n",
+				esc(release.Link()), name, esc(release.TagName)))
+		} else {
+			content.WriteString(fmt.Sprintf("• [%s](%s) `%s`\n", name, release.Link(), release.TagName))
+		}
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString("\n" + esc(footer) + "\n")
+	}
+
+	return content.String()
+}
@@ -4,22 +4,55 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
+	"net/url"
+	"strings"
 	"sync"
 	"text/template"
 	"time"
 
-	"golang.org/x/time/rate"
-
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
 	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
 )
 
+// DefaultAPIBaseURL 官方Bot API地址
+const DefaultAPIBaseURL = "https://api.telegram.org"
+
+// DefaultPace 默认发送速率
+// Telegram API限制: 每秒1条消息
+var DefaultPace = pacing.Limit{Interval: 1 * time.Second, Burst: 3}
+
 // Config Telegram通知配置
 type Config struct {
+	// Name 渠道名称，同时配置多个Telegram机器人时为 telegram/<实例名>，为空时为 telegram
+	Name     string
 	Enabled  bool
 	BotToken string
 	ChatID   string
+	// ParseMode 消息解析模式，Markdown（默认）或 HTML
+	ParseMode string
+	// SendPhoto 单个版本的消息是否以仓库预览图+说明文字的形式发送
+	SendPhoto bool
+	// Ack 版本消息下方是否附带"已查看"按钮，按钮中的确认标识由通知管理器通过 RunContext.AckID 指定
+	Ack bool
+	// APIBaseURL Bot API地址，默认为官方地址，使用自建Bot API服务时修改
+	APIBaseURL string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Proxy 仅用于Telegram的代理地址，如 socks5://127.0.0.1:1080
+	Proxy string
+	// SSH 仅用于Telegram的SSH跳板机，与 Proxy 二选一
+	SSH *util.SSHOptions
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
 }
 
 // Notifier Telegram通知器
@@ -27,8 +60,8 @@ type Notifier struct {
 	config   Config
 	template *template.Template
 	client   *http.Client
-	limiter  *rate.Limiter // 速率限制器
-	mu       sync.Mutex    // 保护冷却状态
+	limiter  *pacing.Bucket // 速率限制器
+	mu       sync.Mutex     // 保护冷却状态
 	cooldown struct {
 		active bool
 		until  time.Time
@@ -45,13 +78,41 @@ func New(config Config, tmpl *template.Template) (*Notifier, error) {
 		return nil, fmt.Errorf("Telegram Chat ID不能为空")
 	}
 
-	// 速率限制器
-	// Telegram API限制: 每秒1条消息
-	limiter := rate.NewLimiter(rate.Every(1*time.Second), 3)
+	switch config.ParseMode {
+	case "":
+		config.ParseMode = ParseModeMarkdown
+	case ParseModeMarkdown, ParseModeHTML:
+	default:
+		return nil, fmt.Errorf("不支持的Telegram解析模式: %s（可选 Markdown、HTML）", config.ParseMode)
+	}
 
-	// 创建带超时的HTTP客户端
-	client := &http.Client{
-		Timeout: 10 * time.Second,
+	if config.APIBaseURL == "" {
+		config.APIBaseURL = DefaultAPIBaseURL
+	}
+	config.APIBaseURL = strings.TrimRight(config.APIBaseURL, "/")
+	if u, err := url.Parse(config.APIBaseURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("无效的Telegram API地址: %s", config.APIBaseURL)
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket(channelName(config), DefaultPace)
+	}
+
+	// 创建带超时的HTTP客户端，按配置绑定出口地址，并可单独经由代理或SSH跳板机访问Telegram
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+		Proxy:     config.Proxy,
+		SSH:       config.SSH,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
 	}
 
 	return &Notifier{
@@ -68,18 +129,22 @@ func New(config Config, tmpl *template.Template) (*Notifier, error) {
 	}, nil
 }
 
-// IsEnabled 是否启用
-func (n *Notifier) IsEnabled() bool {
-	return n.config.Enabled
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return channelName(n.config)
 }
 
-// renderTemplate 渲染通知模板
-func (n *Notifier) renderTemplate(release *github.ReleaseInfo) (string, error) {
-	var buf bytes.Buffer
-	if err := n.template.Execute(&buf, release); err != nil {
-		return "", err
+// channelName 配置中的渠道名称，未设置时为 telegram
+func channelName(config Config) string {
+	if config.Name == "" {
+		return "telegram"
 	}
-	return buf.String(), nil
+	return config.Name
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
 }
 
 // canSendMessage 检查是否可以发送消息
@@ -87,7 +152,7 @@ func (n *Notifier) canSendMessage() (bool, time.Duration) {
 	n.mu.Lock()
 	defer n.mu.Unlock()
 
-	now := time.Now()
+	now := n.config.Clock.Now()
 
 	// 检查是否在冷却期
 	if n.cooldown.active && now.Before(n.cooldown.until) {
@@ -105,11 +170,11 @@ func (n *Notifier) setCooldown(duration time.Duration) {
 	defer n.mu.Unlock()
 
 	n.cooldown.active = true
-	n.cooldown.until = time.Now().Add(duration)
+	n.cooldown.until = n.config.Clock.Now().Add(duration)
 }
 
 // Send 发送Telegram通知
-func (n *Notifier) Send(release *github.ReleaseInfo) error {
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
 	// 检查是否可以发送消息
 	canSend, remaining := n.canSendMessage()
 	if !canSend {
@@ -122,23 +187,39 @@ func (n *Notifier) Send(release *github.ReleaseInfo) error {
 		return fmt.Errorf("速率限制等待错误: %v", err)
 	}
 
-	content, err := n.renderTemplate(release)
-	if err != nil {
-		return err
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	return n.sendWithRetry(func() error {
+		return n.sendRelease([]*github.ReleaseInfo{release}, content, n.ackMarkup(run))
+	})
+}
+
+// SendBatch 批量发送Telegram通知（合并成一条消息）
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	// 检查是否可以发送消息
+	canSend, remaining := n.canSendMessage()
+	if !canSend {
+		return fmt.Errorf("Telegram消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
 	}
 
-	err = n.sendMessage(content)
-	if err != nil && (err.Error() == "too many requests" || err.Error() == "rate limit exceeded") {
-		// Telegram 429 错误触发冷却期
-		n.setCooldown(1 * time.Minute)
-		return fmt.Errorf("触发Telegram API限流，已设置1分钟冷却期: %v", err)
+	// 控制发送频率
+	ctx := context.Background()
+	if err := n.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
 	}
 
-	return err
+	text := n.buildBatch(releases, run)
+	return n.sendWithRetry(func() error {
+		return n.sendRelease(releases, text, n.ackMarkup(run))
+	})
 }
 
-// SendBatch 批量发送Telegram通知（合并成一条消息）
-func (n *Notifier) SendBatch(releases []*github.ReleaseInfo) error {
+// SendDigest 将超过每日上限的版本合并为一条摘要消息发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
 	if len(releases) == 0 {
 		return nil
 	}
@@ -155,49 +236,146 @@ func (n *Notifier) SendBatch(releases []*github.ReleaseInfo) error {
 		return fmt.Errorf("速率限制等待错误: %v", err)
 	}
 
-	// 构建批量消息内容
-	var content bytes.Buffer
-	content.WriteString("📦 *GitHub 版本更新汇总*\n\n")
-	content.WriteString(fmt.Sprintf("共 %d 个仓库发布了新版本：\n\n", len(releases)))
+	text := n.buildDigest(releases, run)
+	return n.sendWithRetry(func() error {
+		return n.sendMessage(text, nil)
+	})
+}
+
+// sendWithRetry 发送消息，遇到429限流时按照 retry_after 设置冷却期，等待后自动重试一次
+func (n *Notifier) sendWithRetry(send func() error) error {
+	err := send()
+
+	var rlErr *rateLimitError
+	if !errors.As(err, &rlErr) {
+		return err
+	}
+
+	wait := rlErr.retryAfter
+	if wait <= 0 {
+		wait = defaultCooldown
+	}
+	n.setCooldown(wait)
 
-	for i, release := range releases {
-		content.WriteString(fmt.Sprintf("*%d. %s/%s*\n",
-			i+1, release.Owner, release.Repository))
-		content.WriteString(fmt.Sprintf("版本: `%s`\n", release.TagName))
-		content.WriteString(fmt.Sprintf("时间: %s\n",
-			release.PublishedAt.Format("2006-01-02 15:04:05")))
-		content.WriteString(fmt.Sprintf("[查看详情](%s)\n\n", release.HTMLURL))
+	// 等待时间过长时不阻塞发送流程，交给失败队列在下次运行时重发
+	if wait > maxRetryWait {
+		return fmt.Errorf("触发Telegram API限流，已设置%v冷却期: %v", wait, err)
 	}
 
-	err := n.sendMessage(content.String())
-	if err != nil && (err.Error() == "too many requests" || err.Error() == "rate limit exceeded") {
-		// Telegram 429 错误触发冷却期
-		n.setCooldown(1 * time.Minute)
-		return fmt.Errorf("触发Telegram API限流，已设置1分钟冷却期: %v", err)
+	log.Printf("%s 触发Telegram API限流，%v 后自动重试", n.Name(), wait)
+	// 等待期间发送被取消（如收到终止信号）时不再重试，交给失败队列重发
+	if err := n.limiter.Sleep(context.Background(), n.config.Clock, wait); err != nil {
+		return fmt.Errorf("等待Telegram API限流冷却期间发送已中断: %v", err)
 	}
 
-	return err
+	if err := send(); err != nil {
+		if errors.As(err, &rlErr) && rlErr.retryAfter > 0 {
+			n.setCooldown(rlErr.retryAfter)
+		}
+		return fmt.Errorf("触发Telegram API限流，重试后仍然失败: %v", err)
+	}
+	return nil
+}
+
+const (
+	// defaultCooldown 429响应没有给出 retry_after 时的冷却期
+	defaultCooldown = 1 * time.Minute
+	// maxRetryWait 自动重试时最多等待的时间
+	maxRetryWait = 2 * time.Minute
+)
+
+// rateLimitError Telegram返回的429限流错误
+type rateLimitError struct {
+	// retryAfter Telegram要求等待的时间，未给出时为0
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return "too many requests"
+}
+
+// markupError Telegram无法解析消息中的Markdown或HTML格式，如 "Bad Request: can't parse entities"
+type markupError struct {
+	description string
+}
+
+func (e *markupError) Error() string {
+	return fmt.Sprintf("请求失败，状态码: %d (%s)", http.StatusBadRequest, e.description)
+}
+
+// maxCaptionLength Telegram图片说明文字的最大长度
+const maxCaptionLength = 1024
+
+// sendRelease 发送消息内容；启用图片且只有一个版本时，以仓库预览图+说明文字的形式发送
+// markup 不为nil时在消息下方附带按钮
+func (n *Notifier) sendRelease(releases []*github.ReleaseInfo, text string, markup *replyMarkup) error {
+	if n.config.SendPhoto && len(releases) == 1 && len([]rune(text)) <= maxCaptionLength {
+		if image := releases[0].OpenGraphImageURL(); image != "" {
+			return n.sendPhoto(image, text, markup)
+		}
+	}
+	return n.sendMessage(text, markup)
 }
 
 // sendMessage 发送消息到Telegram
-func (n *Notifier) sendMessage(text string) error {
+func (n *Notifier) sendMessage(text string, markup *replyMarkup) error {
 	type messageRequest struct {
-		ChatID    string `json:"chat_id"`
-		Text      string `json:"text"`
-		ParseMode string `json:"parse_mode"`
+		ChatID      string       `json:"chat_id"`
+		Text        string       `json:"text"`
+		ParseMode   string       `json:"parse_mode,omitempty"`
+		ReplyMarkup *replyMarkup `json:"reply_markup,omitempty"`
+	}
+
+	return n.withPlainFallback(text, func(text, parseMode string) error {
+		return n.callAPI("sendMessage", messageRequest{
+			ChatID:      n.config.ChatID,
+			Text:        text,
+			ParseMode:   parseMode,
+			ReplyMarkup: markup,
+		}, nil)
+	})
+}
+
+// sendPhoto 发送带说明文字的图片到Telegram
+func (n *Notifier) sendPhoto(photoURL, caption string, markup *replyMarkup) error {
+	type photoRequest struct {
+		ChatID      string       `json:"chat_id"`
+		Photo       string       `json:"photo"`
+		Caption     string       `json:"caption"`
+		ParseMode   string       `json:"parse_mode,omitempty"`
+		ReplyMarkup *replyMarkup `json:"reply_markup,omitempty"`
 	}
 
-	apiURL := fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", n.config.BotToken)
+	return n.withPlainFallback(caption, func(caption, parseMode string) error {
+		return n.callAPI("sendPhoto", photoRequest{
+			ChatID:      n.config.ChatID,
+			Photo:       photoURL,
+			Caption:     caption,
+			ParseMode:   parseMode,
+			ReplyMarkup: markup,
+		}, nil)
+	})
+}
+
+// withPlainFallback 按配置的解析模式发送，Telegram无法解析其中的格式时（如发布说明中有未配对的 * 或 _）
+// 去掉格式后以纯文本重新发送一次，不会因为格式问题丢失通知
+func (n *Notifier) withPlainFallback(text string, send func(text, parseMode string) error) error {
+	err := send(text, n.config.ParseMode)
 
-	// 准备请求参数
-	msg := messageRequest{
-		ChatID:    n.config.ChatID,
-		Text:      text,
-		ParseMode: "Markdown", // 支持Markdown格式的消息
+	var markupErr *markupError
+	if !errors.As(err, &markupErr) {
+		return err
 	}
+	log.Printf("%s: Telegram无法解析消息格式（%s），去掉格式后以纯文本重新发送", n.Name(), markupErr.description)
+	return send(toPlainText(text, n.config.ParseMode), "")
+}
+
+// callAPI 调用Telegram Bot API，result 不为nil时解析响应中的 result 字段
+func (n *Notifier) callAPI(method string, payload, result interface{}) error {
+	apiURL := fmt.Sprintf("%s/bot%s/%s", n.config.APIBaseURL, n.config.BotToken, method)
 
 	// 将消息序列化为JSON
-	msgBytes, err := json.Marshal(msg)
+	msgBytes, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("序列化消息失败: %v", err)
 	}
@@ -209,30 +387,42 @@ func (n *Notifier) sendMessage(text string) error {
 	}
 	defer resp.Body.Close()
 
+	// 解析响应（429等错误响应同样包含JSON说明）
+	var response struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description,omitempty"`
+		ErrorCode   int             `json:"error_code,omitempty"`
+		Result      json.RawMessage `json:"result,omitempty"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after,omitempty"`
+		} `json:"parameters,omitempty"`
+	}
+	decodeErr := json.NewDecoder(resp.Body).Decode(&response)
+
 	// 检查响应
-	if resp.StatusCode == 429 {
-		// HTTP 429 Too Many Requests
-		return fmt.Errorf("too many requests")
+	if resp.StatusCode == http.StatusTooManyRequests || response.ErrorCode == http.StatusTooManyRequests {
+		return &rateLimitError{retryAfter: time.Duration(response.Parameters.RetryAfter) * time.Second}
+	} else if resp.StatusCode == http.StatusBadRequest && strings.Contains(response.Description, "can't parse") {
+		return &markupError{description: response.Description}
 	} else if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && response.Description != "" {
+			return fmt.Errorf("请求失败，状态码: %d (%s)", resp.StatusCode, response.Description)
+		}
 		return fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
 	}
 
-	// 解析响应
-	var response struct {
-		OK          bool   `json:"ok"`
-		Description string `json:"description,omitempty"`
-		ErrorCode   int    `json:"error_code,omitempty"`
+	if decodeErr != nil {
+		return fmt.Errorf("解析响应失败: %v", decodeErr)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return fmt.Errorf("解析响应失败: %v", err)
+	if !response.OK {
+		return fmt.Errorf("Telegram API返回错误: %s (code: %d)", response.Description, response.ErrorCode)
 	}
 
-	if !response.OK {
-		if response.ErrorCode == 429 {
-			return fmt.Errorf("too many requests")
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("解析响应失败: %v", err)
 		}
-		return fmt.Errorf("Telegram API返回错误: %s (code: %d)", response.Description, response.ErrorCode)
 	}
 
 	return nil
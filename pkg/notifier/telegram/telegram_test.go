@@ -0,0 +1,118 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSendBatch_PlainFallback 测试Telegram无法解析消息格式时去掉格式以纯文本重新发送
+func TestSendBatch_PlainFallback(t *testing.T) {
+	var requests []map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, body)
+		if body["parse_mode"] != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: can't parse entities: Can't find end of the entity starting at byte offset 57"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled:    true,
+		BotToken:   "t",
+		ChatID:     "1",
+		APIBaseURL: srv.URL,
+		Bucket:     pacing.NewBucket("telegram", pacing.Limit{Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{{Owner: "o", Repository: "my_lib", TagName: "v1.0.0", HTMLURL: "https://github.com/o/my_lib/releases/tag/v1.0.0"}}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 1}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("发送了 %d 次请求，期望 2 次", len(requests))
+	}
+	if requests[0]["parse_mode"] != ParseModeMarkdown {
+		t.Errorf("第一次请求应使用 Markdown 解析模式: %v", requests[0])
+	}
+	want := "1. o/my_lib\n版本: v1.0.0\n"
+	if text := requests[1]["text"]; !strings.Contains(text, want) || strings.Contains(text, "*") || !strings.Contains(text, "查看详情 (https://github.com/o/my_lib/releases/tag/v1.0.0)") {
+		t.Errorf("纯文本内容不正确: %q", text)
+	}
+}
+
+// TestToPlainText 测试去掉Markdown和HTML格式
+func TestToPlainText(t *testing.T) {
+	for _, tc := range []struct {
+		in, mode, want string
+	}{
+		{"*my_lib* `v1.0`\n[查看详情](https://x/a_b)", ParseModeMarkdown, "my_lib v1.0\n查看详情 (https://x/a_b)"},
+		{"<b>a &lt; b</b> <a href=\"https://x/?a=1&amp;b=2\">链接</a>", ParseModeHTML, "a < b 链接 (https://x/?a=1&b=2)"},
+	} {
+		if got := toPlainText(tc.in, tc.mode); got != tc.want {
+			t.Errorf("toPlainText(%q) = %q，期望 %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+// TestSendBatch_RetryCancelled 测试限流后等待重试期间发送被取消时立即返回错误，不再重试
+func TestSendBatch_RetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		// 收到请求后取消本次发送，模拟等待重试期间收到终止信号
+		cancel()
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 60","parameters":{"retry_after":60}}`))
+	}))
+	defer srv.Close()
+
+	pacer := pacing.New(nil)
+	pacer.SetContext(ctx)
+	n, err := New(Config{
+		Enabled:    true,
+		BotToken:   "t",
+		ChatID:     "1",
+		APIBaseURL: srv.URL,
+		Bucket:     pacer.Bucket("telegram", pacing.Limit{Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- n.SendBatch([]*github.ReleaseInfo{{Owner: "o", Repository: "a", TagName: "v1.0.0"}}, render.RunContext{Timestamp: time.Now(), Total: 1})
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "已中断") {
+			t.Errorf("取消后应返回中断错误: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("取消后仍在等待重试")
+	}
+	if requests != 1 {
+		t.Errorf("发送了 %d 次请求，期望 1 次", requests)
+	}
+}
@@ -0,0 +1,118 @@
+package notifier
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// defaultLang 未配置 lang 的渠道使用的语言，与 format.locale 一致
+func defaultLang(cfg *config.Config) string {
+	if cfg.Format.Locale != "" {
+		return cfg.Format.Locale
+	}
+	return render.LocaleZH
+}
+
+// templateText 查找语言对应的模板
+// 优先使用 templates 中的配置，默认语言回退到顶层 template，其余语言回退到内置模板
+func templateText(cfg *config.Config, lang string) (string, error) {
+	if text := cfg.Templates[lang]; text != "" {
+		return text, nil
+	}
+	if lang == defaultLang(cfg) {
+		return cfg.Template, nil
+	}
+	if text, ok := config.DefaultTemplates[lang]; ok {
+		return text, nil
+	}
+	return "", fmt.Errorf("没有语言 %s 的通知模板，请在 templates 中配置", lang)
+}
+
+// parseTemplates 为各渠道使用到的语言解析模板，返回按语言索引的模板
+// 相对时间和本地化日期使用模板所属的语言，不支持的语言使用 format.locale
+func parseTemplates(cfg *config.Config, langs map[string]string) (map[string]*template.Template, error) {
+	needed := map[string]bool{defaultLang(cfg): true}
+	for _, lang := range langs {
+		needed[lang] = true
+	}
+
+	templates := make(map[string]*template.Template, len(needed))
+	for lang := range needed {
+		text, err := templateText(cfg, lang)
+		if err != nil {
+			return nil, err
+		}
+
+		locale := cfg.Format.Locale
+		if lang == render.LocaleZH || lang == render.LocaleEN {
+			locale = lang
+		}
+
+		tmpl, err := render.Parse(text, render.Options{
+			Partials:   cfg.Partials,
+			TimeFormat: cfg.Format.TimeFormat,
+			Locale:     locale,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("语言 %s: %v", lang, err)
+		}
+		templates[lang] = tmpl
+	}
+
+	return templates, nil
+}
+
+// channelLangs 各渠道配置的语言，未配置的渠道使用默认语言
+func channelLangs(cfg *config.Config) map[string]string {
+	configured := map[string]string{
+		"slack":      cfg.Notifications.Slack.Lang,
+		"email":      cfg.Notifications.Email.Lang,
+		"wecom":      cfg.Notifications.WeCom.Lang,
+		"feishu":     cfg.Notifications.Feishu.Lang,
+		"ntfy":       cfg.Notifications.Ntfy.Lang,
+		"teams":      cfg.Notifications.Teams.Lang,
+		"desktop":    cfg.Notifications.Desktop.Lang,
+		"rocketchat": cfg.Notifications.RocketChat.Lang,
+		"googlechat": cfg.Notifications.GoogleChat.Lang,
+		"irc":        cfg.Notifications.IRC.Lang,
+		"atom":       cfg.Notifications.Atom.Lang,
+		"pushbullet": cfg.Notifications.Pushbullet.Lang,
+		"syslog":     cfg.Notifications.Syslog.Lang,
+		"mastodon":   cfg.Notifications.Mastodon.Lang,
+		"webex":      cfg.Notifications.Webex.Lang,
+	}
+	for _, c := range cfg.Notifications.DingTalk {
+		configured[c.Channel()] = c.Lang
+	}
+	for _, c := range cfg.Notifications.Telegram {
+		configured[c.Channel()] = c.Lang
+	}
+
+	langs := make(map[string]string, len(configured))
+	for channel, lang := range configured {
+		if lang == "" {
+			lang = defaultLang(cfg)
+		}
+		langs[channel] = lang
+	}
+	return langs
+}
+
+// templateFor 渠道使用的模板
+func (m *Manager) templateFor(channel string) *template.Template {
+	if tmpl, ok := m.templates[m.langs[channel]]; ok {
+		return tmpl
+	}
+	return m.template
+}
+
+// localeFor 渠道消息中相对时间和本地化日期使用的语言
+func (m *Manager) localeFor(channel string) string {
+	if lang := m.langs[channel]; lang == render.LocaleZH || lang == render.LocaleEN {
+		return lang
+	}
+	return m.format.Locale
+}
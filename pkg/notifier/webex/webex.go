@@ -0,0 +1,213 @@
+package webex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultAPIBaseURL 官方API地址
+const DefaultAPIBaseURL = "https://webexapis.com"
+
+// defaultCooldown 429响应没有给出 Retry-After 时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// DefaultPace 默认发送速率
+// Webex 对同一机器人向同一空间发送消息的频率有限制，每秒1条足以避免触发限流
+var DefaultPace = pacing.Limit{Interval: 1 * time.Second, Burst: 3}
+
+// Config Webex机器人通知配置
+type Config struct {
+	Enabled bool
+	// BotToken 在 developer.webex.com 创建机器人后获得的访问令牌
+	BotToken string
+	// RoomID 发送到的空间ID，机器人需要已加入该空间
+	RoomID string
+	// APIBaseURL API地址，默认为官方地址
+	APIBaseURL string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier Webex通知器
+type Notifier struct {
+	config   Config
+	template *template.Template
+	client   *http.Client
+	limiter  *pacing.Bucket // 速率限制器
+	mu       sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// New 创建Webex通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.BotToken == "" {
+		return nil, fmt.Errorf("Webex Bot Token不能为空")
+	}
+
+	if config.RoomID == "" {
+		return nil, fmt.Errorf("Webex Room ID不能为空")
+	}
+
+	if config.APIBaseURL == "" {
+		config.APIBaseURL = DefaultAPIBaseURL
+	}
+	config.APIBaseURL = strings.TrimRight(config.APIBaseURL, "/")
+	if u, err := url.Parse(config.APIBaseURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("无效的Webex API地址: %s", config.APIBaseURL)
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("webex", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		client:   client,
+		limiter:  limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "webex"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送Webex通知，Webex支持标准markdown，模板渲染结果直接发送
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	return n.post(truncate(content, maxMessageBytes))
+}
+
+// SendBatch 批量发送Webex通知（合并成一条消息）
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.post(buildBatchMarkdown(releases, run))
+}
+
+// SendDigest 将超过每日上限的版本合并为一条摘要消息发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.post(buildDigestMarkdown(releases, run))
+}
+
+// wait 等待冷却期结束和速率限制
+func (n *Notifier) wait() error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+
+	if remaining > 0 {
+		return fmt.Errorf("Webex消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+	return nil
+}
+
+// message Webex创建消息的请求体
+type message struct {
+	RoomID   string `json:"roomId"`
+	Markdown string `json:"markdown"`
+}
+
+// post 调用 /v1/messages 发送markdown消息并检查返回结果
+func (n *Notifier) post(markdown string) error {
+	if err := n.wait(); err != nil {
+		return err
+	}
+
+	msgBytes, err := json.Marshal(message{RoomID: n.config.RoomID, Markdown: markdown})
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.config.APIBaseURL+"/v1/messages", bytes.NewReader(msgBytes))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+n.config.BotToken)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 触发限流时按照 Retry-After 设置冷却期
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("触发Webex API限流，已设置%v冷却期: rate limit exceeded", wait)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		// 错误响应形如 {"message":"...","trackingId":"..."}，trackingId 用于向Webex支持提交问题
+		var response struct {
+			Message    string `json:"message"`
+			TrackingID string `json:"trackingId"`
+		}
+		if err := json.Unmarshal(body, &response); err == nil && response.Message != "" {
+			return fmt.Errorf("Webex API返回错误，状态码: %d (%s, trackingId: %s)", resp.StatusCode, response.Message, response.TrackingID)
+		}
+		return fmt.Errorf("请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
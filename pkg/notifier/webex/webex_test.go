@@ -0,0 +1,113 @@
+package webex
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSendBatch 测试请求的认证头、空间ID和markdown内容
+func TestSendBatch(t *testing.T) {
+	var got message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/messages" {
+			t.Errorf("请求路径为 %s", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer token" {
+			t.Errorf("认证头为 %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("无效的消息: %v", err)
+		}
+		w.Write([]byte(`{"id":"m1"}`))
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled:    true,
+		BotToken:   "token",
+		RoomID:     "room",
+		APIBaseURL: srv.URL,
+		Bucket:     pacing.NewBucket("webex", pacing.Limit{Burst: 10}),
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Owner: "o", Repository: "a", TagName: "v1.0.0", HTMLURL: "https://github.com/o/a/releases/tag/v1.0.0"},
+		{Owner: "o", Repository: "b", TagName: "v2.0.0", HTMLURL: "https://github.com/o/b/releases/tag/v2.0.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 2}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if got.RoomID != "room" {
+		t.Errorf("空间ID为 %q", got.RoomID)
+	}
+	if !strings.Contains(got.Markdown, "[o/a](https://github.com/o/a/releases/tag/v1.0.0)") || !strings.Contains(got.Markdown, "`v2.0.0`") {
+		t.Errorf("消息内容不正确: %s", got.Markdown)
+	}
+}
+
+// TestSend_RateLimit 测试429响应按 Retry-After 设置冷却期，冷却期内不发送请求
+func TestSend_RateLimit(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Now())
+	n, err := New(Config{
+		Enabled:    true,
+		BotToken:   "token",
+		RoomID:     "room",
+		APIBaseURL: srv.URL,
+		Bucket:     pacing.NewBucket("webex", pacing.Limit{Burst: 10}),
+		Clock:      fake,
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := []*github.ReleaseInfo{{Owner: "o", Repository: "a", TagName: "v1.0.0"}}
+	run := render.RunContext{Timestamp: time.Now(), Total: 1}
+	if err := n.SendBatch(release, run); err == nil {
+		t.Fatal("限流时应返回错误")
+	}
+	fake.Advance(10 * time.Second)
+	if err := n.SendBatch(release, run); err == nil || !strings.Contains(err.Error(), "冷却中") {
+		t.Errorf("冷却期内应直接返回错误: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("发送了 %d 次请求，期望 1 次", requests)
+	}
+
+	fake.Advance(30 * time.Second)
+	n.SendBatch(release, run)
+	if requests != 2 {
+		t.Errorf("冷却期结束后应重新发送，共 %d 次请求", requests)
+	}
+}
+
+// TestTruncate 测试按字节截断时不会截断多字节字符
+func TestTruncate(t *testing.T) {
+	s := truncate(strings.Repeat("版本", 10), 20)
+	if len(s) > 20 || !strings.HasSuffix(s, "...") || !strings.HasPrefix(s, "版本版本版") {
+		t.Errorf("截断结果为 %q", s)
+	}
+	if s := truncate("short", 20); s != "short" {
+		t.Errorf("未超长的文本不应截断: %q", s)
+	}
+}
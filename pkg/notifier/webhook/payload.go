@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/orange-juzipi/notify/pkg/schema"
+)
+
+// 负载类型，同时通过 X-Notify-Event 请求头发送
+const (
+	// PayloadRelease 单个版本
+	PayloadRelease = schema.TypeRelease
+	// PayloadBatch 一批版本
+	PayloadBatch = schema.TypeBatch
+	// PayloadDigest 超过每日上限后的摘要
+	PayloadDigest = schema.TypeDigest
+)
+
+// Payload 默认的JSON负载，也是自定义请求体模板的数据，结构见 schema 包
+type Payload = schema.Payload
+
+// PayloadRun 本次运行的上下文
+type PayloadRun = schema.Run
+
+// parseBodyTemplate 解析自定义请求体模板，模板中可以使用 json 函数输出JSON
+func parseBodyTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("解析webhook请求体模板失败: %v", err)
+	}
+	return tmpl, nil
+}
+
+// encodePayload 生成请求体，未配置模板时为JSON格式的负载
+func encodePayload(p Payload, tmpl *template.Template) ([]byte, error) {
+	if tmpl == nil {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("序列化webhook负载失败: %v", err)
+		}
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, p); err != nil {
+		return nil, fmt.Errorf("渲染webhook请求体失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
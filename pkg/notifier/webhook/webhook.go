@@ -0,0 +1,220 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+	"github.com/orange-juzipi/notify/pkg/schema"
+)
+
+// DefaultSignatureHeader 默认的签名请求头
+const DefaultSignatureHeader = "X-Notify-Signature"
+
+// KeyHeader 单个版本的负载中携带版本稳定标识（release.key）的请求头，接收方可以据此去重重发的请求
+const KeyHeader = "X-Notify-Key"
+
+// defaultCooldown 429响应没有给出 Retry-After 时的冷却期
+const defaultCooldown = 1 * time.Minute
+
+// DefaultPace 默认发送速率
+// 默认每秒1个请求，突发允许5个
+var DefaultPace = pacing.Limit{Interval: time.Second, Burst: 5}
+
+// Config 通用webhook配置
+type Config struct {
+	Enabled bool
+	URL     string
+	// Method 请求方法，默认为POST
+	Method string
+	// Headers 附加的请求头
+	Headers map[string]string
+	// BodyTemplate 自定义请求体模板，为空时发送默认的JSON负载
+	BodyTemplate string
+	// Secret 签名密钥，设置后在 SignatureHeader 中携带 sha256=<HMAC-SHA256(请求体)>
+	Secret string
+	// SignatureHeader 签名请求头，默认为 X-Notify-Signature
+	SignatureHeader string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier 通用webhook通知器
+type Notifier struct {
+	config  Config
+	body    *template.Template // 自定义请求体模板，为nil时发送默认的JSON负载
+	client  *http.Client
+	limiter *pacing.Bucket // 速率限制器
+	mu      sync.Mutex     // 保护冷却状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+}
+
+// New 创建通用webhook通知器
+// 通用webhook发送结构化数据，不使用消息模板
+func New(config Config, _ *template.Template) (*Notifier, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL不能为空")
+	}
+
+	if config.Method == "" {
+		config.Method = http.MethodPost
+	}
+	config.Method = strings.ToUpper(config.Method)
+
+	if config.SignatureHeader == "" {
+		config.SignatureHeader = DefaultSignatureHeader
+	}
+
+	var body *template.Template
+	if config.BodyTemplate != "" {
+		var err error
+		body, err = parseBodyTemplate(config.BodyTemplate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("webhook", DefaultPace)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:  config,
+		body:    body,
+		client:  client,
+		limiter: limiter,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "webhook"
+}
+
+// IsEnabled 是否启用
+func (n *Notifier) IsEnabled() bool {
+	return n.config.Enabled
+}
+
+// Send 发送单个版本
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	return n.deliver(schema.NewPayload(PayloadRelease, []*github.ReleaseInfo{release}, run))
+}
+
+// SendBatch 发送一批版本
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+	return n.deliver(schema.NewPayload(PayloadBatch, releases, run))
+}
+
+// SendDigest 发送超过每日上限后的摘要
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+	return n.deliver(schema.NewPayload(PayloadDigest, releases, run))
+}
+
+// deliver 渲染请求体、签名并发送
+func (n *Notifier) deliver(payload Payload) error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+	if remaining > 0 {
+		return fmt.Errorf("webhook触发限流，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+
+	body, err := encodePayload(payload, n.body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(n.config.Method, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "notify-webhook")
+	req.Header.Set("X-Notify-Event", payload.Type)
+	req.Header.Set("X-Notify-Schema-Version", strconv.Itoa(payload.SchemaVersion))
+	if payload.Release != nil && payload.Release.Key != "" {
+		req.Header.Set(KeyHeader, payload.Release.Key)
+	}
+	for key, value := range n.config.Headers {
+		req.Header.Set(key, value)
+	}
+	if n.config.Secret != "" {
+		req.Header.Set(n.config.SignatureHeader, Sign(n.config.Secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送webhook失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait := defaultCooldown
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(wait)
+		n.mu.Unlock()
+		return fmt.Errorf("webhook触发限流，已设置%v冷却期: rate limit exceeded", wait)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook请求失败，状态码: %d (%s)", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return nil
+}
+
+// Sign 计算请求体签名，格式为 sha256=<十六进制HMAC-SHA256>，与GitHub webhook的签名格式相同
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
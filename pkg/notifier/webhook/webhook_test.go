@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// TestSendBatch_Signature 测试默认JSON负载和签名请求头
+func TestSendBatch_Signature(t *testing.T) {
+	const secret = "s3cret"
+
+	var gotBody []byte
+	var gotSig, gotEvent, gotCustom string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSig = r.Header.Get(DefaultSignatureHeader)
+		gotEvent = r.Header.Get("X-Notify-Event")
+		gotCustom = r.Header.Get("X-Custom")
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled: true,
+		URL:     srv.URL,
+		Secret:  secret,
+		Headers: map[string]string{"X-Custom": "yes"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	releases := []*github.ReleaseInfo{
+		{Event: github.EventRelease, Owner: "o", Repository: "a", TagName: "v1.0.0"},
+		{Event: github.EventRelease, Owner: "o", Repository: "b", TagName: "v2.0.0"},
+	}
+	if err := n.SendBatch(releases, render.RunContext{Timestamp: time.Now(), Total: 2}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(gotBody)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); gotSig != want {
+		t.Errorf("签名 = %q, 期望 %q", gotSig, want)
+	}
+	if gotEvent != PayloadBatch || gotCustom != "yes" {
+		t.Errorf("请求头 X-Notify-Event=%q X-Custom=%q", gotEvent, gotCustom)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(gotBody, &payload); err != nil {
+		t.Fatalf("解析负载失败: %v", err)
+	}
+	if payload.Type != PayloadBatch || len(payload.Releases) != 2 || payload.Release != nil || payload.Run.Total != 2 {
+		t.Errorf("负载不符合预期: %s", gotBody)
+	}
+}
+
+// TestSend_KeyHeader 测试单个版本的请求头中携带版本标识
+func TestSend_KeyHeader(t *testing.T) {
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get(KeyHeader)
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{Enabled: true, URL: srv.URL}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Event: github.EventRelease, Owner: "o", Repository: "a", TagName: "v1.0.0"}
+	release.Key = release.EventKey()
+	if err := n.Send(release, render.RunContext{Timestamp: time.Now(), Total: 1}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if gotKey != release.Key {
+		t.Errorf("%s = %q，期望 %q", KeyHeader, gotKey, release.Key)
+	}
+}
+
+// TestSend_BodyTemplate 测试自定义请求体模板
+func TestSend_BodyTemplate(t *testing.T) {
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+	}))
+	defer srv.Close()
+
+	n, err := New(Config{
+		Enabled:      true,
+		URL:          srv.URL,
+		BodyTemplate: `{"text": {{printf "%s/%s %s" .Release.Owner .Release.Repository .Release.TagName | json}}}`,
+	}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+
+	release := &github.ReleaseInfo{Owner: "o", Repository: "a", TagName: `v1 "beta"`}
+	if err := n.Send(release, render.RunContext{}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+
+	if want := `{"text": "o/a v1 \"beta\""}`; strings.TrimSpace(gotBody) != want {
+		t.Errorf("请求体 = %s, 期望 %s", gotBody, want)
+	}
+}
+
+// TestSend_Cooldown 测试429响应后进入冷却期，按 Retry-After 到期后恢复发送
+func TestSend_Cooldown(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	n, err := New(Config{Enabled: true, URL: srv.URL, Clock: fake}, nil)
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	release := &github.ReleaseInfo{Owner: "o", Repository: "r", TagName: "v1.0.0"}
+
+	if err := n.Send(release, render.RunContext{}); err == nil {
+		t.Fatal("429响应应返回错误")
+	}
+	fake.Advance(29 * time.Second)
+	if err := n.Send(release, render.RunContext{}); err == nil || !strings.Contains(err.Error(), "冷却中") {
+		t.Errorf("冷却期内应直接返回错误，实际: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("冷却期内不应发送请求，实际共 %d 次", requests)
+	}
+
+	fake.Advance(time.Second)
+	if err := n.Send(release, render.RunContext{}); err != nil {
+		t.Errorf("冷却期结束后应恢复发送: %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package wecom
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// maxContentBytes 企业微信markdown消息内容的最大字节数
+const maxContentBytes = 4096
+
+// maxDigestItems 摘要消息中最多列出的版本数
+const maxDigestItems = 50
+
+// truncate 按字节数截断文本，不截断多字节字符
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	n -= len("...")
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n] + "..."
+}
+
+// buildReleaseMarkdown 构建单个版本在批量消息中的内容
+func buildReleaseMarkdown(i int, release *github.ReleaseInfo, run render.RunContext) string {
+	var content bytes.Buffer
+	content.WriteString(fmt.Sprintf("### %d. [%s/%s](%s)\n", i+1, release.Owner, release.Repository, release.Link()))
+	if label := release.EventLabel(); label != "" {
+		content.WriteString(fmt.Sprintf("**%s**\n", label))
+	}
+	if release.IsHighlighted() {
+		content.WriteString(fmt.Sprintf("<font color=\"warning\">%s</font>\n", release.HighlightBanner()))
+	}
+	if release.Contributed {
+		content.WriteString(fmt.Sprintf("**%s**\n", release.ContributorBadge()))
+	}
+	content.WriteString(fmt.Sprintf("> 版本: <font color=\"info\">%s</font>\n", release.TagName))
+	content.WriteString(fmt.Sprintf("> 发布时间: %s\n", run.FormatTime(release.PublishedAt)))
+	if release.SignatureChecked {
+		content.WriteString(fmt.Sprintf("> 签名: %s\n", release.SignatureStatus()))
+	}
+	if release.PinnedVersion != "" {
+		content.WriteString(fmt.Sprintf("> 锁定版本: %s\n", release.PinnedStatus()))
+	}
+	if len(release.MatchedAssets) > 0 {
+		content.WriteString(fmt.Sprintf("> 附件: %s\n", strings.Join(release.MatchedAssets, ", ")))
+	}
+	if release.NotesDiff != "" {
+		for _, line := range strings.Split(release.NotesDiff, "\n") {
+			content.WriteString(fmt.Sprintf("> %s\n", line))
+		}
+	}
+	if release.Description != "" {
+		desc := strings.ReplaceAll(release.Description, "\n", " ")
+		content.WriteString(fmt.Sprintf("> 说明: %s\n", truncate(desc, 200)))
+	}
+	content.WriteString("\n")
+	return content.String()
+}
+
+// buildBatchMarkdown 构建批量markdown消息内容
+// 企业微信限制消息内容不超过4096字节，超出部分只显示剩余数量
+func buildBatchMarkdown(releases []*github.ReleaseInfo, run render.RunContext) string {
+	var content bytes.Buffer
+	content.WriteString("## 📦 新版本发布汇总\n")
+	content.WriteString(fmt.Sprintf("共 %d 个仓库发布了新版本：\n\n", len(releases)))
+
+	footer := ""
+	if f := run.Footer(); f != "" {
+		footer = fmt.Sprintf("<font color=\"comment\">%s</font>", f)
+	}
+
+	// 为"以及其他 N 个版本"和页脚预留空间
+	budget := maxContentBytes - len(footer) - 64
+	for i, release := range releases {
+		entry := buildReleaseMarkdown(i, release, run)
+		if content.Len()+len(entry) > budget {
+			content.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n\n", len(releases)-i))
+			break
+		}
+		content.WriteString(entry)
+	}
+
+	content.WriteString(footer)
+	return truncate(content.String(), maxContentBytes)
+}
+
+// buildDigestMarkdown 构建超过每日上限后的摘要消息，每个版本只占一行
+func buildDigestMarkdown(releases []*github.ReleaseInfo, run render.RunContext) string {
+	var content bytes.Buffer
+	content.WriteString(fmt.Sprintf("## 📦 今天还有 %d 个新版本\n", len(releases)))
+	content.WriteString("今天的消息数已达到上限，以下版本合并发送：\n")
+
+	for i, release := range releases {
+		if i == maxDigestItems {
+			content.WriteString(fmt.Sprintf("...以及其他 %d 个版本\n", len(releases)-maxDigestItems))
+			break
+		}
+		content.WriteString(fmt.Sprintf("> [%s/%s](%s) %s\n",
+			release.Owner, release.Repository, release.Link(), release.TagName))
+	}
+
+	if footer := run.Footer(); footer != "" {
+		content.WriteString(fmt.Sprintf("\n<font color=\"comment\">%s</font>", footer))
+	}
+
+	return truncate(content.String(), maxContentBytes)
+}
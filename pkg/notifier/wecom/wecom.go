@@ -0,0 +1,212 @@
+package wecom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// 企业微信群机器人常见错误码
+const (
+	errCodeRateLimit  = 45009 // 接口调用超过限制
+	errCodeInvalidKey = 93000 // webhook地址中的key无效
+	errCodeRemoved    = 93004 // 机器人已被移出群聊
+)
+
+// DefaultPace 默认发送速率
+// 企业微信群机器人限制为每分钟20条消息
+// 与钉钉相同，设置为每4秒一条（15条/分钟），突发允许3条
+var DefaultPace = pacing.Limit{Interval: 4 * time.Second, Burst: 3}
+
+// Config 企业微信群机器人配置
+type Config struct {
+	Enabled bool
+	// WebhookURL 机器人webhook地址，包含key参数
+	WebhookURL string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+	// Bucket 发送速率令牌桶，由通知管理器按渠道创建，为nil时使用 DefaultPace
+	Bucket *pacing.Bucket
+	// Clock 限流冷却期使用的时钟，由通知管理器设置，为nil时使用系统时钟
+	Clock clock.Clock
+}
+
+// Notifier 企业微信群机器人通知器
+type Notifier struct {
+	config   Config
+	template *template.Template
+	limiter  *pacing.Bucket // 速率限制器
+	client   *http.Client
+	mu       sync.Mutex // 保护冷却和停用状态
+	// cooldownUntil 触发限流后的冷却截止时间
+	cooldownUntil time.Time
+	// disabledReason 因配置错误被停用时的原因，为空表示未停用
+	disabledReason string
+}
+
+// New 创建企业微信群机器人通知器
+func New(config Config, tmpl *template.Template) (*Notifier, error) {
+	if config.WebhookURL == "" {
+		return nil, fmt.Errorf("企业微信webhook URL不能为空")
+	}
+
+	// 发送速率令牌桶，由通知管理器按渠道统一创建，未指定时使用默认速率
+	limiter := config.Bucket
+	if limiter == nil {
+		limiter = pacing.NewBucket("wecom", DefaultPace)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	if config.Clock == nil {
+		config.Clock = clock.System
+	}
+
+	return &Notifier{
+		config:   config,
+		template: tmpl,
+		limiter:  limiter,
+		client:   client,
+	}, nil
+}
+
+// Name 通知渠道名称
+func (n *Notifier) Name() string {
+	return "wecom"
+}
+
+// IsEnabled 是否启用（因配置错误被停用后返回false）
+func (n *Notifier) IsEnabled() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.config.Enabled && n.disabledReason == ""
+}
+
+// Send 发送企业微信通知
+func (n *Notifier) Send(release *github.ReleaseInfo, run render.RunContext) error {
+	content := render.ExecuteOrFallback(n.template, release, run)
+
+	return n.sendMarkdown(truncate(content, maxContentBytes))
+}
+
+// SendBatch 批量发送企业微信通知（合并成一条消息）
+func (n *Notifier) SendBatch(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.sendMarkdown(buildBatchMarkdown(releases, run))
+}
+
+// SendDigest 将超过每日上限的版本合并为一条摘要消息发送
+func (n *Notifier) SendDigest(releases []*github.ReleaseInfo, run render.RunContext) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	return n.sendMarkdown(buildDigestMarkdown(releases, run))
+}
+
+// wait 等待冷却期结束和速率限制
+func (n *Notifier) wait() error {
+	n.mu.Lock()
+	remaining := n.cooldownUntil.Sub(n.config.Clock.Now())
+	n.mu.Unlock()
+
+	if remaining > 0 {
+		return fmt.Errorf("企业微信消息发送频率超过限制，冷却中，剩余时间：%v", remaining.Round(time.Second))
+	}
+
+	if err := n.limiter.Wait(context.Background()); err != nil {
+		return fmt.Errorf("速率限制等待错误: %v", err)
+	}
+	return nil
+}
+
+// sendMarkdown 发送markdown消息
+func (n *Notifier) sendMarkdown(content string) error {
+	if err := n.wait(); err != nil {
+		return err
+	}
+
+	type markdownMsg struct {
+		Content string `json:"content"`
+	}
+
+	type wecomMsg struct {
+		Msgtype  string      `json:"msgtype"`
+		Markdown markdownMsg `json:"markdown"`
+	}
+
+	return n.post(wecomMsg{
+		Msgtype:  "markdown",
+		Markdown: markdownMsg{Content: content},
+	})
+}
+
+// post 发送消息到企业微信webhook并检查返回结果
+func (n *Notifier) post(msg interface{}) error {
+	msgBytes, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("序列化消息失败: %v", err)
+	}
+
+	resp, err := n.client.Post(n.config.WebhookURL, "application/json", bytes.NewBuffer(msgBytes))
+	if err != nil {
+		return fmt.Errorf("发送消息失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
+	}
+
+	var response struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("解析响应失败: %v", err)
+	}
+
+	switch response.ErrCode {
+	case 0:
+		return nil
+	case errCodeRateLimit:
+		// 企业微信按分钟统计，冷却1分钟后即可恢复
+		n.mu.Lock()
+		n.cooldownUntil = n.config.Clock.Now().Add(time.Minute)
+		n.mu.Unlock()
+		return fmt.Errorf("触发企业微信API限流，已设置1分钟冷却期: %s", response.ErrMsg)
+	case errCodeInvalidKey, errCodeRemoved:
+		err := fmt.Errorf("企业微信API错误: %s (code: %d)，webhook中的key无效或机器人已被移出群聊，请检查webhook_url",
+			response.ErrMsg, response.ErrCode)
+		n.mu.Lock()
+		if n.disabledReason == "" {
+			n.disabledReason = err.Error()
+			log.Printf("已停用企业微信通知渠道: %s", n.disabledReason)
+		}
+		n.mu.Unlock()
+		return err
+	default:
+		return fmt.Errorf("企业微信API错误: %s (code: %d)", response.ErrMsg, response.ErrCode)
+	}
+}
@@ -0,0 +1,182 @@
+package wecom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// sentMessage 企业微信webhook收到的消息
+type sentMessage struct {
+	Msgtype  string `json:"msgtype"`
+	Markdown struct {
+		Content string `json:"content"`
+	} `json:"markdown"`
+}
+
+// newTestServer 启动模拟企业微信webhook的服务器，记录收到的消息并返回指定的响应
+func newTestServer(t *testing.T, response string) (*httptest.Server, *[]sentMessage) {
+	t.Helper()
+	var messages []sentMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var msg sentMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Errorf("无效的消息: %s", body)
+		}
+		if r.URL.Query().Get("key") != "k" {
+			t.Errorf("请求参数不正确: %s", r.URL.RawQuery)
+		}
+		messages = append(messages, msg)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &messages
+}
+
+func newTestNotifier(t *testing.T, url string, clk clock.Clock) *Notifier {
+	t.Helper()
+	n, err := New(Config{
+		Enabled:    true,
+		WebhookURL: url + "/cgi-bin/webhook/send?key=k",
+		Bucket:     pacing.NewBucket("wecom", pacing.Limit{Burst: 10}),
+		Clock:      clk,
+	}, template.Must(template.New("wecom").Parse("{{.Owner}}/{{.Repository}} {{.TagName}}")))
+	if err != nil {
+		t.Fatalf("创建通知器失败: %v", err)
+	}
+	return n
+}
+
+func testReleases(count int) []*github.ReleaseInfo {
+	var releases []*github.ReleaseInfo
+	for i := 0; i < count; i++ {
+		releases = append(releases, &github.ReleaseInfo{
+			Event:       github.EventRelease,
+			Owner:       "o",
+			Repository:  fmt.Sprintf("r%d", i),
+			TagName:     "v1.0.0",
+			HTMLURL:     fmt.Sprintf("https://github.com/o/r%d/releases/tag/v1.0.0", i),
+			Description: strings.Repeat("发布说明", 20),
+		})
+	}
+	return releases
+}
+
+// TestSend_Markdown 测试单个版本以markdown消息发送渲染后的模板
+func TestSend_Markdown(t *testing.T) {
+	srv, messages := newTestServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	n := newTestNotifier(t, srv.URL, nil)
+
+	if err := n.Send(testReleases(1)[0], render.RunContext{Timestamp: time.Now(), Total: 1}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if len(*messages) != 1 {
+		t.Fatalf("收到 %d 条消息，期望 1 条", len(*messages))
+	}
+	msg := (*messages)[0]
+	if msg.Msgtype != "markdown" || msg.Markdown.Content != "o/r0 v1.0.0" {
+		t.Errorf("消息内容不正确: %+v", msg)
+	}
+}
+
+// TestSendBatch_Split 测试批量消息超过4096字节时只发送能放下的版本，并注明剩余数量
+func TestSendBatch_Split(t *testing.T) {
+	srv, messages := newTestServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	n := newTestNotifier(t, srv.URL, nil)
+
+	if err := n.SendBatch(testReleases(3), render.RunContext{Timestamp: time.Now(), Total: 3}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	content := (*messages)[0].Markdown.Content
+	if !strings.Contains(content, "共 3 个仓库") || !strings.Contains(content, "[o/r2](https://github.com/o/r2/releases/tag/v1.0.0)") {
+		t.Errorf("批量消息应包含所有版本: %s", content)
+	}
+	if strings.Contains(content, "以及其他") {
+		t.Errorf("版本未超过大小限制时不应省略: %s", content)
+	}
+
+	if err := n.SendBatch(testReleases(40), render.RunContext{Timestamp: time.Now(), Total: 40}); err != nil {
+		t.Fatalf("发送失败: %v", err)
+	}
+	if len(*messages) != 2 {
+		t.Fatalf("收到 %d 条消息，期望 2 条", len(*messages))
+	}
+	content = (*messages)[1].Markdown.Content
+	if len(content) > maxContentBytes {
+		t.Errorf("消息内容 %d 字节，超过 %d 字节限制", len(content), maxContentBytes)
+	}
+	if !strings.Contains(content, "共 40 个仓库") || !strings.Contains(content, "...以及其他 ") {
+		t.Errorf("超出大小限制的版本应注明剩余数量: %s", content)
+	}
+	if strings.Contains(content, "o/r39") {
+		t.Errorf("超出大小限制的版本不应出现在消息中: %s", content)
+	}
+}
+
+// TestSend_ErrCode 测试企业微信返回非零 errcode 时发送失败
+func TestSend_ErrCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		disabled bool
+	}{
+		{"未知错误", `{"errcode":40008,"errmsg":"invalid message type"}`, false},
+		{"key无效", `{"errcode":93000,"errmsg":"invalid webhook url"}`, true},
+		{"机器人被移出群聊", `{"errcode":93004,"errmsg":"robot removed"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := newTestServer(t, tt.response)
+			n := newTestNotifier(t, srv.URL, nil)
+
+			err := n.SendBatch(testReleases(1), render.RunContext{Timestamp: time.Now(), Total: 1})
+			if err == nil || !strings.Contains(err.Error(), "企业微信API错误") {
+				t.Fatalf("期望返回企业微信API错误，得到: %v", err)
+			}
+			if n.IsEnabled() == tt.disabled {
+				t.Errorf("渠道启用状态不正确: %v", n.IsEnabled())
+			}
+		})
+	}
+}
+
+// TestSend_RateLimitCooldown 测试触发限流后冷却1分钟，冷却期间不再请求
+func TestSend_RateLimitCooldown(t *testing.T) {
+	srv, messages := newTestServer(t, `{"errcode":45009,"errmsg":"api freq out of limit"}`)
+	clk := clock.NewFake(time.Now())
+	n := newTestNotifier(t, srv.URL, clk)
+	run := render.RunContext{Timestamp: time.Now(), Total: 1}
+
+	if err := n.SendBatch(testReleases(1), run); err == nil || !strings.Contains(err.Error(), "限流") {
+		t.Fatalf("期望返回限流错误，得到: %v", err)
+	}
+	clk.Advance(30 * time.Second)
+	if err := n.SendBatch(testReleases(1), run); err == nil || !strings.Contains(err.Error(), "冷却中") {
+		t.Fatalf("冷却期间应直接返回错误，得到: %v", err)
+	}
+	if len(*messages) != 1 {
+		t.Errorf("冷却期间不应请求企业微信，收到 %d 条消息", len(*messages))
+	}
+
+	clk.Advance(31 * time.Second)
+	n.SendBatch(testReleases(1), run)
+	if len(*messages) != 2 {
+		t.Errorf("冷却结束后应重新请求企业微信，收到 %d 条消息", len(*messages))
+	}
+	if !n.IsEnabled() {
+		t.Error("限流不应停用渠道")
+	}
+}
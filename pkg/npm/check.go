@@ -0,0 +1,181 @@
+package npm
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/source"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// DefaultDistTag 未指定 dist_tags 时检查的标签
+const DefaultDistTag = "latest"
+
+// stateOwner 状态文件中的所属空间，每个 dist-tag 分别记录，加上来源前缀避免与GitHub上的同名仓库冲突
+func stateOwner(distTag string) string {
+	return github.SourceNPM + ":" + distTag
+}
+
+// checker 一次检查使用的客户端、状态和配置
+type checker struct {
+	*source.Base
+	client *Client
+}
+
+// Source npm包版本来源
+type Source struct{}
+
+// Name 来源名称
+func (Source) Name() string { return "npm包" }
+
+// Enabled 是否配置了npm包
+func (Source) Enabled(cfg *config.Config) bool { return len(cfg.NPM.Packages) > 0 }
+
+// Check 检查新版本
+func (Source) Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	return CheckForNewReleases(cfg, showDescription)
+}
+
+// CheckForNewReleases 检查配置的npm包是否有新版本
+// 检查期限和时区的设置与GitHub仓库相同（github.check_days、timezone）；
+// 配置的 dist-tag 即为要关注的版本线，include_prereleases 不影响npm包
+func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	packages := source.FilterShard(cfg.NPM.Packages, cfg.Shard, "npm包", func(p config.NPMPackageConfig) string { return github.SourceNPM + "/" + p.Name })
+	if len(packages) == 0 {
+		return nil, nil
+	}
+
+	base, err := source.NewBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(cfg.NPM.Registry, cfg.NPM.Token, cfg.Network.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{
+		Base:   base,
+		client: client,
+	}
+
+	fmt.Printf("正在检查 %d 个npm包（%s）...\n", len(packages), client.baseURL)
+	var results []*github.ReleaseInfo
+	errorCount := 0
+	for _, p := range packages {
+		infos, err := c.check(p, showDescription)
+		if err != nil {
+			fmt.Printf("检查npm包 %s 失败: %v\n", p.Name, err)
+			errorCount++
+		}
+		for _, info := range infos {
+			fmt.Printf("发现新版本: %s@%s\n", p.Name, info.TagName)
+		}
+		results = append(results, infos...)
+	}
+
+	fmt.Printf("npm检查完成: 发现 %d 个新版本", len(results))
+	if errorCount > 0 {
+		fmt.Printf("，%d 个包检查失败", errorCount)
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// check 检查一个包的各个 dist-tag，返回需要通知的新版本
+// 出错时仍返回出错之前已确认的新版本（状态已经更新）
+func (c *checker) check(p config.NPMPackageConfig, showDescription bool) ([]*github.ReleaseInfo, error) {
+	if p.Name == "" {
+		return nil, fmt.Errorf("包名不能为空")
+	}
+	distTags := p.DistTags
+	if len(distTags) == 0 {
+		distTags = []string{DefaultDistTag}
+	}
+
+	tags, err := c.client.distTags(p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		results []*github.ReleaseInfo
+		meta    *packument // 有版本变化时才读取，同一个包只读取一次
+	)
+	for _, distTag := range distTags {
+		v, ok := tags[distTag]
+		if !ok {
+			return results, fmt.Errorf("dist-tag %s 不存在", distTag)
+		}
+		// 通过 notify ignore 标记的版本不通知，也不记录状态
+		if entry, ok := c.Ignored.Match(github.SourceNPM, p.Name, v); ok {
+			fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+			continue
+		}
+		previous := c.Store.GetLatestTag(stateOwner(distTag), p.Name)
+		if previous == v {
+			continue
+		}
+
+		if meta == nil {
+			if meta, err = c.client.packument(p.Name); err != nil {
+				return results, err
+			}
+		}
+		published := meta.published(v)
+		if published.IsZero() {
+			published = time.Now()
+		}
+		// 超过检查期限的版本只记录状态，之后不再读取完整元数据
+		if published.Before(c.Since) {
+			if err := c.Store.UpdateState(stateOwner(distTag), p.Name, v); err != nil {
+				return results, fmt.Errorf("更新版本状态失败: %v", err)
+			}
+			continue
+		}
+
+		isNew, err := c.Store.CheckAndUpdateIfNew(stateOwner(distTag), p.Name, v)
+		if err != nil {
+			return results, fmt.Errorf("检查并更新版本状态失败: %v", err)
+		}
+		if !isNew {
+			continue
+		}
+
+		prerelease := false
+		if parsed, err := version.Parse(v); err == nil {
+			prerelease = parsed.IsPrerelease()
+		}
+		name := v
+		if distTag != DefaultDistTag {
+			name = fmt.Sprintf("%s (%s)", v, distTag)
+		}
+		info := &github.ReleaseInfo{
+			Event:       github.EventRelease,
+			Source:      github.SourceNPM,
+			Owner:       github.SourceNPM,
+			Repository:  p.Name,
+			TagName:     v,
+			Name:        name,
+			HTMLURL:     c.packageURL(p.Name, v),
+			PublishedAt: published.In(c.Loc),
+			Prerelease:  prerelease,
+			PreviousTag: previous,
+		}
+		if showDescription {
+			info.Description = meta.Description
+		}
+		results = append(results, info)
+	}
+	return results, nil
+}
+
+// packageURL 版本的页面地址，npmjs.com 上的包链接到版本页面，其他 registry 链接到包的元数据
+func (c *checker) packageURL(name, v string) string {
+	if c.client.baseURL == DefaultRegistry {
+		return "https://www.npmjs.com/package/" + name + "/v/" + url.PathEscape(v)
+	}
+	return c.client.baseURL + "/" + url.PathEscape(name)
+}
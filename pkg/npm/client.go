@@ -0,0 +1,119 @@
+// Package npm 检查 npm registry 上包的新版本
+//
+// 每次检查只读取包的 dist-tags，标签指向的版本变化时才读取完整的包元数据获取发布时间，
+// 关注多个 dist-tag 时每条版本线分别记录状态。
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultRegistry npmjs.com 的 registry 地址
+const DefaultRegistry = "https://registry.npmjs.org"
+
+// timeout 每个请求的超时时间
+const timeout = 15 * time.Second
+
+// packument 完整的包元数据中用到的字段
+type packument struct {
+	Description string `json:"description"`
+	// Time 每个版本的发布时间，另有 created、modified 等项（包被撤销发布时 unpublished 为对象）
+	Time map[string]any `json:"time"`
+}
+
+// Client npm registry客户端
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewClient 创建npm registry客户端，baseURL 为空时使用 npmjs.com，token 为空时只能访问公开的包
+func NewClient(baseURL, token, localAddr string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultRegistry
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("无效的npm registry地址 %s: %v", baseURL, err)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: timeout, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  client,
+	}, nil
+}
+
+// distTags 返回包的 dist-tags（标签到版本号）
+func (c *Client) distTags(name string) (map[string]string, error) {
+	var tags map[string]string
+	if err := c.get("/-/package/"+url.PathEscape(name)+"/dist-tags", 1<<20, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// packument 返回包的完整元数据，只在版本变化时读取
+// 完整元数据包含所有版本的信息，常用的大型包可达数十MB
+func (c *Client) packument(name string) (*packument, error) {
+	var p packument
+	if err := c.get("/"+url.PathEscape(name), 64<<20, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// published 返回版本的发布时间，没有记录时返回零值
+func (p *packument) published(version string) time.Time {
+	s, _ := p.Time[version].(string)
+	t, _ := time.Parse(time.RFC3339, s)
+	return t
+}
+
+// get 读取 registry 接口，响应最多读取 limit 字节
+func (c *Client) get(path string, limit int64, v any) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("请求npm registry失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("npm令牌无效或没有访问权限")
+	case http.StatusNotFound:
+		// 私有包在没有权限时同样返回404
+		return fmt.Errorf("包不存在或没有访问权限")
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("触发npm registry速率限制，请稍后重试")
+	default:
+		return fmt.Errorf("npm registry返回状态码 %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, limit)).Decode(v); err != nil {
+		return fmt.Errorf("解析npm registry响应失败: %v", err)
+	}
+	return nil
+}
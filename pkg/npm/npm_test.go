@@ -0,0 +1,149 @@
+package npm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// fakeRegistry 模拟npm registry，dist-tags 可在测试中修改，记录每个路径的请求次数和收到的令牌
+type fakeRegistry struct {
+	mu        sync.Mutex
+	distTags  map[string]map[string]string
+	published map[string]time.Time
+	requests  map[string]int
+	tokens    []string
+}
+
+func newFakeRegistry(t *testing.T, published time.Time) (*httptest.Server, *fakeRegistry) {
+	t.Helper()
+
+	f := &fakeRegistry{
+		distTags: map[string]map[string]string{
+			"@scope/pkg": {"latest": "1.2.0", "next": "2.0.0-rc.1"},
+			"left-pad":   {"latest": "1.3.0"},
+		},
+		published: map[string]time.Time{"1.2.0": published, "2.0.0-rc.1": published, "1.3.0": published, "1.3.1": published},
+		requests:  make(map[string]int),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		path := r.URL.EscapedPath()
+		f.requests[path]++
+		f.tokens = append(f.tokens, r.Header.Get("Authorization"))
+		switch path {
+		case "/-/package/@scope%2Fpkg/dist-tags":
+			fmt.Fprintf(w, `{"latest": %q, "next": %q}`, f.distTags["@scope/pkg"]["latest"], f.distTags["@scope/pkg"]["next"])
+		case "/-/package/left-pad/dist-tags":
+			fmt.Fprintf(w, `{"latest": %q}`, f.distTags["left-pad"]["latest"])
+		case "/@scope%2Fpkg", "/left-pad":
+			fmt.Fprintf(w, `{"description": "示例包", "time": {"created": %q, "unpublished": {"versions": ["0.1.0"]}`, published.Format(time.RFC3339))
+			for v, at := range f.published {
+				fmt.Fprintf(w, `, %q: %q`, v, at.Format(time.RFC3339))
+			}
+			fmt.Fprint(w, `}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "Not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, f
+}
+
+// TestCheckForNewReleases 测试按 dist-tag 检查、状态去重，以及版本未变化时不读取完整元数据
+func TestCheckForNewReleases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, registry := newFakeRegistry(t, time.Now().Add(-time.Hour))
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		NPM: config.NPMConfig{
+			Registry: server.URL + "/",
+			Token:    "npm-test",
+			Packages: []config.NPMPackageConfig{
+				{Name: "@scope/pkg", DistTags: []string{"latest", "next"}},
+				{Name: "left-pad"},
+				{Name: "missing"},
+			},
+		},
+		State: config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+
+	releases, err := CheckForNewReleases(cfg, true)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 3 {
+		t.Fatalf("发现 %d 个新版本，期望 3 个", len(releases))
+	}
+
+	latest, next := releases[0], releases[1]
+	if latest.Source != github.SourceNPM || latest.Owner != github.SourceNPM || latest.Repository != "@scope/pkg" || latest.TagName != "1.2.0" || latest.Prerelease {
+		t.Errorf("latest 版本为 %+v", latest)
+	}
+	if latest.HTMLURL != server.URL+"/@scope%2Fpkg" || latest.Description != "示例包" {
+		t.Errorf("链接为 %s，说明为 %q", latest.HTMLURL, latest.Description)
+	}
+	if next.TagName != "2.0.0-rc.1" || next.Name != "2.0.0-rc.1 (next)" || !next.Prerelease {
+		t.Errorf("next 版本为 %+v", next)
+	}
+	if releases[2].Repository != "left-pad" || releases[2].TagName != "1.3.0" {
+		t.Errorf("left-pad 版本为 %+v", releases[2])
+	}
+	for _, token := range registry.tokens {
+		if token != "Bearer npm-test" {
+			t.Fatalf("请求的令牌为 %q", token)
+		}
+	}
+
+	// 已通知的版本不再通知，也不再读取完整元数据；新版本的上一个版本为之前记录的版本
+	registry.mu.Lock()
+	registry.distTags["left-pad"]["latest"] = "1.3.1"
+	registry.mu.Unlock()
+	releases, err = CheckForNewReleases(cfg, false)
+	if err != nil {
+		t.Fatalf("再次检查失败: %v", err)
+	}
+	if len(releases) != 1 || releases[0].TagName != "1.3.1" || releases[0].PreviousTag != "1.3.0" {
+		t.Fatalf("再次检查发现 %+v，期望 left-pad 1.3.1", releases)
+	}
+	if n := registry.requests["/@scope%2Fpkg"]; n != 1 {
+		t.Errorf("@scope/pkg 的完整元数据读取了 %d 次，期望 1 次", n)
+	}
+}
+
+// TestCheckForNewReleases_CheckDays 发布时间早于检查期限的版本不通知，只记录状态
+func TestCheckForNewReleases_CheckDays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server, registry := newFakeRegistry(t, time.Now().AddDate(0, 0, -30))
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		NPM: config.NPMConfig{
+			Registry: server.URL,
+			Packages: []config.NPMPackageConfig{{Name: "left-pad"}},
+		},
+		State: config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+	for i := 0; i < 2; i++ {
+		releases, err := CheckForNewReleases(cfg, false)
+		if err != nil {
+			t.Fatalf("检查失败: %v", err)
+		}
+		if len(releases) != 0 {
+			t.Errorf("发现 %d 个新版本，期望 0 个", len(releases))
+		}
+	}
+	if n := registry.requests["/left-pad"]; n != 1 {
+		t.Errorf("完整元数据读取了 %d 次，期望 1 次", n)
+	}
+}
@@ -0,0 +1,273 @@
+// Package pacing 通知渠道的发送速率控制
+//
+// 每个渠道一个令牌桶，由通知管理器统一创建并交给对应的通知器，通知器每发出一个请求消耗一个令牌。
+// 默认速率由各通知器根据平台限制给出，可以在配置文件的 pacing 中按渠道覆盖。
+package pacing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"golang.org/x/time/rate"
+)
+
+// Limit 发送速率：每 Interval 补充一个令牌，最多累积 Burst 个
+type Limit struct {
+	Interval time.Duration
+	Burst    int
+}
+
+// String 返回速率描述，如 "每4s 1条，突发3条"
+func (l Limit) String() string {
+	return fmt.Sprintf("每%v 1条，突发%d条", l.Interval, l.Burst)
+}
+
+// Bucket 单个渠道的令牌桶
+type Bucket struct {
+	channel string
+	limit   Limit
+	limiter *rate.Limiter
+	// pacer 创建该令牌桶的 Pacer，单独创建时为nil
+	pacer *Pacer
+
+	mu sync.Mutex
+	// requests 已放行的请求数
+	requests int64
+	// delayed 需要等待令牌的请求数
+	delayed int64
+	// waited 累计等待时间
+	waited time.Duration
+	// lastRequest 最近一次放行的时间
+	lastRequest time.Time
+}
+
+// NewBucket 创建令牌桶
+func NewBucket(channel string, limit Limit) *Bucket {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	every := rate.Inf
+	if limit.Interval > 0 {
+		every = rate.Every(limit.Interval)
+	}
+	return &Bucket{
+		channel: channel,
+		limit:   limit,
+		limiter: rate.NewLimiter(every, limit.Burst),
+	}
+}
+
+// Wait 等待一个令牌
+// 所属的 Pacer 设置了上下文时，该上下文取消后也立即返回，通知器不需要各自传递上下文
+func (b *Bucket) Wait(ctx context.Context) error {
+	ctx, cancel := b.withPacerContext(ctx)
+	defer cancel()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// 按 Pacer 的时钟预约令牌并等待，测试中可以用假时钟控制令牌的补充
+	clk := b.pacer.clock()
+	start := clk.Now()
+	r := b.limiter.ReserveN(start, 1)
+	if !r.OK() {
+		return fmt.Errorf("%s 的令牌桶无法放行请求", b.channel)
+	}
+	if err := clock.Sleep(ctx, clk, r.DelayFrom(start)); err != nil {
+		r.CancelAt(clk.Now())
+		return err
+	}
+
+	now := clk.Now()
+	waited := now.Sub(start)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests++
+	// 忽略调度带来的微小延迟
+	if waited > time.Millisecond {
+		b.delayed++
+		b.waited += waited
+	}
+	b.lastRequest = now
+	return nil
+}
+
+// Sleep 按时钟 c 等待 d，如限流后等待重试
+// 与 Wait 相同，所属的 Pacer 的上下文取消后立即返回该上下文的错误
+func (b *Bucket) Sleep(ctx context.Context, c clock.Clock, d time.Duration) error {
+	ctx, cancel := b.withPacerContext(ctx)
+	defer cancel()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return clock.Sleep(ctx, c, d)
+}
+
+// withPacerContext 返回在 ctx 或所属 Pacer 的上下文取消时取消的上下文
+func (b *Bucket) withPacerContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	base := b.pacer.context()
+	if base == nil {
+		return ctx, func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	if base.Err() != nil {
+		cancel()
+		return ctx, cancel
+	}
+	stop := context.AfterFunc(base, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Status 令牌桶的当前状态
+type Status struct {
+	Channel string `json:"channel"`
+	// Interval 补充一个令牌的间隔
+	Interval string `json:"interval"`
+	Burst    int    `json:"burst"`
+	// Tokens 当前可用的令牌数，小于0表示已有请求在排队等待
+	Tokens float64 `json:"tokens"`
+	// Requests 已放行的请求数
+	Requests int64 `json:"requests"`
+	// Delayed 需要等待令牌的请求数
+	Delayed int64 `json:"delayed"`
+	// Waited 累计等待时间
+	Waited string `json:"waited"`
+	// LastRequest 最近一次放行的时间，没有请求时为空
+	LastRequest *time.Time `json:"last_request,omitempty"`
+}
+
+// Status 返回令牌桶的当前状态
+func (b *Bucket) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{
+		Channel:  b.channel,
+		Interval: b.limit.Interval.String(),
+		Burst:    b.limit.Burst,
+		Tokens:   b.limiter.TokensAt(b.pacer.clock().Now()),
+		Requests: b.requests,
+		Delayed:  b.delayed,
+		Waited:   b.waited.Round(time.Millisecond).String(),
+	}
+	if !b.lastRequest.IsZero() {
+		last := b.lastRequest
+		status.LastRequest = &last
+	}
+	return status
+}
+
+// Pacer 所有渠道的令牌桶
+type Pacer struct {
+	// overrides 配置中按渠道覆盖的速率
+	overrides map[string]Limit
+
+	mu      sync.Mutex
+	buckets map[string]*Bucket
+	// ctx 当前发送的上下文，取消后所有令牌桶的等待立即返回
+	ctx context.Context
+	// clk 令牌桶使用的时钟，为nil时使用系统时钟
+	clk clock.Clock
+}
+
+// New 创建Pacer，overrides 为按渠道覆盖的速率
+func New(overrides map[string]Limit) *Pacer {
+	return &Pacer{
+		overrides: overrides,
+		buckets:   make(map[string]*Bucket),
+	}
+}
+
+// Bucket 返回渠道的令牌桶，不存在时按配置的速率（未配置时为 def）创建
+// 同一类型的多个渠道实例（如 dingtalk/ops）各自使用一个令牌桶，实例未单独配置速率时使用该类型（如 dingtalk）的配置
+func (p *Pacer) Bucket(channel string, def Limit) *Bucket {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if b, ok := p.buckets[channel]; ok {
+		return b
+	}
+
+	limit := def
+	override, ok := p.overrides[channel]
+	if kind, _, isInstance := strings.Cut(channel, "/"); !ok && isInstance {
+		override, ok = p.overrides[kind]
+	}
+	if ok {
+		if override.Interval > 0 {
+			limit.Interval = override.Interval
+		}
+		if override.Burst > 0 {
+			limit.Burst = override.Burst
+		}
+	}
+
+	b := NewBucket(channel, limit)
+	b.pacer = p
+	p.buckets[channel] = b
+	return b
+}
+
+// SetContext 设置当前发送的上下文，为nil时等待令牌只受调用方的上下文控制
+func (p *Pacer) SetContext(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ctx = ctx
+}
+
+// SetClock 设置令牌桶使用的时钟，为nil时使用系统时钟
+func (p *Pacer) SetClock(c clock.Clock) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clk = c
+}
+
+// clock 返回令牌桶使用的时钟
+func (p *Pacer) clock() clock.Clock {
+	if p == nil {
+		return clock.System
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.clk == nil {
+		return clock.System
+	}
+	return p.clk
+}
+
+// context 返回当前发送的上下文
+func (p *Pacer) context() context.Context {
+	if p == nil {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ctx
+}
+
+// Status 返回所有令牌桶的状态，按渠道名称排序
+func (p *Pacer) Status() []Status {
+	p.mu.Lock()
+	buckets := make([]*Bucket, 0, len(p.buckets))
+	for _, b := range p.buckets {
+		buckets = append(buckets, b)
+	}
+	p.mu.Unlock()
+
+	statuses := make([]Status, 0, len(buckets))
+	for _, b := range buckets {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Channel < statuses[j].Channel
+	})
+	return statuses
+}
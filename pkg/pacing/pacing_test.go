@@ -0,0 +1,131 @@
+package pacing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/clock"
+)
+
+// TestBucketOverride 测试配置覆盖默认速率，同一渠道共用一个令牌桶
+func TestBucketOverride(t *testing.T) {
+	p := New(map[string]Limit{"slack": {Burst: 5}})
+
+	b := p.Bucket("slack", Limit{Interval: time.Second, Burst: 3})
+	if b.limit.Interval != time.Second || b.limit.Burst != 5 {
+		t.Errorf("速率为 %v，期望每1s 1条，突发5条", b.limit)
+	}
+	if p.Bucket("slack", Limit{Interval: time.Minute, Burst: 1}) != b {
+		t.Error("同一渠道应返回同一个令牌桶")
+	}
+
+	d := p.Bucket("dingtalk", Limit{Interval: 4 * time.Second, Burst: 3})
+	if d.limit.Interval != 4*time.Second || d.limit.Burst != 3 {
+		t.Errorf("未配置的渠道应使用默认速率，实际为 %v", d.limit)
+	}
+}
+
+// TestBucketInstance 测试同一类型的渠道实例各自使用令牌桶，未单独配置时使用该类型的速率
+func TestBucketInstance(t *testing.T) {
+	p := New(map[string]Limit{"telegram": {Burst: 5}, "telegram/ops": {Burst: 1}})
+	def := Limit{Interval: time.Second, Burst: 3}
+
+	dev := p.Bucket("telegram/dev", def)
+	if dev.limit.Interval != time.Second || dev.limit.Burst != 5 {
+		t.Errorf("telegram/dev 的速率为 %v，期望每1s 1条，突发5条", dev.limit)
+	}
+	if ops := p.Bucket("telegram/ops", def); ops == dev || ops.limit.Burst != 1 {
+		t.Errorf("telegram/ops 应使用单独的令牌桶和配置，速率为 %v", ops.limit)
+	}
+}
+
+// TestBucketStatus 测试令牌桶统计放行和等待的请求
+func TestBucketStatus(t *testing.T) {
+	p := New(nil)
+	b := p.Bucket("webhook", Limit{Interval: 20 * time.Millisecond, Burst: 1})
+
+	for i := 0; i < 3; i++ {
+		if err := b.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	statuses := p.Status()
+	if len(statuses) != 1 {
+		t.Fatalf("期望1个令牌桶，实际为 %d", len(statuses))
+	}
+	status := statuses[0]
+	if status.Channel != "webhook" || status.Requests != 3 || status.Delayed < 1 || status.LastRequest == nil {
+		t.Errorf("状态不正确: %+v", status)
+	}
+}
+
+// TestSetContext 测试 Pacer 的上下文取消后，通知器使用 context.Background() 的等待也立即返回
+func TestSetContext(t *testing.T) {
+	p := New(nil)
+	b := p.Bucket("dingtalk", Limit{Interval: time.Hour, Burst: 1})
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.SetContext(ctx)
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	start := time.Now()
+	if err := b.Wait(context.Background()); err == nil {
+		t.Fatal("上下文取消后应返回错误")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("取消后应立即返回，实际等待了 %v", elapsed)
+	}
+
+	// 已取消的上下文不再放行令牌
+	if err := b.Wait(context.Background()); err == nil {
+		t.Error("已取消的上下文应直接返回错误")
+	}
+
+	p.SetContext(nil)
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer waitCancel()
+	if err := b.Wait(waitCtx); err == nil {
+		t.Error("清除上下文后应只受调用方的上下文控制")
+	}
+}
+
+// TestSetClock 测试使用假时钟时，令牌按时钟补充，等待时间按时钟统计
+func TestSetClock(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	p := New(nil)
+	p.SetClock(fake)
+	b := p.Bucket("telegram", Limit{Interval: time.Minute, Burst: 1})
+
+	if err := b.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- b.Wait(context.Background()) }()
+	for fake.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatal("令牌补充前不应放行")
+	default:
+	}
+
+	fake.Advance(time.Minute)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	status := b.Status()
+	if status.Requests != 2 || status.Delayed != 1 || status.Waited != "1m0s" {
+		t.Errorf("状态不正确: %+v", status)
+	}
+	if !status.LastRequest.Equal(fake.Now()) {
+		t.Errorf("最近一次放行时间为 %v，期望 %v", status.LastRequest, fake.Now())
+	}
+}
@@ -0,0 +1,198 @@
+package pypi
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/source"
+	"github.com/orange-juzipi/notify/pkg/version"
+)
+
+// preReleasePattern PEP 440 的预发布和开发版本，如 5.0a1、5.0b2、5.0rc1、5.0.dev3
+var preReleasePattern = regexp.MustCompile(`(?i)^\d+(?:\.\d+)*[-_.]?((?:alpha|beta|preview|pre|rc|dev|a|b|c)[-_.]?\d*)`)
+
+// parseVersion 解析PEP 440版本号，识别 version.Parse 不认识的 a、b 等预发布后缀；.post 等发布后版本按正式版处理
+func parseVersion(s string) (version.Version, error) {
+	v, err := version.Parse(s)
+	if err != nil {
+		return v, err
+	}
+	if m := preReleasePattern.FindStringSubmatch(s); m != nil {
+		v.Prerelease = m[1]
+	} else {
+		v.Prerelease = ""
+	}
+	return v, nil
+}
+
+// stateOwner 状态文件中的所属空间，加上来源前缀和索引地址，避免与GitHub上的同名仓库以及其他索引上的同名包冲突
+func stateOwner(baseURL string) string {
+	host := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		host = u.Host + u.Path
+	}
+	return github.SourcePyPI + ":" + host
+}
+
+// checker 一次检查使用的客户端、状态和配置
+type checker struct {
+	*source.Base
+	client          *Client
+	showDescription bool
+}
+
+// Source PyPI包版本来源
+type Source struct{}
+
+// Name 来源名称
+func (Source) Name() string { return "PyPI包" }
+
+// Enabled 是否配置了PyPI包
+func (Source) Enabled(cfg *config.Config) bool { return len(cfg.PyPI.Packages) > 0 }
+
+// Check 检查新版本
+func (Source) Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	return CheckForNewReleases(cfg, showDescription)
+}
+
+// CheckForNewReleases 检查配置的PyPI包是否有新版本
+// 检查期限、时区和预发布版本的设置与GitHub仓库相同（github.check_days、timezone、include_prereleases）
+func CheckForNewReleases(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	packages := source.FilterShard(cfg.PyPI.Packages, cfg.Shard, "PyPI包", func(p config.PyPIPackageConfig) string { return github.SourcePyPI + "/" + normalizeName(p.Name) })
+	if len(packages) == 0 {
+		return nil, nil
+	}
+
+	base, err := source.NewBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(cfg.PyPI.IndexURL, cfg.Network.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{
+		Base:            base,
+		client:          client,
+		showDescription: showDescription,
+	}
+
+	fmt.Printf("正在检查 %d 个PyPI包（%s）...\n", len(packages), client.baseURL)
+	var results []*github.ReleaseInfo
+	errorCount := 0
+	for _, p := range packages {
+		info, err := c.check(p)
+		if err != nil {
+			fmt.Printf("检查PyPI包 %s 失败: %v\n", p.Name, err)
+			errorCount++
+			continue
+		}
+		if info != nil {
+			fmt.Printf("发现新版本: %s (%s)\n", p.Name, info.TagName)
+			results = append(results, info)
+		}
+	}
+
+	fmt.Printf("PyPI检查完成: 发现 %d 个新版本", len(results))
+	if errorCount > 0 {
+		fmt.Printf("，%d 个包检查失败", errorCount)
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// check 检查一个包，有需要通知的新版本时返回版本信息
+func (c *checker) check(p config.PyPIPackageConfig) (*github.ReleaseInfo, error) {
+	name := normalizeName(p.Name)
+	if name == "" {
+		return nil, fmt.Errorf("包名不能为空")
+	}
+	constraint, err := version.ParseConstraint(p.Versions)
+	if err != nil {
+		return nil, err
+	}
+
+	proj, err := c.client.project(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 选出范围内版本号最高的版本，跳过所有文件都已撤回（yanked）或没有文件的版本
+	var (
+		latest    string
+		latestVer version.Version
+		published time.Time
+	)
+	includePre := p.Prereleases || c.Config.GitHub.IncludePrereleases
+	for tag, files := range proj.Releases {
+		v, err := parseVersion(tag)
+		if err != nil || (v.IsPrerelease() && !includePre) || !constraint.Match(v) {
+			continue
+		}
+		uploaded, ok := firstUpload(files)
+		if !ok {
+			continue
+		}
+		if latest == "" || version.Compare(v, latestVer) > 0 {
+			latest, latestVer, published = tag, v, uploaded
+		}
+	}
+	if latest == "" || published.Before(c.Since) {
+		return nil, nil
+	}
+
+	// 通过 notify ignore 标记的版本不通知，也不记录状态
+	if entry, ok := c.Ignored.Match(github.SourcePyPI, name, latest); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+
+	owner := stateOwner(c.client.baseURL)
+	previousTag := c.Store.GetLatestTag(owner, name)
+	isNew, err := c.Store.CheckAndUpdateIfNew(owner, name, latest)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+
+	display := proj.Info.Name
+	if display == "" {
+		display = p.Name
+	}
+	info := &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Source:      github.SourcePyPI,
+		Owner:       github.SourcePyPI,
+		Repository:  display,
+		TagName:     latest,
+		Name:        latest,
+		HTMLURL:     fmt.Sprintf("%s/project/%s/%s/", c.client.baseURL, url.PathEscape(name), url.PathEscape(latest)),
+		PublishedAt: published.In(c.Loc),
+		Prerelease:  latestVer.IsPrerelease(),
+		PreviousTag: previousTag,
+	}
+	if c.showDescription {
+		info.Description = proj.Info.Summary
+	}
+	return info, nil
+}
+
+// firstUpload 返回版本最早上传的未撤回文件的时间，没有可用文件时返回false
+func firstUpload(files []file) (time.Time, bool) {
+	var first time.Time
+	for _, f := range files {
+		if f.Yanked {
+			continue
+		}
+		if first.IsZero() || f.UploadTime.Before(first) {
+			first = f.UploadTime
+		}
+	}
+	return first, !first.IsZero()
+}
@@ -0,0 +1,101 @@
+// Package pypi 检查 PyPI 上包的新版本
+//
+// 通过 JSON API（/pypi/<包名>/json）读取包的全部版本，选出满足版本范围的最新版本，
+// 已撤回（yanked）的版本不通知。
+package pypi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultIndexURL PyPI 的地址
+const DefaultIndexURL = "https://pypi.org"
+
+// timeout 每个请求的超时时间
+const timeout = 15 * time.Second
+
+// project JSON API返回的包信息中用到的字段
+type project struct {
+	Info struct {
+		Name    string `json:"name"`
+		Summary string `json:"summary"`
+	} `json:"info"`
+	// Releases 每个版本上传的文件，没有文件的版本（只注册了版本号）为空列表
+	Releases map[string][]file `json:"releases"`
+}
+
+// file 版本的一个发布文件（sdist 或 wheel）
+type file struct {
+	UploadTime time.Time `json:"upload_time_iso_8601"`
+	Yanked     bool      `json:"yanked"`
+}
+
+// Client PyPI JSON API客户端
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient 创建PyPI客户端，baseURL 为空时使用 pypi.org
+func NewClient(baseURL, localAddr string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultIndexURL
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("无效的PyPI地址 %s: %v", baseURL, err)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: timeout, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &Client{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}, nil
+}
+
+// project 读取包的信息和全部版本
+func (c *Client) project(name string) (*project, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/pypi/"+url.PathEscape(name)+"/json", nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求PyPI失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("包不存在")
+	case http.StatusTooManyRequests:
+		return nil, fmt.Errorf("触发PyPI速率限制，请稍后重试")
+	default:
+		return nil, fmt.Errorf("PyPI返回状态码 %d", resp.StatusCode)
+	}
+
+	var p project
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 32<<20)).Decode(&p); err != nil {
+		return nil, fmt.Errorf("解析PyPI响应失败: %v", err)
+	}
+	return &p, nil
+}
+
+// separators 包名中可以互换的分隔符
+var separators = regexp.MustCompile(`[-_.]+`)
+
+// normalizeName 规范化包名（PEP 503），Django、django_x 与 django-x 视为同一个包
+func normalizeName(name string) string {
+	return strings.ToLower(separators.ReplaceAllString(name, "-"))
+}
@@ -0,0 +1,127 @@
+package pypi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// fakePyPI 模拟PyPI JSON API，Django 有正式版、预发布版和已撤回的版本
+func fakePyPI(t *testing.T, uploaded time.Time) *httptest.Server {
+	t.Helper()
+
+	at := uploaded.Format(time.RFC3339)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pypi/django/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{
+			"info": {"name": "Django", "summary": "A high-level Python web framework."},
+			"releases": {
+				"4.2.10": [{"upload_time_iso_8601": %[1]q}],
+				"4.2.11": [{"upload_time_iso_8601": %[1]q}, {"upload_time_iso_8601": %[1]q}],
+				"4.2.12": [{"upload_time_iso_8601": %[1]q, "yanked": true}],
+				"5.0": [{"upload_time_iso_8601": %[1]q}],
+				"5.1b1": [{"upload_time_iso_8601": %[1]q}],
+				"5.2.dev1": [],
+				"not-a-version": [{"upload_time_iso_8601": %[1]q}]
+			}
+		}`, at)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestCheckForNewReleases 测试版本范围、预发布版本、已撤回的版本和状态去重
+func TestCheckForNewReleases(t *testing.T) {
+	for name, c := range map[string]struct {
+		pkg  config.PyPIPackageConfig
+		want string
+	}{
+		"只通知正式版":  {config.PyPIPackageConfig{Name: "Django"}, "5.0"},
+		"包含预发布版本": {config.PyPIPackageConfig{Name: "django", Prereleases: true}, "5.1b1"},
+		"版本范围":    {config.PyPIPackageConfig{Name: "django", Versions: ">=4.2,<5"}, "4.2.11"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			server := fakePyPI(t, time.Now().Add(-time.Hour))
+			cfg := &config.Config{
+				GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+				PyPI:   config.PyPIConfig{IndexURL: server.URL, Packages: []config.PyPIPackageConfig{c.pkg}},
+				State:  config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+			}
+
+			releases, err := CheckForNewReleases(cfg, true)
+			if err != nil {
+				t.Fatalf("检查失败: %v", err)
+			}
+			if len(releases) != 1 {
+				t.Fatalf("发现 %d 个新版本，期望 1 个", len(releases))
+			}
+			r := releases[0]
+			if r.Source != github.SourcePyPI || r.Repository != "Django" || r.TagName != c.want {
+				t.Errorf("版本为 %s %s，期望 %s", r.Repository, r.TagName, c.want)
+			}
+			if r.HTMLURL != server.URL+"/project/django/"+c.want+"/" || r.Description != "A high-level Python web framework." {
+				t.Errorf("链接为 %s，说明为 %q", r.HTMLURL, r.Description)
+			}
+
+			// 已通知的版本不再通知
+			releases, err = CheckForNewReleases(cfg, true)
+			if err != nil {
+				t.Fatalf("再次检查失败: %v", err)
+			}
+			if len(releases) != 0 {
+				t.Errorf("再次检查发现 %d 个新版本，期望 0 个", len(releases))
+			}
+		})
+	}
+}
+
+// TestCheckForNewReleases_CheckDays 上传时间早于检查期限的版本不通知
+func TestCheckForNewReleases_CheckDays(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := fakePyPI(t, time.Now().AddDate(0, 0, -30))
+
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		PyPI:   config.PyPIConfig{IndexURL: server.URL, Packages: []config.PyPIPackageConfig{{Name: "django"}, {Name: "missing"}, {Name: "django", Versions: ">=x"}}},
+		State:  config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+	releases, err := CheckForNewReleases(cfg, false)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 0 {
+		t.Errorf("发现 %d 个新版本，期望 0 个", len(releases))
+	}
+}
+
+// TestParseVersion 测试PEP 440预发布版本的识别
+func TestParseVersion(t *testing.T) {
+	for s, want := range map[string]bool{
+		"5.0":        false,
+		"5.0a1":      true,
+		"5.0b2":      true,
+		"5.0rc1":     true,
+		"5.0.dev3":   true,
+		"2.0.post1":  false,
+		"1.0alpha2":  true,
+		"24.1.0":     false,
+		"3.12.0rc.1": true,
+	} {
+		v, err := parseVersion(s)
+		if err != nil {
+			t.Errorf("parseVersion(%q) 失败: %v", s, err)
+			continue
+		}
+		if v.IsPrerelease() != want {
+			t.Errorf("%s 是否为预发布版本: %v，期望 %v", s, v.IsPrerelease(), want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultReplacement 未配置替换文字时使用的占位符
+const DefaultReplacement = "[已隐藏]"
+
+// Rule 一条过滤规则
+type Rule struct {
+	// Pattern 正则表达式（Go RE2 语法）
+	Pattern string
+	// Replacement 替换文字，支持 $1、${name} 引用分组，为空时使用 DefaultReplacement
+	Replacement string
+}
+
+// Redactor 按顺序应用过滤规则，隐藏消息中的敏感内容
+type Redactor struct {
+	patterns     []*regexp.Regexp
+	replacements []string
+}
+
+// New 编译过滤规则，任意一条规则无效时返回错误
+// 没有规则时返回nil，nil的 Redactor 不做任何处理
+func New(rules []Rule) (*Redactor, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	r := &Redactor{}
+	for i, rule := range rules {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("第 %d 条过滤规则的 pattern 不能为空", i+1)
+		}
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 条过滤规则无效: %v", i+1, err)
+		}
+		replacement := rule.Replacement
+		if replacement == "" {
+			replacement = DefaultReplacement
+		}
+		r.patterns = append(r.patterns, re)
+		r.replacements = append(r.replacements, replacement)
+	}
+	return r, nil
+}
+
+// Apply 返回过滤后的文字和被替换的次数
+func (r *Redactor) Apply(s string) (string, int) {
+	if r == nil || s == "" {
+		return s, 0
+	}
+
+	count := 0
+	for i, re := range r.patterns {
+		matches := len(re.FindAllStringIndex(s, -1))
+		if matches == 0 {
+			continue
+		}
+		s = re.ReplaceAllString(s, r.replacements[i])
+		count += matches
+	}
+	return s, count
+}
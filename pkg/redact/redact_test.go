@@ -0,0 +1,46 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestApply 测试默认替换文字、分组引用和替换次数
+func TestApply(t *testing.T) {
+	r, err := New([]Rule{
+		{Pattern: `gh[pousr]_[A-Za-z0-9]{36}`},
+		{Pattern: `([a-z0-9-]+)\.corp\.example\.com`, Replacement: "$1.internal"},
+	})
+	if err != nil {
+		t.Fatalf("编译规则失败: %v", err)
+	}
+
+	token := "ghp_" + strings.Repeat("a", 36)
+	got, n := r.Apply("token " + token + " 部署到 build-01.corp.example.com 和 ci.corp.example.com")
+	want := "token [已隐藏] 部署到 build-01.internal 和 ci.internal"
+	if got != want || n != 3 {
+		t.Errorf("过滤结果为 %q（%d 处），期望 %q（3 处）", got, n, want)
+	}
+
+	if got, n := r.Apply("没有敏感内容"); got != "没有敏感内容" || n != 0 {
+		t.Errorf("不应替换: %q（%d 处）", got, n)
+	}
+}
+
+// TestNew 测试无效规则和空规则
+func TestNew(t *testing.T) {
+	if _, err := New([]Rule{{Pattern: "("}}); err == nil {
+		t.Error("无效的正则表达式应返回错误")
+	}
+	if _, err := New([]Rule{{Replacement: "x"}}); err == nil {
+		t.Error("空的 pattern 应返回错误")
+	}
+
+	r, err := New(nil)
+	if err != nil || r != nil {
+		t.Fatalf("没有规则时应返回nil: %v", err)
+	}
+	if got, n := r.Apply("ghp_x"); got != "ghp_x" || n != 0 {
+		t.Errorf("nil 的 Redactor 不应修改内容: %q", got)
+	}
+}
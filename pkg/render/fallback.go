@@ -0,0 +1,74 @@
+package render
+
+import (
+	"fmt"
+	"sync"
+	"text/template"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// Failure 一个版本的模板渲染失败
+type Failure struct {
+	// Channel 渲染消息的通知渠道
+	Channel string
+	Owner   string
+	Repo    string
+	TagName string
+	Err     error
+}
+
+// String 返回失败的描述，如 "dingtalk: owner/repo v1.0.0: 错误信息"
+func (f Failure) String() string {
+	return fmt.Sprintf("%s: %s/%s %s: %v", f.Channel, f.Owner, f.Repo, f.TagName, f.Err)
+}
+
+// Failures 收集一次运行中的模板渲染失败，可以并发使用
+type Failures struct {
+	mu   sync.Mutex
+	list []Failure
+}
+
+// Add 记录一个失败
+func (f *Failures) Add(failure Failure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.list = append(f.list, failure)
+}
+
+// Take 返回并清空记录的失败，用于在运行结束时汇总报告
+func (f *Failures) Take() []Failure {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	taken := f.list
+	f.list = nil
+	return taken
+}
+
+// ExecuteOrFallback 渲染模板，失败时记录到 run.Failures 并返回只包含仓库、版本和链接的简化内容
+// 个别版本的发布说明（如自定义模板函数无法处理的内容）不会导致整条消息发送失败
+func ExecuteOrFallback(tmpl *template.Template, release *github.ReleaseInfo, run RunContext) string {
+	content, err := Execute(tmpl, release, run)
+	if err == nil {
+		return content
+	}
+
+	if run.Failures != nil {
+		run.Failures.Add(Failure{
+			Channel: run.Channel,
+			Owner:   release.Owner,
+			Repo:    release.Repository,
+			TagName: release.TagName,
+			Err:     err,
+		})
+	}
+	return Fallback(release, run)
+}
+
+// Fallback 模板渲染失败时使用的简化内容
+func Fallback(release *github.ReleaseInfo, run RunContext) string {
+	if run.Locale == LocaleEN {
+		return fmt.Sprintf("%s/%s released %s\n%s", release.Owner, release.Repository, release.TagName, release.Link())
+	}
+	return fmt.Sprintf("%s/%s 发布新版本 %s\n%s", release.Owner, release.Repository, release.TagName, release.Link())
+}
@@ -0,0 +1,57 @@
+package render
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// TestExecuteOrFallback 模板渲染失败的版本使用简化内容，并记录到所属运行的收集器
+func TestExecuteOrFallback(t *testing.T) {
+	tmpl := template.Must(template.New("release").Funcs(template.FuncMap{
+		"strict": func(s string) (string, error) {
+			if strings.Contains(s, "\x00") {
+				return "", errors.New("无效字符")
+			}
+			return s, nil
+		},
+	}).Parse("{{.Owner}}/{{.Repository}} {{.TagName}}: {{strict .Description}}"))
+
+	good := &github.ReleaseInfo{Owner: "o", Repository: "a", TagName: "v1.0.0", Description: "修复"}
+	bad := &github.ReleaseInfo{Owner: "o", Repository: "b", TagName: "v2.0.0", Description: "\x00", HTMLURL: "https://example.com/b"}
+	run := RunContext{Channel: "slack", Failures: &Failures{}}
+	other := RunContext{Channel: "slack", Failures: &Failures{}}
+
+	if got := ExecuteOrFallback(tmpl, good, run); got != "o/a v1.0.0: 修复" {
+		t.Errorf("渲染结果为 %q", got)
+	}
+	if got := ExecuteOrFallback(tmpl, bad, run); got != "o/b 发布新版本 v2.0.0\nhttps://example.com/b" {
+		t.Errorf("简化内容为 %q", got)
+	}
+	if got := ExecuteOrFallback(tmpl, bad, RunContext{Channel: "email", Locale: LocaleEN, Failures: run.Failures}); !strings.HasPrefix(got, "o/b released v2.0.0") {
+		t.Errorf("英文简化内容为 %q", got)
+	}
+
+	// 未设置收集器时只返回简化内容
+	if got := ExecuteOrFallback(tmpl, bad, RunContext{}); !strings.HasPrefix(got, "o/b 发布新版本") {
+		t.Errorf("简化内容为 %q", got)
+	}
+
+	failures := run.Failures.Take()
+	if len(failures) != 2 {
+		t.Fatalf("记录了 %d 个失败，期望 2 个", len(failures))
+	}
+	if f := failures[0]; f.Channel != "slack" || f.Repo != "b" || !strings.Contains(f.String(), "无效字符") {
+		t.Errorf("失败记录为 %s", f)
+	}
+	if n := len(run.Failures.Take()); n != 0 {
+		t.Errorf("取出后仍有 %d 个失败", n)
+	}
+	// 失败只记录在所属运行的收集器中
+	if n := len(other.Failures.Take()); n != 0 {
+		t.Errorf("其他运行记录了 %d 个失败", n)
+	}
+}
@@ -0,0 +1,101 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// RunContext 一次运行/一条消息的上下文信息，可在模板中通过 .Run 访问
+type RunContext struct {
+	// Timestamp 本次运行开始的时间（配置的时区）
+	Timestamp time.Time
+	// Total 本次运行需要通知的版本总数
+	Total int
+	// Index 当前消息序号（从1开始）
+	Index int
+	// Of 本次运行的消息总数
+	Of int
+	// Channel 通知渠道名称
+	Channel string
+	// Timezone 配置的时区
+	Timezone string
+	// TimeFormat 消息中的时间格式，预设名称或Go时间格式
+	TimeFormat string
+	// Locale 消息语言
+	Locale string
+	// AckID 消息的确认标识，启用确认跟踪的渠道将它附带在"已查看"按钮中，为空时不附带按钮
+	AckID string
+	// Failures 收集模板渲染失败，为nil时不记录
+	Failures *Failures
+}
+
+// FormatTime 按配置的时间格式格式化时间
+func (r RunContext) FormatTime(t time.Time) string {
+	return FormatTime(t, r.TimeFormat, r.Locale)
+}
+
+// Footer 返回批次信息，如 "第 1/3 批 • 生成于 2024-07-01 09:00 CST"，没有批次信息时返回空字符串
+func (r RunContext) Footer() string {
+	if r.Of == 0 {
+		return ""
+	}
+	if r.Locale == LocaleEN {
+		return fmt.Sprintf("Batch %d/%d • generated at %s", r.Index, r.Of, r.Timestamp.Format("2006-01-02 15:04 MST"))
+	}
+	return fmt.Sprintf("第 %d/%d 批 • 生成于 %s", r.Index, r.Of, r.Timestamp.Format("2006-01-02 15:04 MST"))
+}
+
+// TemplateData 模板渲染数据，可直接访问版本字段（如 .TagName），并通过 .Run 访问运行上下文
+type TemplateData struct {
+	*github.ReleaseInfo
+	Run RunContext
+}
+
+// Options 模板解析选项
+type Options struct {
+	// Partials 命名模板片段
+	Partials map[string]string
+	// TimeFormat formatTime 使用的时间格式，预设名称或Go时间格式
+	TimeFormat string
+	// Locale 相对时间和本地化日期格式使用的语言（zh、en）
+	Locale string
+}
+
+// Parse 解析通知模板及命名片段，并注册时间格式化等模板函数
+// 片段可以在模板中通过 {{template "header" .}} 引用；主模板最后解析，其中的 {{define}} 可以覆盖片段中 {{block}} 的默认内容
+func Parse(text string, opts Options) (*template.Template, error) {
+	tmpl := template.New("release").Funcs(timeFuncs(opts))
+	partials := opts.Partials
+
+	// 按名称顺序解析，重复定义同名块时结果保持稳定
+	names := make([]string, 0, len(partials))
+	for name := range partials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := tmpl.New(name).Parse(partials[name]); err != nil {
+			return nil, fmt.Errorf("解析模板片段 %s 失败: %v", name, err)
+		}
+	}
+
+	if _, err := tmpl.Parse(text); err != nil {
+		return nil, fmt.Errorf("解析通知模板失败: %v", err)
+	}
+	return tmpl, nil
+}
+
+// Execute 使用版本信息和运行上下文渲染模板
+func Execute(tmpl *template.Template, release *github.ReleaseInfo, run RunContext) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, TemplateData{ReleaseInfo: release, Run: run}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
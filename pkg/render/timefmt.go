@@ -0,0 +1,142 @@
+package render
+
+import (
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// 支持的语言
+const (
+	LocaleZH = "zh"
+	LocaleEN = "en"
+)
+
+// DefaultTimeFormat 默认时间格式
+const DefaultTimeFormat = "datetime"
+
+// timePresets 与语言无关的预设时间格式
+var timePresets = map[string]string{
+	"datetime": "2006-01-02 15:04:05",
+	"date":     "2006-01-02",
+	"time":     "15:04",
+	"iso":      time.RFC3339,
+	"rfc1123":  time.RFC1123,
+}
+
+// localePresets 与语言相关的预设时间格式
+var localePresets = map[string]map[string]string{
+	LocaleZH: {
+		"long":  "2006年1月2日 15:04",
+		"short": "1月2日 15:04",
+	},
+	LocaleEN: {
+		"long":  "Jan 2, 2006 3:04 PM",
+		"short": "Jan 2 15:04",
+	},
+}
+
+// timeLayout 将预设名称转换为Go时间格式，不是预设名称时原样作为Go时间格式使用
+func timeLayout(format, locale string) string {
+	if format == "" {
+		format = DefaultTimeFormat
+	}
+	if layout, ok := timePresets[format]; ok {
+		return layout
+	}
+	if presets, ok := localePresets[locale]; ok {
+		if layout, ok := presets[format]; ok {
+			return layout
+		}
+	}
+	if layout, ok := localePresets[LocaleZH][format]; ok {
+		return layout
+	}
+	return format
+}
+
+// FormatTime 按预设名称（datetime、date、time、iso、rfc1123、long、short）或Go时间格式格式化时间
+func FormatTime(t time.Time, format, locale string) string {
+	return t.Format(timeLayout(format, locale))
+}
+
+// Humanize 返回相对于now的易读时间，如 "3 小时前"、"3 hours ago"
+func Humanize(t, now time.Time, locale string) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var n int
+	var unit string
+	switch {
+	case d < time.Minute:
+		if locale == LocaleEN {
+			return "just now"
+		}
+		return "刚刚"
+	case d < time.Hour:
+		n, unit = int(d/time.Minute), "minute"
+	case d < 24*time.Hour:
+		n, unit = int(d/time.Hour), "hour"
+	case d < 30*24*time.Hour:
+		n, unit = int(d/(24*time.Hour)), "day"
+	case d < 365*24*time.Hour:
+		n, unit = int(d/(30*24*time.Hour)), "month"
+	default:
+		n, unit = int(d/(365*24*time.Hour)), "year"
+	}
+
+	if locale == LocaleEN {
+		if n != 1 {
+			unit += "s"
+		}
+		if future {
+			return fmt.Sprintf("in %d %s", n, unit)
+		}
+		return fmt.Sprintf("%d %s ago", n, unit)
+	}
+
+	units := map[string]string{
+		"minute": "分钟",
+		"hour":   "小时",
+		"day":    "天",
+		"month":  "个月",
+		"year":   "年",
+	}
+	if future {
+		return fmt.Sprintf("%d %s后", n, units[unit])
+	}
+	return fmt.Sprintf("%d %s前", n, units[unit])
+}
+
+// inZone 将时间转换到指定时区，时区无效时原样返回
+func inZone(name string, t time.Time) time.Time {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return t
+	}
+	return t.In(loc)
+}
+
+// timeFuncs 模板中可用的时间函数
+//
+//	{{ago .PublishedAt}}                          3 小时前
+//	{{formatTime .PublishedAt}}                   使用配置的时间格式
+//	{{date "long" .PublishedAt}}                  使用预设名称或Go时间格式
+//	{{.PublishedAt | inZone "UTC" | date "iso"}}  转换时区后格式化
+func timeFuncs(opts Options) template.FuncMap {
+	return template.FuncMap{
+		"ago": func(t time.Time) string {
+			return Humanize(t, time.Now(), opts.Locale)
+		},
+		"formatTime": func(t time.Time) string {
+			return FormatTime(t, opts.TimeFormat, opts.Locale)
+		},
+		"date": func(format string, t time.Time) string {
+			return FormatTime(t, format, opts.Locale)
+		},
+		"inZone": inZone,
+	}
+}
@@ -0,0 +1,53 @@
+package render
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHumanize(t *testing.T) {
+	now := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t      time.Time
+		locale string
+		want   string
+	}{
+		{now.Add(-30 * time.Second), LocaleZH, "刚刚"},
+		{now.Add(-3 * time.Hour), LocaleZH, "3 小时前"},
+		{now.Add(-2 * 24 * time.Hour), LocaleZH, "2 天前"},
+		{now.Add(10 * time.Minute), LocaleZH, "10 分钟后"},
+		{now.Add(-1 * time.Hour), LocaleEN, "1 hour ago"},
+		{now.Add(-45 * 24 * time.Hour), LocaleEN, "1 month ago"},
+		{now.Add(-3 * time.Minute), LocaleEN, "3 minutes ago"},
+		{now.Add(2 * 24 * time.Hour), LocaleEN, "in 2 days"},
+	}
+
+	for _, tt := range tests {
+		if got := Humanize(tt.t, now, tt.locale); got != tt.want {
+			t.Errorf("Humanize(%v, %s) = %q, want %q", now.Sub(tt.t), tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	ts := time.Date(2024, 7, 1, 9, 5, 0, 0, time.UTC)
+
+	tests := []struct {
+		format string
+		locale string
+		want   string
+	}{
+		{"", LocaleZH, "2024-07-01 09:05:00"},
+		{"date", LocaleZH, "2024-07-01"},
+		{"long", LocaleZH, "2024年7月1日 09:05"},
+		{"long", LocaleEN, "Jul 1, 2024 9:05 AM"},
+		{"02/01/2006", LocaleEN, "01/07/2024"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatTime(ts, tt.format, tt.locale); got != tt.want {
+			t.Errorf("FormatTime(%q, %s) = %q, want %q", tt.format, tt.locale, got, tt.want)
+		}
+	}
+}
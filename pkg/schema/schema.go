@@ -0,0 +1,74 @@
+// Package schema 定义对外输出的版本事件JSON负载及其JSON Schema
+//
+// 负载结构按 Version 进行版本管理：同一版本内只会新增可选字段，
+// 删除、重命名字段或改变字段含义时 Version 加一，集成方可以据此判断兼容性。
+package schema
+
+import (
+	_ "embed"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// Version 负载结构的版本，对应负载中的 schema_version 字段
+const Version = 1
+
+// ID JSON Schema 的 $id
+const ID = "https://github.com/orange-juzipi/notify/schema/v1/payload.json"
+
+// Document 负载的JSON Schema（draft 2020-12），$defs.release 描述单个版本（ReleaseInfo）
+//
+//go:embed payload.schema.json
+var Document []byte
+
+// 负载类型
+const (
+	// TypeRelease 单个版本
+	TypeRelease = "release"
+	// TypeBatch 一批版本
+	TypeBatch = "batch"
+	// TypeDigest 超过每日上限后的摘要
+	TypeDigest = "digest"
+)
+
+// Payload 版本事件负载
+type Payload struct {
+	// SchemaVersion 负载结构的版本，见 Version
+	SchemaVersion int    `json:"schema_version"`
+	Type          string `json:"type"`
+	// Release 单个版本时的版本信息，批量和摘要时为空
+	Release  *github.ReleaseInfo   `json:"release,omitempty"`
+	Releases []*github.ReleaseInfo `json:"releases"`
+	Run      Run                   `json:"run"`
+}
+
+// Run 本次运行的上下文
+type Run struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Total 本次运行发现的版本总数
+	Total int `json:"total"`
+	// Index/Of 当前批次/批次总数
+	Index int `json:"index,omitempty"`
+	Of    int `json:"of,omitempty"`
+}
+
+// NewPayload 构建负载
+func NewPayload(kind string, releases []*github.ReleaseInfo, run render.RunContext) Payload {
+	p := Payload{
+		SchemaVersion: Version,
+		Type:          kind,
+		Releases:      releases,
+		Run: Run{
+			Timestamp: run.Timestamp,
+			Total:     run.Total,
+			Index:     run.Index,
+			Of:        run.Of,
+		},
+	}
+	if kind == TypeRelease && len(releases) == 1 {
+		p.Release = releases[0]
+	}
+	return p
+}
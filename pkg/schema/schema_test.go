@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// jsonFields 结构体的JSON字段名和是否必有（没有 omitempty）
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fields[name] = !strings.Contains(opts, "omitempty")
+	}
+	return fields
+}
+
+type object struct {
+	Required   []string                   `json:"required"`
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// checkObject 检查结构体字段与Schema中的对象定义一致
+func checkObject(t *testing.T, name string, typ reflect.Type, obj object) {
+	fields := jsonFields(typ)
+	for field := range fields {
+		if _, ok := obj.Properties[field]; !ok {
+			t.Errorf("%s: 字段 %s 没有出现在Schema中", name, field)
+		}
+	}
+	for field := range obj.Properties {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("%s: Schema中的字段 %s 在结构体中不存在", name, field)
+		}
+	}
+	for _, field := range obj.Required {
+		if !fields[field] {
+			t.Errorf("%s: 必需字段 %s 在结构体中可能被省略", name, field)
+		}
+	}
+}
+
+// TestDocumentMatchesTypes 测试Schema与负载结构体保持一致，修改 ReleaseInfo 或 Payload 时需要同步更新Schema
+func TestDocumentMatchesTypes(t *testing.T) {
+	var doc struct {
+		ID         string `json:"$id"`
+		Properties map[string]json.RawMessage
+		Required   []string
+		Defs       map[string]object `json:"$defs"`
+	}
+	if err := json.Unmarshal(Document, &doc); err != nil {
+		t.Fatalf("解析Schema失败: %v", err)
+	}
+	if doc.ID != ID {
+		t.Errorf("$id 为 %s，期望 %s", doc.ID, ID)
+	}
+
+	var version struct {
+		Const int `json:"const"`
+	}
+	json.Unmarshal(doc.Properties["schema_version"], &version)
+	if version.Const != Version {
+		t.Errorf("schema_version 为 %d，期望 %d", version.Const, Version)
+	}
+
+	checkObject(t, "payload", reflect.TypeOf(Payload{}), object{Required: doc.Required, Properties: doc.Properties})
+	checkObject(t, "release", reflect.TypeOf(github.ReleaseInfo{}), doc.Defs["release"])
+
+	var run object
+	json.Unmarshal(doc.Properties["run"], &run)
+	checkObject(t, "run", reflect.TypeOf(Run{}), run)
+}
@@ -0,0 +1,189 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier/webhook"
+	"github.com/orange-juzipi/notify/pkg/render"
+)
+
+// DefaultAlertTemplate 内置的告警消息模板，每条告警显示名称、级别、实例、摘要和时间
+const DefaultAlertTemplate = `{{range .Alerts}}{{if eq .Status "firing"}}🔥{{else}}✅{{end}} {{.Labels.alertname}}{{with .Labels.severity}} [{{.}}]{{end}}{{with .Labels.instance}} @ {{.}}{{end}}
+{{with .Annotations.summary}}{{.}}
+{{end}}{{with .Annotations.description}}{{.}}
+{{end}}开始于 {{formatTime .StartsAt}}{{if eq .Status "resolved"}}，恢复于 {{formatTime .EndsAt}}{{end}}
+
+{{end}}`
+
+// Alertmanager 告警状态
+const (
+	alertFiring   = "firing"
+	alertResolved = "resolved"
+)
+
+// Alert Alertmanager webhook中的一条告警
+type Alert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertGroup Alertmanager webhook的请求体（version 4），同一分组的告警在一个请求中发送，也是告警模板的数据
+type AlertGroup struct {
+	Version           string            `json:"version"`
+	GroupKey          string            `json:"groupKey"`
+	TruncatedAlerts   int               `json:"truncatedAlerts"`
+	Status            string            `json:"status"`
+	Receiver          string            `json:"receiver"`
+	GroupLabels       map[string]string `json:"groupLabels"`
+	CommonLabels      map[string]string `json:"commonLabels"`
+	CommonAnnotations map[string]string `json:"commonAnnotations"`
+	ExternalURL       string            `json:"externalURL"`
+	Alerts            []Alert           `json:"alerts"`
+}
+
+// Firing 返回仍在触发的告警，模板中通过 {{range .Firing}} 使用
+func (g *AlertGroup) Firing() []Alert {
+	return g.filter(alertFiring)
+}
+
+// Resolved 返回已恢复的告警
+func (g *AlertGroup) Resolved() []Alert {
+	return g.filter(alertResolved)
+}
+
+func (g *AlertGroup) filter(status string) []Alert {
+	var alerts []Alert
+	for _, a := range g.Alerts {
+		if a.Status == status {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts
+}
+
+// parseAlertTemplate 解析告警消息模板，为空时使用内置模板
+func parseAlertTemplate(cfg *config.Config) (*template.Template, error) {
+	text := cfg.Serve.Alertmanager.Template
+	if text == "" {
+		text = DefaultAlertTemplate
+	}
+	tmpl, err := render.Parse(text, render.Options{TimeFormat: cfg.Format.TimeFormat, Locale: cfg.Format.Locale})
+	if err != nil {
+		return nil, fmt.Errorf("告警模板: %v", err)
+	}
+	return tmpl, nil
+}
+
+// handleAlertmanager 接收Alertmanager的webhook，将一组告警转换为一条通知，使用与版本通知相同的渠道发送
+// 告警的重复发送由Alertmanager的 repeat_interval 控制，不经过状态文件去重和忽略列表
+func (s *Server) handleAlertmanager(w http.ResponseWriter, r *http.Request) {
+	// Alertmanager 通过 http_config.authorization 发送 Bearer Token
+	if token := s.config.Alertmanager.Token; token != "" {
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			log.Printf("拒绝Alertmanager请求: 令牌校验失败")
+			http.Error(w, "Alertmanager令牌校验失败", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	var group AlertGroup
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&group); err != nil {
+		http.Error(w, fmt.Sprintf("解析告警失败: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(group.Alerts) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	release, err := s.alertRelease(&group)
+	if err != nil {
+		log.Printf("渲染告警模板失败: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.enqueueMu.Lock()
+	defer s.enqueueMu.Unlock()
+	select {
+	case s.queue <- release:
+		log.Printf("收到 Alertmanager 告警: %s %s（%d 条）", release.Repository, group.Status, len(group.Alerts))
+		w.Header().Set(webhook.KeyHeader, release.Key)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		// Alertmanager 收到5xx响应后会重试
+		http.Error(w, "通知队列已满，请稍后重试", http.StatusServiceUnavailable)
+	}
+}
+
+// alertRelease 将一组告警转换为通知：名称为告警名，标签为告警状态，说明为告警模板渲染的内容
+func (s *Server) alertRelease(group *AlertGroup) (*github.ReleaseInfo, error) {
+	var body bytes.Buffer
+	if err := s.alertTemplate.Execute(&body, group); err != nil {
+		return nil, fmt.Errorf("渲染告警模板失败: %v", err)
+	}
+
+	name := group.GroupLabels["alertname"]
+	if name == "" {
+		name = group.CommonLabels["alertname"]
+	}
+	if name == "" {
+		name = group.Alerts[0].Labels["alertname"]
+	}
+
+	event, published := github.EventAlertFiring, group.Alerts[0].StartsAt
+	if group.Status == alertResolved {
+		event = github.EventAlertResolved
+	}
+	for _, a := range group.Alerts {
+		// 触发时使用最早的开始时间，恢复时使用最晚的恢复时间
+		if event == github.EventAlertFiring && a.StartsAt.Before(published) {
+			published = a.StartsAt
+		} else if event == github.EventAlertResolved && a.EndsAt.After(published) {
+			published = a.EndsAt
+		}
+	}
+
+	link := group.ExternalURL
+	if u := group.Alerts[0].GeneratorURL; u != "" {
+		link = u
+	}
+	title := group.CommonAnnotations["summary"]
+	if title == "" {
+		title = name
+	}
+
+	// 同一分组的每次触发、恢复使用不同的标识，下游按 Key 去重时不会丢失再次触发的告警
+	sum := sha256.Sum256([]byte(group.GroupKey + "\n" + group.Status + "\n" + published.UTC().Format(time.RFC3339Nano)))
+	return &github.ReleaseInfo{
+		Event:       event,
+		Source:      github.SourceAlertmanager,
+		Key:         hex.EncodeToString(sum[:16]),
+		Owner:       github.SourceAlertmanager,
+		Repository:  name,
+		TagName:     group.Status,
+		Name:        title,
+		HTMLURL:     link,
+		PublishedAt: published.In(s.loc),
+		Description: strings.TrimSpace(body.String()),
+	}, nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier/webhook"
+)
+
+const alertPayload = `{
+	"version": "4",
+	"groupKey": "{}:{alertname=\"HighCPU\"}",
+	"status": "firing",
+	"receiver": "notify",
+	"groupLabels": {"alertname": "HighCPU"},
+	"commonLabels": {"alertname": "HighCPU", "severity": "critical"},
+	"commonAnnotations": {"summary": "CPU使用率过高"},
+	"externalURL": "http://alertmanager:9093",
+	"alerts": [
+		{"status": "firing", "labels": {"alertname": "HighCPU", "severity": "critical", "instance": "web-1"},
+		 "annotations": {"summary": "web-1 CPU 95%"}, "startsAt": "2024-07-01T09:05:00Z", "generatorURL": "http://prometheus:9090/graph"},
+		{"status": "firing", "labels": {"alertname": "HighCPU", "severity": "critical", "instance": "web-2"},
+		 "annotations": {"summary": "web-2 CPU 91%"}, "startsAt": "2024-07-01T09:00:00Z"}
+	]
+}`
+
+func newAlertServer(t *testing.T, token string) *Server {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Serve.Alertmanager = config.ServeAlertmanagerConfig{Enabled: true, Token: token}
+	tmpl, err := parseAlertTemplate(cfg)
+	if err != nil {
+		t.Fatalf("解析告警模板失败: %v", err)
+	}
+	return &Server{config: cfg.Serve, cfg: cfg, loc: time.UTC, queue: make(chan *github.ReleaseInfo, 1), alertTemplate: tmpl}
+}
+
+// TestHandleAlertmanager 测试将一组告警转换为一条通知，并校验Bearer Token
+func TestHandleAlertmanager(t *testing.T) {
+	s := newAlertServer(t, "secret")
+
+	rec := httptest.NewRecorder()
+	s.handleAlertmanager(rec, httptest.NewRequest(http.MethodPost, "/alertmanager", strings.NewReader(alertPayload)))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("缺少令牌时返回 %d，期望 401", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/alertmanager", strings.NewReader(alertPayload))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec = httptest.NewRecorder()
+	s.handleAlertmanager(rec, req)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("返回 %d，期望 202: %s", rec.Code, rec.Body.String())
+	}
+
+	r := <-s.queue
+	if r.Event != github.EventAlertFiring || r.Source != github.SourceAlertmanager || r.Repository != "HighCPU" || r.TagName != "firing" || r.Name != "CPU使用率过高" {
+		t.Errorf("通知为 %+v", r)
+	}
+	if want := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC); !r.PublishedAt.Equal(want) || r.HTMLURL != "http://prometheus:9090/graph" {
+		t.Errorf("时间为 %v，链接为 %s", r.PublishedAt, r.HTMLURL)
+	}
+	if !strings.Contains(r.Description, "🔥 HighCPU [critical] @ web-1\nweb-1 CPU 95%") || !strings.Contains(r.Description, "web-2 CPU 91%") {
+		t.Errorf("告警内容为 %q", r.Description)
+	}
+	if len(r.Key) != 32 || rec.Header().Get(webhook.KeyHeader) != r.Key {
+		t.Errorf("标识为 %q，响应头为 %q", r.Key, rec.Header().Get(webhook.KeyHeader))
+	}
+
+	// 恢复时使用不同的标识
+	resolved := strings.ReplaceAll(alertPayload, `"firing"`, `"resolved"`)
+	req = httptest.NewRequest(http.MethodPost, "/alertmanager", strings.NewReader(resolved))
+	req.Header.Set("Authorization", "Bearer secret")
+	s.handleAlertmanager(httptest.NewRecorder(), req)
+	if got := <-s.queue; got.Event != github.EventAlertResolved || got.Key == r.Key {
+		t.Errorf("恢复通知为 %+v", got)
+	}
+}
+
+// TestParseAlertTemplate 测试自定义告警模板中的 .Firing 和无效模板
+func TestParseAlertTemplate(t *testing.T) {
+	s := newAlertServer(t, "")
+	s.cfg.Serve.Alertmanager.Template = `{{len .Firing}} 条告警触发`
+	tmpl, err := parseAlertTemplate(s.cfg)
+	if err != nil {
+		t.Fatalf("解析告警模板失败: %v", err)
+	}
+	s.alertTemplate = tmpl
+	r, err := s.alertRelease(&AlertGroup{Status: "firing", Alerts: []Alert{{Status: "firing"}, {Status: "resolved"}}})
+	if err != nil || r.Description != "1 条告警触发" {
+		t.Errorf("渲染结果为 %q（%v）", r.Description, err)
+	}
+
+	s.cfg.Serve.Alertmanager.Template = `{{.Alerts`
+	if _, err := parseAlertTemplate(s.cfg); err == nil {
+		t.Error("无效的模板应返回错误")
+	}
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// defaultPageSize 分页接口默认每页返回的条数
+const defaultPageSize = 100
+
+// maxPageSize 分页接口每页最多返回的条数
+const maxPageSize = 1000
+
+// page 分页参数
+type page struct {
+	Offset int
+	Limit  int
+}
+
+// parsePage 解析 offset、limit 查询参数
+func parsePage(r *http.Request) (page, bool) {
+	p := page{Limit: defaultPageSize}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return p, false
+		}
+		p.Offset = n
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return p, false
+		}
+		p.Limit = min(n, maxPageSize)
+	}
+	return p, true
+}
+
+// bounds 返回总数为 total 时当前页的起止下标，以及下一页的 offset（没有下一页时为nil）
+func (p page) bounds(total int) (start, end int, next *int) {
+	start = min(p.Offset, total)
+	end = min(start+p.Limit, total)
+	if end < total {
+		next = &end
+	}
+	return start, end, next
+}
+
+// repoState 接口返回的仓库状态
+type repoState struct {
+	Owner        string    `json:"owner"`
+	Repository   string    `json:"repository"`
+	LatestTag    string    `json:"latest_tag"`
+	LastNotified time.Time `json:"last_notified"`
+	RepoID       int64     `json:"repo_id,omitempty"`
+	PushedTag    string    `json:"pushed_tag,omitempty"`
+}
+
+// handleState 分页返回每个仓库最近记录的版本
+// 每次请求都重新读取状态文件，轮询实例（如另一个容器中的定时任务）写入的状态也能立即看到
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	p, ok := parsePage(r)
+	if !ok {
+		http.Error(w, "offset 或 limit 参数无效", http.StatusBadRequest)
+		return
+	}
+
+	path, err := util.ResolvePath(s.cfg.State.Path, "state.json", s.cfg.DataSuffix())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	store, err := util.OpenStateStore(path, github.StoreOptions(s.cfg))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	states := store.Snapshot()
+	start, end, next := p.bounds(len(states))
+	repos := make([]repoState, 0, end-start)
+	for _, state := range states[start:end] {
+		repos = append(repos, repoState{
+			Owner:        state.Owner,
+			Repository:   state.Repository,
+			LatestTag:    state.LatestTag,
+			LastNotified: state.LastNotified,
+			RepoID:       state.RepoID,
+			PushedTag:    state.PushedTag,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Total      int         `json:"total"`
+		Offset     int         `json:"offset"`
+		NextOffset *int        `json:"next_offset,omitempty"`
+		Repos      []repoState `json:"repos"`
+	}{len(states), start, next, repos})
+}
+
+// handleRuns 分页返回运行历史，最近的运行在前
+func (s *Server) handleRuns(w http.ResponseWriter, r *http.Request) {
+	p, ok := parsePage(r)
+	if !ok {
+		http.Error(w, "offset 或 limit 参数无效", http.StatusBadRequest)
+		return
+	}
+
+	path, err := util.ResolvePath("", "runs.json", s.cfg.DataSuffix())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	history, err := util.LoadRunHistory(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	runs := history.Runs()
+	start, end, next := p.bounds(len(runs))
+	page := make([]util.RunRecord, 0, end-start)
+	for i := start; i < end; i++ {
+		page = append(page, runs[len(runs)-1-i])
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Total      int              `json:"total"`
+		Offset     int              `json:"offset"`
+		NextOffset *int             `json:"next_offset,omitempty"`
+		Runs       []util.RunRecord `json:"runs"`
+	}{len(runs), start, next, page})
+}
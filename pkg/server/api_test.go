@@ -0,0 +1,88 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// TestHandleState 测试状态接口的分页
+func TestHandleState(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{}
+	cfg.State.Path = filepath.Join(dir, "state.json")
+	store, err := util.OpenStateStore(cfg.State.Path, util.StoreOptions{})
+	if err != nil {
+		t.Fatalf("创建状态存储失败: %v", err)
+	}
+	for _, repo := range []string{"c", "a", "b"} {
+		if _, err := store.CheckAndUpdateIfNew("owner", repo, "v1.0.0"); err != nil {
+			t.Fatalf("写入状态失败: %v", err)
+		}
+	}
+
+	s := &Server{cfg: cfg}
+	rec := httptest.NewRecorder()
+	s.handleState(rec, httptest.NewRequest(http.MethodGet, "/api/v1/state?limit=2", nil))
+
+	var resp struct {
+		Total      int         `json:"total"`
+		NextOffset *int        `json:"next_offset"`
+		Repos      []repoState `json:"repos"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Total != 3 || len(resp.Repos) != 2 || resp.Repos[0].Repository != "a" || resp.NextOffset == nil || *resp.NextOffset != 2 {
+		t.Errorf("第一页不正确: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleState(rec, httptest.NewRequest(http.MethodGet, "/api/v1/state?offset=2&limit=2", nil))
+	resp.NextOffset = nil
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+	if len(resp.Repos) != 1 || resp.Repos[0].Repository != "c" || resp.NextOffset != nil {
+		t.Errorf("最后一页不正确: %s", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleState(rec, httptest.NewRequest(http.MethodGet, "/api/v1/state?limit=-1", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("无效的分页参数应返回400，实际 %d", rec.Code)
+	}
+}
+
+// TestHandleRuns 测试运行历史接口按时间倒序返回
+func TestHandleRuns(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	path, err := util.ResolvePath("", "runs.json", "")
+	if err != nil {
+		t.Fatalf("解析路径失败: %v", err)
+	}
+	history, _ := util.LoadRunHistory(path)
+	started := time.Now()
+	for i := 1; i <= 3; i++ {
+		history.Append(util.RunRecord{StartedAt: started, FinishedAt: started, Success: true, Releases: i}, 0)
+	}
+
+	s := &Server{cfg: &config.Config{}}
+	rec := httptest.NewRecorder()
+	s.handleRuns(rec, httptest.NewRequest(http.MethodGet, "/api/v1/runs?limit=1", nil))
+
+	var resp struct {
+		Total int              `json:"total"`
+		Runs  []util.RunRecord `json:"runs"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Total != 3 || len(resp.Runs) != 1 || resp.Runs[0].Releases != 3 {
+		t.Errorf("应返回最近一次运行: %s", rec.Body.String())
+	}
+}
@@ -0,0 +1,219 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// detectSource 根据请求头判断webhook来源
+// Gitea为了兼容会同时发送 X-GitHub-Event，因此需要优先判断
+func detectSource(r *http.Request) string {
+	switch {
+	case r.Header.Get("X-Gitea-Event") != "" || r.Header.Get("X-Gogs-Event") != "":
+		return github.SourceGitea
+	case r.Header.Get("X-Gitlab-Event") != "":
+		return github.SourceGitLab
+	case r.Header.Get("X-GitHub-Event") != "":
+		return github.SourceGitHub
+	default:
+		return ""
+	}
+}
+
+// verifyHMAC 校验十六进制HMAC-SHA256签名，signature可以带有 sha256= 前缀
+func verifyHMAC(secret string, body []byte, signature string) bool {
+	signature = strings.TrimPrefix(signature, "sha256=")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// verify 按来源校验webhook请求，未配置密钥时不校验
+func (s *Server) verify(source string, r *http.Request, body []byte) error {
+	switch source {
+	case github.SourceGitHub:
+		if s.config.GitHubSecret != "" && !verifyHMAC(s.config.GitHubSecret, body, r.Header.Get("X-Hub-Signature-256")) {
+			return fmt.Errorf("GitHub webhook签名校验失败")
+		}
+	case github.SourceGitea:
+		if s.config.GiteaSecret != "" && !verifyHMAC(s.config.GiteaSecret, body, r.Header.Get("X-Gitea-Signature")) {
+			return fmt.Errorf("Gitea webhook签名校验失败")
+		}
+	case github.SourceGitLab:
+		// GitLab不签名请求，而是原样发送配置的Secret Token
+		token := r.Header.Get("X-Gitlab-Token")
+		if s.config.GitLabToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.GitLabToken)) != 1 {
+			return fmt.Errorf("GitLab webhook令牌校验失败")
+		}
+	}
+	return nil
+}
+
+// splitPath 将 group/subgroup/project 拆分为所属空间和项目名
+func splitPath(fullName string) (string, string) {
+	i := strings.LastIndex(fullName, "/")
+	if i < 0 {
+		return "", fullName
+	}
+	return fullName[:i], fullName[i+1:]
+}
+
+// parseGitHub 解析GitHub的release事件（release published）
+// Gitea的release事件与GitHub格式相同，同样使用该函数解析
+func parseGitHub(event string, body []byte) (*github.ReleaseInfo, error) {
+	if event != "release" {
+		return nil, nil
+	}
+
+	var payload struct {
+		Action  string `json:"action"`
+		Release struct {
+			TagName     string    `json:"tag_name"`
+			Name        string    `json:"name"`
+			Body        string    `json:"body"`
+			HTMLURL     string    `json:"html_url"`
+			Draft       bool      `json:"draft"`
+			Prerelease  bool      `json:"prerelease"`
+			PublishedAt time.Time `json:"published_at"`
+			CreatedAt   time.Time `json:"created_at"`
+		} `json:"release"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("解析release事件失败: %v", err)
+	}
+	if payload.Action != "published" || payload.Release.Draft {
+		return nil, nil
+	}
+
+	owner, repo := splitPath(payload.Repository.FullName)
+	published := payload.Release.PublishedAt
+	if published.IsZero() {
+		published = payload.Release.CreatedAt
+	}
+
+	return &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Owner:       owner,
+		Repository:  repo,
+		TagName:     payload.Release.TagName,
+		Name:        payload.Release.Name,
+		Description: payload.Release.Body,
+		HTMLURL:     payload.Release.HTMLURL,
+		PublishedAt: published,
+		Prerelease:  payload.Release.Prerelease,
+	}, nil
+}
+
+// parseGitLab 解析GitLab的 Release Hook（action=create）和 Tag Push Hook（新建标签）
+func parseGitLab(body []byte) (*github.ReleaseInfo, error) {
+	var payload struct {
+		ObjectKind string `json:"object_kind"`
+		// Release Hook
+		Action      string `json:"action"`
+		Tag         string `json:"tag"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		URL         string `json:"url"`
+		ReleasedAt  string `json:"released_at"`
+		// Tag Push Hook
+		Ref   string `json:"ref"`
+		After string `json:"after"`
+		// 两种事件都包含 project
+		Project struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+			WebURL            string `json:"web_url"`
+		} `json:"project"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("解析GitLab事件失败: %v", err)
+	}
+
+	owner, repo := splitPath(payload.Project.PathWithNamespace)
+	info := &github.ReleaseInfo{
+		Event:      github.EventRelease,
+		Owner:      owner,
+		Repository: repo,
+	}
+
+	switch payload.ObjectKind {
+	case "release":
+		if payload.Action != "create" {
+			return nil, nil
+		}
+		info.TagName = payload.Tag
+		info.Name = payload.Name
+		info.Description = payload.Description
+		info.HTMLURL = payload.URL
+		// GitLab的时间格式为 "2024-07-01 09:00:00 UTC"
+		info.PublishedAt = parseGitLabTime(payload.ReleasedAt)
+	case "tag_push":
+		// 删除标签时 after 为全0
+		if strings.Trim(payload.After, "0") == "" {
+			return nil, nil
+		}
+		info.TagName = strings.TrimPrefix(payload.Ref, "refs/tags/")
+		info.Name = info.TagName
+		info.HTMLURL = fmt.Sprintf("%s/-/tags/%s", payload.Project.WebURL, info.TagName)
+		info.PublishedAt = time.Now()
+	default:
+		return nil, nil
+	}
+
+	return info, nil
+}
+
+// parseGitLabTime 解析GitLab webhook中的时间，失败时返回当前时间
+func parseGitLabTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05 MST", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// parseGitea 解析Gitea的release事件（格式与GitHub相同）和create事件（新建标签）
+func parseGitea(event string, body []byte) (*github.ReleaseInfo, error) {
+	if event != "create" {
+		return parseGitHub(event, body)
+	}
+
+	var payload struct {
+		Ref        string `json:"ref"`
+		RefType    string `json:"ref_type"`
+		Repository struct {
+			FullName string `json:"full_name"`
+			HTMLURL  string `json:"html_url"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("解析Gitea事件失败: %v", err)
+	}
+	if payload.RefType != "tag" {
+		return nil, nil
+	}
+
+	owner, repo := splitPath(payload.Repository.FullName)
+	return &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Owner:       owner,
+		Repository:  repo,
+		TagName:     payload.Ref,
+		Name:        payload.Ref,
+		HTMLURL:     fmt.Sprintf("%s/src/tag/%s", payload.Repository.HTMLURL, payload.Ref),
+		PublishedAt: time.Now(),
+	}, nil
+}
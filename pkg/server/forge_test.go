@@ -0,0 +1,146 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+func TestParseGitHubRelease(t *testing.T) {
+	body := []byte(`{
+		"action": "published",
+		"release": {"tag_name": "v1.2.0", "name": "v1.2.0", "html_url": "https://github.com/o/r/releases/tag/v1.2.0", "published_at": "2024-07-01T09:00:00Z"},
+		"repository": {"full_name": "o/r"}
+	}`)
+
+	info, err := parseGitHub("release", body)
+	if err != nil || info == nil {
+		t.Fatalf("parseGitHub() = %v, %v", info, err)
+	}
+	if info.Owner != "o" || info.Repository != "r" || info.TagName != "v1.2.0" {
+		t.Errorf("unexpected release: %+v", info)
+	}
+
+	if info, _ := parseGitHub("release", []byte(`{"action": "edited"}`)); info != nil {
+		t.Errorf("edited release should be ignored")
+	}
+	if info, _ := parseGitHub("ping", body); info != nil {
+		t.Errorf("ping event should be ignored")
+	}
+}
+
+func TestParseGitLab(t *testing.T) {
+	release := []byte(`{
+		"object_kind": "release", "action": "create", "tag": "v2.0.0", "name": "Release 2.0",
+		"url": "https://gitlab.com/g/sub/p/-/releases/v2.0.0", "released_at": "2024-07-01 09:00:00 UTC",
+		"project": {"path_with_namespace": "g/sub/p"}
+	}`)
+	info, err := parseGitLab(release)
+	if err != nil || info == nil {
+		t.Fatalf("parseGitLab() = %v, %v", info, err)
+	}
+	if info.Owner != "g/sub" || info.Repository != "p" || info.TagName != "v2.0.0" || info.PublishedAt.Hour() != 9 {
+		t.Errorf("unexpected release: %+v", info)
+	}
+
+	tag := []byte(`{
+		"object_kind": "tag_push", "ref": "refs/tags/v2.0.1", "after": "abc123",
+		"project": {"path_with_namespace": "g/p", "web_url": "https://gitlab.com/g/p"}
+	}`)
+	info, err = parseGitLab(tag)
+	if err != nil || info == nil {
+		t.Fatalf("parseGitLab(tag_push) = %v, %v", info, err)
+	}
+	if info.TagName != "v2.0.1" || info.HTMLURL != "https://gitlab.com/g/p/-/tags/v2.0.1" {
+		t.Errorf("unexpected tag release: %+v", info)
+	}
+
+	deleted := []byte(`{"object_kind": "tag_push", "ref": "refs/tags/v2.0.1", "after": "0000000000000000000000000000000000000000"}`)
+	if info, _ := parseGitLab(deleted); info != nil {
+		t.Errorf("deleted tag should be ignored")
+	}
+}
+
+func TestParseGiteaCreate(t *testing.T) {
+	body := []byte(`{"ref": "v0.3.0", "ref_type": "tag", "repository": {"full_name": "o/r", "html_url": "https://gitea.example.com/o/r"}}`)
+	info, err := parseGitea("create", body)
+	if err != nil || info == nil {
+		t.Fatalf("parseGitea() = %v, %v", info, err)
+	}
+	if info.TagName != "v0.3.0" || info.HTMLURL != "https://gitea.example.com/o/r/src/tag/v0.3.0" {
+		t.Errorf("unexpected release: %+v", info)
+	}
+
+	branch := []byte(`{"ref": "main", "ref_type": "branch"}`)
+	if info, _ := parseGitea("create", branch); info != nil {
+		t.Errorf("branch creation should be ignored")
+	}
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	body := []byte(`{"zen": "hello"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	sig := hex.EncodeToString(mac.Sum(nil))
+
+	if !verifyHMAC("secret", body, "sha256="+sig) {
+		t.Errorf("GitHub style signature should verify")
+	}
+	if !verifyHMAC("secret", body, sig) {
+		t.Errorf("Gitea style signature should verify")
+	}
+	if verifyHMAC("other", body, sig) {
+		t.Errorf("wrong secret should not verify")
+	}
+}
+
+// TestHandleWebhook_QueueFull 队列已满时返回503且不记录状态，来源重试后仍能放入队列
+func TestHandleWebhook_QueueFull(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := util.NewStateStore(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatalf("创建状态存储失败: %v", err)
+	}
+	s := &Server{cfg: &config.Config{}, store: store, loc: time.UTC, queue: make(chan *github.ReleaseInfo, 1)}
+
+	post := func(tag string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(`{
+			"action": "published",
+			"release": {"tag_name": "`+tag+`", "html_url": "https://github.com/o/r/releases/tag/`+tag+`", "published_at": "2024-07-01T09:00:00Z"},
+			"repository": {"full_name": "o/r"}
+		}`))
+		req.Header.Set("X-GitHub-Event", "release")
+		rec := httptest.NewRecorder()
+		s.handleWebhook(rec, req)
+		return rec.Code
+	}
+
+	s.queue <- &github.ReleaseInfo{Owner: "o", Repository: "other", TagName: "v0.1.0"}
+	if code := post("v1.0.0"); code != http.StatusServiceUnavailable {
+		t.Fatalf("队列已满时返回 %d，期望 503", code)
+	}
+	if tag := store.GetLatestTag("o", "r"); tag != "" {
+		t.Fatalf("队列已满时记录了版本 %s", tag)
+	}
+
+	<-s.queue
+	if code := post("v1.0.0"); code != http.StatusAccepted {
+		t.Fatalf("重试时返回 %d，期望 202", code)
+	}
+	if r := <-s.queue; r.TagName != "v1.0.0" {
+		t.Errorf("队列中的版本为 %s", r.TagName)
+	}
+	if code := post("v1.0.0"); code != http.StatusNoContent {
+		t.Errorf("重复的webhook返回 %d，期望 204", code)
+	}
+}
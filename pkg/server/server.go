@@ -0,0 +1,324 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/orange-juzipi/notify/pkg/notifier/webhook"
+	"github.com/orange-juzipi/notify/pkg/pacing"
+)
+
+// maxBodySize webhook请求体的最大长度
+const maxBodySize = 5 << 20
+
+// queueSize 等待发送的版本队列长度
+const queueSize = 100
+
+// Server 接收GitHub、GitLab、Gitea的webhook，转换为版本发布信息后通过通知管道发送；
+// 启用后也接收Alertmanager的告警，使用同样的通知渠道转发
+type Server struct {
+	config  config.ServeConfig
+	cfg     *config.Config
+	manager *notifier.Manager
+	store   *util.StateStore
+	loc     *time.Location
+	queue   chan *github.ReleaseInfo
+	// enqueueMu 保证检查队列容量、记录状态和放入队列之间没有其他请求插入，状态只在确定能放入队列时记录
+	enqueueMu sync.Mutex
+	http      *http.Server
+	// alertTemplate Alertmanager告警消息模板，未启用告警接收时为nil
+	alertTemplate *template.Template
+}
+
+// New 创建webhook服务
+func New(cfg *config.Config) (*Server, error) {
+	manager, err := notifier.NewManager(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("创建通知管理器失败: %v", err)
+	}
+
+	// 与轮询共用状态文件，避免同一版本重复通知；状态存储每次修改前加锁并重新读取文件，不会覆盖定时运行写入的版本
+	storePath, err := util.ResolvePath(cfg.State.Path, "state.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+	store, err := util.OpenStateStore(storePath, github.StoreOptions(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("创建状态存储失败: %v", err)
+	}
+
+	loc, err := time.LoadLocation(cfg.GitHub.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	s := &Server{
+		config:  cfg.Serve,
+		cfg:     cfg,
+		manager: manager,
+		store:   store,
+		loc:     loc,
+		queue:   make(chan *github.ReleaseInfo, queueSize),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /webhook", s.handleWebhook)
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	mux.HandleFunc("GET /api/v1/pacing", s.handlePacing)
+	mux.HandleFunc("GET /api/v1/state", s.handleState)
+	mux.HandleFunc("GET /api/v1/runs", s.handleRuns)
+	if cfg.Notifications.Atom.Enabled {
+		mux.HandleFunc("GET /feed.atom", s.handleFeed)
+	}
+	if cfg.Serve.Alertmanager.Enabled {
+		s.alertTemplate, err = parseAlertTemplate(cfg)
+		if err != nil {
+			return nil, err
+		}
+		mux.HandleFunc("POST /alertmanager", s.handleAlertmanager)
+	}
+
+	s.http = &http.Server{
+		Addr:              cfg.Serve.Listen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	return s, nil
+}
+
+// handlePacing 返回各渠道发送速率令牌桶的当前状态
+func (s *Server) handlePacing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Channels []pacing.Status `json:"channels"`
+	}{s.manager.Pacing()})
+}
+
+// handleFeed 提供Atom订阅文件，尚未写入任何版本时返回404
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	path, err := notifier.AtomFeedPath(s.cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	http.ServeFile(w, r, path)
+}
+
+// Run 启动服务，ctx取消后优雅退出
+func (s *Server) Run(ctx context.Context) error {
+	// 优先重发上次发送失败的通知
+	if errs := s.manager.DrainOutboxContext(ctx); len(errs) > 0 {
+		log.Printf("%d 条待重发的通知仍然发送失败，将在下次启动时继续重试", len(errs))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.worker(ctx)
+	}()
+
+	delivered := make(chan struct{})
+	go func() {
+		defer close(delivered)
+		s.deliver(ctx)
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("webhook服务已启动，监听 %s", s.config.Listen)
+		errCh <- s.http.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = s.http.Shutdown(shutdownCtx)
+	case err = <-errCh:
+		if err == http.ErrServerClosed {
+			err = nil
+		}
+	}
+
+	// ctx已取消，队列中剩余的版本直接放入失败队列，下次启动时重发
+	close(s.queue)
+	<-done
+	<-delivered
+	return err
+}
+
+// deliver 每分钟检查一次，渠道的发送时间段开始后发送其中排队的通知，ctx取消后返回
+func (s *Server) deliver(ctx context.Context) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	// due 排队的通知最早可以发送的时间
+	var due time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !due.IsZero() && !time.Now().Before(due) {
+			if errs := s.manager.DrainOutboxContext(ctx); len(errs) > 0 {
+				log.Printf("%d 条排队的通知发送失败，将在下次启动时继续重试", len(errs))
+			}
+		}
+		due = s.manager.NextDelivery()
+	}
+}
+
+// worker 依次发送队列中的版本，通知管理器不支持并发调用
+// ctx取消后正在等待发送速率的通知立即中断，放入失败队列
+func (s *Server) worker(ctx context.Context) {
+	_, wasPaused := s.manager.PausedUntil()
+	for release := range s.queue {
+		// 暂停结束后先发送暂停期间排队的通知
+		_, paused := s.manager.PausedUntil()
+		if wasPaused && !paused {
+			if errs := s.manager.DrainOutboxContext(ctx); len(errs) > 0 {
+				log.Printf("%d 条排队的通知发送失败，将在下次启动时继续重试", len(errs))
+			}
+		}
+		wasPaused = paused
+
+		if errs := s.manager.NotifyAllContext(ctx, []*github.ReleaseInfo{release}); len(errs) > 0 {
+			for _, err := range errs {
+				log.Printf("发送通知失败: %v", err)
+			}
+		}
+		for _, f := range s.manager.TakeRenderFailures() {
+			log.Printf("模板渲染失败，已改用简化内容发送 - %s", f)
+		}
+	}
+}
+
+// handleWebhook 处理webhook请求
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	source := detectSource(r)
+	if source == "" {
+		http.Error(w, "无法识别的webhook来源", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "读取请求失败", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.verify(source, r, body); err != nil {
+		log.Printf("拒绝webhook请求: %v", err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var release *github.ReleaseInfo
+	switch source {
+	case github.SourceGitHub:
+		release, err = parseGitHub(r.Header.Get("X-GitHub-Event"), body)
+	case github.SourceGitLab:
+		release, err = parseGitLab(body)
+	case github.SourceGitea:
+		event := r.Header.Get("X-Gitea-Event")
+		if event == "" {
+			event = r.Header.Get("X-Gogs-Event")
+		}
+		release, err = parseGitea(event, body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 不关心的事件类型（如ping、push）直接忽略
+	if release == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if !s.accept(source, release) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	s.enqueue(w, source, release)
+}
+
+// enqueue 通过状态文件去重后放入发送队列
+// 队列已满或记录状态失败时返回5xx且不修改状态，来源重试webhook时仍按新版本处理
+func (s *Server) enqueue(w http.ResponseWriter, source string, release *github.ReleaseInfo) {
+	s.enqueueMu.Lock()
+	defer s.enqueueMu.Unlock()
+
+	// 只有持有 enqueueMu 时才会放入队列，此时队列未满，之后的放入不会阻塞
+	if len(s.queue) == cap(s.queue) {
+		http.Error(w, "通知队列已满，请稍后重试", http.StatusServiceUnavailable)
+		return
+	}
+
+	// 非GitHub来源的仓库加上来源前缀，避免与GitHub上的同名仓库冲突
+	owner := release.Owner
+	if source != github.SourceGitHub {
+		owner = source + ":" + owner
+	}
+	isNew, err := s.store.CheckAndUpdateIfNew(owner, release.Repository, release.TagName)
+	if err != nil {
+		log.Printf("更新版本状态失败: %v", err)
+		http.Error(w, "更新版本状态失败，请稍后重试", http.StatusInternalServerError)
+		return
+	}
+	if !isNew {
+		log.Printf("%s/%s %s 已通知过，忽略", release.Owner, release.Repository, release.TagName)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	s.queue <- release
+	log.Printf("收到 %s webhook: %s/%s %s", source, release.Owner, release.Repository, release.TagName)
+	w.Header().Set(webhook.KeyHeader, release.Key)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// accept 补全版本信息，返回是否需要通知，是否已通知过在放入队列时检查
+func (s *Server) accept(source string, release *github.ReleaseInfo) bool {
+	if release.Prerelease && !s.cfg.GitHub.IncludePrereleases {
+		return false
+	}
+
+	// 通过 notify ignore 标记的版本不通知，每次重新读取以便命令行的修改立即生效
+	ignored, err := github.LoadIgnoreList(s.cfg.TenantSuffix())
+	if err != nil {
+		log.Printf("读取忽略列表失败: %v", err)
+	} else if entry, ok := ignored.Match(release.Owner, release.Repository, release.TagName); ok {
+		log.Printf("%s 已标记为忽略，跳过通知", entry)
+		return false
+	}
+
+	release.Source = source
+	release.Key = release.EventKey()
+	release.PublishedAt = release.PublishedAt.In(s.loc)
+	release.Highlights = github.FindHighlights(release.Description, s.cfg.Highlight.Keywords)
+
+	// 发布说明只在需要时显示，与轮询模式保持一致
+	if !s.config.ShowDescription {
+		release.Description = ""
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+// Package shortener 通过自建的短链接服务（Shlink、YOURLS）缩短版本链接
+package shortener
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// 支持的短链接服务
+const (
+	// ProviderShlink Shlink（https://shlink.io），使用 REST API v3
+	ProviderShlink = "shlink"
+	// ProviderYOURLS YOURLS（https://yourls.org），使用 yourls-api.php
+	ProviderYOURLS = "yourls"
+)
+
+// Config 短链接服务配置
+type Config struct {
+	// Provider 短链接服务: shlink、yourls
+	Provider string
+	// URL 服务地址，如 https://s.example.com
+	URL string
+	// APIKey Shlink 的 API Key，或 YOURLS 的签名令牌（signature）
+	APIKey string
+	// Domain Shlink 短链接使用的域名（可选，默认为服务的默认域名）
+	Domain string
+	// LocalAddr 绑定的本地IP或网卡名
+	LocalAddr string
+}
+
+// Client 短链接服务客户端，同一地址只请求一次
+type Client struct {
+	config Config
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// New 创建短链接服务客户端
+func New(config Config) (*Client, error) {
+	config.Provider = strings.ToLower(config.Provider)
+	switch config.Provider {
+	case ProviderShlink, ProviderYOURLS:
+	default:
+		return nil, fmt.Errorf("不支持的短链接服务: %s（可选 shlink、yourls）", config.Provider)
+	}
+	if config.URL == "" {
+		return nil, fmt.Errorf("短链接服务地址不能为空")
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("短链接服务的 api_key 不能为空")
+	}
+	config.URL = strings.TrimRight(config.URL, "/")
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{
+		Timeout:   10 * time.Second,
+		LocalAddr: config.LocalAddr,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+
+	return &Client{
+		config: config,
+		client: client,
+		cache:  make(map[string]string),
+	}, nil
+}
+
+// Shorten 返回长链接对应的短链接
+func (c *Client) Shorten(long string) (string, error) {
+	c.mu.Lock()
+	short, ok := c.cache[long]
+	c.mu.Unlock()
+	if ok {
+		return short, nil
+	}
+
+	var err error
+	switch c.config.Provider {
+	case ProviderShlink:
+		short, err = c.shlink(long)
+	case ProviderYOURLS:
+		short, err = c.yourls(long)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.cache[long] = short
+	c.mu.Unlock()
+	return short, nil
+}
+
+// shlink 通过 Shlink REST API 创建短链接，相同的长链接复用已有的短链接
+func (c *Client) shlink(long string) (string, error) {
+	payload := map[string]interface{}{
+		"longUrl":      long,
+		"findIfExists": true,
+		"tags":         []string{"notify"},
+	}
+	if c.config.Domain != "" {
+		payload["domain"] = c.config.Domain
+	}
+	body, _ := json.Marshal(payload)
+
+	req, err := http.NewRequest(http.MethodPost, c.config.URL+"/rest/v3/short-urls", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", c.config.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("请求Shlink失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var response struct {
+		ShortURL string `json:"shortUrl"`
+		// 错误响应为 application/problem+json
+		Title  string `json:"title"`
+		Detail string `json:"detail"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", fmt.Errorf("Shlink请求失败，状态码: %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK || response.ShortURL == "" {
+		return "", fmt.Errorf("Shlink返回错误: %s %s (状态码: %d)", response.Title, response.Detail, resp.StatusCode)
+	}
+	return response.ShortURL, nil
+}
+
+// yourls 通过 YOURLS API 创建短链接
+func (c *Client) yourls(long string) (string, error) {
+	form := url.Values{
+		"action":    {"shorturl"},
+		"format":    {"json"},
+		"url":       {long},
+		"signature": {c.config.APIKey},
+	}
+
+	resp, err := c.client.PostForm(c.config.URL+"/yourls-api.php", form)
+	if err != nil {
+		return "", fmt.Errorf("请求YOURLS失败: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var response struct {
+		Status   string `json:"status"`
+		Message  string `json:"message"`
+		ShortURL string `json:"shorturl"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", fmt.Errorf("YOURLS请求失败，状态码: %d", resp.StatusCode)
+	}
+	// 长链接已存在时 status 为 fail，但仍然返回已有的短链接
+	if response.ShortURL == "" {
+		return "", fmt.Errorf("YOURLS返回错误: %s (状态码: %d)", response.Message, resp.StatusCode)
+	}
+	return response.ShortURL, nil
+}
@@ -0,0 +1,65 @@
+package shortener
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestShlink 测试Shlink短链接和缓存
+func TestShlink(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/rest/v3/short-urls" || r.Header.Get("X-Api-Key") != "key" {
+			t.Errorf("请求不正确: %s %s", r.URL.Path, r.Header.Get("X-Api-Key"))
+		}
+		var body struct {
+			LongURL string `json:"longUrl"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		json.NewEncoder(w).Encode(map[string]string{"shortUrl": "https://s.example.com/abc", "longUrl": body.LongURL})
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{Provider: "Shlink", URL: srv.URL + "/", APIKey: "key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		short, err := c.Shorten("https://github.com/o/r/releases/tag/v1.0.0")
+		if err != nil || short != "https://s.example.com/abc" {
+			t.Fatalf("短链接为 %q，错误: %v", short, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("相同的链接应只请求一次，实际请求 %d 次", requests)
+	}
+}
+
+// TestYOURLSExisting 测试YOURLS中长链接已存在时复用已有的短链接
+func TestYOURLSExisting(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.FormValue("signature") != "sig" || r.FormValue("action") != "shorturl" {
+			t.Errorf("请求参数不正确: %v", r.Form)
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"status":   "fail",
+			"code":     "error:url",
+			"message":  "already exists",
+			"shorturl": "https://y.example.com/1",
+		})
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{Provider: ProviderYOURLS, URL: srv.URL, APIKey: "sig"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	short, err := c.Shorten("https://github.com/o/r/releases/tag/v1.0.0")
+	if err != nil || short != "https://y.example.com/1" {
+		t.Fatalf("短链接为 %q，错误: %v", short, err)
+	}
+}
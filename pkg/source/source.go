@@ -0,0 +1,80 @@
+// Package source 提供GitHub以外的版本来源共用的部分
+//
+// GitLab、npm、PyPI、crates.io、Go模块代理、Maven和VS Code扩展市场各自只负责读取版本列表和挑选要通知的版本，
+// 分片划分、状态存储、忽略列表和检查期限由本包统一处理，保证与GitHub仓库的行为一致。
+package source
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// Source 一个版本来源，检查结果与GitHub仓库的新版本一起发送
+type Source interface {
+	// Name 来源名称，用于日志，如 "npm包"
+	Name() string
+	// Enabled 配置中是否有需要检查的项目
+	Enabled(cfg *config.Config) bool
+	// Check 检查新版本，showDescription 为是否附带发布说明
+	Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error)
+}
+
+// Base 一次检查使用的状态、忽略列表和检查期限
+type Base struct {
+	Store   *util.StateStore
+	Ignored *github.IgnoreList
+	Config  *config.Config
+	Loc     *time.Location
+	// Since 检查期限的起点，早于它发布的版本只记录状态、不通知
+	Since time.Time
+}
+
+// NewBase 打开状态存储和忽略列表，按 github.timezone 和 github.check_days 计算检查期限
+func NewBase(cfg *config.Config) (*Base, error) {
+	storePath, err := util.ResolvePath(cfg.State.Path, "state.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+	store, err := util.OpenStateStore(storePath, github.StoreOptions(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("创建状态存储失败: %v", err)
+	}
+	ignored, err := github.LoadIgnoreList(cfg.TenantSuffix())
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(cfg.GitHub.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return &Base{
+		Store:   store,
+		Ignored: ignored,
+		Config:  cfg,
+		Loc:     loc,
+		Since:   time.Now().In(loc).AddDate(0, 0, -cfg.GitHub.CheckDays),
+	}, nil
+}
+
+// FilterShard 启用分片时返回属于当前分片的项目（按 key 的哈希确定性划分），noun 为日志中项目的名称
+func FilterShard[T any](items []T, shard config.ShardConfig, noun string, key func(T) string) []T {
+	if !shard.Enabled() {
+		return items
+	}
+	var filtered []T
+	for _, item := range items {
+		h := fnv.New32a()
+		h.Write([]byte(key(item)))
+		if int(h.Sum32()%uint32(shard.Total)) == shard.Index-1 {
+			filtered = append(filtered, item)
+		}
+	}
+	fmt.Printf("分片 %s: 共 %d 个%s，当前分片负责 %d 个\n", shard, len(items), noun, len(filtered))
+	return filtered
+}
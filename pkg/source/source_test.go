@@ -0,0 +1,56 @@
+package source
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// TestFilterShard 每个项目恰好属于一个分片，未启用分片时原样返回
+func TestFilterShard(t *testing.T) {
+	var items []string
+	for i := 0; i < 50; i++ {
+		items = append(items, fmt.Sprintf("pkg-%d", i))
+	}
+	key := func(s string) string { return "npm/" + s }
+
+	if got := FilterShard(items, config.ShardConfig{}, "npm包", key); len(got) != len(items) {
+		t.Fatalf("未启用分片时返回 %d 个，期望 %d 个", len(got), len(items))
+	}
+
+	seen := make(map[string]int)
+	for index := 1; index <= 3; index++ {
+		for _, item := range FilterShard(items, config.ShardConfig{Index: index, Total: 3}, "npm包", key) {
+			seen[item]++
+		}
+	}
+	for _, item := range items {
+		if seen[item] != 1 {
+			t.Errorf("%s 属于 %d 个分片，期望 1 个", item, seen[item])
+		}
+	}
+}
+
+// TestNewBase 检查期限按 check_days 计算，时区无效时使用UTC
+func TestNewBase(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cfg := &config.Config{}
+	cfg.GitHub.Timezone = "Invalid/Zone"
+	cfg.GitHub.CheckDays = 7
+
+	base, err := NewBase(cfg)
+	if err != nil {
+		t.Fatalf("创建失败: %v", err)
+	}
+	if base.Loc.String() != "UTC" {
+		t.Errorf("时区为 %s，期望 UTC", base.Loc)
+	}
+	if want := time.Now().AddDate(0, 0, -7); base.Since.Sub(want).Abs() > time.Minute {
+		t.Errorf("检查期限起点为 %v，期望 %v", base.Since, want)
+	}
+	if base.Store == nil || base.Ignored == nil {
+		t.Error("状态存储和忽略列表不应为空")
+	}
+}
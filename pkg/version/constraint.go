@@ -0,0 +1,97 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// clausePattern 约束中的一个条件，如 >=4.2、!=5.0.1、==4.2.*、~=2.1
+var clausePattern = regexp.MustCompile(`^(>=|<=|==|!=|~=|>|<)\s*(\d+(?:\.\d+){0,2})(\.\*)?$`)
+
+// Constraint 版本范围，多个条件以逗号分隔，需要同时满足，如 ">=4.2,<5"
+// 支持 >、>=、<、<=、==、!=，== 和 != 可以使用 4.2.* 形式的前缀，~=2.1 表示 >=2.1 且主版本相同（与 PEP 440 相同）；
+// 条件只比较核心版本号，是否包含预发布版本由调用方决定
+type Constraint struct {
+	clauses []clause
+}
+
+// clause 一个条件，parts 为版本号中写出的段数，用于前缀和 ~= 的匹配
+type clause struct {
+	op       string
+	v        Version
+	parts    int
+	wildcard bool
+}
+
+// ParseConstraint 解析版本范围，空字符串表示不限制
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		m := clausePattern.FindStringSubmatch(part)
+		if m == nil {
+			return Constraint{}, fmt.Errorf("无效的版本范围 %s: 无法解析 %s", s, part)
+		}
+		cl := clause{op: m[1], parts: strings.Count(m[2], ".") + 1, wildcard: m[3] != ""}
+		if cl.wildcard && cl.op != "==" && cl.op != "!=" {
+			return Constraint{}, fmt.Errorf("无效的版本范围 %s: 只有 == 和 != 支持 .* 前缀", s)
+		}
+		if cl.op == "~=" && cl.parts < 2 {
+			return Constraint{}, fmt.Errorf("无效的版本范围 %s: ~= 需要至少两段版本号，如 ~=2.1", s)
+		}
+		cl.v, _ = Parse(m[2])
+		c.clauses = append(c.clauses, cl)
+	}
+	return c, nil
+}
+
+// Match 版本是否满足全部条件
+func (c Constraint) Match(v Version) bool {
+	core := Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+	for _, cl := range c.clauses {
+		cmp := Compare(core, cl.v)
+		var ok bool
+		switch cl.op {
+		case "==":
+			ok = cmp == 0
+			if cl.wildcard {
+				ok = samePrefix(core, cl.v, cl.parts)
+			}
+		case "!=":
+			ok = cmp != 0
+			if cl.wildcard {
+				ok = !samePrefix(core, cl.v, cl.parts)
+			}
+		case ">=":
+			ok = cmp >= 0
+		case ">":
+			ok = cmp > 0
+		case "<=":
+			ok = cmp <= 0
+		case "<":
+			ok = cmp < 0
+		case "~=":
+			ok = cmp >= 0 && samePrefix(core, cl.v, cl.parts-1)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// samePrefix 两个版本的前 n 段是否相同
+func samePrefix(a, b Version, n int) bool {
+	as := []int{a.Major, a.Minor, a.Patch}
+	bs := []int{b.Major, b.Minor, b.Patch}
+	for i := 0; i < n && i < len(as); i++ {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// versionPattern 从标签中提取语义化版本号，兼容 v1.2.3、release-1.2、pkg/v1.2.3-rc.1 等形式
+var versionPattern = regexp.MustCompile(`(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:[-.]?((?:alpha|beta|rc|pre|preview|dev)[.\-]?\d*|[-][0-9A-Za-z.\-]+))?`)
+
+// Version 语义化版本号
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+	// Original 原始标签
+	Original string
+}
+
+// Parse 解析版本号，无法识别时返回错误
+func Parse(s string) (Version, error) {
+	match := versionPattern.FindStringSubmatch(s)
+	if match == nil {
+		return Version{}, fmt.Errorf("无法解析版本号: %s", s)
+	}
+
+	v := Version{Original: s}
+	v.Major, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		v.Minor, _ = strconv.Atoi(match[2])
+	}
+	if match[3] != "" {
+		v.Patch, _ = strconv.Atoi(match[3])
+	}
+	v.Prerelease = strings.TrimLeft(match[4], "-.")
+	return v, nil
+}
+
+// IsPrerelease 是否为预发布版本
+func (v Version) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Core 返回不含预发布后缀的版本号
+func (v Version) Core() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// String 返回标准化后的版本号
+func (v Version) String() string {
+	if v.Prerelease != "" {
+		return v.Core() + "-" + v.Prerelease
+	}
+	return v.Core()
+}
+
+// Compare 比较两个版本，a<b 返回-1，a==b 返回0，a>b 返回1
+func Compare(a, b Version) int {
+	for _, d := range []int{a.Major - b.Major, a.Minor - b.Minor, a.Patch - b.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	// 核心版本相同时，正式版大于预发布版
+	switch {
+	case a.Prerelease == b.Prerelease:
+		return 0
+	case a.Prerelease == "":
+		return 1
+	case b.Prerelease == "":
+		return -1
+	case a.Prerelease < b.Prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// BumpKind 版本升级的类型
+type BumpKind string
+
+const (
+	// BumpNone 版本相同或降级
+	BumpNone BumpKind = ""
+	// BumpMajor 主版本升级
+	BumpMajor BumpKind = "major"
+	// BumpMinor 次版本升级
+	BumpMinor BumpKind = "minor"
+	// BumpPatch 补丁版本升级
+	BumpPatch BumpKind = "patch"
+	// BumpPrerelease 仅预发布后缀变化
+	BumpPrerelease BumpKind = "prerelease"
+)
+
+// Bump 返回从 from 升级到 to 的升级类型以及相差的版本数量
+func Bump(from, to Version) (BumpKind, int) {
+	if Compare(from, to) >= 0 {
+		return BumpNone, 0
+	}
+	switch {
+	case to.Major != from.Major:
+		return BumpMajor, to.Major - from.Major
+	case to.Minor != from.Minor:
+		return BumpMinor, to.Minor - from.Minor
+	case to.Patch != from.Patch:
+		return BumpPatch, to.Patch - from.Patch
+	default:
+		return BumpPrerelease, 1
+	}
+}
+
+// Gap 返回当前版本落后于新版本的描述，例如 "落后 3 个次版本"，未落后时返回空字符串
+func Gap(current, latest Version) string {
+	kind, n := Bump(current, latest)
+	switch kind {
+	case BumpMajor:
+		return fmt.Sprintf("落后 %d 个主版本", n)
+	case BumpMinor:
+		return fmt.Sprintf("落后 %d 个次版本", n)
+	case BumpPatch:
+		return fmt.Sprintf("落后 %d 个补丁版本", n)
+	case BumpPrerelease:
+		return "落后 1 个预发布版本"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,114 @@
+package version
+
+import "testing"
+
+// TestParse 测试版本号解析
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"v1.2.3":            "1.2.3",
+		"1.2":               "1.2.0",
+		"release-2.0.1":     "2.0.1",
+		"cobra/v1.10.1":     "1.10.1",
+		"v2.0.0-rc.1":       "2.0.0-rc.1",
+		"v3.1.0-beta2":      "3.1.0-beta2",
+		"go-github v71.0.0": "71.0.0",
+	}
+
+	for tag, want := range cases {
+		v, err := Parse(tag)
+		if err != nil {
+			t.Errorf("Parse(%q) 失败: %v", tag, err)
+			continue
+		}
+		if v.String() != want {
+			t.Errorf("Parse(%q) = %s，期望 %s", tag, v.String(), want)
+		}
+	}
+
+	if _, err := Parse("latest"); err == nil {
+		t.Error("无版本号的标签应该解析失败")
+	}
+}
+
+// TestCompare 测试版本比较
+func TestCompare(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.3", "v1.10.0", -1},
+		{"v2.0.0", "v1.99.99", 1},
+		{"v2.0.0-rc.1", "v2.0.0", -1},
+		{"v2.0.0-rc.2", "v2.0.0-rc.1", 1},
+	}
+
+	for _, c := range cases {
+		a, _ := Parse(c.a)
+		b, _ := Parse(c.b)
+		if got := Compare(a, b); got != c.want {
+			t.Errorf("Compare(%s, %s) = %d，期望 %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+// TestGap 测试版本差距描述
+func TestGap(t *testing.T) {
+	cases := map[[2]string]string{
+		{"v1.2.3", "v1.5.0"}: "落后 3 个次版本",
+		{"v1.2.3", "v3.0.0"}: "落后 2 个主版本",
+		{"v1.2.3", "v1.2.4"}: "落后 1 个补丁版本",
+		{"v1.2.3", "v1.2.3"}: "",
+		{"v1.3.0", "v1.2.9"}: "",
+	}
+
+	for pair, want := range cases {
+		current, _ := Parse(pair[0])
+		latest, _ := Parse(pair[1])
+		if got := Gap(current, latest); got != want {
+			t.Errorf("Gap(%s, %s) = %q，期望 %q", pair[0], pair[1], got, want)
+		}
+	}
+}
+
+// TestConstraint 测试版本范围的解析和匹配
+func TestConstraint(t *testing.T) {
+	cases := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"", "9.9.9", true},
+		{">=4.2,<5", "4.2.0", true},
+		{">=4.2,<5", "4.10.3", true},
+		{">=4.2,<5", "5.0.0", false},
+		{">=4.2,<5", "4.1.9", false},
+		{"==4.2.*", "4.2.7", true},
+		{"==4.2.*", "4.3.0", false},
+		{"!=5.0.1", "5.0.1", false},
+		{"!=5.*", "4.9.0", true},
+		{"~=2.1", "2.9.0", true},
+		{"~=2.1", "3.0.0", false},
+		{"~=2.1.3", "2.1.9", true},
+		{"~=2.1.3", "2.2.0", false},
+		{">1.0, <=1.5", "1.5.0", true},
+		{"<5", "5.0.0-rc1", false}, // 按核心版本号比较，<5 不包含 5.0 的预发布版本
+	}
+	for _, c := range cases {
+		constraint, err := ParseConstraint(c.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q) 失败: %v", c.constraint, err)
+			continue
+		}
+		v, _ := Parse(c.version)
+		if got := constraint.Match(v); got != c.want {
+			t.Errorf("%q 匹配 %s = %v，期望 %v", c.constraint, c.version, got, c.want)
+		}
+	}
+
+	for _, s := range []string{">=a", "~=2", ">=4.*", "4.2"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%q) 应该失败", s)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package vscode
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/source"
+)
+
+// openVSXSuffix Open VSX 兼容VS Code扩展市场的接口路径
+const openVSXSuffix = "/vscode/gallery"
+
+// stateOwner 状态文件中的所属空间，加上来源前缀和市场地址，避免与GitHub上的同名仓库以及其他市场中的同名扩展冲突
+func stateOwner(baseURL string) string {
+	host := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		host = u.Host + u.Path
+	}
+	return github.SourceVSCode + ":" + host
+}
+
+// parseID 解析 publisher.name 形式的扩展ID，扩展ID不区分大小写，统一转换为小写
+func parseID(s string) (string, error) {
+	publisher, name, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), ".")
+	if !ok || publisher == "" || name == "" || strings.ContainsAny(publisher+name, "/ ") {
+		return "", fmt.Errorf("无效的扩展ID: %s（应为 publisher.name）", s)
+	}
+	return publisher + "." + name, nil
+}
+
+// checker 一次检查使用的客户端、状态和配置
+type checker struct {
+	*source.Base
+	client *Client
+}
+
+// Source VS Code扩展版本来源
+type Source struct{}
+
+// Name 来源名称
+func (Source) Name() string { return "VS Code扩展" }
+
+// Enabled 是否配置了VS Code扩展
+func (Source) Enabled(cfg *config.Config) bool { return len(cfg.VSCode.Extensions) > 0 }
+
+// Check 检查新版本
+func (Source) Check(cfg *config.Config, showDescription bool) ([]*github.ReleaseInfo, error) {
+	return CheckForNewReleases(cfg)
+}
+
+// CheckForNewReleases 检查配置的VS Code扩展是否有新版本
+// 检查期限、时区和预发布版本的设置与GitHub仓库相同（github.check_days、timezone、include_prereleases）
+func CheckForNewReleases(cfg *config.Config) ([]*github.ReleaseInfo, error) {
+	extensions := source.FilterShard(cfg.VSCode.Extensions, cfg.Shard, "VS Code扩展", func(e string) string { return github.SourceVSCode + "/" + e })
+	if len(extensions) == 0 {
+		return nil, nil
+	}
+
+	base, err := source.NewBase(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(cfg.VSCode.Gallery, cfg.Network.LocalAddr)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{
+		Base:   base,
+		client: client,
+	}
+
+	fmt.Printf("正在检查 %d 个VS Code扩展（%s）...\n", len(extensions), client.baseURL)
+	var results []*github.ReleaseInfo
+	errorCount := 0
+	for _, e := range extensions {
+		info, err := c.check(e)
+		if err != nil {
+			fmt.Printf("检查VS Code扩展 %s 失败: %v\n", e, err)
+			errorCount++
+			continue
+		}
+		if info != nil {
+			fmt.Printf("发现新版本: %s (%s)\n", e, info.TagName)
+			results = append(results, info)
+		}
+	}
+
+	fmt.Printf("VS Code扩展检查完成: 发现 %d 个新版本", len(results))
+	if errorCount > 0 {
+		fmt.Printf("，%d 个扩展检查失败", errorCount)
+	}
+	fmt.Println()
+	return results, nil
+}
+
+// check 检查一个扩展，有需要通知的新版本时返回版本信息
+func (c *checker) check(rawID string) (*github.ReleaseInfo, error) {
+	id, err := parseID(rawID)
+	if err != nil {
+		return nil, err
+	}
+
+	ext, err := c.client.extension(id)
+	if err != nil {
+		return nil, err
+	}
+	latest := c.latestVersion(ext)
+	if latest == nil {
+		return nil, nil
+	}
+
+	// 通过 notify ignore 标记的版本不通知，也不记录状态
+	if entry, ok := c.Ignored.Match(github.SourceVSCode, id, latest.Version); ok {
+		fmt.Printf("%s 已标记为忽略，跳过通知\n", entry)
+		return nil, nil
+	}
+	owner := stateOwner(c.client.baseURL)
+	previousTag := c.Store.GetLatestTag(owner, id)
+	if previousTag == latest.Version {
+		return nil, nil
+	}
+
+	// 超过检查期限的版本只记录状态
+	published := latest.LastUpdated
+	if !published.IsZero() && published.Before(c.Since) {
+		if err := c.Store.UpdateState(owner, id, latest.Version); err != nil {
+			return nil, fmt.Errorf("更新版本状态失败: %v", err)
+		}
+		return nil, nil
+	}
+	if published.IsZero() {
+		published = time.Now()
+	}
+
+	isNew, err := c.Store.CheckAndUpdateIfNew(owner, id, latest.Version)
+	if err != nil {
+		return nil, fmt.Errorf("检查并更新版本状态失败: %v", err)
+	}
+	if !isNew {
+		return nil, nil
+	}
+	return &github.ReleaseInfo{
+		Event:       github.EventRelease,
+		Source:      github.SourceVSCode,
+		Owner:       github.SourceVSCode,
+		Repository:  id,
+		TagName:     latest.Version,
+		Name:        latest.Version,
+		HTMLURL:     c.extensionURL(id),
+		PublishedAt: published.In(c.Loc),
+		Prerelease:  latest.prerelease(),
+		PreviousTag: previousTag,
+	}, nil
+}
+
+// latestVersion 返回最新发布的版本，未包含预发布版本时跳过预发布版本
+// 市场返回的版本已按发布时间从新到旧排列，同一版本的多个平台包取第一个
+func (c *checker) latestVersion(ext *extension) *extensionVersion {
+	for i := range ext.Versions {
+		v := &ext.Versions[i]
+		if v.Version == "" || (v.prerelease() && !c.Config.GitHub.IncludePrereleases) {
+			continue
+		}
+		return v
+	}
+	return nil
+}
+
+// extensionURL 返回扩展页面：VS Code扩展市场使用 items 页面，Open VSX 使用扩展页面
+func (c *checker) extensionURL(id string) string {
+	if strings.HasSuffix(c.client.baseURL, openVSXSuffix) {
+		publisher, name, _ := strings.Cut(id, ".")
+		return strings.TrimSuffix(c.client.baseURL, openVSXSuffix) + "/extension/" + publisher + "/" + name
+	}
+	return "https://marketplace.visualstudio.com/items?itemName=" + url.QueryEscape(id)
+}
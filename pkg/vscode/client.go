@@ -0,0 +1,138 @@
+// Package vscode 检查VS Code扩展市场中扩展的新版本
+//
+// 通过扩展市场的 extensionquery 接口读取扩展的版本列表，也支持 Open VSX 等兼容该接口的市场；
+// 标记为预发布的版本按 include_prereleases 处理。
+package vscode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/internal/util"
+)
+
+// DefaultGallery VS Code扩展市场的接口地址
+const DefaultGallery = "https://marketplace.visualstudio.com/_apis/public/gallery"
+
+// timeout 每个请求的超时时间
+const timeout = 15 * time.Second
+
+// 查询条件和返回内容的标志，取值与VS Code客户端相同
+const (
+	filterTypeExtensionName = 7
+
+	flagIncludeVersions          = 0x1
+	flagIncludeVersionProperties = 0x10
+	flagExcludeNonValidated      = 0x20
+)
+
+// prereleaseProperty 标记预发布版本的属性
+const prereleaseProperty = "Microsoft.VisualStudio.Code.PreRelease"
+
+// extension 查询结果中用到的字段，versions 按发布时间从新到旧排列
+// 同一版本为不同平台（targetPlatform）分别发布时会出现多次
+type extension struct {
+	ExtensionName string `json:"extensionName"`
+	DisplayName   string `json:"displayName"`
+	Publisher     struct {
+		PublisherName string `json:"publisherName"`
+	} `json:"publisher"`
+	Versions []extensionVersion `json:"versions"`
+}
+
+type extensionVersion struct {
+	Version        string    `json:"version"`
+	TargetPlatform string    `json:"targetPlatform"`
+	LastUpdated    time.Time `json:"lastUpdated"`
+	Properties     []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"properties"`
+}
+
+// prerelease 是否为预发布版本
+func (v *extensionVersion) prerelease() bool {
+	for _, p := range v.Properties {
+		if p.Key == prereleaseProperty {
+			return strings.EqualFold(p.Value, "true")
+		}
+	}
+	return false
+}
+
+// Client 扩展市场客户端
+type Client struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewClient 创建扩展市场客户端，baseURL 为空时使用VS Code扩展市场
+func NewClient(baseURL, localAddr string) (*Client, error) {
+	if baseURL == "" {
+		baseURL = DefaultGallery
+	}
+	if _, err := url.ParseRequestURI(baseURL); err != nil {
+		return nil, fmt.Errorf("无效的扩展市场地址 %s: %v", baseURL, err)
+	}
+
+	client, err := util.NewHTTPClient(util.HTTPOptions{Timeout: timeout, LocalAddr: localAddr})
+	if err != nil {
+		return nil, fmt.Errorf("创建HTTP客户端失败: %v", err)
+	}
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  client,
+	}, nil
+}
+
+// extension 按 publisher.name 查询扩展及其全部版本
+func (c *Client) extension(id string) (*extension, error) {
+	query := map[string]any{
+		"filters": []map[string]any{{
+			"criteria":   []map[string]any{{"filterType": filterTypeExtensionName, "value": id}},
+			"pageNumber": 1,
+			"pageSize":   1,
+		}},
+		"flags": flagIncludeVersions | flagIncludeVersionProperties | flagExcludeNonValidated,
+	}
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("序列化查询失败: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/extensionquery", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %v", err)
+	}
+	req.Header.Set("Accept", "application/json;api-version=3.0-preview.1")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("请求扩展市场失败: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("扩展市场返回状态码 %d", resp.StatusCode)
+	}
+
+	// 版本较多的扩展（如每天发布预发布版本）返回的内容较大
+	var result struct {
+		Results []struct {
+			Extensions []extension `json:"extensions"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 16<<20)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析扩展市场响应失败: %v", err)
+	}
+	if len(result.Results) == 0 || len(result.Results[0].Extensions) == 0 {
+		return nil, fmt.Errorf("扩展不存在")
+	}
+	return &result.Results[0].Extensions[0], nil
+}
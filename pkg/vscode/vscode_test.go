@@ -0,0 +1,150 @@
+package vscode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/github"
+)
+
+// newFakeGallery 模拟扩展市场，golang.go 的版本为 versions（从新到旧），以 -pre 结尾的为预发布版本
+func newFakeGallery(t *testing.T, published time.Time, versions ...string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/extensionquery", func(w http.ResponseWriter, r *http.Request) {
+		var query struct {
+			Filters []struct {
+				Criteria []struct {
+					FilterType int    `json:"filterType"`
+					Value      string `json:"value"`
+				} `json:"criteria"`
+			} `json:"filters"`
+			Flags int `json:"flags"`
+		}
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&query) != nil || len(query.Filters) != 1 || len(query.Filters[0].Criteria) != 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if query.Flags&flagIncludeVersions == 0 || query.Flags&flagIncludeVersionProperties == 0 {
+			t.Errorf("查询标志为 %#x", query.Flags)
+		}
+		if c := query.Filters[0].Criteria[0]; c.FilterType != filterTypeExtensionName || c.Value != "golang.go" {
+			fmt.Fprint(w, `{"results":[{"extensions":[]}]}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"results":[{"extensions":[{"extensionName":"Go","displayName":"Go","publisher":{"publisherName":"golang"},"versions":[`)
+		for i, v := range versions {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			pre := "false"
+			if n := len(v); n > 4 && v[n-4:] == "-pre" {
+				v, pre = v[:n-4], "true"
+			}
+			// 同一版本为两个平台分别发布
+			for j, platform := range []string{"linux-x64", "darwin-arm64"} {
+				if j > 0 {
+					fmt.Fprint(w, ",")
+				}
+				fmt.Fprintf(w, `{"version":%q,"targetPlatform":%q,"lastUpdated":%q,"properties":[{"key":%q,"value":%q}]}`,
+					v, platform, published.UTC().Format(time.RFC3339), prereleaseProperty, pre)
+			}
+		}
+		fmt.Fprint(w, `]}]}]}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testConfig(t *testing.T, gallery string) *config.Config {
+	return &config.Config{
+		GitHub: config.GitHubConfig{CheckDays: 7, Timezone: "UTC"},
+		VSCode: config.VSCodeConfig{Gallery: gallery, Extensions: []string{"Golang.Go", "example.missing", "invalid"}},
+		State:  config.StateConfig{Path: filepath.Join(t.TempDir(), "state.json")},
+	}
+}
+
+// TestCheckForNewReleases 测试选择最新的正式版本、跳过预发布版本、扩展ID不区分大小写以及状态去重
+func TestCheckForNewReleases(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newFakeGallery(t, time.Now().Add(-time.Hour), "0.43.0-pre", "0.42.1", "0.42.0")
+	cfg := testConfig(t, server.URL)
+
+	releases, err := CheckForNewReleases(cfg)
+	if err != nil {
+		t.Fatalf("检查失败: %v", err)
+	}
+	if len(releases) != 1 {
+		t.Fatalf("发现 %d 个新版本，期望 1 个", len(releases))
+	}
+	r := releases[0]
+	if r.Source != github.SourceVSCode || r.Repository != "golang.go" || r.TagName != "0.42.1" || r.Prerelease {
+		t.Errorf("版本为 %+v", r)
+	}
+	if r.HTMLURL != "https://marketplace.visualstudio.com/items?itemName=golang.go" {
+		t.Errorf("链接为 %s", r.HTMLURL)
+	}
+
+	// 版本没有变化时不重复通知
+	releases, err = CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 0 {
+		t.Errorf("第二次检查发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+
+	// 包含预发布版本时通知 0.43.0
+	cfg.GitHub.IncludePrereleases = true
+	releases, err = CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 1 || releases[0].TagName != "0.43.0" || !releases[0].Prerelease {
+		t.Fatalf("包含预发布版本时的结果为 %+v（%v）", releases, err)
+	}
+}
+
+// TestCheckForNewReleases_Old 超过检查期限的版本只记录状态，不通知
+func TestCheckForNewReleases_Old(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	server := newFakeGallery(t, time.Now().AddDate(0, 0, -30), "1.0.0")
+	cfg := testConfig(t, server.URL)
+
+	releases, err := CheckForNewReleases(cfg)
+	if err != nil || len(releases) != 0 {
+		t.Fatalf("发现 %d 个新版本（%v），期望 0 个", len(releases), err)
+	}
+}
+
+func TestExtensionURL(t *testing.T) {
+	c := &checker{client: &Client{baseURL: "https://open-vsx.org/vscode/gallery"}}
+	if got := c.extensionURL("golang.go"); got != "https://open-vsx.org/extension/golang/go" {
+		t.Errorf("Open VSX 的链接为 %s", got)
+	}
+}
+
+func TestParseID(t *testing.T) {
+	for input, want := range map[string]string{
+		"golang.go":                   "golang.go",
+		" ms-python.Python ":          "ms-python.python",
+		"ms-vscode-remote.remote-ssh": "ms-vscode-remote.remote-ssh",
+		"invalid":                     "",
+		".go":                         "",
+		"golang/go":                   "",
+	} {
+		got, err := parseID(input)
+		if want == "" {
+			if err == nil {
+				t.Errorf("parseID(%q) 期望返回错误", input)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("parseID(%q) = %q（%v），期望 %q", input, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package window 通知渠道的发送时间段
+//
+// 每个渠道可以配置若干个允许发送的时间段，如 "09:00-09:30"、"mon-fri 09:00-18:00"、"sat,sun 10:00-12:00"，
+// 不在时间段内检测到的版本由通知管理器放入队列，时间段开始后再发送。未配置时间段的渠道随时发送。
+package window
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayNames 星期的缩写，下标与 time.Weekday 相同
+var dayNames = [7]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Window 一个发送时间段：在 Days 中的日期从 Start 开始到 End 结束（相对当天零点的时长）
+// End 不大于 Start 时跨过午夜，在次日的 End 结束，Days 按开始的日期判断
+type Window struct {
+	Days  [7]bool
+	Start time.Duration
+	End   time.Duration
+	spec  string
+}
+
+// Schedule 渠道的全部发送时间段，任意一个时间段内都可以发送，为空时随时可以发送
+type Schedule []Window
+
+// Parse 解析发送时间段列表
+func Parse(specs []string) (Schedule, error) {
+	schedule := make(Schedule, 0, len(specs))
+	for _, spec := range specs {
+		w, err := parseWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		schedule = append(schedule, w)
+	}
+	return schedule, nil
+}
+
+// parseWindow 解析 "[星期] HH:MM-HH:MM" 格式的时间段，省略星期时每天生效
+func parseWindow(spec string) (Window, error) {
+	w := Window{spec: strings.TrimSpace(spec)}
+	fields := strings.Fields(strings.ToLower(w.spec))
+
+	var days, clock string
+	switch len(fields) {
+	case 1:
+		clock = fields[0]
+		for i := range w.Days {
+			w.Days[i] = true
+		}
+	case 2:
+		days, clock = fields[0], fields[1]
+		if err := parseDays(days, &w.Days); err != nil {
+			return w, fmt.Errorf("发送时间段 %q 无效: %v", spec, err)
+		}
+	default:
+		return w, fmt.Errorf("发送时间段 %q 无效: 请使用如 09:00-18:00、mon-fri 09:00-18:00 的格式", spec)
+	}
+
+	from, to, ok := strings.Cut(clock, "-")
+	if !ok {
+		return w, fmt.Errorf("发送时间段 %q 无效: 缺少结束时间，请使用如 09:00-09:30 的格式", spec)
+	}
+	var err error
+	if w.Start, err = parseClock(from, false); err != nil {
+		return w, fmt.Errorf("发送时间段 %q 的开始时间无效: %v", spec, err)
+	}
+	if w.End, err = parseClock(to, true); err != nil {
+		return w, fmt.Errorf("发送时间段 %q 的结束时间无效: %v", spec, err)
+	}
+	if w.Start == w.End {
+		return w, fmt.Errorf("发送时间段 %q 无效: 开始和结束时间相同", spec)
+	}
+	return w, nil
+}
+
+// parseDays 解析逗号分隔的星期或星期范围，如 mon-fri、sat,sun、fri-mon
+func parseDays(spec string, days *[7]bool) error {
+	for _, part := range strings.Split(spec, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := parseDay(from)
+		if err != nil {
+			return err
+		}
+		last := first
+		if isRange {
+			if last, err = parseDay(to); err != nil {
+				return err
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// parseDay 解析星期的缩写
+func parseDay(name string) (int, error) {
+	for i, day := range dayNames {
+		if name == day {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("无法识别的星期 %q（可选 %s）", name, strings.Join(dayNames[:], "、"))
+}
+
+// parseClock 解析 HH:MM 格式的时间，allowEnd 为true时允许 24:00
+func parseClock(value string, allowEnd bool) (time.Duration, error) {
+	h, m, ok := strings.Cut(value, ":")
+	if !ok {
+		return 0, fmt.Errorf("%q 不是 HH:MM 格式", value)
+	}
+	hour, err := strconv.Atoi(h)
+	if err != nil {
+		return 0, fmt.Errorf("%q 不是 HH:MM 格式", value)
+	}
+	minute, err := strconv.Atoi(m)
+	if err != nil || len(m) != 2 || minute < 0 || minute > 59 {
+		return 0, fmt.Errorf("%q 的分钟无效", value)
+	}
+	if hour == 24 && minute == 0 && allowEnd {
+		return 24 * time.Hour, nil
+	}
+	if hour < 0 || hour > 23 {
+		return 0, fmt.Errorf("%q 的小时无效", value)
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, nil
+}
+
+// String 返回配置中的时间段写法
+func (w Window) String() string {
+	return w.spec
+}
+
+// Contains t 是否在时间段内，按 t 所在的时区判断
+func (w Window) Contains(t time.Time) bool {
+	day := int(t.Weekday())
+	offset := sinceMidnight(t)
+	if w.Start < w.End {
+		return w.Days[day] && offset >= w.Start && offset < w.End
+	}
+	// 跨过午夜：当天开始的部分，或前一天开始、延续到今天的部分
+	return (w.Days[day] && offset >= w.Start) || (w.Days[(day+6)%7] && offset < w.End)
+}
+
+// sinceMidnight 返回 t 距当天零点的时长（按时钟读数计算，不受夏令时切换影响）
+func sinceMidnight(t time.Time) time.Duration {
+	h, m, s := t.Clock()
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+}
+
+// Contains t 是否在任意一个时间段内，没有配置时间段时返回true
+func (s Schedule) Contains(t time.Time) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, w := range s {
+		if w.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next 返回 t 之后最早可以发送的时间，t 已在时间段内时返回 t
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.Contains(t) {
+		return t
+	}
+	var next time.Time
+	y, mon, d := t.Date()
+	for i := 0; i <= 7; i++ {
+		day := time.Date(y, mon, d+i, 0, 0, 0, 0, t.Location())
+		for _, w := range s {
+			if !w.Days[day.Weekday()] {
+				continue
+			}
+			start := time.Date(y, mon, d+i, int(w.Start/time.Hour), int(w.Start%time.Hour/time.Minute), 0, 0, t.Location())
+			if start.After(t) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+	return next
+}
+
+// String 返回时间段列表的描述，如 "mon-fri 09:00-18:00、sat 10:00-12:00"
+func (s Schedule) String() string {
+	specs := make([]string, 0, len(s))
+	for _, w := range s {
+		specs = append(specs, w.spec)
+	}
+	return strings.Join(specs, "、")
+}
@@ -0,0 +1,86 @@
+package window
+
+import (
+	"testing"
+	"time"
+)
+
+// at 返回2024-07-01（星期一）所在一周中的时间，day 为距星期一的天数
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 7, 1+day, hour, minute, 0, 0, time.UTC)
+}
+
+// TestContains 测试每天、按星期和跨过午夜的时间段
+func TestContains(t *testing.T) {
+	s, err := Parse([]string{"mon-fri 09:00-18:00", "sat 22:00-02:00"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"星期一上午", at(0, 9, 0), true},
+		{"星期五下午", at(4, 17, 59), true},
+		{"结束时间不含在内", at(4, 18, 0), false},
+		{"星期一凌晨", at(0, 8, 59), false},
+		{"星期六晚上", at(5, 23, 0), true},
+		{"星期日凌晨延续星期六的时间段", at(6, 1, 30), true},
+		{"星期日晚上", at(6, 23, 0), false},
+	} {
+		if got := s.Contains(tc.t); got != tc.want {
+			t.Errorf("%s: Contains(%s) = %v，期望 %v", tc.name, tc.t.Format(time.DateTime), got, tc.want)
+		}
+	}
+
+	if !(Schedule{}).Contains(at(0, 3, 0)) {
+		t.Error("没有配置时间段时应随时可以发送")
+	}
+}
+
+// TestNext 测试下一个时间段的开始时间
+func TestNext(t *testing.T) {
+	s, err := Parse([]string{"mon-fri 09:00-09:30"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+
+	for _, tc := range []struct {
+		from, want time.Time
+	}{
+		{at(0, 8, 0), at(0, 9, 0)},
+		{at(0, 9, 10), at(0, 9, 10)},
+		{at(0, 10, 0), at(1, 9, 0)},
+		{at(4, 12, 0), at(7, 9, 0)},
+	} {
+		if got := s.Next(tc.from); !got.Equal(tc.want) {
+			t.Errorf("Next(%s) = %s，期望 %s", tc.from.Format(time.DateTime), got.Format(time.DateTime), tc.want.Format(time.DateTime))
+		}
+	}
+}
+
+// TestParse 测试星期范围和无效的时间段
+func TestParse(t *testing.T) {
+	s, err := Parse([]string{"fri-mon 00:00-24:00", "Wed,sun 10:00-11:00"})
+	if err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	want := [7]bool{true, true, false, false, false, true, true}
+	if s[0].Days != want {
+		t.Errorf("fri-mon 解析为 %v，期望 %v", s[0].Days, want)
+	}
+	if s[0].End != 24*time.Hour {
+		t.Errorf("24:00 解析为 %v", s[0].End)
+	}
+	if !s[1].Days[0] || !s[1].Days[3] || s[1].Days[1] {
+		t.Errorf("Wed,sun 解析为 %v", s[1].Days)
+	}
+
+	for _, spec := range []string{"09:00", "9-18", "25:00-26:00", "09:00-09:00", "everyday 09:00-10:00", "mon 09:60-10:00", "mon tue 09:00-10:00"} {
+		if _, err := Parse([]string{spec}); err == nil {
+			t.Errorf("%q: 期望返回错误", spec)
+		}
+	}
+}
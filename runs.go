@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/notifier"
+)
+
+// lockPollInterval 等待文件锁时的重试间隔
+const lockPollInterval = 2 * time.Second
+
+// acquireLock 获取文件锁，配置了 run.lock_wait 时等待其他实例结束，否则立即返回错误
+func acquireLock(lock *util.FileLock, cfg *config.Config) error {
+	wait, err := parseRunDuration("run.lock_wait", cfg.Run.LockWait)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		err := lock.Lock()
+		if err == nil || time.Now().After(deadline) {
+			return err
+		}
+		if wait > 0 {
+			fmt.Println("已有其他实例正在运行，等待其结束...")
+			wait = 0 // 只提示一次
+		}
+		time.Sleep(lockPollInterval)
+	}
+}
+
+// parseRunDuration 解析可选的时长配置，为空时返回0
+func parseRunDuration(name, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("无效的 %s: %s", name, value)
+	}
+	return d, nil
+}
+
+// runHistory 加载当前租户和分片的运行历史
+func runHistory(cfg *config.Config) (*util.RunHistory, error) {
+	path, err := util.ResolvePath("", "runs.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+	return util.LoadRunHistory(path)
+}
+
+// recentSuccess 最近一次成功的运行是否在 run.skip_if_fresh 窗口内完成
+// 用于跳过与刚结束的运行重叠的cron调用（如等待文件锁的实例），避免紧接着再完整扫描一次
+func recentSuccess(cfg *config.Config) (util.RunRecord, bool) {
+	window, err := parseRunDuration("run.skip_if_fresh", cfg.Run.SkipIfFresh)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return util.RunRecord{}, false
+	}
+	if window <= 0 {
+		return util.RunRecord{}, false
+	}
+
+	history, err := runHistory(cfg)
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+		return util.RunRecord{}, false
+	}
+	last, ok := history.LastSuccess()
+	if !ok || time.Since(last.FinishedAt) >= window {
+		return util.RunRecord{}, false
+	}
+	return last, true
+}
+
+// runResult 返回运行是否成功和失败原因
+func runResult(runErr error) (bool, string) {
+	// --fail-on-new 发现新版本时的退出码不是运行失败
+	var exitErr *exitError
+	if runErr == nil || errors.As(runErr, &exitErr) {
+		return true, ""
+	}
+	return false, runErr.Error()
+}
+
+// reportRenderFailures 汇总本次运行中模板渲染失败、改用简化内容发送的版本
+func reportRenderFailures(manager *notifier.Manager) {
+	if manager == nil {
+		return
+	}
+	failures := manager.TakeRenderFailures()
+	if len(failures) == 0 {
+		return
+	}
+	fmt.Printf("⚠️  %d 个版本的模板渲染失败，已改用简化内容发送，请检查模板:\n", len(failures))
+	for _, f := range failures {
+		fmt.Printf("- %s\n", f)
+	}
+}
+
+// recordRun 记录一次运行的开始、结束时间和结果
+func recordRun(cfg *config.Config, started time.Time, releases int, runErr error) {
+	success, message := runResult(runErr)
+	record := util.RunRecord{
+		StartedAt:  started,
+		FinishedAt: time.Now(),
+		Success:    success,
+		Releases:   releases,
+		Error:      message,
+	}
+
+	history, err := runHistory(cfg)
+	if err == nil {
+		err = history.Append(record, cfg.Run.HistorySize)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/pkg/clock"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/orange-juzipi/notify/pkg/notifier/fault"
+)
+
+// quotaResetMargin 配额重置后再等待的时间，避免与GitHub的重置时间误差
+const quotaResetMargin = 1 * time.Minute
+
+// session 在多次运行之间复用的通知管理器和GitHub检查器
+// 定时运行时保留各渠道的发送速率、限流冷却状态和已解析的模板，以及GitHub API客户端的连接
+type session struct {
+	manager *notifier.Manager
+	checker *github.Checker
+}
+
+// init 创建尚未创建的通知管理器和GitHub检查器
+func (s *session) init(cfg *config.Config) error {
+	if s.manager == nil {
+		manager, err := notifier.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("创建通知管理器失败: %v", err)
+		}
+
+		// 测试模式：添加随机失败的故障注入通知器
+		if faultSpec != "" {
+			faultConfig, _ := fault.ParseSpec(faultSpec)
+			if err := manager.AddFaultNotifier(faultConfig); err != nil {
+				return fmt.Errorf("创建故障注入通知器失败: %v", err)
+			}
+			fmt.Printf("⚠️  已启用故障注入通知器: %s\n", faultSpec)
+		}
+		s.manager = manager
+	}
+	if s.checker == nil {
+		s.checker = github.NewChecker()
+	}
+	return nil
+}
+
+// commitWatchList 通知发送成功后保存本次检查的监控列表快照，发送失败时不调用，下次运行重新对比
+func (s *session) commitWatchList() {
+	if err := s.checker.CommitWatchList(); err != nil {
+		fmt.Printf("⚠️ 保存监控列表快照失败: %v\n", err)
+	}
+}
+
+// scheduler 定时运行的调度状态，GitHub API配额不足时推迟运行
+type scheduler struct {
+	// ctx 取消后中断正在进行的发送
+	ctx context.Context
+	// cfg、sess 只在持有 running 时读写
+	cfg  *config.Config
+	sess *session
+	// running 保证同一时间只有一次检查在运行
+	running sync.Mutex
+	mu      sync.Mutex // 保护推迟状态
+	// deferredUntil 推迟到该时间之后再运行
+	deferredUntil time.Time
+	timer         clock.Timer
+	// deliveryTimer 在渠道的发送时间段开始时发送排队通知的定时器
+	deliveryTimer clock.Timer
+	// lastGC 上一次按保留策略清理数据文件的时间，只在持有 running 时读写
+	lastGC time.Time
+	// clock 判断推迟期和设置推迟检查的定时器使用的时钟
+	clock clock.Clock
+}
+
+// newScheduler 创建调度器
+func newScheduler(ctx context.Context, cfg *config.Config) *scheduler {
+	return &scheduler{ctx: ctx, cfg: cfg, sess: &session{}, clock: clock.System}
+}
+
+// reload 等待正在进行的检查结束后换用新的配置，下次检查时按新配置重新创建通知管理器和GitHub检查器
+func (s *scheduler) reload(cfg *config.Config) {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	s.cfg = cfg
+	s.sess = &session{}
+}
+
+// run 执行一次定时检查，配额不足或处于推迟期时跳过
+func (s *scheduler) run() error {
+	if !s.running.TryLock() {
+		fmt.Println("上一次检查仍在运行，跳过本次定时检查")
+		return nil
+	}
+	defer s.running.Unlock()
+
+	s.mu.Lock()
+	until := s.deferredUntil
+	s.mu.Unlock()
+	if s.clock.Now().Before(until) {
+		fmt.Printf("GitHub API配额不足，检查已推迟到 %s，跳过本次定时检查\n", until.Format(time.DateTime))
+		return nil
+	}
+
+	if s.deferIfQuotaLow() {
+		return nil
+	}
+
+	if s.ctx.Err() != nil {
+		return nil
+	}
+	err := runSession(s.ctx, s.cfg, s.sess)
+	s.scheduleDelivery()
+	s.collectGarbage()
+	return err
+}
+
+// collectGarbage 配置了保留策略时，每天在检查结束后按策略清理一次数据文件
+// 调用方需持有 running
+func (s *scheduler) collectGarbage() {
+	if !s.cfg.Retention.Enabled() || s.sess.manager == nil {
+		return
+	}
+	now := s.clock.Now()
+	if !s.lastGC.IsZero() && now.Sub(s.lastGC) < gcInterval {
+		return
+	}
+	s.lastGC = now
+
+	report, errs := collectGarbage(s.cfg, s.sess.manager, now)
+	for _, err := range errs {
+		fmt.Printf("⚠️ %s清理数据文件失败: %v\n", tenantPrefix(s.cfg), err)
+	}
+	if !report.Empty() {
+		fmt.Printf("✓ %s%s\n", tenantPrefix(s.cfg), report)
+	}
+}
+
+// scheduleDelivery 有通知因不在渠道的发送时间段内而排队时，在时间段开始时发送，不必等到下一次定时检查
+// 调用方需持有 running
+func (s *scheduler) scheduleDelivery() {
+	var next time.Time
+	if s.sess.manager != nil {
+		next = s.sess.manager.NextDelivery()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.deliveryTimer != nil {
+		s.deliveryTimer.Stop()
+		s.deliveryTimer = nil
+	}
+	if next.IsZero() {
+		return
+	}
+	s.deliveryTimer = s.clock.AfterFunc(next.Sub(s.clock.Now()), s.deliver)
+}
+
+// deliver 发送进入发送时间段的渠道中排队的通知，正在检查时等待检查结束
+func (s *scheduler) deliver() {
+	s.running.Lock()
+	defer s.running.Unlock()
+
+	if s.ctx.Err() != nil || s.sess.manager == nil {
+		return
+	}
+	if errs := s.sess.manager.DrainOutboxContext(s.ctx); len(errs) > 0 {
+		fmt.Printf("⚠️ %s%d 条排队的通知发送失败，将在下次运行时继续重试\n", tenantPrefix(s.cfg), len(errs))
+	}
+	s.scheduleDelivery()
+}
+
+// deferIfQuotaLow 剩余配额低于阈值时推迟到配额重置后运行，返回是否已推迟
+func (s *scheduler) deferIfQuotaLow() bool {
+	if s.cfg.Schedule.MinQuota < 0 {
+		return false
+	}
+
+	quota, err := github.GetQuota(s.cfg)
+	if err != nil {
+		// 查询失败时不阻止运行，由检查流程自行处理
+		fmt.Printf("⚠️  %v\n", err)
+		return false
+	}
+
+	// 匿名模式的配额上限只有60次，只要求足够检查手动配置的仓库
+	minQuota := s.cfg.Schedule.MinQuota
+	if s.cfg.GitHub.Token == "" && len(s.cfg.GitHub.Repos) < minQuota {
+		minQuota = len(s.cfg.GitHub.Repos)
+	}
+
+	now := s.clock.Now()
+	if quota.Remaining >= minQuota || !quota.Reset.After(now) {
+		return false
+	}
+
+	until := quota.Reset.Add(quotaResetMargin)
+
+	s.mu.Lock()
+	s.deferredUntil = until
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	s.timer = s.clock.AfterFunc(until.Sub(now), func() {
+		if err := s.run(); err != nil {
+			fmt.Printf("推迟的检查失败: %v\n", err)
+		}
+	})
+	s.mu.Unlock()
+
+	fmt.Printf("⚠️ GitHub API剩余配额 %d/%d 低于 %d，本次检查推迟到配额重置后（%s）运行\n",
+		quota.Remaining, quota.Limit, minQuota, until.Format(time.DateTime))
+	return true
+}
+
+// stop 取消尚未执行的推迟检查
+func (s *scheduler) stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.timer != nil {
+		s.timer.Stop()
+	}
+	if s.deliveryTimer != nil {
+		s.deliveryTimer.Stop()
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/orange-juzipi/notify/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaOutput string
+
+// schemaCmd 输出版本事件负载的JSON Schema
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "输出webhook等渠道使用的JSON负载的Schema",
+	Long: fmt.Sprintf(`输出 webhook 请求体（以及 MQTT、Kafka 消息体使用的 $defs.release）的 JSON Schema（draft 2020-12），
+便于集成方校验负载或生成代码。负载中的 schema_version 字段（当前为 %d）标识结构版本：
+同一版本内只会新增可选字段，删除、重命名字段或改变含义时版本号加一。`, schema.Version),
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if schemaOutput == "" {
+			_, err := os.Stdout.Write(schema.Document)
+			return err
+		}
+
+		if err := os.WriteFile(schemaOutput, schema.Document, 0644); err != nil {
+			return fmt.Errorf("写入Schema文件失败: %v", err)
+		}
+		fmt.Printf("已导出负载Schema（schema_version %d）到 %s\n", schema.Version, schemaOutput)
+		return nil
+	},
+}
+
+func init() {
+	schemaCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "导出文件路径（默认输出到标准输出）")
+	RootCmd.AddCommand(schemaCmd)
+}
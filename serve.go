@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/orange-juzipi/notify/pkg/server"
+	"github.com/spf13/cobra"
+)
+
+// serveCmd 以webhook服务模式运行
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "以webhook服务模式运行，接收GitHub、GitLab、Gitea的发布事件并发送通知",
+	Long: `以webhook服务模式运行，在 /webhook 接收 GitHub、GitLab、Gitea 的 release/tag 事件，
+转换为版本发布信息后使用与轮询相同的通知渠道发送。来源根据请求头自动识别。
+启用 serve.alertmanager 后还在 /alertmanager 接收 Prometheus Alertmanager 的告警，按告警模板转发到相同的渠道。`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		srv, err := server.New(cfg)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return srv.Run(ctx)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(serveCmd)
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/orange-juzipi/notify/config"
+	"github.com/orange-juzipi/notify/internal/util"
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsLast string
+	statsCSV  string
+)
+
+// statsCmd 汇总运行指标
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "汇总最近一段时间的运行指标（如 notify stats --last 30d）",
+	Long: `读取每次检查运行记录的指标（发现的新版本、发送的消息、发送失败、API请求和剩余配额），
+输出指定时间窗口内的汇总、按天统计，以及前后半段的发送失败率对比，便于发现失败率上升等趋势。
+使用 --csv 导出每次运行的指标，可以导入表格或绘图工具。`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		window, label, err := parseWindow(statsLast)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		history, err := metricsHistory(cfg)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		start := now.Add(-window)
+		records := history.Since(start)
+
+		if statsCSV != "" {
+			return exportMetricsCSV(records, statsCSV)
+		}
+
+		if len(records) == 0 {
+			fmt.Printf("最近%s没有运行记录\n", label)
+			return nil
+		}
+		printMetrics(records, label, start.Add(window/2))
+		return nil
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsLast, "last", "30d", "时间窗口，如 30d、2w、72h")
+	statsCmd.Flags().StringVar(&statsCSV, "csv", "", "将每次运行的指标导出为CSV文件，- 表示输出到标准输出")
+	RootCmd.AddCommand(statsCmd)
+}
+
+// metricsHistory 加载当前租户和分片的运行指标
+func metricsHistory(cfg *config.Config) (*util.MetricsHistory, error) {
+	path, err := util.ResolvePath("", "metrics.json", cfg.DataSuffix())
+	if err != nil {
+		return nil, err
+	}
+	return util.LoadMetricsHistory(path)
+}
+
+// recordMetrics 记录一次运行的指标，sent 为本次运行的发送统计
+func recordMetrics(cfg *config.Config, started time.Time, releases int, stats github.CheckStats, sent notifier.SendStats, runErr error) {
+	success, _ := runResult(runErr)
+	record := util.MetricsRecord{
+		Time:           started,
+		DurationMs:     time.Since(started).Milliseconds(),
+		Success:        success,
+		Repos:          stats.Repos,
+		CheckErrors:    stats.Errors,
+		RateLimited:    stats.RateLimited,
+		Releases:       releases,
+		APIRequests:    stats.APIRequests,
+		QuotaRemaining: stats.QuotaRemaining,
+		Messages:       sent.Messages,
+		SendFailures:   sent.Failures,
+	}
+
+	history, err := metricsHistory(cfg)
+	if err == nil {
+		err = history.Append(record, cfg.Run.MetricsDays)
+	}
+	if err != nil {
+		fmt.Printf("⚠️  %v\n", err)
+	}
+}
+
+// printMetrics 输出汇总、按天统计和发送失败率趋势，mid 为时间窗口的中点
+func printMetrics(records []util.MetricsRecord, label string, mid time.Time) {
+	s := util.SummarizeMetrics(records)
+	fmt.Printf("最近%s的运行指标（共 %d 次运行）:\n", label, s.Runs)
+	fmt.Printf("- 成功率: %.1f%%（失败 %d 次）\n", s.SuccessRate()*100, s.Failed)
+	fmt.Printf("- 新版本: %d 个\n", s.Releases)
+	fmt.Printf("- 消息: 发送 %d 条，失败 %d 条（失败率 %.1f%%）\n", s.Messages, s.SendFailures, s.FailureRate()*100)
+	if s.CheckErrors > 0 || s.RateLimited > 0 {
+		fmt.Printf("- 检查失败: %d 个仓库，%d 次运行达到速率限制\n", s.CheckErrors, s.RateLimited)
+	}
+	fmt.Printf("- API请求: 合计 %d 次，平均每次运行 %d 次\n", s.APIRequests, s.APIRequests/int64(s.Runs))
+	if s.MinQuota >= 0 {
+		fmt.Printf("- 最低剩余配额: %d\n", s.MinQuota)
+	}
+
+	fmt.Println("\n按天统计:")
+	fmt.Printf("%-10s  %4s  %4s  %6s  %6s  %8s  %7s\n", "日期", "运行", "失败", "新版本", "消息", "发送失败", "API请求")
+	for i := 0; i < len(records); {
+		day := records[i].Time.Local().Format(time.DateOnly)
+		j := i
+		for j < len(records) && records[j].Time.Local().Format(time.DateOnly) == day {
+			j++
+		}
+		d := util.SummarizeMetrics(records[i:j])
+		fmt.Printf("%-10s  %4d  %4d  %6d  %6d  %8d  %7d\n", day, d.Runs, d.Failed, d.Releases, d.Messages, d.SendFailures, d.APIRequests)
+		i = j
+	}
+
+	// 前后半段的发送失败率对比
+	split := len(records)
+	for i, r := range records {
+		if !r.Time.Before(mid) {
+			split = i
+			break
+		}
+	}
+	if split == 0 || split == len(records) {
+		return
+	}
+	before := util.SummarizeMetrics(records[:split]).FailureRate() * 100
+	after := util.SummarizeMetrics(records[split:]).FailureRate() * 100
+	trend := "持平"
+	switch {
+	case after > before:
+		trend = "上升"
+	case after < before:
+		trend = "下降"
+	}
+	fmt.Printf("\n趋势: 发送失败率 前半段 %.1f%% → 后半段 %.1f%%（%s）\n", before, after, trend)
+}
+
+// exportMetricsCSV 将每次运行的指标导出为CSV，path 为 - 时输出到标准输出
+func exportMetricsCSV(records []util.MetricsRecord, path string) error {
+	if path == "-" {
+		return writeMetricsCSV(os.Stdout, records)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("创建导出文件失败: %v", err)
+	}
+	defer f.Close()
+
+	if err := writeMetricsCSV(f, records); err != nil {
+		return err
+	}
+	fmt.Printf("已导出 %d 条运行指标到 %s\n", len(records), path)
+	return nil
+}
+
+// writeMetricsCSV 每次运行一行
+func writeMetricsCSV(w io.Writer, records []util.MetricsRecord) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"time", "duration_ms", "success", "repos", "check_errors", "rate_limited", "releases", "messages", "send_failures", "api_requests", "quota_remaining"})
+	for _, r := range records {
+		cw.Write([]string{
+			r.Time.Format(time.RFC3339),
+			strconv.FormatInt(r.DurationMs, 10),
+			strconv.FormatBool(r.Success),
+			strconv.Itoa(r.Repos),
+			strconv.Itoa(r.CheckErrors),
+			strconv.FormatBool(r.RateLimited),
+			strconv.Itoa(r.Releases),
+			strconv.Itoa(r.Messages),
+			strconv.Itoa(r.SendFailures),
+			strconv.FormatInt(r.APIRequests, 10),
+			strconv.Itoa(r.QuotaRemaining),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/orange-juzipi/notify/pkg/github"
+	"github.com/orange-juzipi/notify/pkg/notifier"
+	"github.com/spf13/cobra"
+)
+
+var (
+	summarySince string
+	summaryPrint bool
+)
+
+// summaryCmd 汇总指定仓库一段时间内的全部版本
+var summaryCmd = &cobra.Command{
+	Use:   "summary owner/repo",
+	Short: "将仓库一段时间内的全部版本合并为一条汇总发送（如 notify summary owner/repo --since 30d）",
+	Long: `获取仓库在指定时间窗口内发布的全部版本，将各版本的发布说明合并为一条汇总消息，
+发送到所有启用的通知渠道，或使用 --print 只输出到终端，适合休假回来后快速了解错过的更新。
+汇总不读取也不修改已通知的版本状态。`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		owner, repo, ok := strings.Cut(args[0], "/")
+		if !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			return fmt.Errorf("仓库格式无效: %s（应为 owner/repo）", args[0])
+		}
+		window, label, err := parseWindow(summarySince)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+
+		releases, err := github.FetchReleasesSince(cfg, owner, repo, time.Now().Add(-window))
+		if err != nil {
+			return err
+		}
+		if len(releases) == 0 {
+			fmt.Printf("%s/%s 最近%s没有发布新版本\n", owner, repo, label)
+			return nil
+		}
+
+		summary := github.BuildSummary(owner, repo, releases, label)
+		if summaryPrint {
+			fmt.Printf("## %s/%s 版本汇总: %s\n\n%s\n\n%s\n", owner, repo, summary.Name, summary.Description, summary.HTMLURL)
+			return nil
+		}
+
+		manager, err := notifier.NewManager(cfg)
+		if err != nil {
+			return fmt.Errorf("创建通知管理器失败: %v", err)
+		}
+		if errs := manager.NotifyAll([]*github.ReleaseInfo{summary}); len(errs) > 0 {
+			for _, err := range errs {
+				fmt.Printf("发送汇总失败: %v\n", err)
+			}
+			return fmt.Errorf("%d 个渠道发送汇总失败", len(errs))
+		}
+		fmt.Printf("✓ 已发送 %s/%s 的版本汇总（%s）\n", owner, repo, summary.Name)
+		return nil
+	},
+}
+
+// parseWindow 解析时间窗口，支持 30d、2w 以及 Go 的时长格式（如 72h），同时返回用于展示的文字
+func parseWindow(s string) (time.Duration, string, error) {
+	units := map[string]struct {
+		duration time.Duration
+		label    string
+	}{
+		"d": {24 * time.Hour, "天"},
+		"w": {7 * 24 * time.Hour, "周"},
+	}
+	for suffix, unit := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, suffix)); err == nil && strings.HasSuffix(s, suffix) {
+			if n <= 0 {
+				break
+			}
+			return time.Duration(n) * unit.duration, fmt.Sprintf("%d%s", n, unit.label), nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, "", fmt.Errorf("时间窗口无效: %s（如 30d、2w、72h）", s)
+	}
+	return d, d.String(), nil
+}
+
+func init() {
+	summaryCmd.Flags().StringVar(&summarySince, "since", "7d", "汇总的时间窗口，如 30d、2w、72h")
+	summaryCmd.Flags().BoolVar(&summaryPrint, "print", false, "只输出汇总内容，不发送通知")
+	RootCmd.AddCommand(summaryCmd)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/orange-juzipi/notify/config"
+)
+
+// loadConfig 加载配置，指定了 --tenant 时返回该租户的配置
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.LoadConfig(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("加载配置失败: %v", err)
+	}
+	if tenantFlag == "" {
+		return cfg, nil
+	}
+	return cfg.Tenant(tenantFlag)
+}
+
+// runTargets 返回本次运行使用的配置：指定了 --tenant 时只有该租户，配置了租户时为所有租户，否则为顶层配置
+func runTargets(cfg *config.Config) ([]*config.Config, error) {
+	if tenantFlag != "" {
+		tenant, err := cfg.Tenant(tenantFlag)
+		if err != nil {
+			return nil, err
+		}
+		return []*config.Config{tenant}, nil
+	}
+	if len(cfg.Tenants) > 0 {
+		return cfg.TenantConfigs(), nil
+	}
+	return []*config.Config{cfg}, nil
+}
+
+// scheduled 是否有配置启用了定时运行
+func scheduled(cfgs []*config.Config) bool {
+	for _, cfg := range cfgs {
+		if cfg.Schedule.Enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// runAll 依次为每个租户执行一次检查，某个租户失败不影响其他租户
+func runAll(ctx context.Context, cfgs []*config.Config) error {
+	if len(cfgs) == 1 {
+		return runUnlessFresh(ctx, cfgs[0])
+	}
+
+	var (
+		failed  []string
+		gateErr error
+	)
+	for _, cfg := range cfgs {
+		if ctx.Err() != nil {
+			break
+		}
+		fmt.Printf("\n===== 租户 %s =====\n", cfg.TenantName)
+
+		err := runUnlessFresh(ctx, cfg)
+		var exitErr *exitError
+		switch {
+		case err == nil:
+		case errors.As(err, &exitErr):
+			// --fail-on-new 的结果在所有租户运行完成后返回
+			gateErr = err
+		default:
+			fmt.Printf("租户 %s 运行失败: %v\n", cfg.TenantName, err)
+			failed = append(failed, cfg.TenantName)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%d 个租户运行失败: %s", len(failed), strings.Join(failed, "、"))
+	}
+	return gateErr
+}
+
+// tenantPrefix 返回日志中标识租户的前缀，顶层配置返回空字符串
+func tenantPrefix(cfg *config.Config) string {
+	if cfg.TenantName == "" {
+		return ""
+	}
+	return fmt.Sprintf("[%s] ", cfg.TenantName)
+}